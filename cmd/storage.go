@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli/v3"
+
+	clis "github.com/mayswind/ezbookkeeping/pkg/cli"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/storage"
+)
+
+// Storage represents the object storage command
+var Storage = &cli.Command{
+	Name:  "storage",
+	Usage: "ezBookkeeping object storage maintenance",
+	Commands: []*cli.Command{
+		{
+			Name:   "migrate",
+			Usage:  "Migrate all avatars, transaction pictures and attachments from one storage type to another (the settings of both storage types are read from the storage section of config)",
+			Action: bindAction(migrateStorage),
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     "from",
+					Required: true,
+					Usage:    "Source storage type (local_filesystem, minio or webdav)",
+				},
+				&cli.StringFlag{
+					Name:     "to",
+					Required: true,
+					Usage:    "Target storage type (local_filesystem, minio or webdav)",
+				},
+				&cli.BoolFlag{
+					Name:     "dry-run",
+					Required: false,
+					Usage:    "Only check which objects need to be migrated without writing to target storage",
+				},
+			},
+		},
+		{
+			Name:   "encrypt",
+			Usage:  "Encrypt all avatars, transaction pictures and attachments which are not encrypted in current storage in place (requires encryption master key in the storage section of config)",
+			Action: bindAction(encryptStorage),
+			Flags: []cli.Flag{
+				&cli.BoolFlag{
+					Name:     "dry-run",
+					Required: false,
+					Usage:    "Only check which objects need to be encrypted without writing to storage",
+				},
+			},
+		},
+		{
+			Name:   "rotate-key",
+			Usage:  "Re-encrypt the data keys of all objects encrypted by the previous master key with the current master key",
+			Action: bindAction(rotateStorageKey),
+			Flags: []cli.Flag{
+				&cli.BoolFlag{
+					Name:     "dry-run",
+					Required: false,
+					Usage:    "Only check which objects need to be processed without writing to storage",
+				},
+			},
+		},
+	},
+}
+
+func migrateStorage(c *core.CliContext) error {
+	_, err := initializeSystem(c)
+
+	if err != nil {
+		return err
+	}
+
+	fromStorageType := c.String("from")
+	toStorageType := c.String("to")
+	dryRun := c.Bool("dry-run")
+
+	log.CliInfof(c, "[storage.migrateStorage] starting migrating objects from \"%s\" to \"%s\"", fromStorageType, toStorageType)
+
+	summary, err := clis.Storage.MigrateObjects(c, fromStorageType, toStorageType, dryRun)
+
+	if err != nil {
+		log.CliErrorf(c, "[storage.migrateStorage] error occurs when migrating objects")
+		return err
+	}
+
+	printObjectMigrationSummary("Avatar", &summary.Avatar)
+	printObjectMigrationSummary("TransactionPicture", &summary.TransactionPicture)
+	printObjectMigrationSummary("TransactionAttachment", &summary.TransactionAttachment)
+
+	if summary.Avatar.FailedCount > 0 || summary.TransactionPicture.FailedCount > 0 || summary.TransactionAttachment.FailedCount > 0 {
+		log.CliErrorf(c, "[storage.migrateStorage] some objects failed to migrate, please check the log and run this command again to resume")
+		return errs.ErrSomeStorageObjectsMigrateFailed
+	}
+
+	if dryRun {
+		log.CliInfof(c, "[storage.migrateStorage] dry run completed, no objects have been written to \"%s\"", toStorageType)
+	} else {
+		log.CliInfof(c, "[storage.migrateStorage] all objects have been migrated from \"%s\" to \"%s\"", fromStorageType, toStorageType)
+	}
+
+	return nil
+}
+
+func encryptStorage(c *core.CliContext) error {
+	_, err := initializeSystem(c)
+
+	if err != nil {
+		return err
+	}
+
+	dryRun := c.Bool("dry-run")
+	summary, err := clis.Storage.EncryptObjects(c, dryRun)
+
+	if err != nil {
+		log.CliErrorf(c, "[storage.encryptStorage] error occurs when encrypting objects")
+		return err
+	}
+
+	printStorageEncryptionSummary(summary)
+
+	if summary.HasFailed() {
+		log.CliErrorf(c, "[storage.encryptStorage] some objects failed to encrypt, please check the log and run this command again to resume")
+		return errs.ErrSomeStorageObjectsEncryptFailed
+	}
+
+	if dryRun {
+		log.CliInfof(c, "[storage.encryptStorage] dry run completed, no objects have been encrypted")
+	} else {
+		log.CliInfof(c, "[storage.encryptStorage] all objects have been encrypted")
+	}
+
+	return nil
+}
+
+func rotateStorageKey(c *core.CliContext) error {
+	_, err := initializeSystem(c)
+
+	if err != nil {
+		return err
+	}
+
+	dryRun := c.Bool("dry-run")
+	summary, err := clis.Storage.RotateObjectMasterKeys(c, dryRun)
+
+	if err != nil {
+		log.CliErrorf(c, "[storage.rotateStorageKey] error occurs when rotating master key of objects")
+		return err
+	}
+
+	printStorageEncryptionSummary(summary)
+
+	if summary.HasFailed() {
+		log.CliErrorf(c, "[storage.rotateStorageKey] some objects failed to rotate master key, please check the log and run this command again to resume")
+		return errs.ErrSomeStorageObjectsEncryptFailed
+	}
+
+	if dryRun {
+		log.CliInfof(c, "[storage.rotateStorageKey] dry run completed, no objects have been changed")
+	} else {
+		log.CliInfof(c, "[storage.rotateStorageKey] all encrypted objects are using the current master key now, the previous master key can be removed from config")
+	}
+
+	return nil
+}
+
+func printStorageEncryptionSummary(summary *storage.StorageEncryptionSummary) {
+	for _, objectType := range storage.AllStorageObjectTypes {
+		objectSummary := summary.Get(objectType)
+		fmt.Printf("[%s] Total: %d, Encrypted: %d, Rotated: %d, Skipped: %d, Pending: %d, Not Exists: %d, Failed: %d\n",
+			objectType, objectSummary.TotalCount, objectSummary.EncryptedCount, objectSummary.RotatedCount, objectSummary.SkippedCount, objectSummary.PendingCount, objectSummary.NotExistsCount, objectSummary.FailedCount)
+	}
+}
+
+func printObjectMigrationSummary(objectType string, summary *storage.ObjectMigrationSummary) {
+	fmt.Printf("[%s] Total: %d, Migrated: %d, Already Migrated: %d, Pending: %d, Source Not Exists: %d, Failed: %d\n",
+		objectType, summary.TotalCount, summary.MigratedCount, summary.AlreadyMigratedCount, summary.PendingCount, summary.SourceNotExistsCount, summary.FailedCount)
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/urfave/cli/v3"
 
 	clis "github.com/mayswind/ezbookkeeping/pkg/cli"
+	"github.com/mayswind/ezbookkeeping/pkg/converters"
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
 	"github.com/mayswind/ezbookkeeping/pkg/log"
@@ -171,6 +172,31 @@ var UserData = &cli.Command{
 				},
 			},
 		},
+		{
+			Name:   "user-set-quota",
+			Usage:  "Set storage quota and transaction quota of user",
+			Action: bindAction(setUserQuotas),
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     "username",
+					Aliases:  []string{"n"},
+					Required: true,
+					Usage:    "Specific user name",
+				},
+				&cli.Int64Flag{
+					Name:     "storage-quota",
+					Aliases:  []string{"s"},
+					Required: false,
+					Usage:    "Storage quota of transaction pictures and attachments in bytes (0 for using default quota, -1 for unlimited)",
+				},
+				&cli.Int64Flag{
+					Name:     "transaction-quota",
+					Aliases:  []string{"t"},
+					Required: false,
+					Usage:    "Maximum count of transactions (0 for using default quota, -1 for unlimited)",
+				},
+			},
+		},
 		{
 			Name:   "user-resend-verify-email",
 			Usage:  "Resend user verify email",
@@ -366,7 +392,7 @@ var UserData = &cli.Command{
 		},
 		{
 			Name:   "transaction-export",
-			Usage:  "Export user all transactions to file",
+			Usage:  "Export user transactions to file",
 			Action: bindAction(exportUserTransaction),
 			Flags: []cli.Flag{
 				&cli.StringFlag{
@@ -385,7 +411,95 @@ var UserData = &cli.Command{
 					Name:     "type",
 					Aliases:  []string{"t"},
 					Required: false,
-					Usage:    "Export file type, support csv or tsv, default is csv",
+					Usage:    "Export file type, support csv, tsv, ofx, qfx, qif_ymd, qif_mdy, qif_dmy, beancount, ledger, gnucash or xlsx, default is csv",
+				},
+				&cli.Int64Flag{
+					Name:     "min-time",
+					Required: false,
+					Usage:    "Only export transactions after the specified unix time (in seconds)",
+				},
+				&cli.Int64Flag{
+					Name:     "max-time",
+					Required: false,
+					Usage:    "Only export transactions before the specified unix time (in seconds)",
+				},
+				&cli.IntFlag{
+					Name:     "transaction-type",
+					Required: false,
+					Usage:    "Only export transactions of the specified type (1: balance modification, 2: income, 3: expense, 4: transfer)",
+				},
+				&cli.StringFlag{
+					Name:     "account-ids",
+					Required: false,
+					Usage:    "Only export transactions in the specified accounts or their sub-accounts (comma separated account ids)",
+				},
+				&cli.StringFlag{
+					Name:     "category-ids",
+					Required: false,
+					Usage:    "Only export transactions in the specified categories or their sub-categories (comma separated category ids)",
+				},
+				&cli.StringFlag{
+					Name:     "tag-filter",
+					Required: false,
+					Usage:    "Only export transactions matching the specified tag filter (same format as the transaction list)",
+				},
+				&cli.StringFlag{
+					Name:     "amount-filter",
+					Required: false,
+					Usage:    "Only export transactions matching the specified amount filter (e.g. gt:1000, lt:1000, bt:1000:2000)",
+				},
+				&cli.StringFlag{
+					Name:     "keyword",
+					Required: false,
+					Usage:    "Only export transactions whose description contains the specified keyword",
+				},
+				&cli.StringFlag{
+					Name:     "columns",
+					Required: false,
+					Usage:    "Only export the specified columns by the specified order for csv, tsv or xlsx file, support time, timezone, type, category, subCategory, account, accountCurrency, amount, account2, account2Currency, account2Amount, geoLocation, tags, description and customFields (comma separated), default is all columns",
+				},
+				&cli.StringFlag{
+					Name:     "timezone",
+					Required: false,
+					Usage:    "Export all dates in the specified time zone (e.g. UTC, America/New_York), default is the time zone of each transaction",
+				},
+			},
+		},
+		{
+			Name:   "user-data-backup",
+			Usage:  "Backup user all data (including pictures and attachments) to zip archive file",
+			Action: bindAction(backupUserData),
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     "username",
+					Aliases:  []string{"n"},
+					Required: true,
+					Usage:    "Specific user name",
+				},
+				&cli.StringFlag{
+					Name:     "file",
+					Aliases:  []string{"f"},
+					Required: true,
+					Usage:    "Specific backup file path (e.g. backup.zip)",
+				},
+			},
+		},
+		{
+			Name:   "user-data-restore",
+			Usage:  "Restore user all data from zip archive file to the user without any data",
+			Action: bindAction(restoreUserData),
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     "username",
+					Aliases:  []string{"n"},
+					Required: true,
+					Usage:    "Specific user name",
+				},
+				&cli.StringFlag{
+					Name:     "file",
+					Aliases:  []string{"f"},
+					Required: true,
+					Usage:    "Specific backup file path (e.g. backup.zip)",
 				},
 			},
 		},
@@ -434,6 +548,15 @@ func getUserInfo(c *core.CliContext) error {
 
 	printUserInfo(user)
 
+	usage, err := clis.UserData.GetUserQuotaUsage(c, user)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.getUserInfo] error occurs when getting user quota usage")
+		return err
+	}
+
+	printUserQuotaUsage(usage)
+
 	return nil
 }
 
@@ -539,6 +662,28 @@ func setUserFeatureRestriction(c *core.CliContext) error {
 	return nil
 }
 
+func setUserQuotas(c *core.CliContext) error {
+	_, err := initializeSystem(c)
+
+	if err != nil {
+		return err
+	}
+
+	username := c.String("username")
+	storageQuota := c.Int64("storage-quota")
+	transactionQuota := c.Int64("transaction-quota")
+	err = clis.UserData.SetUserQuotas(c, username, storageQuota, transactionQuota)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.setUserQuotas] error occurs when setting user quotas")
+		return err
+	}
+
+	log.CliInfof(c, "[user_data.setUserQuotas] user \"%s\" has been set new quotas", username)
+
+	return nil
+}
+
 func addUserFeatureRestriction(c *core.CliContext) error {
 	_, err := initializeSystem(c)
 
@@ -858,7 +1003,7 @@ func exportUserTransaction(c *core.CliContext) error {
 		fileType = "csv"
 	}
 
-	if fileType != "csv" && fileType != "tsv" {
+	if converters.GetTransactionDataExporter(fileType) == nil {
 		log.CliErrorf(c, "[user_data.exportUserTransaction] export file type is not supported")
 		return errs.ErrNotSupported
 	}
@@ -877,20 +1022,47 @@ func exportUserTransaction(c *core.CliContext) error {
 
 	log.CliInfof(c, "[user_data.exportUserTransaction] starting exporting user \"%s\" data", username)
 
-	content, err := clis.UserData.ExportTransaction(c, username, fileType)
+	transactionType := c.Int("transaction-type")
+
+	if transactionType < 0 || transactionType > int(models.TRANSACTION_TYPE_TRANSFER) {
+		log.CliErrorf(c, "[user_data.exportUserTransaction] transaction type is invalid")
+		return errs.ErrTransactionTypeInvalid
+	}
+
+	exportTransactionDataReq := &models.ExportTransactionDataRequest{
+		Type:         models.TransactionType(transactionType),
+		CategoryIds:  c.String("category-ids"),
+		AccountIds:   c.String("account-ids"),
+		TagFilter:    c.String("tag-filter"),
+		AmountFilter: c.String("amount-filter"),
+		Keyword:      c.String("keyword"),
+		MaxTime:      c.Int64("max-time"),
+		MinTime:      c.Int64("min-time"),
+		Columns:      c.String("columns"),
+		Timezone:     c.String("timezone"),
+	}
+
+	file, err := os.Create(filePath)
 
 	if err != nil {
-		log.CliErrorf(c, "[user_data.exportUserTransaction] error occurs when exporting user data")
+		log.CliErrorf(c, "[user_data.exportUserTransaction] failed to create %s", filePath)
 		return err
 	}
 
-	err = utils.WriteFile(filePath, content)
+	err = clis.UserData.ExportTransaction(c, username, fileType, exportTransactionDataReq, file)
+	closeErr := file.Close()
 
 	if err != nil {
-		log.CliErrorf(c, "[user_data.exportUserTransaction] failed to write to %s", filePath)
+		log.CliErrorf(c, "[user_data.exportUserTransaction] error occurs when exporting user data")
+		_ = os.Remove(filePath)
 		return err
 	}
 
+	if closeErr != nil {
+		log.CliErrorf(c, "[user_data.exportUserTransaction] failed to write to %s", filePath)
+		return closeErr
+	}
+
 	log.CliInfof(c, "[user_data.exportUserTransaction] user transactions have been exported to %s", filePath)
 
 	return nil
@@ -945,6 +1117,101 @@ func importUserTransaction(c *core.CliContext) error {
 	return nil
 }
 
+func backupUserData(c *core.CliContext) error {
+	_, err := initializeSystem(c)
+
+	if err != nil {
+		return err
+	}
+
+	username := c.String("username")
+	filePath := c.String("file")
+
+	if filePath == "" {
+		log.CliErrorf(c, "[user_data.backupUserData] backup file path is unspecified")
+		return os.ErrNotExist
+	}
+
+	fileExists, err := utils.IsExists(filePath)
+
+	if fileExists {
+		log.CliErrorf(c, "[user_data.backupUserData] specified file path already exists")
+		return os.ErrExist
+	}
+
+	log.CliInfof(c, "[user_data.backupUserData] starting backing up user \"%s\" data", username)
+
+	content, err := clis.UserData.BackupUserData(c, username)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.backupUserData] error occurs when backing up user data")
+		return err
+	}
+
+	err = utils.WriteFile(filePath, content)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.backupUserData] failed to write to %s", filePath)
+		return err
+	}
+
+	log.CliInfof(c, "[user_data.backupUserData] user data has been backed up to %s", filePath)
+
+	return nil
+}
+
+func restoreUserData(c *core.CliContext) error {
+	_, err := initializeSystem(c)
+
+	if err != nil {
+		return err
+	}
+
+	username := c.String("username")
+	filePath := c.String("file")
+
+	if filePath == "" {
+		log.CliErrorf(c, "[user_data.restoreUserData] backup file path is not specified")
+		return os.ErrNotExist
+	}
+
+	fileExists, err := utils.IsExists(filePath)
+
+	if !fileExists {
+		log.CliErrorf(c, "[user_data.restoreUserData] backup file does not exist")
+		return os.ErrNotExist
+	}
+
+	file, err := os.Open(filePath)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.restoreUserData] failed to open backup file")
+		return err
+	}
+
+	defer file.Close()
+
+	fileInfo, err := file.Stat()
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.restoreUserData] failed to get backup file info")
+		return err
+	}
+
+	log.CliInfof(c, "[user_data.restoreUserData] start restoring data to user \"%s\"", username)
+
+	err = clis.UserData.RestoreUserData(c, username, file, fileInfo.Size())
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.restoreUserData] error occurs when restoring user data")
+		return err
+	}
+
+	log.CliInfof(c, "[user_data.restoreUserData] user data has been restored to user \"%s\"", username)
+
+	return nil
+}
+
 func printUserInfo(user *models.User) {
 	fmt.Printf("[Uid] %d\n", user.Uid)
 	fmt.Printf("[Username] %s\n", user.Username)
@@ -974,6 +1241,8 @@ func printUserInfo(user *models.User) {
 	fmt.Printf("[ExpenseAmountColor] %s (%d)\n", user.ExpenseAmountColor, user.ExpenseAmountColor)
 	fmt.Printf("[IncomeAmountColor] %s (%d)\n", user.IncomeAmountColor, user.IncomeAmountColor)
 	fmt.Printf("[FeatureRestriction] %s (%d)\n", user.FeatureRestriction, user.FeatureRestriction)
+	fmt.Printf("[StorageQuota] %s\n", getUserQuotaDisplayText(user.StorageQuota))
+	fmt.Printf("[TransactionQuota] %s\n", getUserQuotaDisplayText(user.TransactionQuota))
 	fmt.Printf("[Deleted] %t\n", user.Deleted)
 	fmt.Printf("[EmailVerified] %t\n", user.EmailVerified)
 	fmt.Printf("[CreatedAt] %s (%d)\n", utils.FormatUnixTimeToLongDateTimeInServerTimezone(user.CreatedUnixTime), user.CreatedUnixTime)
@@ -997,3 +1266,28 @@ func printTokenInfo(token *models.TokenRecord) {
 	fmt.Printf("[LastSeen] %s (%d)\n", utils.FormatUnixTimeToLongDateTimeInServerTimezone(token.LastSeenUnixTime), token.LastSeenUnixTime)
 	fmt.Printf("[UserAgent] %s\n", token.UserAgent)
 }
+
+func printUserQuotaUsage(usage *models.UserQuotaUsage) {
+	fmt.Printf("[EffectiveStorageQuota] %s\n", getEffectiveUserQuotaDisplayText(usage.StorageQuota))
+	fmt.Printf("[StorageUsed] %d\n", usage.StorageUsedBytes)
+	fmt.Printf("[EffectiveTransactionQuota] %s\n", getEffectiveUserQuotaDisplayText(usage.TransactionQuota))
+	fmt.Printf("[TransactionUsed] %d\n", usage.TransactionUsedCount)
+}
+
+func getUserQuotaDisplayText(quota int64) string {
+	if quota == models.UserQuotaUseDefault {
+		return "Default"
+	} else if quota < 0 {
+		return "Unlimited"
+	}
+
+	return utils.Int64ToString(quota)
+}
+
+func getEffectiveUserQuotaDisplayText(quota int64) string {
+	if quota <= 0 {
+		return "Unlimited"
+	}
+
+	return utils.Int64ToString(quota)
+}
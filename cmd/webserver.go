@@ -186,11 +186,22 @@ func startWebServer(c *core.CliContext) error {
 	if config.EnableTransactionPictures {
 		pictureRoute := router.Group("/pictures")
 		pictureRoute.Use(bindMiddleware(middlewares.JWTAuthorizationByQueryString(config)))
+		pictureRoute.Use(bindMiddleware(middlewares.CurrentLedger))
 		{
 			pictureRoute.GET("/:fileName", bindImage(api.TransactionPictures.TransactionPictureGetHandler))
 		}
 	}
 
+	if config.EnableTransactionAttachments {
+		attachmentRoute := router.Group("/attachments")
+		attachmentRoute.Use(bindMiddleware(middlewares.JWTAuthorizationByQueryString(config)))
+		attachmentRoute.Use(bindMiddleware(middlewares.CurrentLedger))
+		{
+			attachmentRoute.GET("/:fileName", bindFile(api.TransactionAttachments.TransactionAttachmentGetHandler))
+			attachmentRoute.GET("/thumbnails/:fileName", bindImage(api.TransactionAttachments.TransactionAttachmentThumbnailGetHandler))
+		}
+	}
+
 	router.GET("/healthz.json", bindApi(api.Healths.HealthStatusHandler))
 
 	proxyRoute := router.Group("/proxy")
@@ -316,6 +327,7 @@ func startWebServer(c *core.CliContext) error {
 
 		apiV1Route := apiRoute.Group("/v1")
 		apiV1Route.Use(bindMiddleware(middlewares.JWTAuthorization(config)))
+		apiV1Route.Use(bindMiddleware(middlewares.CurrentLedger))
 		{
 			// Tokens
 			apiV1Route.GET("/tokens/list.json", bindApi(api.Tokens.TokenListHandler))
@@ -367,8 +379,38 @@ func startWebServer(c *core.CliContext) error {
 			if config.EnableDataExport {
 				apiV1Route.GET("/data/export.csv", bindCsv(api.DataManagements.ExportDataToEzbookkeepingCSVHandler))
 				apiV1Route.GET("/data/export.tsv", bindTsv(api.DataManagements.ExportDataToEzbookkeepingTSVHandler))
+				apiV1Route.GET("/data/export.ofx", bindDataFile(api.DataManagements.ExportDataToOFXHandler, "application/x-ofx"))
+				apiV1Route.GET("/data/export.qfx", bindDataFile(api.DataManagements.ExportDataToQFXHandler, "application/vnd.intu.qfx"))
+				apiV1Route.GET("/data/export.qif", bindDataFile(api.DataManagements.ExportDataToQIFHandler, "application/qif"))
+				apiV1Route.GET("/data/export.beancount", bindDataFile(api.DataManagements.ExportDataToBeancountHandler, "text/x-beancount; charset=utf-8"))
+				apiV1Route.GET("/data/export.ledger", bindDataFile(api.DataManagements.ExportDataToLedgerHandler, "text/x-ledger; charset=utf-8"))
+				apiV1Route.GET("/data/export.gnucash", bindDataFile(api.DataManagements.ExportDataToGnuCashHandler, "application/x-gnucash"))
+				apiV1Route.GET("/data/export.xlsx", bindDataFile(api.DataManagements.ExportDataToXlsxHandler, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"))
+				apiV1Route.GET("/data/backup.zip", bindDataFile(api.DataManagements.ExportDataToBackupHandler, "application/zip"))
 			}
 
+			// Ledgers
+			apiV1Route.GET("/ledgers/list.json", bindApi(api.Ledgers.LedgerListHandler))
+			apiV1Route.GET("/ledgers/get.json", bindApi(api.Ledgers.LedgerGetHandler))
+			apiV1Route.POST("/ledgers/add.json", bindApi(api.Ledgers.LedgerCreateHandler))
+			apiV1Route.POST("/ledgers/modify.json", bindApi(api.Ledgers.LedgerModifyHandler))
+			apiV1Route.POST("/ledgers/set_default.json", bindApi(api.Ledgers.LedgerSetDefaultHandler))
+			apiV1Route.POST("/ledgers/move.json", bindApi(api.Ledgers.LedgerMoveHandler))
+			apiV1Route.POST("/ledgers/delete.json", bindApi(api.Ledgers.LedgerDeleteHandler))
+			apiV1Route.POST("/ledgers/leave.json", bindApi(api.LedgerMembers.LedgerLeaveHandler))
+
+			// Ledger Members
+			apiV1Route.GET("/ledgers/members/list.json", bindApi(api.LedgerMembers.LedgerMemberListHandler))
+			apiV1Route.GET("/ledgers/members/statistics.json", bindApi(api.LedgerMembers.LedgerMemberStatisticsHandler))
+			apiV1Route.POST("/ledgers/members/invite.json", bindApi(api.LedgerMembers.LedgerMemberInviteHandler))
+			apiV1Route.POST("/ledgers/members/modify.json", bindApi(api.LedgerMembers.LedgerMemberModifyHandler))
+			apiV1Route.POST("/ledgers/members/remove.json", bindApi(api.LedgerMembers.LedgerMemberRemoveHandler))
+
+			// Ledger Invitations
+			apiV1Route.GET("/ledgers/invitations/list.json", bindApi(api.LedgerMembers.LedgerInvitationListHandler))
+			apiV1Route.POST("/ledgers/invitations/accept.json", bindApi(api.LedgerMembers.LedgerInvitationAcceptHandler))
+			apiV1Route.POST("/ledgers/invitations/decline.json", bindApi(api.LedgerMembers.LedgerInvitationDeclineHandler))
+
 			// Accounts
 			apiV1Route.GET("/accounts/list.json", bindApi(api.Accounts.AccountListHandler))
 			apiV1Route.GET("/accounts/get.json", bindApi(api.Accounts.AccountGetHandler))
@@ -377,6 +419,7 @@ func startWebServer(c *core.CliContext) error {
 			apiV1Route.POST("/accounts/hide.json", bindApi(api.Accounts.AccountHideHandler))
 			apiV1Route.POST("/accounts/move.json", bindApi(api.Accounts.AccountMoveHandler))
 			apiV1Route.POST("/accounts/delete.json", bindApi(api.Accounts.AccountDeleteHandler))
+			apiV1Route.POST("/accounts/merge.json", bindApi(api.Accounts.AccountMergeHandler))
 			apiV1Route.POST("/accounts/sub_account/delete.json", bindApi(api.Accounts.SubAccountDeleteHandler))
 
 			// Transactions
@@ -408,6 +451,12 @@ func startWebServer(c *core.CliContext) error {
 				apiV1Route.POST("/transaction/pictures/remove_unused.json", bindApi(api.TransactionPictures.TransactionPictureRemoveUnusedHandler))
 			}
 
+			// Transaction Attachments
+			if config.EnableTransactionAttachments {
+				apiV1Route.POST("/transaction/attachments/upload.json", bindApi(api.TransactionAttachments.TransactionAttachmentUploadHandler))
+				apiV1Route.POST("/transaction/attachments/remove_unused.json", bindApi(api.TransactionAttachments.TransactionAttachmentRemoveUnusedHandler))
+			}
+
 			// Transaction Categories
 			apiV1Route.GET("/transaction/categories/list.json", bindApi(api.TransactionCategories.CategoryListHandler))
 			apiV1Route.GET("/transaction/categories/get.json", bindApi(api.TransactionCategories.CategoryGetHandler))
@@ -417,6 +466,7 @@ func startWebServer(c *core.CliContext) error {
 			apiV1Route.POST("/transaction/categories/hide.json", bindApi(api.TransactionCategories.CategoryHideHandler))
 			apiV1Route.POST("/transaction/categories/move.json", bindApi(api.TransactionCategories.CategoryMoveHandler))
 			apiV1Route.POST("/transaction/categories/delete.json", bindApi(api.TransactionCategories.CategoryDeleteHandler))
+			apiV1Route.POST("/transaction/categories/merge.json", bindApi(api.TransactionCategories.CategoryMergeHandler))
 
 			// Transaction Tags
 			apiV1Route.GET("/transaction/tags/list.json", bindApi(api.TransactionTags.TagListHandler))
@@ -427,6 +477,46 @@ func startWebServer(c *core.CliContext) error {
 			apiV1Route.POST("/transaction/tags/hide.json", bindApi(api.TransactionTags.TagHideHandler))
 			apiV1Route.POST("/transaction/tags/move.json", bindApi(api.TransactionTags.TagMoveHandler))
 			apiV1Route.POST("/transaction/tags/delete.json", bindApi(api.TransactionTags.TagDeleteHandler))
+			apiV1Route.POST("/transaction/tags/merge.json", bindApi(api.TransactionTags.TagMergeHandler))
+
+			// Transaction Tag Groups
+			apiV1Route.GET("/transaction/tag_groups/list.json", bindApi(api.TransactionTagGroups.TagGroupListHandler))
+			apiV1Route.GET("/transaction/tag_groups/get.json", bindApi(api.TransactionTagGroups.TagGroupGetHandler))
+			apiV1Route.GET("/transaction/tag_groups/statistics.json", bindApi(api.TransactionTagGroups.TagGroupStatisticHandler))
+			apiV1Route.POST("/transaction/tag_groups/add.json", bindApi(api.TransactionTagGroups.TagGroupCreateHandler))
+			apiV1Route.POST("/transaction/tag_groups/modify.json", bindApi(api.TransactionTagGroups.TagGroupModifyHandler))
+			apiV1Route.POST("/transaction/tag_groups/move.json", bindApi(api.TransactionTagGroups.TagGroupMoveHandler))
+			apiV1Route.POST("/transaction/tag_groups/delete.json", bindApi(api.TransactionTagGroups.TagGroupDeleteHandler))
+
+			// Transaction Custom Fields
+			apiV1Route.GET("/transaction/custom_fields/list.json", bindApi(api.TransactionCustomFields.CustomFieldListHandler))
+			apiV1Route.GET("/transaction/custom_fields/get.json", bindApi(api.TransactionCustomFields.CustomFieldGetHandler))
+			apiV1Route.POST("/transaction/custom_fields/add.json", bindApi(api.TransactionCustomFields.CustomFieldCreateHandler))
+			apiV1Route.POST("/transaction/custom_fields/modify.json", bindApi(api.TransactionCustomFields.CustomFieldModifyHandler))
+			apiV1Route.POST("/transaction/custom_fields/move.json", bindApi(api.TransactionCustomFields.CustomFieldMoveHandler))
+			apiV1Route.POST("/transaction/custom_fields/delete.json", bindApi(api.TransactionCustomFields.CustomFieldDeleteHandler))
+
+			// Contacts
+			apiV1Route.GET("/contacts/list.json", bindApi(api.Contacts.ContactListHandler))
+			apiV1Route.GET("/contacts/get.json", bindApi(api.Contacts.ContactGetHandler))
+			apiV1Route.GET("/contacts/statement.json", bindApi(api.Contacts.ContactStatementHandler))
+			apiV1Route.POST("/contacts/add.json", bindApi(api.Contacts.ContactCreateHandler))
+			apiV1Route.POST("/contacts/modify.json", bindApi(api.Contacts.ContactModifyHandler))
+			apiV1Route.POST("/contacts/hide.json", bindApi(api.Contacts.ContactHideHandler))
+			apiV1Route.POST("/contacts/delete.json", bindApi(api.Contacts.ContactDeleteHandler))
+			apiV1Route.POST("/contacts/split.json", bindApi(api.Contacts.ContactSplitHandler))
+			apiV1Route.POST("/contacts/settle.json", bindApi(api.Contacts.ContactSettleHandler))
+
+			// Events
+			apiV1Route.GET("/events/list.json", bindApi(api.Events.EventListHandler))
+			apiV1Route.GET("/events/get.json", bindApi(api.Events.EventGetHandler))
+			apiV1Route.GET("/events/statistics.json", bindApi(api.Events.EventStatisticHandler))
+			apiV1Route.POST("/events/add.json", bindApi(api.Events.EventCreateHandler))
+			apiV1Route.POST("/events/modify.json", bindApi(api.Events.EventModifyHandler))
+			apiV1Route.POST("/events/hide.json", bindApi(api.Events.EventHideHandler))
+			apiV1Route.POST("/events/delete.json", bindApi(api.Events.EventDeleteHandler))
+			apiV1Route.POST("/events/assign.json", bindApi(api.Events.EventAssignTransactionsHandler))
+			apiV1Route.POST("/events/auto_assign.json", bindApi(api.Events.EventAutoAssignTransactionsHandler))
 
 			// Transaction Templates
 			apiV1Route.GET("/transaction/templates/list.json", bindApi(api.TransactionTemplates.TemplateListHandler))
@@ -589,20 +679,33 @@ func bindCachedJs(fn core.DataHandlerFunc, store persistence.CacheStore) gin.Han
 	})
 }
 
-func bindCsv(fn core.DataHandlerFunc) gin.HandlerFunc {
+func bindCsv(fn core.DataStreamHandlerFunc) gin.HandlerFunc {
 	return func(ginCtx *gin.Context) {
 		c := core.WrapWebContext(ginCtx)
-		result, fileName, err := fn(c)
+		writer, fileName, err := fn(c)
+
+		if err != nil {
+			utils.PrintDataErrorResult(c, "text/text", err)
+		} else {
+			utils.PrintDataStreamSuccessResult(c, "text/csv; charset=utf-8", fileName, writer)
+		}
+	}
+}
+
+func bindTsv(fn core.DataStreamHandlerFunc) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		c := core.WrapWebContext(ginCtx)
+		writer, fileName, err := fn(c)
 
 		if err != nil {
 			utils.PrintDataErrorResult(c, "text/text", err)
 		} else {
-			utils.PrintDataSuccessResult(c, "text/csv; charset=utf-8", fileName, result)
+			utils.PrintDataStreamSuccessResult(c, "text/tab-separated-values; charset=utf-8", fileName, writer)
 		}
 	}
 }
 
-func bindTsv(fn core.DataHandlerFunc) gin.HandlerFunc {
+func bindDataFile(fn core.DataHandlerFunc, contentType string) gin.HandlerFunc {
 	return func(ginCtx *gin.Context) {
 		c := core.WrapWebContext(ginCtx)
 		result, fileName, err := fn(c)
@@ -610,7 +713,7 @@ func bindTsv(fn core.DataHandlerFunc) gin.HandlerFunc {
 		if err != nil {
 			utils.PrintDataErrorResult(c, "text/text", err)
 		} else {
-			utils.PrintDataSuccessResult(c, "text/tab-separated-values; charset=utf-8", fileName, result)
+			utils.PrintDataSuccessResult(c, contentType, fileName, result)
 		}
 	}
 }
@@ -628,6 +731,19 @@ func bindImage(fn core.ImageHandlerFunc) gin.HandlerFunc {
 	}
 }
 
+func bindFile(fn core.FileHandlerFunc) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		c := core.WrapWebContext(ginCtx)
+		result, contentType, fileName, err := fn(c)
+
+		if err != nil {
+			utils.PrintDataErrorResult(c, "text/text", err)
+		} else {
+			utils.PrintFileSuccessResult(c, contentType, fileName, result)
+		}
+	}
+}
+
 func bindCachedImage(fn core.ImageHandlerFunc, store persistence.CacheStore) gin.HandlerFunc {
 	return cache.CachePage(store, time.Minute, func(ginCtx *gin.Context) {
 		c := core.WrapWebContext(ginCtx)
@@ -39,6 +39,7 @@ func main() {
 			cmd.Database,
 			cmd.UserData,
 			cmd.CronJobs,
+			cmd.Storage,
 			cmd.SecurityUtils,
 			cmd.Utilities,
 		},
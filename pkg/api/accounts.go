@@ -18,7 +18,9 @@ import (
 type AccountsApi struct {
 	ApiUsingConfig
 	ApiUsingDuplicateChecker
-	accounts *services.AccountService
+	ApiUsingLedgerAccess
+	accounts     *services.AccountService
+	transactions *services.TransactionService
 }
 
 // Initialize an account api singleton instance
@@ -33,7 +35,11 @@ var (
 			},
 			container: duplicatechecker.Container,
 		},
-		accounts: services.Accounts,
+		ApiUsingLedgerAccess: ApiUsingLedgerAccess{
+			ledgerAccesses: services.LedgerAccesses,
+		},
+		accounts:     services.Accounts,
+		transactions: services.Transactions,
 	}
 )
 
@@ -47,8 +53,13 @@ func (a *AccountsApi) AccountListHandler(c *core.WebContext) (any, *errs.Error)
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
-	accounts, err := a.accounts.GetAllAccountsByUid(c, uid)
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_VIEW_BASIC_DATA)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	accounts, err := a.accounts.GetAllAccountsByUid(c, a.GetCurrentLedgerAccess(c))
 
 	if err != nil {
 		log.Errorf(c, "[accounts.AccountListHandler] failed to get all accounts for user \"uid:%d\", because %s", uid, err.Error())
@@ -107,7 +118,12 @@ func (a *AccountsApi) AccountGetHandler(c *core.WebContext) (any, *errs.Error) {
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_VIEW_BASIC_DATA)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
 	accountAndSubAccounts, err := a.accounts.GetAccountAndSubAccountsByAccountId(c, uid, accountGetReq.Id)
 
 	if err != nil {
@@ -128,6 +144,12 @@ func (a *AccountsApi) AccountGetHandler(c *core.WebContext) (any, *errs.Error) {
 		return nil, errs.ErrAccountNotFound
 	}
 
+	errResult = a.CheckDataInCurrentLedger(c, accountResp.LedgerId)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
 	for i := 0; i < len(accountAndSubAccounts); i++ {
 		if accountAndSubAccounts[i].ParentAccountId == accountResp.Id {
 			subAccountResp := accountAndSubAccounts[i].ToAccountInfoResponse()
@@ -231,7 +253,12 @@ func (a *AccountsApi) AccountCreateHandler(c *core.WebContext) (any, *errs.Error
 		return nil, errs.ErrAccountTypeInvalid
 	}
 
-	uid := c.GetCurrentUid()
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_MANAGE_BASIC_DATA)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
 	maxOrderId, err := a.accounts.GetMaxDisplayOrder(c, uid, accountCreateReq.Category)
 
 	if err != nil {
@@ -239,8 +266,8 @@ func (a *AccountsApi) AccountCreateHandler(c *core.WebContext) (any, *errs.Error
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
-	mainAccount := a.createNewAccountModel(uid, &accountCreateReq, false, maxOrderId+1)
-	childrenAccounts, childrenAccountBalanceTimes := a.createSubAccountModels(uid, &accountCreateReq)
+	mainAccount := a.createNewAccountModel(uid, c.GetCurrentLedgerId(), &accountCreateReq, false, maxOrderId+1)
+	childrenAccounts, childrenAccountBalanceTimes := a.createSubAccountModels(uid, c.GetCurrentLedgerId(), &accountCreateReq)
 
 	if a.CurrentConfig().EnableDuplicateSubmissionsCheck && accountCreateReq.ClientSessionId != "" {
 		found, remark := a.GetSubmissionRemark(duplicatechecker.DUPLICATE_CHECKER_TYPE_NEW_ACCOUNT, uid, accountCreateReq.ClientSessionId)
@@ -332,7 +359,12 @@ func (a *AccountsApi) AccountModifyHandler(c *core.WebContext) (any, *errs.Error
 		return nil, errs.ErrCannotSetStatementDateForNonCreditCard
 	}
 
-	uid := c.GetCurrentUid()
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_MANAGE_BASIC_DATA)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
 	accountAndSubAccounts, err := a.accounts.GetAccountAndSubAccountsByAccountId(c, uid, accountModifyReq.Id)
 
 	if err != nil {
@@ -347,6 +379,12 @@ func (a *AccountsApi) AccountModifyHandler(c *core.WebContext) (any, *errs.Error
 		return nil, errs.ErrAccountNotFound
 	}
 
+	errResult = a.CheckDataInCurrentLedger(c, mainAccount.LedgerId)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
 	if accountModifyReq.Currency != nil && mainAccount.Currency != *accountModifyReq.Currency {
 		return nil, errs.ErrNotSupportedChangeCurrency
 	}
@@ -463,7 +501,7 @@ func (a *AccountsApi) AccountModifyHandler(c *core.WebContext) (any, *errs.Error
 		if _, exists := accountMap[subAccountReq.Id]; !exists {
 			anythingUpdate = true
 			maxOrderId = maxOrderId + 1
-			newSubAccount := a.createNewSubAccountModelForModify(uid, mainAccount.Type, subAccountReq, maxOrderId)
+			newSubAccount := a.createNewSubAccountModelForModify(uid, mainAccount.LedgerId, mainAccount.Type, subAccountReq, maxOrderId)
 			toAddAccounts = append(toAddAccounts, newSubAccount)
 
 			if subAccountReq.BalanceTime != nil {
@@ -607,7 +645,18 @@ func (a *AccountsApi) AccountHideHandler(c *core.WebContext) (any, *errs.Error)
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_MANAGE_BASIC_DATA)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	errResult = a.checkAccountInCurrentLedger(c, uid, accountHideReq.Id)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
 	err = a.accounts.HideAccount(c, uid, []int64{accountHideReq.Id}, accountHideReq.Hidden)
 
 	if err != nil {
@@ -629,7 +678,12 @@ func (a *AccountsApi) AccountMoveHandler(c *core.WebContext) (any, *errs.Error)
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_MANAGE_BASIC_DATA)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
 	accounts := make([]*models.Account, len(accountMoveReq.NewDisplayOrders))
 
 	for i := 0; i < len(accountMoveReq.NewDisplayOrders); i++ {
@@ -664,7 +718,18 @@ func (a *AccountsApi) AccountDeleteHandler(c *core.WebContext) (any, *errs.Error
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_MANAGE_BASIC_DATA)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	errResult = a.checkAccountInCurrentLedger(c, uid, accountDeleteReq.Id)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
 	err = a.accounts.DeleteAccount(c, uid, accountDeleteReq.Id)
 
 	if err != nil {
@@ -686,7 +751,18 @@ func (a *AccountsApi) SubAccountDeleteHandler(c *core.WebContext) (any, *errs.Er
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_MANAGE_BASIC_DATA)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	errResult = a.checkAccountInCurrentLedger(c, uid, accountDeleteReq.Id)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
 	err = a.accounts.DeleteSubAccount(c, uid, accountDeleteReq.Id)
 
 	if err != nil {
@@ -698,7 +774,94 @@ func (a *AccountsApi) SubAccountDeleteHandler(c *core.WebContext) (any, *errs.Er
 	return true, nil
 }
 
-func (a *AccountsApi) createNewAccountModel(uid int64, accountCreateReq *models.AccountCreateRequest, isSubAccount bool, order int32) *models.Account {
+// AccountMergeHandler merges an existed account into another one by request parameters for current user
+func (a *AccountsApi) AccountMergeHandler(c *core.WebContext) (any, *errs.Error) {
+	var accountMergeReq models.AccountMergeRequest
+	err := c.ShouldBindJSON(&accountMergeReq)
+
+	if err != nil {
+		log.Warnf(c, "[accounts.AccountMergeHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	if accountMergeReq.FromAccountId == accountMergeReq.ToAccountId {
+		return nil, errs.ErrCannotMergeAccountIntoItself
+	}
+
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_MANAGE_BASIC_DATA)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	accountMap, err := a.accounts.GetAccountsByAccountIds(c, uid, []int64{accountMergeReq.FromAccountId, accountMergeReq.ToAccountId})
+
+	if err != nil {
+		log.Errorf(c, "[accounts.AccountMergeHandler] failed to get accounts for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	fromAccount, exists := accountMap[accountMergeReq.FromAccountId]
+
+	if !exists {
+		return nil, errs.ErrSourceAccountNotFound
+	}
+
+	toAccount, exists := accountMap[accountMergeReq.ToAccountId]
+
+	if !exists {
+		return nil, errs.ErrDestinationAccountNotFound
+	}
+
+	if fromAccount.LedgerId != toAccount.LedgerId {
+		return nil, errs.ErrCannotUseDataInDifferentLedger
+	}
+
+	errResult = a.CheckDataInCurrentLedger(c, fromAccount.LedgerId)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	if fromAccount.Hidden || toAccount.Hidden {
+		return nil, errs.ErrCannotMoveTransactionFromOrToHiddenAccount
+	}
+
+	if fromAccount.Type == models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS || toAccount.Type == models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS {
+		return nil, errs.ErrCannotMoveTransactionFromOrToParentAccount
+	}
+
+	if fromAccount.Currency != toAccount.Currency {
+		return nil, errs.ErrCannotMoveTransactionBetweenAccountsWithDifferentCurrencies
+	}
+
+	err = a.transactions.MergeAccounts(c, uid, accountMergeReq.FromAccountId, accountMergeReq.ToAccountId)
+
+	if err != nil {
+		log.Errorf(c, "[accounts.AccountMergeHandler] failed to merge account \"id:%d\" into account \"id:%d\" for user \"uid:%d\", because %s", accountMergeReq.FromAccountId, accountMergeReq.ToAccountId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[accounts.AccountMergeHandler] user \"uid:%d\" has merged account \"id:%d\" into account \"id:%d\"", uid, accountMergeReq.FromAccountId, accountMergeReq.ToAccountId)
+	return true, nil
+}
+
+func (a *AccountsApi) checkAccountInCurrentLedger(c *core.WebContext, uid int64, accountId int64) *errs.Error {
+	if a.GetCurrentLedgerAccess(c).IsOwner() {
+		return nil
+	}
+
+	account, err := a.accounts.GetAccountByAccountId(c, uid, accountId)
+
+	if err != nil {
+		log.Errorf(c, "[accounts.checkAccountInCurrentLedger] failed to get account \"id:%d\" for user \"uid:%d\", because %s", accountId, uid, err.Error())
+		return errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return a.CheckDataInCurrentLedger(c, account.LedgerId)
+}
+
+func (a *AccountsApi) createNewAccountModel(uid int64, ledgerId int64, accountCreateReq *models.AccountCreateRequest, isSubAccount bool, order int32) *models.Account {
 	accountExtend := &models.AccountExtend{}
 
 	if !isSubAccount && accountCreateReq.Category == models.ACCOUNT_CATEGORY_CREDIT_CARD {
@@ -707,6 +870,7 @@ func (a *AccountsApi) createNewAccountModel(uid int64, accountCreateReq *models.
 
 	return &models.Account{
 		Uid:          uid,
+		LedgerId:     ledgerId,
 		Name:         accountCreateReq.Name,
 		DisplayOrder: order,
 		Category:     accountCreateReq.Category,
@@ -720,11 +884,12 @@ func (a *AccountsApi) createNewAccountModel(uid int64, accountCreateReq *models.
 	}
 }
 
-func (a *AccountsApi) createNewSubAccountModelForModify(uid int64, accountType models.AccountType, accountModifyReq *models.AccountModifyRequest, order int32) *models.Account {
+func (a *AccountsApi) createNewSubAccountModelForModify(uid int64, ledgerId int64, accountType models.AccountType, accountModifyReq *models.AccountModifyRequest, order int32) *models.Account {
 	accountExtend := &models.AccountExtend{}
 
 	return &models.Account{
 		Uid:          uid,
+		LedgerId:     ledgerId,
 		Name:         accountModifyReq.Name,
 		DisplayOrder: order,
 		Category:     accountModifyReq.Category,
@@ -738,7 +903,7 @@ func (a *AccountsApi) createNewSubAccountModelForModify(uid int64, accountType m
 	}
 }
 
-func (a *AccountsApi) createSubAccountModels(uid int64, accountCreateReq *models.AccountCreateRequest) ([]*models.Account, []int64) {
+func (a *AccountsApi) createSubAccountModels(uid int64, ledgerId int64, accountCreateReq *models.AccountCreateRequest) ([]*models.Account, []int64) {
 	if len(accountCreateReq.SubAccounts) <= 0 {
 		return nil, nil
 	}
@@ -747,7 +912,7 @@ func (a *AccountsApi) createSubAccountModels(uid int64, accountCreateReq *models
 	childrenAccountBalanceTimes := make([]int64, len(accountCreateReq.SubAccounts))
 
 	for i := int32(0); i < int32(len(accountCreateReq.SubAccounts)); i++ {
-		childrenAccounts[i] = a.createNewAccountModel(uid, accountCreateReq.SubAccounts[i], true, i+1)
+		childrenAccounts[i] = a.createNewAccountModel(uid, ledgerId, accountCreateReq.SubAccounts[i], true, i+1)
 		childrenAccountBalanceTimes[i] = accountCreateReq.SubAccounts[i].BalanceTime
 	}
 
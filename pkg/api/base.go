@@ -11,11 +11,15 @@ import (
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
 	"github.com/mayswind/ezbookkeeping/pkg/log"
 	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
 	"github.com/mayswind/ezbookkeeping/pkg/settings"
 	"github.com/mayswind/ezbookkeeping/pkg/utils"
 )
 
 const internalTransactionPictureUrlFormat = "%spictures/%d.%s"
+const internalTransactionPictureThumbnailUrlFormat = "%spictures/%d.%s?size=%s"
+const internalTransactionAttachmentUrlFormat = "%sattachments/%d.%s"
+const internalTransactionAttachmentThumbnailUrlFormat = "%sattachments/thumbnails/%d.jpg"
 
 // ApiUsingConfig represents an api that need to use config
 type ApiUsingConfig struct {
@@ -30,7 +34,13 @@ func (a *ApiUsingConfig) CurrentConfig() *settings.Config {
 // GetTransactionPictureInfoResponse returns the view-object of transaction picture basic info according to the transaction picture model
 func (a *ApiUsingConfig) GetTransactionPictureInfoResponse(pictureInfo *models.TransactionPictureInfo) *models.TransactionPictureInfoBasicResponse {
 	originalUrl := fmt.Sprintf(internalTransactionPictureUrlFormat, a.CurrentConfig().RootUrl, pictureInfo.PictureId, pictureInfo.PictureExtension)
-	return pictureInfo.ToTransactionPictureInfoBasicResponse(originalUrl)
+	thumbnailUrl := ""
+
+	if a.CurrentConfig().EnableTransactionPictureThumbnails {
+		thumbnailUrl = fmt.Sprintf(internalTransactionPictureThumbnailUrlFormat, a.CurrentConfig().RootUrl, pictureInfo.PictureId, pictureInfo.PictureExtension, models.TRANSACTION_PICTURE_SIZE_SMALL)
+	}
+
+	return pictureInfo.ToTransactionPictureInfoBasicResponse(originalUrl, thumbnailUrl)
 }
 
 // GetTransactionPictureInfoResponseList returns the view-object list of transaction picture basic info according to the transaction picture model
@@ -46,6 +56,31 @@ func (a *ApiUsingConfig) GetTransactionPictureInfoResponseList(pictureInfos []*m
 	return pictureInfoResps
 }
 
+// GetTransactionAttachmentInfoResponse returns the view-object of transaction attachment basic info according to the transaction attachment model
+func (a *ApiUsingConfig) GetTransactionAttachmentInfoResponse(attachment *models.TransactionAttachment) *models.TransactionAttachmentInfoBasicResponse {
+	originalUrl := fmt.Sprintf(internalTransactionAttachmentUrlFormat, a.CurrentConfig().RootUrl, attachment.AttachmentId, attachment.FileExtension)
+	thumbnailUrl := ""
+
+	if attachment.HasThumbnail {
+		thumbnailUrl = fmt.Sprintf(internalTransactionAttachmentThumbnailUrlFormat, a.CurrentConfig().RootUrl, attachment.AttachmentId)
+	}
+
+	return attachment.ToTransactionAttachmentInfoBasicResponse(originalUrl, thumbnailUrl)
+}
+
+// GetTransactionAttachmentInfoResponseList returns the view-object list of transaction attachment basic info according to the transaction attachment model
+func (a *ApiUsingConfig) GetTransactionAttachmentInfoResponseList(attachments []*models.TransactionAttachment) models.TransactionAttachmentInfoBasicResponseSlice {
+	attachmentResps := make(models.TransactionAttachmentInfoBasicResponseSlice, len(attachments))
+
+	for i := 0; i < len(attachments); i++ {
+		attachmentResps[i] = a.GetTransactionAttachmentInfoResponse(attachments[i])
+	}
+
+	sort.Sort(attachmentResps)
+
+	return attachmentResps
+}
+
 // GetAfterRegisterNotificationContent returns the notification content displayed each time users register
 func (a *ApiUsingConfig) GetAfterRegisterNotificationContent(userLanguage string, clientLanguage string) string {
 	language := userLanguage
@@ -219,3 +254,118 @@ type ApiWithUserInfo struct {
 func (a *ApiWithUserInfo) GetUserBasicInfo(user *models.User) *models.UserBasicInfo {
 	return user.ToUserBasicInfo(a.CurrentConfig().AvatarProvider, a.GetAvatarUrl(user))
 }
+
+// ApiUsingLedgerAccess represents an api that need to check the access of current user to current ledger
+type ApiUsingLedgerAccess struct {
+	ledgerAccesses *services.LedgerAccessService
+}
+
+// GetCurrentLedgerAccess returns the access of current user to current ledger, which is resolved by the ledger middleware
+func (a *ApiUsingLedgerAccess) GetCurrentLedgerAccess(c *core.WebContext) *models.LedgerAccess {
+	role := models.LedgerMemberRole(c.GetCurrentLedgerRole())
+
+	if role == 0 {
+		role = models.LEDGER_MEMBER_ROLE_OWNER
+	}
+
+	return &models.LedgerAccess{
+		LedgerId: c.GetCurrentLedgerId(),
+		OwnerUid: c.GetCurrentLedgerOwnerUid(),
+		Uid:      c.GetCurrentUid(),
+		Role:     role,
+	}
+}
+
+// GetLedgerOwnerUidWithPermission returns the owner uid of current ledger if current user has the specified permission in current ledger
+func (a *ApiUsingLedgerAccess) GetLedgerOwnerUidWithPermission(c *core.WebContext, permission models.LedgerPermission) (int64, *errs.Error) {
+	ownerUid, _, err := a.ledgerAccesses.GetLedgerOwnerUid(c, a.GetCurrentLedgerAccess(c), permission)
+
+	if err != nil {
+		return 0, errs.Or(err, errs.ErrLedgerPermissionDenied)
+	}
+
+	return ownerUid, nil
+}
+
+// CheckDataInCurrentLedger returns ErrLedgerPermissionDenied if the data does not belong to current ledger
+func (a *ApiUsingLedgerAccess) CheckDataInCurrentLedger(c *core.WebContext, ledgerId int64) *errs.Error {
+	access := a.GetCurrentLedgerAccess(c)
+	err := a.ledgerAccesses.CheckDataInLedger(access, ledgerId)
+
+	if err != nil {
+		log.Warnf(c, "[base.CheckDataInCurrentLedger] user \"uid:%d\" cannot access data of ledger \"id:%d\" in ledger \"id:%d\"", access.Uid, ledgerId, access.LedgerId)
+		return errs.Or(err, errs.ErrLedgerPermissionDenied)
+	}
+
+	return nil
+}
+
+// CheckTransactionModifiable returns ErrLedgerPermissionDenied if current user cannot modify or delete the specified transaction in current ledger
+func (a *ApiUsingLedgerAccess) CheckTransactionModifiable(c *core.WebContext, transaction *models.Transaction) *errs.Error {
+	access := a.GetCurrentLedgerAccess(c)
+	err := a.ledgerAccesses.CheckTransactionModifiable(access, transaction)
+
+	if err != nil {
+		log.Warnf(c, "[base.CheckTransactionModifiable] user \"uid:%d\" cannot modify transaction \"id:%d\" in ledger \"id:%d\"", access.Uid, transaction.TransactionId, access.LedgerId)
+		return errs.Or(err, errs.ErrLedgerPermissionDenied)
+	}
+
+	return nil
+}
+
+// CheckTransactionViewable returns ErrLedgerPermissionDenied if current user cannot view the specified transaction in current ledger
+func (a *ApiUsingLedgerAccess) CheckTransactionViewable(c *core.WebContext, transaction *models.Transaction) *errs.Error {
+	access := a.GetCurrentLedgerAccess(c)
+	err := a.ledgerAccesses.CheckTransactionViewable(access, transaction)
+
+	if err != nil {
+		log.Warnf(c, "[base.CheckTransactionViewable] user \"uid:%d\" cannot view transaction \"id:%d\" in ledger \"id:%d\"", access.Uid, transaction.TransactionId, access.LedgerId)
+		return errs.Or(err, errs.ErrLedgerPermissionDenied)
+	}
+
+	return nil
+}
+
+// ApiUsingUserQuota represents an api that need to check the storage or transaction quota of user
+type ApiUsingUserQuota struct {
+	users      *services.UserService
+	userQuotas *services.UserQuotaService
+}
+
+// CheckUserStorageQuota returns ErrUserStorageQuotaExceeded if the storage quota of the specified user would be exceeded after adding the specified bytes
+func (a *ApiUsingUserQuota) CheckUserStorageQuota(c *core.WebContext, uid int64, additionalBytes int64) *errs.Error {
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[base.CheckUserStorageQuota] failed to get user \"uid:%d\" info, because %s", uid, err.Error())
+		return errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	err = a.userQuotas.CheckStorageQuota(c, user, additionalBytes)
+
+	if err != nil {
+		log.Warnf(c, "[base.CheckUserStorageQuota] cannot add \"%d\" bytes for user \"uid:%d\", because %s", additionalBytes, uid, err.Error())
+		return errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return nil
+}
+
+// CheckUserTransactionQuota returns ErrUserTransactionQuotaExceeded if the transaction quota of the specified user would be exceeded after adding the specified count of transactions
+func (a *ApiUsingUserQuota) CheckUserTransactionQuota(c *core.WebContext, uid int64, additionalCount int64) *errs.Error {
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[base.CheckUserTransactionQuota] failed to get user \"uid:%d\" info, because %s", uid, err.Error())
+		return errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	err = a.userQuotas.CheckTransactionQuota(c, user, additionalCount)
+
+	if err != nil {
+		log.Warnf(c, "[base.CheckUserTransactionQuota] cannot add \"%d\" transactions for user \"uid:%d\", because %s", additionalCount, uid, err.Error())
+		return errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return nil
+}
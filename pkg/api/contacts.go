@@ -0,0 +1,628 @@
+package api
+
+import (
+	"sort"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// ContactsApi represents contact api
+type ContactsApi struct {
+	ApiUsingLedgerAccess
+	ApiUsingUserQuota
+	contacts     *services.ContactService
+	accounts     *services.AccountService
+	transactions *services.TransactionService
+}
+
+// Initialize a contact api singleton instance
+var (
+	Contacts = &ContactsApi{
+		ApiUsingLedgerAccess: ApiUsingLedgerAccess{
+			ledgerAccesses: services.LedgerAccesses,
+		},
+		ApiUsingUserQuota: ApiUsingUserQuota{
+			users:      services.Users,
+			userQuotas: services.UserQuotas,
+		},
+		contacts:     services.Contacts,
+		accounts:     services.Accounts,
+		transactions: services.Transactions,
+	}
+)
+
+// ContactListHandler returns contact list with balances of current user
+func (a *ContactsApi) ContactListHandler(c *core.WebContext) (any, *errs.Error) {
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_VIEW_BASIC_DATA)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	contacts, err := a.contacts.GetAllContactsByUid(c, a.GetCurrentLedgerAccess(c))
+
+	if err != nil {
+		log.Errorf(c, "[contacts.ContactListHandler] failed to get contacts for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	balances, err := a.contacts.GetAllContactBalances(c, a.GetCurrentLedgerAccess(c))
+
+	if err != nil {
+		log.Errorf(c, "[contacts.ContactListHandler] failed to get contact balances for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	contactResps := make(models.ContactInfoResponseSlice, len(contacts))
+
+	for i := 0; i < len(contacts); i++ {
+		contactResps[i] = contacts[i].ToContactInfoResponse(balances[contacts[i].ContactId])
+	}
+
+	sort.Sort(contactResps)
+
+	return contactResps, nil
+}
+
+// ContactGetHandler returns one specific contact with balances of current user
+func (a *ContactsApi) ContactGetHandler(c *core.WebContext) (any, *errs.Error) {
+	var contactGetReq models.ContactGetRequest
+	err := c.ShouldBindQuery(&contactGetReq)
+
+	if err != nil {
+		log.Warnf(c, "[contacts.ContactGetHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_VIEW_BASIC_DATA)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	contact, contactTransactions, errResult := a.getContactAndContactTransactions(c, uid, contactGetReq.Id)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	contactResp := contact.ToContactInfoResponse(a.getContactBalances(contactTransactions))
+
+	return contactResp, nil
+}
+
+// ContactCreateHandler saves a new contact by request parameters for current user
+func (a *ContactsApi) ContactCreateHandler(c *core.WebContext) (any, *errs.Error) {
+	var contactCreateReq models.ContactCreateRequest
+	err := c.ShouldBindJSON(&contactCreateReq)
+
+	if err != nil {
+		log.Warnf(c, "[contacts.ContactCreateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_MANAGE_BASIC_DATA)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	maxOrderId, err := a.contacts.GetMaxDisplayOrder(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[contacts.ContactCreateHandler] failed to get max display order for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	contact := &models.Contact{
+		Uid:          uid,
+		LedgerId:     c.GetCurrentLedgerId(),
+		Name:         contactCreateReq.Name,
+		DisplayOrder: maxOrderId + 1,
+		Comment:      contactCreateReq.Comment,
+	}
+
+	err = a.contacts.CreateContact(c, contact)
+
+	if err != nil {
+		log.Errorf(c, "[contacts.ContactCreateHandler] failed to create contact \"id:%d\" for user \"uid:%d\", because %s", contact.ContactId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[contacts.ContactCreateHandler] user \"uid:%d\" has created a new contact \"id:%d\" successfully", uid, contact.ContactId)
+
+	contactResp := contact.ToContactInfoResponse(nil)
+
+	return contactResp, nil
+}
+
+// ContactModifyHandler saves an existed contact by request parameters for current user
+func (a *ContactsApi) ContactModifyHandler(c *core.WebContext) (any, *errs.Error) {
+	var contactModifyReq models.ContactModifyRequest
+	err := c.ShouldBindJSON(&contactModifyReq)
+
+	if err != nil {
+		log.Warnf(c, "[contacts.ContactModifyHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_MANAGE_BASIC_DATA)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	contact, contactTransactions, errResult := a.getContactAndContactTransactions(c, uid, contactModifyReq.Id)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	newContact := &models.Contact{
+		ContactId: contact.ContactId,
+		Uid:       uid,
+		LedgerId:  contact.LedgerId,
+		Name:      contactModifyReq.Name,
+		Comment:   contactModifyReq.Comment,
+	}
+
+	if newContact.Name == contact.Name && newContact.Comment == contact.Comment {
+		return nil, errs.ErrNothingWillBeUpdated
+	}
+
+	err = a.contacts.ModifyContact(c, newContact)
+
+	if err != nil {
+		log.Errorf(c, "[contacts.ContactModifyHandler] failed to update contact \"id:%d\" for user \"uid:%d\", because %s", contactModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[contacts.ContactModifyHandler] user \"uid:%d\" has updated contact \"id:%d\" successfully", uid, contactModifyReq.Id)
+
+	contact.Name = newContact.Name
+	contact.Comment = newContact.Comment
+	contactResp := contact.ToContactInfoResponse(a.getContactBalances(contactTransactions))
+
+	return contactResp, nil
+}
+
+// ContactHideHandler hides a contact by request parameters for current user
+func (a *ContactsApi) ContactHideHandler(c *core.WebContext) (any, *errs.Error) {
+	var contactHideReq models.ContactHideRequest
+	err := c.ShouldBindJSON(&contactHideReq)
+
+	if err != nil {
+		log.Warnf(c, "[contacts.ContactHideHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_MANAGE_BASIC_DATA)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	errResult = a.checkContactInCurrentLedger(c, uid, contactHideReq.Id)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	err = a.contacts.HideContact(c, uid, []int64{contactHideReq.Id}, contactHideReq.Hidden)
+
+	if err != nil {
+		log.Errorf(c, "[contacts.ContactHideHandler] failed to hide contact \"id:%d\" for user \"uid:%d\", because %s", contactHideReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[contacts.ContactHideHandler] user \"uid:%d\" has hidden contact \"id:%d\"", uid, contactHideReq.Id)
+	return true, nil
+}
+
+// ContactDeleteHandler deletes an existed contact by request parameters for current user
+func (a *ContactsApi) ContactDeleteHandler(c *core.WebContext) (any, *errs.Error) {
+	var contactDeleteReq models.ContactDeleteRequest
+	err := c.ShouldBindJSON(&contactDeleteReq)
+
+	if err != nil {
+		log.Warnf(c, "[contacts.ContactDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_MANAGE_BASIC_DATA)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	errResult = a.checkContactInCurrentLedger(c, uid, contactDeleteReq.Id)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	err = a.contacts.DeleteContact(c, uid, contactDeleteReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[contacts.ContactDeleteHandler] failed to delete contact \"id:%d\" for user \"uid:%d\", because %s", contactDeleteReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[contacts.ContactDeleteHandler] user \"uid:%d\" has deleted contact \"id:%d\"", uid, contactDeleteReq.Id)
+	return true, nil
+}
+
+// ContactSplitHandler splits an expense between current user and contacts, and records the receivables or debts of contacts
+func (a *ContactsApi) ContactSplitHandler(c *core.WebContext) (any, *errs.Error) {
+	var splitReq models.ContactSplitRequest
+	err := c.ShouldBindJSON(&splitReq)
+
+	if err != nil {
+		log.Warnf(c, "[contacts.ContactSplitHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_CREATE_TRANSACTIONS)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	amounts, err := a.contacts.CalculateSplitAmounts(splitReq.Amount, splitReq.Method, splitReq.Participants)
+
+	if err != nil {
+		log.Warnf(c, "[contacts.ContactSplitHandler] failed to calculate split amounts for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	selfAmount := int64(0)
+	contactIds := make([]int64, 0, len(splitReq.Participants))
+
+	for i := 0; i < len(splitReq.Participants); i++ {
+		if splitReq.Participants[i].ContactId == 0 {
+			selfAmount = amounts[i]
+		} else {
+			contactIds = append(contactIds, splitReq.Participants[i].ContactId)
+		}
+	}
+
+	if splitReq.PaidByContactId > 0 {
+		contactIds = append(contactIds, splitReq.PaidByContactId)
+	}
+
+	if len(contactIds) > 0 {
+		contacts, err := a.contacts.GetContactsByContactIds(c, uid, utils.ToUniqueInt64Slice(contactIds))
+
+		if err != nil {
+			log.Errorf(c, "[contacts.ContactSplitHandler] failed to get contacts for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+
+		for i := 0; i < len(contactIds); i++ {
+			contact, exists := contacts[contactIds[i]]
+
+			if !exists {
+				return nil, errs.ErrContactNotFound
+			}
+
+			errResult = a.CheckDataInCurrentLedger(c, contact.LedgerId)
+
+			if errResult != nil {
+				return nil, errResult
+			}
+		}
+	}
+
+	creatorUid := c.GetCurrentUid()
+	ledgerId := c.GetCurrentLedgerId()
+	clientIp := c.ClientIP()
+	transactions := make([]*models.Transaction, 0, len(splitReq.Participants))
+	contactTransactions := make([]*models.ContactTransaction, 0, len(splitReq.Participants))
+
+	if splitReq.PaidByContactId == 0 {
+		sourceAccount, receivableAccount, errResult := a.getContactAccounts(c, uid, splitReq.SourceAccountId, splitReq.ReceivableAccountId, models.ACCOUNT_CATEGORY_RECEIVABLES, len(contactIds) > 0)
+
+		if errResult != nil {
+			return nil, errResult
+		}
+
+		if selfAmount > 0 {
+			transactions = append(transactions, a.createNewTransactionModel(uid, creatorUid, ledgerId, models.TRANSACTION_DB_TYPE_EXPENSE, splitReq.CategoryId, splitReq.Time, splitReq.UtcOffset, sourceAccount.AccountId, 0, selfAmount, splitReq.Comment, clientIp))
+			contactTransactions = append(contactTransactions, nil)
+		}
+
+		for i := 0; i < len(splitReq.Participants); i++ {
+			participant := splitReq.Participants[i]
+
+			if participant.ContactId == 0 || amounts[i] == 0 {
+				continue
+			}
+
+			transactions = append(transactions, a.createNewTransactionModel(uid, creatorUid, ledgerId, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, splitReq.TransferCategoryId, splitReq.Time, splitReq.UtcOffset, sourceAccount.AccountId, receivableAccount.AccountId, amounts[i], splitReq.Comment, clientIp))
+			contactTransactions = append(contactTransactions, &models.ContactTransaction{
+				ContactId: participant.ContactId,
+				Type:      models.CONTACT_TRANSACTION_TYPE_SPLIT,
+				AccountId: receivableAccount.AccountId,
+				Currency:  receivableAccount.Currency,
+				Amount:    amounts[i],
+			})
+		}
+	} else {
+		if selfAmount == 0 {
+			return nil, errs.ErrSplitPayerInvalid
+		}
+
+		_, debtAccount, errResult := a.getContactAccounts(c, uid, 0, splitReq.DebtAccountId, models.ACCOUNT_CATEGORY_DEBT, true)
+
+		if errResult != nil {
+			return nil, errResult
+		}
+
+		transactions = append(transactions, a.createNewTransactionModel(uid, creatorUid, ledgerId, models.TRANSACTION_DB_TYPE_EXPENSE, splitReq.CategoryId, splitReq.Time, splitReq.UtcOffset, debtAccount.AccountId, 0, selfAmount, splitReq.Comment, clientIp))
+		contactTransactions = append(contactTransactions, &models.ContactTransaction{
+			ContactId: splitReq.PaidByContactId,
+			Type:      models.CONTACT_TRANSACTION_TYPE_SPLIT,
+			AccountId: debtAccount.AccountId,
+			Currency:  debtAccount.Currency,
+			Amount:    -selfAmount,
+		})
+	}
+
+	if len(transactions) < 1 {
+		return nil, errs.ErrSplitParticipantsEmpty
+	}
+
+	for i := 0; i < len(transactions); i++ {
+		transactions[i].EventId = splitReq.EventId
+	}
+
+	errResult = a.CheckUserTransactionQuota(c, uid, int64(len(transactions)))
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	err = a.transactions.CreateContactTransactions(c, uid, transactions, contactTransactions)
+
+	if err != nil {
+		log.Errorf(c, "[contacts.ContactSplitHandler] failed to create split transactions for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[contacts.ContactSplitHandler] user \"uid:%d\" has split an expense into %d transactions successfully", uid, len(transactions))
+
+	splitResp := &models.ContactSplitResponse{
+		TransactionIds: make([]string, len(transactions)),
+	}
+
+	for i := 0; i < len(transactions); i++ {
+		splitResp.TransactionIds[i] = utils.Int64ToString(transactions[i].TransactionId)
+	}
+
+	return splitResp, nil
+}
+
+// ContactSettleHandler saves a settle-up transaction between current user and contact
+func (a *ContactsApi) ContactSettleHandler(c *core.WebContext) (any, *errs.Error) {
+	var settleReq models.ContactSettleRequest
+	err := c.ShouldBindJSON(&settleReq)
+
+	if err != nil {
+		log.Warnf(c, "[contacts.ContactSettleHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_CREATE_TRANSACTIONS)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	errResult = a.checkContactInCurrentLedger(c, uid, settleReq.ContactId)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	account, contactAccount, errResult := a.getContactAccounts(c, uid, settleReq.AccountId, settleReq.ContactAccountId, 0, true)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	var transaction *models.Transaction
+	contactTransaction := &models.ContactTransaction{
+		ContactId: settleReq.ContactId,
+		Type:      models.CONTACT_TRANSACTION_TYPE_SETTLE,
+		AccountId: contactAccount.AccountId,
+		Currency:  contactAccount.Currency,
+	}
+
+	if contactAccount.Category == models.ACCOUNT_CATEGORY_RECEIVABLES {
+		transaction = a.createNewTransactionModel(uid, c.GetCurrentUid(), c.GetCurrentLedgerId(), models.TRANSACTION_DB_TYPE_TRANSFER_OUT, settleReq.CategoryId, settleReq.Time, settleReq.UtcOffset, contactAccount.AccountId, account.AccountId, settleReq.Amount, settleReq.Comment, c.ClientIP())
+		contactTransaction.Amount = -settleReq.Amount
+	} else {
+		transaction = a.createNewTransactionModel(uid, c.GetCurrentUid(), c.GetCurrentLedgerId(), models.TRANSACTION_DB_TYPE_TRANSFER_OUT, settleReq.CategoryId, settleReq.Time, settleReq.UtcOffset, account.AccountId, contactAccount.AccountId, settleReq.Amount, settleReq.Comment, c.ClientIP())
+		contactTransaction.Amount = settleReq.Amount
+	}
+
+	errResult = a.CheckUserTransactionQuota(c, uid, 1)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	err = a.transactions.CreateContactTransactions(c, uid, []*models.Transaction{transaction}, []*models.ContactTransaction{contactTransaction})
+
+	if err != nil {
+		log.Errorf(c, "[contacts.ContactSettleHandler] failed to create settle-up transaction with contact \"id:%d\" for user \"uid:%d\", because %s", settleReq.ContactId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[contacts.ContactSettleHandler] user \"uid:%d\" has settled up with contact \"id:%d\" successfully", uid, settleReq.ContactId)
+
+	transactionResp := transaction.ToTransactionInfoResponse(nil, true)
+
+	return transactionResp, nil
+}
+
+// ContactStatementHandler returns all receivable and payable movements with running balances of one specific contact of current user
+func (a *ContactsApi) ContactStatementHandler(c *core.WebContext) (any, *errs.Error) {
+	var statementReq models.ContactStatementRequest
+	err := c.ShouldBindQuery(&statementReq)
+
+	if err != nil {
+		log.Warnf(c, "[contacts.ContactStatementHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_VIEW_TRANSACTIONS)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	contact, contactTransactions, errResult := a.getContactAndContactTransactions(c, uid, statementReq.Id)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	statementResp := &models.ContactStatementResponse{
+		Contact: contact.ToContactInfoResponse(a.getContactBalances(contactTransactions)),
+		Items:   models.ToContactStatementItemResponses(contactTransactions),
+	}
+
+	return statementResp, nil
+}
+
+func (a *ContactsApi) checkContactInCurrentLedger(c *core.WebContext, uid int64, contactId int64) *errs.Error {
+	contact, err := a.contacts.GetContactByContactId(c, uid, contactId)
+
+	if err != nil {
+		log.Errorf(c, "[contacts.checkContactInCurrentLedger] failed to get contact \"id:%d\" for user \"uid:%d\", because %s", contactId, uid, err.Error())
+		return errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return a.CheckDataInCurrentLedger(c, contact.LedgerId)
+}
+
+func (a *ContactsApi) getContactAndContactTransactions(c *core.WebContext, uid int64, contactId int64) (*models.Contact, []*models.ContactTransaction, *errs.Error) {
+	contact, err := a.contacts.GetContactByContactId(c, uid, contactId)
+
+	if err != nil {
+		log.Errorf(c, "[contacts.getContactAndContactTransactions] failed to get contact \"id:%d\" for user \"uid:%d\", because %s", contactId, uid, err.Error())
+		return nil, nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	errResult := a.CheckDataInCurrentLedger(c, contact.LedgerId)
+
+	if errResult != nil {
+		return nil, nil, errResult
+	}
+
+	contactTransactions, err := a.contacts.GetAllContactTransactionsByContactId(c, uid, contactId)
+
+	if err != nil {
+		log.Errorf(c, "[contacts.getContactAndContactTransactions] failed to get transactions of contact \"id:%d\" for user \"uid:%d\", because %s", contactId, uid, err.Error())
+		return nil, nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return contact, contactTransactions, nil
+}
+
+func (a *ContactsApi) getContactBalances(contactTransactions []*models.ContactTransaction) map[string]int64 {
+	balances := make(map[string]int64)
+
+	for i := 0; i < len(contactTransactions); i++ {
+		balances[contactTransactions[i].Currency] += contactTransactions[i].Amount
+	}
+
+	return balances
+}
+
+// getContactAccounts returns the account which the money comes from or goes to and the receivables or debt account of contact,
+// the category of contact account is not limited if the expected category is zero
+func (a *ContactsApi) getContactAccounts(c *core.WebContext, uid int64, accountId int64, contactAccountId int64, expectedCategory models.AccountCategory, contactAccountRequired bool) (*models.Account, *models.Account, *errs.Error) {
+	accountIds := make([]int64, 0, 2)
+
+	if accountId > 0 {
+		accountIds = append(accountIds, accountId)
+	}
+
+	if contactAccountId > 0 {
+		accountIds = append(accountIds, contactAccountId)
+	} else if contactAccountRequired {
+		return nil, nil, errs.ErrContactAccountCategoryInvalid
+	}
+
+	if len(accountIds) < 1 {
+		return nil, nil, errs.ErrAccountNotFound
+	}
+
+	accountMap, err := a.accounts.GetAccountsByAccountIds(c, uid, accountIds)
+
+	if err != nil {
+		log.Errorf(c, "[contacts.getContactAccounts] failed to get accounts for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	for i := 0; i < len(accountIds); i++ {
+		account, exists := accountMap[accountIds[i]]
+
+		if !exists {
+			return nil, nil, errs.ErrAccountNotFound
+		}
+
+		errResult := a.CheckDataInCurrentLedger(c, account.LedgerId)
+
+		if errResult != nil {
+			return nil, nil, errResult
+		}
+	}
+
+	account := accountMap[accountId]
+	contactAccount := accountMap[contactAccountId]
+
+	if contactAccount != nil {
+		if expectedCategory != 0 && contactAccount.Category != expectedCategory {
+			return nil, nil, errs.ErrContactAccountCategoryInvalid
+		} else if contactAccount.Category != models.ACCOUNT_CATEGORY_RECEIVABLES && contactAccount.Category != models.ACCOUNT_CATEGORY_DEBT {
+			return nil, nil, errs.ErrContactAccountCategoryInvalid
+		}
+	}
+
+	if account != nil && contactAccount != nil && account.Currency != contactAccount.Currency {
+		return nil, nil, errs.ErrContactAccountsCurrencyNotEqual
+	}
+
+	return account, contactAccount, nil
+}
+
+func (a *ContactsApi) createNewTransactionModel(uid int64, creatorUid int64, ledgerId int64, transactionDbType models.TransactionDbType, categoryId int64, transactionTime int64, utcOffset int16, accountId int64, relatedAccountId int64, amount int64, comment string, clientIp string) *models.Transaction {
+	transaction := &models.Transaction{
+		Uid:               uid,
+		LedgerId:          ledgerId,
+		Type:              transactionDbType,
+		CategoryId:        categoryId,
+		TransactionTime:   utils.GetMinTransactionTimeFromUnixTime(transactionTime),
+		TimezoneUtcOffset: utcOffset,
+		AccountId:         accountId,
+		Amount:            amount,
+		Comment:           comment,
+		CreatedIp:         clientIp,
+		CreatorUid:        creatorUid,
+	}
+
+	if transactionDbType == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+		transaction.RelatedAccountId = relatedAccountId
+		transaction.RelatedAccountAmount = amount
+	}
+
+	return transaction
+}
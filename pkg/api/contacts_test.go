@@ -0,0 +1,107 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/testutils"
+)
+
+func TestContactSplitHandler_TransactionQuotaExhausted(t *testing.T) {
+	c := initializeTestContactSplitData(t, 3)
+	testutils.InsertTestData(t, 1, &models.Transaction{TransactionId: 4001, Uid: 1, LedgerId: 1001, Type: models.TRANSACTION_DB_TYPE_EXPENSE, AccountId: 2001, TransactionTime: 1})
+
+	result, errResult := Contacts.ContactSplitHandler(createTestContactSplitWebContext(t))
+
+	assert.Nil(t, result)
+	assert.Equal(t, errs.ErrUserTransactionQuotaExceeded, errResult)
+
+	count, err := datastore.Container.UserDataStore.Choose(1).NewSession(c).Where("uid=?", 1).Count(&models.Transaction{})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), count)
+
+	count, err = datastore.Container.UserDataStore.Choose(1).NewSession(c).Where("uid=?", 1).Count(&models.ContactTransaction{})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), count)
+}
+
+func TestContactSplitHandler_TransactionQuotaEnough(t *testing.T) {
+	c := initializeTestContactSplitData(t, 3)
+
+	result, errResult := Contacts.ContactSplitHandler(createTestContactSplitWebContext(t))
+
+	assert.Nil(t, errResult)
+	assert.Len(t, result.(*models.ContactSplitResponse).TransactionIds, 3)
+
+	count, err := datastore.Container.UserDataStore.Choose(1).NewSession(c).Where("uid=?", 1).Count(&models.ContactTransaction{})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), count)
+}
+
+func initializeTestContactSplitData(t *testing.T, transactionQuota int64) core.Context {
+	c := testutils.InitializeTestDataStore(t)
+
+	_, err := datastore.Container.UserStore.Choose(1).NewSession(c).Insert(&models.User{
+		Uid:              1,
+		Username:         "user1",
+		Email:            "user1@example.com",
+		TransactionQuota: transactionQuota,
+	})
+	assert.Nil(t, err)
+
+	testutils.InsertTestData(t, 1,
+		&models.Ledger{LedgerId: 1001, Uid: 1, Name: "Personal", IsDefault: true},
+		&models.Account{AccountId: 2001, Uid: 1, LedgerId: 1001, Name: "Cash", Category: models.ACCOUNT_CATEGORY_CASH, Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Currency: "USD"},
+		&models.Account{AccountId: 2002, Uid: 1, LedgerId: 1001, Name: "Receivables", Category: models.ACCOUNT_CATEGORY_RECEIVABLES, Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Currency: "USD"},
+		&models.TransactionCategory{CategoryId: 5000, Uid: 1, LedgerId: 1001, Name: "Food", Type: models.CATEGORY_TYPE_EXPENSE, ParentCategoryId: models.LevelOneTransactionCategoryParentId},
+		&models.TransactionCategory{CategoryId: 5001, Uid: 1, LedgerId: 1001, Name: "Meals", Type: models.CATEGORY_TYPE_EXPENSE, ParentCategoryId: 5000},
+		&models.TransactionCategory{CategoryId: 5010, Uid: 1, LedgerId: 1001, Name: "Transfer", Type: models.CATEGORY_TYPE_TRANSFER, ParentCategoryId: models.LevelOneTransactionCategoryParentId},
+		&models.TransactionCategory{CategoryId: 5002, Uid: 1, LedgerId: 1001, Name: "Lending", Type: models.CATEGORY_TYPE_TRANSFER, ParentCategoryId: 5010},
+		&models.Contact{ContactId: 3001, Uid: 1, LedgerId: 1001, Name: "Alice"},
+		&models.Contact{ContactId: 3002, Uid: 1, LedgerId: 1001, Name: "Bob"},
+	)
+
+	return c
+}
+
+func createTestContactSplitWebContext(t *testing.T) *core.WebContext {
+	body, err := json.Marshal(&models.ContactSplitRequest{
+		CategoryId:          5001,
+		TransferCategoryId:  5002,
+		Time:                1700000000000,
+		Amount:              3000,
+		Method:              models.SPLIT_METHOD_EQUAL,
+		SourceAccountId:     2001,
+		ReceivableAccountId: 2002,
+		Participants: []*models.ContactSplitParticipantRequest{
+			{ContactId: 0},
+			{ContactId: 3001},
+			{ContactId: 3002},
+		},
+	})
+	assert.Nil(t, err)
+
+	ginContext, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ginContext.Request = httptest.NewRequest(http.MethodPost, "/api/v1/contacts/split.json", bytes.NewReader(body))
+	ginContext.Request.Header.Set("Content-Type", "application/json")
+
+	c := core.WrapWebContext(ginContext)
+	c.SetTokenClaims(&core.UserTokenClaims{
+		Uid: 1,
+	})
+	c.SetCurrentLedgerId(1001)
+	c.SetCurrentLedgerOwnerUid(1)
+	c.SetCurrentLedgerRole(byte(models.LEDGER_MEMBER_ROLE_OWNER))
+
+	return c
+}
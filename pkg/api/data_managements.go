@@ -2,11 +2,14 @@ package api
 
 import (
 	"fmt"
+	"io"
 	"math"
 	"strings"
 	"time"
 
+	"github.com/mayswind/ezbookkeeping/pkg/backup"
 	"github.com/mayswind/ezbookkeeping/pkg/converters"
+	"github.com/mayswind/ezbookkeeping/pkg/converters/converter"
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
 	"github.com/mayswind/ezbookkeeping/pkg/log"
@@ -19,18 +22,50 @@ import (
 const pageCountForClearTransactions = 1000
 const pageCountForDataExport = 1000
 
+// transactionDataExportContext represents the user essential data and the transaction conditions for exporting transactions
+type transactionDataExportContext struct {
+	user               *models.User
+	clientTimezone     *time.Location
+	exporterOptions    converter.TransactionDataExporterOptions
+	accountMap         map[int64]*models.Account
+	categoryMap        map[int64]*models.TransactionCategory
+	tagMap             map[int64]*models.TransactionTag
+	customFields       []*models.TransactionCustomField
+	ledgerOwnerUid     int64
+	ledgerAccess       *models.LedgerAccess
+	maxTransactionTime int64
+	minTransactionTime int64
+	transactionType    models.TransactionType
+	categoryIds        []int64
+	accountIds         []int64
+	tagFilters         []*models.TransactionTagFilter
+	noTags             bool
+	amountFilter       string
+	keyword            string
+}
+
 // DataManagementsApi represents data management api
 type DataManagementsApi struct {
 	ApiUsingConfig
+	ApiUsingLedgerAccess
 	tokens                  *services.TokenService
 	users                   *services.UserService
+	ledgers                 *services.LedgerService
+	ledgerMembers           *services.LedgerMemberService
+	contacts                *services.ContactService
+	events                  *services.EventService
 	accounts                *services.AccountService
 	transactions            *services.TransactionService
 	categories              *services.TransactionCategoryService
 	tags                    *services.TransactionTagService
+	tagGroups               *services.TransactionTagGroupService
+	customFields            *services.TransactionCustomFieldService
 	pictures                *services.TransactionPictureService
+	attachments             *services.TransactionAttachmentService
 	templates               *services.TransactionTemplateService
 	userCustomExchangeRates *services.UserCustomExchangeRatesService
+	userQuotas              *services.UserQuotaService
+	userDataBackups         *services.UserDataBackupService
 }
 
 // Initialize a data management api singleton instance
@@ -39,26 +74,131 @@ var (
 		ApiUsingConfig: ApiUsingConfig{
 			container: settings.Container,
 		},
+		ApiUsingLedgerAccess: ApiUsingLedgerAccess{
+			ledgerAccesses: services.LedgerAccesses,
+		},
 		tokens:                  services.Tokens,
 		users:                   services.Users,
+		ledgers:                 services.Ledgers,
+		ledgerMembers:           services.LedgerMembers,
+		contacts:                services.Contacts,
+		events:                  services.Events,
 		accounts:                services.Accounts,
 		transactions:            services.Transactions,
 		categories:              services.TransactionCategories,
 		tags:                    services.TransactionTags,
+		tagGroups:               services.TransactionTagGroups,
+		customFields:            services.TransactionCustomFields,
 		pictures:                services.TransactionPictures,
+		attachments:             services.TransactionAttachments,
 		templates:               services.TransactionTemplates,
 		userCustomExchangeRates: services.UserCustomExchangeRates,
+		userQuotas:              services.UserQuotas,
+		userDataBackups:         services.UserDataBackups,
 	}
 )
 
-// ExportDataToEzbookkeepingCSVHandler returns exported data in csv format
-func (a *DataManagementsApi) ExportDataToEzbookkeepingCSVHandler(c *core.WebContext) ([]byte, string, *errs.Error) {
-	return a.getExportedFileContent(c, "csv")
+// ExportDataToEzbookkeepingCSVHandler writes exported data in csv format to response page by page
+func (a *DataManagementsApi) ExportDataToEzbookkeepingCSVHandler(c *core.WebContext) (core.DataStreamWriterFunc, string, *errs.Error) {
+	return a.getExportedFileStream(c, "csv", "csv")
+}
+
+// ExportDataToEzbookkeepingTSVHandler writes exported data in tsv format to response page by page
+func (a *DataManagementsApi) ExportDataToEzbookkeepingTSVHandler(c *core.WebContext) (core.DataStreamWriterFunc, string, *errs.Error) {
+	return a.getExportedFileStream(c, "tsv", "tsv")
+}
+
+// ExportDataToOFXHandler returns exported data in open financial exchange (ofx) 2.x format
+func (a *DataManagementsApi) ExportDataToOFXHandler(c *core.WebContext) ([]byte, string, *errs.Error) {
+	return a.getExportedFileContent(c, "ofx", "ofx")
+}
+
+// ExportDataToQFXHandler returns exported data in open financial exchange (ofx) 1.x sgml format which is compatible with quicken (qfx)
+func (a *DataManagementsApi) ExportDataToQFXHandler(c *core.WebContext) ([]byte, string, *errs.Error) {
+	return a.getExportedFileContent(c, "qfx", "qfx")
+}
+
+// ExportDataToQIFHandler returns exported data in quicken interchange format (qif)
+func (a *DataManagementsApi) ExportDataToQIFHandler(c *core.WebContext) ([]byte, string, *errs.Error) {
+	dateFormat := c.Query("date_format")
+
+	if dateFormat == "" {
+		dateFormat = "ymd"
+	}
+
+	if dateFormat != "ymd" && dateFormat != "mdy" && dateFormat != "dmy" {
+		log.Warnf(c, "[data_managements.ExportDataToQIFHandler] date format \"%s\" is invalid", dateFormat)
+		return nil, "", errs.ErrParameterInvalid
+	}
+
+	return a.getExportedFileContent(c, "qif_"+dateFormat, "qif")
 }
 
-// ExportDataToEzbookkeepingTSVHandler returns exported data in csv format
-func (a *DataManagementsApi) ExportDataToEzbookkeepingTSVHandler(c *core.WebContext) ([]byte, string, *errs.Error) {
-	return a.getExportedFileContent(c, "tsv")
+// ExportDataToBeancountHandler returns exported data in beancount format
+func (a *DataManagementsApi) ExportDataToBeancountHandler(c *core.WebContext) ([]byte, string, *errs.Error) {
+	return a.getExportedFileContent(c, "beancount", "beancount")
+}
+
+// ExportDataToLedgerHandler returns exported data in ledger journal format
+func (a *DataManagementsApi) ExportDataToLedgerHandler(c *core.WebContext) ([]byte, string, *errs.Error) {
+	return a.getExportedFileContent(c, "ledger", "ledger")
+}
+
+// ExportDataToGnuCashHandler returns exported data in gnucash gzipped xml format
+func (a *DataManagementsApi) ExportDataToGnuCashHandler(c *core.WebContext) ([]byte, string, *errs.Error) {
+	return a.getExportedFileContent(c, "gnucash", "gnucash")
+}
+
+// ExportDataToXlsxHandler returns exported data in excel (Office Open XML) workbook format
+func (a *DataManagementsApi) ExportDataToXlsxHandler(c *core.WebContext) ([]byte, string, *errs.Error) {
+	return a.getExportedFileContent(c, "xlsx", "xlsx")
+}
+
+// ExportDataToBackupHandler returns the full backup archive of all user data
+func (a *DataManagementsApi) ExportDataToBackupHandler(c *core.WebContext) ([]byte, string, *errs.Error) {
+	if !a.CurrentConfig().EnableDataExport {
+		return nil, "", errs.ErrDataExportNotAllowed
+	}
+
+	clientTimezone, err := c.GetClientTimezone()
+
+	if err != nil {
+		log.Warnf(c, "[data_managements.ExportDataToBackupHandler] cannot get client timezone, because %s", err.Error())
+		clientTimezone = time.Local
+	}
+
+	uid := c.GetCurrentUid()
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		if !errs.IsCustomError(err) {
+			log.Warnf(c, "[data_managements.ExportDataToBackupHandler] failed to get user for user \"uid:%d\", because %s", uid, err.Error())
+		}
+
+		return nil, "", errs.ErrUserNotFound
+	}
+
+	if user.FeatureRestriction.Contains(core.USER_FEATURE_RESTRICTION_TYPE_EXPORT_TRANSACTION) {
+		return nil, "", errs.ErrNotPermittedToPerformThisAction
+	}
+
+	userDataBackup, err := a.userDataBackups.GetUserDataBackup(c, user)
+
+	if err != nil {
+		log.Errorf(c, "[data_managements.ExportDataToBackupHandler] failed to get backup data for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, "", errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	result, err := backup.WriteUserDataBackupArchive(userDataBackup)
+
+	if err != nil {
+		log.Errorf(c, "[data_managements.ExportDataToBackupHandler] failed to write backup archive for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, "", errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	fileName := a.getFileName(user, clientTimezone, "zip")
+
+	return result, fileName, nil
 }
 
 // DataStatisticsHandler returns user data statistics
@@ -99,6 +239,13 @@ func (a *DataManagementsApi) DataStatisticsHandler(c *core.WebContext) (any, *er
 		return nil, errs.ErrOperationFailed
 	}
 
+	totalTransactionAttachmentCount, err := a.attachments.GetTotalTransactionAttachmentsCountByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[data_managements.DataStatisticsHandler] failed to get total transaction attachment count for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
 	totalTransactionTemplateCount, err := a.templates.GetTotalNormalTemplateCountByUid(c, uid)
 
 	if err != nil {
@@ -113,14 +260,36 @@ func (a *DataManagementsApi) DataStatisticsHandler(c *core.WebContext) (any, *er
 		return nil, errs.ErrOperationFailed
 	}
 
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		if !errs.IsCustomError(err) {
+			log.Errorf(c, "[data_managements.DataStatisticsHandler] failed to get user for user \"uid:%d\", because %s", uid, err.Error())
+		}
+
+		return nil, errs.ErrUserNotFound
+	}
+
+	quotaUsage, err := a.userQuotas.GetUserQuotaUsage(c, user)
+
+	if err != nil {
+		log.Errorf(c, "[data_managements.DataStatisticsHandler] failed to get quota usage for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
 	dataStatisticsResp := &models.DataStatisticsResponse{
-		TotalAccountCount:              totalAccountCount,
-		TotalTransactionCategoryCount:  totalTransactionCategoryCount,
-		TotalTransactionTagCount:       totalTransactionTagCount,
-		TotalTransactionCount:          totalTransactionCount,
-		TotalTransactionPictureCount:   totalTransactionPictureCount,
-		TotalTransactionTemplateCount:  totalTransactionTemplateCount,
-		TotalScheduledTransactionCount: totalScheduledTransactionCount,
+		TotalAccountCount:               totalAccountCount,
+		TotalTransactionCategoryCount:   totalTransactionCategoryCount,
+		TotalTransactionTagCount:        totalTransactionTagCount,
+		TotalTransactionCount:           totalTransactionCount,
+		TotalTransactionPictureCount:    totalTransactionPictureCount,
+		TotalTransactionAttachmentCount: totalTransactionAttachmentCount,
+		TotalTransactionTemplateCount:   totalTransactionTemplateCount,
+		TotalScheduledTransactionCount:  totalScheduledTransactionCount,
+		StorageUsedBytes:                quotaUsage.StorageUsedBytes,
+		StorageQuota:                    quotaUsage.StorageQuota,
+		TransactionQuotaUsedCount:       quotaUsage.TransactionUsedCount,
+		TransactionQuota:                quotaUsage.TransactionQuota,
 	}
 
 	return dataStatisticsResp, nil
@@ -169,6 +338,20 @@ func (a *DataManagementsApi) ClearAllDataHandler(c *core.WebContext) (any, *errs
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
+	err = a.contacts.DeleteAllContacts(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[data_managements.ClearAllDataHandler] failed to delete all contacts, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	err = a.events.DeleteAllEvents(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[data_managements.ClearAllDataHandler] failed to delete all events, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
 	err = a.categories.DeleteAllCategories(c, uid)
 
 	if err != nil {
@@ -183,6 +366,20 @@ func (a *DataManagementsApi) ClearAllDataHandler(c *core.WebContext) (any, *errs
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
+	err = a.tagGroups.DeleteAllTagGroups(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[data_managements.ClearAllDataHandler] failed to delete all transaction tag groups, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	err = a.customFields.DeleteAllCustomFields(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[data_managements.ClearAllDataHandler] failed to delete all transaction custom fields, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
 	err = a.userCustomExchangeRates.DeleteAllCustomExchangeRates(c, uid)
 
 	if err != nil {
@@ -190,6 +387,27 @@ func (a *DataManagementsApi) ClearAllDataHandler(c *core.WebContext) (any, *errs
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
+	err = a.ledgers.DeleteAllLedgers(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[data_managements.ClearAllDataHandler] failed to delete all ledgers, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	err = a.ledgerMembers.DeleteAllMembersByOwnerUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[data_managements.ClearAllDataHandler] failed to delete all ledger members, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	_, err = a.ledgers.GetOrCreateDefaultLedger(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[data_managements.ClearAllDataHandler] failed to create default ledger, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
 	log.Infof(c, "[data_managements.ClearAllDataHandler] user \"uid:%d\" has cleared all data", uid)
 	return true, nil
 }
@@ -289,85 +507,231 @@ func (a *DataManagementsApi) ClearAllTransactionsByAccountHandler(c *core.WebCon
 	return true, nil
 }
 
-func (a *DataManagementsApi) getExportedFileContent(c *core.WebContext, fileType string) ([]byte, string, *errs.Error) {
+// getExportedFileContent returns the whole exported file built in memory, it is only used by the file types which cannot be written page by page,
+// ofx, qfx and qif group the transactions by account, beancount, ledger and gnucash sort all the transactions by time and write the directives
+// of all used accounts before them, and xlsx builds the whole workbook package, so all the specified transactions are loaded before exporting
+func (a *DataManagementsApi) getExportedFileContent(c *core.WebContext, fileType string, fileExtension string) ([]byte, string, *errs.Error) {
+	exportContext, err := a.getTransactionDataExportContext(c)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	uid := exportContext.ledgerOwnerUid
+	dataExporter := converters.GetTransactionDataExporter(fileType)
+
+	if dataExporter == nil {
+		return nil, "", errs.ErrNotImplemented
+	}
+
+	allTransactions, err2 := a.transactions.GetAllSpecifiedTransactions(c, exportContext.ledgerAccess, exportContext.maxTransactionTime, exportContext.minTransactionTime, exportContext.transactionType, exportContext.categoryIds, exportContext.accountIds, exportContext.tagFilters, exportContext.noTags, nil, exportContext.amountFilter, exportContext.keyword, pageCountForDataExport, true)
+
+	if err2 != nil {
+		log.Errorf(c, "[data_managements.getExportedFileContent] failed to all transactions user \"uid:%d\", because %s", uid, err2.Error())
+		return nil, "", errs.ErrOperationFailed
+	}
+
+	tagIndexes, customFieldValues, err2 := a.getTagIndexesAndCustomFieldValuesOfTransactions(c, uid, allTransactions, len(exportContext.customFields) > 0)
+
+	if err2 != nil {
+		log.Errorf(c, "[data_managements.getExportedFileContent] failed to get tag index or custom field values for user \"uid:%d\", because %s", uid, err2.Error())
+		return nil, "", errs.ErrOperationFailed
+	}
+
+	result, err2 := dataExporter.ToExportedContent(c, uid, allTransactions, exportContext.accountMap, exportContext.categoryMap, exportContext.tagMap, tagIndexes, exportContext.customFields, customFieldValues, exportContext.exporterOptions)
+
+	if err2 != nil {
+		log.Errorf(c, "[data_managements.getExportedFileContent] failed to get exported data for \"uid:%d\", because %s", uid, err2.Error())
+		return nil, "", errs.Or(err2, errs.ErrOperationFailed)
+	}
+
+	fileName := a.getFileName(exportContext.user, exportContext.clientTimezone, fileExtension)
+
+	return result, fileName, nil
+}
+
+// getTagIndexesAndCustomFieldValuesOfTransactions returns the tag indexes and custom field values of the given transactions only, which are queried page by page
+func (a *DataManagementsApi) getTagIndexesAndCustomFieldValuesOfTransactions(c *core.WebContext, uid int64, transactions []*models.Transaction, includeCustomFieldValues bool) (map[int64][]int64, map[int64][]*models.TransactionCustomFieldValue, error) {
+	tagIndexes := make(map[int64][]int64)
+	customFieldValues := make(map[int64][]*models.TransactionCustomFieldValue)
+
+	for i := 0; i < len(transactions); i += pageCountForDataExport {
+		transactionIds := make([]int64, 0, pageCountForDataExport)
+
+		for j := i; j < len(transactions) && j < i+pageCountForDataExport; j++ {
+			transactionIds = append(transactionIds, transactions[j].TransactionId)
+		}
+
+		pageTagIndexes, err := a.tags.GetAllTagIdsOfTransactions(c, uid, transactionIds)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for transactionId, tagIds := range pageTagIndexes {
+			tagIndexes[transactionId] = tagIds
+		}
+
+		if !includeCustomFieldValues {
+			continue
+		}
+
+		pageCustomFieldValues, err := a.customFields.GetCustomFieldValuesByTransactionIds(c, uid, transactionIds)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for transactionId, values := range pageCustomFieldValues {
+			customFieldValues[transactionId] = values
+		}
+	}
+
+	return tagIndexes, customFieldValues, nil
+}
+
+func (a *DataManagementsApi) getExportedFileStream(c *core.WebContext, fileType string, fileExtension string) (core.DataStreamWriterFunc, string, *errs.Error) {
+	exportContext, err := a.getTransactionDataExportContext(c)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	uid := exportContext.ledgerOwnerUid
+	dataExporter := converters.GetTransactionDataStreamExporter(fileType)
+
+	if dataExporter == nil {
+		return nil, "", errs.ErrNotImplemented
+	}
+
+	pageReader, err2 := a.transactions.GetExportedTransactionDataPageReader(c, exportContext.ledgerAccess, exportContext.maxTransactionTime, exportContext.minTransactionTime, exportContext.transactionType, exportContext.categoryIds, exportContext.accountIds, exportContext.tagFilters, exportContext.noTags, exportContext.amountFilter, exportContext.keyword, pageCountForDataExport, len(exportContext.customFields) > 0)
+
+	if err2 != nil {
+		log.Errorf(c, "[data_managements.getExportedFileStream] failed to get transaction page reader for user \"uid:%d\", because %s", uid, err2.Error())
+		return nil, "", errs.Or(err2, errs.ErrOperationFailed)
+	}
+
+	fileName := a.getFileName(exportContext.user, exportContext.clientTimezone, fileExtension)
+
+	writer := func(writer io.Writer) error {
+		err := dataExporter.WriteExportedContent(c, writer, uid, pageReader, exportContext.accountMap, exportContext.categoryMap, exportContext.tagMap, exportContext.customFields, exportContext.exporterOptions)
+
+		if err != nil {
+			log.Errorf(c, "[data_managements.getExportedFileStream] failed to write exported data for \"uid:%d\", because %s", uid, err.Error())
+		}
+
+		return err
+	}
+
+	return writer, fileName, nil
+}
+
+func (a *DataManagementsApi) getTransactionDataExportContext(c *core.WebContext) (*transactionDataExportContext, *errs.Error) {
 	if !a.CurrentConfig().EnableDataExport {
-		return nil, "", errs.ErrDataExportNotAllowed
+		return nil, errs.ErrDataExportNotAllowed
 	}
 
 	var exportTransactionDataReq models.ExportTransactionDataRequest
 	err := c.ShouldBindQuery(&exportTransactionDataReq)
 
 	if err != nil {
-		log.Warnf(c, "[data_managements.getExportedFileContent] parse request failed, because %s", err.Error())
-		return nil, "", errs.NewIncompleteOrIncorrectSubmissionError(err)
+		log.Warnf(c, "[data_managements.getTransactionDataExportContext] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	exporterOptions, err := converter.ParseExporterOptions(exportTransactionDataReq.Timezone, exportTransactionDataReq.Columns)
+
+	if err != nil {
+		log.Warnf(c, "[data_managements.getTransactionDataExportContext] parse exporter options failed, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
 	clientTimezone, err := c.GetClientTimezone()
 
 	if err != nil {
-		log.Warnf(c, "[data_managements.getExportedFileContent] cannot get client timezone, because %s", err.Error())
+		log.Warnf(c, "[data_managements.getTransactionDataExportContext] cannot get client timezone, because %s", err.Error())
 		clientTimezone = time.Local
 	}
 
-	uid := c.GetCurrentUid()
-	user, err := a.users.GetUserById(c, uid)
+	currentUid := c.GetCurrentUid()
+	user, err := a.users.GetUserById(c, currentUid)
 
 	if err != nil {
 		if !errs.IsCustomError(err) {
-			log.Warnf(c, "[data_managements.getExportedFileContent] failed to get user for user \"uid:%d\", because %s", uid, err.Error())
+			log.Warnf(c, "[data_managements.getTransactionDataExportContext] failed to get user for user \"uid:%d\", because %s", currentUid, err.Error())
 		}
 
-		return nil, "", errs.ErrUserNotFound
+		return nil, errs.ErrUserNotFound
 	}
 
 	if user.FeatureRestriction.Contains(core.USER_FEATURE_RESTRICTION_TYPE_EXPORT_TRANSACTION) {
-		return nil, "", errs.ErrNotPermittedToPerformThisAction
+		return nil, errs.ErrNotPermittedToPerformThisAction
 	}
 
-	accounts, err := a.accounts.GetAllAccountsByUid(c, uid)
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_VIEW_TRANSACTIONS)
 
-	if err != nil {
-		log.Errorf(c, "[data_managements.getExportedFileContent] failed to get all accounts for user \"uid:%d\", because %s", uid, err.Error())
-		return nil, "", errs.ErrOperationFailed
+	if errResult != nil {
+		return nil, errResult
 	}
 
-	categories, err := a.categories.GetAllCategoriesByUid(c, uid, 0, -1)
+	accounts, err := a.accounts.GetAllAccountsByUid(c, a.GetCurrentLedgerAccess(c))
 
 	if err != nil {
-		log.Errorf(c, "[data_managements.getExportedFileContent] failed to get categories for user \"uid:%d\", because %s", uid, err.Error())
-		return nil, "", errs.ErrOperationFailed
+		log.Errorf(c, "[data_managements.getTransactionDataExportContext] failed to get all accounts for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrOperationFailed
 	}
 
-	tags, err := a.tags.GetAllTagsByUid(c, uid)
+	categories, err := a.categories.GetAllCategoriesByUid(c, a.GetCurrentLedgerAccess(c), 0, -1)
 
 	if err != nil {
-		log.Errorf(c, "[data_managements.getExportedFileContent] failed to get tags for user \"uid:%d\", because %s", uid, err.Error())
-		return nil, "", errs.ErrOperationFailed
+		log.Errorf(c, "[data_managements.getTransactionDataExportContext] failed to get categories for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrOperationFailed
 	}
 
-	tagIndexes, err := a.tags.GetAllTagIdsMapOfAllTransactions(c, uid)
+	tags, err := a.tags.GetAllTagsByUid(c, a.GetCurrentLedgerAccess(c))
 
 	if err != nil {
-		log.Errorf(c, "[data_managements.getExportedFileContent] failed to get tag index for user \"uid:%d\", because %s", uid, err.Error())
-		return nil, "", errs.ErrOperationFailed
+		log.Errorf(c, "[data_managements.getTransactionDataExportContext] failed to get tags for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrOperationFailed
 	}
 
-	accountMap := a.accounts.GetAccountMapByList(accounts)
-	categoryMap := a.categories.GetCategoryMapByList(categories)
-	tagMap := a.tags.GetTagMapByList(tags)
+	customFields, err := a.customFields.GetAllCustomFieldsByUid(c, a.GetCurrentLedgerAccess(c))
+
+	if err != nil {
+		log.Errorf(c, "[data_managements.getTransactionDataExportContext] failed to get custom fields for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrOperationFailed
+	}
 
 	allAccountIds, err := a.accounts.GetAccountOrSubAccountIds(c, exportTransactionDataReq.AccountIds, uid)
 
 	if err != nil {
-		log.Warnf(c, "[data_managements.getExportedFileContent] get account error, because %s", err.Error())
-		return nil, "", errs.Or(err, errs.ErrOperationFailed)
+		log.Warnf(c, "[data_managements.getTransactionDataExportContext] get account error, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
 	allCategoryIds, err := a.categories.GetCategoryOrSubCategoryIds(c, exportTransactionDataReq.CategoryIds, uid)
 
 	if err != nil {
-		log.Warnf(c, "[data_managements.getExportedFileContent] get transaction category error, because %s", err.Error())
-		return nil, "", errs.Or(err, errs.ErrOperationFailed)
+		log.Warnf(c, "[data_managements.getTransactionDataExportContext] get transaction category error, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	accountMap := a.accounts.GetAccountMapByList(accounts)
+
+	for i := 0; i < len(allAccountIds); i++ {
+		if _, exists := accountMap[allAccountIds[i]]; !exists {
+			log.Warnf(c, "[data_managements.getTransactionDataExportContext] account \"id:%d\" does not exist in current ledger of user \"uid:%d\"", allAccountIds[i], uid)
+			return nil, errs.ErrAccountNotFound
+		}
+	}
+
+	categoryMap := a.categories.GetCategoryMapByList(categories)
+
+	for i := 0; i < len(allCategoryIds); i++ {
+		if _, exists := categoryMap[allCategoryIds[i]]; !exists {
+			log.Warnf(c, "[data_managements.getTransactionDataExportContext] transaction category \"id:%d\" does not exist in current ledger of user \"uid:%d\"", allCategoryIds[i], uid)
+			return nil, errs.ErrTransactionCategoryNotFound
+		}
 	}
 
 	noTags := exportTransactionDataReq.TagFilter == models.TransactionNoTagFilterValue
@@ -377,45 +741,79 @@ func (a *DataManagementsApi) getExportedFileContent(c *core.WebContext, fileType
 		tagFilters, err = models.ParseTransactionTagFilter(exportTransactionDataReq.TagFilter)
 
 		if err != nil {
-			log.Warnf(c, "[data_managements.getExportedFileContent] parse transaction tag filters error, because %s", err.Error())
-			return nil, "", errs.Or(err, errs.ErrOperationFailed)
+			log.Warnf(c, "[data_managements.getTransactionDataExportContext] parse transaction tag filters error, because %s", err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
 		}
 	}
 
-	maxTransactionTime := int64(math.MaxInt64)
-	minTransactionTime := int64(0)
+	tagMap := a.tags.GetTagMapByList(tags)
+	tagGroupIds := make(map[int64]bool)
 
-	if exportTransactionDataReq.MaxTime > 0 {
-		maxTransactionTime = utils.GetMaxTransactionTimeFromUnixTime(exportTransactionDataReq.MaxTime)
-	}
+	for i := 0; i < len(tagFilters); i++ {
+		tagFilter := tagFilters[i]
+		allTagIds := append(append([]int64{}, tagFilter.TagIds...), tagFilter.SubtreeTagIds...)
 
-	if exportTransactionDataReq.MinTime > 0 {
-		minTransactionTime = utils.GetMinTransactionTimeFromUnixTime(exportTransactionDataReq.MinTime)
+		for j := 0; j < len(allTagIds); j++ {
+			if _, exists := tagMap[allTagIds[j]]; !exists {
+				log.Warnf(c, "[data_managements.getTransactionDataExportContext] transaction tag \"id:%d\" does not exist in current ledger of user \"uid:%d\"", allTagIds[j], uid)
+				return nil, errs.ErrTransactionTagNotFound
+			}
+		}
+
+		for j := 0; j < len(tagFilter.TagGroupIds); j++ {
+			tagGroupIds[tagFilter.TagGroupIds[j]] = true
+		}
 	}
 
-	allTransactions, err := a.transactions.GetAllSpecifiedTransactions(c, uid, maxTransactionTime, minTransactionTime, exportTransactionDataReq.Type, allCategoryIds, allAccountIds, tagFilters, noTags, exportTransactionDataReq.AmountFilter, exportTransactionDataReq.Keyword, pageCountForDataExport, true)
+	if len(tagGroupIds) > 0 {
+		tagGroups, err := a.tagGroups.GetAllTagGroupsByUid(c, a.GetCurrentLedgerAccess(c))
 
-	if err != nil {
-		log.Errorf(c, "[data_managements.getExportedFileContent] failed to all transactions user \"uid:%d\", because %s", uid, err.Error())
-		return nil, "", errs.ErrOperationFailed
-	}
+		if err != nil {
+			log.Errorf(c, "[data_managements.getTransactionDataExportContext] failed to get tag groups for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, errs.ErrOperationFailed
+		}
 
-	dataExporter := converters.GetTransactionDataExporter(fileType)
+		for i := 0; i < len(tagGroups); i++ {
+			delete(tagGroupIds, tagGroups[i].TagGroupId)
+		}
 
-	if dataExporter == nil {
-		return nil, "", errs.ErrNotImplemented
+		for tagGroupId := range tagGroupIds {
+			log.Warnf(c, "[data_managements.getTransactionDataExportContext] transaction tag group \"id:%d\" does not exist in current ledger of user \"uid:%d\"", tagGroupId, uid)
+			return nil, errs.ErrTransactionTagGroupNotFound
+		}
 	}
 
-	result, err := dataExporter.ToExportedContent(c, uid, allTransactions, accountMap, categoryMap, tagMap, tagIndexes)
+	maxTransactionTime := int64(math.MaxInt64)
+	minTransactionTime := int64(0)
 
-	if err != nil {
-		log.Errorf(c, "[data_managements.getExportedFileContent] failed to get exported data for \"uid:%d\", because %s", uid, err.Error())
-		return nil, "", errs.Or(err, errs.ErrOperationFailed)
+	if exportTransactionDataReq.MaxTime > 0 {
+		maxTransactionTime = utils.GetMaxTransactionTimeFromUnixTime(exportTransactionDataReq.MaxTime)
 	}
 
-	fileName := a.getFileName(user, clientTimezone, fileType)
+	if exportTransactionDataReq.MinTime > 0 {
+		minTransactionTime = utils.GetMinTransactionTimeFromUnixTime(exportTransactionDataReq.MinTime)
+	}
 
-	return result, fileName, nil
+	return &transactionDataExportContext{
+		user:               user,
+		clientTimezone:     clientTimezone,
+		exporterOptions:    exporterOptions,
+		accountMap:         accountMap,
+		categoryMap:        categoryMap,
+		tagMap:             tagMap,
+		customFields:       customFields,
+		ledgerOwnerUid:     uid,
+		ledgerAccess:       a.GetCurrentLedgerAccess(c),
+		maxTransactionTime: maxTransactionTime,
+		minTransactionTime: minTransactionTime,
+		transactionType:    exportTransactionDataReq.Type,
+		categoryIds:        allCategoryIds,
+		accountIds:         allAccountIds,
+		tagFilters:         tagFilters,
+		noTags:             noTags,
+		amountFilter:       exportTransactionDataReq.AmountFilter,
+		keyword:            exportTransactionDataReq.Keyword,
+	}, nil
 }
 
 func (a *DataManagementsApi) getFileName(user *models.User, clientTimezone *time.Location, fileExtension string) string {
@@ -0,0 +1,517 @@
+package api
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/exchangerates"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const maxEventParticipantCount = 20
+
+// EventsApi represents event api
+type EventsApi struct {
+	ApiUsingConfig
+	ApiUsingLedgerAccess
+	users        *services.UserService
+	events       *services.EventService
+	contacts     *services.ContactService
+	accounts     *services.AccountService
+	transactions *services.TransactionService
+}
+
+// Initialize an event api singleton instance
+var (
+	Events = &EventsApi{
+		ApiUsingConfig: ApiUsingConfig{
+			container: settings.Container,
+		},
+		ApiUsingLedgerAccess: ApiUsingLedgerAccess{
+			ledgerAccesses: services.LedgerAccesses,
+		},
+		users:        services.Users,
+		events:       services.Events,
+		contacts:     services.Contacts,
+		accounts:     services.Accounts,
+		transactions: services.Transactions,
+	}
+)
+
+// EventListHandler returns event list of current user
+func (a *EventsApi) EventListHandler(c *core.WebContext) (any, *errs.Error) {
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_VIEW_BASIC_DATA)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	events, err := a.events.GetAllEventsByUid(c, a.GetCurrentLedgerAccess(c))
+
+	if err != nil {
+		log.Errorf(c, "[events.EventListHandler] failed to get events for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	eventResps := make(models.EventInfoResponseSlice, len(events))
+
+	for i := 0; i < len(events); i++ {
+		eventResps[i] = events[i].ToEventInfoResponse()
+	}
+
+	sort.Sort(eventResps)
+
+	return eventResps, nil
+}
+
+// EventGetHandler returns one specific event of current user
+func (a *EventsApi) EventGetHandler(c *core.WebContext) (any, *errs.Error) {
+	var eventGetReq models.EventGetRequest
+	err := c.ShouldBindQuery(&eventGetReq)
+
+	if err != nil {
+		log.Warnf(c, "[events.EventGetHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_VIEW_BASIC_DATA)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	event, errResult := a.getEventInCurrentLedger(c, uid, eventGetReq.Id)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	return event.ToEventInfoResponse(), nil
+}
+
+// EventCreateHandler saves a new event by request parameters for current user
+func (a *EventsApi) EventCreateHandler(c *core.WebContext) (any, *errs.Error) {
+	var eventCreateReq models.EventCreateRequest
+	err := c.ShouldBindJSON(&eventCreateReq)
+
+	if err != nil {
+		log.Warnf(c, "[events.EventCreateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_MANAGE_BASIC_DATA)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	participantContactIds, errResult := a.checkEventRequest(c, uid, eventCreateReq.StartTime, eventCreateReq.EndTime, eventCreateReq.BudgetCurrency, eventCreateReq.BudgetAmount, eventCreateReq.ParticipantContactIds)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	maxOrderId, err := a.events.GetMaxDisplayOrder(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[events.EventCreateHandler] failed to get max display order for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	event := &models.Event{
+		Uid:                   uid,
+		LedgerId:              c.GetCurrentLedgerId(),
+		Name:                  eventCreateReq.Name,
+		StartTime:             eventCreateReq.StartTime,
+		EndTime:               eventCreateReq.EndTime,
+		BudgetCurrency:        eventCreateReq.BudgetCurrency,
+		BudgetAmount:          eventCreateReq.BudgetAmount,
+		ParticipantContactIds: participantContactIds,
+		AutoAssign:            eventCreateReq.AutoAssign,
+		Comment:               eventCreateReq.Comment,
+		DisplayOrder:          maxOrderId + 1,
+	}
+
+	if eventCreateReq.GeoLocation != nil {
+		event.GeoLatitude = eventCreateReq.GeoLocation.Latitude
+		event.GeoLongitude = eventCreateReq.GeoLocation.Longitude
+		event.GeoRadius = eventCreateReq.GeoLocation.Radius
+	}
+
+	err = a.events.CreateEvent(c, event)
+
+	if err != nil {
+		log.Errorf(c, "[events.EventCreateHandler] failed to create event \"id:%d\" for user \"uid:%d\", because %s", event.EventId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[events.EventCreateHandler] user \"uid:%d\" has created a new event \"id:%d\" successfully", uid, event.EventId)
+
+	return event.ToEventInfoResponse(), nil
+}
+
+// EventModifyHandler saves an existed event by request parameters for current user
+func (a *EventsApi) EventModifyHandler(c *core.WebContext) (any, *errs.Error) {
+	var eventModifyReq models.EventModifyRequest
+	err := c.ShouldBindJSON(&eventModifyReq)
+
+	if err != nil {
+		log.Warnf(c, "[events.EventModifyHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_MANAGE_BASIC_DATA)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	event, errResult := a.getEventInCurrentLedger(c, uid, eventModifyReq.Id)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	participantContactIds, errResult := a.checkEventRequest(c, uid, eventModifyReq.StartTime, eventModifyReq.EndTime, eventModifyReq.BudgetCurrency, eventModifyReq.BudgetAmount, eventModifyReq.ParticipantContactIds)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	newEvent := &models.Event{
+		EventId:               event.EventId,
+		Uid:                   uid,
+		LedgerId:              event.LedgerId,
+		Name:                  eventModifyReq.Name,
+		StartTime:             eventModifyReq.StartTime,
+		EndTime:               eventModifyReq.EndTime,
+		BudgetCurrency:        eventModifyReq.BudgetCurrency,
+		BudgetAmount:          eventModifyReq.BudgetAmount,
+		ParticipantContactIds: participantContactIds,
+		AutoAssign:            eventModifyReq.AutoAssign,
+		Comment:               eventModifyReq.Comment,
+		DisplayOrder:          event.DisplayOrder,
+		Hidden:                event.Hidden,
+	}
+
+	if eventModifyReq.GeoLocation != nil {
+		newEvent.GeoLatitude = eventModifyReq.GeoLocation.Latitude
+		newEvent.GeoLongitude = eventModifyReq.GeoLocation.Longitude
+		newEvent.GeoRadius = eventModifyReq.GeoLocation.Radius
+	}
+
+	if newEvent.Name == event.Name &&
+		newEvent.StartTime == event.StartTime &&
+		newEvent.EndTime == event.EndTime &&
+		newEvent.BudgetCurrency == event.BudgetCurrency &&
+		newEvent.BudgetAmount == event.BudgetAmount &&
+		newEvent.ParticipantContactIds == event.ParticipantContactIds &&
+		newEvent.AutoAssign == event.AutoAssign &&
+		newEvent.GeoLatitude == event.GeoLatitude &&
+		newEvent.GeoLongitude == event.GeoLongitude &&
+		newEvent.GeoRadius == event.GeoRadius &&
+		newEvent.Comment == event.Comment {
+		return nil, errs.ErrNothingWillBeUpdated
+	}
+
+	err = a.events.ModifyEvent(c, newEvent)
+
+	if err != nil {
+		log.Errorf(c, "[events.EventModifyHandler] failed to update event \"id:%d\" for user \"uid:%d\", because %s", eventModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[events.EventModifyHandler] user \"uid:%d\" has updated event \"id:%d\" successfully", uid, eventModifyReq.Id)
+
+	return newEvent.ToEventInfoResponse(), nil
+}
+
+// EventHideHandler hides an event by request parameters for current user
+func (a *EventsApi) EventHideHandler(c *core.WebContext) (any, *errs.Error) {
+	var eventHideReq models.EventHideRequest
+	err := c.ShouldBindJSON(&eventHideReq)
+
+	if err != nil {
+		log.Warnf(c, "[events.EventHideHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_MANAGE_BASIC_DATA)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	_, errResult = a.getEventInCurrentLedger(c, uid, eventHideReq.Id)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	err = a.events.HideEvent(c, uid, []int64{eventHideReq.Id}, eventHideReq.Hidden)
+
+	if err != nil {
+		log.Errorf(c, "[events.EventHideHandler] failed to hide event \"id:%d\" for user \"uid:%d\", because %s", eventHideReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[events.EventHideHandler] user \"uid:%d\" has hidden event \"id:%d\"", uid, eventHideReq.Id)
+	return true, nil
+}
+
+// EventDeleteHandler deletes an existed event by request parameters for current user
+func (a *EventsApi) EventDeleteHandler(c *core.WebContext) (any, *errs.Error) {
+	var eventDeleteReq models.EventDeleteRequest
+	err := c.ShouldBindJSON(&eventDeleteReq)
+
+	if err != nil {
+		log.Warnf(c, "[events.EventDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_MANAGE_BASIC_DATA)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	_, errResult = a.getEventInCurrentLedger(c, uid, eventDeleteReq.Id)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	err = a.events.DeleteEvent(c, uid, eventDeleteReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[events.EventDeleteHandler] failed to delete event \"id:%d\" for user \"uid:%d\", because %s", eventDeleteReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[events.EventDeleteHandler] user \"uid:%d\" has deleted event \"id:%d\"", uid, eventDeleteReq.Id)
+	return true, nil
+}
+
+// EventAssignTransactionsHandler assigns transactions to an event, or removes transactions from their events, by request parameters for current user
+func (a *EventsApi) EventAssignTransactionsHandler(c *core.WebContext) (any, *errs.Error) {
+	var assignReq models.EventAssignTransactionsRequest
+	err := c.ShouldBindJSON(&assignReq)
+
+	if err != nil {
+		log.Warnf(c, "[events.EventAssignTransactionsHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_MODIFY_ALL_TRANSACTIONS)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	transactionIds, err := utils.StringArrayToInt64Array(assignReq.TransactionIds)
+
+	if err != nil {
+		log.Warnf(c, "[events.EventAssignTransactionsHandler] parse transaction ids failed, because %s", err.Error())
+		return nil, errs.ErrTransactionIdInvalid
+	}
+
+	if assignReq.Id > 0 {
+		_, errResult = a.getEventInCurrentLedger(c, uid, assignReq.Id)
+
+		if errResult != nil {
+			return nil, errResult
+		}
+	}
+
+	updatedCount, err := a.events.AssignTransactionsToEvent(c, a.GetCurrentLedgerAccess(c), assignReq.Id, transactionIds)
+
+	if err != nil {
+		log.Errorf(c, "[events.EventAssignTransactionsHandler] failed to assign transactions to event \"id:%d\" for user \"uid:%d\", because %s", assignReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[events.EventAssignTransactionsHandler] user \"uid:%d\" has assigned %d transactions to event \"id:%d\"", uid, updatedCount, assignReq.Id)
+	return updatedCount, nil
+}
+
+// EventAutoAssignTransactionsHandler assigns existed transactions matching the date range and geographic area of an event to the event for current user
+func (a *EventsApi) EventAutoAssignTransactionsHandler(c *core.WebContext) (any, *errs.Error) {
+	var autoAssignReq models.EventAutoAssignTransactionsRequest
+	err := c.ShouldBindJSON(&autoAssignReq)
+
+	if err != nil {
+		log.Warnf(c, "[events.EventAutoAssignTransactionsHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_MODIFY_ALL_TRANSACTIONS)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	event, errResult := a.getEventInCurrentLedger(c, uid, autoAssignReq.Id)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	if event.Hidden {
+		return nil, errs.ErrCannotUseHiddenEvent
+	}
+
+	updatedCount, err := a.events.AutoAssignTransactionsToEvent(c, uid, event)
+
+	if err != nil {
+		log.Errorf(c, "[events.EventAutoAssignTransactionsHandler] failed to auto assign transactions to event \"id:%d\" for user \"uid:%d\", because %s", autoAssignReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[events.EventAutoAssignTransactionsHandler] user \"uid:%d\" has auto assigned %d transactions to event \"id:%d\"", uid, updatedCount, autoAssignReq.Id)
+	return updatedCount, nil
+}
+
+// EventStatisticHandler returns the spending statistic by category, currency and participant of an event for current user
+func (a *EventsApi) EventStatisticHandler(c *core.WebContext) (any, *errs.Error) {
+	var statisticReq models.EventStatisticRequest
+	err := c.ShouldBindQuery(&statisticReq)
+
+	if err != nil {
+		log.Warnf(c, "[events.EventStatisticHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_VIEW_TRANSACTIONS)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	event, errResult := a.getEventInCurrentLedger(c, uid, statisticReq.Id)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	transactions, err := a.transactions.GetAllTransactionsByEventId(c, uid, event.EventId)
+
+	if err != nil {
+		log.Errorf(c, "[events.EventStatisticHandler] failed to get transactions of event \"id:%d\" for user \"uid:%d\", because %s", event.EventId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	accountIds := make([]int64, 0)
+	transactionIds := make([]int64, len(transactions))
+	accountIdExists := make(map[int64]bool)
+
+	for i := 0; i < len(transactions); i++ {
+		transactionIds[i] = transactions[i].TransactionId
+
+		if !accountIdExists[transactions[i].AccountId] {
+			accountIds = append(accountIds, transactions[i].AccountId)
+			accountIdExists[transactions[i].AccountId] = true
+		}
+	}
+
+	accountMap := make(map[int64]*models.Account)
+
+	if len(accountIds) > 0 {
+		accountMap, err = a.accounts.GetAccountsByAccountIds(c, uid, accountIds)
+
+		if err != nil {
+			log.Errorf(c, "[events.EventStatisticHandler] failed to get accounts for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+	}
+
+	contactTransactions, err := a.contacts.GetContactTransactionsByTransactionIds(c, uid, transactionIds)
+
+	if err != nil {
+		log.Errorf(c, "[events.EventStatisticHandler] failed to get contact transactions of event \"id:%d\" for user \"uid:%d\", because %s", event.EventId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	currentUid := c.GetCurrentUid()
+	user, err := a.users.GetUserById(c, currentUid)
+
+	if err != nil {
+		log.Errorf(c, "[events.EventStatisticHandler] failed to get user \"uid:%d\", because %s", currentUid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	exchangeRates, err := exchangerates.Container.GetLatestExchangeRates(c, currentUid, a.CurrentConfig())
+
+	if err != nil {
+		log.Warnf(c, "[events.EventStatisticHandler] failed to get latest exchange rates for user \"uid:%d\", because %s", currentUid, err.Error())
+		exchangeRates = nil
+	}
+
+	return a.events.GetEventStatistic(event, transactions, accountMap, contactTransactions, user.DefaultCurrency, exchangeRates), nil
+}
+
+func (a *EventsApi) getEventInCurrentLedger(c *core.WebContext, uid int64, eventId int64) (*models.Event, *errs.Error) {
+	event, err := a.events.GetEventByEventId(c, uid, eventId)
+
+	if err != nil {
+		log.Errorf(c, "[events.getEventInCurrentLedger] failed to get event \"id:%d\" for user \"uid:%d\", because %s", eventId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	errResult := a.CheckDataInCurrentLedger(c, event.LedgerId)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	return event, nil
+}
+
+// checkEventRequest checks the date range, budget and participants of event request, and returns the participant contact ids joined by comma
+func (a *EventsApi) checkEventRequest(c *core.WebContext, uid int64, startTime int64, endTime int64, budgetCurrency string, budgetAmount int64, participantContactIds []string) (string, *errs.Error) {
+	if endTime < startTime {
+		return "", errs.ErrEventTimeRangeInvalid
+	}
+
+	if budgetAmount > 0 && budgetCurrency == "" {
+		return "", errs.ErrEventBudgetCurrencyIsEmpty
+	}
+
+	if len(participantContactIds) > maxEventParticipantCount {
+		return "", errs.ErrEventParticipantsTooMany
+	}
+
+	if len(participantContactIds) < 1 {
+		return "", nil
+	}
+
+	contactIds, err := utils.StringArrayToInt64Array(participantContactIds)
+
+	if err != nil {
+		log.Warnf(c, "[events.checkEventRequest] parse participant contact ids failed, because %s", err.Error())
+		return "", errs.ErrEventParticipantInvalid
+	}
+
+	contactIds = utils.ToUniqueInt64Slice(contactIds)
+	contactMap, err := a.contacts.GetContactsByContactIds(c, uid, contactIds)
+
+	if err != nil {
+		log.Errorf(c, "[events.checkEventRequest] failed to get participant contacts for user \"uid:%d\", because %s", uid, err.Error())
+		return "", errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	for i := 0; i < len(contactIds); i++ {
+		contact, exists := contactMap[contactIds[i]]
+
+		if !exists || contact.LedgerId != c.GetCurrentLedgerId() {
+			return "", errs.ErrEventParticipantInvalid
+		}
+	}
+
+	return strings.Join(utils.Int64ArrayToStringArray(contactIds), ","), nil
+}
@@ -22,6 +22,7 @@ import (
 // LargeLanguageModelsApi represents large language models api
 type LargeLanguageModelsApi struct {
 	ApiUsingConfig
+	ApiUsingLedgerAccess
 	transactionCategories *services.TransactionCategoryService
 	transactionTags       *services.TransactionTagService
 	accounts              *services.AccountService
@@ -34,6 +35,9 @@ var (
 		ApiUsingConfig: ApiUsingConfig{
 			container: settings.Container,
 		},
+		ApiUsingLedgerAccess: ApiUsingLedgerAccess{
+			ledgerAccesses: services.LedgerAccesses,
+		},
 		transactionCategories: services.TransactionCategories,
 		transactionTags:       services.TransactionTags,
 		accounts:              services.Accounts,
@@ -117,7 +121,7 @@ func (a *LargeLanguageModelsApi) RecognizeReceiptImageHandler(c *core.WebContext
 		return nil, errs.ErrOperationFailed
 	}
 
-	accounts, err := a.accounts.GetAllAccountsByUid(c, uid)
+	accounts, err := a.accounts.GetAllAccountsByUid(c, a.GetCurrentLedgerAccess(c))
 
 	if err != nil {
 		log.Errorf(c, "[large_language_models.RecognizeReceiptImageHandler] failed to get all accounts for user \"uid:%d\", because %s", uid, err.Error())
@@ -135,7 +139,7 @@ func (a *LargeLanguageModelsApi) RecognizeReceiptImageHandler(c *core.WebContext
 		accountNames = append(accountNames, accounts[i].Name)
 	}
 
-	categories, err := a.transactionCategories.GetAllCategoriesByUid(c, uid, 0, -1)
+	categories, err := a.transactionCategories.GetAllCategoriesByUid(c, a.GetCurrentLedgerAccess(c), 0, -1)
 
 	if err != nil {
 		log.Errorf(c, "[large_language_models.RecognizeReceiptImageHandler] failed to get categories for user \"uid:%d\", because %s", uid, err.Error())
@@ -170,7 +174,7 @@ func (a *LargeLanguageModelsApi) RecognizeReceiptImageHandler(c *core.WebContext
 		}
 	}
 
-	tags, err := a.transactionTags.GetAllTagsByUid(c, uid)
+	tags, err := a.transactionTags.GetAllTagsByUid(c, a.GetCurrentLedgerAccess(c))
 
 	if err != nil {
 		log.Errorf(c, "[large_language_models.RecognizeReceiptImageHandler] failed to get tags for user \"uid:%d\", because %s", uid, err.Error())
@@ -0,0 +1,464 @@
+package api
+
+import (
+	"sort"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// LedgerMembersApi represents ledger member api
+type LedgerMembersApi struct {
+	ApiUsingConfig
+	ledgers        *services.LedgerService
+	ledgerAccesses *services.LedgerAccessService
+	ledgerMembers  *services.LedgerMemberService
+	transactions   *services.TransactionService
+	accounts       *services.AccountService
+	users          *services.UserService
+}
+
+// Initialize a ledger member api singleton instance
+var (
+	LedgerMembers = &LedgerMembersApi{
+		ApiUsingConfig: ApiUsingConfig{
+			container: settings.Container,
+		},
+		ledgers:        services.Ledgers,
+		ledgerAccesses: services.LedgerAccesses,
+		ledgerMembers:  services.LedgerMembers,
+		transactions:   services.Transactions,
+		accounts:       services.Accounts,
+		users:          services.Users,
+	}
+)
+
+// LedgerMemberListHandler returns member list of the specified ledger
+func (a *LedgerMembersApi) LedgerMemberListHandler(c *core.WebContext) (any, *errs.Error) {
+	var memberListReq models.LedgerMemberListRequest
+	err := c.ShouldBindQuery(&memberListReq)
+
+	if err != nil {
+		log.Warnf(c, "[ledger_members.LedgerMemberListHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	access, err := a.ledgerAccesses.GetLedgerAccess(c, uid, memberListReq.LedgerId)
+
+	if err != nil {
+		log.Errorf(c, "[ledger_members.LedgerMemberListHandler] failed to get ledger access \"id:%d\" for user \"uid:%d\", because %s", memberListReq.LedgerId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	members, err := a.ledgerMembers.GetAllMembersByLedgerId(c, access.OwnerUid, access.LedgerId)
+
+	if err != nil {
+		log.Errorf(c, "[ledger_members.LedgerMemberListHandler] failed to get members of ledger \"id:%d\" for user \"uid:%d\", because %s", access.LedgerId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	memberUids := make([]int64, 0, len(members)+1)
+	memberUids = append(memberUids, access.OwnerUid)
+
+	for i := 0; i < len(members); i++ {
+		memberUids = append(memberUids, members[i].MemberUid)
+	}
+
+	users, err := a.users.GetUsersByUids(c, memberUids)
+
+	if err != nil {
+		log.Errorf(c, "[ledger_members.LedgerMemberListHandler] failed to get users of ledger \"id:%d\" for user \"uid:%d\", because %s", access.LedgerId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	owner := &models.LedgerMember{
+		LedgerId:  access.LedgerId,
+		OwnerUid:  access.OwnerUid,
+		MemberUid: access.OwnerUid,
+		Role:      models.LEDGER_MEMBER_ROLE_OWNER,
+		Status:    models.LEDGER_MEMBER_STATUS_ACCEPTED,
+	}
+
+	memberResps := make(models.LedgerMemberInfoResponseSlice, 0, len(members)+1)
+	memberResps = append(memberResps, owner.ToLedgerMemberInfoResponse(users[owner.MemberUid]))
+
+	for i := 0; i < len(members); i++ {
+		memberResps = append(memberResps, members[i].ToLedgerMemberInfoResponse(users[members[i].MemberUid]))
+	}
+
+	sort.Sort(memberResps)
+
+	return memberResps, nil
+}
+
+// LedgerMemberInviteHandler invites a user to the specified ledger of current user
+func (a *LedgerMembersApi) LedgerMemberInviteHandler(c *core.WebContext) (any, *errs.Error) {
+	var memberInviteReq models.LedgerMemberInviteRequest
+	err := c.ShouldBindJSON(&memberInviteReq)
+
+	if err != nil {
+		log.Warnf(c, "[ledger_members.LedgerMemberInviteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	if !memberInviteReq.Role.IsValidMemberRole() {
+		log.Warnf(c, "[ledger_members.LedgerMemberInviteHandler] ledger member role invalid, role is %d", memberInviteReq.Role)
+		return nil, errs.ErrLedgerMemberRoleInvalid
+	}
+
+	uid := c.GetCurrentUid()
+	ledger, err := a.ledgers.GetLedgerByLedgerId(c, uid, memberInviteReq.LedgerId)
+
+	if err != nil {
+		if err == errs.ErrLedgerNotFound {
+			log.Warnf(c, "[ledger_members.LedgerMemberInviteHandler] ledger \"id:%d\" is not owned by user \"uid:%d\"", memberInviteReq.LedgerId, uid)
+			return nil, errs.ErrOnlyOwnLedgerCanBeShared
+		}
+
+		log.Errorf(c, "[ledger_members.LedgerMemberInviteHandler] failed to get ledger \"id:%d\" for user \"uid:%d\", because %s", memberInviteReq.LedgerId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	owner, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		if !errs.IsCustomError(err) {
+			log.Errorf(c, "[ledger_members.LedgerMemberInviteHandler] failed to get user, because %s", err.Error())
+		}
+
+		return nil, errs.ErrUserNotFound
+	}
+
+	invitedUser, err := a.users.GetUserByEmail(c, memberInviteReq.Email)
+
+	if err != nil {
+		if err == errs.ErrUserNotFound {
+			log.Warnf(c, "[ledger_members.LedgerMemberInviteHandler] invited user with email \"%s\" does not exist", memberInviteReq.Email)
+			return nil, errs.ErrInvitedUserNotFound
+		}
+
+		log.Errorf(c, "[ledger_members.LedgerMemberInviteHandler] failed to get invited user, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	member := &models.LedgerMember{
+		LedgerId:  ledger.LedgerId,
+		OwnerUid:  uid,
+		MemberUid: invitedUser.Uid,
+		Role:      memberInviteReq.Role,
+	}
+
+	err = a.ledgerMembers.InviteMember(c, member)
+
+	if err != nil {
+		log.Errorf(c, "[ledger_members.LedgerMemberInviteHandler] failed to invite user \"uid:%d\" to ledger \"id:%d\" for user \"uid:%d\", because %s", invitedUser.Uid, ledger.LedgerId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[ledger_members.LedgerMemberInviteHandler] user \"uid:%d\" has invited user \"uid:%d\" to ledger \"id:%d\"", uid, invitedUser.Uid, ledger.LedgerId)
+
+	if a.CurrentConfig().EnableSMTP {
+		go func() {
+			err := a.ledgerMembers.SendLedgerInvitationEmail(c, owner, invitedUser, ledger, c.GetClientLocale())
+
+			if err != nil {
+				log.Warnf(c, "[ledger_members.LedgerMemberInviteHandler] cannot send ledger invitation email to \"%s\", because %s", invitedUser.Email, err.Error())
+			}
+		}()
+	}
+
+	return member.ToLedgerMemberInfoResponse(invitedUser), nil
+}
+
+// LedgerMemberModifyHandler modifies the role of a member in the specified ledger of current user
+func (a *LedgerMembersApi) LedgerMemberModifyHandler(c *core.WebContext) (any, *errs.Error) {
+	var memberModifyReq models.LedgerMemberModifyRequest
+	err := c.ShouldBindJSON(&memberModifyReq)
+
+	if err != nil {
+		log.Warnf(c, "[ledger_members.LedgerMemberModifyHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	if !memberModifyReq.Role.IsValidMemberRole() {
+		log.Warnf(c, "[ledger_members.LedgerMemberModifyHandler] ledger member role invalid, role is %d", memberModifyReq.Role)
+		return nil, errs.ErrLedgerMemberRoleInvalid
+	}
+
+	uid := c.GetCurrentUid()
+	err = a.ledgerMembers.ModifyMemberRole(c, uid, memberModifyReq.LedgerId, memberModifyReq.MemberUid, memberModifyReq.Role)
+
+	if err != nil {
+		log.Errorf(c, "[ledger_members.LedgerMemberModifyHandler] failed to modify role of user \"uid:%d\" in ledger \"id:%d\" for user \"uid:%d\", because %s", memberModifyReq.MemberUid, memberModifyReq.LedgerId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[ledger_members.LedgerMemberModifyHandler] user \"uid:%d\" has changed role of user \"uid:%d\" in ledger \"id:%d\" to %s", uid, memberModifyReq.MemberUid, memberModifyReq.LedgerId, memberModifyReq.Role)
+	return true, nil
+}
+
+// LedgerMemberRemoveHandler removes a member or an invitation from the specified ledger of current user
+func (a *LedgerMembersApi) LedgerMemberRemoveHandler(c *core.WebContext) (any, *errs.Error) {
+	var memberRemoveReq models.LedgerMemberRemoveRequest
+	err := c.ShouldBindJSON(&memberRemoveReq)
+
+	if err != nil {
+		log.Warnf(c, "[ledger_members.LedgerMemberRemoveHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	err = a.ledgerMembers.RemoveMember(c, uid, memberRemoveReq.LedgerId, memberRemoveReq.MemberUid)
+
+	if err != nil {
+		log.Errorf(c, "[ledger_members.LedgerMemberRemoveHandler] failed to remove user \"uid:%d\" from ledger \"id:%d\" for user \"uid:%d\", because %s", memberRemoveReq.MemberUid, memberRemoveReq.LedgerId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[ledger_members.LedgerMemberRemoveHandler] user \"uid:%d\" has removed user \"uid:%d\" from ledger \"id:%d\"", uid, memberRemoveReq.MemberUid, memberRemoveReq.LedgerId)
+	return true, nil
+}
+
+// LedgerInvitationListHandler returns pending ledger invitation list of current user
+func (a *LedgerMembersApi) LedgerInvitationListHandler(c *core.WebContext) (any, *errs.Error) {
+	uid := c.GetCurrentUid()
+	invitations, err := a.ledgerMembers.GetAllSharedLedgerMembersByMemberUid(c, uid, models.LEDGER_MEMBER_STATUS_INVITED)
+
+	if err != nil {
+		log.Errorf(c, "[ledger_members.LedgerInvitationListHandler] failed to get ledger invitations for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	ledgers, err := a.ledgerMembers.GetSharedLedgers(c, invitations)
+
+	if err != nil {
+		log.Errorf(c, "[ledger_members.LedgerInvitationListHandler] failed to get invited ledgers for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	ownerUids := make([]int64, 0, len(invitations))
+
+	for i := 0; i < len(invitations); i++ {
+		ownerUids = append(ownerUids, invitations[i].OwnerUid)
+	}
+
+	owners, err := a.users.GetUsersByUids(c, utils.ToUniqueInt64Slice(ownerUids))
+
+	if err != nil {
+		log.Errorf(c, "[ledger_members.LedgerInvitationListHandler] failed to get owners of invited ledgers for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	invitationResps := make([]*models.LedgerInvitationInfoResponse, 0, len(invitations))
+
+	for i := 0; i < len(invitations); i++ {
+		invitation := invitations[i]
+		ledger, exists := ledgers[invitation.LedgerId]
+
+		if !exists {
+			continue
+		}
+
+		invitationResp := &models.LedgerInvitationInfoResponse{
+			LedgerId:   ledger.LedgerId,
+			LedgerName: ledger.Name,
+			Role:       invitation.Role,
+		}
+
+		if owner, exists := owners[invitation.OwnerUid]; exists {
+			invitationResp.OwnerNickname = owner.Nickname
+		}
+
+		invitationResps = append(invitationResps, invitationResp)
+	}
+
+	return invitationResps, nil
+}
+
+// LedgerInvitationAcceptHandler accepts the invitation of the specified ledger for current user
+func (a *LedgerMembersApi) LedgerInvitationAcceptHandler(c *core.WebContext) (any, *errs.Error) {
+	var invitationReplyReq models.LedgerInvitationReplyRequest
+	err := c.ShouldBindJSON(&invitationReplyReq)
+
+	if err != nil {
+		log.Warnf(c, "[ledger_members.LedgerInvitationAcceptHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	err = a.ledgerMembers.AcceptInvitation(c, uid, invitationReplyReq.LedgerId)
+
+	if err != nil {
+		log.Errorf(c, "[ledger_members.LedgerInvitationAcceptHandler] failed to accept invitation of ledger \"id:%d\" for user \"uid:%d\", because %s", invitationReplyReq.LedgerId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[ledger_members.LedgerInvitationAcceptHandler] user \"uid:%d\" has joined ledger \"id:%d\"", uid, invitationReplyReq.LedgerId)
+	return true, nil
+}
+
+// LedgerInvitationDeclineHandler declines the invitation of the specified ledger for current user
+func (a *LedgerMembersApi) LedgerInvitationDeclineHandler(c *core.WebContext) (any, *errs.Error) {
+	var invitationReplyReq models.LedgerInvitationReplyRequest
+	err := c.ShouldBindJSON(&invitationReplyReq)
+
+	if err != nil {
+		log.Warnf(c, "[ledger_members.LedgerInvitationDeclineHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	err = a.ledgerMembers.DeclineInvitation(c, uid, invitationReplyReq.LedgerId)
+
+	if err != nil {
+		log.Errorf(c, "[ledger_members.LedgerInvitationDeclineHandler] failed to decline invitation of ledger \"id:%d\" for user \"uid:%d\", because %s", invitationReplyReq.LedgerId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[ledger_members.LedgerInvitationDeclineHandler] user \"uid:%d\" has declined invitation of ledger \"id:%d\"", uid, invitationReplyReq.LedgerId)
+	return true, nil
+}
+
+// LedgerLeaveHandler removes current user from the specified shared ledger
+func (a *LedgerMembersApi) LedgerLeaveHandler(c *core.WebContext) (any, *errs.Error) {
+	var ledgerLeaveReq models.LedgerLeaveRequest
+	err := c.ShouldBindJSON(&ledgerLeaveReq)
+
+	if err != nil {
+		log.Warnf(c, "[ledger_members.LedgerLeaveHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	err = a.ledgerMembers.LeaveLedger(c, uid, ledgerLeaveReq.LedgerId)
+
+	if err != nil {
+		log.Errorf(c, "[ledger_members.LedgerLeaveHandler] failed to leave ledger \"id:%d\" for user \"uid:%d\", because %s", ledgerLeaveReq.LedgerId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[ledger_members.LedgerLeaveHandler] user \"uid:%d\" has left ledger \"id:%d\"", uid, ledgerLeaveReq.LedgerId)
+	return true, nil
+}
+
+// LedgerMemberStatisticsHandler returns the transaction statistics of each member in the specified ledger
+func (a *LedgerMembersApi) LedgerMemberStatisticsHandler(c *core.WebContext) (any, *errs.Error) {
+	var statisticsReq models.LedgerMemberStatisticsRequest
+	err := c.ShouldBindQuery(&statisticsReq)
+
+	if err != nil {
+		log.Warnf(c, "[ledger_members.LedgerMemberStatisticsHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	access, err := a.ledgerAccesses.GetLedgerAccess(c, uid, statisticsReq.LedgerId)
+
+	if err != nil {
+		log.Errorf(c, "[ledger_members.LedgerMemberStatisticsHandler] failed to get ledger access \"id:%d\" for user \"uid:%d\", because %s", statisticsReq.LedgerId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	totalAmounts, err := a.transactions.GetLedgerMembersTotalIncomeAndExpense(c, access, statisticsReq.StartTime, statisticsReq.EndTime)
+
+	if err != nil {
+		log.Errorf(c, "[ledger_members.LedgerMemberStatisticsHandler] failed to get member statistics of ledger \"id:%d\" for user \"uid:%d\", because %s", access.LedgerId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	accountIds := make([]int64, 0)
+	memberUids := make([]int64, 0, len(totalAmounts))
+
+	for creatorUid, totalAmount := range totalAmounts {
+		memberUids = append(memberUids, creatorUid)
+
+		for accountId := range totalAmount.IncomeAmounts {
+			accountIds = append(accountIds, accountId)
+		}
+
+		for accountId := range totalAmount.ExpenseAmounts {
+			accountIds = append(accountIds, accountId)
+		}
+	}
+
+	accounts, err := a.accounts.GetAccountsByAccountIds(c, access.OwnerUid, utils.ToUniqueInt64Slice(accountIds))
+
+	if err != nil {
+		log.Errorf(c, "[ledger_members.LedgerMemberStatisticsHandler] failed to get accounts of ledger \"id:%d\" for user \"uid:%d\", because %s", access.LedgerId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	users, err := a.users.GetUsersByUids(c, memberUids)
+
+	if err != nil {
+		log.Errorf(c, "[ledger_members.LedgerMemberStatisticsHandler] failed to get members of ledger \"id:%d\" for user \"uid:%d\", because %s", access.LedgerId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	statisticResps := make(models.LedgerMemberStatisticResponseSlice, 0, len(totalAmounts))
+
+	for creatorUid, totalAmount := range totalAmounts {
+		currencyAmounts := make(map[string]*models.LedgerMemberCurrencyStatisticResponse)
+		currencies := make([]string, 0)
+
+		getCurrencyAmount := func(accountId int64) *models.LedgerMemberCurrencyStatisticResponse {
+			account, exists := accounts[accountId]
+
+			if !exists {
+				return nil
+			}
+
+			currencyAmount, exists := currencyAmounts[account.Currency]
+
+			if !exists {
+				currencyAmount = &models.LedgerMemberCurrencyStatisticResponse{
+					Currency: account.Currency,
+				}
+				currencyAmounts[account.Currency] = currencyAmount
+				currencies = append(currencies, account.Currency)
+			}
+
+			return currencyAmount
+		}
+
+		for accountId, amount := range totalAmount.IncomeAmounts {
+			if currencyAmount := getCurrencyAmount(accountId); currencyAmount != nil {
+				currencyAmount.IncomeAmount += amount
+			}
+		}
+
+		for accountId, amount := range totalAmount.ExpenseAmounts {
+			if currencyAmount := getCurrencyAmount(accountId); currencyAmount != nil {
+				currencyAmount.ExpenseAmount += amount
+			}
+		}
+
+		sort.Strings(currencies)
+
+		statisticResp := &models.LedgerMemberStatisticResponse{
+			Uid:                   creatorUid,
+			TotalTransactionCount: totalAmount.TransactionCount,
+			Amounts:               make([]*models.LedgerMemberCurrencyStatisticResponse, 0, len(currencies)),
+		}
+
+		if user, exists := users[creatorUid]; exists {
+			statisticResp.Nickname = user.Nickname
+		}
+
+		for i := 0; i < len(currencies); i++ {
+			statisticResp.Amounts = append(statisticResp.Amounts, currencyAmounts[currencies[i]])
+		}
+
+		statisticResps = append(statisticResps, statisticResp)
+	}
+
+	sort.Sort(statisticResps)
+
+	return statisticResps, nil
+}
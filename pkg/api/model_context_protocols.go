@@ -22,6 +22,7 @@ type ModelContextProtocolAPI struct {
 	transactionCategories *services.TransactionCategoryService
 	transactionTags       *services.TransactionTagService
 	accounts              *services.AccountService
+	ledgerAccesses        *services.LedgerAccessService
 	users                 *services.UserService
 	tokens                *services.TokenService
 }
@@ -36,6 +37,7 @@ var (
 		transactionCategories: services.TransactionCategories,
 		transactionTags:       services.TransactionTags,
 		accounts:              services.Accounts,
+		ledgerAccesses:        services.LedgerAccesses,
 		users:                 services.Users,
 		tokens:                services.Tokens,
 	}
@@ -254,6 +256,11 @@ func (a *ModelContextProtocolAPI) GetAccountService() *services.AccountService {
 	return a.accounts
 }
 
+// GetLedgerAccessService implements the MCPAvailableServices interface
+func (a *ModelContextProtocolAPI) GetLedgerAccessService() *services.LedgerAccessService {
+	return a.ledgerAccesses
+}
+
 // GetUserService implements the MCPAvailableServices interface
 func (a *ModelContextProtocolAPI) GetUserService() *services.UserService {
 	return a.users
@@ -32,6 +32,7 @@ type OAuth2AuthenticationApi struct {
 	users             *services.UserService
 	tokens            *services.TokenService
 	userExternalAuths *services.UserExternalAuthService
+	ledgers           *services.LedgerService
 }
 
 // Initialize a OAuth 2.0 authentication api singleton instance
@@ -49,6 +50,7 @@ var (
 		users:             services.Users,
 		tokens:            services.Tokens,
 		userExternalAuths: services.UserExternalAuths,
+		ledgers:           services.Ledgers,
 	}
 )
 
@@ -322,6 +324,13 @@ func (a *OAuth2AuthenticationApi) CallbackHandler(c *core.WebContext) (string, *
 
 			log.Infof(c, "[oauth2_authentications.CallbackHandler] user \"%s\" has registered successfully, uid is %d", user.Username, user.Uid)
 
+			_, err = a.ledgers.GetOrCreateDefaultLedger(c, user.Uid)
+
+			if err != nil {
+				log.Errorf(c, "[oauth2_authentications.CallbackHandler] failed to create default ledger for user \"uid:%d\", because %s", user.Uid, err.Error())
+				return a.redirectToFailedCallbackPage(c, errs.Or(err, errs.ErrOperationFailed))
+			}
+
 			userExternalAuth = &models.UserExternalAuth{
 				Uid:              user.Uid,
 				ExternalAuthType: userExternalAuthType,
@@ -0,0 +1,304 @@
+package api
+
+import (
+	"io"
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/duplicatechecker"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/thumbnails"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// TransactionAttachmentsApi represents transaction attachments api
+type TransactionAttachmentsApi struct {
+	ApiUsingConfig
+	ApiUsingDuplicateChecker
+	ApiUsingLedgerAccess
+	ApiUsingUserQuota
+	attachments *services.TransactionAttachmentService
+}
+
+// Initialize a transaction attachment api singleton instance
+var (
+	TransactionAttachments = &TransactionAttachmentsApi{
+		ApiUsingConfig: ApiUsingConfig{
+			container: settings.Container,
+		},
+		ApiUsingDuplicateChecker: ApiUsingDuplicateChecker{
+			ApiUsingConfig: ApiUsingConfig{
+				container: settings.Container,
+			},
+			container: duplicatechecker.Container,
+		},
+		ApiUsingLedgerAccess: ApiUsingLedgerAccess{
+			ledgerAccesses: services.LedgerAccesses,
+		},
+		ApiUsingUserQuota: ApiUsingUserQuota{
+			users:      services.Users,
+			userQuotas: services.UserQuotas,
+		},
+		attachments: services.TransactionAttachments,
+	}
+)
+
+// TransactionAttachmentUploadHandler saves transaction attachment by request parameters for current user
+func (a *TransactionAttachmentsApi) TransactionAttachmentUploadHandler(c *core.WebContext) (any, *errs.Error) {
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_CREATE_TRANSACTIONS)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	form, err := c.MultipartForm()
+
+	if err != nil {
+		log.Errorf(c, "[transaction_attachments.TransactionAttachmentUploadHandler] failed to get multi-part form data for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrParameterInvalid
+	}
+
+	attachmentFiles := form.File["attachment"]
+
+	if len(attachmentFiles) < 1 {
+		log.Warnf(c, "[transaction_attachments.TransactionAttachmentUploadHandler] there is no transaction attachment in request for user \"uid:%d\"", uid)
+		return nil, errs.ErrNoTransactionAttachment
+	}
+
+	if attachmentFiles[0].Size < 1 {
+		log.Warnf(c, "[transaction_attachments.TransactionAttachmentUploadHandler] the size of transaction attachment in request is zero for user \"uid:%d\"", uid)
+		return nil, errs.ErrTransactionAttachmentIsEmpty
+	}
+
+	fileName := models.GetTransactionAttachmentFileName(attachmentFiles[0].Filename)
+	fileExtension := strings.ToLower(utils.GetFileNameExtension(fileName))
+
+	if fileName == "" || fileExtension == "" {
+		log.Warnf(c, "[transaction_attachments.TransactionAttachmentUploadHandler] the file name of transaction attachment in request is invalid for user \"uid:%d\"", uid)
+		return nil, errs.ErrTransactionAttachmentFileNameInvalid
+	}
+
+	fileType, _ := models.GetTransactionAttachmentFileTypeAndContentType(fileExtension)
+
+	if fileType == 0 {
+		log.Warnf(c, "[transaction_attachments.TransactionAttachmentUploadHandler] the file extension \"%s\" of transaction attachment in request is not supported for user \"uid:%d\"", fileExtension, uid)
+		return nil, errs.ErrTransactionAttachmentTypeNotSupported
+	}
+
+	maxFileSize := a.getMaxAttachmentFileSize(fileType)
+
+	if attachmentFiles[0].Size > int64(maxFileSize) {
+		log.Warnf(c, "[transaction_attachments.TransactionAttachmentUploadHandler] the upload file size \"%d\" exceeds the maximum size \"%d\" of transaction attachment type \"%d\" for user \"uid:%d\"", attachmentFiles[0].Size, maxFileSize, fileType, uid)
+		return nil, errs.ErrExceedMaxTransactionAttachmentFileSize
+	}
+
+	attachmentFile, err := attachmentFiles[0].Open()
+
+	if err != nil {
+		log.Errorf(c, "[transaction_attachments.TransactionAttachmentUploadHandler] failed to get transaction attachment file from request for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	defer attachmentFile.Close()
+
+	attachmentData, err := io.ReadAll(io.LimitReader(attachmentFile, int64(maxFileSize)+1))
+
+	if err != nil {
+		log.Errorf(c, "[transaction_attachments.TransactionAttachmentUploadHandler] failed to read transaction attachment file for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	if len(attachmentData) > int(maxFileSize) {
+		log.Warnf(c, "[transaction_attachments.TransactionAttachmentUploadHandler] the actual file size exceeds the maximum size \"%d\" of transaction attachment type \"%d\" for user \"uid:%d\"", maxFileSize, fileType, uid)
+		return nil, errs.ErrExceedMaxTransactionAttachmentFileSize
+	}
+
+	sniffedContentType := utils.DetectFileContentType(attachmentData)
+
+	if !models.IsTransactionAttachmentContentMatched(fileExtension, sniffedContentType) {
+		log.Warnf(c, "[transaction_attachments.TransactionAttachmentUploadHandler] the content type \"%s\" of transaction attachment does not match the file extension \"%s\" for user \"uid:%d\"", sniffedContentType, fileExtension, uid)
+		return nil, errs.ErrTransactionAttachmentContentMismatch
+	}
+
+	clientSessionIds := form.Value["clientSessionId"]
+	clientSessionId := ""
+
+	if len(clientSessionIds) > 0 {
+		clientSessionId = clientSessionIds[0]
+	}
+
+	if a.CurrentConfig().EnableDuplicateSubmissionsCheck && clientSessionId != "" {
+		found, remark := a.GetSubmissionRemark(duplicatechecker.DUPLICATE_CHECKER_TYPE_NEW_ATTACHMENT, uid, clientSessionId)
+
+		if found {
+			log.Infof(c, "[transaction_attachments.TransactionAttachmentUploadHandler] another transaction attachment \"id:%s\" has been uploaded for user \"uid:%d\"", remark, uid)
+			attachmentId, err := utils.StringToInt64(remark)
+
+			if err == nil {
+				attachment, err := a.attachments.GetAttachmentByAttachmentId(c, uid, attachmentId)
+
+				if err != nil {
+					log.Errorf(c, "[transaction_attachments.TransactionAttachmentUploadHandler] failed to get existed transaction attachment \"id:%d\" for user \"uid:%d\", because %s", attachmentId, uid, err.Error())
+					return nil, errs.Or(err, errs.ErrOperationFailed)
+				}
+
+				return a.GetTransactionAttachmentInfoResponse(attachment), nil
+			}
+		}
+	}
+
+	errResult = a.CheckUserStorageQuota(c, uid, int64(len(attachmentData)))
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	attachment := &models.TransactionAttachment{
+		Uid:           uid,
+		TransactionId: models.TransactionAttachmentNewAttachmentTransactionId,
+		FileType:      fileType,
+		FileName:      fileName,
+		FileExtension: fileExtension,
+		CreatedIp:     c.ClientIP(),
+	}
+
+	thumbnailData := a.createThumbnail(c, uid, fileType, attachmentData)
+	err = a.attachments.UploadAttachment(c, attachment, attachmentData, thumbnailData)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_attachments.TransactionAttachmentUploadHandler] failed to upload transaction attachment for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transaction_attachments.TransactionAttachmentUploadHandler] user \"uid:%d\" has uploaded a new transaction attachment \"id:%d\" successfully", uid, attachment.AttachmentId)
+
+	a.SetSubmissionRemarkIfEnable(duplicatechecker.DUPLICATE_CHECKER_TYPE_NEW_ATTACHMENT, uid, clientSessionId, utils.Int64ToString(attachment.AttachmentId))
+
+	return a.GetTransactionAttachmentInfoResponse(attachment), nil
+}
+
+// TransactionAttachmentGetHandler returns transaction attachment file data for current user
+func (a *TransactionAttachmentsApi) TransactionAttachmentGetHandler(c *core.WebContext) ([]byte, string, string, *errs.Error) {
+	fileName := c.Param("fileName")
+	fileExtension := utils.GetFileNameExtension(fileName)
+	attachmentId, err := utils.StringToInt64(utils.GetFileNameWithoutExtension(fileName))
+
+	if err != nil {
+		return nil, "", "", errs.ErrTransactionAttachmentIdInvalid
+	}
+
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_VIEW_TRANSACTIONS)
+
+	if errResult != nil {
+		return nil, "", "", errResult
+	}
+
+	attachment, attachmentData, err := a.attachments.GetAttachmentDataByAttachmentId(c, uid, attachmentId, fileExtension, false)
+
+	if err != nil {
+		if !errs.IsCustomError(err) {
+			log.Errorf(c, "[transaction_attachments.TransactionAttachmentGetHandler] failed to get transaction attachment, because %s", err.Error())
+		}
+
+		return nil, "", "", errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return attachmentData, attachment.GetContentType(), attachment.FileName, nil
+}
+
+// TransactionAttachmentThumbnailGetHandler returns transaction attachment thumbnail data for current user
+func (a *TransactionAttachmentsApi) TransactionAttachmentThumbnailGetHandler(c *core.WebContext) ([]byte, string, *errs.Error) {
+	fileName := c.Param("fileName")
+	attachmentId, err := utils.StringToInt64(utils.GetFileNameWithoutExtension(fileName))
+
+	if err != nil {
+		return nil, "", errs.ErrTransactionAttachmentIdInvalid
+	}
+
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_VIEW_TRANSACTIONS)
+
+	if errResult != nil {
+		return nil, "", errResult
+	}
+
+	_, thumbnailData, err := a.attachments.GetAttachmentDataByAttachmentId(c, uid, attachmentId, "", true)
+
+	if err != nil {
+		if !errs.IsCustomError(err) {
+			log.Errorf(c, "[transaction_attachments.TransactionAttachmentThumbnailGetHandler] failed to get transaction attachment thumbnail, because %s", err.Error())
+		}
+
+		return nil, "", errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return thumbnailData, utils.GetImageContentType("jpg"), nil
+}
+
+// TransactionAttachmentRemoveUnusedHandler removes unused transaction attachment by request parameters for current user
+func (a *TransactionAttachmentsApi) TransactionAttachmentRemoveUnusedHandler(c *core.WebContext) (any, *errs.Error) {
+	var attachmentDeleteReq models.TransactionAttachmentUnusedDeleteRequest
+	err := c.ShouldBindJSON(&attachmentDeleteReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_attachments.TransactionAttachmentRemoveUnusedHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_CREATE_TRANSACTIONS)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	err = a.attachments.RemoveUnusedTransactionAttachment(c, uid, attachmentDeleteReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_attachments.TransactionAttachmentRemoveUnusedHandler] failed to remove unused transaction attachment for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return true, nil
+}
+
+func (a *TransactionAttachmentsApi) getMaxAttachmentFileSize(fileType models.TransactionAttachmentFileType) uint32 {
+	config := a.CurrentConfig()
+
+	switch fileType {
+	case models.TRANSACTION_ATTACHMENT_FILE_TYPE_IMAGE:
+		return config.MaxTransactionAttachmentImageFileSize
+	case models.TRANSACTION_ATTACHMENT_FILE_TYPE_PDF:
+		return config.MaxTransactionAttachmentPdfFileSize
+	case models.TRANSACTION_ATTACHMENT_FILE_TYPE_DOCUMENT:
+		return config.MaxTransactionAttachmentDocumentFileSize
+	case models.TRANSACTION_ATTACHMENT_FILE_TYPE_TEXT:
+		return config.MaxTransactionAttachmentTextFileSize
+	case models.TRANSACTION_ATTACHMENT_FILE_TYPE_ARCHIVE:
+		return config.MaxTransactionAttachmentArchiveFileSize
+	default:
+		return config.MaxTransactionAttachmentFileSize
+	}
+}
+
+func (a *TransactionAttachmentsApi) createThumbnail(c *core.WebContext, uid int64, fileType models.TransactionAttachmentFileType, attachmentData []byte) []byte {
+	var thumbnailData []byte
+	var err error
+
+	if fileType == models.TRANSACTION_ATTACHMENT_FILE_TYPE_PDF {
+		thumbnailData, err = thumbnails.CreatePdfThumbnail(attachmentData, thumbnails.DefaultThumbnailMaxSize)
+	} else if fileType == models.TRANSACTION_ATTACHMENT_FILE_TYPE_IMAGE {
+		thumbnailData, err = thumbnails.CreateThumbnailFromImageData(attachmentData, thumbnails.DefaultThumbnailMaxSize)
+	} else {
+		return nil
+	}
+
+	if err != nil {
+		log.Debugf(c, "[transaction_attachments.createThumbnail] cannot create thumbnail of transaction attachment for user \"uid:%d\", because %s", uid, err.Error())
+		return nil
+	}
+
+	return thumbnailData
+}
@@ -19,6 +19,7 @@ import (
 type TransactionCategoriesApi struct {
 	ApiUsingConfig
 	ApiUsingDuplicateChecker
+	ApiUsingLedgerAccess
 	categories *services.TransactionCategoryService
 }
 
@@ -34,6 +35,9 @@ var (
 			},
 			container: duplicatechecker.Container,
 		},
+		ApiUsingLedgerAccess: ApiUsingLedgerAccess{
+			ledgerAccesses: services.LedgerAccesses,
+		},
 		categories: services.TransactionCategories,
 	}
 )
@@ -48,8 +52,13 @@ func (a *TransactionCategoriesApi) CategoryListHandler(c *core.WebContext) (any,
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
-	categories, err := a.categories.GetAllCategoriesByUid(c, uid, categoryListReq.Type, categoryListReq.ParentId)
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_VIEW_BASIC_DATA)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	categories, err := a.categories.GetAllCategoriesByUid(c, a.GetCurrentLedgerAccess(c), categoryListReq.Type, categoryListReq.ParentId)
 
 	if err != nil {
 		log.Errorf(c, "[transaction_categories.CategoryListHandler] failed to get categories for user \"uid:%d\", because %s", uid, err.Error())
@@ -69,7 +78,12 @@ func (a *TransactionCategoriesApi) CategoryGetHandler(c *core.WebContext) (any,
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_VIEW_BASIC_DATA)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
 	category, err := a.categories.GetCategoryByCategoryId(c, uid, categoryGetReq.Id)
 
 	if err != nil {
@@ -77,6 +91,12 @@ func (a *TransactionCategoriesApi) CategoryGetHandler(c *core.WebContext) (any,
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
+	errResult = a.CheckDataInCurrentLedger(c, category.LedgerId)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
 	categoryResp := category.ToTransactionCategoryInfoResponse()
 
 	return categoryResp, nil
@@ -97,7 +117,11 @@ func (a *TransactionCategoriesApi) CategoryCreateHandler(c *core.WebContext) (an
 		return nil, errs.ErrTransactionCategoryTypeInvalid
 	}
 
-	uid := c.GetCurrentUid()
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_MANAGE_BASIC_DATA)
+
+	if errResult != nil {
+		return nil, errResult
+	}
 
 	if categoryCreateReq.ParentId > 0 {
 		parentCategory, err := a.categories.GetCategoryByCategoryId(c, uid, categoryCreateReq.ParentId)
@@ -131,7 +155,7 @@ func (a *TransactionCategoriesApi) CategoryCreateHandler(c *core.WebContext) (an
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
-	category := a.createNewCategoryModel(uid, &categoryCreateReq, maxOrderId+1)
+	category := a.createNewCategoryModel(uid, c.GetCurrentLedgerId(), &categoryCreateReq, maxOrderId+1)
 
 	if a.CurrentConfig().EnableDuplicateSubmissionsCheck && categoryCreateReq.ClientSessionId != "" {
 		found, remark := a.GetSubmissionRemark(duplicatechecker.DUPLICATE_CHECKER_TYPE_NEW_CATEGORY, uid, categoryCreateReq.ClientSessionId)
@@ -180,7 +204,11 @@ func (a *TransactionCategoriesApi) CategoryCreateBatchHandler(c *core.WebContext
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_MANAGE_BASIC_DATA)
+
+	if errResult != nil {
+		return nil, errResult
+	}
 
 	categories, err := a.createBatchCategories(c, uid, &categoryCreateBatchReq)
 
@@ -201,7 +229,12 @@ func (a *TransactionCategoriesApi) CategoryModifyHandler(c *core.WebContext) (an
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_MANAGE_BASIC_DATA)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
 	category, err := a.categories.GetCategoryByCategoryId(c, uid, categoryModifyReq.Id)
 
 	if err != nil {
@@ -209,6 +242,12 @@ func (a *TransactionCategoriesApi) CategoryModifyHandler(c *core.WebContext) (an
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
+	errResult = a.CheckDataInCurrentLedger(c, category.LedgerId)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
 	newCategory := &models.TransactionCategory{
 		CategoryId:       category.CategoryId,
 		Uid:              uid,
@@ -287,7 +326,18 @@ func (a *TransactionCategoriesApi) CategoryHideHandler(c *core.WebContext) (any,
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_MANAGE_BASIC_DATA)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	errResult = a.checkCategoryInCurrentLedger(c, uid, categoryHideReq.Id)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
 	err = a.categories.HideCategory(c, uid, []int64{categoryHideReq.Id}, categoryHideReq.Hidden)
 
 	if err != nil {
@@ -309,7 +359,12 @@ func (a *TransactionCategoriesApi) CategoryMoveHandler(c *core.WebContext) (any,
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_MANAGE_BASIC_DATA)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
 	categories := make([]*models.TransactionCategory, len(categoryMoveReq.NewDisplayOrders))
 
 	for i := 0; i < len(categoryMoveReq.NewDisplayOrders); i++ {
@@ -344,7 +399,18 @@ func (a *TransactionCategoriesApi) CategoryDeleteHandler(c *core.WebContext) (an
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_MANAGE_BASIC_DATA)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	errResult = a.checkCategoryInCurrentLedger(c, uid, categoryDeleteReq.Id)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
 	err = a.categories.DeleteCategory(c, uid, categoryDeleteReq.Id)
 
 	if err != nil {
@@ -356,6 +422,81 @@ func (a *TransactionCategoriesApi) CategoryDeleteHandler(c *core.WebContext) (an
 	return true, nil
 }
 
+// CategoryMergeHandler merges an existed transaction category into another one by request parameters for current user
+func (a *TransactionCategoriesApi) CategoryMergeHandler(c *core.WebContext) (any, *errs.Error) {
+	var categoryMergeReq models.TransactionCategoryMergeRequest
+	err := c.ShouldBindJSON(&categoryMergeReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_categories.CategoryMergeHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	if categoryMergeReq.FromCategoryId == categoryMergeReq.ToCategoryId {
+		return nil, errs.ErrCannotMergeTransactionCategoryIntoItself
+	}
+
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_MANAGE_BASIC_DATA)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	categoryMap, err := a.categories.GetCategoriesByCategoryIds(c, uid, []int64{categoryMergeReq.FromCategoryId, categoryMergeReq.ToCategoryId})
+
+	if err != nil {
+		log.Errorf(c, "[transaction_categories.CategoryMergeHandler] failed to get categories for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	fromCategory, exists := categoryMap[categoryMergeReq.FromCategoryId]
+
+	if !exists {
+		return nil, errs.ErrTransactionCategoryNotFound
+	}
+
+	toCategory, exists := categoryMap[categoryMergeReq.ToCategoryId]
+
+	if !exists {
+		return nil, errs.ErrTransactionCategoryNotFound
+	}
+
+	if fromCategory.LedgerId != toCategory.LedgerId {
+		return nil, errs.ErrCannotUseDataInDifferentLedger
+	}
+
+	errResult = a.CheckDataInCurrentLedger(c, fromCategory.LedgerId)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	err = a.categories.MergeCategory(c, uid, categoryMergeReq.FromCategoryId, categoryMergeReq.ToCategoryId)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_categories.CategoryMergeHandler] failed to merge category \"id:%d\" into category \"id:%d\" for user \"uid:%d\", because %s", categoryMergeReq.FromCategoryId, categoryMergeReq.ToCategoryId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transaction_categories.CategoryMergeHandler] user \"uid:%d\" has merged category \"id:%d\" into category \"id:%d\"", uid, categoryMergeReq.FromCategoryId, categoryMergeReq.ToCategoryId)
+	return true, nil
+}
+
+func (a *TransactionCategoriesApi) checkCategoryInCurrentLedger(c *core.WebContext, uid int64, categoryId int64) *errs.Error {
+	if a.GetCurrentLedgerAccess(c).IsOwner() {
+		return nil
+	}
+
+	category, err := a.categories.GetCategoryByCategoryId(c, uid, categoryId)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_categories.checkCategoryInCurrentLedger] failed to get category \"id:%d\" for user \"uid:%d\", because %s", categoryId, uid, err.Error())
+		return errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return a.CheckDataInCurrentLedger(c, category.LedgerId)
+}
+
 func (a *TransactionCategoriesApi) createBatchCategories(c *core.WebContext, uid int64, categoryCreateBatchReq *models.TransactionCategoryCreateBatchRequest) ([]*models.TransactionCategory, error) {
 	var err error
 	categoryTypeMaxOrderMap := make(map[models.TransactionCategoryType]int32)
@@ -376,7 +517,7 @@ func (a *TransactionCategoriesApi) createBatchCategories(c *core.WebContext, uid
 			}
 		}
 
-		category := a.createNewCategoryModel(uid, &models.TransactionCategoryCreateRequest{
+		category := a.createNewCategoryModel(uid, c.GetCurrentLedgerId(), &models.TransactionCategoryCreateRequest{
 			Name:  categoryCreateReq.Name,
 			Type:  categoryCreateReq.Type,
 			Icon:  categoryCreateReq.Icon,
@@ -386,7 +527,7 @@ func (a *TransactionCategoriesApi) createBatchCategories(c *core.WebContext, uid
 		categoriesMap[category] = make([]*models.TransactionCategory, len(categoryCreateReq.SubCategories))
 
 		for j := int32(0); j < int32(len(categoryCreateReq.SubCategories)); j++ {
-			subCategory := a.createNewCategoryModel(uid, categoryCreateReq.SubCategories[j], j+1)
+			subCategory := a.createNewCategoryModel(uid, c.GetCurrentLedgerId(), categoryCreateReq.SubCategories[j], j+1)
 			categoriesMap[category][j] = subCategory
 			totalCount++
 		}
@@ -408,9 +549,10 @@ func (a *TransactionCategoriesApi) createBatchCategories(c *core.WebContext, uid
 	return categories, nil
 }
 
-func (a *TransactionCategoriesApi) createNewCategoryModel(uid int64, categoryCreateReq *models.TransactionCategoryCreateRequest, order int32) *models.TransactionCategory {
+func (a *TransactionCategoriesApi) createNewCategoryModel(uid int64, ledgerId int64, categoryCreateReq *models.TransactionCategoryCreateRequest, order int32) *models.TransactionCategory {
 	return &models.TransactionCategory{
 		Uid:              uid,
+		LedgerId:         ledgerId,
 		Name:             categoryCreateReq.Name,
 		Type:             categoryCreateReq.Type,
 		ParentCategoryId: categoryCreateReq.ParentId,
@@ -0,0 +1,296 @@
+package api
+
+import (
+	"sort"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+)
+
+// TransactionCustomFieldsApi represents transaction custom field api
+type TransactionCustomFieldsApi struct {
+	ApiUsingLedgerAccess
+	customFields *services.TransactionCustomFieldService
+}
+
+// Initialize a transaction custom field api singleton instance
+var (
+	TransactionCustomFields = &TransactionCustomFieldsApi{
+		ApiUsingLedgerAccess: ApiUsingLedgerAccess{
+			ledgerAccesses: services.LedgerAccesses,
+		},
+		customFields: services.TransactionCustomFields,
+	}
+)
+
+// CustomFieldListHandler returns transaction custom field list of current user
+func (a *TransactionCustomFieldsApi) CustomFieldListHandler(c *core.WebContext) (any, *errs.Error) {
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_VIEW_BASIC_DATA)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	customFields, err := a.customFields.GetAllCustomFieldsByUid(c, a.GetCurrentLedgerAccess(c))
+
+	if err != nil {
+		log.Errorf(c, "[transaction_custom_fields.CustomFieldListHandler] failed to get custom fields for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	customFieldResps := make(models.TransactionCustomFieldInfoResponseSlice, len(customFields))
+
+	for i := 0; i < len(customFields); i++ {
+		customFieldResps[i] = customFields[i].ToTransactionCustomFieldInfoResponse()
+	}
+
+	sort.Sort(customFieldResps)
+
+	return customFieldResps, nil
+}
+
+// CustomFieldGetHandler returns one specific transaction custom field of current user
+func (a *TransactionCustomFieldsApi) CustomFieldGetHandler(c *core.WebContext) (any, *errs.Error) {
+	var customFieldGetReq models.TransactionCustomFieldGetRequest
+	err := c.ShouldBindQuery(&customFieldGetReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_custom_fields.CustomFieldGetHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_VIEW_BASIC_DATA)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	customField, errResult := a.getCustomFieldInCurrentLedger(c, uid, customFieldGetReq.Id)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	return customField.ToTransactionCustomFieldInfoResponse(), nil
+}
+
+// CustomFieldCreateHandler saves a new transaction custom field by request parameters for current user
+func (a *TransactionCustomFieldsApi) CustomFieldCreateHandler(c *core.WebContext) (any, *errs.Error) {
+	var customFieldCreateReq models.TransactionCustomFieldCreateRequest
+	err := c.ShouldBindJSON(&customFieldCreateReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_custom_fields.CustomFieldCreateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	if !customFieldCreateReq.Type.IsValid() {
+		log.Warnf(c, "[transaction_custom_fields.CustomFieldCreateHandler] custom field type invalid, type is %d", customFieldCreateReq.Type)
+		return nil, errs.ErrTransactionCustomFieldTypeInvalid
+	}
+
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_MANAGE_BASIC_DATA)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	totalCount, err := a.customFields.GetTotalCustomFieldCountByLedgerId(c, uid, c.GetCurrentLedgerId())
+
+	if err != nil {
+		log.Errorf(c, "[transaction_custom_fields.CustomFieldCreateHandler] failed to get total custom field count for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	if totalCount >= models.MaximumCustomFieldsCountPerLedger {
+		log.Warnf(c, "[transaction_custom_fields.CustomFieldCreateHandler] there are too many custom fields for user \"uid:%d\"", uid)
+		return nil, errs.ErrTooManyTransactionCustomFields
+	}
+
+	maxOrderId, err := a.customFields.GetMaxDisplayOrder(c, uid, c.GetCurrentLedgerId())
+
+	if err != nil {
+		log.Errorf(c, "[transaction_custom_fields.CustomFieldCreateHandler] failed to get max display order for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	customField := &models.TransactionCustomField{
+		Uid:          uid,
+		LedgerId:     c.GetCurrentLedgerId(),
+		Name:         customFieldCreateReq.Name,
+		Type:         customFieldCreateReq.Type,
+		DisplayOrder: maxOrderId + 1,
+	}
+
+	err = customField.SetOptions(customFieldCreateReq.Options)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_custom_fields.CustomFieldCreateHandler] custom field options invalid for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrTransactionCustomFieldOptionsInvalid)
+	}
+
+	err = a.customFields.CreateCustomField(c, customField)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_custom_fields.CustomFieldCreateHandler] failed to create custom field \"id:%d\" for user \"uid:%d\", because %s", customField.FieldId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transaction_custom_fields.CustomFieldCreateHandler] user \"uid:%d\" has created a new custom field \"id:%d\" successfully", uid, customField.FieldId)
+
+	return customField.ToTransactionCustomFieldInfoResponse(), nil
+}
+
+// CustomFieldModifyHandler saves an existed transaction custom field by request parameters for current user
+func (a *TransactionCustomFieldsApi) CustomFieldModifyHandler(c *core.WebContext) (any, *errs.Error) {
+	var customFieldModifyReq models.TransactionCustomFieldModifyRequest
+	err := c.ShouldBindJSON(&customFieldModifyReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_custom_fields.CustomFieldModifyHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_MANAGE_BASIC_DATA)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	customField, errResult := a.getCustomFieldInCurrentLedger(c, uid, customFieldModifyReq.Id)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	newCustomField := &models.TransactionCustomField{
+		FieldId:  customField.FieldId,
+		Uid:      uid,
+		LedgerId: customField.LedgerId,
+		Name:     customFieldModifyReq.Name,
+		Type:     customField.Type,
+		Hidden:   customFieldModifyReq.Hidden,
+	}
+
+	err = newCustomField.SetOptions(customFieldModifyReq.Options)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_custom_fields.CustomFieldModifyHandler] custom field options invalid for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrTransactionCustomFieldOptionsInvalid)
+	}
+
+	nameChanged := newCustomField.Name != customField.Name
+
+	if !nameChanged &&
+		newCustomField.Options == customField.Options &&
+		newCustomField.Hidden == customField.Hidden {
+		return nil, errs.ErrNothingWillBeUpdated
+	}
+
+	err = a.customFields.ModifyCustomField(c, newCustomField, nameChanged)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_custom_fields.CustomFieldModifyHandler] failed to update custom field \"id:%d\" for user \"uid:%d\", because %s", customFieldModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transaction_custom_fields.CustomFieldModifyHandler] user \"uid:%d\" has updated custom field \"id:%d\" successfully", uid, customFieldModifyReq.Id)
+
+	newCustomField.DisplayOrder = customField.DisplayOrder
+
+	return newCustomField.ToTransactionCustomFieldInfoResponse(), nil
+}
+
+// CustomFieldMoveHandler moves display order of existed transaction custom fields by request parameters for current user
+func (a *TransactionCustomFieldsApi) CustomFieldMoveHandler(c *core.WebContext) (any, *errs.Error) {
+	var customFieldMoveReq models.TransactionCustomFieldMoveRequest
+	err := c.ShouldBindJSON(&customFieldMoveReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_custom_fields.CustomFieldMoveHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_MANAGE_BASIC_DATA)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	customFields := make([]*models.TransactionCustomField, len(customFieldMoveReq.NewDisplayOrders))
+
+	for i := 0; i < len(customFieldMoveReq.NewDisplayOrders); i++ {
+		newDisplayOrder := customFieldMoveReq.NewDisplayOrders[i]
+
+		if _, errResult := a.getCustomFieldInCurrentLedger(c, uid, newDisplayOrder.Id); errResult != nil {
+			return nil, errResult
+		}
+
+		customFields[i] = &models.TransactionCustomField{
+			Uid:          uid,
+			FieldId:      newDisplayOrder.Id,
+			DisplayOrder: newDisplayOrder.DisplayOrder,
+		}
+	}
+
+	err = a.customFields.ModifyCustomFieldDisplayOrders(c, uid, customFields)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_custom_fields.CustomFieldMoveHandler] failed to move custom fields for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transaction_custom_fields.CustomFieldMoveHandler] user \"uid:%d\" has moved custom fields", uid)
+	return true, nil
+}
+
+// CustomFieldDeleteHandler deletes an existed transaction custom field and all its values by request parameters for current user
+func (a *TransactionCustomFieldsApi) CustomFieldDeleteHandler(c *core.WebContext) (any, *errs.Error) {
+	var customFieldDeleteReq models.TransactionCustomFieldDeleteRequest
+	err := c.ShouldBindJSON(&customFieldDeleteReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_custom_fields.CustomFieldDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_MANAGE_BASIC_DATA)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	if _, errResult := a.getCustomFieldInCurrentLedger(c, uid, customFieldDeleteReq.Id); errResult != nil {
+		return nil, errResult
+	}
+
+	err = a.customFields.DeleteCustomField(c, uid, customFieldDeleteReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_custom_fields.CustomFieldDeleteHandler] failed to delete custom field \"id:%d\" for user \"uid:%d\", because %s", customFieldDeleteReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transaction_custom_fields.CustomFieldDeleteHandler] user \"uid:%d\" has deleted custom field \"id:%d\"", uid, customFieldDeleteReq.Id)
+	return true, nil
+}
+
+func (a *TransactionCustomFieldsApi) getCustomFieldInCurrentLedger(c *core.WebContext, uid int64, fieldId int64) (*models.TransactionCustomField, *errs.Error) {
+	customField, err := a.customFields.GetCustomFieldByFieldId(c, uid, fieldId)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_custom_fields.getCustomFieldInCurrentLedger] failed to get custom field \"id:%d\" for user \"uid:%d\", because %s", fieldId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	errResult := a.CheckDataInCurrentLedger(c, customField.LedgerId)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	return customField, nil
+}
@@ -1,6 +1,8 @@
 package api
 
 import (
+	"io"
+
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/duplicatechecker"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
@@ -8,13 +10,18 @@ import (
 	"github.com/mayswind/ezbookkeeping/pkg/models"
 	"github.com/mayswind/ezbookkeeping/pkg/services"
 	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/thumbnails"
 	"github.com/mayswind/ezbookkeeping/pkg/utils"
 )
 
+const convertedTransactionPictureJpegQuality = 90
+
 // TransactionPicturesApi represents transaction pictures api
 type TransactionPicturesApi struct {
 	ApiUsingConfig
 	ApiUsingDuplicateChecker
+	ApiUsingLedgerAccess
+	ApiUsingUserQuota
 	users    *services.UserService
 	pictures *services.TransactionPictureService
 }
@@ -31,6 +38,13 @@ var (
 			},
 			container: duplicatechecker.Container,
 		},
+		ApiUsingLedgerAccess: ApiUsingLedgerAccess{
+			ledgerAccesses: services.LedgerAccesses,
+		},
+		ApiUsingUserQuota: ApiUsingUserQuota{
+			users:      services.Users,
+			userQuotas: services.UserQuotas,
+		},
 		users:    services.Users,
 		pictures: services.TransactionPictures,
 	}
@@ -38,7 +52,12 @@ var (
 
 // TransactionPictureUploadHandler saves transaction picture by request parameters for current user
 func (a *TransactionPicturesApi) TransactionPictureUploadHandler(c *core.WebContext) (any, *errs.Error) {
-	uid := c.GetCurrentUid()
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_CREATE_TRANSACTIONS)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
 	form, err := c.MultipartForm()
 
 	if err != nil {
@@ -77,6 +96,27 @@ func (a *TransactionPicturesApi) TransactionPictureUploadHandler(c *core.WebCont
 		return nil, errs.ErrOperationFailed
 	}
 
+	defer pictureFile.Close()
+
+	pictureData, err := io.ReadAll(io.LimitReader(pictureFile, int64(a.CurrentConfig().MaxTransactionPictureFileSize)+1))
+
+	if err != nil {
+		log.Errorf(c, "[transaction_pictures.TransactionPictureUploadHandler] failed to read transaction picture file from request for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	if len(pictureData) > int(a.CurrentConfig().MaxTransactionPictureFileSize) {
+		log.Warnf(c, "[transaction_pictures.TransactionPictureUploadHandler] the upload file size exceeds the maximum size \"%d\" of transaction picture for user \"uid:%d\"", a.CurrentConfig().MaxTransactionPictureFileSize, uid)
+		return nil, errs.ErrExceedMaxTransactionPictureFileSize
+	}
+
+	pictureData, fileExtension, err = a.processPictureData(pictureData, fileExtension)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_pictures.TransactionPictureUploadHandler] failed to process transaction picture for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrImageTypeNotSupported)
+	}
+
 	pictureInfo := a.createNewPictureInfoModel(uid, fileExtension, c.ClientIP())
 
 	clientSessionIds := form.Value["clientSessionId"]
@@ -108,7 +148,19 @@ func (a *TransactionPicturesApi) TransactionPictureUploadHandler(c *core.WebCont
 		}
 	}
 
-	err = a.pictures.UploadPicture(c, pictureInfo, pictureFile)
+	errResult = a.CheckUserStorageQuota(c, uid, int64(len(pictureData)))
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	var thumbnailDatas map[models.TransactionPictureSize][]byte
+
+	if a.CurrentConfig().EnableTransactionPictureThumbnails {
+		thumbnailDatas = a.pictures.CreatePictureThumbnails(c, pictureData)
+	}
+
+	err = a.pictures.UploadPicture(c, pictureInfo, pictureData, thumbnailDatas)
 
 	if err != nil {
 		log.Errorf(c, "[transaction_pictures.TransactionPictureUploadHandler] failed to update transaction picture for user \"uid:%d\", because %s", uid, err.Error())
@@ -123,14 +175,25 @@ func (a *TransactionPicturesApi) TransactionPictureUploadHandler(c *core.WebCont
 
 // TransactionPictureGetHandler returns transaction picture data for current user
 func (a *TransactionPicturesApi) TransactionPictureGetHandler(c *core.WebContext) ([]byte, string, *errs.Error) {
+	var pictureGetReq models.TransactionPictureGetRequest
+	err := c.ShouldBindQuery(&pictureGetReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_pictures.TransactionPictureGetHandler] parse request failed, because %s", err.Error())
+		return nil, "", errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
 	fileName := c.Param("fileName")
 	fileExtension := utils.GetFileNameExtension(fileName)
-	contentType := utils.GetImageContentType(fileExtension)
 
-	if contentType == "" {
+	if utils.GetImageContentType(fileExtension) == "" {
 		return nil, "", errs.ErrImageTypeNotSupported
 	}
 
+	if !a.CurrentConfig().EnableTransactionPictureThumbnails {
+		pictureGetReq.Size = models.TRANSACTION_PICTURE_SIZE_ORIGINAL
+	}
+
 	fileBaseName := utils.GetFileNameWithoutExtension(fileName)
 	pictureId, err := utils.StringToInt64(fileBaseName)
 
@@ -138,12 +201,17 @@ func (a *TransactionPicturesApi) TransactionPictureGetHandler(c *core.WebContext
 		return nil, "", errs.ErrTransactionPictureIdInvalid
 	}
 
-	uid := c.GetCurrentUid()
-	pictureData, err := a.pictures.GetPictureByPictureId(c, uid, pictureId, fileExtension)
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_VIEW_BASIC_DATA)
+
+	if errResult != nil {
+		return nil, "", errResult
+	}
+
+	pictureData, contentType, err := a.pictures.GetPictureByPictureId(c, uid, pictureId, fileExtension, pictureGetReq.Size)
 
 	if err != nil {
 		if !errs.IsCustomError(err) {
-			log.Errorf(c, "[transaction_pictures.TransactionPictureUploadHandler] failed to get transaction picture, because %s", err.Error())
+			log.Errorf(c, "[transaction_pictures.TransactionPictureGetHandler] failed to get transaction picture, because %s", err.Error())
 		}
 
 		return nil, "", errs.Or(err, errs.ErrOperationFailed)
@@ -162,7 +230,12 @@ func (a *TransactionPicturesApi) TransactionPictureRemoveUnusedHandler(c *core.W
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_CREATE_TRANSACTIONS)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
 	err = a.pictures.RemoveUnusedTransactionPicture(c, uid, pictureDeleteReq.Id)
 
 	if err != nil {
@@ -173,6 +246,25 @@ func (a *TransactionPicturesApi) TransactionPictureRemoveUnusedHandler(c *core.W
 	return true, nil
 }
 
+func (a *TransactionPicturesApi) processPictureData(pictureData []byte, fileExtension string) ([]byte, string, error) {
+	// webp pictures cannot be decoded, so they are always stored in the original format
+	if a.CurrentConfig().TransactionPictureConvertFormat == settings.TRANSACTION_PICTURE_CONVERT_FORMAT_JPEG && fileExtension != "jpg" && fileExtension != "jpeg" && thumbnails.IsDecodableImageFileExtension(fileExtension) {
+		jpegData, err := thumbnails.ConvertImageDataToJpeg(pictureData, convertedTransactionPictureJpegQuality)
+
+		if err != nil {
+			return nil, "", err
+		}
+
+		return jpegData, "jpg", nil
+	}
+
+	if a.CurrentConfig().StripTransactionPictureGpsMetadata {
+		return thumbnails.StripGpsMetadata(pictureData), fileExtension, nil
+	}
+
+	return pictureData, fileExtension, nil
+}
+
 func (a *TransactionPicturesApi) createNewPictureInfoModel(uid int64, fileExtension string, clientIp string) *models.TransactionPictureInfo {
 	return &models.TransactionPictureInfo{
 		Uid:              uid,
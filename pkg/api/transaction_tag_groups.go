@@ -0,0 +1,355 @@
+package api
+
+import (
+	"sort"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// TransactionTagGroupsApi represents transaction tag group api
+type TransactionTagGroupsApi struct {
+	ApiUsingLedgerAccess
+	tagGroups    *services.TransactionTagGroupService
+	tags         *services.TransactionTagService
+	transactions *services.TransactionService
+	accounts     *services.AccountService
+}
+
+// Initialize a transaction tag group api singleton instance
+var (
+	TransactionTagGroups = &TransactionTagGroupsApi{
+		ApiUsingLedgerAccess: ApiUsingLedgerAccess{
+			ledgerAccesses: services.LedgerAccesses,
+		},
+		tagGroups:    services.TransactionTagGroups,
+		tags:         services.TransactionTags,
+		transactions: services.Transactions,
+		accounts:     services.Accounts,
+	}
+)
+
+// TagGroupListHandler returns transaction tag group list of current user
+func (a *TransactionTagGroupsApi) TagGroupListHandler(c *core.WebContext) (any, *errs.Error) {
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_VIEW_BASIC_DATA)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	tagGroups, err := a.tagGroups.GetAllTagGroupsByUid(c, a.GetCurrentLedgerAccess(c))
+
+	if err != nil {
+		log.Errorf(c, "[transaction_tag_groups.TagGroupListHandler] failed to get tag groups for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	tagGroupResps := make(models.TransactionTagGroupInfoResponseSlice, len(tagGroups))
+
+	for i := 0; i < len(tagGroups); i++ {
+		tagGroupResps[i] = tagGroups[i].ToTransactionTagGroupInfoResponse()
+	}
+
+	sort.Sort(tagGroupResps)
+
+	return tagGroupResps, nil
+}
+
+// TagGroupGetHandler returns one specific transaction tag group of current user
+func (a *TransactionTagGroupsApi) TagGroupGetHandler(c *core.WebContext) (any, *errs.Error) {
+	var tagGroupGetReq models.TransactionTagGroupGetRequest
+	err := c.ShouldBindQuery(&tagGroupGetReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_tag_groups.TagGroupGetHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_VIEW_BASIC_DATA)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	tagGroup, errResult := a.getTagGroupInCurrentLedger(c, uid, tagGroupGetReq.Id)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	return tagGroup.ToTransactionTagGroupInfoResponse(), nil
+}
+
+// TagGroupCreateHandler saves a new transaction tag group by request parameters for current user
+func (a *TransactionTagGroupsApi) TagGroupCreateHandler(c *core.WebContext) (any, *errs.Error) {
+	var tagGroupCreateReq models.TransactionTagGroupCreateRequest
+	err := c.ShouldBindJSON(&tagGroupCreateReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_tag_groups.TagGroupCreateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_MANAGE_BASIC_DATA)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	maxOrderId, err := a.tagGroups.GetMaxDisplayOrder(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_tag_groups.TagGroupCreateHandler] failed to get max display order for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	tagGroup := &models.TransactionTagGroup{
+		Uid:          uid,
+		LedgerId:     c.GetCurrentLedgerId(),
+		Name:         tagGroupCreateReq.Name,
+		DisplayOrder: maxOrderId + 1,
+	}
+
+	err = a.tagGroups.CreateTagGroup(c, tagGroup)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_tag_groups.TagGroupCreateHandler] failed to create tag group \"id:%d\" for user \"uid:%d\", because %s", tagGroup.TagGroupId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transaction_tag_groups.TagGroupCreateHandler] user \"uid:%d\" has created a new tag group \"id:%d\" successfully", uid, tagGroup.TagGroupId)
+
+	return tagGroup.ToTransactionTagGroupInfoResponse(), nil
+}
+
+// TagGroupModifyHandler saves an existed transaction tag group by request parameters for current user
+func (a *TransactionTagGroupsApi) TagGroupModifyHandler(c *core.WebContext) (any, *errs.Error) {
+	var tagGroupModifyReq models.TransactionTagGroupModifyRequest
+	err := c.ShouldBindJSON(&tagGroupModifyReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_tag_groups.TagGroupModifyHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_MANAGE_BASIC_DATA)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	tagGroup, errResult := a.getTagGroupInCurrentLedger(c, uid, tagGroupModifyReq.Id)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	if tagGroup.Name == tagGroupModifyReq.Name {
+		return nil, errs.ErrNothingWillBeUpdated
+	}
+
+	newTagGroup := &models.TransactionTagGroup{
+		TagGroupId: tagGroup.TagGroupId,
+		Uid:        uid,
+		LedgerId:   tagGroup.LedgerId,
+		Name:       tagGroupModifyReq.Name,
+	}
+
+	err = a.tagGroups.ModifyTagGroup(c, newTagGroup)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_tag_groups.TagGroupModifyHandler] failed to update tag group \"id:%d\" for user \"uid:%d\", because %s", tagGroupModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transaction_tag_groups.TagGroupModifyHandler] user \"uid:%d\" has updated tag group \"id:%d\" successfully", uid, tagGroupModifyReq.Id)
+
+	tagGroup.Name = newTagGroup.Name
+
+	return tagGroup.ToTransactionTagGroupInfoResponse(), nil
+}
+
+// TagGroupMoveHandler moves display order of existed transaction tag groups by request parameters for current user
+func (a *TransactionTagGroupsApi) TagGroupMoveHandler(c *core.WebContext) (any, *errs.Error) {
+	var tagGroupMoveReq models.TransactionTagGroupMoveRequest
+	err := c.ShouldBindJSON(&tagGroupMoveReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_tag_groups.TagGroupMoveHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_MANAGE_BASIC_DATA)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	tagGroups := make([]*models.TransactionTagGroup, len(tagGroupMoveReq.NewDisplayOrders))
+
+	for i := 0; i < len(tagGroupMoveReq.NewDisplayOrders); i++ {
+		newDisplayOrder := tagGroupMoveReq.NewDisplayOrders[i]
+
+		if _, errResult := a.getTagGroupInCurrentLedger(c, uid, newDisplayOrder.Id); errResult != nil {
+			return nil, errResult
+		}
+
+		tagGroups[i] = &models.TransactionTagGroup{
+			Uid:          uid,
+			TagGroupId:   newDisplayOrder.Id,
+			DisplayOrder: newDisplayOrder.DisplayOrder,
+		}
+	}
+
+	err = a.tagGroups.ModifyTagGroupDisplayOrders(c, uid, tagGroups)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_tag_groups.TagGroupMoveHandler] failed to move tag groups for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transaction_tag_groups.TagGroupMoveHandler] user \"uid:%d\" has moved tag groups", uid)
+	return true, nil
+}
+
+// TagGroupDeleteHandler deletes an existed transaction tag group by request parameters for current user
+func (a *TransactionTagGroupsApi) TagGroupDeleteHandler(c *core.WebContext) (any, *errs.Error) {
+	var tagGroupDeleteReq models.TransactionTagGroupDeleteRequest
+	err := c.ShouldBindJSON(&tagGroupDeleteReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_tag_groups.TagGroupDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_MANAGE_BASIC_DATA)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	if _, errResult := a.getTagGroupInCurrentLedger(c, uid, tagGroupDeleteReq.Id); errResult != nil {
+		return nil, errResult
+	}
+
+	err = a.tagGroups.DeleteTagGroup(c, uid, tagGroupDeleteReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_tag_groups.TagGroupDeleteHandler] failed to delete tag group \"id:%d\" for user \"uid:%d\", because %s", tagGroupDeleteReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transaction_tag_groups.TagGroupDeleteHandler] user \"uid:%d\" has deleted tag group \"id:%d\"", uid, tagGroupDeleteReq.Id)
+	return true, nil
+}
+
+// TagGroupStatisticHandler returns the income and expense amounts of every tag in one specific transaction tag group of current user
+func (a *TransactionTagGroupsApi) TagGroupStatisticHandler(c *core.WebContext) (any, *errs.Error) {
+	var statisticReq models.TransactionTagGroupStatisticRequest
+	err := c.ShouldBindQuery(&statisticReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_tag_groups.TagGroupStatisticHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_VIEW_TRANSACTIONS)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	tagGroup, errResult := a.getTagGroupInCurrentLedger(c, uid, statisticReq.Id)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	tagGroupLedgerAccess := a.GetCurrentLedgerAccess(c)
+	tagGroupLedgerAccess.LedgerId = tagGroup.LedgerId
+	tags, err := a.tags.GetAllTagsByUid(c, tagGroupLedgerAccess)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_tag_groups.TagGroupStatisticHandler] failed to get tags for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	tagIds := make([]int64, 0)
+
+	for i := 0; i < len(tags); i++ {
+		if tags[i].TagGroupId == tagGroup.TagGroupId {
+			tagIds = append(tagIds, tags[i].TagId)
+		}
+	}
+
+	var maxTransactionTime int64 = 0
+	var minTransactionTime int64 = 0
+
+	if statisticReq.EndTime > 0 {
+		maxTransactionTime = utils.GetMaxTransactionTimeFromUnixTime(statisticReq.EndTime)
+	}
+
+	if statisticReq.StartTime > 0 {
+		minTransactionTime = utils.GetMinTransactionTimeFromUnixTime(statisticReq.StartTime)
+	}
+
+	tagIndexes, err := a.tags.GetTagIndexesByTagIds(c, uid, tagIds, maxTransactionTime, minTransactionTime)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_tag_groups.TagGroupStatisticHandler] failed to get tag indexes of tag group \"id:%d\" for user \"uid:%d\", because %s", tagGroup.TagGroupId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	transactionIds := make([]int64, len(tagIndexes))
+
+	for i := 0; i < len(tagIndexes); i++ {
+		transactionIds[i] = tagIndexes[i].TransactionId
+	}
+
+	transactions, err := a.transactions.GetTransactionsByTransactionIds(c, uid, utils.ToUniqueInt64Slice(transactionIds))
+
+	if err != nil {
+		log.Errorf(c, "[transaction_tag_groups.TagGroupStatisticHandler] failed to get transactions of tag group \"id:%d\" for user \"uid:%d\", because %s", tagGroup.TagGroupId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	accountIds := make([]int64, len(transactions))
+
+	for i := 0; i < len(transactions); i++ {
+		accountIds[i] = transactions[i].AccountId
+	}
+
+	accountMap := make(map[int64]*models.Account)
+
+	if len(accountIds) > 0 {
+		accountMap, err = a.accounts.GetAccountsByAccountIds(c, uid, utils.ToUniqueInt64Slice(accountIds))
+
+		if err != nil {
+			log.Errorf(c, "[transaction_tag_groups.TagGroupStatisticHandler] failed to get accounts for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+	}
+
+	return a.tagGroups.GetTagGroupStatistic(tagGroup, tagIndexes, a.transactions.GetTransactionMapByList(transactions), accountMap), nil
+}
+
+func (a *TransactionTagGroupsApi) getTagGroupInCurrentLedger(c *core.WebContext, uid int64, tagGroupId int64) (*models.TransactionTagGroup, *errs.Error) {
+	tagGroup, err := a.tagGroups.GetTagGroupByTagGroupId(c, uid, tagGroupId)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_tag_groups.getTagGroupInCurrentLedger] failed to get tag group \"id:%d\" for user \"uid:%d\", because %s", tagGroupId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	errResult := a.CheckDataInCurrentLedger(c, tagGroup.LedgerId)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	return tagGroup, nil
+}
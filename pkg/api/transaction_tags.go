@@ -12,20 +12,31 @@ import (
 
 // TransactionTagsApi represents transaction tag api
 type TransactionTagsApi struct {
-	tags *services.TransactionTagService
+	ApiUsingLedgerAccess
+	tags      *services.TransactionTagService
+	tagGroups *services.TransactionTagGroupService
 }
 
 // Initialize a transaction tag api singleton instance
 var (
 	TransactionTags = &TransactionTagsApi{
-		tags: services.TransactionTags,
+		ApiUsingLedgerAccess: ApiUsingLedgerAccess{
+			ledgerAccesses: services.LedgerAccesses,
+		},
+		tags:      services.TransactionTags,
+		tagGroups: services.TransactionTagGroups,
 	}
 )
 
 // TagListHandler returns transaction tag list of current user
 func (a *TransactionTagsApi) TagListHandler(c *core.WebContext) (any, *errs.Error) {
-	uid := c.GetCurrentUid()
-	tags, err := a.tags.GetAllTagsByUid(c, uid)
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_VIEW_BASIC_DATA)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	tags, err := a.tags.GetAllTagsByUid(c, a.GetCurrentLedgerAccess(c))
 
 	if err != nil {
 		log.Errorf(c, "[transaction_tags.TagListHandler] failed to get tags for user \"uid:%d\", because %s", uid, err.Error())
@@ -53,7 +64,12 @@ func (a *TransactionTagsApi) TagGetHandler(c *core.WebContext) (any, *errs.Error
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_VIEW_BASIC_DATA)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
 	tag, err := a.tags.GetTagByTagId(c, uid, tagGetReq.Id)
 
 	if err != nil {
@@ -61,6 +77,12 @@ func (a *TransactionTagsApi) TagGetHandler(c *core.WebContext) (any, *errs.Error
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
+	errResult = a.CheckDataInCurrentLedger(c, tag.LedgerId)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
 	tagResp := tag.ToTransactionTagInfoResponse()
 
 	return tagResp, nil
@@ -76,7 +98,11 @@ func (a *TransactionTagsApi) TagCreateHandler(c *core.WebContext) (any, *errs.Er
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_MANAGE_BASIC_DATA)
+
+	if errResult != nil {
+		return nil, errResult
+	}
 
 	maxOrderId, err := a.tags.GetMaxDisplayOrder(c, uid)
 
@@ -85,7 +111,12 @@ func (a *TransactionTagsApi) TagCreateHandler(c *core.WebContext) (any, *errs.Er
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
-	tag := a.createNewTagModel(uid, &tagCreateReq, maxOrderId+1)
+	tag := a.createNewTagModel(uid, c.GetCurrentLedgerId(), &tagCreateReq, maxOrderId+1)
+	tag.TagGroupId, errResult = a.getTagGroupIdOfTag(c, uid, tag.LedgerId, 0, tagCreateReq.TagGroupId, tagCreateReq.ParentTagId)
+
+	if errResult != nil {
+		return nil, errResult
+	}
 
 	err = a.tags.CreateTag(c, tag)
 
@@ -111,7 +142,11 @@ func (a *TransactionTagsApi) TagCreateBatchHandler(c *core.WebContext) (any, *er
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_MANAGE_BASIC_DATA)
+
+	if errResult != nil {
+		return nil, errResult
+	}
 
 	maxOrderId, err := a.tags.GetMaxDisplayOrder(c, uid)
 
@@ -120,7 +155,22 @@ func (a *TransactionTagsApi) TagCreateBatchHandler(c *core.WebContext) (any, *er
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
-	tags := a.createNewTagModels(uid, &tagCreateBatchReq, maxOrderId+1)
+	tags := a.createNewTagModels(uid, c.GetCurrentLedgerId(), &tagCreateBatchReq, maxOrderId+1)
+
+	for i := 0; i < len(tags); i++ {
+		tagCreateReq := tagCreateBatchReq.Tags[i]
+		tagGroupId := tagCreateReq.TagGroupId
+
+		if tagGroupId == 0 && tagCreateReq.ParentTagId == 0 {
+			tagGroupId = tagCreateBatchReq.TagGroupId
+		}
+
+		tags[i].TagGroupId, errResult = a.getTagGroupIdOfTag(c, uid, tags[i].LedgerId, 0, tagGroupId, tagCreateReq.ParentTagId)
+
+		if errResult != nil {
+			return nil, errResult
+		}
+	}
 
 	err = a.tags.CreateTags(c, uid, tags, tagCreateBatchReq.SkipExists)
 
@@ -152,7 +202,12 @@ func (a *TransactionTagsApi) TagModifyHandler(c *core.WebContext) (any, *errs.Er
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_MANAGE_BASIC_DATA)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
 	tag, err := a.tags.GetTagByTagId(c, uid, tagModifyReq.Id)
 
 	if err != nil {
@@ -160,16 +215,41 @@ func (a *TransactionTagsApi) TagModifyHandler(c *core.WebContext) (any, *errs.Er
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
+	errResult = a.CheckDataInCurrentLedger(c, tag.LedgerId)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
 	newTag := &models.TransactionTag{
-		TagId: tag.TagId,
-		Uid:   uid,
-		Name:  tagModifyReq.Name,
+		TagId:       tag.TagId,
+		Uid:         uid,
+		LedgerId:    tag.LedgerId,
+		ParentTagId: tagModifyReq.ParentTagId,
+		Name:        tagModifyReq.Name,
 	}
 
-	if newTag.Name == tag.Name {
+	newTag.TagGroupId, errResult = a.getTagGroupIdOfTag(c, uid, tag.LedgerId, tag.TagId, tagModifyReq.TagGroupId, tagModifyReq.ParentTagId)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	if newTag.Name == tag.Name && newTag.TagGroupId == tag.TagGroupId && newTag.ParentTagId == tag.ParentTagId {
 		return nil, errs.ErrNothingWillBeUpdated
 	}
 
+	if newTag.ParentTagId > 0 && newTag.ParentTagId != tag.ParentTagId {
+		hasChildTags, err := a.tags.ExistsChildTags(c, uid, tag.TagId)
+
+		if err != nil {
+			log.Errorf(c, "[transaction_tags.TagModifyHandler] failed to check whether tag \"id:%d\" has child tags for user \"uid:%d\", because %s", tag.TagId, uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		} else if hasChildTags {
+			return nil, errs.ErrTransactionTagHasChildTags
+		}
+	}
+
 	err = a.tags.ModifyTag(c, newTag)
 
 	if err != nil {
@@ -180,6 +260,8 @@ func (a *TransactionTagsApi) TagModifyHandler(c *core.WebContext) (any, *errs.Er
 	log.Infof(c, "[transaction_tags.TagModifyHandler] user \"uid:%d\" has updated tag \"id:%d\" successfully", uid, tagModifyReq.Id)
 
 	tag.Name = newTag.Name
+	tag.TagGroupId = newTag.TagGroupId
+	tag.ParentTagId = newTag.ParentTagId
 	tagResp := tag.ToTransactionTagInfoResponse()
 
 	return tagResp, nil
@@ -195,7 +277,18 @@ func (a *TransactionTagsApi) TagHideHandler(c *core.WebContext) (any, *errs.Erro
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_MANAGE_BASIC_DATA)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	errResult = a.checkTagInCurrentLedger(c, uid, tagHideReq.Id)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
 	err = a.tags.HideTag(c, uid, []int64{tagHideReq.Id}, tagHideReq.Hidden)
 
 	if err != nil {
@@ -217,7 +310,12 @@ func (a *TransactionTagsApi) TagMoveHandler(c *core.WebContext) (any, *errs.Erro
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_MANAGE_BASIC_DATA)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
 	tags := make([]*models.TransactionTag, len(tagMoveReq.NewDisplayOrders))
 
 	for i := 0; i < len(tagMoveReq.NewDisplayOrders); i++ {
@@ -252,7 +350,18 @@ func (a *TransactionTagsApi) TagDeleteHandler(c *core.WebContext) (any, *errs.Er
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_MANAGE_BASIC_DATA)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	errResult = a.checkTagInCurrentLedger(c, uid, tagDeleteReq.Id)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
 	err = a.tags.DeleteTag(c, uid, tagDeleteReq.Id)
 
 	if err != nil {
@@ -264,20 +373,135 @@ func (a *TransactionTagsApi) TagDeleteHandler(c *core.WebContext) (any, *errs.Er
 	return true, nil
 }
 
-func (a *TransactionTagsApi) createNewTagModel(uid int64, tagCreateReq *models.TransactionTagCreateRequest, order int32) *models.TransactionTag {
+// TagMergeHandler merges an existed transaction tag into another one by request parameters for current user
+func (a *TransactionTagsApi) TagMergeHandler(c *core.WebContext) (any, *errs.Error) {
+	var tagMergeReq models.TransactionTagMergeRequest
+	err := c.ShouldBindJSON(&tagMergeReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_tags.TagMergeHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	if tagMergeReq.FromTagId == tagMergeReq.ToTagId {
+		return nil, errs.ErrCannotMergeTransactionTagIntoItself
+	}
+
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_MANAGE_BASIC_DATA)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	tagMap, err := a.tags.GetTagsByTagIds(c, uid, []int64{tagMergeReq.FromTagId, tagMergeReq.ToTagId})
+
+	if err != nil {
+		log.Errorf(c, "[transaction_tags.TagMergeHandler] failed to get tags for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	fromTag, exists := tagMap[tagMergeReq.FromTagId]
+
+	if !exists {
+		return nil, errs.ErrTransactionTagNotFound
+	}
+
+	toTag, exists := tagMap[tagMergeReq.ToTagId]
+
+	if !exists {
+		return nil, errs.ErrTransactionTagNotFound
+	}
+
+	if fromTag.LedgerId != toTag.LedgerId {
+		return nil, errs.ErrCannotUseDataInDifferentLedger
+	}
+
+	errResult = a.CheckDataInCurrentLedger(c, fromTag.LedgerId)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	err = a.tags.MergeTag(c, uid, tagMergeReq.FromTagId, tagMergeReq.ToTagId)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_tags.TagMergeHandler] failed to merge tag \"id:%d\" into tag \"id:%d\" for user \"uid:%d\", because %s", tagMergeReq.FromTagId, tagMergeReq.ToTagId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transaction_tags.TagMergeHandler] user \"uid:%d\" has merged tag \"id:%d\" into tag \"id:%d\"", uid, tagMergeReq.FromTagId, tagMergeReq.ToTagId)
+	return true, nil
+}
+
+func (a *TransactionTagsApi) checkTagInCurrentLedger(c *core.WebContext, uid int64, tagId int64) *errs.Error {
+	if a.GetCurrentLedgerAccess(c).IsOwner() {
+		return nil
+	}
+
+	tag, err := a.tags.GetTagByTagId(c, uid, tagId)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_tags.checkTagInCurrentLedger] failed to get tag \"id:%d\" for user \"uid:%d\", because %s", tagId, uid, err.Error())
+		return errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return a.CheckDataInCurrentLedger(c, tag.LedgerId)
+}
+
+// getTagGroupIdOfTag checks the tag group and parent tag of the tag, and returns the final tag group id,
+// the child tag is always in the same tag group as its parent tag, and only one level of child tags is supported
+func (a *TransactionTagsApi) getTagGroupIdOfTag(c *core.WebContext, uid int64, ledgerId int64, tagId int64, tagGroupId int64, parentTagId int64) (int64, *errs.Error) {
+	if parentTagId > 0 {
+		parentTag, err := a.tags.GetTagByTagId(c, uid, parentTagId)
+
+		if err != nil {
+			log.Errorf(c, "[transaction_tags.getTagGroupIdOfTag] failed to get parent tag \"id:%d\" for user \"uid:%d\", because %s", parentTagId, uid, err.Error())
+			return 0, errs.Or(err, errs.ErrOperationFailed)
+		}
+
+		if parentTag.LedgerId != ledgerId {
+			return 0, errs.ErrCannotUseDataInDifferentLedger
+		}
+
+		if parentTag.TagId == tagId || parentTag.ParentTagId > 0 || (tagGroupId > 0 && tagGroupId != parentTag.TagGroupId) {
+			return 0, errs.ErrParentTransactionTagInvalid
+		}
+
+		return parentTag.TagGroupId, nil
+	}
+
+	if tagGroupId > 0 {
+		tagGroup, err := a.tagGroups.GetTagGroupByTagGroupId(c, uid, tagGroupId)
+
+		if err != nil {
+			log.Errorf(c, "[transaction_tags.getTagGroupIdOfTag] failed to get tag group \"id:%d\" for user \"uid:%d\", because %s", tagGroupId, uid, err.Error())
+			return 0, errs.Or(err, errs.ErrOperationFailed)
+		}
+
+		if tagGroup.LedgerId != ledgerId {
+			return 0, errs.ErrCannotUseDataInDifferentLedger
+		}
+	}
+
+	return tagGroupId, nil
+}
+
+func (a *TransactionTagsApi) createNewTagModel(uid int64, ledgerId int64, tagCreateReq *models.TransactionTagCreateRequest, order int32) *models.TransactionTag {
 	return &models.TransactionTag{
 		Uid:          uid,
+		LedgerId:     ledgerId,
+		ParentTagId:  tagCreateReq.ParentTagId,
 		Name:         tagCreateReq.Name,
 		DisplayOrder: order,
 	}
 }
 
-func (a *TransactionTagsApi) createNewTagModels(uid int64, tagCreateBatchReq *models.TransactionTagCreateBatchRequest, order int32) []*models.TransactionTag {
+func (a *TransactionTagsApi) createNewTagModels(uid int64, ledgerId int64, tagCreateBatchReq *models.TransactionTagCreateBatchRequest, order int32) []*models.TransactionTag {
 	tags := make([]*models.TransactionTag, len(tagCreateBatchReq.Tags))
 
 	for i := 0; i < len(tagCreateBatchReq.Tags); i++ {
 		tagCreateReq := tagCreateBatchReq.Tags[i]
-		tag := a.createNewTagModel(uid, tagCreateReq, order+int32(i))
+		tag := a.createNewTagModel(uid, ledgerId, tagCreateReq, order+int32(i))
 		tags[i] = tag
 	}
 
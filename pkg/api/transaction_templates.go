@@ -21,6 +21,7 @@ const maximumTagsCountOfTemplate = 10
 type TransactionTemplatesApi struct {
 	ApiUsingConfig
 	ApiUsingDuplicateChecker
+	ApiUsingLedgerAccess
 	templates *services.TransactionTemplateService
 }
 
@@ -36,6 +37,9 @@ var (
 			},
 			container: duplicatechecker.Container,
 		},
+		ApiUsingLedgerAccess: ApiUsingLedgerAccess{
+			ledgerAccesses: services.LedgerAccesses,
+		},
 		templates: services.TransactionTemplates,
 	}
 )
@@ -59,8 +63,13 @@ func (a *TransactionTemplatesApi) TemplateListHandler(c *core.WebContext) (any,
 		return nil, errs.ErrScheduledTransactionNotEnabled
 	}
 
-	uid := c.GetCurrentUid()
-	templates, err := a.templates.GetAllTemplatesByUid(c, uid, templateListReq.TemplateType)
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_VIEW_BASIC_DATA)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	templates, err := a.templates.GetAllTemplatesByUid(c, a.GetCurrentLedgerAccess(c), templateListReq.TemplateType)
 
 	if err != nil {
 		log.Errorf(c, "[transaction_templates.TemplateListHandler] failed to get templates for user \"uid:%d\", because %s", uid, err.Error())
@@ -89,7 +98,12 @@ func (a *TransactionTemplatesApi) TemplateGetHandler(c *core.WebContext) (any, *
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_VIEW_BASIC_DATA)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
 	template, err := a.templates.GetTemplateByTemplateId(c, uid, templateGetReq.Id)
 
 	if err != nil {
@@ -97,6 +111,12 @@ func (a *TransactionTemplatesApi) TemplateGetHandler(c *core.WebContext) (any, *
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
+	errResult = a.CheckDataInCurrentLedger(c, template.LedgerId)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
 	if template.TemplateType == models.TRANSACTION_TEMPLATE_TYPE_SCHEDULE && !a.CurrentConfig().EnableScheduledTransaction {
 		return nil, errs.ErrScheduledTransactionNotEnabled
 	}
@@ -149,7 +169,11 @@ func (a *TransactionTemplatesApi) TemplateCreateHandler(c *core.WebContext) (any
 		return nil, errs.ErrTransactionTemplateHasTooManyTags
 	}
 
-	uid := c.GetCurrentUid()
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_MANAGE_BASIC_DATA)
+
+	if errResult != nil {
+		return nil, errResult
+	}
 
 	maxOrderId, err := a.templates.GetMaxDisplayOrder(c, uid, templateCreateReq.TemplateType)
 
@@ -159,7 +183,7 @@ func (a *TransactionTemplatesApi) TemplateCreateHandler(c *core.WebContext) (any
 	}
 
 	serverUtcOffset := utils.GetServerTimezoneOffsetMinutes()
-	template, err := a.createNewTemplateModel(uid, &templateCreateReq, maxOrderId+1)
+	template, err := a.createNewTemplateModel(uid, c.GetCurrentLedgerId(), &templateCreateReq, maxOrderId+1)
 
 	if err != nil {
 		log.Errorf(c, "[transaction_templates.TemplateCreateHandler] failed to create new template for user \"uid:%d\", because %s", uid, err.Error())
@@ -218,7 +242,12 @@ func (a *TransactionTemplatesApi) TemplateModifyHandler(c *core.WebContext) (any
 		return nil, errs.ErrTransactionTypeInvalid
 	}
 
-	uid := c.GetCurrentUid()
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_MANAGE_BASIC_DATA)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
 	template, err := a.templates.GetTemplateByTemplateId(c, uid, templateModifyReq.Id)
 
 	if err != nil {
@@ -226,6 +255,12 @@ func (a *TransactionTemplatesApi) TemplateModifyHandler(c *core.WebContext) (any
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
+	errResult = a.CheckDataInCurrentLedger(c, template.LedgerId)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
 	if template.TemplateType == models.TRANSACTION_TEMPLATE_TYPE_SCHEDULE && !a.CurrentConfig().EnableScheduledTransaction {
 		return nil, errs.ErrScheduledTransactionNotEnabled
 	}
@@ -350,7 +385,11 @@ func (a *TransactionTemplatesApi) TemplateHideHandler(c *core.WebContext) (any,
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_MANAGE_BASIC_DATA)
+
+	if errResult != nil {
+		return nil, errResult
+	}
 
 	template, err := a.templates.GetTemplateByTemplateId(c, uid, templateHideReq.Id)
 
@@ -359,6 +398,12 @@ func (a *TransactionTemplatesApi) TemplateHideHandler(c *core.WebContext) (any,
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
+	errResult = a.CheckDataInCurrentLedger(c, template.LedgerId)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
 	if template.TemplateType == models.TRANSACTION_TEMPLATE_TYPE_SCHEDULE && !a.CurrentConfig().EnableScheduledTransaction {
 		return nil, errs.ErrScheduledTransactionNotEnabled
 	}
@@ -384,7 +429,11 @@ func (a *TransactionTemplatesApi) TemplateMoveHandler(c *core.WebContext) (any,
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_MANAGE_BASIC_DATA)
+
+	if errResult != nil {
+		return nil, errResult
+	}
 
 	if len(templateMoveReq.NewDisplayOrders) > 0 {
 		template, err := a.templates.GetTemplateByTemplateId(c, uid, templateMoveReq.NewDisplayOrders[0].Id)
@@ -433,7 +482,11 @@ func (a *TransactionTemplatesApi) TemplateDeleteHandler(c *core.WebContext) (any
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_MANAGE_BASIC_DATA)
+
+	if errResult != nil {
+		return nil, errResult
+	}
 
 	template, err := a.templates.GetTemplateByTemplateId(c, uid, templateDeleteReq.Id)
 
@@ -442,6 +495,12 @@ func (a *TransactionTemplatesApi) TemplateDeleteHandler(c *core.WebContext) (any
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
+	errResult = a.CheckDataInCurrentLedger(c, template.LedgerId)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
 	if template.TemplateType == models.TRANSACTION_TEMPLATE_TYPE_SCHEDULE && !a.CurrentConfig().EnableScheduledTransaction {
 		return nil, errs.ErrScheduledTransactionNotEnabled
 	}
@@ -457,9 +516,10 @@ func (a *TransactionTemplatesApi) TemplateDeleteHandler(c *core.WebContext) (any
 	return true, nil
 }
 
-func (a *TransactionTemplatesApi) createNewTemplateModel(uid int64, templateCreateReq *models.TransactionTemplateCreateRequest, order int32) (*models.TransactionTemplate, error) {
+func (a *TransactionTemplatesApi) createNewTemplateModel(uid int64, ledgerId int64, templateCreateReq *models.TransactionTemplateCreateRequest, order int32) (*models.TransactionTemplate, error) {
 	template := &models.TransactionTemplate{
 		Uid:                  uid,
+		LedgerId:             ledgerId,
 		TemplateType:         templateCreateReq.TemplateType,
 		Name:                 templateCreateReq.Name,
 		Type:                 templateCreateReq.Type,
@@ -30,12 +30,16 @@ const pageCountForAccountStatement = 1000
 type TransactionsApi struct {
 	ApiUsingConfig
 	ApiUsingDuplicateChecker
-	transactions          *services.TransactionService
-	transactionCategories *services.TransactionCategoryService
-	transactionTags       *services.TransactionTagService
-	transactionPictures   *services.TransactionPictureService
-	accounts              *services.AccountService
-	users                 *services.UserService
+	ApiUsingLedgerAccess
+	transactions            *services.TransactionService
+	transactionCategories   *services.TransactionCategoryService
+	transactionTags         *services.TransactionTagService
+	transactionPictures     *services.TransactionPictureService
+	transactionAttachments  *services.TransactionAttachmentService
+	transactionCustomFields *services.TransactionCustomFieldService
+	accounts                *services.AccountService
+	users                   *services.UserService
+	userQuotas              *services.UserQuotaService
 }
 
 // Initialize a transaction api singleton instance
@@ -50,12 +54,18 @@ var (
 			},
 			container: duplicatechecker.Container,
 		},
-		transactions:          services.Transactions,
-		transactionCategories: services.TransactionCategories,
-		transactionTags:       services.TransactionTags,
-		transactionPictures:   services.TransactionPictures,
-		accounts:              services.Accounts,
-		users:                 services.Users,
+		ApiUsingLedgerAccess: ApiUsingLedgerAccess{
+			ledgerAccesses: services.LedgerAccesses,
+		},
+		transactions:            services.Transactions,
+		transactionCategories:   services.TransactionCategories,
+		transactionTags:         services.TransactionTags,
+		transactionPictures:     services.TransactionPictures,
+		transactionAttachments:  services.TransactionAttachments,
+		transactionCustomFields: services.TransactionCustomFields,
+		accounts:                services.Accounts,
+		users:                   services.Users,
+		userQuotas:              services.UserQuotas,
 	}
 )
 
@@ -69,7 +79,11 @@ func (a *TransactionsApi) TransactionCountHandler(c *core.WebContext) (any, *err
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_VIEW_TRANSACTIONS)
+
+	if errResult != nil {
+		return nil, errResult
+	}
 
 	allAccountIds, err := a.accounts.GetAccountOrSubAccountIds(c, transactionCountReq.AccountIds, uid)
 
@@ -85,6 +99,13 @@ func (a *TransactionsApi) TransactionCountHandler(c *core.WebContext) (any, *err
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
+	customFieldFilters, err := a.getCustomFieldFilters(c, uid, transactionCountReq.CustomFieldFilter)
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionCountHandler] parse transaction custom field filters error, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrTransactionCustomFieldFilterInvalid)
+	}
+
 	noTags := transactionCountReq.TagFilter == models.TransactionNoTagFilterValue
 	var tagFilters []*models.TransactionTagFilter
 
@@ -97,7 +118,7 @@ func (a *TransactionsApi) TransactionCountHandler(c *core.WebContext) (any, *err
 		}
 	}
 
-	totalCount, err := a.transactions.GetTransactionCount(c, uid, transactionCountReq.MaxTime, transactionCountReq.MinTime, transactionCountReq.Type, allCategoryIds, allAccountIds, tagFilters, noTags, transactionCountReq.AmountFilter, transactionCountReq.Keyword)
+	totalCount, err := a.transactions.GetTransactionCount(c, a.GetCurrentLedgerAccess(c), transactionCountReq.MaxTime, transactionCountReq.MinTime, transactionCountReq.Type, allCategoryIds, allAccountIds, tagFilters, noTags, customFieldFilters, transactionCountReq.AmountFilter, transactionCountReq.Keyword)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionCountHandler] failed to get transaction count for user \"uid:%d\", because %s", uid, err.Error())
@@ -128,7 +149,12 @@ func (a *TransactionsApi) TransactionListHandler(c *core.WebContext) (any, *errs
 		return nil, errs.ErrClientTimezoneOffsetInvalid
 	}
 
-	uid := c.GetCurrentUid()
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_VIEW_TRANSACTIONS)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
 	user, err := a.users.GetUserById(c, uid)
 
 	if err != nil {
@@ -153,6 +179,13 @@ func (a *TransactionsApi) TransactionListHandler(c *core.WebContext) (any, *errs
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
+	customFieldFilters, err := a.getCustomFieldFilters(c, uid, transactionListReq.CustomFieldFilter)
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionListHandler] parse transaction custom field filters error, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrTransactionCustomFieldFilterInvalid)
+	}
+
 	noTags := transactionListReq.TagFilter == models.TransactionNoTagFilterValue
 	var tagFilters []*models.TransactionTagFilter
 
@@ -168,7 +201,7 @@ func (a *TransactionsApi) TransactionListHandler(c *core.WebContext) (any, *errs
 	var totalCount int64
 
 	if transactionListReq.WithCount {
-		totalCount, err = a.transactions.GetTransactionCount(c, uid, transactionListReq.MaxTime, transactionListReq.MinTime, transactionListReq.Type, allCategoryIds, allAccountIds, tagFilters, noTags, transactionListReq.AmountFilter, transactionListReq.Keyword)
+		totalCount, err = a.transactions.GetTransactionCount(c, a.GetCurrentLedgerAccess(c), transactionListReq.MaxTime, transactionListReq.MinTime, transactionListReq.Type, allCategoryIds, allAccountIds, tagFilters, noTags, customFieldFilters, transactionListReq.AmountFilter, transactionListReq.Keyword)
 
 		if err != nil {
 			log.Errorf(c, "[transactions.TransactionListHandler] failed to get transaction count for user \"uid:%d\", because %s", uid, err.Error())
@@ -176,7 +209,7 @@ func (a *TransactionsApi) TransactionListHandler(c *core.WebContext) (any, *errs
 		}
 	}
 
-	transactions, err := a.transactions.GetTransactionsByMaxTime(c, uid, transactionListReq.MaxTime, transactionListReq.MinTime, transactionListReq.Type, allCategoryIds, allAccountIds, tagFilters, noTags, transactionListReq.AmountFilter, transactionListReq.Keyword, transactionListReq.Page, transactionListReq.Count, true, true)
+	transactions, err := a.transactions.GetTransactionsByMaxTime(c, a.GetCurrentLedgerAccess(c), transactionListReq.MaxTime, transactionListReq.MinTime, transactionListReq.Type, allCategoryIds, allAccountIds, tagFilters, noTags, customFieldFilters, transactionListReq.AmountFilter, transactionListReq.Keyword, transactionListReq.Page, transactionListReq.Count, true, true)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionListHandler] failed to get transactions earlier than \"%d\" for user \"uid:%d\", because %s", transactionListReq.MaxTime, uid, err.Error())
@@ -231,7 +264,12 @@ func (a *TransactionsApi) TransactionMonthListHandler(c *core.WebContext) (any,
 		return nil, errs.ErrClientTimezoneOffsetInvalid
 	}
 
-	uid := c.GetCurrentUid()
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_VIEW_TRANSACTIONS)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
 	user, err := a.users.GetUserById(c, uid)
 
 	if err != nil {
@@ -256,6 +294,13 @@ func (a *TransactionsApi) TransactionMonthListHandler(c *core.WebContext) (any,
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
+	customFieldFilters, err := a.getCustomFieldFilters(c, uid, transactionListReq.CustomFieldFilter)
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionMonthListHandler] parse transaction custom field filters error, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrTransactionCustomFieldFilterInvalid)
+	}
+
 	noTags := transactionListReq.TagFilter == models.TransactionNoTagFilterValue
 	var tagFilters []*models.TransactionTagFilter
 
@@ -268,7 +313,7 @@ func (a *TransactionsApi) TransactionMonthListHandler(c *core.WebContext) (any,
 		}
 	}
 
-	transactions, err := a.transactions.GetTransactionsInMonthByPage(c, uid, transactionListReq.Year, transactionListReq.Month, transactionListReq.Type, allCategoryIds, allAccountIds, tagFilters, noTags, transactionListReq.AmountFilter, transactionListReq.Keyword)
+	transactions, err := a.transactions.GetTransactionsInMonthByPage(c, a.GetCurrentLedgerAccess(c), transactionListReq.Year, transactionListReq.Month, transactionListReq.Type, allCategoryIds, allAccountIds, tagFilters, noTags, customFieldFilters, transactionListReq.AmountFilter, transactionListReq.Keyword)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionMonthListHandler] failed to get transactions in month \"%d-%d\" for user \"uid:%d\", because %s", transactionListReq.Year, transactionListReq.Month, uid, err.Error())
@@ -307,7 +352,12 @@ func (a *TransactionsApi) TransactionListAllHandler(c *core.WebContext) (any, *e
 		return nil, errs.ErrClientTimezoneOffsetInvalid
 	}
 
-	uid := c.GetCurrentUid()
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_VIEW_TRANSACTIONS)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
 	user, err := a.users.GetUserById(c, uid)
 
 	if err != nil {
@@ -332,6 +382,13 @@ func (a *TransactionsApi) TransactionListAllHandler(c *core.WebContext) (any, *e
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
+	customFieldFilters, err := a.getCustomFieldFilters(c, uid, transactionAllListReq.CustomFieldFilter)
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionListAllHandler] parse transaction custom field filters error, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrTransactionCustomFieldFilterInvalid)
+	}
+
 	noTags := transactionAllListReq.TagFilter == models.TransactionNoTagFilterValue
 	var tagFilters []*models.TransactionTagFilter
 
@@ -355,7 +412,7 @@ func (a *TransactionsApi) TransactionListAllHandler(c *core.WebContext) (any, *e
 		minTransactionTime = utils.GetMinTransactionTimeFromUnixTime(transactionAllListReq.StartTime)
 	}
 
-	allTransactions, err := a.transactions.GetAllSpecifiedTransactions(c, uid, maxTransactionTime, minTransactionTime, transactionAllListReq.Type, allCategoryIds, allAccountIds, tagFilters, noTags, transactionAllListReq.AmountFilter, transactionAllListReq.Keyword, pageCountForDataExport, true)
+	allTransactions, err := a.transactions.GetAllSpecifiedTransactions(c, a.GetCurrentLedgerAccess(c), maxTransactionTime, minTransactionTime, transactionAllListReq.Type, allCategoryIds, allAccountIds, tagFilters, noTags, customFieldFilters, transactionAllListReq.AmountFilter, transactionAllListReq.Keyword, pageCountForDataExport, true)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionListAllHandler] failed to get all transactions for user \"uid:%d\", because %s", uid, err.Error())
@@ -389,7 +446,12 @@ func (a *TransactionsApi) TransactionReconciliationStatementHandler(c *core.WebC
 		return nil, errs.ErrClientTimezoneOffsetInvalid
 	}
 
-	uid := c.GetCurrentUid()
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_VIEW_TRANSACTIONS)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
 	user, err := a.users.GetUserById(c, uid)
 
 	if err != nil {
@@ -407,6 +469,12 @@ func (a *TransactionsApi) TransactionReconciliationStatementHandler(c *core.WebC
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
+	errResult = a.CheckDataInCurrentLedger(c, account.LedgerId)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
 	if account.Type != models.ACCOUNT_TYPE_SINGLE_ACCOUNT {
 		log.Errorf(c, "[transactions.TransactionReconciliationStatementHandler] account \"id:%d\" for user \"uid:%d\" is not a single account", reconciliationStatementRequest.AccountId, uid)
 		return nil, errs.ErrAccountTypeInvalid
@@ -509,8 +577,13 @@ func (a *TransactionsApi) TransactionStatisticsHandler(c *core.WebContext) (any,
 		}
 	}
 
-	uid := c.GetCurrentUid()
-	totalAmounts, err := a.transactions.GetAccountsAndCategoriesTotalInflowAndOutflow(c, uid, statisticReq.StartTime, statisticReq.EndTime, tagFilters, noTags, statisticReq.Keyword, clientTimezone, statisticReq.UseTransactionTimezone)
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_VIEW_TRANSACTIONS)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	totalAmounts, err := a.transactions.GetAccountsAndCategoriesTotalInflowAndOutflow(c, a.GetCurrentLedgerAccess(c), statisticReq.StartTime, statisticReq.EndTime, tagFilters, noTags, statisticReq.Keyword, clientTimezone, statisticReq.UseTransactionTimezone)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionStatisticsHandler] failed to get accounts and categories total income and expense for user \"uid:%d\", because %s", uid, err.Error())
@@ -577,8 +650,13 @@ func (a *TransactionsApi) TransactionStatisticsTrendsHandler(c *core.WebContext)
 		}
 	}
 
-	uid := c.GetCurrentUid()
-	allMonthlyTotalAmounts, err := a.transactions.GetAccountsAndCategoriesMonthlyInflowAndOutflow(c, uid, startYear, startMonth, endYear, endMonth, tagFilters, noTags, statisticTrendsReq.Keyword, clientTimezone, statisticTrendsReq.UseTransactionTimezone)
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_VIEW_TRANSACTIONS)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	allMonthlyTotalAmounts, err := a.transactions.GetAccountsAndCategoriesMonthlyInflowAndOutflow(c, a.GetCurrentLedgerAccess(c), startYear, startMonth, endYear, endMonth, tagFilters, noTags, statisticTrendsReq.Keyword, clientTimezone, statisticTrendsReq.UseTransactionTimezone)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionStatisticsTrendsHandler] failed to get accounts and categories total income and expense for user \"uid:%d\", because %s", uid, err.Error())
@@ -633,7 +711,11 @@ func (a *TransactionsApi) TransactionStatisticsAssetTrendsHandler(c *core.WebCon
 		return nil, errs.ErrClientTimezoneOffsetInvalid
 	}
 
-	uid := c.GetCurrentUid()
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_VIEW_TRANSACTIONS)
+
+	if errResult != nil {
+		return nil, errResult
+	}
 
 	maxTransactionTime := int64(0)
 
@@ -647,7 +729,7 @@ func (a *TransactionsApi) TransactionStatisticsAssetTrendsHandler(c *core.WebCon
 		minTransactionTime = utils.GetMinTransactionTimeFromUnixTime(statisticAssetTrendsReq.StartTime)
 	}
 
-	accountDailyBalances, err := a.transactions.GetAllAccountsDailyOpeningAndClosingBalance(c, uid, maxTransactionTime, minTransactionTime, clientTimezone)
+	accountDailyBalances, err := a.transactions.GetAllAccountsDailyOpeningAndClosingBalance(c, a.GetCurrentLedgerAccess(c), maxTransactionTime, minTransactionTime, clientTimezone)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionStatisticsAssetTrendsHandler] failed to get transactions from \"%d\" to \"%d\" for user \"uid:%d\", because %s", statisticAssetTrendsReq.StartTime, statisticAssetTrendsReq.EndTime, uid, err.Error())
@@ -734,9 +816,13 @@ func (a *TransactionsApi) TransactionAmountsHandler(c *core.WebContext) (any, *e
 		return nil, errs.ErrClientTimezoneOffsetInvalid
 	}
 
-	uid := c.GetCurrentUid()
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_VIEW_TRANSACTIONS)
+
+	if errResult != nil {
+		return nil, errResult
+	}
 
-	accounts, err := a.accounts.GetAllAccountsByUid(c, uid)
+	accounts, err := a.accounts.GetAllAccountsByUid(c, a.GetCurrentLedgerAccess(c))
 	accountMap := a.accounts.GetAccountMapByList(accounts)
 
 	if err != nil {
@@ -749,7 +835,7 @@ func (a *TransactionsApi) TransactionAmountsHandler(c *core.WebContext) (any, *e
 	for i := 0; i < len(requestItems); i++ {
 		requestItem := requestItems[i]
 
-		incomeAmounts, expenseAmounts, err := a.transactions.GetAccountsTotalIncomeAndExpense(c, uid, requestItem.StartTime, requestItem.EndTime, excludeAccountIds, excludeCategoryIds, clientTimezone, transactionAmountsReq.UseTransactionTimezone)
+		incomeAmounts, expenseAmounts, err := a.transactions.GetAccountsTotalIncomeAndExpense(c, a.GetCurrentLedgerAccess(c), requestItem.StartTime, requestItem.EndTime, excludeAccountIds, excludeCategoryIds, clientTimezone, transactionAmountsReq.UseTransactionTimezone)
 
 		if err != nil {
 			log.Errorf(c, "[transactions.TransactionAmountsHandler] failed to get transaction amounts item for user \"uid:%d\", because %s", uid, err.Error())
@@ -837,7 +923,12 @@ func (a *TransactionsApi) TransactionGetHandler(c *core.WebContext) (any, *errs.
 		return nil, errs.ErrClientTimezoneOffsetInvalid
 	}
 
-	uid := c.GetCurrentUid()
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_VIEW_BASIC_DATA)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
 	user, err := a.users.GetUserById(c, uid)
 
 	if err != nil {
@@ -855,6 +946,12 @@ func (a *TransactionsApi) TransactionGetHandler(c *core.WebContext) (any, *errs.
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
+	errResult = a.CheckTransactionViewable(c, transaction)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
 	if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
 		transaction = a.transactions.GetRelatedTransferTransaction(transaction)
 	}
@@ -891,6 +988,7 @@ func (a *TransactionsApi) TransactionGetHandler(c *core.WebContext) (any, *errs.
 	var category *models.TransactionCategory
 	var tagMap map[int64]*models.TransactionTag
 	var pictureInfos []*models.TransactionPictureInfo
+	var attachments []*models.TransactionAttachment
 
 	if !transactionGetReq.TrimCategory {
 		category, err = a.transactionCategories.GetCategoryByCategoryId(c, uid, transaction.CategoryId)
@@ -919,6 +1017,22 @@ func (a *TransactionsApi) TransactionGetHandler(c *core.WebContext) (any, *errs.
 		}
 	}
 
+	if transactionGetReq.WithAttachments && a.CurrentConfig().EnableTransactionAttachments {
+		attachments, err = a.transactionAttachments.GetAttachmentsByTransactionId(c, uid, transaction.TransactionId)
+
+		if err != nil {
+			log.Errorf(c, "[transactions.TransactionGetHandler] failed to get transactions attachments for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+	}
+
+	allCustomFieldValues, err := a.transactionCustomFields.GetCustomFieldValuesByTransactionIds(c, uid, []int64{transaction.TransactionId})
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionGetHandler] failed to get transactions custom field values for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
 	transactionEditable := transaction.IsEditable(user, clientTimezone, accountMap[transaction.AccountId], accountMap[transaction.RelatedAccountId])
 	transactionTagIds := allTransactionTagIds[transaction.TransactionId]
 	transactionResp := transaction.ToTransactionInfoResponse(transactionTagIds, transactionEditable)
@@ -947,6 +1061,12 @@ func (a *TransactionsApi) TransactionGetHandler(c *core.WebContext) (any, *errs.
 		transactionResp.Pictures = a.GetTransactionPictureInfoResponseList(pictureInfos)
 	}
 
+	if transactionGetReq.WithAttachments && a.CurrentConfig().EnableTransactionAttachments {
+		transactionResp.Attachments = a.GetTransactionAttachmentInfoResponseList(attachments)
+	}
+
+	transactionResp.CustomFieldValues = a.getTransactionCustomFieldValueResponses(allCustomFieldValues[transaction.TransactionId])
+
 	return transactionResp, nil
 }
 
@@ -989,6 +1109,17 @@ func (a *TransactionsApi) TransactionCreateHandler(c *core.WebContext) (any, *er
 		return nil, errs.ErrTransactionHasTooManyPictures
 	}
 
+	attachmentIds, err := utils.StringArrayToInt64Array(transactionCreateReq.AttachmentIds)
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionCreateHandler] parse attachment ids failed, because %s", err.Error())
+		return nil, errs.ErrTransactionAttachmentIdInvalid
+	}
+
+	if len(attachmentIds) > models.MaximumAttachmentsCountOfTransaction {
+		return nil, errs.ErrTransactionHasTooManyAttachments
+	}
+
 	if transactionCreateReq.Type < models.TRANSACTION_TYPE_MODIFY_BALANCE || transactionCreateReq.Type > models.TRANSACTION_TYPE_TRANSFER {
 		log.Warnf(c, "[transactions.TransactionCreateHandler] transaction type is invalid")
 		return nil, errs.ErrTransactionTypeInvalid
@@ -1012,7 +1143,12 @@ func (a *TransactionsApi) TransactionCreateHandler(c *core.WebContext) (any, *er
 		return nil, errs.ErrTransactionDestinationAmountCannotBeSet
 	}
 
-	uid := c.GetCurrentUid()
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_CREATE_TRANSACTIONS)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
 	user, err := a.users.GetUserById(c, uid)
 
 	if err != nil {
@@ -1023,13 +1159,20 @@ func (a *TransactionsApi) TransactionCreateHandler(c *core.WebContext) (any, *er
 		return nil, errs.ErrUserNotFound
 	}
 
-	transaction := a.createNewTransactionModel(uid, &transactionCreateReq, c.ClientIP())
+	transaction := a.createNewTransactionModel(uid, c.GetCurrentUid(), c.GetCurrentLedgerId(), &transactionCreateReq, c.ClientIP())
 	transactionEditable := user.CanEditTransactionByTransactionTime(transaction.TransactionTime, clientTimezone)
 
 	if !transactionEditable {
 		return nil, errs.ErrCannotCreateTransactionWithThisTransactionTime
 	}
 
+	customFieldValues, err := a.getTransactionCustomFieldValueModels(c, uid, transactionCreateReq.CustomFieldValues)
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionCreateHandler] failed to parse transaction custom field values for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrTransactionCustomFieldValueInvalid)
+	}
+
 	var pictureInfos []*models.TransactionPictureInfo
 
 	if len(pictureIds) > 0 {
@@ -1048,6 +1191,24 @@ func (a *TransactionsApi) TransactionCreateHandler(c *core.WebContext) (any, *er
 		}
 	}
 
+	var attachments []*models.TransactionAttachment
+
+	if len(attachmentIds) > 0 {
+		attachments, err = a.transactionAttachments.GetNewAttachmentsByAttachmentIds(c, uid, attachmentIds)
+
+		if err != nil {
+			log.Errorf(c, "[transactions.TransactionCreateHandler] failed to get transactions attachments for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+
+		notExistsAttachmentIds := utils.Int64SliceMinus(attachmentIds, a.transactionAttachments.GetTransactionAttachmentIds(attachments))
+
+		if len(notExistsAttachmentIds) > 0 {
+			log.Errorf(c, "[transactions.TransactionCreateHandler] some attachments \"ids:%s\" does not exists for user \"uid:%d\"", strings.Join(utils.Int64ArrayToStringArray(notExistsAttachmentIds), ","), uid)
+			return nil, errs.ErrTransactionAttachmentNotFound
+		}
+	}
+
 	if a.CurrentConfig().EnableDuplicateSubmissionsCheck && transactionCreateReq.ClientSessionId != "" {
 		found, remark := a.GetSubmissionRemark(duplicatechecker.DUPLICATE_CHECKER_TYPE_NEW_TRANSACTION, uid, transactionCreateReq.ClientSessionId)
 
@@ -1071,7 +1232,14 @@ func (a *TransactionsApi) TransactionCreateHandler(c *core.WebContext) (any, *er
 		}
 	}
 
-	err = a.transactions.CreateTransaction(c, transaction, tagIds, pictureIds)
+	err = a.userQuotas.CheckTransactionQuota(c, user, 1)
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionCreateHandler] cannot create transaction for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	err = a.transactions.CreateTransaction(c, transaction, tagIds, pictureIds, attachmentIds, customFieldValues)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionCreateHandler] failed to create transaction \"id:%d\" for user \"uid:%d\", because %s", transaction.TransactionId, uid, err.Error())
@@ -1083,6 +1251,8 @@ func (a *TransactionsApi) TransactionCreateHandler(c *core.WebContext) (any, *er
 	a.SetSubmissionRemarkIfEnable(duplicatechecker.DUPLICATE_CHECKER_TYPE_NEW_TRANSACTION, uid, transactionCreateReq.ClientSessionId, utils.Int64ToString(transaction.TransactionId))
 	transactionResp := transaction.ToTransactionInfoResponse(tagIds, transactionEditable)
 	transactionResp.Pictures = a.GetTransactionPictureInfoResponseList(pictureInfos)
+	transactionResp.Attachments = a.GetTransactionAttachmentInfoResponseList(attachments)
+	transactionResp.CustomFieldValues = a.getTransactionCustomFieldValueResponses(customFieldValues)
 
 	return transactionResp, nil
 }
@@ -1126,7 +1296,23 @@ func (a *TransactionsApi) TransactionModifyHandler(c *core.WebContext) (any, *er
 		return nil, errs.ErrTransactionHasTooManyPictures
 	}
 
-	uid := c.GetCurrentUid()
+	attachmentIds, err := utils.StringArrayToInt64Array(transactionModifyReq.AttachmentIds)
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionModifyHandler] parse attachment ids failed, because %s", err.Error())
+		return nil, errs.ErrTransactionAttachmentIdInvalid
+	}
+
+	if len(attachmentIds) > models.MaximumAttachmentsCountOfTransaction {
+		return nil, errs.ErrTransactionHasTooManyAttachments
+	}
+
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_MODIFY_OWN_TRANSACTIONS)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
 	user, err := a.users.GetUserById(c, uid)
 
 	if err != nil {
@@ -1144,6 +1330,12 @@ func (a *TransactionsApi) TransactionModifyHandler(c *core.WebContext) (any, *er
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
+	errResult = a.CheckTransactionModifiable(c, transaction)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
 	if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
 		log.Warnf(c, "[transactions.TransactionModifyHandler] cannot modify transaction \"id:%d\" for user \"uid:%d\", because transaction type is transfer in", transactionModifyReq.Id, uid)
 		return nil, errs.ErrTransactionTypeInvalid
@@ -1179,6 +1371,41 @@ func (a *TransactionsApi) TransactionModifyHandler(c *core.WebContext) (any, *er
 
 	transactionPictureIds := a.transactionPictures.GetTransactionPictureIds(transactionPictureInfos)
 
+	transactionAttachments, err := a.transactionAttachments.GetAttachmentsByTransactionId(c, uid, transaction.TransactionId)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionModifyHandler] failed to get transaction attachments for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	transactionAttachmentIds := a.transactionAttachments.GetTransactionAttachmentIds(transactionAttachments)
+
+	if transactionModifyReq.AttachmentIds == nil {
+		attachmentIds = transactionAttachmentIds
+	}
+
+	allCustomFieldValues, err := a.transactionCustomFields.GetCustomFieldValuesByTransactionIds(c, uid, []int64{transaction.TransactionId})
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionModifyHandler] failed to get transaction custom field values for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	transactionCustomFieldValues := allCustomFieldValues[transaction.TransactionId]
+	newCustomFieldValues := transactionCustomFieldValues
+	customFieldValuesChanged := false
+
+	if transactionModifyReq.CustomFieldValues != nil {
+		newCustomFieldValues, err = a.getTransactionCustomFieldValueModels(c, uid, transactionModifyReq.CustomFieldValues)
+
+		if err != nil {
+			log.Warnf(c, "[transactions.TransactionModifyHandler] failed to parse transaction custom field values for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, errs.Or(err, errs.ErrTransactionCustomFieldValueInvalid)
+		}
+
+		customFieldValuesChanged = !a.isTransactionCustomFieldValuesEqual(transactionCustomFieldValues, newCustomFieldValues)
+	}
+
 	newTransaction := &models.Transaction{
 		TransactionId:     transaction.TransactionId,
 		Uid:               uid,
@@ -1188,9 +1415,14 @@ func (a *TransactionsApi) TransactionModifyHandler(c *core.WebContext) (any, *er
 		AccountId:         transactionModifyReq.SourceAccountId,
 		Amount:            transactionModifyReq.SourceAmount,
 		HideAmount:        transactionModifyReq.HideAmount,
+		EventId:           transaction.EventId,
 		Comment:           transactionModifyReq.Comment,
 	}
 
+	if transactionModifyReq.EventId != nil {
+		newTransaction.EventId = *transactionModifyReq.EventId
+	}
+
 	if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
 		newTransaction.RelatedAccountId = transactionModifyReq.DestinationAccountId
 		newTransaction.RelatedAccountAmount = transactionModifyReq.DestinationAmount
@@ -1209,11 +1441,14 @@ func (a *TransactionsApi) TransactionModifyHandler(c *core.WebContext) (any, *er
 		(transaction.Type != models.TRANSACTION_DB_TYPE_TRANSFER_OUT || newTransaction.RelatedAccountId == transaction.RelatedAccountId) &&
 		(transaction.Type != models.TRANSACTION_DB_TYPE_TRANSFER_OUT || newTransaction.RelatedAccountAmount == transaction.RelatedAccountAmount) &&
 		newTransaction.HideAmount == transaction.HideAmount &&
+		newTransaction.EventId == transaction.EventId &&
 		newTransaction.Comment == transaction.Comment &&
 		newTransaction.GeoLongitude == transaction.GeoLongitude &&
 		newTransaction.GeoLatitude == transaction.GeoLatitude &&
 		utils.Int64SliceEquals(tagIds, transactionTagIds) &&
-		utils.Int64SliceEquals(pictureIds, transactionPictureIds) {
+		utils.Int64SliceEquals(pictureIds, transactionPictureIds) &&
+		utils.Int64SliceEquals(attachmentIds, transactionAttachmentIds) &&
+		!customFieldValuesChanged {
 		return nil, errs.ErrNothingWillBeUpdated
 	}
 
@@ -1271,7 +1506,55 @@ func (a *TransactionsApi) TransactionModifyHandler(c *core.WebContext) (any, *er
 		}
 	}
 
-	err = a.transactions.ModifyTransaction(c, newTransaction, len(transactionTagIds), addTransactionTagIds, removeTransactionTagIds, addTransactionPictureIds, removeTransactionPictureIds)
+	addTransactionAttachmentIds := utils.Int64SliceMinus(attachmentIds, transactionAttachmentIds)
+	removeTransactionAttachmentIds := utils.Int64SliceMinus(transactionAttachmentIds, attachmentIds)
+	newAttachments := transactionAttachments
+
+	if len(addTransactionAttachmentIds) > 0 || len(removeTransactionAttachmentIds) > 0 {
+		oldAndNewAttachmentMap := make(map[int64]*models.TransactionAttachment, len(transactionAttachments))
+
+		for i := 0; i < len(transactionAttachments); i++ {
+			oldAndNewAttachmentMap[transactionAttachments[i].AttachmentId] = transactionAttachments[i]
+		}
+
+		if len(addTransactionAttachmentIds) > 0 {
+			addAttachments, err := a.transactionAttachments.GetNewAttachmentsByAttachmentIds(c, uid, addTransactionAttachmentIds)
+
+			if err != nil {
+				log.Errorf(c, "[transactions.TransactionModifyHandler] failed to get transactions attachments for user \"uid:%d\", because %s", uid, err.Error())
+				return nil, errs.Or(err, errs.ErrOperationFailed)
+			}
+
+			notExistsAttachmentIds := utils.Int64SliceMinus(addTransactionAttachmentIds, a.transactionAttachments.GetTransactionAttachmentIds(addAttachments))
+
+			if len(notExistsAttachmentIds) > 0 {
+				log.Errorf(c, "[transactions.TransactionModifyHandler] some attachments \"ids:%s\" does not exists for user \"uid:%d\"", strings.Join(utils.Int64ArrayToStringArray(notExistsAttachmentIds), ","), uid)
+				return nil, errs.ErrTransactionAttachmentNotFound
+			}
+
+			for i := 0; i < len(addAttachments); i++ {
+				oldAndNewAttachmentMap[addAttachments[i].AttachmentId] = addAttachments[i]
+			}
+		}
+
+		newAttachments = make([]*models.TransactionAttachment, 0, len(attachmentIds))
+
+		for i := 0; i < len(attachmentIds); i++ {
+			attachment, exists := oldAndNewAttachmentMap[attachmentIds[i]]
+
+			if exists {
+				newAttachments = append(newAttachments, attachment)
+			}
+		}
+	}
+
+	var updateCustomFieldValues []*models.TransactionCustomFieldValue
+
+	if customFieldValuesChanged {
+		updateCustomFieldValues = newCustomFieldValues
+	}
+
+	err = a.transactions.ModifyTransaction(c, newTransaction, len(transactionTagIds), addTransactionTagIds, removeTransactionTagIds, addTransactionPictureIds, removeTransactionPictureIds, addTransactionAttachmentIds, removeTransactionAttachmentIds, updateCustomFieldValues)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionModifyHandler] failed to update transaction \"id:%d\" for user \"uid:%d\", because %s", transactionModifyReq.Id, uid, err.Error())
@@ -1283,6 +1566,8 @@ func (a *TransactionsApi) TransactionModifyHandler(c *core.WebContext) (any, *er
 	newTransaction.Type = transaction.Type
 	newTransactionResp := newTransaction.ToTransactionInfoResponse(tagIds, transactionEditable)
 	newTransactionResp.Pictures = a.GetTransactionPictureInfoResponseList(newPictureInfos)
+	newTransactionResp.Attachments = a.GetTransactionAttachmentInfoResponseList(newAttachments)
+	newTransactionResp.CustomFieldValues = a.getTransactionCustomFieldValueResponses(newCustomFieldValues)
 
 	return newTransactionResp, nil
 }
@@ -1301,7 +1586,12 @@ func (a *TransactionsApi) TransactionMoveAllBetweenAccountsHandler(c *core.WebCo
 		return nil, errs.ErrCannotMoveTransactionToSameAccount
 	}
 
-	uid := c.GetCurrentUid()
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_MODIFY_ALL_TRANSACTIONS)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
 	accountMap, err := a.accounts.GetAccountsByAccountIds(c, uid, []int64{transactionMoveReq.FromAccountId, transactionMoveReq.ToAccountId})
 
 	if err != nil {
@@ -1321,6 +1611,18 @@ func (a *TransactionsApi) TransactionMoveAllBetweenAccountsHandler(c *core.WebCo
 		return nil, errs.ErrDestinationAccountNotFound
 	}
 
+	errResult = a.CheckDataInCurrentLedger(c, fromAccount.LedgerId)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	errResult = a.CheckDataInCurrentLedger(c, toAccount.LedgerId)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
 	if fromAccount.Hidden || toAccount.Hidden {
 		return nil, errs.ErrCannotMoveTransactionFromOrToHiddenAccount
 	}
@@ -1361,7 +1663,12 @@ func (a *TransactionsApi) TransactionDeleteHandler(c *core.WebContext) (any, *er
 		return nil, errs.ErrClientTimezoneOffsetInvalid
 	}
 
-	uid := c.GetCurrentUid()
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_MODIFY_OWN_TRANSACTIONS)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
 	user, err := a.users.GetUserById(c, uid)
 
 	if err != nil {
@@ -1379,6 +1686,12 @@ func (a *TransactionsApi) TransactionDeleteHandler(c *core.WebContext) (any, *er
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
+	errResult = a.CheckTransactionModifiable(c, transaction)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
 	if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
 		log.Warnf(c, "[transactions.TransactionDeleteHandler] cannot delete transaction \"id:%d\" for user \"uid:%d\", because transaction type is transfer in", transactionDeleteReq.Id, uid)
 		return nil, errs.ErrTransactionTypeInvalid
@@ -1403,7 +1716,12 @@ func (a *TransactionsApi) TransactionDeleteHandler(c *core.WebContext) (any, *er
 
 // TransactionParseImportDsvFileDataHandler returns the parsed file data by request parameters for current user
 func (a *TransactionsApi) TransactionParseImportDsvFileDataHandler(c *core.WebContext) (any, *errs.Error) {
-	uid := c.GetCurrentUid()
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_CREATE_TRANSACTIONS)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
 	form, err := c.MultipartForm()
 
 	if err != nil {
@@ -1480,7 +1798,12 @@ func (a *TransactionsApi) TransactionParseImportDsvFileDataHandler(c *core.WebCo
 
 // TransactionParseImportFileHandler returns the parsed transaction data by request parameters for current user
 func (a *TransactionsApi) TransactionParseImportFileHandler(c *core.WebContext) (any, *errs.Error) {
-	uid := c.GetCurrentUid()
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_CREATE_TRANSACTIONS)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
 	form, err := c.MultipartForm()
 
 	if err != nil {
@@ -1513,6 +1836,7 @@ func (a *TransactionsApi) TransactionParseImportFileHandler(c *core.WebContext)
 	additionalOptions := converter.ParseImporterOptions(textualOption)
 
 	var dataImporter converter.TransactionDataImporter
+	customFieldMap := make(map[int64]*models.TransactionCustomField)
 
 	if converters.IsCustomDelimiterSeparatedValuesFileType(fileType) {
 		fileEncodings := form.Value["fileEncoding"]
@@ -1599,6 +1923,42 @@ func (a *TransactionsApi) TransactionParseImportFileHandler(c *core.WebContext)
 			geoLocationOrder = geoLocationOrders[0]
 		}
 
+		customFieldMappings := form.Value["customFieldMapping"]
+
+		if len(customFieldMappings) > 0 && customFieldMappings[0] != "" {
+			var customFieldColumnIndexMapping = map[string]int{}
+			err = json.Unmarshal([]byte(customFieldMappings[0]), &customFieldColumnIndexMapping)
+
+			if err != nil {
+				log.Errorf(c, "[transactions.TransactionParseImportFileHandler] failed to parse custom field mapping for user \"uid:%d\", because %s", uid, err.Error())
+				return nil, errs.ErrImportFileColumnMappingInvalid
+			}
+
+			customFields, err := a.transactionCustomFields.GetAllCustomFieldsByUid(c, a.GetCurrentLedgerAccess(c))
+
+			if err != nil {
+				log.Errorf(c, "[transactions.TransactionParseImportFileHandler] failed to get custom fields for user \"uid:%d\", because %s", uid, err.Error())
+				return nil, errs.Or(err, errs.ErrOperationFailed)
+			}
+
+			customFieldIds := make([]int64, 0, len(customFieldColumnIndexMapping))
+
+			for i := 0; i < len(customFields) && len(customFieldIds) < datatable.TRANSACTION_DATA_TABLE_MAX_CUSTOM_FIELD_COUNT; i++ {
+				customField := customFields[i]
+				columnIndex, exists := customFieldColumnIndexMapping[utils.Int64ToString(customField.FieldId)]
+
+				if !exists {
+					continue
+				}
+
+				columnIndexMapping[datatable.GetCustomFieldDataTableColumn(len(customFieldIds))] = columnIndex
+				customFieldIds = append(customFieldIds, customField.FieldId)
+				customFieldMap[customField.FieldId] = customField
+			}
+
+			additionalOptions = additionalOptions.WithCustomFieldIds(customFieldIds)
+		}
+
 		transactionTagSeparators := form.Value["tagSeparator"]
 		transactionTagSeparator := ""
 
@@ -1661,7 +2021,7 @@ func (a *TransactionsApi) TransactionParseImportFileHandler(c *core.WebContext)
 		return nil, errs.ErrNotPermittedToPerformThisAction
 	}
 
-	accounts, err := a.accounts.GetAllAccountsByUid(c, user.Uid)
+	accounts, err := a.accounts.GetAllAccountsByUid(c, a.GetCurrentLedgerAccess(c))
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionParseImportFileHandler] failed to get accounts for user \"uid:%d\", because %s", user.Uid, err.Error())
@@ -1670,7 +2030,7 @@ func (a *TransactionsApi) TransactionParseImportFileHandler(c *core.WebContext)
 
 	accountMap := a.accounts.GetVisibleAccountNameMapByList(accounts)
 
-	categories, err := a.transactionCategories.GetAllCategoriesByUid(c, user.Uid, 0, -1)
+	categories, err := a.transactionCategories.GetAllCategoriesByUid(c, a.GetCurrentLedgerAccess(c), 0, -1)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionParseImportFileHandler] failed to get categories for user \"uid:%d\", because %s", user.Uid, err.Error())
@@ -1679,7 +2039,7 @@ func (a *TransactionsApi) TransactionParseImportFileHandler(c *core.WebContext)
 
 	expenseCategoryMap, incomeCategoryMap, transferCategoryMap := a.transactionCategories.GetVisibleSubCategoryNameMapByList(categories)
 
-	tags, err := a.transactionTags.GetAllTagsByUid(c, user.Uid)
+	tags, err := a.transactionTags.GetAllTagsByUid(c, a.GetCurrentLedgerAccess(c))
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionParseImportFileHandler] failed to get tags for user \"uid:%d\", because %s", user.Uid, err.Error())
@@ -1695,6 +2055,23 @@ func (a *TransactionsApi) TransactionParseImportFileHandler(c *core.WebContext)
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
+	for i := 0; i < len(parsedTransactions); i++ {
+		customFieldValues := parsedTransactions[i].CustomFieldValues
+
+		for j := 0; j < len(customFieldValues); j++ {
+			customField, exists := customFieldMap[customFieldValues[j].FieldId]
+
+			if !exists {
+				return nil, errs.ErrTransactionCustomFieldNotFound
+			}
+
+			if _, _, err = customField.ParseValue(customFieldValues[j].Value); err != nil {
+				log.Warnf(c, "[transactions.TransactionParseImportFileHandler] cannot parse value of custom field \"id:%d\" for user \"uid:%d\", because %s", customField.FieldId, user.Uid, err.Error())
+				return nil, errs.Or(err, errs.ErrTransactionCustomFieldValueInvalid)
+			}
+		}
+	}
+
 	parsedTransactionRespsList := parsedTransactions.ToImportTransactionResponseList()
 
 	if len(parsedTransactionRespsList) < 1 {
@@ -1726,7 +2103,11 @@ func (a *TransactionsApi) TransactionImportHandler(c *core.WebContext) (any, *er
 		return nil, errs.ErrClientTimezoneOffsetInvalid
 	}
 
-	uid := c.GetCurrentUid()
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_CREATE_TRANSACTIONS)
+
+	if errResult != nil {
+		return nil, errResult
+	}
 
 	if a.CurrentConfig().EnableDuplicateSubmissionsCheck && transactionImportReq.ClientSessionId != "" {
 		found, remark := a.GetSubmissionRemark(duplicatechecker.DUPLICATE_CHECKER_TYPE_IMPORT_TRANSACTIONS, uid, transactionImportReq.ClientSessionId)
@@ -1806,17 +2187,36 @@ func (a *TransactionsApi) TransactionImportHandler(c *core.WebContext) (any, *er
 		return nil, errs.ErrNotPermittedToPerformThisAction
 	}
 
+	err = a.userQuotas.CheckTransactionQuota(c, user, int64(len(transactionImportReq.Transactions)))
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionImportHandler] cannot import %d transactions for user \"uid:%d\", because %s", len(transactionImportReq.Transactions), uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
 	newTransactions := make([]*models.Transaction, len(transactionImportReq.Transactions))
+	newTransactionCustomFieldValuesMap := make(map[int][]*models.TransactionCustomFieldValue)
 
 	for i := 0; i < len(transactionImportReq.Transactions); i++ {
 		transactionCreateReq := transactionImportReq.Transactions[i]
-		transaction := a.createNewTransactionModel(uid, transactionCreateReq, c.ClientIP())
+		transaction := a.createNewTransactionModel(uid, c.GetCurrentUid(), c.GetCurrentLedgerId(), transactionCreateReq, c.ClientIP())
 		transactionEditable := user.CanEditTransactionByTransactionTime(transaction.TransactionTime, clientTimezone)
 
 		if !transactionEditable {
 			return nil, errs.ErrCannotCreateTransactionWithThisTransactionTime
 		}
 
+		if len(transactionCreateReq.CustomFieldValues) > 0 {
+			customFieldValues, err := a.getTransactionCustomFieldValueModels(c, uid, transactionCreateReq.CustomFieldValues)
+
+			if err != nil {
+				log.Warnf(c, "[transactions.TransactionImportHandler] failed to parse custom field values of transaction \"index:%d\", because %s", i, err.Error())
+				return nil, errs.Or(err, errs.ErrTransactionCustomFieldValueInvalid)
+			}
+
+			newTransactionCustomFieldValuesMap[i] = customFieldValues
+		}
+
 		newTransactions[i] = transaction
 	}
 
@@ -1831,6 +2231,22 @@ func (a *TransactionsApi) TransactionImportHandler(c *core.WebContext) (any, *er
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
+	if len(newTransactionCustomFieldValuesMap) > 0 {
+		allCustomFieldValues := make(map[int64][]*models.TransactionCustomFieldValue, len(newTransactionCustomFieldValuesMap))
+
+		for index, customFieldValues := range newTransactionCustomFieldValuesMap {
+			allCustomFieldValues[newTransactions[index].TransactionId] = customFieldValues
+		}
+
+		err = a.transactionCustomFields.SetTransactionsCustomFieldValues(c, uid, allCustomFieldValues)
+
+		if err != nil {
+			a.RemoveSubmissionRemarkIfEnable(duplicatechecker.DUPLICATE_CHECKER_TYPE_IMPORT_TRANSACTIONS, uid, transactionImportReq.ClientSessionId)
+			log.Errorf(c, "[transactions.TransactionImportHandler] failed to save custom field values of %d imported transactions for user \"uid:%d\", because %s", len(allCustomFieldValues), uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+	}
+
 	log.Infof(c, "[transactions.TransactionImportHandler] user \"uid:%d\" has imported %d transactions successfully", uid, count)
 
 	a.SetSubmissionRemarkIfEnable(duplicatechecker.DUPLICATE_CHECKER_TYPE_IMPORT_TRANSACTIONS, uid, transactionImportReq.ClientSessionId, fmt.Sprintf("finished:%d", count))
@@ -1848,7 +2264,11 @@ func (a *TransactionsApi) TransactionImportProcessHandler(c *core.WebContext) (a
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
+	uid, errResult := a.GetLedgerOwnerUidWithPermission(c, models.LEDGER_PERMISSION_CREATE_TRANSACTIONS)
+
+	if errResult != nil {
+		return nil, errResult
+	}
 
 	if !a.CurrentConfig().EnableDuplicateSubmissionsCheck {
 		return nil, nil
@@ -1967,6 +2387,13 @@ func (a *TransactionsApi) getTransactionResponseListResult(c *core.WebContext, u
 		return nil, err
 	}
 
+	allCustomFieldValues, err := a.transactionCustomFields.GetCustomFieldValuesByTransactionIds(c, uid, transactionIds)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.getTransactionResponseListResult] failed to get transactions custom field values for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, err
+	}
+
 	var categoryMap map[int64]*models.TransactionCategory
 	var tagMap map[int64]*models.TransactionTag
 	var pictureInfoMap map[int64][]*models.TransactionPictureInfo
@@ -2038,6 +2465,8 @@ func (a *TransactionsApi) getTransactionResponseListResult(c *core.WebContext, u
 				result[i].Pictures = a.GetTransactionPictureInfoResponseList(pictureInfos)
 			}
 		}
+
+		result[i].CustomFieldValues = a.getTransactionCustomFieldValueResponses(allCustomFieldValues[transaction.TransactionId])
 	}
 
 	sort.Sort(result)
@@ -2045,7 +2474,93 @@ func (a *TransactionsApi) getTransactionResponseListResult(c *core.WebContext, u
 	return result, nil
 }
 
-func (a *TransactionsApi) createNewTransactionModel(uid int64, transactionCreateReq *models.TransactionCreateRequest, clientIp string) *models.Transaction {
+func (a *TransactionsApi) getCustomFieldFilters(c *core.WebContext, uid int64, customFieldFilterStr string) ([]*models.TransactionCustomFieldFilter, error) {
+	customFieldFilters, err := models.ParseTransactionCustomFieldFilter(customFieldFilterStr)
+
+	if err != nil || len(customFieldFilters) < 1 {
+		return customFieldFilters, err
+	}
+
+	fieldIds := make([]int64, len(customFieldFilters))
+
+	for i := 0; i < len(customFieldFilters); i++ {
+		fieldIds[i] = customFieldFilters[i].FieldId
+	}
+
+	customFieldMap, err := a.transactionCustomFields.GetCustomFieldsByFieldIds(c, uid, utils.ToUniqueInt64Slice(fieldIds))
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < len(customFieldFilters); i++ {
+		customField := customFieldMap[customFieldFilters[i].FieldId]
+
+		if customField != nil && customField.LedgerId != c.GetCurrentLedgerId() {
+			return nil, errs.ErrTransactionCustomFieldInDifferentLedger
+		}
+
+		err = customFieldFilters[i].Resolve(customField)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return customFieldFilters, nil
+}
+
+func (a *TransactionsApi) getTransactionCustomFieldValueModels(c *core.WebContext, uid int64, valueRequests []*models.TransactionCustomFieldValueRequest) ([]*models.TransactionCustomFieldValue, error) {
+	if len(valueRequests) < 1 {
+		return make([]*models.TransactionCustomFieldValue, 0), nil
+	}
+
+	customFieldMap, err := a.transactionCustomFields.GetCustomFieldsByFieldIds(c, uid, a.transactionCustomFields.GetCustomFieldIdsFromValueRequests(valueRequests))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return a.transactionCustomFields.ParseCustomFieldValues(c.GetCurrentLedgerId(), customFieldMap, valueRequests)
+}
+
+func (a *TransactionsApi) getTransactionCustomFieldValueResponses(values []*models.TransactionCustomFieldValue) []*models.TransactionCustomFieldValueResponse {
+	if len(values) < 1 {
+		return nil
+	}
+
+	valueResps := make([]*models.TransactionCustomFieldValueResponse, len(values))
+
+	for i := 0; i < len(values); i++ {
+		valueResps[i] = values[i].ToTransactionCustomFieldValueResponse()
+	}
+
+	return valueResps
+}
+
+func (a *TransactionsApi) isTransactionCustomFieldValuesEqual(oldValues []*models.TransactionCustomFieldValue, newValues []*models.TransactionCustomFieldValue) bool {
+	if len(oldValues) != len(newValues) {
+		return false
+	}
+
+	oldValueMap := make(map[int64]string, len(oldValues))
+
+	for i := 0; i < len(oldValues); i++ {
+		oldValueMap[oldValues[i].FieldId] = oldValues[i].TextValue
+	}
+
+	for i := 0; i < len(newValues); i++ {
+		oldValue, exists := oldValueMap[newValues[i].FieldId]
+
+		if !exists || oldValue != newValues[i].TextValue {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (a *TransactionsApi) createNewTransactionModel(uid int64, creatorUid int64, ledgerId int64, transactionCreateReq *models.TransactionCreateRequest, clientIp string) *models.Transaction {
 	var transactionDbType models.TransactionDbType
 
 	if transactionCreateReq.Type == models.TRANSACTION_TYPE_MODIFY_BALANCE {
@@ -2060,6 +2575,7 @@ func (a *TransactionsApi) createNewTransactionModel(uid int64, transactionCreate
 
 	transaction := &models.Transaction{
 		Uid:               uid,
+		LedgerId:          ledgerId,
 		Type:              transactionDbType,
 		CategoryId:        transactionCreateReq.CategoryId,
 		TransactionTime:   utils.GetMinTransactionTimeFromUnixTime(transactionCreateReq.Time),
@@ -2067,8 +2583,10 @@ func (a *TransactionsApi) createNewTransactionModel(uid int64, transactionCreate
 		AccountId:         transactionCreateReq.SourceAccountId,
 		Amount:            transactionCreateReq.SourceAmount,
 		HideAmount:        transactionCreateReq.HideAmount,
+		EventId:           transactionCreateReq.EventId,
 		Comment:           transactionCreateReq.Comment,
 		CreatedIp:         clientIp,
+		CreatorUid:        creatorUid,
 	}
 
 	if transactionCreateReq.Type == models.TRANSACTION_TYPE_TRANSFER {
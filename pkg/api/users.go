@@ -25,6 +25,7 @@ type UsersApi struct {
 	users    *services.UserService
 	tokens   *services.TokenService
 	accounts *services.AccountService
+	ledgers  *services.LedgerService
 }
 
 // Initialize a user api singleton instance
@@ -44,6 +45,7 @@ var (
 		users:    services.Users,
 		tokens:   services.Tokens,
 		accounts: services.Accounts,
+		ledgers:  services.Ledgers,
 	}
 )
 
@@ -92,6 +94,13 @@ func (a *UsersApi) UserRegisterHandler(c *core.WebContext) (any, *errs.Error) {
 
 	log.Infof(c, "[users.UserRegisterHandler] user \"%s\" has registered successfully, uid is %d", user.Username, user.Uid)
 
+	_, err = a.ledgers.GetOrCreateDefaultLedger(c, user.Uid)
+
+	if err != nil {
+		log.Errorf(c, "[users.UserRegisterHandler] failed to create default ledger for user \"uid:%d\", because %s", user.Uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
 	presetCategoriesSaved := false
 
 	if len(userRegisterReq.Categories) > 0 {
@@ -0,0 +1,73 @@
+package backup
+
+import (
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+// UserDataBackupFormatVersion represents the current format version of user data backup archive
+const UserDataBackupFormatVersion = 1
+
+const userDataBackupManifestFileName = "manifest.json"
+const userDataBackupDataFileName = "data.json"
+const userDataBackupPictureDirectory = "pictures/"
+const userDataBackupAttachmentDirectory = "attachments/"
+const userDataBackupAttachmentThumbnailDirectory = "attachments/thumbnails/"
+
+// UserDataBackupManifest represents the manifest of user data backup archive
+type UserDataBackupManifest struct {
+	FormatVersion             int    `json:"formatVersion"`
+	ApplicationVersion        string `json:"applicationVersion"`
+	Uid                       int64  `json:"uid,string"`
+	Username                  string `json:"username"`
+	CreatedUnixTime           int64  `json:"createdUnixTime"`
+	ExcludedLedgerMemberCount int64  `json:"excludedLedgerMemberCount"`
+}
+
+// UserDataBackupContent represents all user data stored in user data backup archive,
+// the members of the ledgers are not included, because they refer to other users who may not exist where the backup is restored,
+// so the ledgers are always restored as unshared and the count of excluded members is recorded in the manifest
+type UserDataBackupContent struct {
+	Ledgers                  []*models.Ledger                      `json:"ledgers"`
+	Accounts                 []*models.Account                     `json:"accounts"`
+	Categories               []*models.TransactionCategory         `json:"categories"`
+	TagGroups                []*models.TransactionTagGroup         `json:"tagGroups"`
+	Tags                     []*models.TransactionTag              `json:"tags"`
+	CustomFields             []*models.TransactionCustomField      `json:"customFields"`
+	Contacts                 []*models.Contact                     `json:"contacts"`
+	Events                   []*models.Event                       `json:"events"`
+	Transactions             []*models.Transaction                 `json:"transactions"`
+	TagIndexes               []*models.TransactionTagIndex         `json:"tagIndexes"`
+	CustomFieldValues        []*models.TransactionCustomFieldValue `json:"customFieldValues"`
+	ContactTransactions      []*models.ContactTransaction          `json:"contactTransactions"`
+	Templates                []*models.TransactionTemplate         `json:"templates"`
+	Pictures                 []*models.TransactionPictureInfo      `json:"pictures"`
+	Attachments              []*models.TransactionAttachment       `json:"attachments"`
+	CustomExchangeRates      []*models.UserCustomExchangeRate      `json:"customExchangeRates"`
+	ApplicationCloudSettings *models.UserApplicationCloudSetting   `json:"applicationCloudSettings"`
+}
+
+// UserDataBackup represents a whole user data backup, including the data and the files of pictures and attachments
+type UserDataBackup struct {
+	Manifest                 *UserDataBackupManifest
+	Content                  *UserDataBackupContent
+	PictureFiles             map[int64][]byte
+	AttachmentFiles          map[int64][]byte
+	AttachmentThumbnailFiles map[int64][]byte
+}
+
+// NewUserDataBackup returns a new empty user data backup
+func NewUserDataBackup(uid int64, username string, applicationVersion string, createdUnixTime int64) *UserDataBackup {
+	return &UserDataBackup{
+		Manifest: &UserDataBackupManifest{
+			FormatVersion:      UserDataBackupFormatVersion,
+			ApplicationVersion: applicationVersion,
+			Uid:                uid,
+			Username:           username,
+			CreatedUnixTime:    createdUnixTime,
+		},
+		Content:                  &UserDataBackupContent{},
+		PictureFiles:             make(map[int64][]byte),
+		AttachmentFiles:          make(map[int64][]byte),
+		AttachmentThumbnailFiles: make(map[int64][]byte),
+	}
+}
@@ -0,0 +1,273 @@
+package backup
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+// WriteUserDataBackupArchive returns the zip archive content of the specified user data backup
+func WriteUserDataBackupArchive(backup *UserDataBackup) ([]byte, error) {
+	if backup == nil || backup.Manifest == nil || backup.Content == nil {
+		return nil, errs.ErrOperationFailed
+	}
+
+	buffer := &bytes.Buffer{}
+	writer := zip.NewWriter(buffer)
+
+	if err := writeJsonFileToArchive(writer, userDataBackupManifestFileName, backup.Manifest); err != nil {
+		return nil, err
+	}
+
+	if err := writeJsonFileToArchive(writer, userDataBackupDataFileName, backup.Content); err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < len(backup.Content.Pictures); i++ {
+		pictureInfo := backup.Content.Pictures[i]
+		pictureData, exists := backup.PictureFiles[pictureInfo.PictureId]
+
+		if !exists {
+			continue
+		}
+
+		fileName := fmt.Sprintf("%s%d.%s", userDataBackupPictureDirectory, pictureInfo.PictureId, pictureInfo.PictureExtension)
+
+		if err := writeFileToArchive(writer, fileName, pictureData); err != nil {
+			return nil, err
+		}
+	}
+
+	for i := 0; i < len(backup.Content.Attachments); i++ {
+		attachment := backup.Content.Attachments[i]
+		attachmentData, exists := backup.AttachmentFiles[attachment.AttachmentId]
+
+		if !exists {
+			continue
+		}
+
+		fileName := fmt.Sprintf("%s%d.%s", userDataBackupAttachmentDirectory, attachment.AttachmentId, attachment.FileExtension)
+
+		if err := writeFileToArchive(writer, fileName, attachmentData); err != nil {
+			return nil, err
+		}
+
+		thumbnailData, exists := backup.AttachmentThumbnailFiles[attachment.AttachmentId]
+
+		if !exists {
+			continue
+		}
+
+		fileName = fmt.Sprintf("%s%d.%s", userDataBackupAttachmentThumbnailDirectory, attachment.AttachmentId, models.TransactionAttachmentThumbnailFileExtension)
+
+		if err := writeFileToArchive(writer, fileName, thumbnailData); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// ReadUserDataBackupArchive parses the zip archive from the reader and returns the user data backup,
+// each file in the archive is read through a limited reader, so that the uncompressed size of every file
+// cannot exceed the max entry size and the uncompressed size of all files cannot exceed the max total size
+func ReadUserDataBackupArchive(reader io.ReaderAt, size int64, maxEntrySize uint32, maxTotalSize uint64) (*UserDataBackup, error) {
+	zipReader, err := zip.NewReader(reader, size)
+
+	if err != nil {
+		return nil, errs.ErrBackupFileInvalid
+	}
+
+	archiveReader := &userDataBackupArchiveReader{
+		files:        make(map[string]*zip.File, len(zipReader.File)),
+		maxEntrySize: uint64(maxEntrySize),
+		maxTotalSize: maxTotalSize,
+	}
+
+	for i := 0; i < len(zipReader.File); i++ {
+		archiveReader.files[zipReader.File[i].Name] = zipReader.File[i]
+	}
+
+	manifest := &UserDataBackupManifest{}
+
+	if err := archiveReader.readJsonFile(userDataBackupManifestFileName, manifest); err != nil {
+		return nil, err
+	}
+
+	if manifest.FormatVersion < 1 || manifest.FormatVersion > UserDataBackupFormatVersion {
+		return nil, errs.ErrBackupVersionNotSupported
+	}
+
+	content := &UserDataBackupContent{}
+
+	if err := archiveReader.readJsonFile(userDataBackupDataFileName, content); err != nil {
+		return nil, err
+	}
+
+	backup := &UserDataBackup{
+		Manifest:                 manifest,
+		Content:                  content,
+		PictureFiles:             make(map[int64][]byte, len(content.Pictures)),
+		AttachmentFiles:          make(map[int64][]byte, len(content.Attachments)),
+		AttachmentThumbnailFiles: make(map[int64][]byte),
+	}
+
+	for i := 0; i < len(content.Pictures); i++ {
+		pictureInfo := content.Pictures[i]
+		fileName := fmt.Sprintf("%s%d.%s", userDataBackupPictureDirectory, pictureInfo.PictureId, pictureInfo.PictureExtension)
+		pictureData, err := archiveReader.readFile(fileName)
+
+		if err != nil {
+			return nil, err
+		}
+
+		backup.PictureFiles[pictureInfo.PictureId] = pictureData
+	}
+
+	for i := 0; i < len(content.Attachments); i++ {
+		attachment := content.Attachments[i]
+		fileName := fmt.Sprintf("%s%d.%s", userDataBackupAttachmentDirectory, attachment.AttachmentId, attachment.FileExtension)
+		attachmentData, err := archiveReader.readFile(fileName)
+
+		if err != nil {
+			return nil, err
+		}
+
+		backup.AttachmentFiles[attachment.AttachmentId] = attachmentData
+
+		thumbnailFileName := fmt.Sprintf("%s%d.%s", userDataBackupAttachmentThumbnailDirectory, attachment.AttachmentId, models.TransactionAttachmentThumbnailFileExtension)
+
+		if archiveReader.files[thumbnailFileName] == nil {
+			continue
+		}
+
+		thumbnailData, err := archiveReader.readFile(thumbnailFileName)
+
+		if err != nil {
+			return nil, err
+		}
+
+		backup.AttachmentThumbnailFiles[attachment.AttachmentId] = thumbnailData
+	}
+
+	return backup, nil
+}
+
+func writeJsonFileToArchive(writer *zip.Writer, fileName string, value any) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+
+	if err != nil {
+		return err
+	}
+
+	return writeFileToArchive(writer, fileName, data)
+}
+
+func writeFileToArchive(writer *zip.Writer, fileName string, data []byte) error {
+	fileWriter, err := writer.Create(fileName)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = fileWriter.Write(data)
+
+	return err
+}
+
+// userDataBackupArchiveReader reads the files in user data backup archive and tracks the total uncompressed size that has been read
+type userDataBackupArchiveReader struct {
+	files        map[string]*zip.File
+	maxEntrySize uint64
+	maxTotalSize uint64
+	totalSize    uint64
+}
+
+// archiveFileCountingReader counts the bytes read from the underlying reader
+type archiveFileCountingReader struct {
+	reader   io.Reader
+	readSize uint64
+}
+
+// Read implements io.Reader
+func (r *archiveFileCountingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.readSize += uint64(n)
+
+	return n, err
+}
+
+func (r *userDataBackupArchiveReader) readJsonFile(fileName string, value any) error {
+	return r.readFileWith(fileName, func(reader io.Reader) error {
+		return json.NewDecoder(reader).Decode(value)
+	})
+}
+
+func (r *userDataBackupArchiveReader) readFile(fileName string) ([]byte, error) {
+	var data []byte
+
+	err := r.readFileWith(fileName, func(reader io.Reader) error {
+		var err error
+		data, err = io.ReadAll(reader)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (r *userDataBackupArchiveReader) readFileWith(fileName string, consume func(reader io.Reader) error) error {
+	file := r.files[fileName]
+
+	if file == nil {
+		return errs.ErrBackupFileInvalid
+	}
+
+	limit := r.maxEntrySize
+
+	if r.maxTotalSize-r.totalSize < limit {
+		limit = r.maxTotalSize - r.totalSize
+	}
+
+	if file.UncompressedSize64 > limit {
+		return errs.ErrBackupFileTooLarge
+	}
+
+	fileReader, err := file.Open()
+
+	if err != nil {
+		return errs.ErrBackupFileInvalid
+	}
+
+	defer fileReader.Close()
+
+	// read one more byte than the limit, so that the file whose actual size exceeds the declared size can be detected
+	countingReader := &archiveFileCountingReader{
+		reader: io.LimitReader(fileReader, int64(limit)+1),
+	}
+
+	err = consume(countingReader)
+	r.totalSize += countingReader.readSize
+
+	if countingReader.readSize > limit {
+		return errs.ErrBackupFileTooLarge
+	}
+
+	if err != nil {
+		return errs.ErrBackupFileInvalid
+	}
+
+	return nil
+}
@@ -0,0 +1,283 @@
+package backup
+
+import (
+	"archive/zip"
+	"bytes"
+	"hash/crc32"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+func TestWriteUserDataBackupArchive_FileList(t *testing.T) {
+	backup := createTestUserDataBackup()
+
+	data, err := WriteUserDataBackupArchive(backup)
+	assert.Nil(t, err)
+
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	assert.Nil(t, err)
+
+	fileNames := make([]string, 0, len(reader.File))
+
+	for i := 0; i < len(reader.File); i++ {
+		fileNames = append(fileNames, reader.File[i].Name)
+	}
+
+	sort.Strings(fileNames)
+
+	assert.Equal(t, []string{
+		"attachments/5001.pdf",
+		"attachments/thumbnails/5001.thumbnail.jpg",
+		"data.json",
+		"manifest.json",
+		"pictures/4001.jpg",
+	}, fileNames)
+}
+
+func TestWriteAndReadUserDataBackupArchive(t *testing.T) {
+	backup := createTestUserDataBackup()
+
+	data, err := WriteUserDataBackupArchive(backup)
+	assert.Nil(t, err)
+
+	actualBackup, err := readTestUserDataBackupArchive(data)
+	assert.Nil(t, err)
+
+	assert.Equal(t, UserDataBackupFormatVersion, actualBackup.Manifest.FormatVersion)
+	assert.Equal(t, "1.0.0", actualBackup.Manifest.ApplicationVersion)
+	assert.Equal(t, int64(1234567890), actualBackup.Manifest.Uid)
+	assert.Equal(t, "test", actualBackup.Manifest.Username)
+	assert.Equal(t, int64(1725120000), actualBackup.Manifest.CreatedUnixTime)
+
+	assert.Equal(t, backup.Content, actualBackup.Content)
+	assert.Equal(t, []byte("picture"), actualBackup.PictureFiles[4001])
+	assert.Equal(t, []byte("attachment"), actualBackup.AttachmentFiles[5001])
+	assert.Equal(t, []byte("thumbnail"), actualBackup.AttachmentThumbnailFiles[5001])
+}
+
+func TestReadUserDataBackupArchive_InvalidArchive(t *testing.T) {
+	_, err := readTestUserDataBackupArchive([]byte("not a zip file"))
+	assert.EqualError(t, err, errs.ErrBackupFileInvalid.Message)
+}
+
+func TestReadUserDataBackupArchive_MissingManifest(t *testing.T) {
+	data := createTestZipArchive(t, map[string]string{
+		"data.json": "{}",
+	})
+
+	_, err := readTestUserDataBackupArchive(data)
+	assert.EqualError(t, err, errs.ErrBackupFileInvalid.Message)
+}
+
+func TestReadUserDataBackupArchive_UnsupportedVersion(t *testing.T) {
+	data := createTestZipArchive(t, map[string]string{
+		"manifest.json": "{\"formatVersion\":2}",
+		"data.json":     "{}",
+	})
+
+	_, err := readTestUserDataBackupArchive(data)
+	assert.EqualError(t, err, errs.ErrBackupVersionNotSupported.Message)
+
+	data = createTestZipArchive(t, map[string]string{
+		"manifest.json": "{}",
+		"data.json":     "{}",
+	})
+
+	_, err = readTestUserDataBackupArchive(data)
+	assert.EqualError(t, err, errs.ErrBackupVersionNotSupported.Message)
+}
+
+func TestReadUserDataBackupArchive_InvalidData(t *testing.T) {
+	data := createTestZipArchive(t, map[string]string{
+		"manifest.json": "{\"formatVersion\":1}",
+		"data.json":     "{",
+	})
+
+	_, err := readTestUserDataBackupArchive(data)
+	assert.EqualError(t, err, errs.ErrBackupFileInvalid.Message)
+}
+
+func TestReadUserDataBackupArchive_MissingPictureFile(t *testing.T) {
+	data := createTestZipArchive(t, map[string]string{
+		"manifest.json": "{\"formatVersion\":1}",
+		"data.json":     "{\"pictures\":[{\"PictureId\":4001,\"PictureExtension\":\"jpg\"}]}",
+	})
+
+	_, err := readTestUserDataBackupArchive(data)
+	assert.EqualError(t, err, errs.ErrBackupFileInvalid.Message)
+}
+
+func TestReadUserDataBackupArchive_EntryTooLarge(t *testing.T) {
+	backup := createTestUserDataBackup()
+	backup.AttachmentFiles[5001] = bytes.Repeat([]byte("a"), 65536)
+
+	data, err := WriteUserDataBackupArchive(backup)
+	assert.Nil(t, err)
+
+	_, err = ReadUserDataBackupArchive(bytes.NewReader(data), int64(len(data)), 65535, 1048576)
+	assert.EqualError(t, err, errs.ErrBackupFileTooLarge.Message)
+
+	actualBackup, err := ReadUserDataBackupArchive(bytes.NewReader(data), int64(len(data)), 65536, 1048576)
+	assert.Nil(t, err)
+	assert.Equal(t, backup.AttachmentFiles[5001], actualBackup.AttachmentFiles[5001])
+}
+
+func TestReadUserDataBackupArchive_TotalTooLarge(t *testing.T) {
+	backup := createTestUserDataBackup()
+	backup.PictureFiles[4001] = bytes.Repeat([]byte("p"), 1024)
+	backup.AttachmentFiles[5001] = bytes.Repeat([]byte("a"), 1024)
+
+	data, err := WriteUserDataBackupArchive(backup)
+	assert.Nil(t, err)
+
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	assert.Nil(t, err)
+
+	totalSize := uint64(0)
+
+	for i := 0; i < len(reader.File); i++ {
+		totalSize += reader.File[i].UncompressedSize64
+	}
+
+	_, err = ReadUserDataBackupArchive(bytes.NewReader(data), int64(len(data)), 1048576, totalSize-1)
+	assert.EqualError(t, err, errs.ErrBackupFileTooLarge.Message)
+
+	_, err = ReadUserDataBackupArchive(bytes.NewReader(data), int64(len(data)), 1048576, totalSize)
+	assert.Nil(t, err)
+}
+
+func TestReadUserDataBackupArchive_ActualSizeExceedsDeclaredSize(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	writer := zip.NewWriter(buffer)
+
+	manifest := []byte("{\"formatVersion\":1}")
+	fileWriter, err := writer.CreateRaw(&zip.FileHeader{
+		Name:               "manifest.json",
+		Method:             zip.Store,
+		CRC32:              crc32.ChecksumIEEE(manifest),
+		CompressedSize64:   uint64(len(manifest)),
+		UncompressedSize64: 1,
+	})
+	assert.Nil(t, err)
+
+	_, err = fileWriter.Write(manifest)
+	assert.Nil(t, err)
+	assert.Nil(t, writer.Close())
+
+	data := buffer.Bytes()
+
+	_, err = ReadUserDataBackupArchive(bytes.NewReader(data), int64(len(data)), 10, 1048576)
+	assert.EqualError(t, err, errs.ErrBackupFileInvalid.Message)
+}
+
+func TestWriteAndReadUserDataBackupArchive_ExcludedLedgerMemberCount(t *testing.T) {
+	backup := createTestUserDataBackup()
+	backup.Manifest.ExcludedLedgerMemberCount = 2
+
+	data, err := WriteUserDataBackupArchive(backup)
+	assert.Nil(t, err)
+
+	actualBackup, err := readTestUserDataBackupArchive(data)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), actualBackup.Manifest.ExcludedLedgerMemberCount)
+}
+
+func readTestUserDataBackupArchive(data []byte) (*UserDataBackup, error) {
+	return ReadUserDataBackupArchive(bytes.NewReader(data), int64(len(data)), 104857600, 1073741824)
+}
+
+func createTestZipArchive(t *testing.T, files map[string]string) []byte {
+	buffer := &bytes.Buffer{}
+	writer := zip.NewWriter(buffer)
+
+	for fileName, content := range files {
+		fileWriter, err := writer.Create(fileName)
+		assert.Nil(t, err)
+
+		_, err = fileWriter.Write([]byte(content))
+		assert.Nil(t, err)
+	}
+
+	assert.Nil(t, writer.Close())
+
+	return buffer.Bytes()
+}
+
+func createTestUserDataBackup() *UserDataBackup {
+	backup := NewUserDataBackup(1234567890, "test", "1.0.0", 1725120000)
+	statementDate := 15
+
+	backup.Content = &UserDataBackupContent{
+		Ledgers: []*models.Ledger{
+			{LedgerId: 100, Uid: 1234567890, Name: "Default", IsDefault: true},
+		},
+		Accounts: []*models.Account{
+			{AccountId: 1001, Uid: 1234567890, LedgerId: 100, Category: models.ACCOUNT_CATEGORY_CREDIT_CARD, Type: models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS, Name: "Card", Currency: "---", Icon: 1, Color: "000000"},
+			{AccountId: 1002, Uid: 1234567890, LedgerId: 100, Category: models.ACCOUNT_CATEGORY_CREDIT_CARD, Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, ParentAccountId: 1001, Name: "Sub Card", Currency: "USD", Balance: -12345, Extend: &models.AccountExtend{CreditCardStatementDate: &statementDate}},
+			{AccountId: 1003, Uid: 1234567890, LedgerId: 100, Category: models.ACCOUNT_CATEGORY_CASH, Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Name: "Cash", Currency: "USD", Balance: 100000},
+		},
+		Categories: []*models.TransactionCategory{
+			{CategoryId: 2001, Uid: 1234567890, LedgerId: 100, Type: models.CATEGORY_TYPE_EXPENSE, Name: "Food", Icon: 1, Color: "ff0000"},
+			{CategoryId: 2002, Uid: 1234567890, LedgerId: 100, Type: models.CATEGORY_TYPE_EXPENSE, ParentCategoryId: 2001, Name: "Lunch", Icon: 2, Color: "00ff00"},
+		},
+		TagGroups: []*models.TransactionTagGroup{
+			{TagGroupId: 2501, Uid: 1234567890, LedgerId: 100, Name: "Group"},
+		},
+		Tags: []*models.TransactionTag{
+			{TagId: 3001, Uid: 1234567890, LedgerId: 100, TagGroupId: 2501, Name: "Tag"},
+			{TagId: 3002, Uid: 1234567890, LedgerId: 100, TagGroupId: 2501, ParentTagId: 3001, Name: "Child Tag"},
+		},
+		CustomFields: []*models.TransactionCustomField{
+			{FieldId: 3501, Uid: 1234567890, LedgerId: 100, Name: "Invoice", Type: models.TRANSACTION_CUSTOM_FIELD_TYPE_TEXT},
+		},
+		Contacts: []*models.Contact{
+			{ContactId: 3601, Uid: 1234567890, LedgerId: 100, Name: "Alice"},
+		},
+		Events: []*models.Event{
+			{EventId: 3701, Uid: 1234567890, LedgerId: 100, Name: "Trip", ParticipantContactIds: "3601"},
+		},
+		Transactions: []*models.Transaction{
+			{TransactionId: 6001, Uid: 1234567890, LedgerId: 100, Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 2002, AccountId: 1002, TransactionTime: 1725120000000, TimezoneUtcOffset: 480, Amount: 12345, Comment: "Lunch", CreatorUid: 1234567890, EventId: 3701},
+			{TransactionId: 6002, Uid: 1234567890, LedgerId: 100, Type: models.TRANSACTION_DB_TYPE_TRANSFER_OUT, CategoryId: 0, AccountId: 1003, TransactionTime: 1725120001000, Amount: 100, RelatedId: 6003, RelatedAccountId: 1002, RelatedAccountAmount: 100},
+			{TransactionId: 6003, Uid: 1234567890, LedgerId: 100, Type: models.TRANSACTION_DB_TYPE_TRANSFER_IN, CategoryId: 0, AccountId: 1002, TransactionTime: 1725120001001, Amount: 100, RelatedId: 6002, RelatedAccountId: 1003, RelatedAccountAmount: 100},
+		},
+		TagIndexes: []*models.TransactionTagIndex{
+			{TagIndexId: 7001, Uid: 1234567890, TransactionTime: 1725120000000, TagId: 3002, TransactionId: 6001},
+		},
+		CustomFieldValues: []*models.TransactionCustomFieldValue{
+			{TransactionId: 6001, FieldId: 3501, Uid: 1234567890, TextValue: "INV-001"},
+		},
+		ContactTransactions: []*models.ContactTransaction{
+			{TransactionId: 6001, Uid: 1234567890, ContactId: 3601, LedgerId: 100, AccountId: 1002, Currency: "USD", Amount: 6000},
+		},
+		Templates: []*models.TransactionTemplate{
+			{TemplateId: 8001, Uid: 1234567890, LedgerId: 100, TemplateType: models.TRANSACTION_TEMPLATE_TYPE_SCHEDULE, Name: "Monthly", Type: models.TRANSACTION_TYPE_EXPENSE, CategoryId: 2002, AccountId: 1003, ScheduledFrequencyType: models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY, ScheduledFrequency: "1", TagIds: "3001,3002", Amount: 500},
+		},
+		Pictures: []*models.TransactionPictureInfo{
+			{PictureId: 4001, Uid: 1234567890, TransactionId: 6001, PictureExtension: "jpg", StoredPictureId: 4001},
+		},
+		Attachments: []*models.TransactionAttachment{
+			{AttachmentId: 5001, Uid: 1234567890, TransactionId: 6001, FileName: "invoice.pdf", FileExtension: "pdf", FileSize: 10, HasThumbnail: true},
+		},
+		CustomExchangeRates: []*models.UserCustomExchangeRate{
+			{Uid: 1234567890, Currency: "EUR", Rate: 90000000},
+		},
+		ApplicationCloudSettings: &models.UserApplicationCloudSetting{
+			Uid: 1234567890,
+			Settings: models.ApplicationCloudSettingSlice{
+				{SettingKey: "showAccountBalance", SettingValue: "true"},
+			},
+		},
+	}
+
+	backup.PictureFiles[4001] = []byte("picture")
+	backup.AttachmentFiles[5001] = []byte("attachment")
+	backup.AttachmentThumbnailFiles[5001] = []byte("thumbnail")
+
+	return backup
+}
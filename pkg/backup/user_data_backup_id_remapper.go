@@ -0,0 +1,383 @@
+package backup
+
+import (
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+	"github.com/mayswind/ezbookkeeping/pkg/uuid"
+)
+
+type userDataBackupIdRemapper struct {
+	generator uuid.UuidGenerator
+	idMaps    map[uuid.UuidType]map[int64]int64
+}
+
+type userDataBackupIdReference struct {
+	uuidType uuid.UuidType
+	id       *int64
+}
+
+// RemapUserDataBackupIds replaces all ids in user data backup with new generated ids and replaces the owner of all data with the specified user,
+// it returns error if any data in backup references an id which does not exist in backup
+func RemapUserDataBackupIds(backup *UserDataBackup, uid int64, generator uuid.UuidGenerator) error {
+	if backup == nil || backup.Content == nil {
+		return errs.ErrBackupFileInvalid
+	}
+
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	r := &userDataBackupIdRemapper{
+		generator: generator,
+		idMaps:    make(map[uuid.UuidType]map[int64]int64),
+	}
+
+	content := backup.Content
+
+	for i := 0; i < len(content.Ledgers); i++ {
+		ledger := content.Ledgers[i]
+		ledger.Uid = uid
+
+		if err := r.generateNewId(uuid.UUID_TYPE_LEDGER, &ledger.LedgerId); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(content.Accounts); i++ {
+		if err := r.generateNewId(uuid.UUID_TYPE_ACCOUNT, &content.Accounts[i].AccountId); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(content.Accounts); i++ {
+		account := content.Accounts[i]
+		account.Uid = uid
+
+		if err := r.replaceIds([]userDataBackupIdReference{{uuid.UUID_TYPE_LEDGER, &account.LedgerId}, {uuid.UUID_TYPE_ACCOUNT, &account.ParentAccountId}}); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(content.Categories); i++ {
+		if err := r.generateNewId(uuid.UUID_TYPE_CATEGORY, &content.Categories[i].CategoryId); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(content.Categories); i++ {
+		category := content.Categories[i]
+		category.Uid = uid
+
+		if err := r.replaceIds([]userDataBackupIdReference{{uuid.UUID_TYPE_LEDGER, &category.LedgerId}, {uuid.UUID_TYPE_CATEGORY, &category.ParentCategoryId}}); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(content.TagGroups); i++ {
+		tagGroup := content.TagGroups[i]
+		tagGroup.Uid = uid
+
+		if err := r.generateNewId(uuid.UUID_TYPE_TAG_GROUP, &tagGroup.TagGroupId); err != nil {
+			return err
+		}
+
+		if err := r.replaceIds([]userDataBackupIdReference{{uuid.UUID_TYPE_LEDGER, &tagGroup.LedgerId}}); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(content.Tags); i++ {
+		if err := r.generateNewId(uuid.UUID_TYPE_TAG, &content.Tags[i].TagId); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(content.Tags); i++ {
+		tag := content.Tags[i]
+		tag.Uid = uid
+		tag.TagGroupId = r.getNewIdOrZero(uuid.UUID_TYPE_TAG_GROUP, tag.TagGroupId)
+		tag.ParentTagId = r.getNewIdOrZero(uuid.UUID_TYPE_TAG, tag.ParentTagId)
+
+		if err := r.replaceIds([]userDataBackupIdReference{{uuid.UUID_TYPE_LEDGER, &tag.LedgerId}}); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(content.CustomFields); i++ {
+		customField := content.CustomFields[i]
+		customField.Uid = uid
+
+		if err := r.generateNewId(uuid.UUID_TYPE_CUSTOM_FIELD, &customField.FieldId); err != nil {
+			return err
+		}
+
+		if err := r.replaceIds([]userDataBackupIdReference{{uuid.UUID_TYPE_LEDGER, &customField.LedgerId}}); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(content.Contacts); i++ {
+		contact := content.Contacts[i]
+		contact.Uid = uid
+
+		if err := r.generateNewId(uuid.UUID_TYPE_CONTACT, &contact.ContactId); err != nil {
+			return err
+		}
+
+		if err := r.replaceIds([]userDataBackupIdReference{{uuid.UUID_TYPE_LEDGER, &contact.LedgerId}}); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(content.Events); i++ {
+		event := content.Events[i]
+		event.Uid = uid
+		event.ParticipantContactIds = r.getNewIdsString(uuid.UUID_TYPE_CONTACT, event.ParticipantContactIds)
+
+		if err := r.generateNewId(uuid.UUID_TYPE_EVENT, &event.EventId); err != nil {
+			return err
+		}
+
+		if err := r.replaceIds([]userDataBackupIdReference{{uuid.UUID_TYPE_LEDGER, &event.LedgerId}}); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(content.Transactions); i++ {
+		if err := r.generateNewId(uuid.UUID_TYPE_TRANSACTION, &content.Transactions[i].TransactionId); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(content.Transactions); i++ {
+		transaction := content.Transactions[i]
+		transaction.Uid = uid
+		transaction.CreatorUid = uid
+		transaction.EventId = r.getNewIdOrZero(uuid.UUID_TYPE_EVENT, transaction.EventId)
+
+		err := r.replaceIds([]userDataBackupIdReference{
+			{uuid.UUID_TYPE_LEDGER, &transaction.LedgerId},
+			{uuid.UUID_TYPE_TRANSACTION, &transaction.RelatedId},
+			{uuid.UUID_TYPE_ACCOUNT, &transaction.AccountId},
+			{uuid.UUID_TYPE_ACCOUNT, &transaction.RelatedAccountId},
+			{uuid.UUID_TYPE_CATEGORY, &transaction.CategoryId},
+		})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(content.TagIndexes); i++ {
+		tagIndex := content.TagIndexes[i]
+		tagIndex.Uid = uid
+
+		if err := r.generateNewId(uuid.UUID_TYPE_TAG_INDEX, &tagIndex.TagIndexId); err != nil {
+			return err
+		}
+
+		if err := r.replaceIds([]userDataBackupIdReference{{uuid.UUID_TYPE_TAG, &tagIndex.TagId}, {uuid.UUID_TYPE_TRANSACTION, &tagIndex.TransactionId}}); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(content.CustomFieldValues); i++ {
+		customFieldValue := content.CustomFieldValues[i]
+		customFieldValue.Uid = uid
+
+		if err := r.replaceIds([]userDataBackupIdReference{{uuid.UUID_TYPE_TRANSACTION, &customFieldValue.TransactionId}, {uuid.UUID_TYPE_CUSTOM_FIELD, &customFieldValue.FieldId}}); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(content.ContactTransactions); i++ {
+		contactTransaction := content.ContactTransactions[i]
+		contactTransaction.Uid = uid
+
+		err := r.replaceIds([]userDataBackupIdReference{
+			{uuid.UUID_TYPE_TRANSACTION, &contactTransaction.TransactionId},
+			{uuid.UUID_TYPE_CONTACT, &contactTransaction.ContactId},
+			{uuid.UUID_TYPE_LEDGER, &contactTransaction.LedgerId},
+			{uuid.UUID_TYPE_ACCOUNT, &contactTransaction.AccountId},
+		})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(content.Templates); i++ {
+		template := content.Templates[i]
+		template.Uid = uid
+		template.TagIds = r.getNewIdsString(uuid.UUID_TYPE_TAG, template.TagIds)
+
+		if err := r.generateNewId(uuid.UUID_TYPE_TEMPLATE, &template.TemplateId); err != nil {
+			return err
+		}
+
+		err := r.replaceIds([]userDataBackupIdReference{
+			{uuid.UUID_TYPE_LEDGER, &template.LedgerId},
+			{uuid.UUID_TYPE_CATEGORY, &template.CategoryId},
+			{uuid.UUID_TYPE_ACCOUNT, &template.AccountId},
+			{uuid.UUID_TYPE_ACCOUNT, &template.RelatedAccountId},
+		})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	pictureFiles := make(map[int64][]byte, len(backup.PictureFiles))
+
+	for i := 0; i < len(content.Pictures); i++ {
+		pictureInfo := content.Pictures[i]
+		oldPictureId := pictureInfo.PictureId
+		pictureInfo.Uid = uid
+		pictureInfo.StoredPictureId = 0
+
+		if !isFileExtensionValid(pictureInfo.PictureExtension) {
+			return errs.ErrBackupFileInvalid
+		}
+
+		if err := r.generateNewId(uuid.UUID_TYPE_PICTURE, &pictureInfo.PictureId); err != nil {
+			return err
+		}
+
+		if err := r.replaceIds([]userDataBackupIdReference{{uuid.UUID_TYPE_TRANSACTION, &pictureInfo.TransactionId}}); err != nil {
+			return err
+		}
+
+		if pictureData, exists := backup.PictureFiles[oldPictureId]; exists {
+			pictureFiles[pictureInfo.PictureId] = pictureData
+		}
+	}
+
+	attachmentFiles := make(map[int64][]byte, len(backup.AttachmentFiles))
+	attachmentThumbnailFiles := make(map[int64][]byte, len(backup.AttachmentThumbnailFiles))
+
+	for i := 0; i < len(content.Attachments); i++ {
+		attachment := content.Attachments[i]
+		oldAttachmentId := attachment.AttachmentId
+		attachment.Uid = uid
+
+		if !isFileExtensionValid(attachment.FileExtension) {
+			return errs.ErrBackupFileInvalid
+		}
+
+		if err := r.generateNewId(uuid.UUID_TYPE_ATTACHMENT, &attachment.AttachmentId); err != nil {
+			return err
+		}
+
+		if err := r.replaceIds([]userDataBackupIdReference{{uuid.UUID_TYPE_TRANSACTION, &attachment.TransactionId}}); err != nil {
+			return err
+		}
+
+		if attachmentData, exists := backup.AttachmentFiles[oldAttachmentId]; exists {
+			attachmentFiles[attachment.AttachmentId] = attachmentData
+		}
+
+		if thumbnailData, exists := backup.AttachmentThumbnailFiles[oldAttachmentId]; exists {
+			attachmentThumbnailFiles[attachment.AttachmentId] = thumbnailData
+		}
+	}
+
+	backup.PictureFiles = pictureFiles
+	backup.AttachmentFiles = attachmentFiles
+	backup.AttachmentThumbnailFiles = attachmentThumbnailFiles
+
+	for i := 0; i < len(content.CustomExchangeRates); i++ {
+		content.CustomExchangeRates[i].Uid = uid
+	}
+
+	if content.ApplicationCloudSettings != nil {
+		content.ApplicationCloudSettings.Uid = uid
+	}
+
+	return nil
+}
+
+func (r *userDataBackupIdRemapper) generateNewId(uuidType uuid.UuidType, id *int64) error {
+	newId := r.generator.GenerateUuid(uuidType)
+
+	if newId < 1 {
+		return errs.ErrSystemIsBusy
+	}
+
+	idMap, exists := r.idMaps[uuidType]
+
+	if !exists {
+		idMap = make(map[int64]int64)
+		r.idMaps[uuidType] = idMap
+	}
+
+	if _, exists := idMap[*id]; exists || *id <= 0 {
+		return errs.ErrBackupFileInvalid
+	}
+
+	idMap[*id] = newId
+	*id = newId
+
+	return nil
+}
+
+// replaceIds replaces the referenced ids with new generated ids, the id which is zero means no reference and is kept as it is
+func (r *userDataBackupIdRemapper) replaceIds(references []userDataBackupIdReference) error {
+	for i := 0; i < len(references); i++ {
+		id := references[i].id
+
+		if *id == 0 {
+			continue
+		}
+
+		newId, exists := r.idMaps[references[i].uuidType][*id]
+
+		if !exists {
+			return errs.ErrBackupFileInvalid
+		}
+
+		*id = newId
+	}
+
+	return nil
+}
+
+func (r *userDataBackupIdRemapper) getNewIdOrZero(uuidType uuid.UuidType, id int64) int64 {
+	if id == 0 {
+		return 0
+	}
+
+	return r.idMaps[uuidType][id]
+}
+
+func (r *userDataBackupIdRemapper) getNewIdsString(uuidType uuid.UuidType, ids string) string {
+	if ids == "" {
+		return ids
+	}
+
+	items := strings.Split(ids, ",")
+	newItems := make([]string, 0, len(items))
+
+	for i := 0; i < len(items); i++ {
+		id, err := utils.StringToInt64(items[i])
+
+		if err != nil {
+			continue
+		}
+
+		newId := r.getNewIdOrZero(uuidType, id)
+
+		if newId == 0 {
+			continue
+		}
+
+		newItems = append(newItems, utils.Int64ToString(newId))
+	}
+
+	return strings.Join(newItems, ",")
+}
+
+func isFileExtensionValid(fileExtension string) bool {
+	return fileExtension != "" && !strings.ContainsAny(fileExtension, "/\\") && !strings.Contains(fileExtension, "..")
+}
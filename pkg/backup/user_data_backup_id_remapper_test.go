@@ -0,0 +1,182 @@
+package backup
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+	"github.com/mayswind/ezbookkeeping/pkg/uuid"
+)
+
+type testSequenceUuidGenerator struct {
+	lastId int64
+}
+
+func (g *testSequenceUuidGenerator) GenerateUuid(uuidType uuid.UuidType) int64 {
+	g.lastId++
+	return int64(uuidType)*1000000 + g.lastId
+}
+
+func (g *testSequenceUuidGenerator) GenerateUuids(uuidType uuid.UuidType, count uint16) []int64 {
+	uuids := make([]int64, count)
+
+	for i := 0; i < int(count); i++ {
+		uuids[i] = g.GenerateUuid(uuidType)
+	}
+
+	return uuids
+}
+
+func TestRemapUserDataBackupIds(t *testing.T) {
+	backup := createTestUserDataBackup()
+	err := RemapUserDataBackupIds(backup, 9876543210, &testSequenceUuidGenerator{})
+	assert.Nil(t, err)
+
+	content := backup.Content
+
+	ledgerId := content.Ledgers[0].LedgerId
+	assert.Equal(t, int64(9000001), ledgerId)
+	assert.Equal(t, int64(9876543210), content.Ledgers[0].Uid)
+
+	assert.Equal(t, int64(2000002), content.Accounts[0].AccountId)
+	assert.Equal(t, int64(2000003), content.Accounts[1].AccountId)
+	assert.Equal(t, int64(2000004), content.Accounts[2].AccountId)
+	assert.Equal(t, int64(0), content.Accounts[0].ParentAccountId)
+	assert.Equal(t, content.Accounts[0].AccountId, content.Accounts[1].ParentAccountId)
+	assert.Equal(t, ledgerId, content.Accounts[1].LedgerId)
+	assert.Equal(t, int64(9876543210), content.Accounts[1].Uid)
+	assert.Equal(t, 15, *content.Accounts[1].Extend.CreditCardStatementDate)
+
+	assert.Equal(t, int64(0), content.Categories[0].ParentCategoryId)
+	assert.Equal(t, content.Categories[0].CategoryId, content.Categories[1].ParentCategoryId)
+
+	assert.Equal(t, content.TagGroups[0].TagGroupId, content.Tags[0].TagGroupId)
+	assert.Equal(t, content.TagGroups[0].TagGroupId, content.Tags[1].TagGroupId)
+	assert.Equal(t, int64(0), content.Tags[0].ParentTagId)
+	assert.Equal(t, content.Tags[0].TagId, content.Tags[1].ParentTagId)
+
+	assert.Equal(t, utils.Int64ToString(content.Contacts[0].ContactId), content.Events[0].ParticipantContactIds)
+
+	expenseTransaction := content.Transactions[0]
+	assert.Equal(t, int64(9876543210), expenseTransaction.Uid)
+	assert.Equal(t, int64(9876543210), expenseTransaction.CreatorUid)
+	assert.Equal(t, ledgerId, expenseTransaction.LedgerId)
+	assert.Equal(t, content.Categories[1].CategoryId, expenseTransaction.CategoryId)
+	assert.Equal(t, content.Accounts[1].AccountId, expenseTransaction.AccountId)
+	assert.Equal(t, content.Events[0].EventId, expenseTransaction.EventId)
+	assert.Equal(t, int64(1725120000000), expenseTransaction.TransactionTime)
+	assert.Equal(t, int64(12345), expenseTransaction.Amount)
+
+	transferOutTransaction := content.Transactions[1]
+	transferInTransaction := content.Transactions[2]
+	assert.Equal(t, transferInTransaction.TransactionId, transferOutTransaction.RelatedId)
+	assert.Equal(t, transferOutTransaction.TransactionId, transferInTransaction.RelatedId)
+	assert.Equal(t, content.Accounts[2].AccountId, transferOutTransaction.AccountId)
+	assert.Equal(t, content.Accounts[1].AccountId, transferOutTransaction.RelatedAccountId)
+	assert.Equal(t, int64(0), transferOutTransaction.CategoryId)
+
+	assert.Equal(t, content.Tags[1].TagId, content.TagIndexes[0].TagId)
+	assert.Equal(t, expenseTransaction.TransactionId, content.TagIndexes[0].TransactionId)
+	assert.NotEqual(t, int64(7001), content.TagIndexes[0].TagIndexId)
+
+	assert.Equal(t, expenseTransaction.TransactionId, content.CustomFieldValues[0].TransactionId)
+	assert.Equal(t, content.CustomFields[0].FieldId, content.CustomFieldValues[0].FieldId)
+
+	assert.Equal(t, expenseTransaction.TransactionId, content.ContactTransactions[0].TransactionId)
+	assert.Equal(t, content.Contacts[0].ContactId, content.ContactTransactions[0].ContactId)
+	assert.Equal(t, content.Accounts[1].AccountId, content.ContactTransactions[0].AccountId)
+
+	template := content.Templates[0]
+	assert.Equal(t, content.Categories[1].CategoryId, template.CategoryId)
+	assert.Equal(t, content.Accounts[2].AccountId, template.AccountId)
+	assert.Equal(t, int64(0), template.RelatedAccountId)
+	assert.Equal(t, utils.Int64ToString(content.Tags[0].TagId)+","+utils.Int64ToString(content.Tags[1].TagId), template.TagIds)
+
+	pictureInfo := content.Pictures[0]
+	assert.Equal(t, expenseTransaction.TransactionId, pictureInfo.TransactionId)
+	assert.Equal(t, int64(0), pictureInfo.StoredPictureId)
+	assert.Equal(t, []byte("picture"), backup.PictureFiles[pictureInfo.PictureId])
+	assert.Nil(t, backup.PictureFiles[4001])
+
+	attachment := content.Attachments[0]
+	assert.Equal(t, expenseTransaction.TransactionId, attachment.TransactionId)
+	assert.Equal(t, []byte("attachment"), backup.AttachmentFiles[attachment.AttachmentId])
+	assert.Equal(t, []byte("thumbnail"), backup.AttachmentThumbnailFiles[attachment.AttachmentId])
+
+	assert.Equal(t, int64(9876543210), content.CustomExchangeRates[0].Uid)
+	assert.Equal(t, int64(9876543210), content.ApplicationCloudSettings.Uid)
+}
+
+func TestRemapUserDataBackupIds_DropMissingOptionalReferences(t *testing.T) {
+	backup := createTestUserDataBackup()
+	backup.Content.Transactions[0].EventId = 9999
+	backup.Content.Tags[1].ParentTagId = 9999
+	backup.Content.Templates[0].TagIds = "3001,9999,abc"
+	backup.Content.Events[0].ParticipantContactIds = "9999"
+
+	err := RemapUserDataBackupIds(backup, 9876543210, &testSequenceUuidGenerator{})
+	assert.Nil(t, err)
+
+	assert.Equal(t, int64(0), backup.Content.Transactions[0].EventId)
+	assert.Equal(t, int64(0), backup.Content.Tags[1].ParentTagId)
+	assert.Equal(t, utils.Int64ToString(backup.Content.Tags[0].TagId), backup.Content.Templates[0].TagIds)
+	assert.Equal(t, "", backup.Content.Events[0].ParticipantContactIds)
+}
+
+func TestRemapUserDataBackupIds_MissingRequiredReference(t *testing.T) {
+	backup := createTestUserDataBackup()
+	backup.Content.Transactions[0].AccountId = 9999
+
+	err := RemapUserDataBackupIds(backup, 9876543210, &testSequenceUuidGenerator{})
+	assert.EqualError(t, err, errs.ErrBackupFileInvalid.Message)
+
+	backup = createTestUserDataBackup()
+	backup.Content.TagIndexes[0].TagId = 9999
+
+	err = RemapUserDataBackupIds(backup, 9876543210, &testSequenceUuidGenerator{})
+	assert.EqualError(t, err, errs.ErrBackupFileInvalid.Message)
+}
+
+func TestRemapUserDataBackupIds_DuplicateId(t *testing.T) {
+	backup := createTestUserDataBackup()
+	backup.Content.Accounts[2].AccountId = 1001
+
+	err := RemapUserDataBackupIds(backup, 9876543210, &testSequenceUuidGenerator{})
+	assert.EqualError(t, err, errs.ErrBackupFileInvalid.Message)
+}
+
+func TestRemapUserDataBackupIds_InvalidFileExtension(t *testing.T) {
+	backup := createTestUserDataBackup()
+	backup.Content.Attachments[0].FileExtension = "../pdf"
+
+	err := RemapUserDataBackupIds(backup, 9876543210, &testSequenceUuidGenerator{})
+	assert.EqualError(t, err, errs.ErrBackupFileInvalid.Message)
+}
+
+func TestRemapUserDataBackupIds_InvalidUid(t *testing.T) {
+	backup := createTestUserDataBackup()
+
+	err := RemapUserDataBackupIds(backup, 0, &testSequenceUuidGenerator{})
+	assert.EqualError(t, err, errs.ErrUserIdInvalid.Message)
+}
+
+func TestRemapUserDataBackupIds_SystemIsBusy(t *testing.T) {
+	backup := createTestUserDataBackup()
+	backup.Content.Ledgers = []*models.Ledger{}
+
+	err := RemapUserDataBackupIds(backup, 9876543210, &testZeroUuidGenerator{})
+	assert.EqualError(t, err, errs.ErrSystemIsBusy.Message)
+}
+
+type testZeroUuidGenerator struct{}
+
+func (g *testZeroUuidGenerator) GenerateUuid(uuidType uuid.UuidType) int64 {
+	return 0
+}
+
+func (g *testZeroUuidGenerator) GenerateUuids(uuidType uuid.UuidType, count uint16) []int64 {
+	return make([]int64, count)
+}
@@ -0,0 +1,168 @@
+package cli
+
+import (
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/storage"
+)
+
+// StorageCli represents object storage cli
+type StorageCli struct {
+	CliUsingConfig
+	storageMigrations *services.StorageMigrationService
+}
+
+// Initialize an object storage cli singleton instance
+var (
+	Storage = &StorageCli{
+		CliUsingConfig: CliUsingConfig{
+			container: settings.Container,
+		},
+		storageMigrations: services.StorageMigrations,
+	}
+)
+
+// MigrateObjects copies all avatars, transaction pictures and transaction attachments from the source storage type to the target storage type
+func (l *StorageCli) MigrateObjects(c *core.CliContext, fromStorageType string, toStorageType string, dryRun bool) (*storage.StorageMigrationSummary, error) {
+	if fromStorageType == toStorageType {
+		log.CliErrorf(c, "[storage.MigrateObjects] source storage type and target storage type are both \"%s\"", fromStorageType)
+		return nil, errs.ErrSourceAndTargetStorageTypeEqual
+	}
+
+	config := l.CurrentConfig()
+	from, err := storage.NewStorageContainer(config, fromStorageType)
+
+	if err != nil {
+		log.CliErrorf(c, "[storage.MigrateObjects] failed to initialize source storage \"%s\", because %s", fromStorageType, err.Error())
+		return nil, err
+	}
+
+	to, err := storage.NewStorageContainer(config, toStorageType)
+
+	if err != nil {
+		log.CliErrorf(c, "[storage.MigrateObjects] failed to initialize target storage \"%s\", because %s", toStorageType, err.Error())
+		return nil, err
+	}
+
+	summary := &storage.StorageMigrationSummary{}
+
+	if config.AvatarProvider == core.USER_AVATAR_PROVIDER_INTERNAL {
+		err = l.storageMigrations.MigrateAvatars(c, from, to, dryRun, &summary.Avatar)
+
+		if err != nil {
+			log.CliErrorf(c, "[storage.MigrateObjects] failed to migrate avatars, because %s", err.Error())
+			return nil, err
+		}
+	}
+
+	if config.EnableTransactionPictures {
+		err = l.storageMigrations.MigrateTransactionPictures(c, from, to, dryRun, &summary.TransactionPicture)
+
+		if err != nil {
+			log.CliErrorf(c, "[storage.MigrateObjects] failed to migrate transaction pictures, because %s", err.Error())
+			return nil, err
+		}
+	}
+
+	if config.EnableTransactionAttachments {
+		err = l.storageMigrations.MigrateTransactionAttachments(c, from, to, dryRun, &summary.TransactionAttachment)
+
+		if err != nil {
+			log.CliErrorf(c, "[storage.MigrateObjects] failed to migrate transaction attachments, because %s", err.Error())
+			return nil, err
+		}
+	}
+
+	return summary, nil
+}
+
+// EncryptObjects encrypts all avatars, transaction pictures and transaction attachments which are not encrypted in the current storage in place
+func (l *StorageCli) EncryptObjects(c *core.CliContext, dryRun bool) (*storage.StorageEncryptionSummary, error) {
+	container, err := l.getEncryptedStorageContainer(c)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return l.encryptAllObjects(c, container, "encrypt", func(objectStorage *storage.EncryptedObjectStorage, path string) (storage.ObjectEncryptionResult, error) {
+		return objectStorage.EncryptExistingObject(c, path, dryRun)
+	})
+}
+
+// RotateObjectMasterKeys re-wraps the data keys of all encrypted objects in the current storage which are encrypted by the previous master key with the current master key
+func (l *StorageCli) RotateObjectMasterKeys(c *core.CliContext, dryRun bool) (*storage.StorageEncryptionSummary, error) {
+	container, err := l.getEncryptedStorageContainer(c)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return l.encryptAllObjects(c, container, "rotate master key of", func(objectStorage *storage.EncryptedObjectStorage, path string) (storage.ObjectEncryptionResult, error) {
+		return objectStorage.RotateObjectMasterKey(c, path, dryRun)
+	})
+}
+
+func (l *StorageCli) getEncryptedStorageContainer(c *core.CliContext) (*storage.StorageContainer, error) {
+	if len(l.CurrentConfig().StorageEncryptionMasterKey) < 1 {
+		log.CliErrorf(c, "[storage.getEncryptedStorageContainer] storage encryption master key is not set")
+		return nil, errs.ErrStorageEncryptionNotEnabled
+	}
+
+	container, err := storage.NewStorageContainer(l.CurrentConfig(), l.CurrentConfig().StorageType)
+
+	if err != nil {
+		log.CliErrorf(c, "[storage.getEncryptedStorageContainer] failed to initialize storage \"%s\", because %s", l.CurrentConfig().StorageType, err.Error())
+		return nil, err
+	}
+
+	return container, nil
+}
+
+func (l *StorageCli) encryptAllObjects(c *core.CliContext, container *storage.StorageContainer, operation string, fn func(objectStorage *storage.EncryptedObjectStorage, path string) (storage.ObjectEncryptionResult, error)) (*storage.StorageEncryptionSummary, error) {
+	summary := &storage.StorageEncryptionSummary{}
+
+	for _, objectType := range storage.AllStorageObjectTypes {
+		objectStorage, ok := container.GetObjectStorage(objectType).(*storage.EncryptedObjectStorage)
+
+		if !ok {
+			continue
+		}
+
+		paths, err := l.storageMigrations.GetAllObjectPaths(c, objectType)
+
+		if err != nil {
+			log.CliErrorf(c, "[storage.encryptAllObjects] failed to get all object paths of \"%s\", because %s", objectType, err.Error())
+			return nil, err
+		}
+
+		objectSummary := summary.Get(objectType)
+
+		for i := 0; i < len(paths); i++ {
+			path := paths[i]
+			result, err := fn(objectStorage, path.Path)
+
+			if result == storage.OBJECT_ENCRYPTION_RESULT_NOT_EXISTS && path.Optional {
+				continue
+			}
+
+			if err != nil {
+				log.CliErrorf(c, "[storage.encryptAllObjects] failed to %s %s object \"%s\", because %s", operation, objectType, path.Path, err.Error())
+			} else if result == storage.OBJECT_ENCRYPTION_RESULT_ENCRYPTED {
+				log.CliInfof(c, "[storage.encryptAllObjects] %s object \"%s\" has been encrypted", objectType, path.Path)
+			} else if result == storage.OBJECT_ENCRYPTION_RESULT_ROTATED {
+				log.CliInfof(c, "[storage.encryptAllObjects] master key of %s object \"%s\" has been rotated", objectType, path.Path)
+			} else if result == storage.OBJECT_ENCRYPTION_RESULT_PENDING {
+				log.CliInfof(c, "[storage.encryptAllObjects] %s object \"%s\" would be processed", objectType, path.Path)
+			} else if result == storage.OBJECT_ENCRYPTION_RESULT_NOT_EXISTS {
+				log.CliWarnf(c, "[storage.encryptAllObjects] %s object \"%s\" does not exist", objectType, path.Path)
+			}
+
+			objectSummary.Add(result)
+		}
+	}
+
+	return summary, nil
+}
@@ -1,9 +1,12 @@
 package cli
 
 import (
+	"io"
+	"math"
 	"strings"
 	"time"
 
+	"github.com/mayswind/ezbookkeeping/pkg/backup"
 	"github.com/mayswind/ezbookkeeping/pkg/converters"
 	"github.com/mayswind/ezbookkeeping/pkg/converters/converter"
 	"github.com/mayswind/ezbookkeeping/pkg/core"
@@ -22,14 +25,20 @@ const pageCountForDataExport = 1000
 // UserDataCli represents user data cli
 type UserDataCli struct {
 	CliUsingConfig
+	ledgerAccesses          *services.LedgerAccessService
+	ledgers                 *services.LedgerService
 	accounts                *services.AccountService
 	transactions            *services.TransactionService
 	categories              *services.TransactionCategoryService
 	tags                    *services.TransactionTagService
+	customFields            *services.TransactionCustomFieldService
+	pictures                *services.TransactionPictureService
 	users                   *services.UserService
+	userQuotas              *services.UserQuotaService
 	twoFactorAuthorizations *services.TwoFactorAuthorizationService
 	tokens                  *services.TokenService
 	forgetPasswords         *services.ForgetPasswordService
+	userDataBackups         *services.UserDataBackupService
 }
 
 // Initialize a user data cli singleton instance
@@ -38,14 +47,20 @@ var (
 		CliUsingConfig: CliUsingConfig{
 			container: settings.Container,
 		},
+		ledgerAccesses:          services.LedgerAccesses,
+		ledgers:                 services.Ledgers,
 		accounts:                services.Accounts,
 		transactions:            services.Transactions,
 		categories:              services.TransactionCategories,
 		tags:                    services.TransactionTags,
+		customFields:            services.TransactionCustomFields,
+		pictures:                services.TransactionPictures,
 		users:                   services.Users,
+		userQuotas:              services.UserQuotas,
 		twoFactorAuthorizations: services.TwoFactorAuthorizations,
 		tokens:                  services.Tokens,
 		forgetPasswords:         services.ForgetPasswords,
+		userDataBackups:         services.UserDataBackups,
 	}
 )
 
@@ -101,6 +116,13 @@ func (l *UserDataCli) AddNewUser(c *core.CliContext, username string, email stri
 
 	log.CliInfof(c, "[user_data.AddNewUser] user \"%s\" has add successfully, uid is %d", user.Username, user.Uid)
 
+	_, err = l.ledgers.GetOrCreateDefaultLedger(c, user.Uid)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.AddNewUser] failed to create default ledger for user \"%s\", because %s", user.Username, err.Error())
+		return nil, err
+	}
+
 	return user, nil
 }
 
@@ -256,6 +278,35 @@ func (l *UserDataCli) SetUserFeatureRestrictions(c *core.CliContext, username st
 	return nil
 }
 
+// SetUserQuotas sets user storage quota and transaction quota according to the specified user name
+func (l *UserDataCli) SetUserQuotas(c *core.CliContext, username string, storageQuota int64, transactionQuota int64) error {
+	if username == "" {
+		log.CliErrorf(c, "[user_data.SetUserQuotas] user name is empty")
+		return errs.ErrUsernameIsEmpty
+	}
+
+	err := l.userQuotas.UpdateUserQuotas(c, username, storageQuota, transactionQuota)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.SetUserQuotas] failed to set user quotas by user name \"%s\", because %s", username, err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// GetUserQuotaUsage returns the storage and transaction usage and the effective quotas of the specified user
+func (l *UserDataCli) GetUserQuotaUsage(c *core.CliContext, user *models.User) (*models.UserQuotaUsage, error) {
+	usage, err := l.userQuotas.GetUserQuotaUsage(c, user)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.GetUserQuotaUsage] failed to get quota usage of user \"uid:%d\", because %s", user.Uid, err.Error())
+		return nil, err
+	}
+
+	return usage, nil
+}
+
 // AddUserFeatureRestrictions adds user feature restrictions according to the specified user name
 func (l *UserDataCli) AddUserFeatureRestrictions(c *core.CliContext, username string, featureRestriction core.UserFeatureRestrictions) error {
 	if username == "" {
@@ -371,13 +422,27 @@ func (l *UserDataCli) DeleteUser(c *core.CliContext, username string) error {
 		return errs.ErrUsernameIsEmpty
 	}
 
-	err := l.users.DeleteUser(c, username)
+	uid, err := l.getUserIdByUsername(c, username)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.DeleteUser] error occurs when getting user id by user name")
+		return err
+	}
+
+	err = l.users.DeleteUser(c, username)
 
 	if err != nil {
 		log.CliErrorf(c, "[user_data.DeleteUser] failed to delete user by user name \"%s\", because %s", username, err.Error())
 		return err
 	}
 
+	err = l.pictures.DeleteAllPictures(c, uid)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.DeleteUser] failed to delete all transaction pictures of user \"uid:%d\", because %s", uid, err.Error())
+		return err
+	}
+
 	return nil
 }
 
@@ -750,47 +815,138 @@ func (l *UserDataCli) FixTransactionTagIndexWithTransactionTime(c *core.CliConte
 }
 
 // ExportTransaction returns csv file content according user all transactions
-func (l *UserDataCli) ExportTransaction(c *core.CliContext, username string, fileType string) ([]byte, error) {
+func (l *UserDataCli) ExportTransaction(c *core.CliContext, username string, fileType string, exportTransactionDataReq *models.ExportTransactionDataRequest, writer io.Writer) error {
 	if username == "" {
 		log.CliErrorf(c, "[user_data.ExportTransaction] user name is empty")
-		return nil, errs.ErrUsernameIsEmpty
+		return errs.ErrUsernameIsEmpty
 	}
 
 	uid, err := l.getUserIdByUsername(c, username)
 
 	if err != nil {
 		log.CliErrorf(c, "[user_data.ExportTransaction] error occurs when getting user id by user name")
-		return nil, err
+		return err
+	}
+
+	exporterOptions, err := converter.ParseExporterOptions(exportTransactionDataReq.Timezone, exportTransactionDataReq.Columns)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.ExportTransaction] failed to parse exporter options, because %s", err.Error())
+		return err
+	}
+
+	allAccountIds, err := l.accounts.GetAccountOrSubAccountIds(c, exportTransactionDataReq.AccountIds, uid)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.ExportTransaction] failed to get accounts for user \"%s\", because %s", username, err.Error())
+		return err
+	}
+
+	allCategoryIds, err := l.categories.GetCategoryOrSubCategoryIds(c, exportTransactionDataReq.CategoryIds, uid)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.ExportTransaction] failed to get categories for user \"%s\", because %s", username, err.Error())
+		return err
+	}
+
+	noTags := exportTransactionDataReq.TagFilter == models.TransactionNoTagFilterValue
+	var tagFilters []*models.TransactionTagFilter
+
+	if !noTags {
+		tagFilters, err = models.ParseTransactionTagFilter(exportTransactionDataReq.TagFilter)
+
+		if err != nil {
+			log.CliErrorf(c, "[user_data.ExportTransaction] failed to parse transaction tag filters, because %s", err.Error())
+			return err
+		}
+	}
+
+	maxTransactionTime := int64(math.MaxInt64)
+	minTransactionTime := int64(0)
+
+	if exportTransactionDataReq.MaxTime > 0 {
+		maxTransactionTime = utils.GetMaxTransactionTimeFromUnixTime(exportTransactionDataReq.MaxTime)
+	}
+
+	if exportTransactionDataReq.MinTime > 0 {
+		minTransactionTime = utils.GetMinTransactionTimeFromUnixTime(exportTransactionDataReq.MinTime)
+	}
+
+	customFields, err := l.customFields.GetAllCustomFieldsByUid(c, l.ledgerAccesses.GetAllLedgersAccessOfOwner(uid))
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.ExportTransaction] failed to get custom fields for user \"%s\", because %s", username, err.Error())
+		return err
+	}
+
+	if dataStreamExporter := converters.GetTransactionDataStreamExporter(fileType); dataStreamExporter != nil {
+		accountMap, categoryMap, tagMap, err := l.getUserEssentialDataForExport(c, uid, username)
+
+		if err != nil {
+			log.CliErrorf(c, "[user_data.ExportTransaction] failed to get essential data for user \"%s\", because %s", username, err.Error())
+			return err
+		}
+
+		pageReader, err := l.transactions.GetExportedTransactionDataPageReader(c, l.ledgerAccesses.GetAllLedgersAccessOfOwner(uid), maxTransactionTime, minTransactionTime, exportTransactionDataReq.Type, allCategoryIds, allAccountIds, tagFilters, noTags, exportTransactionDataReq.AmountFilter, exportTransactionDataReq.Keyword, pageCountForDataExport, len(customFields) > 0)
+
+		if err != nil {
+			log.CliErrorf(c, "[user_data.ExportTransaction] failed to get transaction page reader for user \"%s\", because %s", username, err.Error())
+			return err
+		}
+
+		err = dataStreamExporter.WriteExportedContent(c, writer, uid, pageReader, accountMap, categoryMap, tagMap, customFields, exporterOptions)
+
+		if err != nil {
+			log.CliErrorf(c, "[user_data.ExportTransaction] failed to write %s format exported data for \"%s\", because %s", fileType, username, err.Error())
+			return err
+		}
+
+		return nil
+	}
+
+	// the other file types need all the transactions before writing any data, so the whole file is built in memory
+	dataExporter := converters.GetTransactionDataExporter(fileType)
+
+	if dataExporter == nil {
+		return errs.ErrNotImplemented
 	}
 
 	accountMap, categoryMap, tagMap, _, tagIndexesMap, err := l.getUserEssentialData(c, uid, username)
 
 	if err != nil {
 		log.CliErrorf(c, "[user_data.ExportTransaction] failed to get essential data for user \"%s\", because %s", username, err.Error())
-		return nil, err
+		return err
 	}
 
-	allTransactions, err := l.transactions.GetAllTransactions(c, uid, pageCountForDataExport, true)
+	allTransactions, err := l.transactions.GetAllSpecifiedTransactions(c, l.ledgerAccesses.GetAllLedgersAccessOfOwner(uid), maxTransactionTime, minTransactionTime, exportTransactionDataReq.Type, allCategoryIds, allAccountIds, tagFilters, noTags, nil, exportTransactionDataReq.AmountFilter, exportTransactionDataReq.Keyword, pageCountForDataExport, true)
 
 	if err != nil {
 		log.CliErrorf(c, "[user_data.ExportTransaction] failed to all transactions for user \"%s\", because %s", username, err.Error())
-		return nil, err
+		return err
 	}
 
-	dataExporter := converters.GetTransactionDataExporter(fileType)
+	customFieldValues, err := l.customFields.GetAllCustomFieldValuesByUid(c, uid)
 
-	if dataExporter == nil {
-		return nil, errs.ErrNotImplemented
+	if err != nil {
+		log.CliErrorf(c, "[user_data.ExportTransaction] failed to get custom field values for user \"%s\", because %s", username, err.Error())
+		return err
 	}
 
-	result, err := dataExporter.ToExportedContent(c, uid, allTransactions, accountMap, categoryMap, tagMap, tagIndexesMap)
+	result, err := dataExporter.ToExportedContent(c, uid, allTransactions, accountMap, categoryMap, tagMap, tagIndexesMap, customFields, customFieldValues, exporterOptions)
 
 	if err != nil {
-		log.CliErrorf(c, "[user_data.ExportTransaction] failed to get csv format exported data for \"%s\", because %s", username, err.Error())
-		return nil, err
+		log.CliErrorf(c, "[user_data.ExportTransaction] failed to get %s format exported data for \"%s\", because %s", fileType, username, err.Error())
+		return err
 	}
 
-	return result, nil
+	_, err = writer.Write(result)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.ExportTransaction] failed to write %s format exported data for \"%s\", because %s", fileType, username, err.Error())
+		return err
+	}
+
+	return nil
 }
 
 func (l *UserDataCli) ImportTransaction(c *core.CliContext, username string, fileType string, data []byte) error {
@@ -879,6 +1035,82 @@ func (l *UserDataCli) ImportTransaction(c *core.CliContext, username string, fil
 	return nil
 }
 
+// BackupUserData returns the backup archive content of all data of specified user
+func (l *UserDataCli) BackupUserData(c *core.CliContext, username string) ([]byte, error) {
+	if username == "" {
+		log.CliErrorf(c, "[user_data.BackupUserData] user name is empty")
+		return nil, errs.ErrUsernameIsEmpty
+	}
+
+	user, err := l.GetUserByUsername(c, username)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.BackupUserData] failed to get user by user name \"%s\", because %s", username, err.Error())
+		return nil, err
+	}
+
+	userDataBackup, err := l.userDataBackups.GetUserDataBackup(c, user)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.BackupUserData] failed to get backup data for user \"%s\", because %s", username, err.Error())
+		return nil, err
+	}
+
+	result, err := backup.WriteUserDataBackupArchive(userDataBackup)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.BackupUserData] failed to write backup archive for user \"%s\", because %s", username, err.Error())
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// RestoreUserData recreates all data in the backup archive read from the reader for specified user
+func (l *UserDataCli) RestoreUserData(c *core.CliContext, username string, reader io.ReaderAt, size int64) error {
+	if username == "" {
+		log.CliErrorf(c, "[user_data.RestoreUserData] user name is empty")
+		return errs.ErrUsernameIsEmpty
+	}
+
+	uid, err := l.getUserIdByUsername(c, username)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.RestoreUserData] error occurs when getting user id by user name")
+		return err
+	}
+
+	userDataBackup, err := backup.ReadUserDataBackupArchive(reader, size, l.CurrentConfig().MaxBackupArchiveEntrySize, l.CurrentConfig().MaxBackupArchiveTotalSize)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.RestoreUserData] failed to read backup archive, because %s", err.Error())
+		return err
+	}
+
+	if (len(userDataBackup.Content.Pictures) > 0 && !l.CurrentConfig().EnableTransactionPictures) ||
+		(len(userDataBackup.Content.Attachments) > 0 && !l.CurrentConfig().EnableTransactionAttachments) {
+		log.CliErrorf(c, "[user_data.RestoreUserData] backup contains transaction pictures or attachments, but they are not enabled")
+		return errs.ErrBackupFileStorageDisabled
+	}
+
+	log.CliInfof(c, "[user_data.RestoreUserData] the backup of user \"%s\" was created at %s by version \"%s\"", userDataBackup.Manifest.Username, utils.FormatUnixTimeToLongDateTime(userDataBackup.Manifest.CreatedUnixTime, time.Local), userDataBackup.Manifest.ApplicationVersion)
+
+	if userDataBackup.Manifest.ExcludedLedgerMemberCount > 0 {
+		log.CliWarnf(c, "[user_data.RestoreUserData] %d ledger members are not included in the backup, all ledgers will be restored as unshared and the members need to be invited again", userDataBackup.Manifest.ExcludedLedgerMemberCount)
+	}
+
+	err = l.userDataBackups.RestoreUserDataBackup(c, uid, userDataBackup)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.RestoreUserData] failed to restore backup for user \"%s\", because %s", username, err.Error())
+		return err
+	}
+
+	log.CliInfof(c, "[user_data.RestoreUserData] %d accounts, %d transactions, %d pictures and %d attachments have been restored to user \"%s\"", len(userDataBackup.Content.Accounts), len(userDataBackup.Content.Transactions), len(userDataBackup.Content.Pictures), len(userDataBackup.Content.Attachments), username)
+
+	return nil
+}
+
 func (l *UserDataCli) getUserIdByUsername(c *core.CliContext, username string) (int64, error) {
 	user, err := l.GetUserByUsername(c, username)
 
@@ -896,7 +1128,7 @@ func (l *UserDataCli) getUserEssentialData(c *core.CliContext, uid int64, userna
 		return nil, nil, nil, nil, nil, errs.ErrUserIdInvalid
 	}
 
-	accounts, err := l.accounts.GetAllAccountsByUid(c, uid)
+	accounts, err := l.accounts.GetAllAccountsByUid(c, l.ledgerAccesses.GetAllLedgersAccessOfOwner(uid))
 
 	if err != nil {
 		log.CliErrorf(c, "[user_data.getUserEssentialData] failed to get accounts for user \"%s\", because %s", username, err.Error())
@@ -905,7 +1137,7 @@ func (l *UserDataCli) getUserEssentialData(c *core.CliContext, uid int64, userna
 
 	accountMap = l.accounts.GetAccountMapByList(accounts)
 
-	categories, err := l.categories.GetAllCategoriesByUid(c, uid, 0, -1)
+	categories, err := l.categories.GetAllCategoriesByUid(c, l.ledgerAccesses.GetAllLedgersAccessOfOwner(uid), 0, -1)
 
 	if err != nil {
 		log.CliErrorf(c, "[user_data.getUserEssentialData] failed to get categories for user \"%s\", because %s", username, err.Error())
@@ -914,7 +1146,7 @@ func (l *UserDataCli) getUserEssentialData(c *core.CliContext, uid int64, userna
 
 	categoryMap = l.categories.GetCategoryMapByList(categories)
 
-	tags, err := l.tags.GetAllTagsByUid(c, uid)
+	tags, err := l.tags.GetAllTagsByUid(c, l.ledgerAccesses.GetAllLedgersAccessOfOwner(uid))
 
 	if err != nil {
 		log.CliErrorf(c, "[user_data.getUserEssentialData] failed to get tags for user \"%s\", because %s", username, err.Error())
@@ -935,13 +1167,43 @@ func (l *UserDataCli) getUserEssentialData(c *core.CliContext, uid int64, userna
 	return accountMap, categoryMap, tagMap, tagIndexes, tagIndexesMap, nil
 }
 
+func (l *UserDataCli) getUserEssentialDataForExport(c *core.CliContext, uid int64, username string) (accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, err error) {
+	if uid <= 0 {
+		log.CliErrorf(c, "[user_data.getUserEssentialDataForExport] user uid \"%d\" is invalid", uid)
+		return nil, nil, nil, errs.ErrUserIdInvalid
+	}
+
+	accounts, err := l.accounts.GetAllAccountsByUid(c, l.ledgerAccesses.GetAllLedgersAccessOfOwner(uid))
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.getUserEssentialDataForExport] failed to get accounts for user \"%s\", because %s", username, err.Error())
+		return nil, nil, nil, err
+	}
+
+	categories, err := l.categories.GetAllCategoriesByUid(c, l.ledgerAccesses.GetAllLedgersAccessOfOwner(uid), 0, -1)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.getUserEssentialDataForExport] failed to get categories for user \"%s\", because %s", username, err.Error())
+		return nil, nil, nil, err
+	}
+
+	tags, err := l.tags.GetAllTagsByUid(c, l.ledgerAccesses.GetAllLedgersAccessOfOwner(uid))
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.getUserEssentialDataForExport] failed to get tags for user \"%s\", because %s", username, err.Error())
+		return nil, nil, nil, err
+	}
+
+	return l.accounts.GetAccountMapByList(accounts), l.categories.GetCategoryMapByList(categories), l.tags.GetTagMapByList(tags), nil
+}
+
 func (l *UserDataCli) getUserEssentialDataForImport(c *core.CliContext, uid int64, username string) (accountMap map[string]*models.Account, expenseCategoryMap map[string]map[string]*models.TransactionCategory, incomeCategoryMap map[string]map[string]*models.TransactionCategory, transferCategoryMap map[string]map[string]*models.TransactionCategory, tagMap map[string]*models.TransactionTag, err error) {
 	if uid <= 0 {
 		log.CliErrorf(c, "[user_data.getUserEssentialDataForImport] user uid \"%d\" is invalid", uid)
 		return nil, nil, nil, nil, nil, errs.ErrUserIdInvalid
 	}
 
-	accounts, err := l.accounts.GetAllAccountsByUid(c, uid)
+	accounts, err := l.accounts.GetAllAccountsByUid(c, l.ledgerAccesses.GetAllLedgersAccessOfOwner(uid))
 
 	if err != nil {
 		log.CliErrorf(c, "[user_data.getUserEssentialDataForImport] failed to get accounts for user \"%s\", because %s", username, err.Error())
@@ -950,7 +1212,7 @@ func (l *UserDataCli) getUserEssentialDataForImport(c *core.CliContext, uid int6
 
 	accountMap = l.accounts.GetVisibleAccountNameMapByList(accounts)
 
-	categories, err := l.categories.GetAllCategoriesByUid(c, uid, 0, -1)
+	categories, err := l.categories.GetAllCategoriesByUid(c, l.ledgerAccesses.GetAllLedgersAccessOfOwner(uid), 0, -1)
 
 	if err != nil {
 		log.CliErrorf(c, "[user_data.getUserEssentialDataForImport] failed to get categories for user \"%s\", because %s", username, err.Error())
@@ -959,7 +1221,7 @@ func (l *UserDataCli) getUserEssentialDataForImport(c *core.CliContext, uid int6
 
 	expenseCategoryMap, incomeCategoryMap, transferCategoryMap = l.categories.GetVisibleSubCategoryNameMapByList(categories)
 
-	tags, err := l.tags.GetAllTagsByUid(c, uid)
+	tags, err := l.tags.GetAllTagsByUid(c, l.ledgerAccesses.GetAllLedgersAccessOfOwner(uid))
 
 	if err != nil {
 		log.CliErrorf(c, "[user_data.getUserEssentialDataForImport] failed to get tags for user \"%s\", because %s", username, err.Error())
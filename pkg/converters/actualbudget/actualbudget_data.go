@@ -0,0 +1,55 @@
+package actualbudget
+
+// actualBudgetData defines the structure of actual budget data
+type actualBudgetData struct {
+	Accounts       map[string]*actualBudgetAccountData
+	CategoryGroups map[string]*actualBudgetCategoryGroupData
+	Categories     map[string]*actualBudgetCategoryData
+	Payees         map[string]*actualBudgetPayeeData
+	Transactions   []*actualBudgetTransactionData
+}
+
+// actualBudgetAccountData defines the structure of actual budget account data
+type actualBudgetAccountData struct {
+	Id        string
+	Name      string
+	OffBudget bool
+}
+
+// actualBudgetCategoryGroupData defines the structure of actual budget category group data
+type actualBudgetCategoryGroupData struct {
+	Id       string
+	Name     string
+	IsIncome bool
+}
+
+// actualBudgetCategoryData defines the structure of actual budget category data
+type actualBudgetCategoryData struct {
+	Id       string
+	Name     string
+	IsIncome bool
+	GroupId  string
+}
+
+// actualBudgetPayeeData defines the structure of actual budget payee data
+type actualBudgetPayeeData struct {
+	Id                string
+	Name              string
+	TransferAccountId string
+}
+
+// actualBudgetTransactionData defines the structure of actual budget transaction data
+type actualBudgetTransactionData struct {
+	Id                    string
+	AccountId             string
+	CategoryId            string
+	Amount                int64
+	PayeeId               string
+	Notes                 string
+	Date                  int64
+	IsParent              bool
+	IsChild               bool
+	ParentId              string
+	TransferredId         string
+	IsStartingBalanceFlag bool
+}
@@ -0,0 +1,310 @@
+package actualbudget
+
+import (
+	"archive/zip"
+	"bytes"
+	"database/sql"
+	"io"
+	"os"
+	"path"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+)
+
+const actualBudgetDatabaseFileName = "db.sqlite"
+const actualBudgetTempDatabaseFileNamePattern = "ezbookkeeping_actual_budget_*.sqlite"
+
+var sqliteFileHeader = []byte("SQLite format 3\x00")
+
+// actualBudgetDataReader defines the structure of actual budget data reader
+type actualBudgetDataReader struct {
+	databaseData []byte
+}
+
+// read returns the imported actual budget data
+func (r *actualBudgetDataReader) read(ctx core.Context) (*actualBudgetData, error) {
+	tempFile, err := os.CreateTemp("", actualBudgetTempDatabaseFileNamePattern)
+
+	if err != nil {
+		log.Errorf(ctx, "[actualbudget_data_reader.read] cannot create temp database file, because %s", err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	tempFilePath := tempFile.Name()
+	defer os.Remove(tempFilePath)
+
+	_, err = tempFile.Write(r.databaseData)
+	closeErr := tempFile.Close()
+
+	if err != nil || closeErr != nil {
+		log.Errorf(ctx, "[actualbudget_data_reader.read] cannot write temp database file \"%s\"", tempFilePath)
+		return nil, errs.ErrOperationFailed
+	}
+
+	db, err := sql.Open("sqlite3", "file:"+tempFilePath+"?mode=ro&immutable=1")
+
+	if err != nil {
+		log.Errorf(ctx, "[actualbudget_data_reader.read] cannot open database file, because %s", err.Error())
+		return nil, errs.ErrInvalidActualBudgetFile
+	}
+
+	defer db.Close()
+
+	data := &actualBudgetData{}
+
+	if data.Accounts, err = r.readAccounts(db); err != nil {
+		log.Errorf(ctx, "[actualbudget_data_reader.read] cannot read accounts, because %s", err.Error())
+		return nil, errs.ErrInvalidActualBudgetFile
+	}
+
+	if data.CategoryGroups, err = r.readCategoryGroups(db); err != nil {
+		log.Errorf(ctx, "[actualbudget_data_reader.read] cannot read category groups, because %s", err.Error())
+		return nil, errs.ErrInvalidActualBudgetFile
+	}
+
+	if data.Categories, err = r.readCategories(db); err != nil {
+		log.Errorf(ctx, "[actualbudget_data_reader.read] cannot read categories, because %s", err.Error())
+		return nil, errs.ErrInvalidActualBudgetFile
+	}
+
+	if data.Payees, err = r.readPayees(db); err != nil {
+		log.Errorf(ctx, "[actualbudget_data_reader.read] cannot read payees, because %s", err.Error())
+		return nil, errs.ErrInvalidActualBudgetFile
+	}
+
+	if data.Transactions, err = r.readTransactions(db); err != nil {
+		log.Errorf(ctx, "[actualbudget_data_reader.read] cannot read transactions, because %s", err.Error())
+		return nil, errs.ErrInvalidActualBudgetFile
+	}
+
+	// the merged categories and payees are deleted and mapped to the target ones in actual budget
+	categoryMapping, err := r.readIdMapping(db, "SELECT id, transferId FROM category_mapping")
+
+	if err != nil {
+		log.Warnf(ctx, "[actualbudget_data_reader.read] cannot read category mapping, because %s", err.Error())
+	}
+
+	payeeMapping, err := r.readIdMapping(db, "SELECT id, targetId FROM payee_mapping")
+
+	if err != nil {
+		log.Warnf(ctx, "[actualbudget_data_reader.read] cannot read payee mapping, because %s", err.Error())
+	}
+
+	for i := 0; i < len(data.Transactions); i++ {
+		transaction := data.Transactions[i]
+
+		if targetId, exists := categoryMapping[transaction.CategoryId]; exists && targetId != "" {
+			transaction.CategoryId = targetId
+		}
+
+		if targetId, exists := payeeMapping[transaction.PayeeId]; exists && targetId != "" {
+			transaction.PayeeId = targetId
+		}
+	}
+
+	return data, nil
+}
+
+func (r *actualBudgetDataReader) readAccounts(db *sql.DB) (map[string]*actualBudgetAccountData, error) {
+	rows, err := db.Query("SELECT id, COALESCE(name, ''), COALESCE(offbudget, 0) FROM accounts WHERE COALESCE(tombstone, 0) = 0")
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	accounts := make(map[string]*actualBudgetAccountData)
+
+	for rows.Next() {
+		account := &actualBudgetAccountData{}
+
+		if err = rows.Scan(&account.Id, &account.Name, &account.OffBudget); err != nil {
+			return nil, err
+		}
+
+		accounts[account.Id] = account
+	}
+
+	return accounts, rows.Err()
+}
+
+func (r *actualBudgetDataReader) readCategoryGroups(db *sql.DB) (map[string]*actualBudgetCategoryGroupData, error) {
+	rows, err := db.Query("SELECT id, COALESCE(name, ''), COALESCE(is_income, 0) FROM category_groups WHERE COALESCE(tombstone, 0) = 0")
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	categoryGroups := make(map[string]*actualBudgetCategoryGroupData)
+
+	for rows.Next() {
+		categoryGroup := &actualBudgetCategoryGroupData{}
+
+		if err = rows.Scan(&categoryGroup.Id, &categoryGroup.Name, &categoryGroup.IsIncome); err != nil {
+			return nil, err
+		}
+
+		categoryGroups[categoryGroup.Id] = categoryGroup
+	}
+
+	return categoryGroups, rows.Err()
+}
+
+func (r *actualBudgetDataReader) readCategories(db *sql.DB) (map[string]*actualBudgetCategoryData, error) {
+	rows, err := db.Query("SELECT id, COALESCE(name, ''), COALESCE(is_income, 0), COALESCE(cat_group, '') FROM categories WHERE COALESCE(tombstone, 0) = 0")
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	categories := make(map[string]*actualBudgetCategoryData)
+
+	for rows.Next() {
+		category := &actualBudgetCategoryData{}
+
+		if err = rows.Scan(&category.Id, &category.Name, &category.IsIncome, &category.GroupId); err != nil {
+			return nil, err
+		}
+
+		categories[category.Id] = category
+	}
+
+	return categories, rows.Err()
+}
+
+func (r *actualBudgetDataReader) readPayees(db *sql.DB) (map[string]*actualBudgetPayeeData, error) {
+	rows, err := db.Query("SELECT id, COALESCE(name, ''), COALESCE(transfer_acct, '') FROM payees WHERE COALESCE(tombstone, 0) = 0")
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	payees := make(map[string]*actualBudgetPayeeData)
+
+	for rows.Next() {
+		payee := &actualBudgetPayeeData{}
+
+		if err = rows.Scan(&payee.Id, &payee.Name, &payee.TransferAccountId); err != nil {
+			return nil, err
+		}
+
+		payees[payee.Id] = payee
+	}
+
+	return payees, rows.Err()
+}
+
+func (r *actualBudgetDataReader) readTransactions(db *sql.DB) ([]*actualBudgetTransactionData, error) {
+	rows, err := db.Query("SELECT id, COALESCE(acct, ''), COALESCE(category, ''), COALESCE(amount, 0), COALESCE(description, ''), COALESCE(notes, ''), COALESCE(date, 0), " +
+		"COALESCE(isParent, 0), COALESCE(isChild, 0), COALESCE(parent_id, ''), COALESCE(transferred_id, ''), COALESCE(starting_balance_flag, 0) " +
+		"FROM transactions WHERE COALESCE(tombstone, 0) = 0 ORDER BY date, id")
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	transactions := make([]*actualBudgetTransactionData, 0)
+
+	for rows.Next() {
+		transaction := &actualBudgetTransactionData{}
+
+		err = rows.Scan(&transaction.Id, &transaction.AccountId, &transaction.CategoryId, &transaction.Amount, &transaction.PayeeId, &transaction.Notes, &transaction.Date,
+			&transaction.IsParent, &transaction.IsChild, &transaction.ParentId, &transaction.TransferredId, &transaction.IsStartingBalanceFlag)
+
+		if err != nil {
+			return nil, err
+		}
+
+		transactions = append(transactions, transaction)
+	}
+
+	return transactions, rows.Err()
+}
+
+func (r *actualBudgetDataReader) readIdMapping(db *sql.DB, query string) (map[string]string, error) {
+	idMapping := make(map[string]string)
+	rows, err := db.Query(query)
+
+	if err != nil {
+		return idMapping, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		var targetId sql.NullString
+
+		if err = rows.Scan(&id, &targetId); err != nil {
+			return idMapping, err
+		}
+
+		if targetId.Valid && targetId.String != id {
+			idMapping[id] = targetId.String
+		}
+	}
+
+	return idMapping, rows.Err()
+}
+
+func createNewActualBudgetDataReader(ctx core.Context, data []byte) (*actualBudgetDataReader, error) {
+	if bytes.HasPrefix(data, sqliteFileHeader) {
+		return &actualBudgetDataReader{
+			databaseData: data,
+		}, nil
+	}
+
+	if len(data) > 4 && data[0] == 0x50 && data[1] == 0x4B && data[2] == 0x03 && data[3] == 0x04 { // zip magic number
+		zipReader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+
+		if err != nil {
+			log.Errorf(ctx, "[actualbudget_data_reader.createNewActualBudgetDataReader] cannot open zip file, because %s", err.Error())
+			return nil, errs.ErrInvalidActualBudgetFile
+		}
+
+		for i := 0; i < len(zipReader.File); i++ {
+			file := zipReader.File[i]
+
+			if file.FileInfo().IsDir() || path.Base(file.Name) != actualBudgetDatabaseFileName {
+				continue
+			}
+
+			fileReader, err := file.Open()
+
+			if err != nil {
+				log.Errorf(ctx, "[actualbudget_data_reader.createNewActualBudgetDataReader] cannot open \"%s\" in zip file, because %s", file.Name, err.Error())
+				return nil, errs.ErrInvalidActualBudgetFile
+			}
+
+			databaseData, err := io.ReadAll(fileReader)
+			fileReader.Close()
+
+			if err != nil || !bytes.HasPrefix(databaseData, sqliteFileHeader) {
+				log.Errorf(ctx, "[actualbudget_data_reader.createNewActualBudgetDataReader] \"%s\" in zip file is not a valid database file", file.Name)
+				return nil, errs.ErrInvalidActualBudgetFile
+			}
+
+			return &actualBudgetDataReader{
+				databaseData: databaseData,
+			}, nil
+		}
+
+		log.Errorf(ctx, "[actualbudget_data_reader.createNewActualBudgetDataReader] there is no database file in zip file")
+		return nil, errs.ErrInvalidActualBudgetFile
+	}
+
+	return nil, errs.ErrInvalidActualBudgetFile
+}
@@ -0,0 +1,189 @@
+package actualbudget
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/converter"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+func TestActualBudgetTransactionDataImporterParseImportedData_ExportZipFile(t *testing.T) {
+	importer := ActualBudgetTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "USD",
+	}
+
+	testdata, err := os.ReadFile("../../../testdata/actual_budget_test_file.zip")
+	assert.Nil(t, err)
+
+	allNewTransactions, allNewAccounts, allNewSubExpenseCategories, allNewSubIncomeCategories, allNewSubTransferCategories, allNewTags, err := importer.ParseImportedData(context, user, testdata, time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 6, len(allNewTransactions))
+	assert.Equal(t, 2, len(allNewAccounts))
+	assert.Equal(t, 2, len(allNewSubExpenseCategories))
+	assert.Equal(t, 1, len(allNewSubIncomeCategories))
+	assert.Equal(t, 1, len(allNewSubTransferCategories))
+	assert.Equal(t, 2, len(allNewTags))
+
+	assert.Equal(t, int64(1234567890), allNewTransactions[0].Uid)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_MODIFY_BALANCE, allNewTransactions[0].Type)
+	assert.Equal(t, "2024-09-01 00:00:00", utils.FormatUnixTimeToLongDateTime(utils.GetUnixTimeFromTransactionTime(allNewTransactions[0].TransactionTime), time.UTC))
+	assert.Equal(t, int64(100000), allNewTransactions[0].Amount)
+	assert.Equal(t, "Checking", allNewTransactions[0].OriginalSourceAccountName)
+
+	assert.Equal(t, int64(1234567890), allNewTransactions[1].Uid)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[1].Type)
+	assert.Equal(t, "2024-09-02 00:00:00", utils.FormatUnixTimeToLongDateTime(utils.GetUnixTimeFromTransactionTime(allNewTransactions[1].TransactionTime), time.UTC))
+	assert.Equal(t, int64(12345), allNewTransactions[1].Amount)
+	assert.Equal(t, "Checking", allNewTransactions[1].OriginalSourceAccountName)
+	assert.Equal(t, "Food", allNewTransactions[1].OriginalCategoryName)
+	assert.Equal(t, []string{"groceries", "family"}, allNewTransactions[1].OriginalTagNames)
+	assert.Equal(t, "Weekly #groceries #family", allNewTransactions[1].Comment)
+
+	assert.Equal(t, int64(1234567890), allNewTransactions[2].Uid)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, allNewTransactions[2].Type)
+	assert.Equal(t, "2024-09-03 00:00:00", utils.FormatUnixTimeToLongDateTime(utils.GetUnixTimeFromTransactionTime(allNewTransactions[2].TransactionTime), time.UTC))
+	assert.Equal(t, int64(250000), allNewTransactions[2].Amount)
+	assert.Equal(t, "Checking", allNewTransactions[2].OriginalSourceAccountName)
+	assert.Equal(t, "Salary", allNewTransactions[2].OriginalCategoryName)
+	assert.Equal(t, "Salary", allNewTransactions[2].Comment)
+
+	assert.Equal(t, int64(1234567890), allNewTransactions[3].Uid)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[3].Type)
+	assert.Equal(t, "2024-09-04 00:00:00", utils.FormatUnixTimeToLongDateTime(utils.GetUnixTimeFromTransactionTime(allNewTransactions[3].TransactionTime), time.UTC))
+	assert.Equal(t, int64(50000), allNewTransactions[3].Amount)
+	assert.Equal(t, int64(50000), allNewTransactions[3].RelatedAccountAmount)
+	assert.Equal(t, "Checking", allNewTransactions[3].OriginalSourceAccountName)
+	assert.Equal(t, "Savings", allNewTransactions[3].OriginalDestinationAccountName)
+	assert.Equal(t, "Move to savings", allNewTransactions[3].Comment)
+
+	assert.Equal(t, int64(1234567890), allNewTransactions[4].Uid)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[4].Type)
+	assert.Equal(t, "2024-09-05 00:00:00", utils.FormatUnixTimeToLongDateTime(utils.GetUnixTimeFromTransactionTime(allNewTransactions[4].TransactionTime), time.UTC))
+	assert.Equal(t, int64(3000), allNewTransactions[4].Amount)
+	assert.Equal(t, "Checking", allNewTransactions[4].OriginalSourceAccountName)
+	assert.Equal(t, "Food", allNewTransactions[4].OriginalCategoryName)
+	assert.Equal(t, "Fruit", allNewTransactions[4].Comment)
+
+	assert.Equal(t, int64(1234567890), allNewTransactions[5].Uid)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[5].Type)
+	assert.Equal(t, "2024-09-05 00:00:00", utils.FormatUnixTimeToLongDateTime(utils.GetUnixTimeFromTransactionTime(allNewTransactions[5].TransactionTime), time.UTC))
+	assert.Equal(t, int64(2050), allNewTransactions[5].Amount)
+	assert.Equal(t, "Checking", allNewTransactions[5].OriginalSourceAccountName)
+	assert.Equal(t, "General", allNewTransactions[5].OriginalCategoryName)
+	assert.Equal(t, "Soap", allNewTransactions[5].Comment)
+
+	assert.Equal(t, int64(1234567890), allNewAccounts[0].Uid)
+	assert.Equal(t, "Checking", allNewAccounts[0].Name)
+	assert.Equal(t, "USD", allNewAccounts[0].Currency)
+
+	assert.Equal(t, int64(1234567890), allNewAccounts[1].Uid)
+	assert.Equal(t, "Savings", allNewAccounts[1].Name)
+	assert.Equal(t, "USD", allNewAccounts[1].Currency)
+
+	assert.Equal(t, "groceries", allNewTags[0].Name)
+	assert.Equal(t, "family", allNewTags[1].Name)
+}
+
+func TestActualBudgetTransactionDataImporterParseImportedData_CategoryGroupAsParentCategory(t *testing.T) {
+	importer := ActualBudgetTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "USD",
+	}
+
+	testdata, err := os.ReadFile("../../../testdata/actual_budget_test_file.zip")
+	assert.Nil(t, err)
+
+	expenseCategoryMap := map[string]map[string]*models.TransactionCategory{
+		"Food": {
+			"Dining": &models.TransactionCategory{
+				CategoryId: 1001,
+				Name:       "Food",
+			},
+			"Usual Expenses": &models.TransactionCategory{
+				CategoryId: 1002,
+				Name:       "Food",
+			},
+		},
+	}
+
+	allNewTransactions, _, allNewSubExpenseCategories, _, _, _, err := importer.ParseImportedData(context, user, testdata, time.UTC, converter.DefaultImporterOptions, nil, expenseCategoryMap, nil, nil, nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 1, len(allNewSubExpenseCategories))
+	assert.Equal(t, int64(1002), allNewTransactions[1].CategoryId)
+	assert.Equal(t, int64(1002), allNewTransactions[4].CategoryId)
+}
+
+func TestActualBudgetTransactionDataImporterParseImportedData_PayeeAsTag(t *testing.T) {
+	importer := ActualBudgetTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "USD",
+	}
+
+	testdata, err := os.ReadFile("../../../testdata/actual_budget_test_file.zip")
+	assert.Nil(t, err)
+
+	allNewTransactions, _, _, _, _, allNewTags, err := importer.ParseImportedData(context, user, testdata, time.UTC, converter.DefaultImporterOptions.WithPayeeAsTag(), nil, nil, nil, nil, nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 5, len(allNewTags))
+	assert.Equal(t, []string{"Starting Balance"}, allNewTransactions[0].OriginalTagNames)
+	assert.Equal(t, []string{"groceries", "family", "Supermarket"}, allNewTransactions[1].OriginalTagNames)
+	assert.Equal(t, []string{"Employer"}, allNewTransactions[2].OriginalTagNames)
+	assert.Equal(t, []string{"Supermarket"}, allNewTransactions[4].OriginalTagNames)
+}
+
+func TestActualBudgetTransactionDataImporterParseImportedData_InvalidFile(t *testing.T) {
+	importer := ActualBudgetTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "USD",
+	}
+
+	_, _, _, _, _, _, err := importer.ParseImportedData(context, user, []byte("not a database file"), time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrInvalidActualBudgetFile.Message)
+
+	_, _, _, _, _, _, err = importer.ParseImportedData(context, user, []byte{0x50, 0x4B, 0x03, 0x04, 0x00}, time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrInvalidActualBudgetFile.Message)
+
+	_, _, _, _, _, _, err = importer.ParseImportedData(context, user, append([]byte("SQLite format 3\x00"), make([]byte, 100)...), time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrInvalidActualBudgetFile.Message)
+}
+
+func TestParseActualBudgetTransactionTime(t *testing.T) {
+	actualValue, err := parseActualBudgetTransactionTime(20240901)
+	assert.Nil(t, err)
+	assert.Equal(t, "2024-09-01 00:00:00", actualValue)
+
+	_, err = parseActualBudgetTransactionTime(20241301)
+	assert.EqualError(t, err, errs.ErrTransactionTimeInvalid.Message)
+
+	_, err = parseActualBudgetTransactionTime(240901)
+	assert.EqualError(t, err, errs.ErrTransactionTimeInvalid.Message)
+}
+
+func TestGetActualBudgetNotesTags(t *testing.T) {
+	assert.Equal(t, []string{}, getActualBudgetNotesTags(""))
+	assert.Equal(t, []string{}, getActualBudgetNotesTags("no tags here, issue#1"))
+	assert.Equal(t, []string{"food", "trip2024"}, getActualBudgetNotesTags("#food lunch on #trip2024"))
+}
@@ -0,0 +1,277 @@
+package actualbudget
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/datatable"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const actualBudgetTransactionTagSeparator = " "
+
+var actualBudgetNotesTagPattern = regexp.MustCompile(`(?:^|\s)#([^\s#]+)`)
+
+var actualBudgetTransactionSupportedColumns = map[datatable.TransactionDataTableColumn]bool{
+	datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIME:     true,
+	datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE:     true,
+	datatable.TRANSACTION_DATA_TABLE_CATEGORY:             true,
+	datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY:         true,
+	datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME:         true,
+	datatable.TRANSACTION_DATA_TABLE_AMOUNT:               true,
+	datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME: true,
+	datatable.TRANSACTION_DATA_TABLE_RELATED_AMOUNT:       true,
+	datatable.TRANSACTION_DATA_TABLE_TAGS:                 true,
+	datatable.TRANSACTION_DATA_TABLE_DESCRIPTION:          true,
+	datatable.TRANSACTION_DATA_TABLE_PAYEE:                true,
+}
+
+// actualBudgetTransactionDataTable defines the structure of actual budget transaction data table
+type actualBudgetTransactionDataTable struct {
+	allData []*actualBudgetTransactionData
+	data    *actualBudgetData
+}
+
+// actualBudgetTransactionDataRow defines the structure of actual budget transaction data row
+type actualBudgetTransactionDataRow struct {
+	dataTable  *actualBudgetTransactionDataTable
+	data       *actualBudgetTransactionData
+	finalItems map[datatable.TransactionDataTableColumn]string
+	isValid    bool
+}
+
+// actualBudgetTransactionDataRowIterator defines the structure of actual budget transaction data row iterator
+type actualBudgetTransactionDataRowIterator struct {
+	dataTable    *actualBudgetTransactionDataTable
+	currentIndex int
+}
+
+// HasColumn returns whether the transaction data table has specified column
+func (t *actualBudgetTransactionDataTable) HasColumn(column datatable.TransactionDataTableColumn) bool {
+	_, exists := actualBudgetTransactionSupportedColumns[column]
+	return exists
+}
+
+// TransactionRowCount returns the total count of transaction data row
+func (t *actualBudgetTransactionDataTable) TransactionRowCount() int {
+	return len(t.allData)
+}
+
+// TransactionRowIterator returns the iterator of transaction data row
+func (t *actualBudgetTransactionDataTable) TransactionRowIterator() datatable.TransactionDataRowIterator {
+	return &actualBudgetTransactionDataRowIterator{
+		dataTable:    t,
+		currentIndex: -1,
+	}
+}
+
+// IsValid returns whether this row is valid data for importing
+func (r *actualBudgetTransactionDataRow) IsValid() bool {
+	return r.isValid
+}
+
+// GetData returns the data in the specified column type
+func (r *actualBudgetTransactionDataRow) GetData(column datatable.TransactionDataTableColumn) string {
+	_, exists := actualBudgetTransactionSupportedColumns[column]
+
+	if exists {
+		return r.finalItems[column]
+	}
+
+	return ""
+}
+
+// HasNext returns whether the iterator does not reach the end
+func (t *actualBudgetTransactionDataRowIterator) HasNext() bool {
+	return t.currentIndex+1 < len(t.dataTable.allData)
+}
+
+// Next returns the next transaction data row
+func (t *actualBudgetTransactionDataRowIterator) Next(ctx core.Context, user *models.User) (daraRow datatable.TransactionDataRow, err error) {
+	if t.currentIndex+1 >= len(t.dataTable.allData) {
+		return nil, nil
+	}
+
+	t.currentIndex++
+
+	data := t.dataTable.allData[t.currentIndex]
+	rowItems, isValid, err := t.parseTransaction(ctx, user, data)
+
+	if err != nil {
+		log.Errorf(ctx, "[actualbudget_transaction_data_table.Next] cannot parsing transaction in row#%d, because %s", t.currentIndex, err.Error())
+		return nil, err
+	}
+
+	return &actualBudgetTransactionDataRow{
+		dataTable:  t.dataTable,
+		data:       data,
+		finalItems: rowItems,
+		isValid:    isValid,
+	}, nil
+}
+
+func (t *actualBudgetTransactionDataRowIterator) parseTransaction(ctx core.Context, user *models.User, actualBudgetTransaction *actualBudgetTransactionData) (map[datatable.TransactionDataTableColumn]string, bool, error) {
+	data := make(map[datatable.TransactionDataTableColumn]string, len(actualBudgetTransactionSupportedColumns))
+
+	if actualBudgetTransaction.Date <= 0 {
+		return nil, false, errs.ErrMissingTransactionTime
+	}
+
+	transactionTime, err := parseActualBudgetTransactionTime(actualBudgetTransaction.Date)
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIME] = transactionTime
+
+	if actualBudgetTransaction.Amount == 0 {
+		log.Warnf(ctx, "[actualbudget_transaction_data_table.parseTransaction] skip parsing transaction \"id:%s\" with zero amount", actualBudgetTransaction.Id)
+		return nil, false, nil
+	}
+
+	account := t.dataTable.data.Accounts[actualBudgetTransaction.AccountId]
+
+	if account == nil {
+		return nil, false, errs.ErrMissingAccountData
+	}
+
+	data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME] = account.Name
+
+	payee := t.dataTable.data.Payees[actualBudgetTransaction.PayeeId]
+	amount := actualBudgetTransaction.Amount
+
+	if payee != nil && payee.TransferAccountId != "" {
+		transferAccount := t.dataTable.data.Accounts[payee.TransferAccountId]
+
+		if transferAccount == nil {
+			return nil, false, errs.ErrMissingAccountData
+		}
+
+		data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = actualBudgetTransactionTypeNameMapping[models.TRANSACTION_TYPE_TRANSFER]
+
+		if amount < 0 { // transfer to the account of payee
+			data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(-amount)
+			data[datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME] = transferAccount.Name
+		} else { // transfer from the account of payee, only exists when the other side is not in the file
+			data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(amount)
+			data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME] = transferAccount.Name
+			data[datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME] = account.Name
+		}
+
+		data[datatable.TRANSACTION_DATA_TABLE_RELATED_AMOUNT] = data[datatable.TRANSACTION_DATA_TABLE_AMOUNT]
+	} else if actualBudgetTransaction.IsStartingBalanceFlag {
+		data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = actualBudgetTransactionTypeNameMapping[models.TRANSACTION_TYPE_MODIFY_BALANCE]
+		data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(amount)
+	} else {
+		if amount > 0 {
+			data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = actualBudgetTransactionTypeNameMapping[models.TRANSACTION_TYPE_INCOME]
+			data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(amount)
+		} else {
+			data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = actualBudgetTransactionTypeNameMapping[models.TRANSACTION_TYPE_EXPENSE]
+			data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(-amount)
+		}
+
+		if category := t.dataTable.data.Categories[actualBudgetTransaction.CategoryId]; category != nil {
+			if categoryGroup := t.dataTable.data.CategoryGroups[category.GroupId]; categoryGroup != nil {
+				data[datatable.TRANSACTION_DATA_TABLE_CATEGORY] = categoryGroup.Name
+			}
+
+			data[datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY] = category.Name
+		}
+	}
+
+	if payee != nil {
+		data[datatable.TRANSACTION_DATA_TABLE_PAYEE] = payee.Name
+	}
+
+	data[datatable.TRANSACTION_DATA_TABLE_TAGS] = strings.Join(getActualBudgetNotesTags(actualBudgetTransaction.Notes), actualBudgetTransactionTagSeparator)
+	data[datatable.TRANSACTION_DATA_TABLE_DESCRIPTION] = actualBudgetTransaction.Notes
+
+	return data, true, nil
+}
+
+// parseActualBudgetTransactionTime returns the long date time of the date which is stored as integer (e.g. 20240901) in actual budget
+func parseActualBudgetTransactionTime(date int64) (string, error) {
+	year := date / 10000
+	month := date / 100 % 100
+	day := date % 100
+
+	if year < 1000 || year > 9999 || month < 1 || month > 12 || day < 1 || day > 31 {
+		return "", errs.ErrTransactionTimeInvalid
+	}
+
+	return fmt.Sprintf("%04d-%02d-%02d 00:00:00", year, month, day), nil
+}
+
+// getActualBudgetNotesTags returns the tag names which are written as "#tag" in the notes
+func getActualBudgetNotesTags(notes string) []string {
+	matches := actualBudgetNotesTagPattern.FindAllStringSubmatch(notes, -1)
+	tags := make([]string, 0, len(matches))
+
+	for i := 0; i < len(matches); i++ {
+		tags = append(tags, matches[i][1])
+	}
+
+	return tags
+}
+
+// getActualBudgetImportedTransactions returns the transactions which should be imported, the split parent transactions are replaced by their children,
+// and only one side of each transfer is kept (the outflow side is preferred)
+func getActualBudgetImportedTransactions(ctx core.Context, data *actualBudgetData) []*actualBudgetTransactionData {
+	transactionMap := make(map[string]*actualBudgetTransactionData, len(data.Transactions))
+
+	for i := 0; i < len(data.Transactions); i++ {
+		transactionMap[data.Transactions[i].Id] = data.Transactions[i]
+	}
+
+	result := make([]*actualBudgetTransactionData, 0, len(data.Transactions))
+
+	for i := 0; i < len(data.Transactions); i++ {
+		transaction := data.Transactions[i]
+
+		if transaction.IsParent {
+			continue
+		}
+
+		if transaction.IsChild {
+			if parent, exists := transactionMap[transaction.ParentId]; !exists || !parent.IsParent {
+				log.Warnf(ctx, "[actualbudget_transaction_data_table.getActualBudgetImportedTransactions] skip split transaction \"id:%s\", because parent transaction \"id:%s\" does not exist", transaction.Id, transaction.ParentId)
+				continue
+			}
+		}
+
+		if _, exists := data.Accounts[transaction.AccountId]; !exists {
+			log.Warnf(ctx, "[actualbudget_transaction_data_table.getActualBudgetImportedTransactions] skip transaction \"id:%s\", because account \"id:%s\" does not exist", transaction.Id, transaction.AccountId)
+			continue
+		}
+
+		payee := data.Payees[transaction.PayeeId]
+
+		if payee != nil && payee.TransferAccountId != "" && transaction.Amount > 0 {
+			if otherSide, exists := transactionMap[transaction.TransferredId]; exists && otherSide.Amount < 0 {
+				continue
+			}
+		}
+
+		result = append(result, transaction)
+	}
+
+	return result
+}
+
+func createNewActualBudgetTransactionDataTable(ctx core.Context, data *actualBudgetData) (*actualBudgetTransactionDataTable, error) {
+	if data == nil || len(data.Transactions) < 1 {
+		return nil, errs.ErrNotFoundTransactionDataInFile
+	}
+
+	return &actualBudgetTransactionDataTable{
+		allData: getActualBudgetImportedTransactions(ctx, data),
+		data:    data,
+	}, nil
+}
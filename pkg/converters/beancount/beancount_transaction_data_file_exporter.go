@@ -0,0 +1,468 @@
+package beancount
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/converter"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const beancountExportedDateFormat = "2006-01-02"
+const beancountExportedPriceMaxDecimals = 8
+const beancountExportedUnnamedAccountName = "Unnamed"
+const beancountExportedPostingIndent = "  "
+
+var beancountExportedTextReplacer = strings.NewReplacer("\r\n", " ", "\r", " ", "\n", " ", "\"", "'")
+
+// beancountExportedEntryType represents the type of exported Beancount entry, which is also the order of entries in the same day
+type beancountExportedEntryType byte
+
+// Beancount exported entry types
+const (
+	beancountExportedOpenEntry        beancountExportedEntryType = 0
+	beancountExportedBalanceEntry     beancountExportedEntryType = 1
+	beancountExportedPriceEntry       beancountExportedEntryType = 2
+	beancountExportedTransactionEntry beancountExportedEntryType = 3
+)
+
+// beancountExportedEntry defines the structure of exported Beancount entry
+type beancountExportedEntry struct {
+	date      string
+	entryType beancountExportedEntryType
+	content   string
+}
+
+// beancountExportedPosting defines the structure of exported Beancount posting
+type beancountExportedPosting struct {
+	date      string
+	account   string
+	amount    int64
+	commodity string
+	totalCost string
+}
+
+// beancountTransactionDataExporter defines the structure of Beancount exporter for transaction data
+type beancountTransactionDataExporter struct {
+}
+
+// Initialize a beancount transaction data exporter singleton instance
+var (
+	BeancountTransactionDataExporter = &beancountTransactionDataExporter{}
+)
+
+// ToExportedContent returns the exported Beancount data, it writes open directives for all used accounts,
+// price directives for the exchange rates of cross-currency transfers and balance assertions after each balance modification
+func (c *beancountTransactionDataExporter) ToExportedContent(ctx core.Context, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64, customFields []*models.TransactionCustomField, allCustomFieldValues map[int64][]*models.TransactionCustomFieldValue, options converter.TransactionDataExporterOptions) ([]byte, error) {
+	accountNames := c.buildAccountNames(accountMap)
+	categoryNames := c.buildCategoryNames(categoryMap)
+	openingBalanceAccountName := beancountDefaultEquityAccountTypeName + beancountAccountNameItemsSeparator + beancountEquityAccountNameOpeningBalance
+
+	allTransactions := make([]*models.Transaction, 0, len(transactions))
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+			continue
+		}
+
+		if _, exists := accountMap[transaction.AccountId]; !exists {
+			log.Warnf(ctx, "[beancount_transaction_data_file_exporter.ToExportedContent] account \"id:%d\" of transaction \"id:%d\" does not exist", transaction.AccountId, transaction.TransactionId)
+			continue
+		}
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+			if _, exists := accountMap[transaction.RelatedAccountId]; !exists {
+				log.Warnf(ctx, "[beancount_transaction_data_file_exporter.ToExportedContent] related account \"id:%d\" of transaction \"id:%d\" does not exist", transaction.RelatedAccountId, transaction.TransactionId)
+				continue
+			}
+		}
+
+		allTransactions = append(allTransactions, transaction)
+	}
+
+	sort.SliceStable(allTransactions, func(i, j int) bool {
+		return allTransactions[i].TransactionTime < allTransactions[j].TransactionTime
+	})
+
+	entries := make([]*beancountExportedEntry, 0, len(allTransactions))
+	accountOpenDates := make(map[string]string)
+	accountCurrencies := make(map[string]string)
+	accountPostings := make(map[string][]*beancountExportedPosting)
+	balanceAssertionDates := make(map[string]map[string]bool)
+	prices := make(map[string]string)
+
+	for i := 0; i < len(allTransactions); i++ {
+		transaction := allTransactions[i]
+		account := accountMap[transaction.AccountId]
+		accountName := accountNames[account.AccountId]
+		date := c.formatDate(transaction, options.GetTransactionTimezone(transaction))
+		var postings []*beancountExportedPosting
+
+		switch transaction.Type {
+		case models.TRANSACTION_DB_TYPE_MODIFY_BALANCE:
+			postings = []*beancountExportedPosting{
+				{account: openingBalanceAccountName, amount: -transaction.RelatedAccountAmount, commodity: account.Currency},
+				{account: accountName, amount: transaction.RelatedAccountAmount, commodity: account.Currency},
+			}
+
+			if balanceAssertionDates[accountName] == nil {
+				balanceAssertionDates[accountName] = make(map[string]bool)
+			}
+
+			balanceAssertionDates[accountName][date] = true
+		case models.TRANSACTION_DB_TYPE_INCOME:
+			postings = []*beancountExportedPosting{
+				{account: c.getCategoryName(transaction.CategoryId, categoryNames, beancountDefaultIncomeAccountTypeName), amount: -transaction.Amount, commodity: account.Currency},
+				{account: accountName, amount: transaction.Amount, commodity: account.Currency},
+			}
+		case models.TRANSACTION_DB_TYPE_EXPENSE:
+			postings = []*beancountExportedPosting{
+				{account: accountName, amount: -transaction.Amount, commodity: account.Currency},
+				{account: c.getCategoryName(transaction.CategoryId, categoryNames, beancountDefaultExpenseAccountTypeName), amount: transaction.Amount, commodity: account.Currency},
+			}
+		case models.TRANSACTION_DB_TYPE_TRANSFER_OUT:
+			relatedAccount := accountMap[transaction.RelatedAccountId]
+			fromPosting := &beancountExportedPosting{account: accountName, amount: -transaction.Amount, commodity: account.Currency}
+			toPosting := &beancountExportedPosting{account: accountNames[relatedAccount.AccountId], amount: transaction.RelatedAccountAmount, commodity: relatedAccount.Currency}
+
+			if account.Currency != relatedAccount.Currency {
+				fromPosting.totalCost = utils.FormatAmount(transaction.RelatedAccountAmount) + " " + relatedAccount.Currency
+
+				if transaction.Amount > 0 && transaction.RelatedAccountAmount > 0 {
+					prices[date+" "+account.Currency+" "+relatedAccount.Currency] = c.formatPrice(transaction.Amount, transaction.RelatedAccountAmount)
+				}
+			}
+
+			postings = []*beancountExportedPosting{fromPosting, toPosting}
+		default:
+			continue
+		}
+
+		for j := 0; j < len(postings); j++ {
+			posting := postings[j]
+			posting.date = date
+			accountPostings[posting.account] = append(accountPostings[posting.account], posting)
+
+			if openDate, exists := accountOpenDates[posting.account]; !exists || date < openDate {
+				accountOpenDates[posting.account] = date
+			}
+
+			if strings.HasPrefix(posting.account, beancountDefaultAssetsAccountTypeName+beancountAccountNameItemsSeparator) ||
+				strings.HasPrefix(posting.account, beancountDefaultLiabilitiesAccountTypeName+beancountAccountNameItemsSeparator) {
+				accountCurrencies[posting.account] = posting.commodity
+			}
+		}
+
+		entries = append(entries, &beancountExportedEntry{
+			date:      date,
+			entryType: beancountExportedTransactionEntry,
+			content:   c.getTransactionContent(transaction, date, postings, tagMap, allTagIndexes),
+		})
+	}
+
+	entries = append(entries, c.getBalanceAssertionEntries(balanceAssertionDates, accountPostings, accountCurrencies)...)
+
+	for accountName, openDate := range accountOpenDates {
+		content := openDate + " " + string(beancountDirectiveOpen) + " " + accountName
+
+		if currency, exists := accountCurrencies[accountName]; exists {
+			content += " " + currency
+		}
+
+		entries = append(entries, &beancountExportedEntry{
+			date:      openDate,
+			entryType: beancountExportedOpenEntry,
+			content:   content + "\n",
+		})
+	}
+
+	for dateAndCommodities, price := range prices {
+		items := strings.Split(dateAndCommodities, " ")
+
+		entries = append(entries, &beancountExportedEntry{
+			date:      items[0],
+			entryType: beancountExportedPriceEntry,
+			content:   fmt.Sprintf("%s %s %s %s %s\n", items[0], beancountDirectivePrice, items[1], price, items[2]),
+		})
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].date != entries[j].date {
+			return entries[i].date < entries[j].date
+		}
+
+		if entries[i].entryType != entries[j].entryType {
+			return entries[i].entryType < entries[j].entryType
+		}
+
+		if entries[i].entryType != beancountExportedTransactionEntry {
+			return entries[i].content < entries[j].content
+		}
+
+		return false
+	})
+
+	var ret bytes.Buffer
+
+	for i := 0; i < len(entries); i++ {
+		if i > 0 && (entries[i].entryType == beancountExportedTransactionEntry || entries[i].entryType != entries[i-1].entryType) {
+			ret.WriteString("\n")
+		}
+
+		ret.WriteString(entries[i].content)
+	}
+
+	return ret.Bytes(), nil
+}
+
+func (c *beancountTransactionDataExporter) getTransactionContent(transaction *models.Transaction, date string, postings []*beancountExportedPosting, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64) string {
+	var ret strings.Builder
+
+	ret.WriteString(date)
+	ret.WriteString(" ")
+	ret.WriteString(string(beancountDirectiveCompletedTransaction))
+	ret.WriteString(" \"")
+	ret.WriteString(beancountExportedTextReplacer.Replace(transaction.Comment))
+	ret.WriteString("\"")
+
+	tagIndexes := allTagIndexes[transaction.TransactionId]
+
+	for i := 0; i < len(tagIndexes); i++ {
+		tag, exists := tagMap[tagIndexes[i]]
+
+		if !exists {
+			continue
+		}
+
+		tagName := c.getTagName(tag.Name)
+
+		if tagName == "" {
+			continue
+		}
+
+		ret.WriteString(" ")
+		ret.WriteRune(beancountTagPrefix)
+		ret.WriteString(tagName)
+	}
+
+	ret.WriteString("\n")
+
+	for i := 0; i < len(postings); i++ {
+		posting := postings[i]
+
+		ret.WriteString(beancountExportedPostingIndent)
+		ret.WriteString(posting.account)
+		ret.WriteString(" ")
+		ret.WriteString(utils.FormatAmount(posting.amount))
+		ret.WriteString(" ")
+		ret.WriteString(posting.commodity)
+
+		if posting.totalCost != "" {
+			ret.WriteString(" ")
+			ret.WriteRune(beancountPricePrefix)
+			ret.WriteRune(beancountPricePrefix)
+			ret.WriteString(" ")
+			ret.WriteString(posting.totalCost)
+		}
+
+		ret.WriteString("\n")
+	}
+
+	return ret.String()
+}
+
+func (c *beancountTransactionDataExporter) getBalanceAssertionEntries(balanceAssertionDates map[string]map[string]bool, accountPostings map[string][]*beancountExportedPosting, accountCurrencies map[string]string) []*beancountExportedEntry {
+	balanceAssertionEntries := make([]*beancountExportedEntry, 0)
+
+	for accountName, dates := range balanceAssertionDates {
+		postings := accountPostings[accountName]
+
+		for date := range dates {
+			balance := int64(0)
+
+			for i := 0; i < len(postings); i++ {
+				if postings[i].date <= date {
+					balance += postings[i].amount
+				}
+			}
+
+			// the balance assertion applies at the beginning of the date, so the balance after all postings of the reconciliation date is asserted in the next day
+			assertionDate := c.getNextDate(date)
+
+			balanceAssertionEntries = append(balanceAssertionEntries, &beancountExportedEntry{
+				date:      assertionDate,
+				entryType: beancountExportedBalanceEntry,
+				content:   fmt.Sprintf("%s %s %s %s %s\n", assertionDate, beancountDirectiveBalance, accountName, utils.FormatAmount(balance), accountCurrencies[accountName]),
+			})
+		}
+	}
+
+	return balanceAssertionEntries
+}
+
+func (c *beancountTransactionDataExporter) buildAccountNames(accountMap map[int64]*models.Account) map[int64]string {
+	accountIds := make([]int64, 0, len(accountMap))
+
+	for accountId := range accountMap {
+		accountIds = append(accountIds, accountId)
+	}
+
+	sort.Slice(accountIds, func(i, j int) bool {
+		return accountIds[i] < accountIds[j]
+	})
+
+	accountNames := make(map[int64]string, len(accountMap))
+	usedAccountNames := make(map[string]bool, len(accountMap))
+
+	for i := 0; i < len(accountIds); i++ {
+		account := accountMap[accountIds[i]]
+		topLevelAccount := account
+		nameItems := []string{c.getAccountNameItem(account.Name)}
+
+		if account.ParentAccountId != models.LevelOneAccountParentId {
+			if parentAccount, exists := accountMap[account.ParentAccountId]; exists {
+				topLevelAccount = parentAccount
+				nameItems = append([]string{c.getAccountNameItem(parentAccount.Name)}, nameItems...)
+			}
+		}
+
+		accountTypeName := beancountDefaultAssetsAccountTypeName
+
+		if topLevelAccount.Category.IsLiability() {
+			accountTypeName = beancountDefaultLiabilitiesAccountTypeName
+		}
+
+		accountName := accountTypeName + beancountAccountNameItemsSeparator + strings.Join(nameItems, beancountAccountNameItemsSeparator)
+
+		if usedAccountNames[accountName] {
+			accountName = accountName + "-" + utils.Int64ToString(account.AccountId)
+		}
+
+		accountNames[account.AccountId] = accountName
+		usedAccountNames[accountName] = true
+	}
+
+	return accountNames
+}
+
+func (c *beancountTransactionDataExporter) buildCategoryNames(categoryMap map[int64]*models.TransactionCategory) map[int64]string {
+	categoryIds := make([]int64, 0, len(categoryMap))
+
+	for categoryId := range categoryMap {
+		categoryIds = append(categoryIds, categoryId)
+	}
+
+	sort.Slice(categoryIds, func(i, j int) bool {
+		return categoryIds[i] < categoryIds[j]
+	})
+
+	categoryNames := make(map[int64]string, len(categoryMap))
+	usedCategoryNames := make(map[string]bool, len(categoryMap))
+
+	for i := 0; i < len(categoryIds); i++ {
+		category := categoryMap[categoryIds[i]]
+		nameItems := []string{c.getAccountNameItem(category.Name)}
+
+		if category.ParentCategoryId != models.LevelOneTransactionCategoryParentId {
+			if parentCategory, exists := categoryMap[category.ParentCategoryId]; exists {
+				nameItems = append([]string{c.getAccountNameItem(parentCategory.Name)}, nameItems...)
+			}
+		}
+
+		categoryName := strings.Join(nameItems, beancountAccountNameItemsSeparator)
+
+		if usedCategoryNames[categoryName] {
+			categoryName = categoryName + "-" + utils.Int64ToString(category.CategoryId)
+		}
+
+		categoryNames[category.CategoryId] = categoryName
+		usedCategoryNames[categoryName] = true
+	}
+
+	return categoryNames
+}
+
+func (c *beancountTransactionDataExporter) getCategoryName(categoryId int64, categoryNames map[int64]string, accountTypeName string) string {
+	categoryName, exists := categoryNames[categoryId]
+
+	if !exists {
+		categoryName = beancountExportedUnnamedAccountName
+	}
+
+	return accountTypeName + beancountAccountNameItemsSeparator + categoryName
+}
+
+// getAccountNameItem returns the account name item which only contains letters, numbers and dashes and starts with a capital letter or a number
+func (c *beancountTransactionDataExporter) getAccountNameItem(name string) string {
+	var ret strings.Builder
+	lastIsDash := false
+
+	for _, ch := range strings.TrimSpace(name) {
+		if unicode.IsLetter(ch) || unicode.IsDigit(ch) {
+			if ret.Len() == 0 {
+				ch = unicode.ToUpper(ch)
+			}
+
+			ret.WriteRune(ch)
+			lastIsDash = false
+		} else if ret.Len() > 0 && !lastIsDash {
+			ret.WriteRune('-')
+			lastIsDash = true
+		}
+	}
+
+	item := strings.TrimRight(ret.String(), "-")
+
+	if item == "" {
+		return beancountExportedUnnamedAccountName
+	}
+
+	return item
+}
+
+// getTagName returns the tag name which only contains letters, numbers, dashes, underscores, slashes and dots
+func (c *beancountTransactionDataExporter) getTagName(name string) string {
+	var ret strings.Builder
+
+	for _, ch := range strings.TrimSpace(name) {
+		if unicode.IsLetter(ch) || unicode.IsDigit(ch) || ch == '-' || ch == '_' || ch == '/' || ch == '.' {
+			ret.WriteRune(ch)
+		} else {
+			ret.WriteRune('-')
+		}
+	}
+
+	return ret.String()
+}
+
+func (c *beancountTransactionDataExporter) formatDate(transaction *models.Transaction, timezone *time.Location) string {
+	return time.Unix(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime), 0).In(timezone).Format(beancountExportedDateFormat)
+}
+
+func (c *beancountTransactionDataExporter) getNextDate(date string) string {
+	dateTime, err := time.Parse(beancountExportedDateFormat, date)
+
+	if err != nil {
+		return date
+	}
+
+	return dateTime.AddDate(0, 0, 1).Format(beancountExportedDateFormat)
+}
+
+func (c *beancountTransactionDataExporter) formatPrice(amount int64, relatedAmount int64) string {
+	scale := math.Pow10(beancountExportedPriceMaxDecimals)
+	price := math.Round(float64(relatedAmount)/float64(amount)*scale) / scale
+
+	return strconv.FormatFloat(price, 'f', -1, 64)
+}
@@ -3,7 +3,6 @@ package converter
 import (
 	"fmt"
 	"strings"
-	"time"
 
 	"github.com/mayswind/ezbookkeeping/pkg/converters/datatable"
 	"github.com/mayswind/ezbookkeeping/pkg/core"
@@ -18,8 +17,11 @@ type DataTableTransactionDataExporter struct {
 	transactionTagSeparator string
 }
 
-// BuildExportedContent writes the exported transaction data to the data table builder
-func (c *DataTableTransactionDataExporter) BuildExportedContent(ctx core.Context, dataTableBuilder datatable.TransactionDataTableBuilder, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64) error {
+// BuildExportedContent writes the exported transaction data to the data table builder,
+// the value of the nth custom field is written to the data column returned by datatable.GetCustomFieldDataTableColumn(n)
+func (c *DataTableTransactionDataExporter) BuildExportedContent(ctx core.Context, dataTableBuilder datatable.TransactionDataTableBuilder, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64, customFields []*models.TransactionCustomField, allCustomFieldValues map[int64][]*models.TransactionCustomFieldValue, options TransactionDataExporterOptions) error {
+	customFields = datatable.LimitCustomFieldsCount(customFields)
+
 	for i := 0; i < len(transactions); i++ {
 		transaction := transactions[i]
 
@@ -27,9 +29,9 @@ func (c *DataTableTransactionDataExporter) BuildExportedContent(ctx core.Context
 			continue
 		}
 
-		dataRowMap := make(map[datatable.TransactionDataTableColumn]string, 15)
+		dataRowMap := make(map[datatable.TransactionDataTableColumn]string, 15+len(customFields))
 		transactionUnixTime := utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime)
-		transactionTimeZone := time.FixedZone("Transaction Timezone", int(transaction.TimezoneUtcOffset)*60)
+		transactionTimeZone := options.GetTransactionTimezone(transaction)
 
 		dataRowMap[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIME] = utils.FormatUnixTimeToLongDateTime(transactionUnixTime, transactionTimeZone)
 		dataRowMap[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIMEZONE] = utils.FormatTimezoneOffset(transactionUnixTime, transactionTimeZone)
@@ -50,6 +52,10 @@ func (c *DataTableTransactionDataExporter) BuildExportedContent(ctx core.Context
 		dataRowMap[datatable.TRANSACTION_DATA_TABLE_TAGS] = c.getExportedTags(dataTableBuilder, transaction.TransactionId, allTagIndexes, tagMap)
 		dataRowMap[datatable.TRANSACTION_DATA_TABLE_DESCRIPTION] = dataTableBuilder.ReplaceDelimiters(transaction.Comment)
 
+		if len(customFields) > 0 {
+			c.fillExportedCustomFieldValues(dataTableBuilder, dataRowMap, customFields, allCustomFieldValues[transaction.TransactionId])
+		}
+
 		dataTableBuilder.AppendTransaction(dataRowMap)
 	}
 
@@ -157,6 +163,24 @@ func (c *DataTableTransactionDataExporter) getExportedTags(dataTableBuilder data
 	return dataTableBuilder.ReplaceDelimiters(ret.String())
 }
 
+func (c *DataTableTransactionDataExporter) fillExportedCustomFieldValues(dataTableBuilder datatable.TransactionDataTableBuilder, dataRowMap map[datatable.TransactionDataTableColumn]string, customFields []*models.TransactionCustomField, customFieldValues []*models.TransactionCustomFieldValue) {
+	if len(customFieldValues) < 1 {
+		return
+	}
+
+	customFieldValueMap := make(map[int64]string, len(customFieldValues))
+
+	for i := 0; i < len(customFieldValues); i++ {
+		customFieldValueMap[customFieldValues[i].FieldId] = customFieldValues[i].TextValue
+	}
+
+	for i := 0; i < len(customFields); i++ {
+		if value, exists := customFieldValueMap[customFields[i].FieldId]; exists {
+			dataRowMap[datatable.GetCustomFieldDataTableColumn(i)] = dataTableBuilder.ReplaceDelimiters(value)
+		}
+	}
+}
+
 // CreateNewExporter returns a new data table transaction data exporter according to the specified arguments
 func CreateNewExporter(transactionTypeMapping map[models.TransactionType]string, geoLocationSeparator string, transactionTagSeparator string) *DataTableTransactionDataExporter {
 	return &DataTableTransactionDataExporter{
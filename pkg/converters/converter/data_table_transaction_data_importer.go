@@ -322,7 +322,7 @@ func (c *DataTableTransactionDataImporter) ParseImportedData(ctx core.Context, u
 					continue
 				}
 
-				allNewTags, tagIds, tagNames = c.addTag(user, tagName, tagNamesMap, tagMap, allNewTags, tagIds, tagNames)
+				allNewTags, tagIds, tagNames = c.addTag(user, additionalOptions, tagName, tagNamesMap, tagMap, allNewTags, tagIds, tagNames)
 			}
 		}
 
@@ -330,7 +330,7 @@ func (c *DataTableTransactionDataImporter) ParseImportedData(ctx core.Context, u
 			payee := dataRow.GetData(datatable.TRANSACTION_DATA_TABLE_PAYEE)
 
 			if payee != "" {
-				allNewTags, tagIds, tagNames = c.addTag(user, payee, tagNamesMap, tagMap, allNewTags, tagIds, tagNames)
+				allNewTags, tagIds, tagNames = c.addTag(user, additionalOptions, payee, tagNamesMap, tagMap, allNewTags, tagIds, tagNames)
 			}
 		}
 
@@ -338,7 +338,7 @@ func (c *DataTableTransactionDataImporter) ParseImportedData(ctx core.Context, u
 			member := dataRow.GetData(datatable.TRANSACTION_DATA_TABLE_MEMBER)
 
 			if member != "" {
-				allNewTags, tagIds, tagNames = c.addTag(user, member, tagNamesMap, tagMap, allNewTags, tagIds, tagNames)
+				allNewTags, tagIds, tagNames = c.addTag(user, additionalOptions, member, tagNamesMap, tagMap, allNewTags, tagIds, tagNames)
 			}
 		}
 
@@ -346,7 +346,7 @@ func (c *DataTableTransactionDataImporter) ParseImportedData(ctx core.Context, u
 			project := dataRow.GetData(datatable.TRANSACTION_DATA_TABLE_PROJECT)
 
 			if project != "" {
-				allNewTags, tagIds, tagNames = c.addTag(user, project, tagNamesMap, tagMap, allNewTags, tagIds, tagNames)
+				allNewTags, tagIds, tagNames = c.addTag(user, additionalOptions, project, tagNamesMap, tagMap, allNewTags, tagIds, tagNames)
 			}
 		}
 
@@ -354,7 +354,7 @@ func (c *DataTableTransactionDataImporter) ParseImportedData(ctx core.Context, u
 			merchant := dataRow.GetData(datatable.TRANSACTION_DATA_TABLE_MERCHANT)
 
 			if merchant != "" {
-				allNewTags, tagIds, tagNames = c.addTag(user, merchant, tagNamesMap, tagMap, allNewTags, tagIds, tagNames)
+				allNewTags, tagIds, tagNames = c.addTag(user, additionalOptions, merchant, tagNamesMap, tagMap, allNewTags, tagIds, tagNames)
 			}
 		}
 
@@ -392,6 +392,7 @@ func (c *DataTableTransactionDataImporter) ParseImportedData(ctx core.Context, u
 			OriginalDestinationAccountName:     account2Name,
 			OriginalDestinationAccountCurrency: account2Currency,
 			OriginalTagNames:                   tagNames,
+			CustomFieldValues:                  c.getCustomFieldValues(dataTable, dataRow, additionalOptions.GetCustomFieldIds()),
 		}
 
 		allNewTransactions = append(allNewTransactions, transaction)
@@ -485,12 +486,12 @@ func (c *DataTableTransactionDataImporter) getTransactionCategory(categories map
 	return subCategory, exists
 }
 
-func (c *DataTableTransactionDataImporter) addTag(user *models.User, tagName string, tagNamesMap map[string]bool, tagMap map[string]*models.TransactionTag, allNewTags []*models.TransactionTag, tagIds []string, tagNames []string) ([]*models.TransactionTag, []string, []string) {
+func (c *DataTableTransactionDataImporter) addTag(user *models.User, additionalOptions TransactionDataImporterOptions, tagName string, tagNamesMap map[string]bool, tagMap map[string]*models.TransactionTag, allNewTags []*models.TransactionTag, tagIds []string, tagNames []string) ([]*models.TransactionTag, []string, []string) {
 	if tagName != "" && !tagNamesMap[tagName] {
 		tag, exists := tagMap[tagName]
 
 		if !exists {
-			tag = c.createNewTransactionTagModel(user.Uid, tagName)
+			tag = c.createNewTransactionTagModel(user.Uid, additionalOptions.GetTagGroupId(), tagName)
 			allNewTags = append(allNewTags, tag)
 			tagMap[tagName] = tag
 		}
@@ -506,6 +507,31 @@ func (c *DataTableTransactionDataImporter) addTag(user *models.User, tagName str
 	return allNewTags, tagIds, tagNames
 }
 
+func (c *DataTableTransactionDataImporter) getCustomFieldValues(dataTable datatable.TransactionDataTable, dataRow datatable.TransactionDataRow, customFieldIds []int64) []*models.TransactionCustomFieldValueResponse {
+	var customFieldValues []*models.TransactionCustomFieldValueResponse
+
+	for i := 0; i < len(customFieldIds) && i < datatable.TRANSACTION_DATA_TABLE_MAX_CUSTOM_FIELD_COUNT; i++ {
+		column := datatable.GetCustomFieldDataTableColumn(i)
+
+		if !dataTable.HasColumn(column) {
+			continue
+		}
+
+		value := strings.TrimSpace(dataRow.GetData(column))
+
+		if value == "" {
+			continue
+		}
+
+		customFieldValues = append(customFieldValues, &models.TransactionCustomFieldValueResponse{
+			FieldId: customFieldIds[i],
+			Value:   value,
+		})
+	}
+
+	return customFieldValues
+}
+
 func (c *DataTableTransactionDataImporter) createNewAccountModel(uid int64, accountName string, currency string) *models.Account {
 	return &models.Account{
 		Uid:      uid,
@@ -522,10 +548,11 @@ func (c *DataTableTransactionDataImporter) createNewTransactionCategoryModel(uid
 	}
 }
 
-func (c *DataTableTransactionDataImporter) createNewTransactionTagModel(uid int64, tagName string) *models.TransactionTag {
+func (c *DataTableTransactionDataImporter) createNewTransactionTagModel(uid int64, tagGroupId int64, tagName string) *models.TransactionTag {
 	return &models.TransactionTag{
-		Uid:  uid,
-		Name: tagName,
+		Uid:        uid,
+		TagGroupId: tagGroupId,
+		Name:       tagName,
 	}
 }
 
@@ -1,6 +1,7 @@
 package converter
 
 import (
+	"io"
 	"time"
 
 	"github.com/mayswind/ezbookkeeping/pkg/core"
@@ -10,7 +11,23 @@ import (
 // TransactionDataExporter defines the structure of transaction data exporter
 type TransactionDataExporter interface {
 	// ToExportedContent returns the exported data
-	ToExportedContent(ctx core.Context, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64) ([]byte, error)
+	ToExportedContent(ctx core.Context, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64, customFields []*models.TransactionCustomField, allCustomFieldValues map[int64][]*models.TransactionCustomFieldValue, options TransactionDataExporterOptions) ([]byte, error)
+}
+
+// ExportedTransactionDataPage represents a page of exported transactions with their tag indexes and custom field values
+type ExportedTransactionDataPage struct {
+	Transactions         []*models.Transaction
+	AllTagIndexes        map[int64][]int64
+	AllCustomFieldValues map[int64][]*models.TransactionCustomFieldValue
+}
+
+// ExportedTransactionDataPageReader represents the function that returns the next page of exported transactions, returns nil when there is no more page
+type ExportedTransactionDataPageReader func() (*ExportedTransactionDataPage, error)
+
+// TransactionDataStreamExporter defines the structure of transaction data exporter which writes the exported data page by page
+type TransactionDataStreamExporter interface {
+	// WriteExportedContent writes the exported data of all pages to the specified writer
+	WriteExportedContent(ctx core.Context, writer io.Writer, uid int64, pageReader ExportedTransactionDataPageReader, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, customFields []*models.TransactionCustomField, options TransactionDataExporterOptions) error
 }
 
 // TransactionDataImporter defines the structure of transaction data importer
@@ -0,0 +1,173 @@
+package converter
+
+import (
+	"strings"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/datatable"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+// transactionDataExporterCustomFieldsColumnName is the textual column name which represents the data columns of all custom fields
+const transactionDataExporterCustomFieldsColumnName = "customFields"
+
+// transactionDataExporterColumnNameMapping is the mapping of textual column name and data table column
+var transactionDataExporterColumnNameMapping = map[string]datatable.TransactionDataTableColumn{
+	"time":             datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIME,
+	"timezone":         datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIMEZONE,
+	"type":             datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE,
+	"category":         datatable.TRANSACTION_DATA_TABLE_CATEGORY,
+	"subCategory":      datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY,
+	"account":          datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME,
+	"accountCurrency":  datatable.TRANSACTION_DATA_TABLE_ACCOUNT_CURRENCY,
+	"amount":           datatable.TRANSACTION_DATA_TABLE_AMOUNT,
+	"account2":         datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME,
+	"account2Currency": datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_CURRENCY,
+	"account2Amount":   datatable.TRANSACTION_DATA_TABLE_RELATED_AMOUNT,
+	"geoLocation":      datatable.TRANSACTION_DATA_TABLE_GEOGRAPHIC_LOCATION,
+	"tags":             datatable.TRANSACTION_DATA_TABLE_TAGS,
+	"description":      datatable.TRANSACTION_DATA_TABLE_DESCRIPTION,
+}
+
+// TransactionDataExporterOptions defines the options for transaction data exporter
+type TransactionDataExporterOptions struct {
+	timezone          *time.Location
+	columns           []datatable.TransactionDataTableColumn
+	withCustomFields  bool
+	customFieldsIndex int
+}
+
+// DefaultExporterOptions provides the default options for transaction data exporter
+var DefaultExporterOptions = TransactionDataExporterOptions{
+	timezone: nil,
+	columns:  nil,
+}
+
+// GetTimezone returns the timezone which all the exported dates are converted to, returns nil if each transaction uses its own timezone
+func (o TransactionDataExporterOptions) GetTimezone() *time.Location {
+	return o.timezone
+}
+
+// GetTransactionTimezone returns the timezone which the date of the specified transaction is exported in
+func (o TransactionDataExporterOptions) GetTransactionTimezone(transaction *models.Transaction) *time.Location {
+	if o.timezone != nil {
+		return o.timezone
+	}
+
+	return time.FixedZone("Transaction Timezone", int(transaction.TimezoneUtcOffset)*60)
+}
+
+// GetSelectedDataColumns returns the selected columns in the specified data columns by the selected order,
+// the custom field data columns are kept together in the position of "customFields", returns all the specified data columns if there are no selected columns
+func (o TransactionDataExporterOptions) GetSelectedDataColumns(dataColumns []datatable.TransactionDataTableColumn) []datatable.TransactionDataTableColumn {
+	if o.columns == nil {
+		return dataColumns
+	}
+
+	availableColumns := make(map[datatable.TransactionDataTableColumn]bool, len(dataColumns))
+	customFieldColumns := make([]datatable.TransactionDataTableColumn, 0)
+
+	for i := 0; i < len(dataColumns); i++ {
+		if datatable.IsCustomFieldDataTableColumn(dataColumns[i]) {
+			customFieldColumns = append(customFieldColumns, dataColumns[i])
+		} else {
+			availableColumns[dataColumns[i]] = true
+		}
+	}
+
+	selectedColumns := make([]datatable.TransactionDataTableColumn, 0, len(o.columns)+len(customFieldColumns))
+
+	for i := 0; i < len(o.columns); i++ {
+		if o.withCustomFields && o.customFieldsIndex == i {
+			selectedColumns = append(selectedColumns, customFieldColumns...)
+		}
+
+		if availableColumns[o.columns[i]] {
+			selectedColumns = append(selectedColumns, o.columns[i])
+		}
+	}
+
+	if o.withCustomFields && o.customFieldsIndex >= len(o.columns) {
+		selectedColumns = append(selectedColumns, customFieldColumns...)
+	}
+
+	return selectedColumns
+}
+
+// WithTimezone sets the timezone which all the exported dates are converted to
+func (o TransactionDataExporterOptions) WithTimezone(timezone *time.Location) TransactionDataExporterOptions {
+	cloned := o.Clone()
+	cloned.timezone = timezone
+	return cloned
+}
+
+// WithColumns sets the selected columns by the textual column names (e.g. "time,type,amount,customFields")
+func (o TransactionDataExporterOptions) WithColumns(columnNames string) (TransactionDataExporterOptions, error) {
+	cloned := o.Clone()
+
+	if columnNames == "" {
+		cloned.columns = nil
+		cloned.withCustomFields = false
+		cloned.customFieldsIndex = 0
+		return cloned, nil
+	}
+
+	items := strings.Split(columnNames, ",")
+	cloned.columns = make([]datatable.TransactionDataTableColumn, 0, len(items))
+	cloned.withCustomFields = false
+	cloned.customFieldsIndex = 0
+	addedColumns := make(map[datatable.TransactionDataTableColumn]bool, len(items))
+
+	for i := 0; i < len(items); i++ {
+		columnName := strings.TrimSpace(items[i])
+
+		if columnName == transactionDataExporterCustomFieldsColumnName {
+			if cloned.withCustomFields {
+				return o, errs.ErrExportColumnsInvalid
+			}
+
+			cloned.withCustomFields = true
+			cloned.customFieldsIndex = len(cloned.columns)
+			continue
+		}
+
+		column, exists := transactionDataExporterColumnNameMapping[columnName]
+
+		if !exists || addedColumns[column] {
+			return o, errs.ErrExportColumnsInvalid
+		}
+
+		cloned.columns = append(cloned.columns, column)
+		addedColumns[column] = true
+	}
+
+	return cloned, nil
+}
+
+// Clone creates a copy of the options instance
+func (o TransactionDataExporterOptions) Clone() TransactionDataExporterOptions {
+	return TransactionDataExporterOptions{
+		timezone:          o.timezone,
+		columns:           o.columns,
+		withCustomFields:  o.withCustomFields,
+		customFieldsIndex: o.customFieldsIndex,
+	}
+}
+
+// ParseExporterOptions parses the textual timezone name and column names to the instance
+func ParseExporterOptions(timezoneName string, columnNames string) (TransactionDataExporterOptions, error) {
+	options := DefaultExporterOptions
+
+	if timezoneName != "" {
+		timezone, err := time.LoadLocation(timezoneName)
+
+		if err != nil || timezone == nil {
+			return options, errs.ErrExportTimezoneInvalid
+		}
+
+		options = options.WithTimezone(timezone)
+	}
+
+	return options.WithColumns(columnNames)
+}
@@ -0,0 +1,93 @@
+package converter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/datatable"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+func TestParseExporterOptions(t *testing.T) {
+	actualValue, err := ParseExporterOptions("", "")
+	assert.Nil(t, err)
+	assert.Nil(t, actualValue.GetTimezone())
+
+	dataColumns := []datatable.TransactionDataTableColumn{
+		datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIME,
+		datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE,
+		datatable.TRANSACTION_DATA_TABLE_AMOUNT,
+	}
+	assert.Equal(t, dataColumns, actualValue.GetSelectedDataColumns(dataColumns))
+
+	actualValue, err = ParseExporterOptions("Asia/Shanghai", "")
+	assert.Nil(t, err)
+	assert.Equal(t, "Asia/Shanghai", actualValue.GetTimezone().String())
+}
+
+func TestParseExporterOptions_InvalidTimezone(t *testing.T) {
+	_, err := ParseExporterOptions("Invalid/Timezone", "")
+	assert.EqualError(t, err, errs.ErrExportTimezoneInvalid.Message)
+}
+
+func TestParseExporterOptions_InvalidColumns(t *testing.T) {
+	_, err := ParseExporterOptions("", "time,unknown")
+	assert.EqualError(t, err, errs.ErrExportColumnsInvalid.Message)
+
+	_, err = ParseExporterOptions("", "time,amount,time")
+	assert.EqualError(t, err, errs.ErrExportColumnsInvalid.Message)
+
+	_, err = ParseExporterOptions("", "customFields,time,customFields")
+	assert.EqualError(t, err, errs.ErrExportColumnsInvalid.Message)
+}
+
+func TestTransactionDataExporterOptionsGetTransactionTimezone(t *testing.T) {
+	transaction := &models.Transaction{
+		TransactionTime:   1725165296000,
+		TimezoneUtcOffset: 480,
+	}
+
+	timezone := DefaultExporterOptions.GetTransactionTimezone(transaction)
+	_, offset := time.Unix(1725165296, 0).In(timezone).Zone()
+	assert.Equal(t, 480*60, offset)
+
+	timezone = DefaultExporterOptions.WithTimezone(time.UTC).GetTransactionTimezone(transaction)
+	assert.Equal(t, time.UTC, timezone)
+}
+
+func TestTransactionDataExporterOptionsGetSelectedDataColumns(t *testing.T) {
+	dataColumns := []datatable.TransactionDataTableColumn{
+		datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIME,
+		datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE,
+		datatable.TRANSACTION_DATA_TABLE_AMOUNT,
+		datatable.TRANSACTION_DATA_TABLE_DESCRIPTION,
+		datatable.GetCustomFieldDataTableColumn(0),
+		datatable.GetCustomFieldDataTableColumn(1),
+	}
+
+	options, err := DefaultExporterOptions.WithColumns("amount, customFields, time")
+	assert.Nil(t, err)
+	assert.Equal(t, []datatable.TransactionDataTableColumn{
+		datatable.TRANSACTION_DATA_TABLE_AMOUNT,
+		datatable.GetCustomFieldDataTableColumn(0),
+		datatable.GetCustomFieldDataTableColumn(1),
+		datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIME,
+	}, options.GetSelectedDataColumns(dataColumns))
+
+	options, err = DefaultExporterOptions.WithColumns("description,tags,customFields")
+	assert.Nil(t, err)
+	assert.Equal(t, []datatable.TransactionDataTableColumn{
+		datatable.TRANSACTION_DATA_TABLE_DESCRIPTION,
+		datatable.GetCustomFieldDataTableColumn(0),
+		datatable.GetCustomFieldDataTableColumn(1),
+	}, options.GetSelectedDataColumns(dataColumns))
+
+	options, err = DefaultExporterOptions.WithColumns("type")
+	assert.Nil(t, err)
+	assert.Equal(t, []datatable.TransactionDataTableColumn{
+		datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE,
+	}, options.GetSelectedDataColumns(dataColumns))
+}
@@ -1,6 +1,10 @@
 package converter
 
-import "strings"
+import (
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
 
 // TransactionDataImporterOptions defines the options for transaction data importer
 type TransactionDataImporterOptions struct {
@@ -9,6 +13,8 @@ type TransactionDataImporterOptions struct {
 	memberAsTag        bool
 	projectAsTag       bool
 	merchantAsTag      bool
+	tagGroupId         int64
+	customFieldIds     []int64
 }
 
 // DefaultImporterOptions provides the default options for transaction data importer
@@ -18,6 +24,7 @@ var DefaultImporterOptions = TransactionDataImporterOptions{
 	memberAsTag:        false,
 	projectAsTag:       false,
 	merchantAsTag:      false,
+	tagGroupId:         0,
 }
 
 // IsPayeeAsTag returns whether to import payee as tag
@@ -45,6 +52,16 @@ func (o TransactionDataImporterOptions) IsMerchantAsTag() bool {
 	return o.merchantAsTag
 }
 
+// GetTagGroupId returns the tag group id which the new tags are created into
+func (o TransactionDataImporterOptions) GetTagGroupId() int64 {
+	return o.tagGroupId
+}
+
+// GetCustomFieldIds returns the custom field ids of custom field data columns, the value in the nth custom field data column belongs to the nth custom field
+func (o TransactionDataImporterOptions) GetCustomFieldIds() []int64 {
+	return o.customFieldIds
+}
+
 // WithPayeeAsTag sets the option to import payee as tag
 func (o TransactionDataImporterOptions) WithPayeeAsTag() TransactionDataImporterOptions {
 	cloned := o.Clone()
@@ -80,6 +97,20 @@ func (o TransactionDataImporterOptions) WithMerchantAsTag() TransactionDataImpor
 	return cloned
 }
 
+// WithTagGroupId sets the tag group id which the new tags are created into
+func (o TransactionDataImporterOptions) WithTagGroupId(tagGroupId int64) TransactionDataImporterOptions {
+	cloned := o.Clone()
+	cloned.tagGroupId = tagGroupId
+	return cloned
+}
+
+// WithCustomFieldIds sets the custom field ids of custom field data columns
+func (o TransactionDataImporterOptions) WithCustomFieldIds(customFieldIds []int64) TransactionDataImporterOptions {
+	cloned := o.Clone()
+	cloned.customFieldIds = customFieldIds
+	return cloned
+}
+
 // Clone creates a copy of the options instance
 func (o TransactionDataImporterOptions) Clone() TransactionDataImporterOptions {
 	return TransactionDataImporterOptions{
@@ -88,6 +119,8 @@ func (o TransactionDataImporterOptions) Clone() TransactionDataImporterOptions {
 		memberAsTag:        o.memberAsTag,
 		projectAsTag:       o.projectAsTag,
 		merchantAsTag:      o.merchantAsTag,
+		tagGroupId:         o.tagGroupId,
+		customFieldIds:     o.customFieldIds,
 	}
 }
 
@@ -111,6 +144,14 @@ func ParseImporterOptions(s string) TransactionDataImporterOptions {
 			options.projectAsTag = true
 		case "merchantAsTag":
 			options.merchantAsTag = true
+		default:
+			if strings.HasPrefix(option, "tagGroupId:") {
+				tagGroupId, err := utils.StringToInt64(option[len("tagGroupId:"):])
+
+				if err == nil && tagGroupId > 0 {
+					options.tagGroupId = tagGroupId
+				}
+			}
 		}
 	}
 
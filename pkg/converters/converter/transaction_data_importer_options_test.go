@@ -108,3 +108,16 @@ func TestParseImporterOptions_Clone(t *testing.T) {
 	assert.Equal(t, true, cloned.projectAsTag)
 	assert.Equal(t, false, cloned.merchantAsTag)
 }
+
+func TestParseImporterOptions_WithTagGroupId(t *testing.T) {
+	actualValue := ParseImporterOptions("payeeAsTag,tagGroupId:123")
+	assert.Equal(t, true, actualValue.IsPayeeAsTag())
+	assert.Equal(t, int64(123), actualValue.GetTagGroupId())
+
+	actualValue = ParseImporterOptions("tagGroupId:abc")
+	assert.Equal(t, int64(0), actualValue.GetTagGroupId())
+
+	actualValue = DefaultImporterOptions.WithTagGroupId(456)
+	assert.Equal(t, int64(456), actualValue.GetTagGroupId())
+	assert.Equal(t, int64(0), DefaultImporterOptions.GetTagGroupId())
+}
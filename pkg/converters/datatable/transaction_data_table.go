@@ -78,5 +78,31 @@ const (
 	TRANSACTION_DATA_TABLE_MERCHANT                 TransactionDataTableColumn = 104
 )
 
+// TRANSACTION_DATA_TABLE_CUSTOM_FIELD_BASE represents the data column of the first transaction custom field,
+// the data column of other custom fields is this value plus the index of custom field
+const TRANSACTION_DATA_TABLE_CUSTOM_FIELD_BASE TransactionDataTableColumn = 200
+
+// TRANSACTION_DATA_TABLE_MAX_CUSTOM_FIELD_COUNT represents the maximum count of transaction custom field data columns
+const TRANSACTION_DATA_TABLE_MAX_CUSTOM_FIELD_COUNT = 255 - int(TRANSACTION_DATA_TABLE_CUSTOM_FIELD_BASE) + 1
+
+// GetCustomFieldDataTableColumn returns the data column of the transaction custom field in the specified index
+func GetCustomFieldDataTableColumn(index int) TransactionDataTableColumn {
+	return TRANSACTION_DATA_TABLE_CUSTOM_FIELD_BASE + TransactionDataTableColumn(index)
+}
+
+// IsCustomFieldDataTableColumn returns whether the data column is the data column of transaction custom field
+func IsCustomFieldDataTableColumn(column TransactionDataTableColumn) bool {
+	return column >= TRANSACTION_DATA_TABLE_CUSTOM_FIELD_BASE
+}
+
+// LimitCustomFieldsCount returns the custom fields which can be written to data columns
+func LimitCustomFieldsCount(customFields []*models.TransactionCustomField) []*models.TransactionCustomField {
+	if len(customFields) > TRANSACTION_DATA_TABLE_MAX_CUSTOM_FIELD_COUNT {
+		return customFields[:TRANSACTION_DATA_TABLE_MAX_CUSTOM_FIELD_COUNT]
+	}
+
+	return customFields
+}
+
 // TRANSACTION_DATA_TABLE_TIMEZONE_NOT_AVAILABLE represents the constant for timezone not available
 const TRANSACTION_DATA_TABLE_TIMEZONE_NOT_AVAILABLE = "TIMEZONE_NOT_AVAILABLE"
@@ -1,6 +1,8 @@
 package _default
 
 import (
+	"io"
+	"strings"
 	"time"
 
 	"github.com/mayswind/ezbookkeeping/pkg/converters/converter"
@@ -61,22 +63,12 @@ var ezbookkeepingDataColumns = []datatable.TransactionDataTableColumn{
 }
 
 // ToExportedContent returns the exported transaction plain text data
-func (c *defaultTransactionDataPlainTextConverter) ToExportedContent(ctx core.Context, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64) ([]byte, error) {
-	dataTableBuilder := createNewDefaultTransactionPlainTextDataTableBuilder(
-		len(transactions),
-		ezbookkeepingDataColumns,
-		ezbookkeepingDataColumnNameMapping,
-		c.columnSeparator,
-		ezbookkeepingLineSeparator,
-	)
+func (c *defaultTransactionDataPlainTextConverter) ToExportedContent(ctx core.Context, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64, customFields []*models.TransactionCustomField, allCustomFieldValues map[int64][]*models.TransactionCustomFieldValue, options converter.TransactionDataExporterOptions) ([]byte, error) {
+	customFields = datatable.LimitCustomFieldsCount(customFields)
+	dataTableBuilder := c.createDataTableBuilder(len(transactions), customFields, options)
+	dataTableExporter := c.createDataTableExporter()
 
-	dataTableExporter := converter.CreateNewExporter(
-		ezbookkeepingTransactionTypeNameMapping,
-		ezbookkeepingGeoLocationSeparator,
-		ezbookkeepingTagSeparator,
-	)
-
-	err := dataTableExporter.BuildExportedContent(ctx, dataTableBuilder, uid, transactions, accountMap, categoryMap, tagMap, allTagIndexes)
+	err := dataTableExporter.BuildExportedContent(ctx, dataTableBuilder, uid, transactions, accountMap, categoryMap, tagMap, allTagIndexes, customFields, allCustomFieldValues, options)
 
 	if err != nil {
 		return nil, err
@@ -85,6 +77,40 @@ func (c *defaultTransactionDataPlainTextConverter) ToExportedContent(ctx core.Co
 	return []byte(dataTableBuilder.String()), nil
 }
 
+// WriteExportedContent writes the exported transaction plain text data to the specified writer page by page,
+// only the data of current page is kept in memory
+func (c *defaultTransactionDataPlainTextConverter) WriteExportedContent(ctx core.Context, writer io.Writer, uid int64, pageReader converter.ExportedTransactionDataPageReader, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, customFields []*models.TransactionCustomField, options converter.TransactionDataExporterOptions) error {
+	customFields = datatable.LimitCustomFieldsCount(customFields)
+	dataTableBuilder := c.createDataTableBuilder(0, customFields, options)
+	dataTableExporter := c.createDataTableExporter()
+
+	if err := dataTableBuilder.flush(writer); err != nil {
+		return err
+	}
+
+	for {
+		page, err := pageReader()
+
+		if err != nil {
+			return err
+		}
+
+		if page == nil {
+			return nil
+		}
+
+		err = dataTableExporter.BuildExportedContent(ctx, dataTableBuilder, uid, page.Transactions, accountMap, categoryMap, tagMap, page.AllTagIndexes, customFields, page.AllCustomFieldValues, options)
+
+		if err != nil {
+			return err
+		}
+
+		if err = dataTableBuilder.flush(writer); err != nil {
+			return err
+		}
+	}
+}
+
 // ParseImportedData returns the imported data by parsing the transaction plain text data
 func (c *defaultTransactionDataPlainTextConverter) ParseImportedData(ctx core.Context, user *models.User, data []byte, defaultTimezone *time.Location, additionalOptions converter.TransactionDataImporterOptions, accountMap map[string]*models.Account, expenseCategoryMap map[string]map[string]*models.TransactionCategory, incomeCategoryMap map[string]map[string]*models.TransactionCategory, transferCategoryMap map[string]map[string]*models.TransactionCategory, tagMap map[string]*models.TransactionTag) (models.ImportedTransactionSlice, []*models.Account, []*models.TransactionCategory, []*models.TransactionCategory, []*models.TransactionCategory, []*models.TransactionTag, error) {
 	dataTable, err := createNewDefaultPlainTextDataTable(
@@ -108,3 +134,51 @@ func (c *defaultTransactionDataPlainTextConverter) ParseImportedData(ctx core.Co
 
 	return dataTableImporter.ParseImportedData(ctx, user, transactionDataTable, defaultTimezone, additionalOptions, accountMap, expenseCategoryMap, incomeCategoryMap, transferCategoryMap, tagMap)
 }
+
+func (c *defaultTransactionDataPlainTextConverter) createDataTableBuilder(transactionCount int, customFields []*models.TransactionCustomField, options converter.TransactionDataExporterOptions) *defaultTransactionPlainTextDataTableBuilder {
+	dataColumns := ezbookkeepingDataColumns
+	dataColumnNameMapping := ezbookkeepingDataColumnNameMapping
+
+	if len(customFields) > 0 {
+		dataColumns, dataColumnNameMapping = c.getDataColumnsWithCustomFields(customFields)
+	}
+
+	dataColumns = options.GetSelectedDataColumns(dataColumns)
+
+	return createNewDefaultTransactionPlainTextDataTableBuilder(
+		transactionCount,
+		dataColumns,
+		dataColumnNameMapping,
+		c.columnSeparator,
+		ezbookkeepingLineSeparator,
+	)
+}
+
+func (c *defaultTransactionDataPlainTextConverter) createDataTableExporter() *converter.DataTableTransactionDataExporter {
+	return converter.CreateNewExporter(
+		ezbookkeepingTransactionTypeNameMapping,
+		ezbookkeepingGeoLocationSeparator,
+		ezbookkeepingTagSeparator,
+	)
+}
+
+// getDataColumnsWithCustomFields returns the data columns and column names which contain the columns of all custom fields after the default columns
+func (c *defaultTransactionDataPlainTextConverter) getDataColumnsWithCustomFields(customFields []*models.TransactionCustomField) ([]datatable.TransactionDataTableColumn, map[datatable.TransactionDataTableColumn]string) {
+	dataColumns := make([]datatable.TransactionDataTableColumn, 0, len(ezbookkeepingDataColumns)+len(customFields))
+	dataColumns = append(dataColumns, ezbookkeepingDataColumns...)
+	dataColumnNameMapping := make(map[datatable.TransactionDataTableColumn]string, len(ezbookkeepingDataColumnNameMapping)+len(customFields))
+
+	for column, columnName := range ezbookkeepingDataColumnNameMapping {
+		dataColumnNameMapping[column] = columnName
+	}
+
+	for i := 0; i < len(customFields); i++ {
+		column := datatable.GetCustomFieldDataTableColumn(i)
+		columnName := strings.NewReplacer("\r", " ", "\n", " ", c.columnSeparator, " ").Replace(customFields[i].Name)
+
+		dataColumns = append(dataColumns, column)
+		dataColumnNameMapping[column] = columnName
+	}
+
+	return dataColumns, dataColumnNameMapping
+}
@@ -1,6 +1,10 @@
 package _default
 
 import (
+	"bytes"
+	"fmt"
+	"io"
+	"runtime"
 	"testing"
 	"time"
 
@@ -121,12 +125,281 @@ func TestDefaultTransactionDataCSVFileConverterToExportedContent(t *testing.T) {
 		"2024-09-01 12:34:56,+08:00,Income,Test Category,Test Sub Category,Test Account,CNY,123.45,,,,123.450000 45.670000,Test Tag;Test Tag2,Hello World\n" +
 		"2024-09-01 12:34:56,+00:00,Expense,Test Category2,Test Sub Category2,Test Account,CNY,-0.10,,,,,Test Tag,Foo#Bar\n" +
 		"2024-09-01 12:34:56,-05:00,Transfer,Test Category3,Test Sub Category3,Test Account,CNY,123.45,Test Account2,USD,17.35,,Test Tag2,T\te s t test\n"
-	actualContent, err := exporter.ToExportedContent(context, 123, transactions, accountMap, categoryMap, tagMap, allTagIndexes)
+	actualContent, err := exporter.ToExportedContent(context, 123, transactions, accountMap, categoryMap, tagMap, allTagIndexes, nil, nil, converter.DefaultExporterOptions)
 
 	assert.Nil(t, err)
 	assert.Equal(t, expectedContent, string(actualContent))
 }
 
+func TestDefaultTransactionDataCSVFileConverterToExportedContent_WithSelectedColumnsAndTimezone(t *testing.T) {
+	exporter := DefaultTransactionDataCSVFileConverter
+	context := core.NewNullContext()
+
+	transactions := make([]*models.Transaction, 2)
+	transactions[0] = &models.Transaction{
+		TransactionId:     1,
+		TransactionTime:   1725165296000,
+		Type:              models.TRANSACTION_DB_TYPE_INCOME,
+		TimezoneUtcOffset: 480,
+		CategoryId:        1,
+		AccountId:         1,
+		Amount:            12345,
+		Comment:           "Hello World",
+	}
+	transactions[1] = &models.Transaction{
+		TransactionId:     2,
+		TransactionTime:   1725212096000,
+		Type:              models.TRANSACTION_DB_TYPE_EXPENSE,
+		TimezoneUtcOffset: -300,
+		CategoryId:        1,
+		AccountId:         1,
+		Amount:            10,
+		Comment:           "Foo",
+	}
+
+	accountMap := make(map[int64]*models.Account, 1)
+	accountMap[1] = &models.Account{
+		AccountId: 1,
+		Name:      "Test Account",
+		Currency:  "CNY",
+	}
+
+	customFields := []*models.TransactionCustomField{
+		{FieldId: 1, Name: "Invoice"},
+	}
+
+	allCustomFieldValues := make(map[int64][]*models.TransactionCustomFieldValue, 1)
+	allCustomFieldValues[2] = []*models.TransactionCustomFieldValue{
+		{TransactionId: 2, FieldId: 1, TextValue: "INV-001"},
+	}
+
+	options, err := converter.ParseExporterOptions("UTC", "amount,time,customFields,description")
+	assert.Nil(t, err)
+
+	expectedContent := "Amount,Time,Invoice,Description\n" +
+		"123.45,2024-09-01 04:34:56,,Hello World\n" +
+		"0.10,2024-09-01 17:34:56,INV-001,Foo\n"
+	actualContent, err := exporter.ToExportedContent(context, 123, transactions, accountMap, nil, nil, nil, customFields, allCustomFieldValues, options)
+
+	assert.Nil(t, err)
+	assert.Equal(t, expectedContent, string(actualContent))
+
+	options, err = converter.ParseExporterOptions("", "type,timezone")
+	assert.Nil(t, err)
+
+	expectedContent = "Type,Timezone\n" +
+		"Income,+08:00\n" +
+		"Expense,-05:00\n"
+	actualContent, err = exporter.ToExportedContent(context, 123, transactions, accountMap, nil, nil, nil, customFields, allCustomFieldValues, options)
+
+	assert.Nil(t, err)
+	assert.Equal(t, expectedContent, string(actualContent))
+}
+
+func TestDefaultTransactionDataCSVFileConverterWriteExportedContent(t *testing.T) {
+	exporter := DefaultTransactionDataCSVFileConverter
+	context := core.NewNullContext()
+
+	transactions := make([]*models.Transaction, 3)
+	transactions[0] = &models.Transaction{
+		TransactionId:     1,
+		TransactionTime:   1725212096000,
+		Type:              models.TRANSACTION_DB_TYPE_INCOME,
+		TimezoneUtcOffset: 480,
+		CategoryId:        1,
+		AccountId:         1,
+		Amount:            12345,
+		Comment:           "Hello World",
+	}
+	transactions[1] = &models.Transaction{
+		TransactionId:     2,
+		TransactionTime:   1725194096000,
+		Type:              models.TRANSACTION_DB_TYPE_EXPENSE,
+		TimezoneUtcOffset: 0,
+		CategoryId:        2,
+		AccountId:         1,
+		Amount:            -10,
+		Comment:           "Foo",
+	}
+	transactions[2] = &models.Transaction{
+		TransactionId:     3,
+		TransactionTime:   1725165296000,
+		Type:              models.TRANSACTION_DB_TYPE_EXPENSE,
+		TimezoneUtcOffset: -300,
+		CategoryId:        2,
+		AccountId:         1,
+		Amount:            100,
+		Comment:           "Bar",
+	}
+
+	accountMap := make(map[int64]*models.Account, 1)
+	accountMap[1] = &models.Account{
+		AccountId: 1,
+		Name:      "Test Account",
+		Currency:  "CNY",
+	}
+
+	categoryMap := make(map[int64]*models.TransactionCategory, 2)
+	categoryMap[1] = &models.TransactionCategory{
+		CategoryId: 1,
+		Type:       models.CATEGORY_TYPE_INCOME,
+		Name:       "Test Category",
+	}
+	categoryMap[2] = &models.TransactionCategory{
+		CategoryId: 2,
+		Type:       models.CATEGORY_TYPE_EXPENSE,
+		Name:       "Test Category2",
+	}
+
+	tagMap := make(map[int64]*models.TransactionTag, 1)
+	tagMap[1] = &models.TransactionTag{
+		TagId: 1,
+		Name:  "Test Tag",
+	}
+
+	allTagIndexes := make(map[int64][]int64, 2)
+	allTagIndexes[1] = []int64{1}
+	allTagIndexes[3] = []int64{1}
+
+	expectedContent, err := exporter.ToExportedContent(context, 123, transactions, accountMap, categoryMap, tagMap, allTagIndexes, nil, nil, converter.DefaultExporterOptions)
+	assert.Nil(t, err)
+
+	pages := []*converter.ExportedTransactionDataPage{
+		{
+			Transactions:  transactions[0:2],
+			AllTagIndexes: map[int64][]int64{1: {1}},
+		},
+		{
+			Transactions:  transactions[2:3],
+			AllTagIndexes: map[int64][]int64{3: {1}},
+		},
+	}
+	pageIndex := 0
+	pageReader := func() (*converter.ExportedTransactionDataPage, error) {
+		if pageIndex >= len(pages) {
+			return nil, nil
+		}
+
+		page := pages[pageIndex]
+		pageIndex++
+		return page, nil
+	}
+
+	var actualContent bytes.Buffer
+	err = exporter.WriteExportedContent(context, &actualContent, 123, pageReader, accountMap, categoryMap, tagMap, nil, converter.DefaultExporterOptions)
+
+	assert.Nil(t, err)
+	assert.Equal(t, string(expectedContent), actualContent.String())
+}
+
+func TestDefaultTransactionDataCSVFileConverterWriteExportedContent_EmptyPages(t *testing.T) {
+	exporter := DefaultTransactionDataCSVFileConverter
+	context := core.NewNullContext()
+
+	pageReader := func() (*converter.ExportedTransactionDataPage, error) {
+		return nil, nil
+	}
+
+	var actualContent bytes.Buffer
+	err := exporter.WriteExportedContent(context, &actualContent, 123, pageReader, nil, nil, nil, nil, converter.DefaultExporterOptions)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Time,Timezone,Type,Category,Sub Category,Account,Account Currency,Amount,Account2,Account2 Currency,Account2 Amount,Geographic Location,Tags,Description\n", actualContent.String())
+}
+
+func TestDefaultTransactionDataCSVFileConverterWriteExportedContent_PageReaderError(t *testing.T) {
+	exporter := DefaultTransactionDataCSVFileConverter
+	context := core.NewNullContext()
+
+	pageReader := func() (*converter.ExportedTransactionDataPage, error) {
+		return nil, errs.ErrOperationFailed
+	}
+
+	var actualContent bytes.Buffer
+	err := exporter.WriteExportedContent(context, &actualContent, 123, pageReader, nil, nil, nil, nil, converter.DefaultExporterOptions)
+
+	assert.EqualError(t, err, errs.ErrOperationFailed.Message)
+}
+
+func BenchmarkDefaultTransactionDataCSVFileConverterWriteExportedContent(b *testing.B) {
+	for _, transactionCount := range []int{10000, 100000, 200000} {
+		b.Run(fmt.Sprintf("%d", transactionCount), func(b *testing.B) {
+			benchmarkDefaultTransactionDataCSVFileConverterWriteExportedContent(b, transactionCount)
+		})
+	}
+}
+
+func benchmarkDefaultTransactionDataCSVFileConverterWriteExportedContent(b *testing.B, transactionCount int) {
+	const pageCount = 1000
+
+	exporter := DefaultTransactionDataCSVFileConverter
+	context := core.NewNullContext()
+
+	accountMap := map[int64]*models.Account{
+		1: {AccountId: 1, Name: "Test Account", Currency: "CNY"},
+	}
+	categoryMap := map[int64]*models.TransactionCategory{
+		1: {CategoryId: 1, Type: models.CATEGORY_TYPE_EXPENSE, Name: "Test Category"},
+		2: {CategoryId: 2, Type: models.CATEGORY_TYPE_EXPENSE, ParentCategoryId: 1, Name: "Test Sub Category"},
+	}
+	tagMap := map[int64]*models.TransactionTag{
+		1: {TagId: 1, Name: "Test Tag"},
+	}
+
+	var peakHeapInUse uint64
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		remainCount := transactionCount
+		nextTransactionId := int64(1)
+
+		pageReader := func() (*converter.ExportedTransactionDataPage, error) {
+			if remainCount <= 0 {
+				return nil, nil
+			}
+
+			count := min(pageCount, remainCount)
+			remainCount -= count
+
+			transactions := make([]*models.Transaction, count)
+			allTagIndexes := make(map[int64][]int64, count)
+
+			for j := 0; j < count; j++ {
+				transactions[j] = &models.Transaction{
+					TransactionId:   nextTransactionId,
+					TransactionTime: 1725165296000 - nextTransactionId*1000,
+					Type:            models.TRANSACTION_DB_TYPE_EXPENSE,
+					CategoryId:      2,
+					AccountId:       1,
+					Amount:          12345,
+					Comment:         "Benchmark transaction description",
+				}
+				allTagIndexes[nextTransactionId] = []int64{1}
+				nextTransactionId++
+			}
+
+			var memStats runtime.MemStats
+			runtime.ReadMemStats(&memStats)
+			peakHeapInUse = max(peakHeapInUse, memStats.HeapInuse)
+
+			return &converter.ExportedTransactionDataPage{
+				Transactions:  transactions,
+				AllTagIndexes: allTagIndexes,
+			}, nil
+		}
+
+		err := exporter.WriteExportedContent(context, io.Discard, 123, pageReader, accountMap, categoryMap, tagMap, nil, converter.DefaultExporterOptions)
+
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	b.ReportMetric(float64(peakHeapInUse)/1024/1024, "MB-heap")
+}
+
 func TestDefaultTransactionDataCSVFileConverterParseImportedData_MinimumValidData(t *testing.T) {
 	importer := DefaultTransactionDataCSVFileConverter
 	context := core.NewNullContext()
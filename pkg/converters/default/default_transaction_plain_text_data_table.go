@@ -2,6 +2,7 @@ package _default
 
 import (
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/mayswind/ezbookkeeping/pkg/converters/datatable"
@@ -126,6 +127,18 @@ func (b *defaultTransactionPlainTextDataTableBuilder) String() string {
 	return b.builder.String()
 }
 
+// flush writes the data which has been built to the specified writer and clears the built data
+func (b *defaultTransactionPlainTextDataTableBuilder) flush(writer io.Writer) error {
+	if b.builder.Len() < 1 {
+		return nil
+	}
+
+	_, err := io.WriteString(writer, b.builder.String())
+	b.builder.Reset()
+
+	return err
+}
+
 func (b *defaultTransactionPlainTextDataTableBuilder) generateHeaderLine() string {
 	var ret strings.Builder
 
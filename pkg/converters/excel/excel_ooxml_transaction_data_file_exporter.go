@@ -0,0 +1,617 @@
+package excel
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/converter"
+	"github.com/mayswind/ezbookkeeping/pkg/converters/datatable"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+	"github.com/mayswind/ezbookkeeping/pkg/validators"
+)
+
+const excelOOXMLTransactionsSheetName = "Transactions"
+const excelOOXMLAccountBalancesSheetName = "Account Balances"
+const excelOOXMLMonthlyCategorySummarySheetName = "Monthly Category Summary"
+
+const excelOOXMLExportedDateTimeNumberFormat = "yyyy-mm-dd hh:mm:ss"
+const excelOOXMLExportedAmountNumberFormat = "#,##0.00"
+const excelOOXMLExportedGeoLocationSeparator = " "
+const excelOOXMLExportedTagSeparator = ";"
+
+const excelOOXMLExportedDefaultColumnWidth = 15
+const excelOOXMLExportedDateTimeColumnWidth = 20
+const excelOOXMLExportedDescriptionColumnWidth = 40
+
+var excelOOXMLTransactionDataColumnNameMapping = map[datatable.TransactionDataTableColumn]string{
+	datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIME:         "Time",
+	datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIMEZONE:     "Timezone",
+	datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE:         "Type",
+	datatable.TRANSACTION_DATA_TABLE_CATEGORY:                 "Category",
+	datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY:             "Sub Category",
+	datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME:             "Account",
+	datatable.TRANSACTION_DATA_TABLE_ACCOUNT_CURRENCY:         "Account Currency",
+	datatable.TRANSACTION_DATA_TABLE_AMOUNT:                   "Amount",
+	datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME:     "Account2",
+	datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_CURRENCY: "Account2 Currency",
+	datatable.TRANSACTION_DATA_TABLE_RELATED_AMOUNT:           "Account2 Amount",
+	datatable.TRANSACTION_DATA_TABLE_GEOGRAPHIC_LOCATION:      "Geographic Location",
+	datatable.TRANSACTION_DATA_TABLE_TAGS:                     "Tags",
+	datatable.TRANSACTION_DATA_TABLE_DESCRIPTION:              "Description",
+}
+
+var excelOOXMLTransactionTypeNameMapping = map[models.TransactionType]string{
+	models.TRANSACTION_TYPE_MODIFY_BALANCE: "Balance Modification",
+	models.TRANSACTION_TYPE_INCOME:         "Income",
+	models.TRANSACTION_TYPE_EXPENSE:        "Expense",
+	models.TRANSACTION_TYPE_TRANSFER:       "Transfer",
+}
+
+var excelOOXMLTransactionDataColumns = []datatable.TransactionDataTableColumn{
+	datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIME,
+	datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIMEZONE,
+	datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE,
+	datatable.TRANSACTION_DATA_TABLE_CATEGORY,
+	datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY,
+	datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME,
+	datatable.TRANSACTION_DATA_TABLE_ACCOUNT_CURRENCY,
+	datatable.TRANSACTION_DATA_TABLE_AMOUNT,
+	datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME,
+	datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_CURRENCY,
+	datatable.TRANSACTION_DATA_TABLE_RELATED_AMOUNT,
+	datatable.TRANSACTION_DATA_TABLE_GEOGRAPHIC_LOCATION,
+	datatable.TRANSACTION_DATA_TABLE_TAGS,
+	datatable.TRANSACTION_DATA_TABLE_DESCRIPTION,
+}
+
+var excelOOXMLAccountBalancesColumnNames = []string{"Account", "Parent Account", "Currency", "Balance"}
+var excelOOXMLMonthlyCategorySummaryColumnNames = []string{"Month", "Type", "Category", "Sub Category", "Currency", "Amount", "Count"}
+
+// excelOOXMLTransactionDataExporter defines the structure of excel (Office Open XML) exporter for transaction data
+type excelOOXMLTransactionDataExporter struct {
+}
+
+// excelOOXMLTransactionDataSheetBuilder defines the structure of excel (Office Open XML) transaction data sheet builder
+type excelOOXMLTransactionDataSheetBuilder struct {
+	file            *excelize.File
+	sheetName       string
+	columns         []datatable.TransactionDataTableColumn
+	currentRowIndex int
+	err             error
+}
+
+// excelOOXMLMonthlyCategorySummaryItem defines the structure of monthly category summary item
+type excelOOXMLMonthlyCategorySummaryItem struct {
+	month           string
+	transactionType models.TransactionType
+	categoryName    string
+	subCategoryName string
+	currency        string
+	amount          int64
+	count           int
+}
+
+// excelOOXMLExportedStyles defines the structure of cell styles in exported excel (Office Open XML) file
+type excelOOXMLExportedStyles struct {
+	headerStyleId   int
+	dateTimeStyleId int
+	amountStyleId   int
+}
+
+// Initialize an excel (Office Open XML) transaction data exporter singleton instance
+var (
+	ExcelOOXMLTransactionDataExporter = &excelOOXMLTransactionDataExporter{}
+)
+
+// ToExportedContent returns the exported excel (Office Open XML) workbook, which contains the transactions sheet,
+// the account balances sheet and the monthly category summary sheet
+func (c *excelOOXMLTransactionDataExporter) ToExportedContent(ctx core.Context, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64, customFields []*models.TransactionCustomField, allCustomFieldValues map[int64][]*models.TransactionCustomFieldValue, options converter.TransactionDataExporterOptions) ([]byte, error) {
+	file := excelize.NewFile()
+
+	defer file.Close()
+
+	styles, err := c.createStyles(file)
+
+	if err != nil {
+		log.Errorf(ctx, "[excel_ooxml_transaction_data_file_exporter.ToExportedContent] failed to create cell styles, because %s", err.Error())
+		return nil, err
+	}
+
+	if err = file.SetSheetName(file.GetSheetName(0), excelOOXMLTransactionsSheetName); err != nil {
+		log.Errorf(ctx, "[excel_ooxml_transaction_data_file_exporter.ToExportedContent] failed to create transactions sheet, because %s", err.Error())
+		return nil, err
+	}
+
+	if err = c.writeTransactionsSheet(ctx, file, styles, uid, transactions, accountMap, categoryMap, tagMap, allTagIndexes, customFields, allCustomFieldValues, options); err != nil {
+		log.Errorf(ctx, "[excel_ooxml_transaction_data_file_exporter.ToExportedContent] failed to write transactions sheet, because %s", err.Error())
+		return nil, err
+	}
+
+	if err = c.writeAccountBalancesSheet(file, styles, accountMap); err != nil {
+		log.Errorf(ctx, "[excel_ooxml_transaction_data_file_exporter.ToExportedContent] failed to write account balances sheet, because %s", err.Error())
+		return nil, err
+	}
+
+	if err = c.writeMonthlyCategorySummarySheet(file, styles, transactions, accountMap, categoryMap, options); err != nil {
+		log.Errorf(ctx, "[excel_ooxml_transaction_data_file_exporter.ToExportedContent] failed to write monthly category summary sheet, because %s", err.Error())
+		return nil, err
+	}
+
+	file.SetActiveSheet(0)
+	buffer, err := file.WriteToBuffer()
+
+	if err != nil {
+		log.Errorf(ctx, "[excel_ooxml_transaction_data_file_exporter.ToExportedContent] failed to write excel file, because %s", err.Error())
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func (c *excelOOXMLTransactionDataExporter) writeTransactionsSheet(ctx core.Context, file *excelize.File, styles *excelOOXMLExportedStyles, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64, customFields []*models.TransactionCustomField, allCustomFieldValues map[int64][]*models.TransactionCustomFieldValue, options converter.TransactionDataExporterOptions) error {
+	customFields = datatable.LimitCustomFieldsCount(customFields)
+	dataColumns := excelOOXMLTransactionDataColumns
+	dataColumnNameMapping := excelOOXMLTransactionDataColumnNameMapping
+
+	if len(customFields) > 0 {
+		dataColumns, dataColumnNameMapping = c.getDataColumnsWithCustomFields(customFields)
+	}
+
+	dataColumns = options.GetSelectedDataColumns(dataColumns)
+
+	columnNames := make([]string, len(dataColumns))
+
+	for i := 0; i < len(dataColumns); i++ {
+		columnNames[i] = dataColumnNameMapping[dataColumns[i]]
+	}
+
+	if err := c.writeHeaderRow(file, styles, excelOOXMLTransactionsSheetName, columnNames); err != nil {
+		return err
+	}
+
+	dataTableBuilder := &excelOOXMLTransactionDataSheetBuilder{
+		file:            file,
+		sheetName:       excelOOXMLTransactionsSheetName,
+		columns:         dataColumns,
+		currentRowIndex: 1,
+	}
+
+	dataTableExporter := converter.CreateNewExporter(
+		excelOOXMLTransactionTypeNameMapping,
+		excelOOXMLExportedGeoLocationSeparator,
+		excelOOXMLExportedTagSeparator,
+	)
+
+	if err := dataTableExporter.BuildExportedContent(ctx, dataTableBuilder, uid, transactions, accountMap, categoryMap, tagMap, allTagIndexes, customFields, allCustomFieldValues, options); err != nil {
+		return err
+	}
+
+	if dataTableBuilder.err != nil {
+		return dataTableBuilder.err
+	}
+
+	for i := 0; i < len(dataColumns); i++ {
+		column := dataColumns[i]
+		width := float64(excelOOXMLExportedDefaultColumnWidth)
+		styleId := 0
+
+		switch column {
+		case datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIME:
+			width = excelOOXMLExportedDateTimeColumnWidth
+			styleId = styles.dateTimeStyleId
+		case datatable.TRANSACTION_DATA_TABLE_AMOUNT, datatable.TRANSACTION_DATA_TABLE_RELATED_AMOUNT:
+			styleId = styles.amountStyleId
+		case datatable.TRANSACTION_DATA_TABLE_DESCRIPTION:
+			width = excelOOXMLExportedDescriptionColumnWidth
+		}
+
+		if err := c.setColumnStyle(file, excelOOXMLTransactionsSheetName, i+1, dataTableBuilder.currentRowIndex, width, styleId); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *excelOOXMLTransactionDataExporter) writeAccountBalancesSheet(file *excelize.File, styles *excelOOXMLExportedStyles, accountMap map[int64]*models.Account) error {
+	if _, err := file.NewSheet(excelOOXMLAccountBalancesSheetName); err != nil {
+		return err
+	}
+
+	if err := c.writeHeaderRow(file, styles, excelOOXMLAccountBalancesSheetName, excelOOXMLAccountBalancesColumnNames); err != nil {
+		return err
+	}
+
+	accounts := c.getSortedAccounts(accountMap)
+	rowIndex := 1
+
+	for i := 0; i < len(accounts); i++ {
+		account := accounts[i]
+
+		// the balance of parent account is the sum of sub accounts, which may be in different currencies
+		if account.Currency == validators.ParentAccountCurrencyPlaceholder {
+			continue
+		}
+
+		parentAccountName := ""
+
+		if parentAccount, exists := accountMap[account.ParentAccountId]; exists && account.ParentAccountId != models.LevelOneAccountParentId {
+			parentAccountName = parentAccount.Name
+		}
+
+		rowIndex++
+
+		if err := c.writeRow(file, excelOOXMLAccountBalancesSheetName, rowIndex, []any{
+			account.Name,
+			parentAccountName,
+			account.Currency,
+			c.getAmountValue(account.Balance),
+		}); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(excelOOXMLAccountBalancesColumnNames); i++ {
+		styleId := 0
+
+		if i == len(excelOOXMLAccountBalancesColumnNames)-1 {
+			styleId = styles.amountStyleId
+		}
+
+		if err := c.setColumnStyle(file, excelOOXMLAccountBalancesSheetName, i+1, rowIndex, excelOOXMLExportedDefaultColumnWidth, styleId); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *excelOOXMLTransactionDataExporter) writeMonthlyCategorySummarySheet(file *excelize.File, styles *excelOOXMLExportedStyles, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, options converter.TransactionDataExporterOptions) error {
+	if _, err := file.NewSheet(excelOOXMLMonthlyCategorySummarySheetName); err != nil {
+		return err
+	}
+
+	if err := c.writeHeaderRow(file, styles, excelOOXMLMonthlyCategorySummarySheetName, excelOOXMLMonthlyCategorySummaryColumnNames); err != nil {
+		return err
+	}
+
+	summaryItems := c.getMonthlyCategorySummaryItems(transactions, accountMap, categoryMap, options)
+	rowIndex := 1
+
+	for i := 0; i < len(summaryItems); i++ {
+		summaryItem := summaryItems[i]
+		rowIndex++
+
+		if err := c.writeRow(file, excelOOXMLMonthlyCategorySummarySheetName, rowIndex, []any{
+			summaryItem.month,
+			excelOOXMLTransactionTypeNameMapping[summaryItem.transactionType],
+			summaryItem.categoryName,
+			summaryItem.subCategoryName,
+			summaryItem.currency,
+			c.getAmountValue(summaryItem.amount),
+			summaryItem.count,
+		}); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(excelOOXMLMonthlyCategorySummaryColumnNames); i++ {
+		styleId := 0
+
+		if excelOOXMLMonthlyCategorySummaryColumnNames[i] == "Amount" {
+			styleId = styles.amountStyleId
+		}
+
+		if err := c.setColumnStyle(file, excelOOXMLMonthlyCategorySummarySheetName, i+1, rowIndex, excelOOXMLExportedDefaultColumnWidth, styleId); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getMonthlyCategorySummaryItems returns the total amounts of income and expense transactions grouped by month (in transaction timezone), category and account currency
+func (c *excelOOXMLTransactionDataExporter) getMonthlyCategorySummaryItems(transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, options converter.TransactionDataExporterOptions) []*excelOOXMLMonthlyCategorySummaryItem {
+	summaryItemMap := make(map[string]*excelOOXMLMonthlyCategorySummaryItem)
+	summaryItems := make([]*excelOOXMLMonthlyCategorySummaryItem, 0)
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+		var transactionType models.TransactionType
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_INCOME {
+			transactionType = models.TRANSACTION_TYPE_INCOME
+		} else if transaction.Type == models.TRANSACTION_DB_TYPE_EXPENSE {
+			transactionType = models.TRANSACTION_TYPE_EXPENSE
+		} else {
+			continue
+		}
+
+		account, exists := accountMap[transaction.AccountId]
+
+		if !exists {
+			continue
+		}
+
+		transactionTimeZone := options.GetTransactionTimezone(transaction)
+		month := utils.FormatUnixTimeToYearMonth(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime), transactionTimeZone)
+		categoryName, subCategoryName := c.getCategoryNames(transaction.CategoryId, categoryMap)
+		key := strings.Join([]string{month, utils.IntToString(int(transactionType)), utils.Int64ToString(transaction.CategoryId), account.Currency}, "|")
+		summaryItem, exists := summaryItemMap[key]
+
+		if !exists {
+			summaryItem = &excelOOXMLMonthlyCategorySummaryItem{
+				month:           month,
+				transactionType: transactionType,
+				categoryName:    categoryName,
+				subCategoryName: subCategoryName,
+				currency:        account.Currency,
+			}
+
+			summaryItemMap[key] = summaryItem
+			summaryItems = append(summaryItems, summaryItem)
+		}
+
+		summaryItem.amount += transaction.Amount
+		summaryItem.count++
+	}
+
+	sort.SliceStable(summaryItems, func(i, j int) bool {
+		if summaryItems[i].month != summaryItems[j].month {
+			return summaryItems[i].month < summaryItems[j].month
+		}
+
+		if summaryItems[i].transactionType != summaryItems[j].transactionType {
+			return summaryItems[i].transactionType < summaryItems[j].transactionType
+		}
+
+		if summaryItems[i].categoryName != summaryItems[j].categoryName {
+			return summaryItems[i].categoryName < summaryItems[j].categoryName
+		}
+
+		if summaryItems[i].subCategoryName != summaryItems[j].subCategoryName {
+			return summaryItems[i].subCategoryName < summaryItems[j].subCategoryName
+		}
+
+		return summaryItems[i].currency < summaryItems[j].currency
+	})
+
+	return summaryItems
+}
+
+func (c *excelOOXMLTransactionDataExporter) getCategoryNames(categoryId int64, categoryMap map[int64]*models.TransactionCategory) (string, string) {
+	category, exists := categoryMap[categoryId]
+
+	if !exists {
+		return "", ""
+	}
+
+	if category.ParentCategoryId == models.LevelOneTransactionCategoryParentId {
+		return category.Name, ""
+	}
+
+	parentCategory, exists := categoryMap[category.ParentCategoryId]
+
+	if !exists {
+		return "", category.Name
+	}
+
+	return parentCategory.Name, category.Name
+}
+
+// getSortedAccounts returns the accounts which are sorted in the same order as the account list page
+func (c *excelOOXMLTransactionDataExporter) getSortedAccounts(accountMap map[int64]*models.Account) []*models.Account {
+	accounts := make([]*models.Account, 0, len(accountMap))
+
+	for _, account := range accountMap {
+		accounts = append(accounts, account)
+	}
+
+	getTopLevelAccount := func(account *models.Account) *models.Account {
+		if parentAccount, exists := accountMap[account.ParentAccountId]; exists && account.ParentAccountId != models.LevelOneAccountParentId {
+			return parentAccount
+		}
+
+		return account
+	}
+
+	sort.Slice(accounts, func(i, j int) bool {
+		topLevelAccount1 := getTopLevelAccount(accounts[i])
+		topLevelAccount2 := getTopLevelAccount(accounts[j])
+
+		if topLevelAccount1.Category != topLevelAccount2.Category {
+			return topLevelAccount1.Category < topLevelAccount2.Category
+		}
+
+		if topLevelAccount1.DisplayOrder != topLevelAccount2.DisplayOrder {
+			return topLevelAccount1.DisplayOrder < topLevelAccount2.DisplayOrder
+		}
+
+		if topLevelAccount1.AccountId != topLevelAccount2.AccountId {
+			return topLevelAccount1.AccountId < topLevelAccount2.AccountId
+		}
+
+		if accounts[i].DisplayOrder != accounts[j].DisplayOrder {
+			return accounts[i].DisplayOrder < accounts[j].DisplayOrder
+		}
+
+		return accounts[i].AccountId < accounts[j].AccountId
+	})
+
+	return accounts
+}
+
+// getDataColumnsWithCustomFields returns the data columns and column names which contain the columns of all custom fields after the default columns
+func (c *excelOOXMLTransactionDataExporter) getDataColumnsWithCustomFields(customFields []*models.TransactionCustomField) ([]datatable.TransactionDataTableColumn, map[datatable.TransactionDataTableColumn]string) {
+	dataColumns := make([]datatable.TransactionDataTableColumn, 0, len(excelOOXMLTransactionDataColumns)+len(customFields))
+	dataColumns = append(dataColumns, excelOOXMLTransactionDataColumns...)
+	dataColumnNameMapping := make(map[datatable.TransactionDataTableColumn]string, len(excelOOXMLTransactionDataColumnNameMapping)+len(customFields))
+
+	for column, columnName := range excelOOXMLTransactionDataColumnNameMapping {
+		dataColumnNameMapping[column] = columnName
+	}
+
+	for i := 0; i < len(customFields); i++ {
+		column := datatable.GetCustomFieldDataTableColumn(i)
+		dataColumns = append(dataColumns, column)
+		dataColumnNameMapping[column] = customFields[i].Name
+	}
+
+	return dataColumns, dataColumnNameMapping
+}
+
+func (c *excelOOXMLTransactionDataExporter) getAmountValue(amount int64) float64 {
+	return float64(amount) / 100
+}
+
+func (c *excelOOXMLTransactionDataExporter) writeHeaderRow(file *excelize.File, styles *excelOOXMLExportedStyles, sheetName string, columnNames []string) error {
+	values := make([]any, len(columnNames))
+
+	for i := 0; i < len(columnNames); i++ {
+		values[i] = columnNames[i]
+	}
+
+	if err := c.writeRow(file, sheetName, 1, values); err != nil {
+		return err
+	}
+
+	startCell, err := excelize.CoordinatesToCellName(1, 1)
+
+	if err != nil {
+		return err
+	}
+
+	endCell, err := excelize.CoordinatesToCellName(len(columnNames), 1)
+
+	if err != nil {
+		return err
+	}
+
+	if err = file.SetCellStyle(sheetName, startCell, endCell, styles.headerStyleId); err != nil {
+		return err
+	}
+
+	return file.SetPanes(sheetName, &excelize.Panes{
+		Freeze:      true,
+		YSplit:      1,
+		TopLeftCell: "A2",
+		ActivePane:  "bottomLeft",
+	})
+}
+
+func (c *excelOOXMLTransactionDataExporter) writeRow(file *excelize.File, sheetName string, rowIndex int, values []any) error {
+	cell, err := excelize.CoordinatesToCellName(1, rowIndex)
+
+	if err != nil {
+		return err
+	}
+
+	return file.SetSheetRow(sheetName, cell, &values)
+}
+
+// setColumnStyle sets the width of the specified column and sets the style of all data cells in this column
+func (c *excelOOXMLTransactionDataExporter) setColumnStyle(file *excelize.File, sheetName string, columnIndex int, lastRowIndex int, width float64, styleId int) error {
+	columnName, err := excelize.ColumnNumberToName(columnIndex)
+
+	if err != nil {
+		return err
+	}
+
+	if err = file.SetColWidth(sheetName, columnName, columnName, width); err != nil {
+		return err
+	}
+
+	if styleId == 0 || lastRowIndex < 2 {
+		return nil
+	}
+
+	return file.SetCellStyle(sheetName, columnName+"2", columnName+utils.IntToString(lastRowIndex), styleId)
+}
+
+func (c *excelOOXMLTransactionDataExporter) createStyles(file *excelize.File) (*excelOOXMLExportedStyles, error) {
+	headerStyleId, err := file.NewStyle(&excelize.Style{
+		Font: &excelize.Font{
+			Bold: true,
+		},
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	dateTimeNumberFormat := excelOOXMLExportedDateTimeNumberFormat
+	dateTimeStyleId, err := file.NewStyle(&excelize.Style{
+		CustomNumFmt: &dateTimeNumberFormat,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	amountNumberFormat := excelOOXMLExportedAmountNumberFormat
+	amountStyleId, err := file.NewStyle(&excelize.Style{
+		CustomNumFmt: &amountNumberFormat,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &excelOOXMLExportedStyles{
+		headerStyleId:   headerStyleId,
+		dateTimeStyleId: dateTimeStyleId,
+		amountStyleId:   amountStyleId,
+	}, nil
+}
+
+// AppendTransaction appends the specified transaction to the sheet, the transaction time is written as date cell and the amounts are written as number cells
+func (b *excelOOXMLTransactionDataSheetBuilder) AppendTransaction(data map[datatable.TransactionDataTableColumn]string) {
+	if b.err != nil {
+		return
+	}
+
+	values := make([]any, len(b.columns))
+
+	for i := 0; i < len(b.columns); i++ {
+		column := b.columns[i]
+		value := data[column]
+		values[i] = value
+
+		if value == "" {
+			continue
+		}
+
+		switch column {
+		case datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIME:
+			// excel does not support timezone, so the local time in transaction timezone is written
+			if dateTime, err := utils.ParseFromLongDateTimeInTimeZone(value, time.UTC); err == nil {
+				values[i] = dateTime
+			}
+		case datatable.TRANSACTION_DATA_TABLE_AMOUNT, datatable.TRANSACTION_DATA_TABLE_RELATED_AMOUNT:
+			if amount, err := utils.ParseAmount(value); err == nil {
+				values[i] = float64(amount) / 100
+			}
+		}
+	}
+
+	b.currentRowIndex++
+	cell, err := excelize.CoordinatesToCellName(1, b.currentRowIndex)
+
+	if err != nil {
+		b.err = err
+		return
+	}
+
+	b.err = b.file.SetSheetRow(b.sheetName, cell, &values)
+}
+
+// ReplaceDelimiters returns the text as is, because the cell of excel file can contain any character
+func (b *excelOOXMLTransactionDataSheetBuilder) ReplaceDelimiters(text string) string {
+	return text
+}
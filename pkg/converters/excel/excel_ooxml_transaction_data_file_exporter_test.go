@@ -0,0 +1,412 @@
+package excel
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xuri/excelize/v2"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/converter"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/validators"
+)
+
+func TestExcelOOXMLTransactionDataExporterToExportedContent_SheetNames(t *testing.T) {
+	exporter := ExcelOOXMLTransactionDataExporter
+	context := core.NewNullContext()
+	transactions, accountMap, categoryMap, tagMap, allTagIndexes := createTestExcelOOXMLExportedTransactions()
+
+	actualContent, err := exporter.ToExportedContent(context, 123, transactions, accountMap, categoryMap, tagMap, allTagIndexes, nil, nil, converter.DefaultExporterOptions)
+	assert.Nil(t, err)
+
+	file, err := excelize.OpenReader(bytes.NewReader(actualContent))
+	assert.Nil(t, err)
+
+	defer file.Close()
+
+	assert.Equal(t, []string{"Transactions", "Account Balances", "Monthly Category Summary"}, file.GetSheetList())
+	assert.Equal(t, 0, file.GetActiveSheetIndex())
+}
+
+func TestExcelOOXMLTransactionDataExporterToExportedContent_TransactionsSheet(t *testing.T) {
+	exporter := ExcelOOXMLTransactionDataExporter
+	context := core.NewNullContext()
+	transactions, accountMap, categoryMap, tagMap, allTagIndexes := createTestExcelOOXMLExportedTransactions()
+
+	actualContent, err := exporter.ToExportedContent(context, 123, transactions, accountMap, categoryMap, tagMap, allTagIndexes, nil, nil, converter.DefaultExporterOptions)
+	assert.Nil(t, err)
+
+	file, err := excelize.OpenReader(bytes.NewReader(actualContent))
+	assert.Nil(t, err)
+
+	defer file.Close()
+
+	rows, err := file.GetRows("Transactions")
+	assert.Nil(t, err)
+	assert.Equal(t, 5, len(rows))
+
+	assert.Equal(t, []string{"Time", "Timezone", "Type", "Category", "Sub Category", "Account", "Account Currency", "Amount", "Account2", "Account2 Currency", "Account2 Amount", "Geographic Location", "Tags", "Description"}, rows[0])
+	assert.Equal(t, []string{"2024-09-01 12:34:56", "+08:00", "Income", "Test Category", "Test Sub Category", "Test Account", "CNY", "12,345.67", "", "", "", "", "Tag 1;Tag2", "Foo\nBar"}, rows[1])
+	assert.Equal(t, []string{"2024-09-01 12:34:56", "+00:00", "Expense", "Test Category2", "Test Sub Category2", "Test Account", "CNY", "0.10"}, rows[2])
+	assert.Equal(t, []string{"2024-09-01 12:34:56", "-05:00", "Transfer", "Test Category3", "Test Sub Category3", "Test Account", "CNY", "100.00", "USD Cash", "USD", "14.00"}, rows[3])
+	assert.Equal(t, []string{"2024-08-31 18:26:40", "+08:00", "Balance Modification", "", "", "Test Account", "CNY", "1,000.00"}, rows[4])
+
+	cellType, err := file.GetCellType("Transactions", "A2")
+	assert.Nil(t, err)
+	assert.NotEqual(t, excelize.CellTypeInlineString, cellType)
+	assert.NotEqual(t, excelize.CellTypeSharedString, cellType)
+
+	rawDateTime, err := file.GetCellValue("Transactions", "A2", excelize.Options{RawCellValue: true})
+	assert.Nil(t, err)
+	assert.Equal(t, "45536.52425925926", rawDateTime)
+
+	rawAmount, err := file.GetCellValue("Transactions", "H2", excelize.Options{RawCellValue: true})
+	assert.Nil(t, err)
+	assert.Equal(t, "12345.67", rawAmount)
+
+	rawAmount, err = file.GetCellValue("Transactions", "K4", excelize.Options{RawCellValue: true})
+	assert.Nil(t, err)
+	assert.Equal(t, "14", rawAmount)
+}
+
+func TestExcelOOXMLTransactionDataExporterToExportedContent_TransactionsSheetWithCustomFields(t *testing.T) {
+	exporter := ExcelOOXMLTransactionDataExporter
+	context := core.NewNullContext()
+	transactions, accountMap, categoryMap, tagMap, allTagIndexes := createTestExcelOOXMLExportedTransactions()
+
+	customFields := []*models.TransactionCustomField{
+		{FieldId: 1, Name: "Invoice No"},
+	}
+	allCustomFieldValues := map[int64][]*models.TransactionCustomFieldValue{
+		1: {
+			{FieldId: 1, TextValue: "INV-001"},
+		},
+	}
+
+	actualContent, err := exporter.ToExportedContent(context, 123, transactions, accountMap, categoryMap, tagMap, allTagIndexes, customFields, allCustomFieldValues, converter.DefaultExporterOptions)
+	assert.Nil(t, err)
+
+	file, err := excelize.OpenReader(bytes.NewReader(actualContent))
+	assert.Nil(t, err)
+
+	defer file.Close()
+
+	value, err := file.GetCellValue("Transactions", "O1")
+	assert.Nil(t, err)
+	assert.Equal(t, "Invoice No", value)
+
+	value, err = file.GetCellValue("Transactions", "O2")
+	assert.Nil(t, err)
+	assert.Equal(t, "INV-001", value)
+}
+
+func TestExcelOOXMLTransactionDataExporterToExportedContent_AccountBalancesSheet(t *testing.T) {
+	exporter := ExcelOOXMLTransactionDataExporter
+	context := core.NewNullContext()
+	transactions, accountMap, categoryMap, tagMap, allTagIndexes := createTestExcelOOXMLExportedTransactions()
+
+	actualContent, err := exporter.ToExportedContent(context, 123, transactions, accountMap, categoryMap, tagMap, allTagIndexes, nil, nil, converter.DefaultExporterOptions)
+	assert.Nil(t, err)
+
+	file, err := excelize.OpenReader(bytes.NewReader(actualContent))
+	assert.Nil(t, err)
+
+	defer file.Close()
+
+	rows, err := file.GetRows("Account Balances")
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(rows))
+
+	assert.Equal(t, []string{"Account", "Parent Account", "Currency", "Balance"}, rows[0])
+	assert.Equal(t, []string{"Test Account", "", "CNY", "12,245.57"}, rows[1])
+	assert.Equal(t, []string{"USD Cash", "Test Parent Account", "USD", "14.00"}, rows[2])
+	assert.Equal(t, []string{"Test Credit Card", "", "CNY", "-1,735.00"}, rows[3])
+
+	rawBalance, err := file.GetCellValue("Account Balances", "D2", excelize.Options{RawCellValue: true})
+	assert.Nil(t, err)
+	assert.Equal(t, "12245.57", rawBalance)
+}
+
+func TestExcelOOXMLTransactionDataExporterToExportedContent_MonthlyCategorySummarySheet(t *testing.T) {
+	exporter := ExcelOOXMLTransactionDataExporter
+	context := core.NewNullContext()
+	transactions, accountMap, categoryMap, tagMap, allTagIndexes := createTestExcelOOXMLExportedTransactions()
+
+	actualContent, err := exporter.ToExportedContent(context, 123, transactions, accountMap, categoryMap, tagMap, allTagIndexes, nil, nil, converter.DefaultExporterOptions)
+	assert.Nil(t, err)
+
+	file, err := excelize.OpenReader(bytes.NewReader(actualContent))
+	assert.Nil(t, err)
+
+	defer file.Close()
+
+	rows, err := file.GetRows("Monthly Category Summary")
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(rows))
+
+	assert.Equal(t, []string{"Month", "Type", "Category", "Sub Category", "Currency", "Amount", "Count"}, rows[0])
+	assert.Equal(t, []string{"2024-09", "Income", "Test Category", "Test Sub Category", "CNY", "12,345.67", "1"}, rows[1])
+	assert.Equal(t, []string{"2024-09", "Expense", "Test Category2", "Test Sub Category2", "CNY", "0.10", "1"}, rows[2])
+}
+
+func TestExcelOOXMLTransactionDataExporterToExportedContent_EscapeSpecialCharacters(t *testing.T) {
+	exporter := ExcelOOXMLTransactionDataExporter
+	context := core.NewNullContext()
+
+	transactions := []*models.Transaction{
+		{
+			TransactionId:     1,
+			TransactionTime:   1725165296000,
+			Type:              models.TRANSACTION_DB_TYPE_INCOME,
+			TimezoneUtcOffset: 480,
+			CategoryId:        12,
+			AccountId:         1,
+			Amount:            12345,
+			Comment:           "=SUM(A1:A2)\nFoo",
+		},
+	}
+	accountMap := map[int64]*models.Account{
+		1: {AccountId: 1, Category: models.ACCOUNT_CATEGORY_CHECKING_ACCOUNT, Name: "Test Account", Currency: "CNY"},
+	}
+	categoryMap := map[int64]*models.TransactionCategory{
+		11: {CategoryId: 11, Name: "Test Category", ParentCategoryId: models.LevelOneTransactionCategoryParentId},
+		12: {CategoryId: 12, Name: "+Sub Category", ParentCategoryId: 11},
+	}
+	tagMap := map[int64]*models.TransactionTag{
+		21: {TagId: 21, Name: "Tag;1"},
+	}
+	allTagIndexes := map[int64][]int64{
+		1: {21},
+	}
+
+	actualContent, err := exporter.ToExportedContent(context, 123, transactions, accountMap, categoryMap, tagMap, allTagIndexes, nil, nil, converter.DefaultExporterOptions)
+	assert.Nil(t, err)
+
+	file, err := excelize.OpenReader(bytes.NewReader(actualContent))
+	assert.Nil(t, err)
+
+	defer file.Close()
+
+	rows, err := file.GetRows("Transactions")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(rows))
+	assert.Equal(t, []string{"2024-09-01 12:34:56", "+08:00", "Income", "Test Category", "+Sub Category", "Test Account", "CNY", "123.45", "", "", "", "", "Tag 1", "=SUM(A1:A2)\nFoo"}, rows[1])
+
+	formula, err := file.GetCellFormula("Transactions", "N2")
+	assert.Nil(t, err)
+	assert.Equal(t, "", formula)
+
+	formula, err = file.GetCellFormula("Transactions", "E2")
+	assert.Nil(t, err)
+	assert.Equal(t, "", formula)
+}
+
+func TestExcelOOXMLTransactionDataExporterToExportedContent_EmptyTransactions(t *testing.T) {
+	exporter := ExcelOOXMLTransactionDataExporter
+	context := core.NewNullContext()
+
+	actualContent, err := exporter.ToExportedContent(context, 123, nil, nil, nil, nil, nil, nil, nil, converter.DefaultExporterOptions)
+	assert.Nil(t, err)
+
+	file, err := excelize.OpenReader(bytes.NewReader(actualContent))
+	assert.Nil(t, err)
+
+	defer file.Close()
+
+	rows, err := file.GetRows("Transactions")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(rows))
+
+	rows, err = file.GetRows("Account Balances")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(rows))
+
+	rows, err = file.GetRows("Monthly Category Summary")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(rows))
+}
+
+func TestExcelOOXMLTransactionDataExporterGetMonthlyCategorySummaryItems(t *testing.T) {
+	exporter := ExcelOOXMLTransactionDataExporter
+	_, accountMap, categoryMap, _, _ := createTestExcelOOXMLExportedTransactions()
+
+	transactions := []*models.Transaction{
+		{TransactionTime: 1725165296000, Type: models.TRANSACTION_DB_TYPE_EXPENSE, TimezoneUtcOffset: 480, CategoryId: 22, AccountId: 1, Amount: 100},
+		{TransactionTime: 1725120000000, Type: models.TRANSACTION_DB_TYPE_EXPENSE, TimezoneUtcOffset: 480, CategoryId: 22, AccountId: 1, Amount: 200},
+		{TransactionTime: 1725120000000, Type: models.TRANSACTION_DB_TYPE_EXPENSE, TimezoneUtcOffset: 0, CategoryId: 22, AccountId: 1, Amount: 300},
+		{TransactionTime: 1725062400000, Type: models.TRANSACTION_DB_TYPE_EXPENSE, TimezoneUtcOffset: 0, CategoryId: 22, AccountId: 3, Amount: 400},
+		{TransactionTime: 1725062400000, Type: models.TRANSACTION_DB_TYPE_INCOME, TimezoneUtcOffset: 0, CategoryId: 12, AccountId: 1, Amount: 500},
+		{TransactionTime: 1725062400000, Type: models.TRANSACTION_DB_TYPE_EXPENSE, TimezoneUtcOffset: 0, CategoryId: 99, AccountId: 1, Amount: 600},
+		{TransactionTime: 1725062400000, Type: models.TRANSACTION_DB_TYPE_TRANSFER_OUT, TimezoneUtcOffset: 0, CategoryId: 32, AccountId: 1, Amount: 700},
+	}
+
+	summaryItems := exporter.getMonthlyCategorySummaryItems(transactions, accountMap, categoryMap, converter.DefaultExporterOptions)
+	assert.Equal(t, 5, len(summaryItems))
+
+	assert.Equal(t, "2024-08", summaryItems[0].month)
+	assert.Equal(t, models.TRANSACTION_TYPE_INCOME, summaryItems[0].transactionType)
+	assert.Equal(t, "Test Sub Category", summaryItems[0].subCategoryName)
+	assert.Equal(t, int64(500), summaryItems[0].amount)
+
+	assert.Equal(t, "2024-08", summaryItems[1].month)
+	assert.Equal(t, models.TRANSACTION_TYPE_EXPENSE, summaryItems[1].transactionType)
+	assert.Equal(t, "", summaryItems[1].categoryName)
+	assert.Equal(t, "", summaryItems[1].subCategoryName)
+	assert.Equal(t, int64(600), summaryItems[1].amount)
+
+	assert.Equal(t, "2024-08", summaryItems[2].month)
+	assert.Equal(t, "Test Sub Category2", summaryItems[2].subCategoryName)
+	assert.Equal(t, "CNY", summaryItems[2].currency)
+	assert.Equal(t, int64(300), summaryItems[2].amount)
+	assert.Equal(t, 1, summaryItems[2].count)
+
+	assert.Equal(t, "2024-08", summaryItems[3].month)
+	assert.Equal(t, "Test Sub Category2", summaryItems[3].subCategoryName)
+	assert.Equal(t, "USD", summaryItems[3].currency)
+	assert.Equal(t, int64(400), summaryItems[3].amount)
+
+	assert.Equal(t, "2024-09", summaryItems[4].month)
+	assert.Equal(t, "Test Sub Category2", summaryItems[4].subCategoryName)
+	assert.Equal(t, "CNY", summaryItems[4].currency)
+	assert.Equal(t, int64(300), summaryItems[4].amount)
+	assert.Equal(t, 2, summaryItems[4].count)
+}
+
+func createTestExcelOOXMLExportedTransactions() ([]*models.Transaction, map[int64]*models.Account, map[int64]*models.TransactionCategory, map[int64]*models.TransactionTag, map[int64][]int64) {
+	transactions := make([]*models.Transaction, 5)
+	transactions[0] = &models.Transaction{
+		TransactionId:     1,
+		TransactionTime:   1725165296000,
+		Type:              models.TRANSACTION_DB_TYPE_INCOME,
+		TimezoneUtcOffset: 480,
+		CategoryId:        12,
+		AccountId:         1,
+		Amount:            1234567,
+		Comment:           "Foo\nBar",
+	}
+	transactions[1] = &models.Transaction{
+		TransactionId:     2,
+		TransactionTime:   1725194096000,
+		Type:              models.TRANSACTION_DB_TYPE_EXPENSE,
+		TimezoneUtcOffset: 0,
+		CategoryId:        22,
+		AccountId:         1,
+		Amount:            10,
+	}
+	transactions[2] = &models.Transaction{
+		TransactionId:        3,
+		TransactionTime:      1725212096000,
+		Type:                 models.TRANSACTION_DB_TYPE_TRANSFER_OUT,
+		TimezoneUtcOffset:    -300,
+		CategoryId:           32,
+		AccountId:            1,
+		Amount:               10000,
+		RelatedId:            4,
+		RelatedAccountId:     3,
+		RelatedAccountAmount: 1400,
+	}
+	transactions[3] = &models.Transaction{
+		TransactionId:        4,
+		TransactionTime:      1725212096000,
+		Type:                 models.TRANSACTION_DB_TYPE_TRANSFER_IN,
+		TimezoneUtcOffset:    -300,
+		CategoryId:           32,
+		AccountId:            3,
+		Amount:               1400,
+		RelatedId:            3,
+		RelatedAccountId:     1,
+		RelatedAccountAmount: 10000,
+	}
+	transactions[4] = &models.Transaction{
+		TransactionId:        5,
+		TransactionTime:      1725100000000,
+		Type:                 models.TRANSACTION_DB_TYPE_MODIFY_BALANCE,
+		TimezoneUtcOffset:    480,
+		AccountId:            1,
+		Amount:               100000,
+		RelatedAccountAmount: 100000,
+	}
+
+	accountMap := make(map[int64]*models.Account, 4)
+	accountMap[1] = &models.Account{
+		AccountId:    1,
+		Category:     models.ACCOUNT_CATEGORY_CHECKING_ACCOUNT,
+		Name:         "Test Account",
+		DisplayOrder: 1,
+		Currency:     "CNY",
+		Balance:      1224557,
+	}
+	accountMap[2] = &models.Account{
+		AccountId:    2,
+		Category:     models.ACCOUNT_CATEGORY_CREDIT_CARD,
+		Name:         "Test Credit Card",
+		DisplayOrder: 1,
+		Currency:     "CNY",
+		Balance:      -173500,
+	}
+	accountMap[3] = &models.Account{
+		AccountId:       3,
+		Category:        models.ACCOUNT_CATEGORY_CHECKING_ACCOUNT,
+		ParentAccountId: 4,
+		Name:            "USD Cash",
+		DisplayOrder:    1,
+		Currency:        "USD",
+		Balance:         1400,
+	}
+	accountMap[4] = &models.Account{
+		AccountId:    4,
+		Category:     models.ACCOUNT_CATEGORY_CHECKING_ACCOUNT,
+		Name:         "Test Parent Account",
+		DisplayOrder: 2,
+		Currency:     validators.ParentAccountCurrencyPlaceholder,
+	}
+
+	categoryMap := make(map[int64]*models.TransactionCategory, 6)
+	categoryMap[11] = &models.TransactionCategory{
+		CategoryId:       11,
+		Name:             "Test Category",
+		ParentCategoryId: models.LevelOneTransactionCategoryParentId,
+	}
+	categoryMap[12] = &models.TransactionCategory{
+		CategoryId:       12,
+		Name:             "Test Sub Category",
+		ParentCategoryId: 11,
+	}
+	categoryMap[21] = &models.TransactionCategory{
+		CategoryId:       21,
+		Name:             "Test Category2",
+		ParentCategoryId: models.LevelOneTransactionCategoryParentId,
+	}
+	categoryMap[22] = &models.TransactionCategory{
+		CategoryId:       22,
+		Name:             "Test Sub Category2",
+		ParentCategoryId: 21,
+	}
+	categoryMap[31] = &models.TransactionCategory{
+		CategoryId:       31,
+		Name:             "Test Category3",
+		ParentCategoryId: models.LevelOneTransactionCategoryParentId,
+	}
+	categoryMap[32] = &models.TransactionCategory{
+		CategoryId:       32,
+		Name:             "Test Sub Category3",
+		ParentCategoryId: 31,
+	}
+
+	tagMap := make(map[int64]*models.TransactionTag, 2)
+	tagMap[41] = &models.TransactionTag{
+		TagId: 41,
+		Name:  "Tag 1",
+	}
+	tagMap[42] = &models.TransactionTag{
+		TagId: 42,
+		Name:  "Tag2",
+	}
+
+	allTagIndexes := make(map[int64][]int64, 1)
+	allTagIndexes[1] = []int64{41, 42}
+
+	return transactions, accountMap, categoryMap, tagMap, allTagIndexes
+}
@@ -4,23 +4,31 @@ import "encoding/xml"
 
 const gnucashCommodityCurrencySpace = "CURRENCY"
 const gnucashRootAccountType = "ROOT"
+const gnucashAssetAccountType = "ASSET"
+const gnucashBankAccountType = "BANK"
+const gnucashCashAccountType = "CASH"
+const gnucashCreditAccountType = "CREDIT"
+const gnucashLiabilityAccountType = "LIABILITY"
+const gnucashReceivableAccountType = "RECEIVABLE"
 const gnucashEquityAccountType = "EQUITY"
 const gnucashIncomeAccountType = "INCOME"
 const gnucashExpenseAccountType = "EXPENSE"
 
 const gnucashSlotEquityType = "equity-type"
 const gnucashSlotEquityTypeOpeningBalance = "opening-balance"
+const gnucashSlotPlaceholder = "placeholder"
+const gnucashSlotPlaceholderTrue = "true"
 
 var gnucashAssetOrLiabilityAccountTypes = map[string]bool{
-	"ASSET":      true,
-	"BANK":       true,
-	"CASH":       true,
-	"CREDIT":     true,
-	"LIABILITY":  true,
-	"MUTUAL":     true,
-	"PAYABLE":    true,
-	"RECEIVABLE": true,
-	"STOCK":      true,
+	gnucashAssetAccountType:      true,
+	gnucashBankAccountType:       true,
+	gnucashCashAccountType:       true,
+	gnucashCreditAccountType:     true,
+	gnucashLiabilityAccountType:  true,
+	"MUTUAL":                     true,
+	"PAYABLE":                    true,
+	gnucashReceivableAccountType: true,
+	"STOCK":                      true,
 }
 
 // gnucashDatabase represents the struct of gnucash database file
@@ -40,6 +48,7 @@ type gnucashCountData struct {
 type gnucashBookData struct {
 	Id           string                    `xml:"id"`
 	Counts       []*gnucashCountData       `xml:"count-data"`
+	Commodities  []*gnucashCommodityData   `xml:"commodity"`
 	Accounts     []*gnucashAccountData     `xml:"account"`
 	Transactions []*gnucashTransactionData `xml:"transaction"`
 }
@@ -0,0 +1,531 @@
+package gnucash
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/xml"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/converter"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+	"github.com/mayswind/ezbookkeeping/pkg/validators"
+)
+
+const gnucashExportedRootAccountName = "Root Account"
+const gnucashExportedAssetsAccountName = "Assets"
+const gnucashExportedLiabilitiesAccountName = "Liabilities"
+const gnucashExportedEquityAccountName = "Equity"
+const gnucashExportedOpeningBalancesAccountName = "Opening Balances"
+const gnucashExportedIncomeAccountName = "Income"
+const gnucashExportedExpensesAccountName = "Expenses"
+const gnucashExportedUnnamedAccountName = "Unnamed"
+
+const gnucashExportedElementVersion = "2.0.0"
+const gnucashExportedDateTimeFormat = "2006-01-02 15:04:05 -0700"
+const gnucashExportedAmountDenominator = "100"
+const gnucashExportedReconciledStateNotReconciled = "n"
+
+const gnucashExportedFileHeader = "<?xml version=\"1.0\" encoding=\"utf-8\" ?>\n" +
+	"<gnc-v2\n" +
+	"     xmlns:gnc=\"http://www.gnucash.org/XML/gnc\"\n" +
+	"     xmlns:act=\"http://www.gnucash.org/XML/act\"\n" +
+	"     xmlns:book=\"http://www.gnucash.org/XML/book\"\n" +
+	"     xmlns:cd=\"http://www.gnucash.org/XML/cd\"\n" +
+	"     xmlns:cmdty=\"http://www.gnucash.org/XML/cmdty\"\n" +
+	"     xmlns:slot=\"http://www.gnucash.org/XML/slot\"\n" +
+	"     xmlns:split=\"http://www.gnucash.org/XML/split\"\n" +
+	"     xmlns:trn=\"http://www.gnucash.org/XML/trn\"\n" +
+	"     xmlns:ts=\"http://www.gnucash.org/XML/ts\">\n"
+
+const gnucashExportedFileFooter = "</gnc-v2>\n"
+
+var gnucashExportedAccountNameReplacer = strings.NewReplacer(":", "-")
+
+var gnucashExportedAccountTypes = map[models.AccountCategory]string{
+	models.ACCOUNT_CATEGORY_CASH:                   gnucashCashAccountType,
+	models.ACCOUNT_CATEGORY_CHECKING_ACCOUNT:       gnucashBankAccountType,
+	models.ACCOUNT_CATEGORY_CREDIT_CARD:            gnucashCreditAccountType,
+	models.ACCOUNT_CATEGORY_VIRTUAL:                gnucashAssetAccountType,
+	models.ACCOUNT_CATEGORY_DEBT:                   gnucashLiabilityAccountType,
+	models.ACCOUNT_CATEGORY_RECEIVABLES:            gnucashReceivableAccountType,
+	models.ACCOUNT_CATEGORY_INVESTMENT:             gnucashAssetAccountType,
+	models.ACCOUNT_CATEGORY_SAVINGS_ACCOUNT:        gnucashBankAccountType,
+	models.ACCOUNT_CATEGORY_CERTIFICATE_OF_DEPOSIT: gnucashBankAccountType,
+}
+
+// gnucashTransactionDataExporter defines the structure of gnucash exporter for transaction data
+type gnucashTransactionDataExporter struct {
+}
+
+// gnucashExportedBookBuilder defines the structure of gnucash book builder for exporting
+type gnucashExportedBookBuilder struct {
+	uid             int64
+	defaultCurrency string
+	rootAccountId   string
+	accounts        []*gnucashAccountData
+	accountIds      map[string]string
+	currencies      map[string]bool
+}
+
+// Initialize a gnucash transaction data exporter singleton instance
+var (
+	GnuCashTransactionDataExporter = &gnucashTransactionDataExporter{}
+)
+
+// ToExportedContent returns the exported gnucash data, which is the gzipped xml file that can be opened by GnuCash,
+// ezbookkeeping accounts are placed under the top level asset and liability accounts, and categories are placed under
+// the top level income and expense accounts (one for each currency, because gnucash account only holds one commodity)
+func (c *gnucashTransactionDataExporter) ToExportedContent(ctx core.Context, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64, customFields []*models.TransactionCustomField, allCustomFieldValues map[int64][]*models.TransactionCustomFieldValue, options converter.TransactionDataExporterOptions) ([]byte, error) {
+	allTransactions := make([]*models.Transaction, 0, len(transactions))
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+			continue
+		}
+
+		if _, exists := accountMap[transaction.AccountId]; !exists {
+			log.Warnf(ctx, "[gnucash_transaction_data_file_exporter.ToExportedContent] account \"id:%d\" of transaction \"id:%d\" does not exist", transaction.AccountId, transaction.TransactionId)
+			continue
+		}
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+			if _, exists := accountMap[transaction.RelatedAccountId]; !exists {
+				log.Warnf(ctx, "[gnucash_transaction_data_file_exporter.ToExportedContent] related account \"id:%d\" of transaction \"id:%d\" does not exist", transaction.RelatedAccountId, transaction.TransactionId)
+				continue
+			}
+		}
+
+		allTransactions = append(allTransactions, transaction)
+	}
+
+	if len(allTransactions) < 1 {
+		return nil, nil
+	}
+
+	sort.SliceStable(allTransactions, func(i, j int) bool {
+		return allTransactions[i].TransactionTime < allTransactions[j].TransactionTime
+	})
+
+	builder := createNewGnuCashExportedBookBuilder(uid, c.getDefaultCurrency(allTransactions, accountMap))
+	gnucashTransactions := make([]*gnucashTransactionData, 0, len(allTransactions))
+
+	for i := 0; i < len(allTransactions); i++ {
+		transaction := allTransactions[i]
+		account := accountMap[transaction.AccountId]
+		accountId := builder.getAccountId(account, accountMap)
+		var splits []*gnucashTransactionSplitData
+
+		switch transaction.Type {
+		case models.TRANSACTION_DB_TYPE_MODIFY_BALANCE:
+			splits = []*gnucashTransactionSplitData{
+				c.createSplit(builder.getOpeningBalancesAccountId(account.Currency), -transaction.RelatedAccountAmount, -transaction.RelatedAccountAmount),
+				c.createSplit(accountId, transaction.RelatedAccountAmount, transaction.RelatedAccountAmount),
+			}
+		case models.TRANSACTION_DB_TYPE_INCOME:
+			splits = []*gnucashTransactionSplitData{
+				c.createSplit(builder.getCategoryAccountId(transaction.CategoryId, categoryMap, gnucashIncomeAccountType, account.Currency), -transaction.Amount, -transaction.Amount),
+				c.createSplit(accountId, transaction.Amount, transaction.Amount),
+			}
+		case models.TRANSACTION_DB_TYPE_EXPENSE:
+			splits = []*gnucashTransactionSplitData{
+				c.createSplit(accountId, -transaction.Amount, -transaction.Amount),
+				c.createSplit(builder.getCategoryAccountId(transaction.CategoryId, categoryMap, gnucashExpenseAccountType, account.Currency), transaction.Amount, transaction.Amount),
+			}
+		case models.TRANSACTION_DB_TYPE_TRANSFER_OUT:
+			relatedAccount := accountMap[transaction.RelatedAccountId]
+
+			// the value of split is in transaction currency, and the quantity of split is in account currency
+			splits = []*gnucashTransactionSplitData{
+				c.createSplit(accountId, -transaction.Amount, -transaction.Amount),
+				c.createSplit(builder.getAccountId(relatedAccount, accountMap), transaction.Amount, transaction.RelatedAccountAmount),
+			}
+		default:
+			continue
+		}
+
+		for j := 0; j < len(splits); j++ {
+			splits[j].Id = builder.getGuid("transaction:" + utils.Int64ToString(transaction.TransactionId) + ":split:" + utils.IntToString(j))
+		}
+
+		gnucashTransactions = append(gnucashTransactions, &gnucashTransactionData{
+			Id:          builder.getGuid("transaction:" + utils.Int64ToString(transaction.TransactionId)),
+			Currency:    c.createCurrencyCommodity(account.Currency),
+			PostedDate:  c.formatPostedDate(transaction, options.GetTransactionTimezone(transaction)),
+			EnteredDate: c.formatEnteredDate(transaction),
+			Description: transaction.Comment,
+			Splits:      splits,
+		})
+	}
+
+	currencies := make([]string, 0, len(builder.currencies))
+
+	for currency := range builder.currencies {
+		currencies = append(currencies, currency)
+	}
+
+	sort.Strings(currencies)
+	commodities := make([]*gnucashCommodityData, 0, len(currencies))
+
+	for i := 0; i < len(currencies); i++ {
+		commodities = append(commodities, c.createCurrencyCommodity(currencies[i]))
+	}
+
+	book := &gnucashBookData{
+		Id: builder.getGuid("book"),
+		Counts: []*gnucashCountData{
+			{Key: "commodity", Value: utils.IntToString(len(commodities))},
+			{Key: "account", Value: utils.IntToString(len(builder.accounts))},
+			{Key: "transaction", Value: utils.IntToString(len(gnucashTransactions))},
+		},
+		Commodities:  commodities,
+		Accounts:     builder.accounts,
+		Transactions: gnucashTransactions,
+	}
+
+	database := &gnucashDatabase{
+		Counts: []*gnucashCountData{
+			{Key: "book", Value: "1"},
+		},
+		Books: []*gnucashBookData{book},
+	}
+
+	var ret bytes.Buffer
+	gzipWriter := gzip.NewWriter(&ret)
+
+	if _, err := gzipWriter.Write(c.getXmlContent(database)); err != nil {
+		log.Errorf(ctx, "[gnucash_transaction_data_file_exporter.ToExportedContent] failed to compress exported data, because %s", err.Error())
+		return nil, err
+	}
+
+	if err := gzipWriter.Close(); err != nil {
+		log.Errorf(ctx, "[gnucash_transaction_data_file_exporter.ToExportedContent] failed to compress exported data, because %s", err.Error())
+		return nil, err
+	}
+
+	return ret.Bytes(), nil
+}
+
+// getDefaultCurrency returns the currency which is used by the most transactions, the top level accounts use this currency
+func (c *gnucashTransactionDataExporter) getDefaultCurrency(transactions []*models.Transaction, accountMap map[int64]*models.Account) string {
+	currencyCounts := make(map[string]int)
+
+	for i := 0; i < len(transactions); i++ {
+		currencyCounts[accountMap[transactions[i].AccountId].Currency]++
+	}
+
+	defaultCurrency := ""
+
+	for currency, count := range currencyCounts {
+		if defaultCurrency == "" || count > currencyCounts[defaultCurrency] || (count == currencyCounts[defaultCurrency] && currency < defaultCurrency) {
+			defaultCurrency = currency
+		}
+	}
+
+	return defaultCurrency
+}
+
+func (c *gnucashTransactionDataExporter) createSplit(accountId string, value int64, quantity int64) *gnucashTransactionSplitData {
+	return &gnucashTransactionSplitData{
+		ReconciledState: gnucashExportedReconciledStateNotReconciled,
+		Value:           c.formatAmount(value),
+		Quantity:        c.formatAmount(quantity),
+		Account:         accountId,
+	}
+}
+
+func (c *gnucashTransactionDataExporter) createCurrencyCommodity(currency string) *gnucashCommodityData {
+	return &gnucashCommodityData{
+		Space: gnucashCommodityCurrencySpace,
+		Id:    currency,
+	}
+}
+
+func (c *gnucashTransactionDataExporter) formatAmount(amount int64) string {
+	return utils.Int64ToString(amount) + "/" + gnucashExportedAmountDenominator
+}
+
+func (c *gnucashTransactionDataExporter) formatPostedDate(transaction *models.Transaction, timezone *time.Location) string {
+	return time.Unix(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime), 0).In(timezone).Format(gnucashExportedDateTimeFormat)
+}
+
+func (c *gnucashTransactionDataExporter) formatEnteredDate(transaction *models.Transaction) string {
+	enteredUnixTime := transaction.CreatedUnixTime
+
+	if enteredUnixTime <= 0 {
+		enteredUnixTime = utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime)
+	}
+
+	return time.Unix(enteredUnixTime, 0).In(time.UTC).Format(gnucashExportedDateTimeFormat)
+}
+
+func (c *gnucashTransactionDataExporter) getXmlContent(database *gnucashDatabase) []byte {
+	var ret bytes.Buffer
+
+	ret.WriteString(gnucashExportedFileHeader)
+
+	for i := 0; i < len(database.Counts); i++ {
+		c.writeCountData(&ret, database.Counts[i])
+	}
+
+	for i := 0; i < len(database.Books); i++ {
+		book := database.Books[i]
+
+		ret.WriteString("<gnc:book version=\"" + gnucashExportedElementVersion + "\">\n")
+		ret.WriteString("<book:id type=\"guid\">" + book.Id + "</book:id>\n")
+
+		for j := 0; j < len(book.Counts); j++ {
+			c.writeCountData(&ret, book.Counts[j])
+		}
+
+		for j := 0; j < len(book.Commodities); j++ {
+			ret.WriteString("<gnc:commodity version=\"" + gnucashExportedElementVersion + "\">\n")
+			c.writeCommodityItems(&ret, "  ", book.Commodities[j])
+			ret.WriteString("</gnc:commodity>\n")
+		}
+
+		for j := 0; j < len(book.Accounts); j++ {
+			c.writeAccount(&ret, book.Accounts[j])
+		}
+
+		for j := 0; j < len(book.Transactions); j++ {
+			c.writeTransaction(&ret, book.Transactions[j])
+		}
+
+		ret.WriteString("</gnc:book>\n")
+	}
+
+	ret.WriteString(gnucashExportedFileFooter)
+
+	return ret.Bytes()
+}
+
+func (c *gnucashTransactionDataExporter) writeCountData(ret *bytes.Buffer, countData *gnucashCountData) {
+	ret.WriteString("<gnc:count-data cd:type=\"" + countData.Key + "\">" + countData.Value + "</gnc:count-data>\n")
+}
+
+func (c *gnucashTransactionDataExporter) writeCommodityItems(ret *bytes.Buffer, indent string, commodity *gnucashCommodityData) {
+	ret.WriteString(indent + "<cmdty:space>" + c.escapeText(commodity.Space) + "</cmdty:space>\n")
+	ret.WriteString(indent + "<cmdty:id>" + c.escapeText(commodity.Id) + "</cmdty:id>\n")
+}
+
+func (c *gnucashTransactionDataExporter) writeAccount(ret *bytes.Buffer, account *gnucashAccountData) {
+	ret.WriteString("<gnc:account version=\"" + gnucashExportedElementVersion + "\">\n")
+	ret.WriteString("  <act:name>" + c.escapeText(account.Name) + "</act:name>\n")
+	ret.WriteString("  <act:id type=\"guid\">" + account.Id + "</act:id>\n")
+	ret.WriteString("  <act:type>" + account.AccountType + "</act:type>\n")
+
+	if account.Commodity != nil {
+		ret.WriteString("  <act:commodity>\n")
+		c.writeCommodityItems(ret, "    ", account.Commodity)
+		ret.WriteString("  </act:commodity>\n")
+		ret.WriteString("  <act:commodity-scu>" + gnucashExportedAmountDenominator + "</act:commodity-scu>\n")
+	}
+
+	if account.Description != "" {
+		ret.WriteString("  <act:description>" + c.escapeText(account.Description) + "</act:description>\n")
+	}
+
+	if len(account.Slots) > 0 {
+		ret.WriteString("  <act:slots>\n")
+
+		for i := 0; i < len(account.Slots); i++ {
+			ret.WriteString("    <slot>\n")
+			ret.WriteString("      <slot:key>" + c.escapeText(account.Slots[i].Key) + "</slot:key>\n")
+			ret.WriteString("      <slot:value type=\"string\">" + c.escapeText(account.Slots[i].Value) + "</slot:value>\n")
+			ret.WriteString("    </slot>\n")
+		}
+
+		ret.WriteString("  </act:slots>\n")
+	}
+
+	if account.ParentId != "" {
+		ret.WriteString("  <act:parent type=\"guid\">" + account.ParentId + "</act:parent>\n")
+	}
+
+	ret.WriteString("</gnc:account>\n")
+}
+
+func (c *gnucashTransactionDataExporter) writeTransaction(ret *bytes.Buffer, transaction *gnucashTransactionData) {
+	ret.WriteString("<gnc:transaction version=\"" + gnucashExportedElementVersion + "\">\n")
+	ret.WriteString("  <trn:id type=\"guid\">" + transaction.Id + "</trn:id>\n")
+	ret.WriteString("  <trn:currency>\n")
+	c.writeCommodityItems(ret, "    ", transaction.Currency)
+	ret.WriteString("  </trn:currency>\n")
+	ret.WriteString("  <trn:date-posted>\n")
+	ret.WriteString("    <ts:date>" + transaction.PostedDate + "</ts:date>\n")
+	ret.WriteString("  </trn:date-posted>\n")
+	ret.WriteString("  <trn:date-entered>\n")
+	ret.WriteString("    <ts:date>" + transaction.EnteredDate + "</ts:date>\n")
+	ret.WriteString("  </trn:date-entered>\n")
+	ret.WriteString("  <trn:description>" + c.escapeText(transaction.Description) + "</trn:description>\n")
+	ret.WriteString("  <trn:splits>\n")
+
+	for i := 0; i < len(transaction.Splits); i++ {
+		split := transaction.Splits[i]
+
+		ret.WriteString("    <trn:split>\n")
+		ret.WriteString("      <split:id type=\"guid\">" + split.Id + "</split:id>\n")
+		ret.WriteString("      <split:reconciled-state>" + split.ReconciledState + "</split:reconciled-state>\n")
+		ret.WriteString("      <split:value>" + split.Value + "</split:value>\n")
+		ret.WriteString("      <split:quantity>" + split.Quantity + "</split:quantity>\n")
+		ret.WriteString("      <split:account type=\"guid\">" + split.Account + "</split:account>\n")
+		ret.WriteString("    </trn:split>\n")
+	}
+
+	ret.WriteString("  </trn:splits>\n")
+	ret.WriteString("</gnc:transaction>\n")
+}
+
+func (c *gnucashTransactionDataExporter) escapeText(text string) string {
+	var ret bytes.Buffer
+	_ = xml.EscapeText(&ret, []byte(text))
+	return ret.String()
+}
+
+// getGuid returns the guid of specified item, which is stable for the same user and the same item key
+func (b *gnucashExportedBookBuilder) getGuid(key string) string {
+	return utils.MD5EncodeToString([]byte(utils.Int64ToString(b.uid) + ":" + key))
+}
+
+func (b *gnucashExportedBookBuilder) getAccountId(account *models.Account, accountMap map[int64]*models.Account) string {
+	parentId := ""
+
+	if account.ParentAccountId != models.LevelOneAccountParentId {
+		if parentAccount, exists := accountMap[account.ParentAccountId]; exists {
+			parentId = b.getAccountId(parentAccount, accountMap)
+		}
+	}
+
+	if parentId == "" {
+		if account.Category.IsLiability() {
+			parentId = b.getOrAddAccount("top:liabilities", gnucashExportedLiabilitiesAccountName, gnucashLiabilityAccountType, b.rootAccountId, b.defaultCurrency, b.getPlaceholderSlots())
+		} else {
+			parentId = b.getOrAddAccount("top:assets", gnucashExportedAssetsAccountName, gnucashAssetAccountType, b.rootAccountId, b.defaultCurrency, b.getPlaceholderSlots())
+		}
+	}
+
+	accountType, exists := gnucashExportedAccountTypes[account.Category]
+
+	if !exists {
+		accountType = gnucashAssetAccountType
+	}
+
+	currency := account.Currency
+	var slots []*gnucashSlotData
+
+	if currency == "" || currency == validators.ParentAccountCurrencyPlaceholder {
+		currency = b.defaultCurrency
+		slots = b.getPlaceholderSlots()
+	}
+
+	return b.getOrAddAccount("account:"+utils.Int64ToString(account.AccountId), b.getAccountName(account.Name), accountType, parentId, currency, slots)
+}
+
+func (b *gnucashExportedBookBuilder) getCategoryAccountId(categoryId int64, categoryMap map[int64]*models.TransactionCategory, accountType string, currency string) string {
+	parentId := ""
+	categoryName := gnucashExportedUnnamedAccountName
+
+	if accountType == gnucashIncomeAccountType {
+		parentId = b.getOrAddAccount("top:income:"+currency, b.getCurrencySpecifiedAccountName(gnucashExportedIncomeAccountName, currency), accountType, b.rootAccountId, currency, b.getPlaceholderSlots())
+	} else {
+		parentId = b.getOrAddAccount("top:expenses:"+currency, b.getCurrencySpecifiedAccountName(gnucashExportedExpensesAccountName, currency), accountType, b.rootAccountId, currency, b.getPlaceholderSlots())
+	}
+
+	if category, exists := categoryMap[categoryId]; exists {
+		categoryName = b.getAccountName(category.Name)
+
+		if category.ParentCategoryId != models.LevelOneTransactionCategoryParentId {
+			if parentCategory, exists := categoryMap[category.ParentCategoryId]; exists {
+				parentId = b.getOrAddAccount("category:"+utils.Int64ToString(parentCategory.CategoryId)+":"+currency, b.getAccountName(parentCategory.Name), accountType, parentId, currency, nil)
+			}
+		}
+	}
+
+	return b.getOrAddAccount("category:"+utils.Int64ToString(categoryId)+":"+currency, categoryName, accountType, parentId, currency, nil)
+}
+
+func (b *gnucashExportedBookBuilder) getOpeningBalancesAccountId(currency string) string {
+	parentId := b.getOrAddAccount("top:equity", gnucashExportedEquityAccountName, gnucashEquityAccountType, b.rootAccountId, b.defaultCurrency, b.getPlaceholderSlots())
+	slots := []*gnucashSlotData{
+		{Key: gnucashSlotEquityType, Value: gnucashSlotEquityTypeOpeningBalance},
+	}
+
+	return b.getOrAddAccount("equity:opening-balances:"+currency, b.getCurrencySpecifiedAccountName(gnucashExportedOpeningBalancesAccountName, currency), gnucashEquityAccountType, parentId, currency, slots)
+}
+
+func (b *gnucashExportedBookBuilder) getOrAddAccount(key string, name string, accountType string, parentId string, currency string, slots []*gnucashSlotData) string {
+	if accountId, exists := b.accountIds[key]; exists {
+		return accountId
+	}
+
+	accountId := b.getGuid(key)
+	account := &gnucashAccountData{
+		Name:        name,
+		Id:          accountId,
+		AccountType: accountType,
+		ParentId:    parentId,
+		Slots:       slots,
+	}
+
+	if currency != "" {
+		account.Commodity = &gnucashCommodityData{
+			Space: gnucashCommodityCurrencySpace,
+			Id:    currency,
+		}
+
+		b.currencies[currency] = true
+	}
+
+	b.accounts = append(b.accounts, account)
+	b.accountIds[key] = accountId
+
+	return accountId
+}
+
+// getCurrencySpecifiedAccountName returns the account name with currency suffix if the currency is not the default currency,
+// which is the same as the naming rule of the opening balances accounts created by gnucash
+func (b *gnucashExportedBookBuilder) getCurrencySpecifiedAccountName(name string, currency string) string {
+	if currency == b.defaultCurrency {
+		return name
+	}
+
+	return name + " - " + currency
+}
+
+// getAccountName returns the account name without default account separator and consecutive whitespaces
+func (b *gnucashExportedBookBuilder) getAccountName(name string) string {
+	accountName := strings.Join(strings.Fields(gnucashExportedAccountNameReplacer.Replace(name)), " ")
+
+	if accountName == "" {
+		return gnucashExportedUnnamedAccountName
+	}
+
+	return accountName
+}
+
+func (b *gnucashExportedBookBuilder) getPlaceholderSlots() []*gnucashSlotData {
+	return []*gnucashSlotData{
+		{Key: gnucashSlotPlaceholder, Value: gnucashSlotPlaceholderTrue},
+	}
+}
+
+func createNewGnuCashExportedBookBuilder(uid int64, defaultCurrency string) *gnucashExportedBookBuilder {
+	builder := &gnucashExportedBookBuilder{
+		uid:             uid,
+		defaultCurrency: defaultCurrency,
+		accounts:        make([]*gnucashAccountData, 0),
+		accountIds:      make(map[string]string),
+		currencies:      make(map[string]bool),
+	}
+
+	builder.rootAccountId = builder.getOrAddAccount("root", gnucashExportedRootAccountName, gnucashRootAccountType, "", "", nil)
+
+	return builder
+}
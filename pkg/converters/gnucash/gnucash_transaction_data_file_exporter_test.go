@@ -0,0 +1,575 @@
+package gnucash
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/converter"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+	"github.com/mayswind/ezbookkeeping/pkg/validators"
+)
+
+func TestGnuCashTransactionDataExporterToExportedContent(t *testing.T) {
+	exporter := GnuCashTransactionDataExporter
+	context := core.NewNullContext()
+
+	transactions := make([]*models.Transaction, 1)
+	transactions[0] = &models.Transaction{
+		TransactionId:     1,
+		TransactionTime:   1725165296000,
+		Type:              models.TRANSACTION_DB_TYPE_INCOME,
+		TimezoneUtcOffset: 480,
+		CategoryId:        12,
+		AccountId:         1,
+		Amount:            12345,
+		Comment:           "Foo & <Bar>\nsecond line",
+		CreatedUnixTime:   1725165300,
+	}
+
+	accountMap := make(map[int64]*models.Account, 1)
+	accountMap[1] = &models.Account{
+		AccountId: 1,
+		Category:  models.ACCOUNT_CATEGORY_CHECKING_ACCOUNT,
+		Name:      "Test Account",
+		Currency:  "CNY",
+	}
+
+	categoryMap := make(map[int64]*models.TransactionCategory, 2)
+	categoryMap[11] = &models.TransactionCategory{
+		CategoryId:       11,
+		Name:             "Test Category",
+		ParentCategoryId: models.LevelOneTransactionCategoryParentId,
+	}
+	categoryMap[12] = &models.TransactionCategory{
+		CategoryId:       12,
+		Name:             "Test Sub Category",
+		ParentCategoryId: 11,
+	}
+
+	actualContent, err := exporter.ToExportedContent(context, 123, transactions, accountMap, categoryMap, nil, nil, nil, nil, converter.DefaultExporterOptions)
+	assert.Nil(t, err)
+	assert.Equal(t, byte(0x1F), actualContent[0])
+	assert.Equal(t, byte(0x8B), actualContent[1])
+
+	gzipReader, err := gzip.NewReader(bytes.NewReader(actualContent))
+	assert.Nil(t, err)
+
+	actualXmlContent, err := io.ReadAll(gzipReader)
+	assert.Nil(t, err)
+
+	expectedXmlContent := "<?xml version=\"1.0\" encoding=\"utf-8\" ?>\n" +
+		"<gnc-v2\n" +
+		"     xmlns:gnc=\"http://www.gnucash.org/XML/gnc\"\n" +
+		"     xmlns:act=\"http://www.gnucash.org/XML/act\"\n" +
+		"     xmlns:book=\"http://www.gnucash.org/XML/book\"\n" +
+		"     xmlns:cd=\"http://www.gnucash.org/XML/cd\"\n" +
+		"     xmlns:cmdty=\"http://www.gnucash.org/XML/cmdty\"\n" +
+		"     xmlns:slot=\"http://www.gnucash.org/XML/slot\"\n" +
+		"     xmlns:split=\"http://www.gnucash.org/XML/split\"\n" +
+		"     xmlns:trn=\"http://www.gnucash.org/XML/trn\"\n" +
+		"     xmlns:ts=\"http://www.gnucash.org/XML/ts\">\n" +
+		"<gnc:count-data cd:type=\"book\">1</gnc:count-data>\n" +
+		"<gnc:book version=\"2.0.0\">\n" +
+		"<book:id type=\"guid\">7f021808d17b61c0cf71c2ad8b1138ad</book:id>\n" +
+		"<gnc:count-data cd:type=\"commodity\">1</gnc:count-data>\n" +
+		"<gnc:count-data cd:type=\"account\">6</gnc:count-data>\n" +
+		"<gnc:count-data cd:type=\"transaction\">1</gnc:count-data>\n" +
+		"<gnc:commodity version=\"2.0.0\">\n" +
+		"  <cmdty:space>CURRENCY</cmdty:space>\n" +
+		"  <cmdty:id>CNY</cmdty:id>\n" +
+		"</gnc:commodity>\n" +
+		"<gnc:account version=\"2.0.0\">\n" +
+		"  <act:name>Root Account</act:name>\n" +
+		"  <act:id type=\"guid\">3c76a4f1d2e9b74501283874e07d9f59</act:id>\n" +
+		"  <act:type>ROOT</act:type>\n" +
+		"</gnc:account>\n" +
+		"<gnc:account version=\"2.0.0\">\n" +
+		"  <act:name>Assets</act:name>\n" +
+		"  <act:id type=\"guid\">31de32235b87067f4871ed44cbadd53d</act:id>\n" +
+		"  <act:type>ASSET</act:type>\n" +
+		"  <act:commodity>\n" +
+		"    <cmdty:space>CURRENCY</cmdty:space>\n" +
+		"    <cmdty:id>CNY</cmdty:id>\n" +
+		"  </act:commodity>\n" +
+		"  <act:commodity-scu>100</act:commodity-scu>\n" +
+		"  <act:slots>\n" +
+		"    <slot>\n" +
+		"      <slot:key>placeholder</slot:key>\n" +
+		"      <slot:value type=\"string\">true</slot:value>\n" +
+		"    </slot>\n" +
+		"  </act:slots>\n" +
+		"  <act:parent type=\"guid\">3c76a4f1d2e9b74501283874e07d9f59</act:parent>\n" +
+		"</gnc:account>\n" +
+		"<gnc:account version=\"2.0.0\">\n" +
+		"  <act:name>Test Account</act:name>\n" +
+		"  <act:id type=\"guid\">1609cc4e2fb2df133a4109a60b934b38</act:id>\n" +
+		"  <act:type>BANK</act:type>\n" +
+		"  <act:commodity>\n" +
+		"    <cmdty:space>CURRENCY</cmdty:space>\n" +
+		"    <cmdty:id>CNY</cmdty:id>\n" +
+		"  </act:commodity>\n" +
+		"  <act:commodity-scu>100</act:commodity-scu>\n" +
+		"  <act:parent type=\"guid\">31de32235b87067f4871ed44cbadd53d</act:parent>\n" +
+		"</gnc:account>\n" +
+		"<gnc:account version=\"2.0.0\">\n" +
+		"  <act:name>Income</act:name>\n" +
+		"  <act:id type=\"guid\">d58fc23eebe7ad90261e487bd9d079a0</act:id>\n" +
+		"  <act:type>INCOME</act:type>\n" +
+		"  <act:commodity>\n" +
+		"    <cmdty:space>CURRENCY</cmdty:space>\n" +
+		"    <cmdty:id>CNY</cmdty:id>\n" +
+		"  </act:commodity>\n" +
+		"  <act:commodity-scu>100</act:commodity-scu>\n" +
+		"  <act:slots>\n" +
+		"    <slot>\n" +
+		"      <slot:key>placeholder</slot:key>\n" +
+		"      <slot:value type=\"string\">true</slot:value>\n" +
+		"    </slot>\n" +
+		"  </act:slots>\n" +
+		"  <act:parent type=\"guid\">3c76a4f1d2e9b74501283874e07d9f59</act:parent>\n" +
+		"</gnc:account>\n" +
+		"<gnc:account version=\"2.0.0\">\n" +
+		"  <act:name>Test Category</act:name>\n" +
+		"  <act:id type=\"guid\">8054d05a623f9cab1b8fc0ac986e5b2c</act:id>\n" +
+		"  <act:type>INCOME</act:type>\n" +
+		"  <act:commodity>\n" +
+		"    <cmdty:space>CURRENCY</cmdty:space>\n" +
+		"    <cmdty:id>CNY</cmdty:id>\n" +
+		"  </act:commodity>\n" +
+		"  <act:commodity-scu>100</act:commodity-scu>\n" +
+		"  <act:parent type=\"guid\">d58fc23eebe7ad90261e487bd9d079a0</act:parent>\n" +
+		"</gnc:account>\n" +
+		"<gnc:account version=\"2.0.0\">\n" +
+		"  <act:name>Test Sub Category</act:name>\n" +
+		"  <act:id type=\"guid\">272c975f5e2a09afb4e83d636299fea3</act:id>\n" +
+		"  <act:type>INCOME</act:type>\n" +
+		"  <act:commodity>\n" +
+		"    <cmdty:space>CURRENCY</cmdty:space>\n" +
+		"    <cmdty:id>CNY</cmdty:id>\n" +
+		"  </act:commodity>\n" +
+		"  <act:commodity-scu>100</act:commodity-scu>\n" +
+		"  <act:parent type=\"guid\">8054d05a623f9cab1b8fc0ac986e5b2c</act:parent>\n" +
+		"</gnc:account>\n" +
+		"<gnc:transaction version=\"2.0.0\">\n" +
+		"  <trn:id type=\"guid\">6f39b0c9cf057c8d50bbc63fc18901d1</trn:id>\n" +
+		"  <trn:currency>\n" +
+		"    <cmdty:space>CURRENCY</cmdty:space>\n" +
+		"    <cmdty:id>CNY</cmdty:id>\n" +
+		"  </trn:currency>\n" +
+		"  <trn:date-posted>\n" +
+		"    <ts:date>2024-09-01 12:34:56 +0800</ts:date>\n" +
+		"  </trn:date-posted>\n" +
+		"  <trn:date-entered>\n" +
+		"    <ts:date>2024-09-01 04:35:00 +0000</ts:date>\n" +
+		"  </trn:date-entered>\n" +
+		"  <trn:description>Foo &amp; &lt;Bar&gt;&#xA;second line</trn:description>\n" +
+		"  <trn:splits>\n" +
+		"    <trn:split>\n" +
+		"      <split:id type=\"guid\">0a44b8f54a0678c4048d94a948c06bef</split:id>\n" +
+		"      <split:reconciled-state>n</split:reconciled-state>\n" +
+		"      <split:value>-12345/100</split:value>\n" +
+		"      <split:quantity>-12345/100</split:quantity>\n" +
+		"      <split:account type=\"guid\">272c975f5e2a09afb4e83d636299fea3</split:account>\n" +
+		"    </trn:split>\n" +
+		"    <trn:split>\n" +
+		"      <split:id type=\"guid\">2354d92a0ef3e5d938ed4a46683b104d</split:id>\n" +
+		"      <split:reconciled-state>n</split:reconciled-state>\n" +
+		"      <split:value>12345/100</split:value>\n" +
+		"      <split:quantity>12345/100</split:quantity>\n" +
+		"      <split:account type=\"guid\">1609cc4e2fb2df133a4109a60b934b38</split:account>\n" +
+		"    </trn:split>\n" +
+		"  </trn:splits>\n" +
+		"</gnc:transaction>\n" +
+		"</gnc:book>\n" +
+		"</gnc-v2>\n"
+
+	assert.Equal(t, expectedXmlContent, string(actualXmlContent))
+}
+
+func TestGnuCashTransactionDataExporterToExportedContent_TransferSplits(t *testing.T) {
+	exporter := GnuCashTransactionDataExporter
+	context := core.NewNullContext()
+
+	transactions := []*models.Transaction{
+		{
+			TransactionId:        1,
+			TransactionTime:      1725212096000,
+			Type:                 models.TRANSACTION_DB_TYPE_TRANSFER_OUT,
+			TimezoneUtcOffset:    -300,
+			AccountId:            1,
+			Amount:               1735,
+			RelatedId:            2,
+			RelatedAccountId:     2,
+			RelatedAccountAmount: 1735,
+		},
+		{
+			TransactionId:        2,
+			TransactionTime:      1725212096000,
+			Type:                 models.TRANSACTION_DB_TYPE_TRANSFER_IN,
+			TimezoneUtcOffset:    -300,
+			AccountId:            2,
+			Amount:               1735,
+			RelatedId:            1,
+			RelatedAccountId:     1,
+			RelatedAccountAmount: 1735,
+		},
+	}
+	accountMap := map[int64]*models.Account{
+		1: {AccountId: 1, Category: models.ACCOUNT_CATEGORY_CHECKING_ACCOUNT, Name: "Test Account", Currency: "CNY"},
+		2: {AccountId: 2, Category: models.ACCOUNT_CATEGORY_CREDIT_CARD, Name: "Test Credit Card", Currency: "CNY"},
+	}
+
+	actualContent, err := exporter.ToExportedContent(context, 123, transactions, accountMap, nil, nil, nil, nil, nil, converter.DefaultExporterOptions)
+	assert.Nil(t, err)
+
+	actualXmlContent := readTestGnuCashExportedXmlContent(t, actualContent)
+	assert.Contains(t, actualXmlContent, "<gnc:count-data cd:type=\"transaction\">1</gnc:count-data>\n")
+	assert.Equal(t, 1, strings.Count(actualXmlContent, "<gnc:transaction "))
+	assert.Equal(t, 2, strings.Count(actualXmlContent, "<trn:split>"))
+	assert.Contains(t, actualXmlContent, "<split:value>-1735/100</split:value>\n      <split:quantity>-1735/100</split:quantity>\n")
+	assert.Contains(t, actualXmlContent, "<split:value>1735/100</split:value>\n      <split:quantity>1735/100</split:quantity>\n")
+}
+
+func TestGnuCashTransactionDataExporterToExportedContent_MultiCurrencyTransfer(t *testing.T) {
+	exporter := GnuCashTransactionDataExporter
+	context := core.NewNullContext()
+
+	transactions := []*models.Transaction{
+		{
+			TransactionId:        1,
+			TransactionTime:      1725212156000,
+			Type:                 models.TRANSACTION_DB_TYPE_TRANSFER_OUT,
+			TimezoneUtcOffset:    -300,
+			AccountId:            1,
+			Amount:               10000,
+			RelatedId:            2,
+			RelatedAccountId:     2,
+			RelatedAccountAmount: 1400,
+		},
+		{
+			TransactionId:        2,
+			TransactionTime:      1725212156000,
+			Type:                 models.TRANSACTION_DB_TYPE_TRANSFER_IN,
+			TimezoneUtcOffset:    -300,
+			AccountId:            2,
+			Amount:               1400,
+			RelatedId:            1,
+			RelatedAccountId:     1,
+			RelatedAccountAmount: 10000,
+		},
+	}
+	accountMap := map[int64]*models.Account{
+		1: {AccountId: 1, Category: models.ACCOUNT_CATEGORY_CHECKING_ACCOUNT, Name: "Test Account", Currency: "CNY"},
+		2: {AccountId: 2, Category: models.ACCOUNT_CATEGORY_CASH, Name: "USD Cash", Currency: "USD"},
+	}
+
+	actualContent, err := exporter.ToExportedContent(context, 123, transactions, accountMap, nil, nil, nil, nil, nil, converter.DefaultExporterOptions)
+	assert.Nil(t, err)
+
+	actualXmlContent := readTestGnuCashExportedXmlContent(t, actualContent)
+	assert.Contains(t, actualXmlContent, "<gnc:count-data cd:type=\"commodity\">2</gnc:count-data>\n")
+	assert.Contains(t, actualXmlContent, "<trn:currency>\n    <cmdty:space>CURRENCY</cmdty:space>\n    <cmdty:id>CNY</cmdty:id>\n  </trn:currency>\n")
+	assert.Contains(t, actualXmlContent, "<split:value>-10000/100</split:value>\n      <split:quantity>-10000/100</split:quantity>\n")
+	assert.Contains(t, actualXmlContent, "<split:value>10000/100</split:value>\n      <split:quantity>1400/100</split:quantity>\n")
+}
+
+func TestGnuCashTransactionDataExporterToExportedContent_EmptyTransactions(t *testing.T) {
+	exporter := GnuCashTransactionDataExporter
+	context := core.NewNullContext()
+
+	actualContent, err := exporter.ToExportedContent(context, 123, nil, nil, nil, nil, nil, nil, nil, converter.DefaultExporterOptions)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(actualContent))
+}
+
+func TestGnuCashTransactionDataExporterToExportedContent_ImportExportedContent(t *testing.T) {
+	exporter := GnuCashTransactionDataExporter
+	importer := GnuCashTransactionDataImporter
+	context := core.NewNullContext()
+	transactions, accountMap, categoryMap := createTestGnuCashExportedTransactions()
+
+	actualContent, err := exporter.ToExportedContent(context, 123, transactions, accountMap, categoryMap, nil, nil, nil, nil, converter.DefaultExporterOptions)
+	assert.Nil(t, err)
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	allNewTransactions, allNewAccounts, allNewSubExpenseCategories, allNewSubIncomeCategories, _, _, err := importer.ParseImportedData(context, user, actualContent, time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 6, len(allNewTransactions))
+	assert.Equal(t, 3, len(allNewAccounts))
+	assert.Equal(t, 1, len(allNewSubExpenseCategories))
+	assert.Equal(t, 1, len(allNewSubIncomeCategories))
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_MODIFY_BALANCE, allNewTransactions[0].Type)
+	assert.Equal(t, int64(1725100000), utils.GetUnixTimeFromTransactionTime(allNewTransactions[0].TransactionTime))
+	assert.Equal(t, int16(480), allNewTransactions[0].TimezoneUtcOffset)
+	assert.Equal(t, int64(100000), allNewTransactions[0].Amount)
+	assert.Equal(t, "Test Account", allNewTransactions[0].OriginalSourceAccountName)
+	assert.Equal(t, "CNY", allNewTransactions[0].OriginalSourceAccountCurrency)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, allNewTransactions[1].Type)
+	assert.Equal(t, int64(1725165296), utils.GetUnixTimeFromTransactionTime(allNewTransactions[1].TransactionTime))
+	assert.Equal(t, int16(480), allNewTransactions[1].TimezoneUtcOffset)
+	assert.Equal(t, int64(12345), allNewTransactions[1].Amount)
+	assert.Equal(t, "Test Account", allNewTransactions[1].OriginalSourceAccountName)
+	assert.Equal(t, "Test Sub Category", allNewTransactions[1].OriginalCategoryName)
+	assert.Equal(t, "Foo & <Bar>\nsecond line", allNewTransactions[1].Comment)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[2].Type)
+	assert.Equal(t, int64(1725194096), utils.GetUnixTimeFromTransactionTime(allNewTransactions[2].TransactionTime))
+	assert.Equal(t, int16(0), allNewTransactions[2].TimezoneUtcOffset)
+	assert.Equal(t, int64(10), allNewTransactions[2].Amount)
+	assert.Equal(t, "Test Account", allNewTransactions[2].OriginalSourceAccountName)
+	assert.Equal(t, "Test Category2", allNewTransactions[2].OriginalCategoryName)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[3].Type)
+	assert.Equal(t, int64(1725212096), utils.GetUnixTimeFromTransactionTime(allNewTransactions[3].TransactionTime))
+	assert.Equal(t, int16(-300), allNewTransactions[3].TimezoneUtcOffset)
+	assert.Equal(t, int64(1735), allNewTransactions[3].Amount)
+	assert.Equal(t, "Test Account", allNewTransactions[3].OriginalSourceAccountName)
+	assert.Equal(t, int64(1735), allNewTransactions[3].RelatedAccountAmount)
+	assert.Equal(t, "Test Credit Card", allNewTransactions[3].OriginalDestinationAccountName)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[4].Type)
+	assert.Equal(t, int64(10000), allNewTransactions[4].Amount)
+	assert.Equal(t, "Test Account", allNewTransactions[4].OriginalSourceAccountName)
+	assert.Equal(t, "CNY", allNewTransactions[4].OriginalSourceAccountCurrency)
+	assert.Equal(t, int64(1400), allNewTransactions[4].RelatedAccountAmount)
+	assert.Equal(t, "USD Cash", allNewTransactions[4].OriginalDestinationAccountName)
+	assert.Equal(t, "USD", allNewTransactions[4].OriginalDestinationAccountCurrency)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[5].Type)
+	assert.Equal(t, int64(520), allNewTransactions[5].Amount)
+	assert.Equal(t, "USD Cash", allNewTransactions[5].OriginalSourceAccountName)
+	assert.Equal(t, "USD", allNewTransactions[5].OriginalSourceAccountCurrency)
+	assert.Equal(t, "Test Category2", allNewTransactions[5].OriginalCategoryName)
+}
+
+func TestGnuCashTransactionDataExporterGetDefaultCurrency(t *testing.T) {
+	exporter := GnuCashTransactionDataExporter
+	transactions, accountMap, _ := createTestGnuCashExportedTransactions()
+
+	assert.Equal(t, "CNY", exporter.getDefaultCurrency(transactions, accountMap))
+
+	transactions = []*models.Transaction{
+		{AccountId: 1},
+		{AccountId: 3},
+	}
+	assert.Equal(t, "CNY", exporter.getDefaultCurrency(transactions, accountMap))
+
+	transactions = []*models.Transaction{
+		{AccountId: 1},
+		{AccountId: 3},
+		{AccountId: 3},
+	}
+	assert.Equal(t, "USD", exporter.getDefaultCurrency(transactions, accountMap))
+}
+
+func TestGnuCashExportedBookBuilderGetAccountName(t *testing.T) {
+	builder := createNewGnuCashExportedBookBuilder(123, "CNY")
+
+	assert.Equal(t, "Test Account", builder.getAccountName("Test Account"))
+	assert.Equal(t, "test account!", builder.getAccountName(" test  account! "))
+	assert.Equal(t, "Ratio 1-2", builder.getAccountName("Ratio 1:2"))
+	assert.Equal(t, "招商银行", builder.getAccountName("招商银行"))
+	assert.Equal(t, "Unnamed", builder.getAccountName(" \t "))
+}
+
+func TestGnuCashExportedBookBuilderGetCurrencySpecifiedAccountName(t *testing.T) {
+	builder := createNewGnuCashExportedBookBuilder(123, "CNY")
+
+	assert.Equal(t, "Income", builder.getCurrencySpecifiedAccountName("Income", "CNY"))
+	assert.Equal(t, "Income - USD", builder.getCurrencySpecifiedAccountName("Income", "USD"))
+}
+
+func TestGnuCashExportedBookBuilderGetAccountId(t *testing.T) {
+	_, accountMap, _ := createTestGnuCashExportedTransactions()
+	builder := createNewGnuCashExportedBookBuilder(123, "CNY")
+
+	accountId := builder.getAccountId(accountMap[3], accountMap)
+	assert.Equal(t, accountId, builder.getAccountId(accountMap[3], accountMap))
+	assert.Equal(t, 4, len(builder.accounts))
+
+	assert.Equal(t, gnucashRootAccountType, builder.accounts[0].AccountType)
+
+	assert.Equal(t, "Assets", builder.accounts[1].Name)
+	assert.Equal(t, gnucashAssetAccountType, builder.accounts[1].AccountType)
+	assert.Equal(t, builder.rootAccountId, builder.accounts[1].ParentId)
+
+	assert.Equal(t, "Test Parent Account", builder.accounts[2].Name)
+	assert.Equal(t, gnucashCashAccountType, builder.accounts[2].AccountType)
+	assert.Equal(t, "CNY", builder.accounts[2].Commodity.Id)
+	assert.Equal(t, 1, len(builder.accounts[2].Slots))
+	assert.Equal(t, gnucashSlotPlaceholder, builder.accounts[2].Slots[0].Key)
+	assert.Equal(t, builder.accounts[1].Id, builder.accounts[2].ParentId)
+
+	assert.Equal(t, "USD Cash", builder.accounts[3].Name)
+	assert.Equal(t, accountId, builder.accounts[3].Id)
+	assert.Equal(t, "USD", builder.accounts[3].Commodity.Id)
+	assert.Equal(t, 0, len(builder.accounts[3].Slots))
+	assert.Equal(t, builder.accounts[2].Id, builder.accounts[3].ParentId)
+
+	accountId = builder.getAccountId(accountMap[2], accountMap)
+	assert.Equal(t, 6, len(builder.accounts))
+
+	assert.Equal(t, "Liabilities", builder.accounts[4].Name)
+	assert.Equal(t, gnucashLiabilityAccountType, builder.accounts[4].AccountType)
+
+	assert.Equal(t, "Test Credit Card", builder.accounts[5].Name)
+	assert.Equal(t, accountId, builder.accounts[5].Id)
+	assert.Equal(t, gnucashCreditAccountType, builder.accounts[5].AccountType)
+	assert.Equal(t, builder.accounts[4].Id, builder.accounts[5].ParentId)
+}
+
+func readTestGnuCashExportedXmlContent(t *testing.T, content []byte) string {
+	gzipReader, err := gzip.NewReader(bytes.NewReader(content))
+	assert.Nil(t, err)
+
+	xmlContent, err := io.ReadAll(gzipReader)
+	assert.Nil(t, err)
+
+	return string(xmlContent)
+}
+
+func createTestGnuCashExportedTransactions() ([]*models.Transaction, map[int64]*models.Account, map[int64]*models.TransactionCategory) {
+	transactions := make([]*models.Transaction, 8)
+	transactions[0] = &models.Transaction{
+		TransactionId:     1,
+		TransactionTime:   1725165296000,
+		Type:              models.TRANSACTION_DB_TYPE_INCOME,
+		TimezoneUtcOffset: 480,
+		CategoryId:        12,
+		AccountId:         1,
+		Amount:            12345,
+		Comment:           "Foo & <Bar>\nsecond line",
+	}
+	transactions[1] = &models.Transaction{
+		TransactionId:     2,
+		TransactionTime:   1725194096000,
+		Type:              models.TRANSACTION_DB_TYPE_EXPENSE,
+		TimezoneUtcOffset: 0,
+		CategoryId:        13,
+		AccountId:         1,
+		Amount:            10,
+	}
+	transactions[2] = &models.Transaction{
+		TransactionId:        3,
+		TransactionTime:      1725212096000,
+		Type:                 models.TRANSACTION_DB_TYPE_TRANSFER_OUT,
+		TimezoneUtcOffset:    -300,
+		AccountId:            1,
+		Amount:               1735,
+		RelatedId:            4,
+		RelatedAccountId:     2,
+		RelatedAccountAmount: 1735,
+	}
+	transactions[3] = &models.Transaction{
+		TransactionId:        4,
+		TransactionTime:      1725212096000,
+		Type:                 models.TRANSACTION_DB_TYPE_TRANSFER_IN,
+		TimezoneUtcOffset:    -300,
+		AccountId:            2,
+		Amount:               1735,
+		RelatedId:            3,
+		RelatedAccountId:     1,
+		RelatedAccountAmount: 1735,
+	}
+	transactions[4] = &models.Transaction{
+		TransactionId:        5,
+		TransactionTime:      1725100000000,
+		Type:                 models.TRANSACTION_DB_TYPE_MODIFY_BALANCE,
+		TimezoneUtcOffset:    480,
+		AccountId:            1,
+		Amount:               100000,
+		RelatedAccountAmount: 100000,
+	}
+	transactions[5] = &models.Transaction{
+		TransactionId:        6,
+		TransactionTime:      1725212156000,
+		Type:                 models.TRANSACTION_DB_TYPE_TRANSFER_OUT,
+		TimezoneUtcOffset:    -300,
+		AccountId:            1,
+		Amount:               10000,
+		RelatedId:            7,
+		RelatedAccountId:     3,
+		RelatedAccountAmount: 1400,
+	}
+	transactions[6] = &models.Transaction{
+		TransactionId:        7,
+		TransactionTime:      1725212156000,
+		Type:                 models.TRANSACTION_DB_TYPE_TRANSFER_IN,
+		TimezoneUtcOffset:    -300,
+		AccountId:            3,
+		Amount:               1400,
+		RelatedId:            6,
+		RelatedAccountId:     1,
+		RelatedAccountAmount: 10000,
+	}
+	transactions[7] = &models.Transaction{
+		TransactionId:     8,
+		TransactionTime:   1725212216000,
+		Type:              models.TRANSACTION_DB_TYPE_EXPENSE,
+		TimezoneUtcOffset: -300,
+		CategoryId:        13,
+		AccountId:         3,
+		Amount:            520,
+	}
+
+	accountMap := make(map[int64]*models.Account, 4)
+	accountMap[1] = &models.Account{
+		AccountId: 1,
+		Category:  models.ACCOUNT_CATEGORY_CHECKING_ACCOUNT,
+		Name:      "Test Account",
+		Currency:  "CNY",
+	}
+	accountMap[2] = &models.Account{
+		AccountId: 2,
+		Category:  models.ACCOUNT_CATEGORY_CREDIT_CARD,
+		Name:      "Test Credit Card",
+		Currency:  "CNY",
+	}
+	accountMap[3] = &models.Account{
+		AccountId:       3,
+		Category:        models.ACCOUNT_CATEGORY_CASH,
+		ParentAccountId: 4,
+		Name:            "USD Cash",
+		Currency:        "USD",
+	}
+	accountMap[4] = &models.Account{
+		AccountId: 4,
+		Category:  models.ACCOUNT_CATEGORY_CASH,
+		Name:      "Test Parent Account",
+		Currency:  validators.ParentAccountCurrencyPlaceholder,
+	}
+
+	categoryMap := make(map[int64]*models.TransactionCategory, 3)
+	categoryMap[11] = &models.TransactionCategory{
+		CategoryId:       11,
+		Name:             "Test Category",
+		ParentCategoryId: models.LevelOneTransactionCategoryParentId,
+	}
+	categoryMap[12] = &models.TransactionCategory{
+		CategoryId:       12,
+		Name:             "Test Sub Category",
+		ParentCategoryId: 11,
+	}
+	categoryMap[13] = &models.TransactionCategory{
+		CategoryId:       13,
+		Name:             "Test Category2",
+		ParentCategoryId: models.LevelOneTransactionCategoryParentId,
+	}
+
+	return transactions, accountMap, categoryMap
+}
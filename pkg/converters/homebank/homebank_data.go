@@ -0,0 +1,94 @@
+package homebank
+
+import "encoding/xml"
+
+const homebankInternalTransferPaymentMode = 5
+const homebankVoidTransactionStatus = 4
+const homebankSplitSeparator = "||"
+
+// homebankFile represents the struct of homebank xml data file
+type homebankFile struct {
+	XMLName      xml.Name                   `xml:"homebank"`
+	Version      string                     `xml:"v,attr"`
+	Currencies   []*homebankCurrencyData    `xml:"cur"`
+	Accounts     []*homebankAccountData     `xml:"account"`
+	Payees       []*homebankPayeeData       `xml:"pay"`
+	Categories   []*homebankCategoryData    `xml:"cat"`
+	Tags         []*homebankTagData         `xml:"tag"`
+	Transactions []*homebankTransactionData `xml:"ope"`
+}
+
+// homebankCurrencyData represents the struct of homebank currency data
+type homebankCurrencyData struct {
+	Key     string `xml:"key,attr"`
+	IsoCode string `xml:"iso,attr"`
+	Name    string `xml:"name,attr"`
+}
+
+// homebankAccountData represents the struct of homebank account data
+type homebankAccountData struct {
+	Key         string `xml:"key,attr"`
+	Name        string `xml:"name,attr"`
+	AccountType string `xml:"type,attr"`
+	CurrencyKey string `xml:"curr,attr"`
+}
+
+// homebankPayeeData represents the struct of homebank payee data
+type homebankPayeeData struct {
+	Key  string `xml:"key,attr"`
+	Name string `xml:"name,attr"`
+}
+
+// homebankCategoryData represents the struct of homebank category data
+type homebankCategoryData struct {
+	Key       string `xml:"key,attr"`
+	ParentKey string `xml:"parent,attr"`
+	Flags     int    `xml:"flags,attr"`
+	Name      string `xml:"name,attr"`
+}
+
+// homebankTagData represents the struct of homebank tag data
+type homebankTagData struct {
+	Key  string `xml:"key,attr"`
+	Name string `xml:"name,attr"`
+}
+
+// homebankTransactionData represents the struct of homebank transaction data
+type homebankTransactionData struct {
+	Date                  string `xml:"date,attr"`
+	Amount                string `xml:"amount,attr"`
+	AccountKey            string `xml:"account,attr"`
+	DestinationAccountKey string `xml:"dst_account,attr"`
+	PaymentMode           int    `xml:"paymode,attr"`
+	Status                int    `xml:"st,attr"`
+	Flags                 int    `xml:"flags,attr"`
+	PayeeKey              string `xml:"payee,attr"`
+	CategoryKey           string `xml:"category,attr"`
+	Wording               string `xml:"wording,attr"`
+	Memo                  string `xml:"memo,attr"`
+	Info                  string `xml:"info,attr"`
+	Tags                  string `xml:"tags,attr"`
+	TransferKey           string `xml:"kxfer,attr"`
+	SplitCategoryKeys     string `xml:"scat,attr"`
+	SplitAmounts          string `xml:"samt,attr"`
+	SplitMemos            string `xml:"smem,attr"`
+}
+
+// GetMemo returns the memo of transaction, older versions of homebank save it in the "wording" attribute
+func (t *homebankTransactionData) GetMemo() string {
+	if t.Memo != "" {
+		return t.Memo
+	}
+
+	return t.Wording
+}
+
+// IsTransfer returns whether the transaction is an internal transfer
+func (t *homebankTransactionData) IsTransfer() bool {
+	return t.PaymentMode == homebankInternalTransferPaymentMode && t.DestinationAccountKey != "" && t.DestinationAccountKey != "0"
+}
+
+// IsSplit returns whether the transaction is split into several categories
+func (t *homebankTransactionData) IsSplit() bool {
+	return t.SplitCategoryKeys != "" || t.SplitAmounts != ""
+}
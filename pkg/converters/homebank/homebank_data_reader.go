@@ -0,0 +1,46 @@
+package homebank
+
+import (
+	"bytes"
+	"encoding/xml"
+
+	"golang.org/x/net/html/charset"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+)
+
+// homebankDataReader defines the structure of homebank data reader
+type homebankDataReader struct {
+	xmlDecoder *xml.Decoder
+}
+
+// read returns the imported homebank data
+func (r *homebankDataReader) read(ctx core.Context) (*homebankFile, error) {
+	file := &homebankFile{}
+
+	err := r.xmlDecoder.Decode(&file)
+
+	if err != nil {
+		log.Errorf(ctx, "[homebank_data_reader.read] cannot decode homebank data file, because %s", err.Error())
+		return nil, errs.ErrInvalidHomeBankFile
+	}
+
+	return file, nil
+}
+
+func createNewHomeBankDataReader(data []byte) (*homebankDataReader, error) {
+	data = bytes.TrimLeft(data, "\xEF\xBB\xBF \t\r\n")
+
+	if !bytes.HasPrefix(data, []byte("<?xml")) && !bytes.HasPrefix(data, []byte("<homebank")) {
+		return nil, errs.ErrInvalidHomeBankFile
+	}
+
+	xmlDecoder := xml.NewDecoder(bytes.NewReader(data))
+	xmlDecoder.CharsetReader = charset.NewReaderLabel
+
+	return &homebankDataReader{
+		xmlDecoder: xmlDecoder,
+	}, nil
+}
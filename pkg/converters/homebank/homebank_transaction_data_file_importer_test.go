@@ -0,0 +1,245 @@
+package homebank
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/converter"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+func TestHomeBankTransactionDataImporterParseImportedData_DataFile(t *testing.T) {
+	importer := HomeBankTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	testdata, err := os.ReadFile("../../../testdata/homebank_test_file.xhb")
+	assert.Nil(t, err)
+
+	allNewTransactions, allNewAccounts, allNewSubExpenseCategories, allNewSubIncomeCategories, allNewSubTransferCategories, allNewTags, err := importer.ParseImportedData(context, user, testdata, time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 7, len(allNewTransactions))
+	assert.Equal(t, 3, len(allNewAccounts))
+	assert.Equal(t, 2, len(allNewSubExpenseCategories))
+	assert.Equal(t, 1, len(allNewSubIncomeCategories))
+	assert.Equal(t, 1, len(allNewSubTransferCategories))
+	assert.Equal(t, 2, len(allNewTags))
+
+	assert.Equal(t, int64(1234567890), allNewTransactions[0].Uid)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[0].Type)
+	assert.Equal(t, "2024-09-02 00:00:00", utils.FormatUnixTimeToLongDateTime(utils.GetUnixTimeFromTransactionTime(allNewTransactions[0].TransactionTime), time.UTC))
+	assert.Equal(t, int64(1234), allNewTransactions[0].Amount)
+	assert.Equal(t, "Checking", allNewTransactions[0].OriginalSourceAccountName)
+	assert.Equal(t, "Groceries", allNewTransactions[0].OriginalCategoryName)
+	assert.Equal(t, []string{"family", "weekly"}, allNewTransactions[0].OriginalTagNames)
+	assert.Equal(t, "Weekly shopping", allNewTransactions[0].Comment)
+
+	assert.Equal(t, int64(1234567890), allNewTransactions[1].Uid)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, allNewTransactions[1].Type)
+	assert.Equal(t, "2024-09-03 00:00:00", utils.FormatUnixTimeToLongDateTime(utils.GetUnixTimeFromTransactionTime(allNewTransactions[1].TransactionTime), time.UTC))
+	assert.Equal(t, int64(250000), allNewTransactions[1].Amount)
+	assert.Equal(t, "Checking", allNewTransactions[1].OriginalSourceAccountName)
+	assert.Equal(t, "Salary", allNewTransactions[1].OriginalCategoryName)
+	assert.Equal(t, "Salary", allNewTransactions[1].Comment)
+
+	assert.Equal(t, int64(1234567890), allNewTransactions[2].Uid)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[2].Type)
+	assert.Equal(t, "2024-09-04 00:00:00", utils.FormatUnixTimeToLongDateTime(utils.GetUnixTimeFromTransactionTime(allNewTransactions[2].TransactionTime), time.UTC))
+	assert.Equal(t, int64(50000), allNewTransactions[2].Amount)
+	assert.Equal(t, int64(50000), allNewTransactions[2].RelatedAccountAmount)
+	assert.Equal(t, "Checking", allNewTransactions[2].OriginalSourceAccountName)
+	assert.Equal(t, "Savings", allNewTransactions[2].OriginalDestinationAccountName)
+	assert.Equal(t, "Move to savings", allNewTransactions[2].Comment)
+
+	assert.Equal(t, int64(1234567890), allNewTransactions[3].Uid)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[3].Type)
+	assert.Equal(t, "2024-09-05 00:00:00", utils.FormatUnixTimeToLongDateTime(utils.GetUnixTimeFromTransactionTime(allNewTransactions[3].TransactionTime), time.UTC))
+	assert.Equal(t, int64(2050), allNewTransactions[3].Amount)
+	assert.Equal(t, "Checking", allNewTransactions[3].OriginalSourceAccountName)
+	assert.Equal(t, "Cleaning", allNewTransactions[3].OriginalCategoryName)
+	assert.Equal(t, "Soap", allNewTransactions[3].Comment)
+
+	assert.Equal(t, int64(1234567890), allNewTransactions[4].Uid)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[4].Type)
+	assert.Equal(t, "2024-09-05 00:00:00", utils.FormatUnixTimeToLongDateTime(utils.GetUnixTimeFromTransactionTime(allNewTransactions[4].TransactionTime), time.UTC))
+	assert.Equal(t, int64(3000), allNewTransactions[4].Amount)
+	assert.Equal(t, "Checking", allNewTransactions[4].OriginalSourceAccountName)
+	assert.Equal(t, "Groceries", allNewTransactions[4].OriginalCategoryName)
+	assert.Equal(t, "Fruit", allNewTransactions[4].Comment)
+
+	assert.Equal(t, int64(1234567890), allNewTransactions[5].Uid)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[5].Type)
+	assert.Equal(t, "2024-09-06 00:00:00", utils.FormatUnixTimeToLongDateTime(utils.GetUnixTimeFromTransactionTime(allNewTransactions[5].TransactionTime), time.UTC))
+	assert.Equal(t, int64(10000), allNewTransactions[5].Amount)
+	assert.Equal(t, int64(9250), allNewTransactions[5].RelatedAccountAmount)
+	assert.Equal(t, "Checking", allNewTransactions[5].OriginalSourceAccountName)
+	assert.Equal(t, "Euro Wallet", allNewTransactions[5].OriginalDestinationAccountName)
+	assert.Equal(t, "Travel money", allNewTransactions[5].Comment)
+
+	assert.Equal(t, int64(1234567890), allNewTransactions[6].Uid)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[6].Type)
+	assert.Equal(t, "2024-09-08 00:00:00", utils.FormatUnixTimeToLongDateTime(utils.GetUnixTimeFromTransactionTime(allNewTransactions[6].TransactionTime), time.UTC))
+	assert.Equal(t, int64(1500), allNewTransactions[6].Amount)
+	assert.Equal(t, int64(1500), allNewTransactions[6].RelatedAccountAmount)
+	assert.Equal(t, "Euro Wallet", allNewTransactions[6].OriginalSourceAccountName)
+	assert.Equal(t, "Savings", allNewTransactions[6].OriginalDestinationAccountName)
+	assert.Equal(t, "Change back", allNewTransactions[6].Comment)
+
+	assert.Equal(t, int64(1234567890), allNewAccounts[0].Uid)
+	assert.Equal(t, "Checking", allNewAccounts[0].Name)
+	assert.Equal(t, "USD", allNewAccounts[0].Currency)
+
+	assert.Equal(t, int64(1234567890), allNewAccounts[1].Uid)
+	assert.Equal(t, "Savings", allNewAccounts[1].Name)
+	assert.Equal(t, "USD", allNewAccounts[1].Currency)
+
+	assert.Equal(t, int64(1234567890), allNewAccounts[2].Uid)
+	assert.Equal(t, "Euro Wallet", allNewAccounts[2].Name)
+	assert.Equal(t, "EUR", allNewAccounts[2].Currency)
+
+	assert.Equal(t, "family", allNewTags[0].Name)
+	assert.Equal(t, "weekly", allNewTags[1].Name)
+}
+
+func TestHomeBankTransactionDataImporterParseImportedData_ParentCategory(t *testing.T) {
+	importer := HomeBankTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "USD",
+	}
+
+	testdata, err := os.ReadFile("../../../testdata/homebank_test_file.xhb")
+	assert.Nil(t, err)
+
+	expenseCategoryMap := map[string]map[string]*models.TransactionCategory{
+		"Groceries": {
+			"Shopping": &models.TransactionCategory{
+				CategoryId: 1001,
+				Name:       "Groceries",
+			},
+			"Food": &models.TransactionCategory{
+				CategoryId: 1002,
+				Name:       "Groceries",
+			},
+		},
+	}
+
+	allNewTransactions, _, allNewSubExpenseCategories, _, _, _, err := importer.ParseImportedData(context, user, testdata, time.UTC, converter.DefaultImporterOptions, nil, expenseCategoryMap, nil, nil, nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 1, len(allNewSubExpenseCategories))
+	assert.Equal(t, "Cleaning", allNewSubExpenseCategories[0].Name)
+	assert.Equal(t, int64(1002), allNewTransactions[0].CategoryId)
+	assert.Equal(t, int64(1002), allNewTransactions[4].CategoryId)
+}
+
+func TestHomeBankTransactionDataImporterParseImportedData_PayeeAsDescription(t *testing.T) {
+	importer := HomeBankTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "USD",
+	}
+
+	data := "<?xml version=\"1.0\"?>\n" +
+		"<homebank v=\"1.4\" d=\"050504\">\n" +
+		"<account key=\"1\" pos=\"1\" type=\"1\" name=\"Checking\"/>\n" +
+		"<pay key=\"1\" name=\"Supermarket\"/>\n" +
+		"<ope date=\"739131\" amount=\"-1.5\" account=\"1\" paymode=\"0\" payee=\"1\"/>\n" +
+		"<ope date=\"739132\" amount=\"-2\" account=\"1\" paymode=\"0\" payee=\"1\" memo=\"Milk\"/>\n" +
+		"</homebank>\n"
+
+	allNewTransactions, _, _, _, _, allNewTags, err := importer.ParseImportedData(context, user, []byte(data), time.UTC, converter.DefaultImporterOptions.WithPayeeAsDescription(), nil, nil, nil, nil, nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 2, len(allNewTransactions))
+	assert.Equal(t, 0, len(allNewTags))
+	assert.Equal(t, int64(150), allNewTransactions[0].Amount)
+	assert.Equal(t, "Supermarket", allNewTransactions[0].Comment)
+	assert.Equal(t, int64(200), allNewTransactions[1].Amount)
+	assert.Equal(t, "Milk", allNewTransactions[1].Comment)
+}
+
+func TestHomeBankTransactionDataImporterParseImportedData_InvalidFile(t *testing.T) {
+	importer := HomeBankTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "USD",
+	}
+
+	_, _, _, _, _, _, err := importer.ParseImportedData(context, user, []byte("not a homebank file"), time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrInvalidHomeBankFile.Message)
+
+	_, _, _, _, _, _, err = importer.ParseImportedData(context, user, []byte("<?xml version=\"1.0\"?>\n<gnc-v2></gnc-v2>"), time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrInvalidHomeBankFile.Message)
+
+	_, _, _, _, _, _, err = importer.ParseImportedData(context, user, []byte("<?xml version=\"1.0\"?>\n<homebank v=\"1.4\"></homebank>"), time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrNotFoundTransactionDataInFile.Message)
+}
+
+func TestHomeBankTransactionDataImporterParseImportedData_MissingAccount(t *testing.T) {
+	importer := HomeBankTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "USD",
+	}
+
+	data := "<?xml version=\"1.0\"?>\n" +
+		"<homebank v=\"1.4\" d=\"050504\">\n" +
+		"<ope date=\"739131\" amount=\"-1.5\" account=\"1\" paymode=\"0\"/>\n" +
+		"</homebank>\n"
+
+	_, _, _, _, _, _, err := importer.ParseImportedData(context, user, []byte(data), time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrMissingAccountData.Message)
+}
+
+func TestParseHomeBankTransactionTime(t *testing.T) {
+	actualValue, err := parseHomeBankTransactionTime("1")
+	assert.Nil(t, err)
+	assert.Equal(t, "0001-01-01 00:00:00", actualValue)
+
+	actualValue, err = parseHomeBankTransactionTime("739130")
+	assert.Nil(t, err)
+	assert.Equal(t, "2024-09-01 00:00:00", actualValue)
+
+	_, err = parseHomeBankTransactionTime("0")
+	assert.EqualError(t, err, errs.ErrTransactionTimeInvalid.Message)
+
+	_, err = parseHomeBankTransactionTime("2024-09-01")
+	assert.EqualError(t, err, errs.ErrTransactionTimeInvalid.Message)
+}
+
+func TestParseHomeBankAmount(t *testing.T) {
+	actualValue, err := parseHomeBankAmount("-12.34")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(-1234), actualValue)
+
+	actualValue, err = parseHomeBankAmount("0.29999999999999999")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(30), actualValue)
+
+	actualValue, err = parseHomeBankAmount("2500")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(250000), actualValue)
+
+	_, err = parseHomeBankAmount("12,34")
+	assert.EqualError(t, err, errs.ErrAmountInvalid.Message)
+}
@@ -0,0 +1,392 @@
+package homebank
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/datatable"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const homebankTransactionTagSeparator = " "
+
+var homebankJulianDayBaseDate = time.Date(1, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+var homebankTransactionSupportedColumns = map[datatable.TransactionDataTableColumn]bool{
+	datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIME:         true,
+	datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE:         true,
+	datatable.TRANSACTION_DATA_TABLE_CATEGORY:                 true,
+	datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY:             true,
+	datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME:             true,
+	datatable.TRANSACTION_DATA_TABLE_ACCOUNT_CURRENCY:         true,
+	datatable.TRANSACTION_DATA_TABLE_AMOUNT:                   true,
+	datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME:     true,
+	datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_CURRENCY: true,
+	datatable.TRANSACTION_DATA_TABLE_RELATED_AMOUNT:           true,
+	datatable.TRANSACTION_DATA_TABLE_TAGS:                     true,
+	datatable.TRANSACTION_DATA_TABLE_DESCRIPTION:              true,
+	datatable.TRANSACTION_DATA_TABLE_PAYEE:                    true,
+}
+
+// homebankTransactionDataTable defines the structure of homebank transaction data table
+type homebankTransactionDataTable struct {
+	allData        []*homebankTransactionData
+	currencyMap    map[string]*homebankCurrencyData
+	accountMap     map[string]*homebankAccountData
+	payeeMap       map[string]*homebankPayeeData
+	categoryMap    map[string]*homebankCategoryData
+	transferKeyMap map[string][]*homebankTransactionData
+}
+
+// homebankTransactionDataRow defines the structure of homebank transaction data row
+type homebankTransactionDataRow struct {
+	dataTable  *homebankTransactionDataTable
+	data       *homebankTransactionData
+	finalItems map[datatable.TransactionDataTableColumn]string
+	isValid    bool
+}
+
+// homebankTransactionDataRowIterator defines the structure of homebank transaction data row iterator
+type homebankTransactionDataRowIterator struct {
+	dataTable    *homebankTransactionDataTable
+	currentIndex int
+}
+
+// HasColumn returns whether the transaction data table has specified column
+func (t *homebankTransactionDataTable) HasColumn(column datatable.TransactionDataTableColumn) bool {
+	_, exists := homebankTransactionSupportedColumns[column]
+	return exists
+}
+
+// TransactionRowCount returns the total count of transaction data row
+func (t *homebankTransactionDataTable) TransactionRowCount() int {
+	return len(t.allData)
+}
+
+// TransactionRowIterator returns the iterator of transaction data row
+func (t *homebankTransactionDataTable) TransactionRowIterator() datatable.TransactionDataRowIterator {
+	return &homebankTransactionDataRowIterator{
+		dataTable:    t,
+		currentIndex: -1,
+	}
+}
+
+// IsValid returns whether this row is valid data for importing
+func (r *homebankTransactionDataRow) IsValid() bool {
+	return r.isValid
+}
+
+// GetData returns the data in the specified column type
+func (r *homebankTransactionDataRow) GetData(column datatable.TransactionDataTableColumn) string {
+	_, exists := homebankTransactionSupportedColumns[column]
+
+	if exists {
+		return r.finalItems[column]
+	}
+
+	return ""
+}
+
+// HasNext returns whether the iterator does not reach the end
+func (t *homebankTransactionDataRowIterator) HasNext() bool {
+	return t.currentIndex+1 < len(t.dataTable.allData)
+}
+
+// Next returns the next transaction data row
+func (t *homebankTransactionDataRowIterator) Next(ctx core.Context, user *models.User) (daraRow datatable.TransactionDataRow, err error) {
+	if t.currentIndex+1 >= len(t.dataTable.allData) {
+		return nil, nil
+	}
+
+	t.currentIndex++
+
+	data := t.dataTable.allData[t.currentIndex]
+	rowItems, isValid, err := t.parseTransaction(ctx, user, data)
+
+	if err != nil {
+		log.Errorf(ctx, "[homebank_transaction_data_table.Next] cannot parsing transaction in row#%d, because %s", t.currentIndex, err.Error())
+		return nil, err
+	}
+
+	return &homebankTransactionDataRow{
+		dataTable:  t.dataTable,
+		data:       data,
+		finalItems: rowItems,
+		isValid:    isValid,
+	}, nil
+}
+
+func (t *homebankTransactionDataRowIterator) parseTransaction(ctx core.Context, user *models.User, homebankTransaction *homebankTransactionData) (map[datatable.TransactionDataTableColumn]string, bool, error) {
+	data := make(map[datatable.TransactionDataTableColumn]string, len(homebankTransactionSupportedColumns))
+
+	if homebankTransaction.Date == "" {
+		return nil, false, errs.ErrMissingTransactionTime
+	}
+
+	transactionTime, err := parseHomeBankTransactionTime(homebankTransaction.Date)
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIME] = transactionTime
+
+	amount, err := parseHomeBankAmount(homebankTransaction.Amount)
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	if amount == 0 {
+		log.Warnf(ctx, "[homebank_transaction_data_table.parseTransaction] skip parsing transaction with zero amount in account \"key:%s\" at \"%s\"", homebankTransaction.AccountKey, transactionTime)
+		return nil, false, nil
+	}
+
+	account := t.dataTable.accountMap[homebankTransaction.AccountKey]
+
+	if account == nil {
+		return nil, false, errs.ErrMissingAccountData
+	}
+
+	if homebankTransaction.IsTransfer() {
+		destinationAccount := t.dataTable.accountMap[homebankTransaction.DestinationAccountKey]
+
+		if destinationAccount == nil {
+			return nil, false, errs.ErrMissingAccountData
+		}
+
+		relatedAmount := -amount
+
+		if counterpart := t.dataTable.getTransferCounterpart(homebankTransaction); counterpart != nil {
+			counterpartAmount, err := parseHomeBankAmount(counterpart.Amount)
+
+			if err != nil {
+				return nil, false, err
+			}
+
+			relatedAmount = counterpartAmount
+		}
+
+		fromAccount := account
+		toAccount := destinationAccount
+
+		if amount > 0 { // transfer from the destination account, only exists when the other side is not in the file
+			fromAccount = destinationAccount
+			toAccount = account
+			amount = -amount
+			relatedAmount = -relatedAmount
+		}
+
+		data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = homebankTransactionTypeNameMapping[models.TRANSACTION_TYPE_TRANSFER]
+		data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME] = fromAccount.Name
+		data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_CURRENCY] = t.dataTable.getAccountCurrency(fromAccount)
+		data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(-amount)
+		data[datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME] = toAccount.Name
+		data[datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_CURRENCY] = t.dataTable.getAccountCurrency(toAccount)
+		data[datatable.TRANSACTION_DATA_TABLE_RELATED_AMOUNT] = utils.FormatAmount(relatedAmount)
+	} else {
+		if amount > 0 {
+			data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = homebankTransactionTypeNameMapping[models.TRANSACTION_TYPE_INCOME]
+			data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(amount)
+		} else {
+			data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = homebankTransactionTypeNameMapping[models.TRANSACTION_TYPE_EXPENSE]
+			data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(-amount)
+		}
+
+		if category := t.dataTable.categoryMap[homebankTransaction.CategoryKey]; category != nil {
+			if parentCategory := t.dataTable.categoryMap[category.ParentKey]; parentCategory != nil {
+				data[datatable.TRANSACTION_DATA_TABLE_CATEGORY] = parentCategory.Name
+			}
+
+			data[datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY] = category.Name
+		}
+
+		data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME] = account.Name
+		data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_CURRENCY] = t.dataTable.getAccountCurrency(account)
+	}
+
+	if payee := t.dataTable.payeeMap[homebankTransaction.PayeeKey]; payee != nil {
+		data[datatable.TRANSACTION_DATA_TABLE_PAYEE] = payee.Name
+	}
+
+	data[datatable.TRANSACTION_DATA_TABLE_TAGS] = strings.Join(strings.Fields(homebankTransaction.Tags), homebankTransactionTagSeparator)
+	data[datatable.TRANSACTION_DATA_TABLE_DESCRIPTION] = homebankTransaction.GetMemo()
+
+	return data, true, nil
+}
+
+func (t *homebankTransactionDataTable) getAccountCurrency(account *homebankAccountData) string {
+	if currency := t.currencyMap[account.CurrencyKey]; currency != nil {
+		return currency.IsoCode
+	}
+
+	return ""
+}
+
+func (t *homebankTransactionDataTable) getTransferCounterpart(transaction *homebankTransactionData) *homebankTransactionData {
+	if transaction.TransferKey == "" || transaction.TransferKey == "0" {
+		return nil
+	}
+
+	transactions := t.transferKeyMap[transaction.TransferKey]
+
+	for i := 0; i < len(transactions); i++ {
+		if transactions[i] != transaction && transactions[i].AccountKey == transaction.DestinationAccountKey {
+			return transactions[i]
+		}
+	}
+
+	return nil
+}
+
+// parseHomeBankTransactionTime returns the long date time of the julian day number (the days since 0001-01-01, which is day 1) which is used in homebank
+func parseHomeBankTransactionTime(date string) (string, error) {
+	julianDay, err := utils.StringToInt(date)
+
+	if err != nil || julianDay < 1 {
+		return "", errs.ErrTransactionTimeInvalid
+	}
+
+	transactionTime := homebankJulianDayBaseDate.AddDate(0, 0, julianDay-1)
+
+	return transactionTime.Format("2006-01-02") + " 00:00:00", nil
+}
+
+// parseHomeBankAmount returns the amount in cents of the decimal number which is used in homebank
+func parseHomeBankAmount(amount string) (int64, error) {
+	if amount == "" {
+		return 0, nil
+	}
+
+	value, err := utils.StringToFloat64(amount)
+
+	if err != nil {
+		return 0, errs.ErrAmountInvalid
+	}
+
+	return int64(math.Round(value * 100)), nil
+}
+
+// getHomeBankImportedTransactions returns the transactions which should be imported, the split transactions are expanded into one transaction per split,
+// and only one side of each transfer is kept (the outflow side is preferred)
+func getHomeBankImportedTransactions(ctx core.Context, transactions []*homebankTransactionData, transferKeyMap map[string][]*homebankTransactionData) []*homebankTransactionData {
+	result := make([]*homebankTransactionData, 0, len(transactions))
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+
+		if transaction.Status == homebankVoidTransactionStatus {
+			log.Warnf(ctx, "[homebank_transaction_data_table.getHomeBankImportedTransactions] skip void transaction in account \"key:%s\" at \"%s\"", transaction.AccountKey, transaction.Date)
+			continue
+		}
+
+		if transaction.IsTransfer() {
+			if amount, err := parseHomeBankAmount(transaction.Amount); err == nil && amount > 0 && hasHomeBankTransferOutflowSide(transaction, transferKeyMap) {
+				continue
+			}
+
+			result = append(result, transaction)
+			continue
+		}
+
+		if !transaction.IsSplit() {
+			result = append(result, transaction)
+			continue
+		}
+
+		splitCategoryKeys := strings.Split(transaction.SplitCategoryKeys, homebankSplitSeparator)
+		splitAmounts := strings.Split(transaction.SplitAmounts, homebankSplitSeparator)
+		splitMemos := strings.Split(transaction.SplitMemos, homebankSplitSeparator)
+
+		for j := 0; j < len(splitAmounts); j++ {
+			splitTransaction := *transaction
+			splitTransaction.Amount = splitAmounts[j]
+			splitTransaction.CategoryKey = ""
+			splitTransaction.SplitCategoryKeys = ""
+			splitTransaction.SplitAmounts = ""
+			splitTransaction.SplitMemos = ""
+
+			if j < len(splitCategoryKeys) {
+				splitTransaction.CategoryKey = splitCategoryKeys[j]
+			}
+
+			if j < len(splitMemos) && splitMemos[j] != "" {
+				splitTransaction.Memo = splitMemos[j]
+			}
+
+			result = append(result, &splitTransaction)
+		}
+	}
+
+	return result
+}
+
+func hasHomeBankTransferOutflowSide(transaction *homebankTransactionData, transferKeyMap map[string][]*homebankTransactionData) bool {
+	if transaction.TransferKey == "" || transaction.TransferKey == "0" {
+		return false
+	}
+
+	transactions := transferKeyMap[transaction.TransferKey]
+
+	for i := 0; i < len(transactions); i++ {
+		if transactions[i] == transaction || transactions[i].AccountKey != transaction.DestinationAccountKey {
+			continue
+		}
+
+		if amount, err := parseHomeBankAmount(transactions[i].Amount); err == nil && amount < 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+func createNewHomeBankTransactionDataTable(ctx core.Context, file *homebankFile) (*homebankTransactionDataTable, error) {
+	if file == nil || len(file.Transactions) < 1 {
+		return nil, errs.ErrNotFoundTransactionDataInFile
+	}
+
+	currencyMap := make(map[string]*homebankCurrencyData, len(file.Currencies))
+	accountMap := make(map[string]*homebankAccountData, len(file.Accounts))
+	payeeMap := make(map[string]*homebankPayeeData, len(file.Payees))
+	categoryMap := make(map[string]*homebankCategoryData, len(file.Categories))
+	transferKeyMap := make(map[string][]*homebankTransactionData)
+
+	for i := 0; i < len(file.Currencies); i++ {
+		currencyMap[file.Currencies[i].Key] = file.Currencies[i]
+	}
+
+	for i := 0; i < len(file.Accounts); i++ {
+		accountMap[file.Accounts[i].Key] = file.Accounts[i]
+	}
+
+	for i := 0; i < len(file.Payees); i++ {
+		payeeMap[file.Payees[i].Key] = file.Payees[i]
+	}
+
+	for i := 0; i < len(file.Categories); i++ {
+		categoryMap[file.Categories[i].Key] = file.Categories[i]
+	}
+
+	for i := 0; i < len(file.Transactions); i++ {
+		transaction := file.Transactions[i]
+
+		if transaction.IsTransfer() && transaction.TransferKey != "" && transaction.TransferKey != "0" {
+			transferKeyMap[transaction.TransferKey] = append(transferKeyMap[transaction.TransferKey], transaction)
+		}
+	}
+
+	return &homebankTransactionDataTable{
+		allData:        getHomeBankImportedTransactions(ctx, file.Transactions, transferKeyMap),
+		currencyMap:    currencyMap,
+		accountMap:     accountMap,
+		payeeMap:       payeeMap,
+		categoryMap:    categoryMap,
+		transferKeyMap: transferKeyMap,
+	}, nil
+}
@@ -0,0 +1,124 @@
+package kmymoney
+
+import "encoding/xml"
+
+const kmymoneyAssetStandardAccountId = "AStd::Asset"
+const kmymoneyLiabilityStandardAccountId = "AStd::Liability"
+const kmymoneyExpenseStandardAccountId = "AStd::Expense"
+const kmymoneyIncomeStandardAccountId = "AStd::Income"
+const kmymoneyEquityStandardAccountId = "AStd::Equity"
+
+const kmymoneyCheckingAccountType = "1"
+const kmymoneySavingsAccountType = "2"
+const kmymoneyCashAccountType = "3"
+const kmymoneyCreditCardAccountType = "4"
+const kmymoneyLoanAccountType = "5"
+const kmymoneyCertificateDepositAccountType = "6"
+const kmymoneyMoneyMarketAccountType = "8"
+const kmymoneyAssetAccountType = "9"
+const kmymoneyLiabilityAccountType = "10"
+const kmymoneyIncomeAccountType = "12"
+const kmymoneyExpenseAccountType = "13"
+const kmymoneyAssetLoanAccountType = "14"
+const kmymoneyEquityAccountType = "16"
+
+const kmymoneyOpeningBalanceAccountKey = "OpeningBalanceAccount"
+const kmymoneyOpeningBalanceAccountValue = "Yes"
+
+var kmymoneyAssetOrLiabilityAccountTypes = map[string]bool{
+	kmymoneyCheckingAccountType:           true,
+	kmymoneySavingsAccountType:            true,
+	kmymoneyCashAccountType:               true,
+	kmymoneyCreditCardAccountType:         true,
+	kmymoneyLoanAccountType:               true,
+	kmymoneyCertificateDepositAccountType: true,
+	kmymoneyMoneyMarketAccountType:        true,
+	kmymoneyAssetAccountType:              true,
+	kmymoneyLiabilityAccountType:          true,
+	kmymoneyAssetLoanAccountType:          true,
+}
+
+var kmymoneyStandardAccountIds = map[string]bool{
+	kmymoneyAssetStandardAccountId:     true,
+	kmymoneyLiabilityStandardAccountId: true,
+	kmymoneyExpenseStandardAccountId:   true,
+	kmymoneyIncomeStandardAccountId:    true,
+	kmymoneyEquityStandardAccountId:    true,
+}
+
+// kmymoneyFile represents the struct of kmymoney data file
+type kmymoneyFile struct {
+	XMLName      xml.Name                   `xml:"KMYMONEY-FILE"`
+	Payees       []*kmymoneyPayeeData       `xml:"PAYEES>PAYEE"`
+	Tags         []*kmymoneyTagData         `xml:"TAGS>TAG"`
+	Accounts     []*kmymoneyAccountData     `xml:"ACCOUNTS>ACCOUNT"`
+	Transactions []*kmymoneyTransactionData `xml:"TRANSACTIONS>TRANSACTION"`
+}
+
+// kmymoneyPayeeData represents the struct of kmymoney payee data
+type kmymoneyPayeeData struct {
+	Id   string `xml:"id,attr"`
+	Name string `xml:"name,attr"`
+}
+
+// kmymoneyTagData represents the struct of kmymoney tag data
+type kmymoneyTagData struct {
+	Id   string `xml:"id,attr"`
+	Name string `xml:"name,attr"`
+}
+
+// kmymoneyKeyValuePairData represents the struct of kmymoney key value pair data
+type kmymoneyKeyValuePairData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// kmymoneyAccountData represents the struct of kmymoney account data
+type kmymoneyAccountData struct {
+	Id            string                      `xml:"id,attr"`
+	Name          string                      `xml:"name,attr"`
+	AccountType   string                      `xml:"type,attr"`
+	ParentId      string                      `xml:"parentaccount,attr"`
+	Currency      string                      `xml:"currency,attr"`
+	KeyValuePairs []*kmymoneyKeyValuePairData `xml:"KEYVALUEPAIRS>PAIR"`
+}
+
+// kmymoneyTransactionData represents the struct of kmymoney transaction data
+type kmymoneyTransactionData struct {
+	Id       string               `xml:"id,attr"`
+	PostDate string               `xml:"postdate,attr"`
+	Memo     string               `xml:"memo,attr"`
+	Currency string               `xml:"commodity,attr"`
+	Splits   []*kmymoneySplitData `xml:"SPLITS>SPLIT"`
+}
+
+// kmymoneySplitData represents the struct of kmymoney split data
+type kmymoneySplitData struct {
+	Id        string                  `xml:"id,attr"`
+	PayeeId   string                  `xml:"payee,attr"`
+	Value     string                  `xml:"value,attr"`
+	Shares    string                  `xml:"shares,attr"`
+	Memo      string                  `xml:"memo,attr"`
+	AccountId string                  `xml:"account,attr"`
+	Tags      []*kmymoneySplitTagData `xml:"TAG"`
+}
+
+// kmymoneySplitTagData represents the struct of kmymoney tag reference in split
+type kmymoneySplitTagData struct {
+	Id string `xml:"id,attr"`
+}
+
+// IsOpeningBalanceAccount returns whether the account is the equity account for opening balances
+func (a *kmymoneyAccountData) IsOpeningBalanceAccount() bool {
+	if a.AccountType != kmymoneyEquityAccountType {
+		return false
+	}
+
+	for i := 0; i < len(a.KeyValuePairs); i++ {
+		if a.KeyValuePairs[i].Key == kmymoneyOpeningBalanceAccountKey && a.KeyValuePairs[i].Value == kmymoneyOpeningBalanceAccountValue {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,59 @@
+package kmymoney
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/xml"
+
+	"golang.org/x/net/html/charset"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+)
+
+// kmymoneyDataReader defines the structure of kmymoney data reader
+type kmymoneyDataReader struct {
+	xmlDecoder *xml.Decoder
+}
+
+// read returns the imported kmymoney data
+func (r *kmymoneyDataReader) read(ctx core.Context) (*kmymoneyFile, error) {
+	file := &kmymoneyFile{}
+
+	err := r.xmlDecoder.Decode(&file)
+
+	if err != nil {
+		log.Errorf(ctx, "[kmymoney_data_reader.read] cannot decode kmymoney data file, because %s", err.Error())
+		return nil, errs.ErrInvalidKMyMoneyFile
+	}
+
+	return file, nil
+}
+
+func createNewKMyMoneyDataReader(ctx core.Context, data []byte) (*kmymoneyDataReader, error) {
+	if len(data) > 2 && data[0] == 0x1F && data[1] == 0x8B { // gzip magic number
+		gzipReader, err := gzip.NewReader(bytes.NewReader(data))
+
+		if err != nil {
+			log.Errorf(ctx, "[kmymoney_data_reader.createNewKMyMoneyDataReader] cannot open gzip file, because %s", err.Error())
+			return nil, errs.ErrInvalidKMyMoneyFile
+		}
+
+		xmlDecoder := xml.NewDecoder(gzipReader)
+		xmlDecoder.CharsetReader = charset.NewReaderLabel
+
+		return &kmymoneyDataReader{
+			xmlDecoder: xmlDecoder,
+		}, nil
+	} else if bytes.HasPrefix(data, []byte("<?xml")) || bytes.HasPrefix(data, []byte("<!DOCTYPE KMYMONEY-FILE>")) {
+		xmlDecoder := xml.NewDecoder(bytes.NewReader(data))
+		xmlDecoder.CharsetReader = charset.NewReaderLabel
+
+		return &kmymoneyDataReader{
+			xmlDecoder: xmlDecoder,
+		}, nil
+	}
+
+	return nil, errs.ErrInvalidKMyMoneyFile
+}
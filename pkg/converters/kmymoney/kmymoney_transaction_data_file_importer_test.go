@@ -0,0 +1,251 @@
+package kmymoney
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/converter"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+func TestKMyMoneyTransactionDataImporterParseImportedData_DataFile(t *testing.T) {
+	importer := KMyMoneyTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	testdata, err := os.ReadFile("../../../testdata/kmymoney_test_file.kmy")
+	assert.Nil(t, err)
+
+	allNewTransactions, allNewAccounts, allNewSubExpenseCategories, allNewSubIncomeCategories, allNewSubTransferCategories, allNewTags, err := importer.ParseImportedData(context, user, testdata, time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 8, len(allNewTransactions))
+	assert.Equal(t, 4, len(allNewAccounts))
+	assert.Equal(t, 2, len(allNewSubExpenseCategories))
+	assert.Equal(t, 1, len(allNewSubIncomeCategories))
+	assert.Equal(t, 1, len(allNewSubTransferCategories))
+	assert.Equal(t, 2, len(allNewTags))
+
+	assert.Equal(t, int64(1234567890), allNewTransactions[0].Uid)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_MODIFY_BALANCE, allNewTransactions[0].Type)
+	assert.Equal(t, "2024-09-01 00:00:00", utils.FormatUnixTimeToLongDateTime(utils.GetUnixTimeFromTransactionTime(allNewTransactions[0].TransactionTime), time.UTC))
+	assert.Equal(t, int64(100000), allNewTransactions[0].Amount)
+	assert.Equal(t, "Checking", allNewTransactions[0].OriginalSourceAccountName)
+
+	assert.Equal(t, int64(1234567890), allNewTransactions[1].Uid)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[1].Type)
+	assert.Equal(t, "2024-09-02 00:00:00", utils.FormatUnixTimeToLongDateTime(utils.GetUnixTimeFromTransactionTime(allNewTransactions[1].TransactionTime), time.UTC))
+	assert.Equal(t, int64(12345), allNewTransactions[1].Amount)
+	assert.Equal(t, "Checking", allNewTransactions[1].OriginalSourceAccountName)
+	assert.Equal(t, "Groceries", allNewTransactions[1].OriginalCategoryName)
+	assert.Equal(t, []string{"family", "weekly"}, allNewTransactions[1].OriginalTagNames)
+	assert.Equal(t, "Weekly shopping", allNewTransactions[1].Comment)
+
+	assert.Equal(t, int64(1234567890), allNewTransactions[2].Uid)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, allNewTransactions[2].Type)
+	assert.Equal(t, "2024-09-03 00:00:00", utils.FormatUnixTimeToLongDateTime(utils.GetUnixTimeFromTransactionTime(allNewTransactions[2].TransactionTime), time.UTC))
+	assert.Equal(t, int64(250000), allNewTransactions[2].Amount)
+	assert.Equal(t, "Checking", allNewTransactions[2].OriginalSourceAccountName)
+	assert.Equal(t, "Salary", allNewTransactions[2].OriginalCategoryName)
+	assert.Equal(t, "Salary", allNewTransactions[2].Comment)
+
+	assert.Equal(t, int64(1234567890), allNewTransactions[3].Uid)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[3].Type)
+	assert.Equal(t, "2024-09-04 00:00:00", utils.FormatUnixTimeToLongDateTime(utils.GetUnixTimeFromTransactionTime(allNewTransactions[3].TransactionTime), time.UTC))
+	assert.Equal(t, int64(50000), allNewTransactions[3].Amount)
+	assert.Equal(t, int64(50000), allNewTransactions[3].RelatedAccountAmount)
+	assert.Equal(t, "Checking", allNewTransactions[3].OriginalSourceAccountName)
+	assert.Equal(t, "Savings", allNewTransactions[3].OriginalDestinationAccountName)
+	assert.Equal(t, "Move to savings", allNewTransactions[3].Comment)
+
+	assert.Equal(t, int64(1234567890), allNewTransactions[4].Uid)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[4].Type)
+	assert.Equal(t, "2024-09-05 00:00:00", utils.FormatUnixTimeToLongDateTime(utils.GetUnixTimeFromTransactionTime(allNewTransactions[4].TransactionTime), time.UTC))
+	assert.Equal(t, int64(2050), allNewTransactions[4].Amount)
+	assert.Equal(t, "Checking", allNewTransactions[4].OriginalSourceAccountName)
+	assert.Equal(t, "Cleaning", allNewTransactions[4].OriginalCategoryName)
+	assert.Equal(t, "Soap", allNewTransactions[4].Comment)
+
+	assert.Equal(t, int64(1234567890), allNewTransactions[5].Uid)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[5].Type)
+	assert.Equal(t, "2024-09-05 00:00:00", utils.FormatUnixTimeToLongDateTime(utils.GetUnixTimeFromTransactionTime(allNewTransactions[5].TransactionTime), time.UTC))
+	assert.Equal(t, int64(3000), allNewTransactions[5].Amount)
+	assert.Equal(t, "Checking", allNewTransactions[5].OriginalSourceAccountName)
+	assert.Equal(t, "Groceries", allNewTransactions[5].OriginalCategoryName)
+	assert.Equal(t, "Fruit", allNewTransactions[5].Comment)
+
+	assert.Equal(t, int64(1234567890), allNewTransactions[6].Uid)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[6].Type)
+	assert.Equal(t, "2024-09-06 00:00:00", utils.FormatUnixTimeToLongDateTime(utils.GetUnixTimeFromTransactionTime(allNewTransactions[6].TransactionTime), time.UTC))
+	assert.Equal(t, int64(10000), allNewTransactions[6].Amount)
+	assert.Equal(t, int64(9250), allNewTransactions[6].RelatedAccountAmount)
+	assert.Equal(t, "Checking", allNewTransactions[6].OriginalSourceAccountName)
+	assert.Equal(t, "Euro Account", allNewTransactions[6].OriginalDestinationAccountName)
+	assert.Equal(t, "Travel money", allNewTransactions[6].Comment)
+
+	assert.Equal(t, int64(1234567890), allNewTransactions[7].Uid)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[7].Type)
+	assert.Equal(t, "2024-09-07 00:00:00", utils.FormatUnixTimeToLongDateTime(utils.GetUnixTimeFromTransactionTime(allNewTransactions[7].TransactionTime), time.UTC))
+	assert.Equal(t, int64(1999), allNewTransactions[7].Amount)
+	assert.Equal(t, "Credit Card", allNewTransactions[7].OriginalSourceAccountName)
+	assert.Equal(t, "Cleaning", allNewTransactions[7].OriginalCategoryName)
+	assert.Equal(t, "Detergent", allNewTransactions[7].Comment)
+
+	assert.Equal(t, int64(1234567890), allNewAccounts[0].Uid)
+	assert.Equal(t, "Checking", allNewAccounts[0].Name)
+	assert.Equal(t, "USD", allNewAccounts[0].Currency)
+
+	assert.Equal(t, int64(1234567890), allNewAccounts[1].Uid)
+	assert.Equal(t, "Savings", allNewAccounts[1].Name)
+	assert.Equal(t, "USD", allNewAccounts[1].Currency)
+
+	assert.Equal(t, int64(1234567890), allNewAccounts[2].Uid)
+	assert.Equal(t, "Euro Account", allNewAccounts[2].Name)
+	assert.Equal(t, "EUR", allNewAccounts[2].Currency)
+
+	assert.Equal(t, int64(1234567890), allNewAccounts[3].Uid)
+	assert.Equal(t, "Credit Card", allNewAccounts[3].Name)
+	assert.Equal(t, "USD", allNewAccounts[3].Currency)
+
+	assert.Equal(t, "family", allNewTags[0].Name)
+	assert.Equal(t, "weekly", allNewTags[1].Name)
+}
+
+func TestKMyMoneyTransactionDataImporterParseImportedData_ParentCategory(t *testing.T) {
+	importer := KMyMoneyTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "USD",
+	}
+
+	testdata, err := os.ReadFile("../../../testdata/kmymoney_test_file.kmy")
+	assert.Nil(t, err)
+
+	expenseCategoryMap := map[string]map[string]*models.TransactionCategory{
+		"Groceries": {
+			"Shopping": &models.TransactionCategory{
+				CategoryId: 1001,
+				Name:       "Groceries",
+			},
+			"Food": &models.TransactionCategory{
+				CategoryId: 1002,
+				Name:       "Groceries",
+			},
+		},
+	}
+
+	allNewTransactions, _, allNewSubExpenseCategories, _, _, _, err := importer.ParseImportedData(context, user, testdata, time.UTC, converter.DefaultImporterOptions, nil, expenseCategoryMap, nil, nil, nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 1, len(allNewSubExpenseCategories))
+	assert.Equal(t, "Cleaning", allNewSubExpenseCategories[0].Name)
+	assert.Equal(t, int64(1002), allNewTransactions[1].CategoryId)
+	assert.Equal(t, int64(1002), allNewTransactions[5].CategoryId)
+}
+
+func TestKMyMoneyTransactionDataImporterParseImportedData_PayeeAsTag(t *testing.T) {
+	importer := KMyMoneyTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "USD",
+	}
+
+	testdata, err := os.ReadFile("../../../testdata/kmymoney_test_file.kmy")
+	assert.Nil(t, err)
+
+	allNewTransactions, _, _, _, _, allNewTags, err := importer.ParseImportedData(context, user, testdata, time.UTC, converter.DefaultImporterOptions.WithPayeeAsTag(), nil, nil, nil, nil, nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 4, len(allNewTags))
+	assert.Equal(t, []string{"family", "weekly", "Supermarket"}, allNewTransactions[1].OriginalTagNames)
+	assert.Equal(t, []string{"Employer"}, allNewTransactions[2].OriginalTagNames)
+	assert.Equal(t, 0, len(allNewTransactions[3].OriginalTagNames))
+}
+
+func TestKMyMoneyTransactionDataImporterParseImportedData_NotSupportedSplitTransaction(t *testing.T) {
+	importer := KMyMoneyTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "USD",
+	}
+
+	data := "<?xml version=\"1.0\" encoding=\"utf-8\"?>\n" +
+		"<!DOCTYPE KMYMONEY-FILE>\n" +
+		"<KMYMONEY-FILE>\n" +
+		" <ACCOUNTS>\n" +
+		"  <ACCOUNT id=\"A000001\" name=\"Checking\" type=\"1\" parentaccount=\"AStd::Asset\" currency=\"USD\"/>\n" +
+		"  <ACCOUNT id=\"A000002\" name=\"Savings\" type=\"2\" parentaccount=\"AStd::Asset\" currency=\"USD\"/>\n" +
+		"  <ACCOUNT id=\"A000003\" name=\"Food\" type=\"13\" parentaccount=\"AStd::Expense\" currency=\"USD\"/>\n" +
+		" </ACCOUNTS>\n" +
+		" <TRANSACTIONS>\n" +
+		"  <TRANSACTION id=\"T000000000000000001\" postdate=\"2024-09-01\" commodity=\"USD\">\n" +
+		"   <SPLITS>\n" +
+		"    <SPLIT id=\"S0001\" value=\"-300/100\" shares=\"-300/100\" account=\"A000001\"/>\n" +
+		"    <SPLIT id=\"S0002\" value=\"100/100\" shares=\"100/100\" account=\"A000002\"/>\n" +
+		"    <SPLIT id=\"S0003\" value=\"200/100\" shares=\"200/100\" account=\"A000003\"/>\n" +
+		"   </SPLITS>\n" +
+		"  </TRANSACTION>\n" +
+		" </TRANSACTIONS>\n" +
+		"</KMYMONEY-FILE>\n"
+
+	_, _, _, _, _, _, err := importer.ParseImportedData(context, user, []byte(data), time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrNotSupportedSplitTransactions.Message)
+}
+
+func TestKMyMoneyTransactionDataImporterParseImportedData_InvalidFile(t *testing.T) {
+	importer := KMyMoneyTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "USD",
+	}
+
+	_, _, _, _, _, _, err := importer.ParseImportedData(context, user, []byte("not a kmymoney file"), time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrInvalidKMyMoneyFile.Message)
+
+	_, _, _, _, _, _, err = importer.ParseImportedData(context, user, []byte{0x1F, 0x8B, 0x00}, time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrInvalidKMyMoneyFile.Message)
+
+	_, _, _, _, _, _, err = importer.ParseImportedData(context, user, []byte("<?xml version=\"1.0\"?>\n<gnc-v2></gnc-v2>"), time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrInvalidKMyMoneyFile.Message)
+
+	_, _, _, _, _, _, err = importer.ParseImportedData(context, user, []byte("<!DOCTYPE KMYMONEY-FILE>\n<KMYMONEY-FILE></KMYMONEY-FILE>"), time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrNotFoundTransactionDataInFile.Message)
+}
+
+func TestParseKMyMoneyAmount(t *testing.T) {
+	actualValue, err := parseKMyMoneyAmount("-12345/100")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(-12345), actualValue)
+
+	actualValue, err = parseKMyMoneyAmount("123/10")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1230), actualValue)
+
+	actualValue, err = parseKMyMoneyAmount("12")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1200), actualValue)
+
+	_, err = parseKMyMoneyAmount("1/0")
+	assert.EqualError(t, err, errs.ErrAmountInvalid.Message)
+
+	_, err = parseKMyMoneyAmount("1.5")
+	assert.EqualError(t, err, errs.ErrAmountInvalid.Message)
+}
@@ -0,0 +1,427 @@
+package kmymoney
+
+import (
+	"strings"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/datatable"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const kmymoneyTransactionTagSeparator = ";"
+
+var kmymoneyTransactionSupportedColumns = map[datatable.TransactionDataTableColumn]bool{
+	datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIME:         true,
+	datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE:         true,
+	datatable.TRANSACTION_DATA_TABLE_CATEGORY:                 true,
+	datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY:             true,
+	datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME:             true,
+	datatable.TRANSACTION_DATA_TABLE_ACCOUNT_CURRENCY:         true,
+	datatable.TRANSACTION_DATA_TABLE_AMOUNT:                   true,
+	datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME:     true,
+	datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_CURRENCY: true,
+	datatable.TRANSACTION_DATA_TABLE_RELATED_AMOUNT:           true,
+	datatable.TRANSACTION_DATA_TABLE_TAGS:                     true,
+	datatable.TRANSACTION_DATA_TABLE_DESCRIPTION:              true,
+	datatable.TRANSACTION_DATA_TABLE_PAYEE:                    true,
+}
+
+// kmymoneyTransactionDataTable defines the structure of kmymoney transaction data table
+type kmymoneyTransactionDataTable struct {
+	allData    []*kmymoneyTransactionRowData
+	accountMap map[string]*kmymoneyAccountData
+	payeeMap   map[string]*kmymoneyPayeeData
+	tagMap     map[string]*kmymoneyTagData
+}
+
+// kmymoneyTransactionRowData defines the structure of the transaction data in one row, the split transaction which has one asset or liability account
+// and several categories is expanded into several rows, and each row contains the split of asset or liability account and the split of one category
+type kmymoneyTransactionRowData struct {
+	transaction *kmymoneyTransactionData
+	splits      []*kmymoneySplitData
+}
+
+// kmymoneyTransactionDataRow defines the structure of kmymoney transaction data row
+type kmymoneyTransactionDataRow struct {
+	dataTable  *kmymoneyTransactionDataTable
+	data       *kmymoneyTransactionRowData
+	finalItems map[datatable.TransactionDataTableColumn]string
+	isValid    bool
+}
+
+// kmymoneyTransactionDataRowIterator defines the structure of kmymoney transaction data row iterator
+type kmymoneyTransactionDataRowIterator struct {
+	dataTable    *kmymoneyTransactionDataTable
+	currentIndex int
+}
+
+// HasColumn returns whether the transaction data table has specified column
+func (t *kmymoneyTransactionDataTable) HasColumn(column datatable.TransactionDataTableColumn) bool {
+	_, exists := kmymoneyTransactionSupportedColumns[column]
+	return exists
+}
+
+// TransactionRowCount returns the total count of transaction data row
+func (t *kmymoneyTransactionDataTable) TransactionRowCount() int {
+	return len(t.allData)
+}
+
+// TransactionRowIterator returns the iterator of transaction data row
+func (t *kmymoneyTransactionDataTable) TransactionRowIterator() datatable.TransactionDataRowIterator {
+	return &kmymoneyTransactionDataRowIterator{
+		dataTable:    t,
+		currentIndex: -1,
+	}
+}
+
+// IsValid returns whether this row is valid data for importing
+func (r *kmymoneyTransactionDataRow) IsValid() bool {
+	return r.isValid
+}
+
+// GetData returns the data in the specified column type
+func (r *kmymoneyTransactionDataRow) GetData(column datatable.TransactionDataTableColumn) string {
+	_, exists := kmymoneyTransactionSupportedColumns[column]
+
+	if exists {
+		return r.finalItems[column]
+	}
+
+	return ""
+}
+
+// HasNext returns whether the iterator does not reach the end
+func (t *kmymoneyTransactionDataRowIterator) HasNext() bool {
+	return t.currentIndex+1 < len(t.dataTable.allData)
+}
+
+// Next returns the next transaction data row
+func (t *kmymoneyTransactionDataRowIterator) Next(ctx core.Context, user *models.User) (daraRow datatable.TransactionDataRow, err error) {
+	if t.currentIndex+1 >= len(t.dataTable.allData) {
+		return nil, nil
+	}
+
+	t.currentIndex++
+
+	data := t.dataTable.allData[t.currentIndex]
+	rowItems, isValid, err := t.parseTransaction(ctx, user, data)
+
+	if err != nil {
+		log.Errorf(ctx, "[kmymoney_transaction_data_table.Next] cannot parsing transaction in row#%d, because %s", t.currentIndex, err.Error())
+		return nil, err
+	}
+
+	return &kmymoneyTransactionDataRow{
+		dataTable:  t.dataTable,
+		data:       data,
+		finalItems: rowItems,
+		isValid:    isValid,
+	}, nil
+}
+
+func (t *kmymoneyTransactionDataRowIterator) parseTransaction(ctx core.Context, user *models.User, rowData *kmymoneyTransactionRowData) (map[datatable.TransactionDataTableColumn]string, bool, error) {
+	data := make(map[datatable.TransactionDataTableColumn]string, len(kmymoneyTransactionSupportedColumns))
+	kmymoneyTransaction := rowData.transaction
+
+	if kmymoneyTransaction.PostDate == "" {
+		return nil, false, errs.ErrMissingTransactionTime
+	}
+
+	postDate, err := time.Parse("2006-01-02", kmymoneyTransaction.PostDate)
+
+	if err != nil {
+		return nil, false, errs.ErrTransactionTimeInvalid
+	}
+
+	data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIME] = postDate.Format("2006-01-02") + " 00:00:00"
+
+	if len(rowData.splits) != 2 {
+		if len(kmymoneyTransaction.Splits) == 1 {
+			amount, err := parseKMyMoneyAmount(kmymoneyTransaction.Splits[0].Shares)
+
+			if err != nil {
+				return nil, false, err
+			}
+
+			if amount == 0 {
+				log.Warnf(ctx, "[kmymoney_transaction_data_table.parseTransaction] skip parsing transaction \"id:%s\" with zero amount", kmymoneyTransaction.Id)
+				return nil, false, nil
+			}
+
+			log.Errorf(ctx, "[kmymoney_transaction_data_table.parseTransaction] cannot parse transaction \"id:%s\", because split count is %d", kmymoneyTransaction.Id, len(kmymoneyTransaction.Splits))
+			return nil, false, errs.ErrThereAreNotSupportedTransactionType
+		} else if len(kmymoneyTransaction.Splits) < 1 {
+			log.Errorf(ctx, "[kmymoney_transaction_data_table.parseTransaction] cannot parse transaction \"id:%s\", because split count is %d", kmymoneyTransaction.Id, len(kmymoneyTransaction.Splits))
+			return nil, false, errs.ErrInvalidKMyMoneyFile
+		}
+
+		log.Errorf(ctx, "[kmymoney_transaction_data_table.parseTransaction] cannot parse split transaction \"id:%s\", because split count is %d", kmymoneyTransaction.Id, len(kmymoneyTransaction.Splits))
+		return nil, false, errs.ErrNotSupportedSplitTransactions
+	}
+
+	splitData1 := rowData.splits[0]
+	splitData2 := rowData.splits[1]
+
+	account1 := t.dataTable.accountMap[splitData1.AccountId]
+	account2 := t.dataTable.accountMap[splitData2.AccountId]
+
+	if account1 == nil || account2 == nil {
+		return nil, false, errs.ErrMissingAccountData
+	}
+
+	amount1, err := parseKMyMoneyAmount(splitData1.Shares)
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	amount2, err := parseKMyMoneyAmount(splitData2.Shares)
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	var accountSplit, otherSplit *kmymoneySplitData
+
+	if kmymoneyAssetOrLiabilityAccountTypes[account1.AccountType] && kmymoneyAssetOrLiabilityAccountTypes[account2.AccountType] {
+		var fromAccount, toAccount *kmymoneyAccountData
+		var fromAmount, toAmount int64
+
+		if amount1 < 0 {
+			fromAccount, fromAmount, toAccount, toAmount = account1, -amount1, account2, amount2
+			accountSplit, otherSplit = splitData1, splitData2
+		} else if amount2 < 0 {
+			fromAccount, fromAmount, toAccount, toAmount = account2, -amount2, account1, amount1
+			accountSplit, otherSplit = splitData2, splitData1
+		} else {
+			log.Errorf(ctx, "[kmymoney_transaction_data_table.parseTransaction] cannot parse transfer transaction \"id:%s\", because unexcepted account amounts \"%d\" and \"%d\"", kmymoneyTransaction.Id, amount1, amount2)
+			return nil, false, errs.ErrInvalidKMyMoneyFile
+		}
+
+		data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = kmymoneyTransactionTypeNameMapping[models.TRANSACTION_TYPE_TRANSFER]
+		data[datatable.TRANSACTION_DATA_TABLE_CATEGORY] = ""
+		data[datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY] = ""
+		data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME] = fromAccount.Name
+		data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_CURRENCY] = fromAccount.Currency
+		data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(fromAmount)
+		data[datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME] = toAccount.Name
+		data[datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_CURRENCY] = toAccount.Currency
+		data[datatable.TRANSACTION_DATA_TABLE_RELATED_AMOUNT] = utils.FormatAmount(toAmount)
+	} else if kmymoneyAssetOrLiabilityAccountTypes[account1.AccountType] || kmymoneyAssetOrLiabilityAccountTypes[account2.AccountType] {
+		account, categoryAccount := account1, account2
+		accountSplit, otherSplit = splitData1, splitData2
+		amount := amount1
+
+		if kmymoneyAssetOrLiabilityAccountTypes[account2.AccountType] {
+			account, categoryAccount = account2, account1
+			accountSplit, otherSplit = splitData2, splitData1
+			amount = amount2
+		}
+
+		if len(kmymoneyTransaction.Splits) > 2 { // the split of asset or liability account contains the total amount of all categories
+			amount, err = parseKMyMoneyAmount(otherSplit.Value)
+
+			if err != nil {
+				return nil, false, err
+			}
+
+			amount = -amount
+		}
+
+		if categoryAccount.IsOpeningBalanceAccount() {
+			data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = kmymoneyTransactionTypeNameMapping[models.TRANSACTION_TYPE_MODIFY_BALANCE]
+			data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(amount)
+		} else if categoryAccount.AccountType == kmymoneyIncomeAccountType || categoryAccount.AccountType == kmymoneyExpenseAccountType || categoryAccount.AccountType == kmymoneyEquityAccountType {
+			if amount > 0 {
+				data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = kmymoneyTransactionTypeNameMapping[models.TRANSACTION_TYPE_INCOME]
+				data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(amount)
+			} else {
+				data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = kmymoneyTransactionTypeNameMapping[models.TRANSACTION_TYPE_EXPENSE]
+				data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(-amount)
+			}
+
+			data[datatable.TRANSACTION_DATA_TABLE_CATEGORY] = t.getCategoryName(categoryAccount)
+			data[datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY] = categoryAccount.Name
+		} else {
+			log.Errorf(ctx, "[kmymoney_transaction_data_table.parseTransaction] cannot parse transaction \"id:%s\", because unexcepted account types \"%s\" and \"%s\"", kmymoneyTransaction.Id, account1.AccountType, account2.AccountType)
+			return nil, false, errs.ErrThereAreNotSupportedTransactionType
+		}
+
+		data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME] = account.Name
+		data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_CURRENCY] = account.Currency
+	} else {
+		log.Errorf(ctx, "[kmymoney_transaction_data_table.parseTransaction] cannot parse transaction \"id:%s\", because unexcepted account types \"%s\" and \"%s\"", kmymoneyTransaction.Id, account1.AccountType, account2.AccountType)
+		return nil, false, errs.ErrThereAreNotSupportedTransactionType
+	}
+
+	if payee := t.dataTable.getPayee(accountSplit, otherSplit); payee != nil {
+		data[datatable.TRANSACTION_DATA_TABLE_PAYEE] = payee.Name
+	}
+
+	data[datatable.TRANSACTION_DATA_TABLE_TAGS] = strings.Join(t.dataTable.getTagNames(accountSplit, otherSplit), kmymoneyTransactionTagSeparator)
+
+	if otherSplit.Memo != "" {
+		data[datatable.TRANSACTION_DATA_TABLE_DESCRIPTION] = otherSplit.Memo
+	} else if accountSplit.Memo != "" {
+		data[datatable.TRANSACTION_DATA_TABLE_DESCRIPTION] = accountSplit.Memo
+	} else {
+		data[datatable.TRANSACTION_DATA_TABLE_DESCRIPTION] = kmymoneyTransaction.Memo
+	}
+
+	return data, true, nil
+}
+
+func (t *kmymoneyTransactionDataRowIterator) getCategoryName(accountData *kmymoneyAccountData) string {
+	if accountData == nil || accountData.ParentId == "" || kmymoneyStandardAccountIds[accountData.ParentId] {
+		return ""
+	}
+
+	parentAccount := t.dataTable.accountMap[accountData.ParentId]
+
+	if parentAccount == nil {
+		return ""
+	}
+
+	return parentAccount.Name
+}
+
+func (t *kmymoneyTransactionDataTable) getPayee(splits ...*kmymoneySplitData) *kmymoneyPayeeData {
+	for i := 0; i < len(splits); i++ {
+		if payee := t.payeeMap[splits[i].PayeeId]; payee != nil {
+			return payee
+		}
+	}
+
+	return nil
+}
+
+func (t *kmymoneyTransactionDataTable) getTagNames(splits ...*kmymoneySplitData) []string {
+	tagNames := make([]string, 0)
+	tagNamesMap := make(map[string]bool)
+
+	for i := 0; i < len(splits); i++ {
+		for j := 0; j < len(splits[i].Tags); j++ {
+			tag := t.tagMap[splits[i].Tags[j].Id]
+
+			if tag == nil || tagNamesMap[tag.Name] {
+				continue
+			}
+
+			tagNames = append(tagNames, tag.Name)
+			tagNamesMap[tag.Name] = true
+		}
+	}
+
+	return tagNames
+}
+
+// parseKMyMoneyAmount returns the amount in cents of the fraction (e.g. -12345/100) which is used in kmymoney
+func parseKMyMoneyAmount(value string) (int64, error) {
+	items := strings.Split(value, "/")
+
+	if len(items) > 2 {
+		return 0, errs.ErrAmountInvalid
+	}
+
+	numerator, err := utils.StringToInt64(items[0])
+
+	if err != nil {
+		return 0, errs.ErrAmountInvalid
+	}
+
+	if len(items) == 1 {
+		return numerator * 100, nil
+	}
+
+	denominator, err := utils.StringToInt64(items[1])
+
+	if err != nil || denominator <= 0 {
+		return 0, errs.ErrAmountInvalid
+	}
+
+	return numerator * 100 / denominator, nil
+}
+
+// getKMyMoneyImportedTransactionRows returns the rows of transactions which should be imported,
+// the split transaction which has one asset or liability account and several categories is expanded into one row per category
+func getKMyMoneyImportedTransactionRows(transactions []*kmymoneyTransactionData, accountMap map[string]*kmymoneyAccountData) []*kmymoneyTransactionRowData {
+	result := make([]*kmymoneyTransactionRowData, 0, len(transactions))
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+
+		if len(transaction.Splits) == 2 {
+			result = append(result, &kmymoneyTransactionRowData{
+				transaction: transaction,
+				splits:      transaction.Splits,
+			})
+			continue
+		}
+
+		var accountSplit *kmymoneySplitData
+		categorySplits := make([]*kmymoneySplitData, 0, len(transaction.Splits))
+
+		for j := 0; j < len(transaction.Splits); j++ {
+			split := transaction.Splits[j]
+			account := accountMap[split.AccountId]
+
+			if account != nil && kmymoneyAssetOrLiabilityAccountTypes[account.AccountType] && accountSplit == nil {
+				accountSplit = split
+			} else if account != nil && (account.AccountType == kmymoneyIncomeAccountType || account.AccountType == kmymoneyExpenseAccountType) {
+				categorySplits = append(categorySplits, split)
+			} else {
+				accountSplit = nil
+				break
+			}
+		}
+
+		if accountSplit == nil || len(categorySplits) < 2 || len(categorySplits)+1 != len(transaction.Splits) {
+			result = append(result, &kmymoneyTransactionRowData{
+				transaction: transaction,
+			})
+			continue
+		}
+
+		for j := 0; j < len(categorySplits); j++ {
+			result = append(result, &kmymoneyTransactionRowData{
+				transaction: transaction,
+				splits:      []*kmymoneySplitData{accountSplit, categorySplits[j]},
+			})
+		}
+	}
+
+	return result
+}
+
+func createNewKMyMoneyTransactionDataTable(file *kmymoneyFile) (*kmymoneyTransactionDataTable, error) {
+	if file == nil || len(file.Transactions) < 1 {
+		return nil, errs.ErrNotFoundTransactionDataInFile
+	}
+
+	accountMap := make(map[string]*kmymoneyAccountData, len(file.Accounts))
+	payeeMap := make(map[string]*kmymoneyPayeeData, len(file.Payees))
+	tagMap := make(map[string]*kmymoneyTagData, len(file.Tags))
+
+	for i := 0; i < len(file.Accounts); i++ {
+		accountMap[file.Accounts[i].Id] = file.Accounts[i]
+	}
+
+	for i := 0; i < len(file.Payees); i++ {
+		payeeMap[file.Payees[i].Id] = file.Payees[i]
+	}
+
+	for i := 0; i < len(file.Tags); i++ {
+		tagMap[file.Tags[i].Id] = file.Tags[i]
+	}
+
+	return &kmymoneyTransactionDataTable{
+		allData:    getKMyMoneyImportedTransactionRows(file.Transactions, accountMap),
+		accountMap: accountMap,
+		payeeMap:   payeeMap,
+		tagMap:     tagMap,
+	}, nil
+}
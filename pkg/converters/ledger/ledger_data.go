@@ -0,0 +1,84 @@
+package ledger
+
+import "strings"
+
+const ledgerEquityAccountNameOpeningBalance = "Opening Balances"
+
+// ledgerTransactionStatus represents the Ledger transaction status
+type ledgerTransactionStatus string
+
+// Ledger transaction statuses
+const (
+	ledgerTransactionStatusUncleared ledgerTransactionStatus = ""
+	ledgerTransactionStatusCleared   ledgerTransactionStatus = "*"
+	ledgerTransactionStatusPending   ledgerTransactionStatus = "!"
+)
+
+// ledgerAccountType represents the Ledger account type
+type ledgerAccountType byte
+
+// Ledger account types
+const (
+	ledgerUnknownAccountType     ledgerAccountType = 0
+	ledgerAssetsAccountType      ledgerAccountType = 1
+	ledgerLiabilitiesAccountType ledgerAccountType = 2
+	ledgerEquityAccountType      ledgerAccountType = 3
+	ledgerIncomeAccountType      ledgerAccountType = 4
+	ledgerExpensesAccountType    ledgerAccountType = 5
+)
+
+// ledgerData defines the structure of ledger data
+type ledgerData struct {
+	Accounts     map[string]*ledgerAccount
+	Commodities  map[string]bool
+	Transactions []*ledgerTransactionEntry
+}
+
+// ledgerAccount defines the structure of ledger account
+type ledgerAccount struct {
+	Name        string
+	AccountType ledgerAccountType
+}
+
+// ledgerTransactionEntry defines the structure of ledger transaction entry
+type ledgerTransactionEntry struct {
+	Date     string
+	Status   ledgerTransactionStatus
+	Code     string
+	Payee    string
+	Notes    []string
+	Postings []*ledgerPosting
+	Tags     []string
+	Metadata map[string]string
+}
+
+// ledgerPosting defines the structure of ledger transaction posting
+type ledgerPosting struct {
+	Account            string
+	Amount             string
+	OriginalAmount     string
+	Commodity          string
+	TotalCost          string
+	TotalCostCommodity string
+	Price              string
+	PriceCommodity     string
+}
+
+func (a *ledgerAccount) isOpeningBalanceEquityAccount() bool {
+	if a.AccountType != ledgerEquityAccountType {
+		return false
+	}
+
+	nameItems := strings.Split(a.Name, ledgerAccountNameItemsSeparator)
+
+	if len(nameItems) != 2 {
+		return false
+	}
+
+	// both "Opening Balances" used by ledger and "Opening-Balances" used by beancount are common
+	return strings.EqualFold(strings.ReplaceAll(nameItems[1], "-", " "), ledgerEquityAccountNameOpeningBalance)
+}
+
+func (p *ledgerPosting) isAmountElided() bool {
+	return p.OriginalAmount == ""
+}
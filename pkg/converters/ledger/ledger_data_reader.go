@@ -0,0 +1,885 @@
+package ledger
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const ledgerAccountNameItemsSeparator = ":"
+const ledgerCommentPrefix = ';'
+const ledgerTopLevelCommentPrefixes = ";#%|*"
+const ledgerTagSeparator = ":"
+const ledgerPricePrefix = "@"
+const ledgerTotalCostPrefix = "@@"
+const ledgerBalanceAssertionPrefix = '='
+const ledgerAuxiliaryDateSeparator = '='
+const ledgerCommodityQuote = '"'
+const ledgerTransactionCodePrefix = '('
+const ledgerTransactionCodeSuffix = ')'
+const ledgerVirtualAccountPrefix = '('
+const ledgerVirtualAccountSuffix = ')'
+const ledgerBalancedVirtualAccountPrefix = '['
+const ledgerBalancedVirtualAccountSuffix = ']'
+const ledgerAccountTypeMetadataKey = "type"
+
+var ledgerAccountTypeNames = map[string]ledgerAccountType{
+	"assets":      ledgerAssetsAccountType,
+	"asset":       ledgerAssetsAccountType,
+	"liabilities": ledgerLiabilitiesAccountType,
+	"liability":   ledgerLiabilitiesAccountType,
+	"equity":      ledgerEquityAccountType,
+	"income":      ledgerIncomeAccountType,
+	"revenue":     ledgerIncomeAccountType,
+	"revenues":    ledgerIncomeAccountType,
+	"expenses":    ledgerExpensesAccountType,
+	"expense":     ledgerExpensesAccountType,
+}
+
+// hledger declares account types by the "type" tag in account directives
+var ledgerAccountTypeTagValues = map[string]ledgerAccountType{
+	"a":          ledgerAssetsAccountType,
+	"asset":      ledgerAssetsAccountType,
+	"c":          ledgerAssetsAccountType,
+	"cash":       ledgerAssetsAccountType,
+	"l":          ledgerLiabilitiesAccountType,
+	"liability":  ledgerLiabilitiesAccountType,
+	"e":          ledgerEquityAccountType,
+	"equity":     ledgerEquityAccountType,
+	"v":          ledgerEquityAccountType,
+	"conversion": ledgerEquityAccountType,
+	"r":          ledgerIncomeAccountType,
+	"revenue":    ledgerIncomeAccountType,
+	"x":          ledgerExpensesAccountType,
+	"expense":    ledgerExpensesAccountType,
+}
+
+// the directives which are not used for importing transactions, the indented lines after them are also skipped
+var ledgerSkippedDirectives = map[string]bool{
+	"P":            true,
+	"D":            true,
+	"N":            true,
+	"Y":            true,
+	"A":            true,
+	"C":            true,
+	"year":         true,
+	"alias":        true,
+	"apply":        true,
+	"end":          true,
+	"bucket":       true,
+	"define":       true,
+	"payee":        true,
+	"tag":          true,
+	"check":        true,
+	"assert":       true,
+	"expr":         true,
+	"eval":         true,
+	"value":        true,
+	"decimal-mark": true,
+	"~":            true,
+	"=":            true,
+	"i":            true,
+	"I":            true,
+	"o":            true,
+	"O":            true,
+	"b":            true,
+	"h":            true,
+}
+
+var ledgerStyleTagsPattern = regexp.MustCompile(`^:([^:]+:)+$`)
+var hledgerStyleTagsPattern = regexp.MustCompile(`^[^\s:,]+:(\s*,\s*[^\s:,]+:)*\s*,?$`)
+var ledgerMetadataPattern = regexp.MustCompile(`^([^\s:]+):\s*(.+)$`)
+
+// ledgerDataReader defines the structure of ledger data reader
+type ledgerDataReader struct {
+	allLines []string
+}
+
+// read returns the imported ledger data, both ledger-cli and hledger journal files are supported
+// Reference: https://ledger-cli.org/doc/ledger3.html#Journal-Format
+// Reference: https://hledger.org/hledger.html#journal
+func (r *ledgerDataReader) read(ctx core.Context) (*ledgerData, error) {
+	if len(r.allLines) < 1 {
+		return nil, errs.ErrNotFoundTransactionDataInFile
+	}
+
+	data := &ledgerData{
+		Accounts:     make(map[string]*ledgerAccount),
+		Commodities:  make(map[string]bool),
+		Transactions: make([]*ledgerTransactionEntry, 0),
+	}
+
+	var err error
+	var currentTransactionEntry *ledgerTransactionEntry
+	var currentAccount *ledgerAccount
+	inBlockComment := false
+	skipIndentedLines := false
+
+	for i := 0; i < len(r.allLines); i++ {
+		line := r.allLines[i]
+		trimmedLine := strings.TrimSpace(line)
+
+		if inBlockComment {
+			if trimmedLine == "end comment" || trimmedLine == "end test" {
+				inBlockComment = false
+			}
+
+			continue
+		}
+
+		if len(trimmedLine) == 0 { // empty line ends current transaction or directive
+			currentTransactionEntry, err = r.updateCurrentState(ctx, data, currentTransactionEntry)
+
+			if err != nil {
+				return nil, err
+			}
+
+			currentAccount = nil
+			skipIndentedLines = false
+			continue
+		}
+
+		if line[0] == ' ' || line[0] == '\t' { // original line has space prefix, maybe transaction posting, comment or sub directive line
+			if skipIndentedLines {
+				continue
+			}
+
+			if trimmedLine[0] == ledgerCommentPrefix {
+				if currentTransactionEntry != nil {
+					r.readTransactionComment(trimmedLine[1:], currentTransactionEntry, len(currentTransactionEntry.Postings) > 0)
+				} else if currentAccount != nil {
+					r.readAccountComment(trimmedLine[1:], currentAccount)
+				}
+
+				continue
+			}
+
+			if currentTransactionEntry != nil {
+				posting, comment, err := r.readTransactionPostingLine(ctx, i, trimmedLine, data)
+
+				if err != nil {
+					return nil, err
+				}
+
+				if posting != nil {
+					currentTransactionEntry.Postings = append(currentTransactionEntry.Postings, posting)
+				}
+
+				if comment != "" {
+					r.readTransactionComment(comment, currentTransactionEntry, true)
+				}
+			} else if currentAccount == nil { // sub directives of account directive are skipped
+				log.Warnf(ctx, "[ledger_data_reader.read] cannot parse line#%d \"%s\", because line prefix is invalid", i, line)
+			}
+
+			continue
+		}
+
+		currentTransactionEntry, err = r.updateCurrentState(ctx, data, currentTransactionEntry)
+
+		if err != nil {
+			return nil, err
+		}
+
+		currentAccount = nil
+		skipIndentedLines = false
+
+		if strings.IndexByte(ledgerTopLevelCommentPrefixes, line[0]) >= 0 { // skip comment lines
+			continue
+		}
+
+		directive, remain := r.splitFirstItem(trimmedLine)
+
+		if directive == "comment" || directive == "test" {
+			inBlockComment = true
+		} else if directive == "include" || directive == "!include" { // not support include directive
+			return nil, errs.ErrLedgerFileNotSupportInclude
+		} else if directive == "account" {
+			currentAccount = r.readAccountLine(ctx, i, remain, data)
+		} else if directive == "commodity" {
+			r.readCommodityLine(remain, data)
+			skipIndentedLines = true
+		} else if '0' <= line[0] && line[0] <= '9' { // original line has date as first item
+			currentTransactionEntry, err = r.readTransactionLine(ctx, i, trimmedLine)
+
+			if err != nil {
+				return nil, err
+			}
+		} else if _, exists := ledgerSkippedDirectives[directive]; exists || directive[0] == '~' || directive[0] == '=' { // skip other directives, periodic transactions and automated transactions
+			skipIndentedLines = true
+		} else {
+			log.Warnf(ctx, "[ledger_data_reader.read] cannot parse line#%d \"%s\", because directive is unknown", i, line)
+			skipIndentedLines = true
+		}
+	}
+
+	_, err = r.updateCurrentState(ctx, data, currentTransactionEntry)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (r *ledgerDataReader) updateCurrentState(ctx core.Context, data *ledgerData, currentTransactionEntry *ledgerTransactionEntry) (*ledgerTransactionEntry, error) {
+	if currentTransactionEntry == nil {
+		return nil, nil
+	}
+
+	err := r.fillElidedAmount(ctx, currentTransactionEntry)
+
+	if err != nil {
+		return nil, err
+	}
+
+	data.Transactions = append(data.Transactions, currentTransactionEntry)
+
+	return nil, nil
+}
+
+func (r *ledgerDataReader) readAccountLine(ctx core.Context, lineIndex int, content string, data *ledgerData) *ledgerAccount {
+	accountName, comment := r.splitComment(content)
+
+	if accountName == "" {
+		log.Warnf(ctx, "[ledger_data_reader.readAccountLine] cannot parse account line#%d \"%s\", because missing account name", lineIndex, content)
+		return nil
+	}
+
+	account, exists := data.Accounts[accountName]
+
+	if !exists {
+		account = r.createAccount(data, accountName)
+	}
+
+	if comment != "" {
+		r.readAccountComment(comment, account)
+	}
+
+	return account
+}
+
+func (r *ledgerDataReader) readAccountComment(comment string, account *ledgerAccount) {
+	_, metadata, _ := r.parseComment(comment)
+	accountType, exists := metadata[ledgerAccountTypeMetadataKey]
+
+	if !exists {
+		return
+	}
+
+	// hledger allows other tags following the type tag in the same comment
+	if index := strings.IndexByte(accountType, ','); index >= 0 {
+		accountType = accountType[:index]
+	}
+
+	if actualAccountType, exists := ledgerAccountTypeTagValues[strings.ToLower(strings.TrimSpace(accountType))]; exists {
+		account.AccountType = actualAccountType
+	}
+}
+
+func (r *ledgerDataReader) readCommodityLine(content string, data *ledgerData) {
+	commodity, _ := r.splitComment(content)
+
+	// hledger also supports declaring commodity with sample amount, e.g. "commodity $1,000.00"
+	if strings.ContainsAny(commodity, "0123456789") {
+		if _, amountCommodity, err := r.parseAmount(commodity); err == nil {
+			commodity = amountCommodity
+		}
+	}
+
+	commodity = r.unquoteCommodity(commodity)
+
+	if commodity != "" {
+		data.Commodities[commodity] = true
+	}
+}
+
+func (r *ledgerDataReader) createAccount(data *ledgerData, accountName string) *ledgerAccount {
+	account := &ledgerAccount{
+		Name:        accountName,
+		AccountType: ledgerUnknownAccountType,
+	}
+
+	accountNameItems := strings.Split(accountName, ledgerAccountNameItemsSeparator)
+
+	if accountType, exists := ledgerAccountTypeNames[strings.ToLower(strings.TrimSpace(accountNameItems[0]))]; exists {
+		account.AccountType = accountType
+	}
+
+	data.Accounts[accountName] = account
+	return account
+}
+
+func (r *ledgerDataReader) readTransactionLine(ctx core.Context, lineIndex int, line string) (*ledgerTransactionEntry, error) {
+	// DATE[=AUX_DATE] [*|!] [(CODE)] PAYEE [; COMMENT]
+	content, comment := r.splitComment(line)
+	dateItem, remain := r.splitFirstItem(content)
+	date, err := r.parseDate(dateItem)
+
+	if err != nil {
+		log.Errorf(ctx, "[ledger_data_reader.readTransactionLine] cannot parse date \"%s\" in line#%d \"%s\", because %s", dateItem, lineIndex, line, err.Error())
+		return nil, errs.ErrTransactionTimeInvalid
+	}
+
+	transactionEntry := &ledgerTransactionEntry{
+		Date:     date,
+		Status:   ledgerTransactionStatusUncleared,
+		Notes:    make([]string, 0),
+		Postings: make([]*ledgerPosting, 0),
+		Tags:     make([]string, 0),
+		Metadata: make(map[string]string),
+	}
+
+	if strings.HasPrefix(remain, string(ledgerTransactionStatusCleared)) {
+		transactionEntry.Status = ledgerTransactionStatusCleared
+		remain = strings.TrimSpace(remain[1:])
+	} else if strings.HasPrefix(remain, string(ledgerTransactionStatusPending)) {
+		transactionEntry.Status = ledgerTransactionStatusPending
+		remain = strings.TrimSpace(remain[1:])
+	}
+
+	if len(remain) > 0 && remain[0] == ledgerTransactionCodePrefix {
+		if codeEndIndex := strings.IndexByte(remain, ledgerTransactionCodeSuffix); codeEndIndex > 0 {
+			transactionEntry.Code = remain[1:codeEndIndex]
+			remain = strings.TrimSpace(remain[codeEndIndex+1:])
+		}
+	}
+
+	transactionEntry.Payee = remain
+
+	if comment != "" {
+		r.readTransactionComment(comment, transactionEntry, false)
+	}
+
+	return transactionEntry, nil
+}
+
+func (r *ledgerDataReader) readTransactionComment(comment string, transactionEntry *ledgerTransactionEntry, isPostingComment bool) {
+	tags, metadata, note := r.parseComment(comment)
+
+	// ezbookkeeping does not support posting tags, so the tags of all postings are added to the transaction
+	for i := 0; i < len(tags); i++ {
+		exists := false
+
+		for j := 0; j < len(transactionEntry.Tags); j++ {
+			if transactionEntry.Tags[j] == tags[i] {
+				exists = true
+				break
+			}
+		}
+
+		if !exists {
+			transactionEntry.Tags = append(transactionEntry.Tags, tags[i])
+		}
+	}
+
+	if isPostingComment {
+		return
+	}
+
+	for key, value := range metadata {
+		if _, exists := transactionEntry.Metadata[key]; !exists {
+			transactionEntry.Metadata[key] = value
+		}
+	}
+
+	if note != "" {
+		transactionEntry.Notes = append(transactionEntry.Notes, note)
+	}
+}
+
+func (r *ledgerDataReader) readTransactionPostingLine(ctx core.Context, lineIndex int, line string, data *ledgerData) (*ledgerPosting, string, error) {
+	// [*|!] ACCOUNT  [AMOUNT] [{LOT_PRICE}] [@ PRICE | @@ TOTAL_COST] [= BALANCE_ASSERTION] [; COMMENT]
+	content, comment := r.splitComment(line)
+
+	if strings.HasPrefix(content, string(ledgerTransactionStatusCleared)+" ") || strings.HasPrefix(content, string(ledgerTransactionStatusPending)+" ") {
+		content = strings.TrimSpace(content[1:])
+	}
+
+	accountName, amountContent := r.splitAccountNameAndAmount(content)
+
+	if accountName == "" {
+		log.Warnf(ctx, "[ledger_data_reader.readTransactionPostingLine] cannot parse transaction posting line#%d \"%s\", because missing account name", lineIndex, line)
+		return nil, "", errs.ErrMissingAccountData
+	}
+
+	if accountName[0] == ledgerVirtualAccountPrefix && accountName[len(accountName)-1] == ledgerVirtualAccountSuffix { // unbalanced virtual posting does not affect real accounts
+		log.Warnf(ctx, "[ledger_data_reader.readTransactionPostingLine] skip virtual posting line#%d \"%s\"", lineIndex, line)
+		return nil, comment, nil
+	}
+
+	if accountName[0] == ledgerBalancedVirtualAccountPrefix && accountName[len(accountName)-1] == ledgerBalancedVirtualAccountSuffix {
+		accountName = strings.TrimSpace(accountName[1 : len(accountName)-1])
+	}
+
+	transactionPosting := &ledgerPosting{
+		Account: accountName,
+	}
+
+	if balanceAssertionIndex := strings.IndexByte(amountContent, ledgerBalanceAssertionPrefix); balanceAssertionIndex >= 0 {
+		amountContent = strings.TrimSpace(amountContent[:balanceAssertionIndex])
+	}
+
+	if totalCostIndex := strings.Index(amountContent, ledgerTotalCostPrefix); totalCostIndex >= 0 {
+		totalCost, totalCostCommodity, err := r.parseAmount(amountContent[totalCostIndex+len(ledgerTotalCostPrefix):])
+
+		if err != nil {
+			log.Warnf(ctx, "[ledger_data_reader.readTransactionPostingLine] cannot parse total cost in transaction posting line#%d \"%s\", because %s", lineIndex, line, err.Error())
+			return nil, "", errs.ErrAmountInvalid
+		}
+
+		transactionPosting.TotalCost = strings.TrimLeft(totalCost, "-")
+		transactionPosting.TotalCostCommodity = totalCostCommodity
+		amountContent = strings.TrimSpace(amountContent[:totalCostIndex])
+	} else if priceIndex := strings.Index(amountContent, ledgerPricePrefix); priceIndex >= 0 {
+		price, priceCommodity, err := r.parseAmount(amountContent[priceIndex+len(ledgerPricePrefix):])
+
+		if err != nil {
+			log.Warnf(ctx, "[ledger_data_reader.readTransactionPostingLine] cannot parse price in transaction posting line#%d \"%s\", because %s", lineIndex, line, err.Error())
+			return nil, "", errs.ErrAmountInvalid
+		}
+
+		transactionPosting.Price = strings.TrimLeft(price, "-")
+		transactionPosting.PriceCommodity = priceCommodity
+		amountContent = strings.TrimSpace(amountContent[:priceIndex])
+	}
+
+	amountContent = r.removeLotAnnotations(amountContent)
+
+	if amountContent != "" {
+		amount, commodity, err := r.parseAmount(amountContent)
+
+		if err != nil {
+			log.Warnf(ctx, "[ledger_data_reader.readTransactionPostingLine] cannot parse amount in transaction posting line#%d \"%s\", because %s", lineIndex, line, err.Error())
+			return nil, "", errs.ErrAmountInvalid
+		}
+
+		transactionPosting.OriginalAmount = amountContent
+		transactionPosting.Amount = amount
+		transactionPosting.Commodity = commodity
+	}
+
+	if _, exists := data.Accounts[transactionPosting.Account]; !exists {
+		r.createAccount(data, transactionPosting.Account)
+	}
+
+	return transactionPosting, comment, nil
+}
+
+func (r *ledgerDataReader) fillElidedAmount(ctx core.Context, transactionEntry *ledgerTransactionEntry) error {
+	elidedPosting := (*ledgerPosting)(nil)
+
+	for i := 0; i < len(transactionEntry.Postings); i++ {
+		if !transactionEntry.Postings[i].isAmountElided() {
+			continue
+		}
+
+		if elidedPosting != nil {
+			log.Errorf(ctx, "[ledger_data_reader.fillElidedAmount] cannot parse transaction in \"%s\", because more than one posting has elided amount", transactionEntry.Date)
+			return errs.ErrInvalidLedgerFile
+		}
+
+		elidedPosting = transactionEntry.Postings[i]
+	}
+
+	if elidedPosting == nil {
+		return nil
+	}
+
+	totalAmount := int64(0)
+	commodity := ""
+	hasCommodity := false
+
+	for i := 0; i < len(transactionEntry.Postings); i++ {
+		posting := transactionEntry.Postings[i]
+
+		if posting == elidedPosting {
+			continue
+		}
+
+		amount, amountCommodity, err := r.getPostingBalancingAmount(posting)
+
+		if err != nil {
+			log.Errorf(ctx, "[ledger_data_reader.fillElidedAmount] cannot parse amount \"%s\" in \"%s\", because %s", posting.Amount, transactionEntry.Date, err.Error())
+			return errs.ErrAmountInvalid
+		}
+
+		if hasCommodity && commodity != amountCommodity {
+			log.Errorf(ctx, "[ledger_data_reader.fillElidedAmount] cannot infer elided amount in \"%s\", because other postings have different commodities", transactionEntry.Date)
+			return errs.ErrInvalidLedgerFile
+		}
+
+		totalAmount += amount
+		commodity = amountCommodity
+		hasCommodity = true
+	}
+
+	elidedPosting.Amount = utils.FormatAmount(-totalAmount)
+	elidedPosting.Commodity = commodity
+
+	return nil
+}
+
+// getPostingBalancingAmount returns the amount and commodity which is used for balancing the transaction
+func (r *ledgerDataReader) getPostingBalancingAmount(posting *ledgerPosting) (int64, string, error) {
+	amount, err := utils.ParseAmount(posting.Amount)
+
+	if err != nil {
+		return 0, "", err
+	}
+
+	if posting.TotalCost != "" {
+		totalCost, err := utils.ParseAmount(posting.TotalCost)
+
+		if err != nil {
+			return 0, "", err
+		}
+
+		if amount < 0 {
+			return -totalCost, posting.TotalCostCommodity, nil
+		}
+
+		return totalCost, posting.TotalCostCommodity, nil
+	}
+
+	if posting.Price != "" {
+		price, err := strconv.ParseFloat(posting.Price, 64)
+
+		if err != nil {
+			return 0, "", err
+		}
+
+		return int64(math.Round(float64(amount) * price)), posting.PriceCommodity, nil
+	}
+
+	return amount, posting.Commodity, nil
+}
+
+// parseComment returns the tags, metadata and note text in the comment, both ledger style tags (e.g. ":tag1:tag2:") and hledger style tags (e.g. "tag1:, tag2:") are supported
+func (r *ledgerDataReader) parseComment(comment string) ([]string, map[string]string, string) {
+	tags := make([]string, 0)
+	metadata := make(map[string]string)
+	items := strings.Fields(comment)
+	remainItems := make([]string, 0, len(items))
+
+	for i := 0; i < len(items); i++ {
+		if ledgerStyleTagsPattern.MatchString(items[i]) {
+			tagNames := strings.Split(strings.Trim(items[i], ledgerTagSeparator), ledgerTagSeparator)
+			tags = append(tags, tagNames...)
+		} else {
+			remainItems = append(remainItems, items[i])
+		}
+	}
+
+	remain := strings.Join(remainItems, " ")
+
+	if hledgerStyleTagsPattern.MatchString(remain) {
+		tagItems := strings.Split(remain, ",")
+
+		for i := 0; i < len(tagItems); i++ {
+			tagName := strings.TrimSuffix(strings.TrimSpace(tagItems[i]), ledgerTagSeparator)
+
+			if tagName != "" {
+				tags = append(tags, tagName)
+			}
+		}
+
+		return tags, metadata, ""
+	}
+
+	if matches := ledgerMetadataPattern.FindStringSubmatch(remain); len(matches) == 3 {
+		metadata[matches[1]] = strings.TrimSpace(matches[2])
+		return tags, metadata, ""
+	}
+
+	return tags, metadata, remain
+}
+
+// parseAmount returns the amount in decimal format and the commodity, the commodity can be the prefix or suffix of the amount
+func (r *ledgerDataReader) parseAmount(content string) (string, string, error) {
+	content = strings.TrimSpace(content)
+
+	if content == "" {
+		return "", "", errs.ErrAmountInvalid
+	}
+
+	if content[0] == '(' { // not support value expression
+		return "", "", errs.ErrAmountInvalid
+	}
+
+	negative := false
+
+	if content[0] == '-' || content[0] == '+' {
+		negative = content[0] == '-'
+		content = strings.TrimSpace(content[1:])
+	}
+
+	if content == "" {
+		return "", "", errs.ErrAmountInvalid
+	}
+
+	number := ""
+	commodity := ""
+	quotedCommodity := false
+
+	if content[0] == ledgerCommodityQuote { // quoted prefix commodity
+		quoteEndIndex := strings.IndexByte(content[1:], ledgerCommodityQuote)
+
+		if quoteEndIndex < 0 {
+			return "", "", errs.ErrAmountInvalid
+		}
+
+		commodity = content[1 : quoteEndIndex+1]
+		number = strings.TrimSpace(content[quoteEndIndex+2:])
+		quotedCommodity = true
+	} else if r.isNumberChar(content[0]) { // number with optional suffix commodity
+		numberEndIndex := len(content)
+
+		for i := 0; i < len(content); i++ {
+			if !r.isNumberChar(content[i]) {
+				numberEndIndex = i
+				break
+			}
+		}
+
+		number = content[:numberEndIndex]
+		commodity = strings.TrimSpace(content[numberEndIndex:])
+
+		if unquotedCommodity := r.unquoteCommodity(commodity); unquotedCommodity != commodity {
+			commodity = unquotedCommodity
+			quotedCommodity = true
+		}
+	} else { // prefix commodity
+		commodityEndIndex := len(content)
+
+		for i := 0; i < len(content); i++ {
+			if r.isNumberChar(content[i]) || content[i] == '-' || content[i] == '+' || content[i] == ' ' || content[i] == '\t' {
+				commodityEndIndex = i
+				break
+			}
+		}
+
+		commodity = content[:commodityEndIndex]
+		number = strings.TrimSpace(content[commodityEndIndex:])
+	}
+
+	if len(number) > 0 && (number[0] == '-' || number[0] == '+') {
+		if number[0] == '-' {
+			negative = !negative
+		}
+
+		number = strings.TrimSpace(number[1:])
+	}
+
+	// commodity which contains digits or whitespaces must be quoted
+	if !quotedCommodity && strings.ContainsAny(commodity, "0123456789 \t") {
+		return "", "", errs.ErrAmountInvalid
+	}
+
+	normalizedNumber, err := r.normalizeNumber(number)
+
+	if err != nil {
+		return "", "", err
+	}
+
+	if negative {
+		normalizedNumber = "-" + normalizedNumber
+	}
+
+	return normalizedNumber, commodity, nil
+}
+
+// normalizeNumber returns the number which uses dot as decimal mark and has no digit group separator
+func (r *ledgerDataReader) normalizeNumber(number string) (string, error) {
+	if number == "" {
+		return "", errs.ErrAmountInvalid
+	}
+
+	decimalMark := byte('.')
+	lastDotIndex := strings.LastIndexByte(number, '.')
+	lastCommaIndex := strings.LastIndexByte(number, ',')
+
+	// comma is the decimal mark when it is after all dots (e.g. "1.000,00"), or it is the only comma and not followed by three digits (e.g. "1,5")
+	if lastCommaIndex > lastDotIndex && (lastDotIndex >= 0 || (strings.Count(number, ",") == 1 && len(number)-lastCommaIndex-1 != 3)) {
+		decimalMark = ','
+	}
+
+	var ret strings.Builder
+	decimalMarkIndex := -1
+
+	for i := 0; i < len(number); i++ {
+		if '0' <= number[i] && number[i] <= '9' {
+			ret.WriteByte(number[i])
+		} else if number[i] == decimalMark {
+			if decimalMarkIndex >= 0 {
+				return "", errs.ErrAmountInvalid
+			}
+
+			decimalMarkIndex = ret.Len()
+			ret.WriteByte('.')
+		} else if number[i] != '.' && number[i] != ',' {
+			return "", errs.ErrAmountInvalid
+		}
+	}
+
+	result := ret.String()
+
+	if decimalMarkIndex >= 0 {
+		// remove the redundant trailing zeros (e.g. "1.000" to "1.00")
+		for len(result)-decimalMarkIndex-1 > 2 && result[len(result)-1] == '0' {
+			result = result[:len(result)-1]
+		}
+
+		if decimalMarkIndex == 0 {
+			result = "0" + result
+		}
+	}
+
+	if result == "" || result == "." {
+		return "", errs.ErrAmountInvalid
+	}
+
+	return result, nil
+}
+
+// parseDate returns the date in "YYYY-MM-DD" format, ledger supports dash, slash or dot as date separator
+func (r *ledgerDataReader) parseDate(content string) (string, error) {
+	if auxiliaryDateIndex := strings.IndexByte(content, ledgerAuxiliaryDateSeparator); auxiliaryDateIndex >= 0 {
+		content = content[:auxiliaryDateIndex]
+	}
+
+	items := strings.FieldsFunc(content, func(r rune) bool {
+		return r == '-' || r == '/' || r == '.'
+	})
+
+	if len(items) != 3 {
+		return "", errs.ErrTransactionTimeInvalid
+	}
+
+	year, err := strconv.Atoi(items[0])
+
+	if err != nil {
+		return "", err
+	}
+
+	month, err := strconv.Atoi(items[1])
+
+	if err != nil {
+		return "", err
+	}
+
+	day, err := strconv.Atoi(items[2])
+
+	if err != nil {
+		return "", err
+	}
+
+	date := fmt.Sprintf("%04d-%02d-%02d", year, month, day)
+
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		return "", err
+	}
+
+	return date, nil
+}
+
+func (r *ledgerDataReader) removeLotAnnotations(content string) string {
+	var ret strings.Builder
+	depth := 0
+
+	for i := 0; i < len(content); i++ {
+		ch := content[i]
+
+		if ch == '{' || ch == '[' || (ch == '(' && i > 0) {
+			depth++
+		} else if depth > 0 && (ch == '}' || ch == ']' || ch == ')') {
+			depth--
+		} else if depth == 0 {
+			ret.WriteByte(ch)
+		}
+	}
+
+	return strings.TrimSpace(ret.String())
+}
+
+// splitComment returns the content and the comment of the line, the comment starts with semicolon after whitespace
+func (r *ledgerDataReader) splitComment(line string) (string, string) {
+	for i := 1; i < len(line); i++ {
+		if line[i] == ledgerCommentPrefix && (line[i-1] == ' ' || line[i-1] == '\t') {
+			return strings.TrimSpace(line[:i]), strings.TrimSpace(line[i+1:])
+		}
+	}
+
+	return strings.TrimSpace(line), ""
+}
+
+// splitAccountNameAndAmount returns the account name and the amount part of the posting, the account name can contain single space, so they are separated by two or more spaces or tab
+func (r *ledgerDataReader) splitAccountNameAndAmount(content string) (string, string) {
+	for i := 0; i < len(content); i++ {
+		if content[i] == '\t' || (content[i] == ' ' && i+1 < len(content) && (content[i+1] == ' ' || content[i+1] == '\t')) {
+			return strings.TrimSpace(content[:i]), strings.TrimSpace(content[i:])
+		}
+	}
+
+	return strings.TrimSpace(content), ""
+}
+
+func (r *ledgerDataReader) splitFirstItem(content string) (string, string) {
+	index := strings.IndexAny(content, " \t")
+
+	if index < 0 {
+		return content, ""
+	}
+
+	return content[:index], strings.TrimSpace(content[index:])
+}
+
+func (r *ledgerDataReader) unquoteCommodity(commodity string) string {
+	if len(commodity) >= 2 && commodity[0] == ledgerCommodityQuote && commodity[len(commodity)-1] == ledgerCommodityQuote {
+		return commodity[1 : len(commodity)-1]
+	}
+
+	return commodity
+}
+
+func (r *ledgerDataReader) isNumberChar(ch byte) bool {
+	return ('0' <= ch && ch <= '9') || ch == '.' || ch == ','
+}
+
+func createNewLedgerDataReader(ctx core.Context, data []byte) (*ledgerDataReader, error) {
+	fallback := unicode.UTF8.NewDecoder()
+	reader := transform.NewReader(bytes.NewReader(data), unicode.BOMOverride(fallback))
+	content, err := io.ReadAll(reader)
+
+	if err != nil {
+		log.Errorf(ctx, "[ledger_data_reader.createNewLedgerDataReader] cannot read data, because %s", err.Error())
+		return nil, errs.ErrInvalidLedgerFile
+	}
+
+	allLines := make([]string, 0)
+
+	if len(strings.TrimSpace(string(content))) > 0 {
+		allLines = strings.Split(string(content), "\n")
+
+		for i := 0; i < len(allLines); i++ {
+			allLines[i] = strings.TrimRight(allLines[i], "\r")
+		}
+	}
+
+	return &ledgerDataReader{
+		allLines: allLines,
+	}, nil
+}
@@ -0,0 +1,269 @@
+package ledger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+)
+
+func TestLedgerDataReaderRead(t *testing.T) {
+	context := core.NewNullContext()
+	reader, err := createNewLedgerDataReader(context, []byte(""+
+		"; Test Ledger Data\n"+
+		"# another comment\n"+
+		"comment\n"+
+		"2024/01/01 * This is not a transaction\n"+
+		"    Assets:Foo  1 CNY\n"+
+		"end comment\n"+
+		"\n"+
+		"commodity CNY\n"+
+		"    format 1,000.00 CNY\n"+
+		"commodity $1,000.00\n"+
+		"\n"+
+		"account Assets:Test Account\n"+
+		"    note This is a test account\n"+
+		"account Bank:Credit Card  ; type: L\n"+
+		"account Salary\n"+
+		"    ; type:R\n"+
+		"\n"+
+		"P 2024/01/01 USD 7.10 CNY\n"+
+		"\n"+
+		"2024/01/05=2024/01/06 * (1001) Payee Name  ; :tag1:tag2:\n"+
+		"    ; Foo Bar\n"+
+		"    ; Key: Value\n"+
+		"    Salary                           -123.45 CNY\n"+
+		"    Assets:Test Account               123.45 CNY  ; :tag3:\n"+
+		"2024.1.6 ! Test Payee\n"+
+		"    ; tag4:, tag5:\n"+
+		"    Bank:Credit Card               $-1,234.56\n"+
+		"    Expenses:Test Category 2\n"+
+		"\n"+
+		"~ monthly\n"+
+		"    Expenses:Test Category 2        $100\n"+
+		"    Assets:Test Account\n"+
+		"\n"+
+		"= /Expenses/\n"+
+		"    (Budget)                          -1\n"+
+		"\n"+
+		"2024-01-07 Test\n"+
+		"    Assets:Test Account              -10 USD @ 7.1 CNY\n"+
+		"    [Assets:Test Account 2]\n"+
+		"    (Budget:Test)                    10 USD\n"+
+		"2024-01-08\n"+
+		"    Assets:Test Account             -100.00 CNY @@ 14.00 USD = 1,013.45 CNY\n"+
+		"    Assets:Test Account 3             14.00 USD\n"))
+	assert.Nil(t, err)
+
+	actualData, err := reader.read(context)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 2, len(actualData.Commodities))
+	assert.True(t, actualData.Commodities["CNY"])
+	assert.True(t, actualData.Commodities["$"])
+
+	assert.Equal(t, 6, len(actualData.Accounts))
+	assert.Equal(t, ledgerAssetsAccountType, actualData.Accounts["Assets:Test Account"].AccountType)
+	assert.Equal(t, ledgerLiabilitiesAccountType, actualData.Accounts["Bank:Credit Card"].AccountType)
+	assert.Equal(t, ledgerIncomeAccountType, actualData.Accounts["Salary"].AccountType)
+	assert.Equal(t, ledgerExpensesAccountType, actualData.Accounts["Expenses:Test Category 2"].AccountType)
+	assert.Equal(t, ledgerAssetsAccountType, actualData.Accounts["Assets:Test Account 2"].AccountType)
+	assert.Equal(t, ledgerAssetsAccountType, actualData.Accounts["Assets:Test Account 3"].AccountType)
+
+	assert.Equal(t, 4, len(actualData.Transactions))
+
+	assert.Equal(t, "2024-01-05", actualData.Transactions[0].Date)
+	assert.Equal(t, ledgerTransactionStatusCleared, actualData.Transactions[0].Status)
+	assert.Equal(t, "1001", actualData.Transactions[0].Code)
+	assert.Equal(t, "Payee Name", actualData.Transactions[0].Payee)
+	assert.Equal(t, []string{"Foo Bar"}, actualData.Transactions[0].Notes)
+	assert.Equal(t, []string{"tag1", "tag2", "tag3"}, actualData.Transactions[0].Tags)
+	assert.Equal(t, "Value", actualData.Transactions[0].Metadata["Key"])
+	assert.Equal(t, 2, len(actualData.Transactions[0].Postings))
+	assert.Equal(t, "Salary", actualData.Transactions[0].Postings[0].Account)
+	assert.Equal(t, "-123.45", actualData.Transactions[0].Postings[0].Amount)
+	assert.Equal(t, "CNY", actualData.Transactions[0].Postings[0].Commodity)
+	assert.Equal(t, "Assets:Test Account", actualData.Transactions[0].Postings[1].Account)
+	assert.Equal(t, "123.45", actualData.Transactions[0].Postings[1].Amount)
+	assert.Equal(t, "CNY", actualData.Transactions[0].Postings[1].Commodity)
+
+	assert.Equal(t, "2024-01-06", actualData.Transactions[1].Date)
+	assert.Equal(t, ledgerTransactionStatusPending, actualData.Transactions[1].Status)
+	assert.Equal(t, "Test Payee", actualData.Transactions[1].Payee)
+	assert.Equal(t, []string{"tag4", "tag5"}, actualData.Transactions[1].Tags)
+	assert.Equal(t, 2, len(actualData.Transactions[1].Postings))
+	assert.Equal(t, "-1234.56", actualData.Transactions[1].Postings[0].Amount)
+	assert.Equal(t, "$", actualData.Transactions[1].Postings[0].Commodity)
+	assert.Equal(t, "1234.56", actualData.Transactions[1].Postings[1].Amount)
+	assert.Equal(t, "$", actualData.Transactions[1].Postings[1].Commodity)
+
+	assert.Equal(t, "2024-01-07", actualData.Transactions[2].Date)
+	assert.Equal(t, ledgerTransactionStatusUncleared, actualData.Transactions[2].Status)
+	assert.Equal(t, 2, len(actualData.Transactions[2].Postings))
+	assert.Equal(t, "-10", actualData.Transactions[2].Postings[0].Amount)
+	assert.Equal(t, "USD", actualData.Transactions[2].Postings[0].Commodity)
+	assert.Equal(t, "7.1", actualData.Transactions[2].Postings[0].Price)
+	assert.Equal(t, "CNY", actualData.Transactions[2].Postings[0].PriceCommodity)
+	assert.Equal(t, "Assets:Test Account 2", actualData.Transactions[2].Postings[1].Account)
+	assert.Equal(t, "71.00", actualData.Transactions[2].Postings[1].Amount)
+	assert.Equal(t, "CNY", actualData.Transactions[2].Postings[1].Commodity)
+
+	assert.Equal(t, "2024-01-08", actualData.Transactions[3].Date)
+	assert.Equal(t, "-100.00", actualData.Transactions[3].Postings[0].Amount)
+	assert.Equal(t, "CNY", actualData.Transactions[3].Postings[0].Commodity)
+	assert.Equal(t, "14.00", actualData.Transactions[3].Postings[0].TotalCost)
+	assert.Equal(t, "USD", actualData.Transactions[3].Postings[0].TotalCostCommodity)
+	assert.Equal(t, "14.00", actualData.Transactions[3].Postings[1].Amount)
+	assert.Equal(t, "USD", actualData.Transactions[3].Postings[1].Commodity)
+}
+
+func TestLedgerDataReaderRead_EmptyContent(t *testing.T) {
+	context := core.NewNullContext()
+	reader, err := createNewLedgerDataReader(context, []byte(""))
+	assert.Nil(t, err)
+
+	_, err = reader.read(context)
+	assert.EqualError(t, err, errs.ErrNotFoundTransactionDataInFile.Message)
+}
+
+func TestLedgerDataReaderRead_NotSupportInclude(t *testing.T) {
+	context := core.NewNullContext()
+	reader, err := createNewLedgerDataReader(context, []byte("include other.journal\n"))
+	assert.Nil(t, err)
+
+	_, err = reader.read(context)
+	assert.EqualError(t, err, errs.ErrLedgerFileNotSupportInclude.Message)
+}
+
+func TestLedgerDataReaderRead_InvalidDate(t *testing.T) {
+	context := core.NewNullContext()
+	reader, err := createNewLedgerDataReader(context, []byte(""+
+		"2024-13-01 * Test\n"+
+		"    Assets:Test  1 CNY\n"+
+		"    Income:Test\n"))
+	assert.Nil(t, err)
+
+	_, err = reader.read(context)
+	assert.EqualError(t, err, errs.ErrTransactionTimeInvalid.Message)
+}
+
+func TestLedgerDataReaderRead_MultipleElidedAmounts(t *testing.T) {
+	context := core.NewNullContext()
+	reader, err := createNewLedgerDataReader(context, []byte(""+
+		"2024-01-01 * Test\n"+
+		"    Assets:Test\n"+
+		"    Income:Test\n"))
+	assert.Nil(t, err)
+
+	_, err = reader.read(context)
+	assert.EqualError(t, err, errs.ErrInvalidLedgerFile.Message)
+}
+
+func TestLedgerDataReaderRead_ElidedAmountWithMultipleCommodities(t *testing.T) {
+	context := core.NewNullContext()
+	reader, err := createNewLedgerDataReader(context, []byte(""+
+		"2024-01-01 * Test\n"+
+		"    Assets:Test  1 CNY\n"+
+		"    Assets:Test2  1 USD\n"+
+		"    Income:Test\n"))
+	assert.Nil(t, err)
+
+	_, err = reader.read(context)
+	assert.EqualError(t, err, errs.ErrInvalidLedgerFile.Message)
+}
+
+func TestLedgerDataReaderRead_InvalidAmount(t *testing.T) {
+	context := core.NewNullContext()
+	reader, err := createNewLedgerDataReader(context, []byte(""+
+		"2024-01-01 * Test\n"+
+		"    Assets:Test  ($1 * 2)\n"+
+		"    Income:Test\n"))
+	assert.Nil(t, err)
+
+	_, err = reader.read(context)
+	assert.EqualError(t, err, errs.ErrAmountInvalid.Message)
+}
+
+func TestLedgerDataReaderParseAmount(t *testing.T) {
+	reader := &ledgerDataReader{}
+
+	testCases := []struct {
+		content   string
+		amount    string
+		commodity string
+	}{
+		{"123.45 CNY", "123.45", "CNY"},
+		{"-123.45 CNY", "-123.45", "CNY"},
+		{"123.45CNY", "123.45", "CNY"},
+		{"$123.45", "123.45", "$"},
+		{"-$123.45", "-123.45", "$"},
+		{"$-123.45", "-123.45", "$"},
+		{"$ 123.45", "123.45", "$"},
+		{"€1.234,56", "1234.56", "€"},
+		{"1,234,567.8 EUR", "1234567.8", "EUR"},
+		{"1,5 EUR", "1.5", "EUR"},
+		{"1,000 EUR", "1000", "EUR"},
+		{"10.000 USD", "10.00", "USD"},
+		{".5 USD", "0.5", "USD"},
+		{"\"ABC 1\" 10", "10", "ABC 1"},
+		{"10 \"ABC 1\"", "10", "ABC 1"},
+		{"100", "100", ""},
+	}
+
+	for _, testCase := range testCases {
+		amount, commodity, err := reader.parseAmount(testCase.content)
+		assert.Nil(t, err, testCase.content)
+		assert.Equal(t, testCase.amount, amount, testCase.content)
+		assert.Equal(t, testCase.commodity, commodity, testCase.content)
+	}
+}
+
+func TestLedgerDataReaderParseAmount_InvalidAmount(t *testing.T) {
+	reader := &ledgerDataReader{}
+
+	_, _, err := reader.parseAmount("")
+	assert.NotNil(t, err)
+
+	_, _, err = reader.parseAmount("$")
+	assert.NotNil(t, err)
+
+	_, _, err = reader.parseAmount("(1 + 2) USD")
+	assert.NotNil(t, err)
+
+	_, _, err = reader.parseAmount("1.2.3 USD")
+	assert.NotNil(t, err)
+
+	_, _, err = reader.parseAmount("1a USD")
+	assert.NotNil(t, err)
+}
+
+func TestLedgerDataReaderParseComment(t *testing.T) {
+	reader := &ledgerDataReader{}
+
+	tags, metadata, note := reader.parseComment(":tag1:tag2:")
+	assert.Equal(t, []string{"tag1", "tag2"}, tags)
+	assert.Equal(t, 0, len(metadata))
+	assert.Equal(t, "", note)
+
+	tags, metadata, note = reader.parseComment("dinner :tag1: with friends")
+	assert.Equal(t, []string{"tag1"}, tags)
+	assert.Equal(t, 0, len(metadata))
+	assert.Equal(t, "dinner with friends", note)
+
+	tags, metadata, note = reader.parseComment("tag1:, tag2:")
+	assert.Equal(t, []string{"tag1", "tag2"}, tags)
+	assert.Equal(t, 0, len(metadata))
+	assert.Equal(t, "", note)
+
+	tags, metadata, note = reader.parseComment("Payee: Foo Bar")
+	assert.Equal(t, 0, len(tags))
+	assert.Equal(t, "Foo Bar", metadata["Payee"])
+	assert.Equal(t, "", note)
+
+	tags, metadata, note = reader.parseComment("just a note")
+	assert.Equal(t, 0, len(tags))
+	assert.Equal(t, 0, len(metadata))
+	assert.Equal(t, "just a note", note)
+}
@@ -0,0 +1,47 @@
+package ledger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLedgerAccount_IsOpeningBalanceEquityAccount_True(t *testing.T) {
+	account := ledgerAccount{
+		AccountType: ledgerEquityAccountType,
+		Name:        "Equity:Opening Balances",
+	}
+	assert.True(t, account.isOpeningBalanceEquityAccount())
+
+	account = ledgerAccount{
+		AccountType: ledgerEquityAccountType,
+		Name:        "equity:opening balances",
+	}
+	assert.True(t, account.isOpeningBalanceEquityAccount())
+
+	account = ledgerAccount{
+		AccountType: ledgerEquityAccountType,
+		Name:        "Equity:Opening-Balances",
+	}
+	assert.True(t, account.isOpeningBalanceEquityAccount())
+}
+
+func TestLedgerAccount_IsOpeningBalanceEquityAccount_False(t *testing.T) {
+	account := ledgerAccount{
+		AccountType: ledgerAssetsAccountType,
+		Name:        "Equity:Opening Balances",
+	}
+	assert.False(t, account.isOpeningBalanceEquityAccount())
+
+	account = ledgerAccount{
+		AccountType: ledgerEquityAccountType,
+		Name:        "Opening Balances",
+	}
+	assert.False(t, account.isOpeningBalanceEquityAccount())
+
+	account = ledgerAccount{
+		AccountType: ledgerEquityAccountType,
+		Name:        "Equity:Other",
+	}
+	assert.False(t, account.isOpeningBalanceEquityAccount())
+}
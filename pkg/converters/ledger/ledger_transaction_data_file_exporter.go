@@ -0,0 +1,405 @@
+package ledger
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/converter"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const ledgerDefaultAssetsAccountTypeName = "Assets"
+const ledgerDefaultLiabilitiesAccountTypeName = "Liabilities"
+const ledgerDefaultEquityAccountTypeName = "Equity"
+const ledgerDefaultIncomeAccountTypeName = "Income"
+const ledgerDefaultExpensesAccountTypeName = "Expenses"
+
+const ledgerExportedDateFormat = "2006-01-02"
+const ledgerExportedUnnamedAccountName = "Unnamed"
+const ledgerExportedPostingIndent = "    "
+const ledgerExportedAmountSeparator = "  "
+
+var ledgerExportedAccountNameItemReplacer = strings.NewReplacer(ledgerAccountNameItemsSeparator, "-", string(ledgerCommentPrefix), "-")
+
+// ledgerExportedPosting defines the structure of exported ledger posting
+type ledgerExportedPosting struct {
+	account   string
+	amount    string
+	commodity string
+	totalCost string
+}
+
+// ledgerTransactionDataExporter defines the structure of ledger exporter for transaction data
+type ledgerTransactionDataExporter struct {
+}
+
+// Initialize a ledger transaction data exporter singleton instance
+var (
+	LedgerTransactionDataExporter = &ledgerTransactionDataExporter{}
+)
+
+// ToExportedContent returns the exported ledger journal data, which can be read by both ledger-cli and hledger,
+// it writes commodity and account directives for all used commodities and accounts before all transactions
+func (c *ledgerTransactionDataExporter) ToExportedContent(ctx core.Context, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64, customFields []*models.TransactionCustomField, allCustomFieldValues map[int64][]*models.TransactionCustomFieldValue, options converter.TransactionDataExporterOptions) ([]byte, error) {
+	accountNames := c.buildAccountNames(accountMap)
+	categoryNames := c.buildCategoryNames(categoryMap)
+	openingBalanceAccountName := ledgerDefaultEquityAccountTypeName + ledgerAccountNameItemsSeparator + ledgerEquityAccountNameOpeningBalance
+
+	allTransactions := make([]*models.Transaction, 0, len(transactions))
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+			continue
+		}
+
+		if _, exists := accountMap[transaction.AccountId]; !exists {
+			log.Warnf(ctx, "[ledger_transaction_data_file_exporter.ToExportedContent] account \"id:%d\" of transaction \"id:%d\" does not exist", transaction.AccountId, transaction.TransactionId)
+			continue
+		}
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+			if _, exists := accountMap[transaction.RelatedAccountId]; !exists {
+				log.Warnf(ctx, "[ledger_transaction_data_file_exporter.ToExportedContent] related account \"id:%d\" of transaction \"id:%d\" does not exist", transaction.RelatedAccountId, transaction.TransactionId)
+				continue
+			}
+		}
+
+		allTransactions = append(allTransactions, transaction)
+	}
+
+	if len(allTransactions) < 1 {
+		return nil, nil
+	}
+
+	sort.SliceStable(allTransactions, func(i, j int) bool {
+		return allTransactions[i].TransactionTime < allTransactions[j].TransactionTime
+	})
+
+	transactionContents := make([]string, 0, len(allTransactions))
+	usedAccountNames := make(map[string]bool)
+	usedCommodities := make(map[string]bool)
+
+	for i := 0; i < len(allTransactions); i++ {
+		transaction := allTransactions[i]
+		account := accountMap[transaction.AccountId]
+		accountName := accountNames[account.AccountId]
+		var postings []*ledgerExportedPosting
+
+		switch transaction.Type {
+		case models.TRANSACTION_DB_TYPE_MODIFY_BALANCE:
+			postings = []*ledgerExportedPosting{
+				{account: openingBalanceAccountName, amount: utils.FormatAmount(-transaction.RelatedAccountAmount), commodity: account.Currency},
+				{account: accountName, amount: utils.FormatAmount(transaction.RelatedAccountAmount), commodity: account.Currency},
+			}
+		case models.TRANSACTION_DB_TYPE_INCOME:
+			postings = []*ledgerExportedPosting{
+				{account: c.getCategoryName(transaction.CategoryId, categoryNames, ledgerDefaultIncomeAccountTypeName), amount: utils.FormatAmount(-transaction.Amount), commodity: account.Currency},
+				{account: accountName, amount: utils.FormatAmount(transaction.Amount), commodity: account.Currency},
+			}
+		case models.TRANSACTION_DB_TYPE_EXPENSE:
+			postings = []*ledgerExportedPosting{
+				{account: accountName, amount: utils.FormatAmount(-transaction.Amount), commodity: account.Currency},
+				{account: c.getCategoryName(transaction.CategoryId, categoryNames, ledgerDefaultExpensesAccountTypeName), amount: utils.FormatAmount(transaction.Amount), commodity: account.Currency},
+			}
+		case models.TRANSACTION_DB_TYPE_TRANSFER_OUT:
+			relatedAccount := accountMap[transaction.RelatedAccountId]
+			fromPosting := &ledgerExportedPosting{account: accountName, amount: utils.FormatAmount(-transaction.Amount), commodity: account.Currency}
+			toPosting := &ledgerExportedPosting{account: accountNames[relatedAccount.AccountId], amount: utils.FormatAmount(transaction.RelatedAccountAmount), commodity: relatedAccount.Currency}
+
+			if account.Currency != relatedAccount.Currency {
+				fromPosting.totalCost = utils.FormatAmount(transaction.RelatedAccountAmount) + " " + relatedAccount.Currency
+			}
+
+			postings = []*ledgerExportedPosting{fromPosting, toPosting}
+		default:
+			continue
+		}
+
+		for j := 0; j < len(postings); j++ {
+			usedAccountNames[postings[j].account] = true
+			usedCommodities[postings[j].commodity] = true
+		}
+
+		transactionContents = append(transactionContents, c.getTransactionContent(transaction, options.GetTransactionTimezone(transaction), postings, tagMap, allTagIndexes))
+	}
+
+	var ret bytes.Buffer
+
+	for _, commodity := range c.getSortedKeys(usedCommodities) {
+		ret.WriteString("commodity ")
+		ret.WriteString(commodity)
+		ret.WriteString("\n")
+	}
+
+	ret.WriteString("\n")
+
+	for _, accountName := range c.getSortedKeys(usedAccountNames) {
+		ret.WriteString("account ")
+		ret.WriteString(accountName)
+		ret.WriteString("\n")
+	}
+
+	for i := 0; i < len(transactionContents); i++ {
+		ret.WriteString("\n")
+		ret.WriteString(transactionContents[i])
+	}
+
+	return ret.Bytes(), nil
+}
+
+func (c *ledgerTransactionDataExporter) getTransactionContent(transaction *models.Transaction, timezone *time.Location, postings []*ledgerExportedPosting, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64) string {
+	var ret strings.Builder
+	payee, notes := c.getPayeeAndNotes(transaction.Comment)
+
+	ret.WriteString(c.formatDate(transaction, timezone))
+	ret.WriteString(" ")
+	ret.WriteString(string(ledgerTransactionStatusCleared))
+
+	if payee != "" {
+		ret.WriteString(" ")
+		ret.WriteString(payee)
+	}
+
+	ret.WriteString("\n")
+
+	tagNames := c.getTagNames(transaction.TransactionId, tagMap, allTagIndexes)
+
+	if len(tagNames) > 0 {
+		ret.WriteString(ledgerExportedPostingIndent)
+		ret.WriteRune(ledgerCommentPrefix)
+		ret.WriteString(" ")
+		ret.WriteString(ledgerTagSeparator)
+		ret.WriteString(strings.Join(tagNames, ledgerTagSeparator))
+		ret.WriteString(ledgerTagSeparator)
+		ret.WriteString("\n")
+	}
+
+	for i := 0; i < len(notes); i++ {
+		ret.WriteString(ledgerExportedPostingIndent)
+		ret.WriteRune(ledgerCommentPrefix)
+		ret.WriteString(" ")
+		ret.WriteString(notes[i])
+		ret.WriteString("\n")
+	}
+
+	maxAccountNameLength := 0
+	maxAmountLength := 0
+
+	for i := 0; i < len(postings); i++ {
+		maxAccountNameLength = max(maxAccountNameLength, utf8.RuneCountInString(postings[i].account))
+		maxAmountLength = max(maxAmountLength, len(postings[i].amount))
+	}
+
+	for i := 0; i < len(postings); i++ {
+		posting := postings[i]
+
+		ret.WriteString(ledgerExportedPostingIndent)
+		ret.WriteString(posting.account)
+		ret.WriteString(strings.Repeat(" ", maxAccountNameLength-utf8.RuneCountInString(posting.account)))
+		ret.WriteString(ledgerExportedAmountSeparator)
+		ret.WriteString(strings.Repeat(" ", maxAmountLength-len(posting.amount)))
+		ret.WriteString(posting.amount)
+		ret.WriteString(" ")
+		ret.WriteString(posting.commodity)
+
+		if posting.totalCost != "" {
+			ret.WriteString(" ")
+			ret.WriteString(ledgerTotalCostPrefix)
+			ret.WriteString(" ")
+			ret.WriteString(posting.totalCost)
+		}
+
+		ret.WriteString("\n")
+	}
+
+	return ret.String()
+}
+
+// getPayeeAndNotes returns the first line of comment as payee and the remaining lines as notes
+func (c *ledgerTransactionDataExporter) getPayeeAndNotes(comment string) (string, []string) {
+	lines := strings.Split(strings.ReplaceAll(comment, "\r\n", "\n"), "\n")
+	payee := ""
+	notes := make([]string, 0, len(lines))
+
+	for i := 0; i < len(lines); i++ {
+		// whitespaces are collapsed, because two or more spaces followed by semicolon are treated as the start of comment
+		line := strings.Join(strings.Fields(lines[i]), " ")
+
+		if line == "" {
+			continue
+		}
+
+		if i == 0 {
+			payee = line
+		} else {
+			notes = append(notes, line)
+		}
+	}
+
+	return payee, notes
+}
+
+func (c *ledgerTransactionDataExporter) getTagNames(transactionId int64, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64) []string {
+	tagIndexes := allTagIndexes[transactionId]
+	tagNames := make([]string, 0, len(tagIndexes))
+
+	for i := 0; i < len(tagIndexes); i++ {
+		tag, exists := tagMap[tagIndexes[i]]
+
+		if !exists {
+			continue
+		}
+
+		tagName := c.getTagName(tag.Name)
+
+		if tagName != "" {
+			tagNames = append(tagNames, tagName)
+		}
+	}
+
+	return tagNames
+}
+
+func (c *ledgerTransactionDataExporter) buildAccountNames(accountMap map[int64]*models.Account) map[int64]string {
+	accountIds := make([]int64, 0, len(accountMap))
+
+	for accountId := range accountMap {
+		accountIds = append(accountIds, accountId)
+	}
+
+	sort.Slice(accountIds, func(i, j int) bool {
+		return accountIds[i] < accountIds[j]
+	})
+
+	accountNames := make(map[int64]string, len(accountMap))
+	usedAccountNames := make(map[string]bool, len(accountMap))
+
+	for i := 0; i < len(accountIds); i++ {
+		account := accountMap[accountIds[i]]
+		topLevelAccount := account
+		nameItems := []string{c.getAccountNameItem(account.Name)}
+
+		if account.ParentAccountId != models.LevelOneAccountParentId {
+			if parentAccount, exists := accountMap[account.ParentAccountId]; exists {
+				topLevelAccount = parentAccount
+				nameItems = append([]string{c.getAccountNameItem(parentAccount.Name)}, nameItems...)
+			}
+		}
+
+		accountTypeName := ledgerDefaultAssetsAccountTypeName
+
+		if topLevelAccount.Category.IsLiability() {
+			accountTypeName = ledgerDefaultLiabilitiesAccountTypeName
+		}
+
+		accountName := accountTypeName + ledgerAccountNameItemsSeparator + strings.Join(nameItems, ledgerAccountNameItemsSeparator)
+
+		if usedAccountNames[accountName] {
+			accountName = accountName + " " + utils.Int64ToString(account.AccountId)
+		}
+
+		accountNames[account.AccountId] = accountName
+		usedAccountNames[accountName] = true
+	}
+
+	return accountNames
+}
+
+func (c *ledgerTransactionDataExporter) buildCategoryNames(categoryMap map[int64]*models.TransactionCategory) map[int64]string {
+	categoryIds := make([]int64, 0, len(categoryMap))
+
+	for categoryId := range categoryMap {
+		categoryIds = append(categoryIds, categoryId)
+	}
+
+	sort.Slice(categoryIds, func(i, j int) bool {
+		return categoryIds[i] < categoryIds[j]
+	})
+
+	categoryNames := make(map[int64]string, len(categoryMap))
+	usedCategoryNames := make(map[string]bool, len(categoryMap))
+
+	for i := 0; i < len(categoryIds); i++ {
+		category := categoryMap[categoryIds[i]]
+		nameItems := []string{c.getAccountNameItem(category.Name)}
+
+		if category.ParentCategoryId != models.LevelOneTransactionCategoryParentId {
+			if parentCategory, exists := categoryMap[category.ParentCategoryId]; exists {
+				nameItems = append([]string{c.getAccountNameItem(parentCategory.Name)}, nameItems...)
+			}
+		}
+
+		categoryName := strings.Join(nameItems, ledgerAccountNameItemsSeparator)
+
+		if usedCategoryNames[categoryName] {
+			categoryName = categoryName + " " + utils.Int64ToString(category.CategoryId)
+		}
+
+		categoryNames[category.CategoryId] = categoryName
+		usedCategoryNames[categoryName] = true
+	}
+
+	return categoryNames
+}
+
+func (c *ledgerTransactionDataExporter) getCategoryName(categoryId int64, categoryNames map[int64]string, accountTypeName string) string {
+	categoryName, exists := categoryNames[categoryId]
+
+	if !exists {
+		categoryName = ledgerExportedUnnamedAccountName
+	}
+
+	return accountTypeName + ledgerAccountNameItemsSeparator + categoryName
+}
+
+// getAccountNameItem returns the account name item without account separator, semicolon, consecutive whitespaces and leading virtual account brackets
+func (c *ledgerTransactionDataExporter) getAccountNameItem(name string) string {
+	item := strings.Join(strings.Fields(ledgerExportedAccountNameItemReplacer.Replace(name)), " ")
+	item = strings.TrimLeft(item, string(ledgerVirtualAccountPrefix)+string(ledgerBalancedVirtualAccountPrefix)+" ")
+
+	if item == "" {
+		return ledgerExportedUnnamedAccountName
+	}
+
+	return item
+}
+
+// getTagName returns the tag name without colons and whitespaces
+func (c *ledgerTransactionDataExporter) getTagName(name string) string {
+	var ret strings.Builder
+
+	for _, ch := range strings.TrimSpace(name) {
+		if unicode.IsSpace(ch) || ch == ':' {
+			ret.WriteRune('-')
+		} else {
+			ret.WriteRune(ch)
+		}
+	}
+
+	return ret.String()
+}
+
+func (c *ledgerTransactionDataExporter) getSortedKeys(items map[string]bool) []string {
+	keys := make([]string, 0, len(items))
+
+	for key := range items {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+func (c *ledgerTransactionDataExporter) formatDate(transaction *models.Transaction, timezone *time.Location) string {
+	return time.Unix(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime), 0).In(timezone).Format(ledgerExportedDateFormat)
+}
@@ -0,0 +1,332 @@
+package ledger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/converter"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+func TestLedgerTransactionDataExporterToExportedContent(t *testing.T) {
+	exporter := LedgerTransactionDataExporter
+	context := core.NewNullContext()
+	transactions, accountMap, categoryMap, tagMap, allTagIndexes := createTestLedgerExportedTransactions()
+
+	actualContent, err := exporter.ToExportedContent(context, 123, transactions, accountMap, categoryMap, tagMap, allTagIndexes, nil, nil, converter.DefaultExporterOptions)
+	assert.Nil(t, err)
+
+	expectedContent := "commodity CNY\n" +
+		"commodity USD\n" +
+		"\n" +
+		"account Assets:Test Account\n" +
+		"account Assets:Test Account:USD Cash\n" +
+		"account Equity:Opening Balances\n" +
+		"account Expenses:Test Category2\n" +
+		"account Income:Test Category:Test Sub Category\n" +
+		"account Liabilities:Test Credit Card\n" +
+		"\n" +
+		"2024-08-31 *\n" +
+		"    Equity:Opening Balances  -1000.00 CNY\n" +
+		"    Assets:Test Account       1000.00 CNY\n" +
+		"\n" +
+		"2024-09-01 * Foo \"Bar\"\n" +
+		"    ; :Tag-1:Tag2:\n" +
+		"    ; second line\n" +
+		"    Income:Test Category:Test Sub Category  -123.45 CNY\n" +
+		"    Assets:Test Account                      123.45 CNY\n" +
+		"\n" +
+		"2024-09-01 *\n" +
+		"    Assets:Test Account      -0.10 CNY\n" +
+		"    Expenses:Test Category2   0.10 CNY\n" +
+		"\n" +
+		"2024-09-01 *\n" +
+		"    ; :Tag2:\n" +
+		"    Assets:Test Account           -17.35 CNY\n" +
+		"    Liabilities:Test Credit Card   17.35 CNY\n" +
+		"\n" +
+		"2024-09-01 *\n" +
+		"    Assets:Test Account           -100.00 CNY @@ 14.00 USD\n" +
+		"    Assets:Test Account:USD Cash    14.00 USD\n"
+
+	assert.Equal(t, expectedContent, string(actualContent))
+}
+
+func TestLedgerTransactionDataExporterToExportedContent_EscapeSpecialCharacters(t *testing.T) {
+	exporter := LedgerTransactionDataExporter
+	importer := LedgerTransactionDataImporter
+	context := core.NewNullContext()
+
+	transactions := []*models.Transaction{
+		{
+			TransactionId:     1,
+			TransactionTime:   1725165296000,
+			Type:              models.TRANSACTION_DB_TYPE_INCOME,
+			TimezoneUtcOffset: 480,
+			CategoryId:        12,
+			AccountId:         1,
+			Amount:            12345,
+			Comment:           "Foo  \"Bar\"\n\nsecond;line",
+		},
+	}
+	accountMap := map[int64]*models.Account{
+		1: {AccountId: 1, Category: models.ACCOUNT_CATEGORY_CHECKING_ACCOUNT, Name: "Bank of \"China\"", Currency: "CNY"},
+	}
+	categoryMap := map[int64]*models.TransactionCategory{
+		11: {CategoryId: 11, Name: "Test Category", ParentCategoryId: models.LevelOneTransactionCategoryParentId},
+		12: {CategoryId: 12, Name: "Sub: Category", ParentCategoryId: 11},
+	}
+	tagMap := map[int64]*models.TransactionTag{
+		21: {TagId: 21, Name: "Tag 1"},
+		22: {TagId: 22, Name: "Tag:#2"},
+	}
+	allTagIndexes := map[int64][]int64{
+		1: {21, 22},
+	}
+
+	actualContent, err := exporter.ToExportedContent(context, 123, transactions, accountMap, categoryMap, tagMap, allTagIndexes, nil, nil, converter.DefaultExporterOptions)
+	assert.Nil(t, err)
+
+	expectedContent := "commodity CNY\n" +
+		"\n" +
+		"account Assets:Bank of \"China\"\n" +
+		"account Income:Test Category:Sub- Category\n" +
+		"\n" +
+		"2024-09-01 * Foo \"Bar\"\n" +
+		"    ; :Tag-1:Tag-#2:\n" +
+		"    ; second;line\n" +
+		"    Income:Test Category:Sub- Category  -123.45 CNY\n" +
+		"    Assets:Bank of \"China\"               123.45 CNY\n"
+
+	assert.Equal(t, expectedContent, string(actualContent))
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	allNewTransactions, _, _, _, _, _, err := importer.ParseImportedData(context, user, actualContent, time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 1, len(allNewTransactions))
+	assert.Equal(t, "Assets:Bank of \"China\"", allNewTransactions[0].OriginalSourceAccountName)
+	assert.Equal(t, []string{"Tag-1", "Tag-#2"}, allNewTransactions[0].OriginalTagNames)
+	assert.Equal(t, "Foo \"Bar\"\nsecond;line", allNewTransactions[0].Comment)
+}
+
+func TestLedgerTransactionDataExporterToExportedContent_EmptyTransactions(t *testing.T) {
+	exporter := LedgerTransactionDataExporter
+	context := core.NewNullContext()
+
+	actualContent, err := exporter.ToExportedContent(context, 123, nil, nil, nil, nil, nil, nil, nil, converter.DefaultExporterOptions)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(actualContent))
+}
+
+func TestLedgerTransactionDataExporterToExportedContent_ImportExportedContent(t *testing.T) {
+	exporter := LedgerTransactionDataExporter
+	importer := LedgerTransactionDataImporter
+	context := core.NewNullContext()
+	transactions, accountMap, categoryMap, tagMap, allTagIndexes := createTestLedgerExportedTransactions()
+
+	actualContent, err := exporter.ToExportedContent(context, 123, transactions, accountMap, categoryMap, tagMap, allTagIndexes, nil, nil, converter.DefaultExporterOptions)
+	assert.Nil(t, err)
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	allNewTransactions, allNewAccounts, allNewSubExpenseCategories, allNewSubIncomeCategories, _, allNewTags, err := importer.ParseImportedData(context, user, actualContent, time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 5, len(allNewTransactions))
+	assert.Equal(t, 3, len(allNewAccounts))
+	assert.Equal(t, 1, len(allNewSubExpenseCategories))
+	assert.Equal(t, 1, len(allNewSubIncomeCategories))
+	assert.Equal(t, 2, len(allNewTags))
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_MODIFY_BALANCE, allNewTransactions[0].Type)
+	assert.Equal(t, int64(1725062400), utils.GetUnixTimeFromTransactionTime(allNewTransactions[0].TransactionTime))
+	assert.Equal(t, int64(100000), allNewTransactions[0].Amount)
+	assert.Equal(t, "Assets:Test Account", allNewTransactions[0].OriginalSourceAccountName)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, allNewTransactions[1].Type)
+	assert.Equal(t, int64(1725148800), utils.GetUnixTimeFromTransactionTime(allNewTransactions[1].TransactionTime))
+	assert.Equal(t, int64(12345), allNewTransactions[1].Amount)
+	assert.Equal(t, "Assets:Test Account", allNewTransactions[1].OriginalSourceAccountName)
+	assert.Equal(t, "Income:Test Category:Test Sub Category", allNewTransactions[1].OriginalCategoryName)
+	assert.Equal(t, []string{"Tag-1", "Tag2"}, allNewTransactions[1].OriginalTagNames)
+	assert.Equal(t, "Foo \"Bar\"\nsecond line", allNewTransactions[1].Comment)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[2].Type)
+	assert.Equal(t, int64(10), allNewTransactions[2].Amount)
+	assert.Equal(t, "Expenses:Test Category2", allNewTransactions[2].OriginalCategoryName)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[3].Type)
+	assert.Equal(t, int64(1735), allNewTransactions[3].Amount)
+	assert.Equal(t, "Assets:Test Account", allNewTransactions[3].OriginalSourceAccountName)
+	assert.Equal(t, "Liabilities:Test Credit Card", allNewTransactions[3].OriginalDestinationAccountName)
+	assert.Equal(t, []string{"Tag2"}, allNewTransactions[3].OriginalTagNames)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[4].Type)
+	assert.Equal(t, int64(10000), allNewTransactions[4].Amount)
+	assert.Equal(t, "CNY", allNewTransactions[4].OriginalSourceAccountCurrency)
+	assert.Equal(t, int64(1400), allNewTransactions[4].RelatedAccountAmount)
+	assert.Equal(t, "Assets:Test Account:USD Cash", allNewTransactions[4].OriginalDestinationAccountName)
+	assert.Equal(t, "USD", allNewTransactions[4].OriginalDestinationAccountCurrency)
+}
+
+func TestLedgerTransactionDataExporterGetAccountNameItem(t *testing.T) {
+	exporter := LedgerTransactionDataExporter
+
+	assert.Equal(t, "Test Account", exporter.getAccountNameItem("Test Account"))
+	assert.Equal(t, "test account!", exporter.getAccountNameItem(" test  account! "))
+	assert.Equal(t, "Ratio 1-2", exporter.getAccountNameItem("Ratio 1:2"))
+	assert.Equal(t, "Foo - Bar", exporter.getAccountNameItem("Foo ; Bar"))
+	assert.Equal(t, "Virtual)", exporter.getAccountNameItem("(Virtual)"))
+	assert.Equal(t, "招商银行", exporter.getAccountNameItem("招商银行"))
+	assert.Equal(t, "Unnamed", exporter.getAccountNameItem(" \t "))
+}
+
+func TestLedgerTransactionDataExporterGetTagName(t *testing.T) {
+	exporter := LedgerTransactionDataExporter
+
+	assert.Equal(t, "Tag-1", exporter.getTagName("Tag 1"))
+	assert.Equal(t, "Foo-Bar", exporter.getTagName("Foo:Bar"))
+	assert.Equal(t, "标签", exporter.getTagName(" 标签 "))
+}
+
+func createTestLedgerExportedTransactions() ([]*models.Transaction, map[int64]*models.Account, map[int64]*models.TransactionCategory, map[int64]*models.TransactionTag, map[int64][]int64) {
+	transactions := make([]*models.Transaction, 7)
+	transactions[0] = &models.Transaction{
+		TransactionId:     1,
+		TransactionTime:   1725165296000,
+		Type:              models.TRANSACTION_DB_TYPE_INCOME,
+		TimezoneUtcOffset: 480,
+		CategoryId:        12,
+		AccountId:         1,
+		Amount:            12345,
+		Comment:           "Foo  \"Bar\"\n\nsecond line",
+	}
+	transactions[1] = &models.Transaction{
+		TransactionId:     2,
+		TransactionTime:   1725194096000,
+		Type:              models.TRANSACTION_DB_TYPE_EXPENSE,
+		TimezoneUtcOffset: 0,
+		CategoryId:        13,
+		AccountId:         1,
+		Amount:            10,
+	}
+	transactions[2] = &models.Transaction{
+		TransactionId:        3,
+		TransactionTime:      1725212096000,
+		Type:                 models.TRANSACTION_DB_TYPE_TRANSFER_OUT,
+		TimezoneUtcOffset:    -300,
+		AccountId:            1,
+		Amount:               1735,
+		RelatedId:            4,
+		RelatedAccountId:     2,
+		RelatedAccountAmount: 1735,
+	}
+	transactions[3] = &models.Transaction{
+		TransactionId:        4,
+		TransactionTime:      1725212096000,
+		Type:                 models.TRANSACTION_DB_TYPE_TRANSFER_IN,
+		TimezoneUtcOffset:    -300,
+		AccountId:            2,
+		Amount:               1735,
+		RelatedId:            3,
+		RelatedAccountId:     1,
+		RelatedAccountAmount: 1735,
+	}
+	transactions[4] = &models.Transaction{
+		TransactionId:        5,
+		TransactionTime:      1725100000000,
+		Type:                 models.TRANSACTION_DB_TYPE_MODIFY_BALANCE,
+		TimezoneUtcOffset:    480,
+		AccountId:            1,
+		Amount:               100000,
+		RelatedAccountAmount: 100000,
+	}
+	transactions[5] = &models.Transaction{
+		TransactionId:        6,
+		TransactionTime:      1725212156000,
+		Type:                 models.TRANSACTION_DB_TYPE_TRANSFER_OUT,
+		TimezoneUtcOffset:    -300,
+		AccountId:            1,
+		Amount:               10000,
+		RelatedId:            7,
+		RelatedAccountId:     3,
+		RelatedAccountAmount: 1400,
+	}
+	transactions[6] = &models.Transaction{
+		TransactionId:        7,
+		TransactionTime:      1725212156000,
+		Type:                 models.TRANSACTION_DB_TYPE_TRANSFER_IN,
+		TimezoneUtcOffset:    -300,
+		AccountId:            3,
+		Amount:               1400,
+		RelatedId:            6,
+		RelatedAccountId:     1,
+		RelatedAccountAmount: 10000,
+	}
+
+	accountMap := make(map[int64]*models.Account, 3)
+	accountMap[1] = &models.Account{
+		AccountId: 1,
+		Category:  models.ACCOUNT_CATEGORY_CHECKING_ACCOUNT,
+		Name:      "Test Account",
+		Currency:  "CNY",
+	}
+	accountMap[2] = &models.Account{
+		AccountId: 2,
+		Category:  models.ACCOUNT_CATEGORY_CREDIT_CARD,
+		Name:      "Test Credit Card",
+		Currency:  "CNY",
+	}
+	accountMap[3] = &models.Account{
+		AccountId:       3,
+		Category:        models.ACCOUNT_CATEGORY_CHECKING_ACCOUNT,
+		ParentAccountId: 1,
+		Name:            "USD Cash",
+		Currency:        "USD",
+	}
+
+	categoryMap := make(map[int64]*models.TransactionCategory, 3)
+	categoryMap[11] = &models.TransactionCategory{
+		CategoryId:       11,
+		Name:             "Test Category",
+		ParentCategoryId: models.LevelOneTransactionCategoryParentId,
+	}
+	categoryMap[12] = &models.TransactionCategory{
+		CategoryId:       12,
+		Name:             "Test Sub Category",
+		ParentCategoryId: 11,
+	}
+	categoryMap[13] = &models.TransactionCategory{
+		CategoryId:       13,
+		Name:             "Test Category2",
+		ParentCategoryId: models.LevelOneTransactionCategoryParentId,
+	}
+
+	tagMap := make(map[int64]*models.TransactionTag, 2)
+	tagMap[21] = &models.TransactionTag{
+		TagId: 21,
+		Name:  "Tag 1",
+	}
+	tagMap[22] = &models.TransactionTag{
+		TagId: 22,
+		Name:  "Tag2",
+	}
+
+	allTagIndexes := make(map[int64][]int64, 2)
+	allTagIndexes[1] = []int64{21, 22}
+	allTagIndexes[3] = []int64{22}
+
+	return transactions, accountMap, categoryMap, tagMap, allTagIndexes
+}
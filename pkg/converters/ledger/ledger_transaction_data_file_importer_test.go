@@ -0,0 +1,254 @@
+package ledger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/converter"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+func TestLedgerTransactionDataFileParseImportedData_MinimumValidData(t *testing.T) {
+	importer := LedgerTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	allNewTransactions, allNewAccounts, allNewSubExpenseCategories, allNewSubIncomeCategories, allNewSubTransferCategories, allNewTags, err := importer.ParseImportedData(context, user, []byte(
+		"2024-09-01 *\n"+
+			"    Equity:Opening Balances  -123.45 CNY\n"+
+			"    Assets:Test Account  123.45 CNY\n"+
+			"2024-09-02 *\n"+
+			"    Income:Test Category  -0.12 CNY\n"+
+			"    Assets:Test Account\n"+
+			"2024-09-03 *\n"+
+			"    Expenses:Test Category2  1.00 CNY\n"+
+			"    Assets:Test Account\n"+
+			"2024-09-04 *\n"+
+			"    Assets:Test Account  -0.05 CNY\n"+
+			"    Assets:Test Account2\n"), time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+
+	assert.Equal(t, 4, len(allNewTransactions))
+	assert.Equal(t, 2, len(allNewAccounts))
+	assert.Equal(t, 1, len(allNewSubExpenseCategories))
+	assert.Equal(t, 1, len(allNewSubIncomeCategories))
+	assert.Equal(t, 1, len(allNewSubTransferCategories))
+	assert.Equal(t, 0, len(allNewTags))
+
+	assert.Equal(t, int64(1234567890), allNewTransactions[0].Uid)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_MODIFY_BALANCE, allNewTransactions[0].Type)
+	assert.Equal(t, int64(1725148800), utils.GetUnixTimeFromTransactionTime(allNewTransactions[0].TransactionTime))
+	assert.Equal(t, int64(12345), allNewTransactions[0].Amount)
+	assert.Equal(t, "Assets:Test Account", allNewTransactions[0].OriginalSourceAccountName)
+	assert.Equal(t, "", allNewTransactions[0].OriginalCategoryName)
+
+	assert.Equal(t, int64(1234567890), allNewTransactions[1].Uid)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, allNewTransactions[1].Type)
+	assert.Equal(t, int64(1725235200), utils.GetUnixTimeFromTransactionTime(allNewTransactions[1].TransactionTime))
+	assert.Equal(t, int64(12), allNewTransactions[1].Amount)
+	assert.Equal(t, "Assets:Test Account", allNewTransactions[1].OriginalSourceAccountName)
+	assert.Equal(t, "Income:Test Category", allNewTransactions[1].OriginalCategoryName)
+
+	assert.Equal(t, int64(1234567890), allNewTransactions[2].Uid)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[2].Type)
+	assert.Equal(t, int64(1725321600), utils.GetUnixTimeFromTransactionTime(allNewTransactions[2].TransactionTime))
+	assert.Equal(t, int64(100), allNewTransactions[2].Amount)
+	assert.Equal(t, "Assets:Test Account", allNewTransactions[2].OriginalSourceAccountName)
+	assert.Equal(t, "Expenses:Test Category2", allNewTransactions[2].OriginalCategoryName)
+
+	assert.Equal(t, int64(1234567890), allNewTransactions[3].Uid)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[3].Type)
+	assert.Equal(t, int64(1725408000), utils.GetUnixTimeFromTransactionTime(allNewTransactions[3].TransactionTime))
+	assert.Equal(t, int64(5), allNewTransactions[3].Amount)
+	assert.Equal(t, "Assets:Test Account", allNewTransactions[3].OriginalSourceAccountName)
+	assert.Equal(t, "Assets:Test Account2", allNewTransactions[3].OriginalDestinationAccountName)
+	assert.Equal(t, "", allNewTransactions[3].OriginalCategoryName)
+
+	assert.Equal(t, int64(1234567890), allNewAccounts[0].Uid)
+	assert.Equal(t, "Assets:Test Account", allNewAccounts[0].Name)
+	assert.Equal(t, "CNY", allNewAccounts[0].Currency)
+
+	assert.Equal(t, int64(1234567890), allNewAccounts[1].Uid)
+	assert.Equal(t, "Assets:Test Account2", allNewAccounts[1].Name)
+	assert.Equal(t, "CNY", allNewAccounts[1].Currency)
+
+	assert.Equal(t, int64(1234567890), allNewSubExpenseCategories[0].Uid)
+	assert.Equal(t, "Expenses:Test Category2", allNewSubExpenseCategories[0].Name)
+
+	assert.Equal(t, int64(1234567890), allNewSubIncomeCategories[0].Uid)
+	assert.Equal(t, "Income:Test Category", allNewSubIncomeCategories[0].Name)
+
+	assert.Equal(t, int64(1234567890), allNewSubTransferCategories[0].Uid)
+	assert.Equal(t, "", allNewSubTransferCategories[0].Name)
+}
+
+func TestLedgerTransactionDataFileParseImportedData_ParseAccountTypeDirective(t *testing.T) {
+	importer := LedgerTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	allNewTransactions, _, allNewSubExpenseCategories, allNewSubIncomeCategories, _, _, err := importer.ParseImportedData(context, user, []byte(
+		"account checking  ; type: A\n"+
+			"account salary  ; type: R\n"+
+			"account food\n"+
+			"    ; type: X\n"+
+			"\n"+
+			"2024-09-01 * Salary\n"+
+			"    salary  -123.45 CNY\n"+
+			"    checking\n"+
+			"2024-09-02 * Dinner\n"+
+			"    food  12.00 CNY\n"+
+			"    checking\n"), time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+
+	assert.Equal(t, 2, len(allNewTransactions))
+	assert.Equal(t, 1, len(allNewSubExpenseCategories))
+	assert.Equal(t, 1, len(allNewSubIncomeCategories))
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, allNewTransactions[0].Type)
+	assert.Equal(t, int64(12345), allNewTransactions[0].Amount)
+	assert.Equal(t, "checking", allNewTransactions[0].OriginalSourceAccountName)
+	assert.Equal(t, "salary", allNewTransactions[0].OriginalCategoryName)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[1].Type)
+	assert.Equal(t, int64(1200), allNewTransactions[1].Amount)
+	assert.Equal(t, "checking", allNewTransactions[1].OriginalSourceAccountName)
+	assert.Equal(t, "food", allNewTransactions[1].OriginalCategoryName)
+}
+
+func TestLedgerTransactionDataFileParseImportedData_ParseValidCurrency(t *testing.T) {
+	importer := LedgerTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	allNewTransactions, allNewAccounts, _, _, _, _, err := importer.ParseImportedData(context, user, []byte(
+		"2024-09-01 *\n"+
+			"    Assets:Test Account  -$123.45\n"+
+			"    Assets:Test Account2  €100.00\n"+
+			"2024-09-02 *\n"+
+			"    Assets:Test Account3  -1.00\n"+
+			"    Expenses:Test Category\n"), time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+
+	assert.Equal(t, 2, len(allNewTransactions))
+	assert.Equal(t, 3, len(allNewAccounts))
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[0].Type)
+	assert.Equal(t, int64(12345), allNewTransactions[0].Amount)
+	assert.Equal(t, "USD", allNewTransactions[0].OriginalSourceAccountCurrency)
+	assert.Equal(t, int64(10000), allNewTransactions[0].RelatedAccountAmount)
+	assert.Equal(t, "EUR", allNewTransactions[0].OriginalDestinationAccountCurrency)
+
+	assert.Equal(t, "Assets:Test Account", allNewAccounts[0].Name)
+	assert.Equal(t, "USD", allNewAccounts[0].Currency)
+	assert.Equal(t, "Assets:Test Account2", allNewAccounts[1].Name)
+	assert.Equal(t, "EUR", allNewAccounts[1].Currency)
+	assert.Equal(t, "Assets:Test Account3", allNewAccounts[2].Name)
+	assert.Equal(t, "CNY", allNewAccounts[2].Currency)
+}
+
+func TestLedgerTransactionDataFileParseImportedData_ParseInvalidCurrency(t *testing.T) {
+	importer := LedgerTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	_, _, _, _, _, _, err := importer.ParseImportedData(context, user, []byte(
+		"2024-09-01 *\n"+
+			"    Assets:Brokerage  10 AAPL\n"+
+			"    Assets:Test Account  -1000.00 USD\n"), time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrAccountCurrencyInvalid.Message)
+}
+
+func TestLedgerTransactionDataFileParseImportedData_ParseDescriptionAndTags(t *testing.T) {
+	importer := LedgerTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	allNewTransactions, _, _, _, _, allNewTags, err := importer.ParseImportedData(context, user, []byte(
+		"2024-09-01 * (123) Grocery Store  ; :food:\n"+
+			"    ; bought some fruits\n"+
+			"    ; Receipt: 20240901-001\n"+
+			"    Expenses:Food  12.34 CNY  ; :weekly:\n"+
+			"    Assets:Cash\n"+
+			"2024-09-02\n"+
+			"    ; only note\n"+
+			"    Expenses:Food  1.00 CNY\n"+
+			"    Assets:Cash\n"), time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+
+	assert.Equal(t, 2, len(allNewTransactions))
+	assert.Equal(t, 2, len(allNewTags))
+
+	assert.Equal(t, "Grocery Store\nbought some fruits", allNewTransactions[0].Comment)
+	assert.Equal(t, []string{"food", "weekly"}, allNewTransactions[0].OriginalTagNames)
+
+	assert.Equal(t, "only note", allNewTransactions[1].Comment)
+	assert.Equal(t, 0, len(allNewTransactions[1].OriginalTagNames))
+}
+
+func TestLedgerTransactionDataFileParseImportedData_InvalidTransaction(t *testing.T) {
+	importer := LedgerTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	_, _, _, _, _, _, err := importer.ParseImportedData(context, user, []byte(
+		"2024-09-01 *\n"+
+			"    Assets:Test Account  1.00 CNY\n"), time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrInvalidLedgerFile.Message)
+
+	_, _, _, _, _, _, err = importer.ParseImportedData(context, user, []byte(
+		"2024-09-01 *\n"+
+			"    Unknown:Test  -1.00 CNY\n"+
+			"    Assets:Test Account\n"), time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrThereAreNotSupportedTransactionType.Message)
+}
+
+func TestLedgerTransactionDataFileParseImportedData_NotSupportedToParseSplitTransaction(t *testing.T) {
+	importer := LedgerTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	_, _, _, _, _, _, err := importer.ParseImportedData(context, user, []byte(
+		"2024-09-01 *\n"+
+			"    Expenses:Food  1.00 CNY\n"+
+			"    Expenses:Drink  2.00 CNY\n"+
+			"    Assets:Test Account\n"), time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrNotSupportedSplitTransactions.Message)
+}
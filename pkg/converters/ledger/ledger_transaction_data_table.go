@@ -0,0 +1,283 @@
+package ledger
+
+import (
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/datatable"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+var ledgerTransactionSupportedColumns = map[datatable.TransactionDataTableColumn]bool{
+	datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIME:         true,
+	datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE:         true,
+	datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY:             true,
+	datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME:             true,
+	datatable.TRANSACTION_DATA_TABLE_ACCOUNT_CURRENCY:         true,
+	datatable.TRANSACTION_DATA_TABLE_AMOUNT:                   true,
+	datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME:     true,
+	datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_CURRENCY: true,
+	datatable.TRANSACTION_DATA_TABLE_RELATED_AMOUNT:           true,
+	datatable.TRANSACTION_DATA_TABLE_DESCRIPTION:              true,
+	datatable.TRANSACTION_DATA_TABLE_TAGS:                     true,
+}
+
+var LEDGER_TRANSACTION_TAG_SEPARATOR = ledgerTagSeparator
+
+// ledgerCommoditySymbolCurrencies defines the currency codes of the commonly used commodity symbols in ledger files
+var ledgerCommoditySymbolCurrencies = map[string]string{
+	"$": "USD",
+	"€": "EUR",
+	"£": "GBP",
+	"₹": "INR",
+	"₩": "KRW",
+	"₽": "RUB",
+	"₺": "TRY",
+	"₫": "VND",
+	"฿": "THB",
+	"₴": "UAH",
+}
+
+// ledgerTransactionDataTable defines the structure of Ledger transaction data table
+type ledgerTransactionDataTable struct {
+	allData    []*ledgerTransactionEntry
+	accountMap map[string]*ledgerAccount
+}
+
+// ledgerTransactionDataRow defines the structure of Ledger transaction data row
+type ledgerTransactionDataRow struct {
+	dataTable  *ledgerTransactionDataTable
+	data       *ledgerTransactionEntry
+	finalItems map[datatable.TransactionDataTableColumn]string
+}
+
+// ledgerTransactionDataRowIterator defines the structure of Ledger transaction data row iterator
+type ledgerTransactionDataRowIterator struct {
+	dataTable    *ledgerTransactionDataTable
+	currentIndex int
+}
+
+// HasColumn returns whether the transaction data table has specified column
+func (t *ledgerTransactionDataTable) HasColumn(column datatable.TransactionDataTableColumn) bool {
+	_, exists := ledgerTransactionSupportedColumns[column]
+	return exists
+}
+
+// TransactionRowCount returns the total count of transaction data row
+func (t *ledgerTransactionDataTable) TransactionRowCount() int {
+	return len(t.allData)
+}
+
+// TransactionRowIterator returns the iterator of transaction data row
+func (t *ledgerTransactionDataTable) TransactionRowIterator() datatable.TransactionDataRowIterator {
+	return &ledgerTransactionDataRowIterator{
+		dataTable:    t,
+		currentIndex: -1,
+	}
+}
+
+// IsValid returns whether this row is valid data for importing
+func (r *ledgerTransactionDataRow) IsValid() bool {
+	return true
+}
+
+// GetData returns the data in the specified column type
+func (r *ledgerTransactionDataRow) GetData(column datatable.TransactionDataTableColumn) string {
+	_, exists := ledgerTransactionSupportedColumns[column]
+
+	if exists {
+		return r.finalItems[column]
+	}
+
+	return ""
+}
+
+// HasNext returns whether the iterator does not reach the end
+func (t *ledgerTransactionDataRowIterator) HasNext() bool {
+	return t.currentIndex+1 < len(t.dataTable.allData)
+}
+
+// Next returns the next transaction data row
+func (t *ledgerTransactionDataRowIterator) Next(ctx core.Context, user *models.User) (daraRow datatable.TransactionDataRow, err error) {
+	if t.currentIndex+1 >= len(t.dataTable.allData) {
+		return nil, nil
+	}
+
+	t.currentIndex++
+
+	data := t.dataTable.allData[t.currentIndex]
+	rowItems, err := t.parseTransaction(ctx, user, data)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &ledgerTransactionDataRow{
+		dataTable:  t.dataTable,
+		data:       data,
+		finalItems: rowItems,
+	}, nil
+}
+
+func (t *ledgerTransactionDataRowIterator) parseTransaction(ctx core.Context, user *models.User, ledgerEntry *ledgerTransactionEntry) (map[datatable.TransactionDataTableColumn]string, error) {
+	data := make(map[datatable.TransactionDataTableColumn]string, len(ledgerTransactionSupportedColumns))
+
+	if ledgerEntry.Date == "" {
+		return nil, errs.ErrMissingTransactionTime
+	}
+
+	// the date of ledger entry has been normalized to "YYYY-MM-DD" format by data reader
+	data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIME] = ledgerEntry.Date + " 00:00:00"
+
+	if len(ledgerEntry.Postings) == 2 {
+		splitData1 := ledgerEntry.Postings[0]
+		splitData2 := ledgerEntry.Postings[1]
+
+		account1 := t.dataTable.accountMap[splitData1.Account]
+		account2 := t.dataTable.accountMap[splitData2.Account]
+
+		if account1 == nil || account2 == nil {
+			return nil, errs.ErrMissingAccountData
+		}
+
+		amount1, err := utils.ParseAmount(splitData1.Amount)
+
+		if err != nil {
+			log.Errorf(ctx, "[ledger_transaction_data_table.parseTransaction] cannot parse amount \"%s\", because %s", splitData1.Amount, err.Error())
+			return nil, errs.ErrAmountInvalid
+		}
+
+		amount2, err := utils.ParseAmount(splitData2.Amount)
+
+		if err != nil {
+			log.Errorf(ctx, "[ledger_transaction_data_table.parseTransaction] cannot parse amount \"%s\", because %s", splitData2.Amount, err.Error())
+			return nil, errs.ErrAmountInvalid
+		}
+
+		if ((account1.AccountType == ledgerEquityAccountType || account1.AccountType == ledgerIncomeAccountType) && (account2.AccountType == ledgerAssetsAccountType || account2.AccountType == ledgerLiabilitiesAccountType)) ||
+			((account2.AccountType == ledgerEquityAccountType || account2.AccountType == ledgerIncomeAccountType) && (account1.AccountType == ledgerAssetsAccountType || account1.AccountType == ledgerLiabilitiesAccountType)) { // income
+			fromAccount := account1
+			toAccount := account2
+			toCurrency := t.getCurrency(splitData2.Commodity)
+			toAmount := amount2
+
+			if (account2.AccountType == ledgerEquityAccountType || account2.AccountType == ledgerIncomeAccountType) && (account1.AccountType == ledgerAssetsAccountType || account1.AccountType == ledgerLiabilitiesAccountType) {
+				fromAccount = account2
+				toAccount = account1
+				toCurrency = t.getCurrency(splitData1.Commodity)
+				toAmount = amount1
+			}
+
+			if fromAccount.isOpeningBalanceEquityAccount() {
+				data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = utils.IntToString(int(models.TRANSACTION_TYPE_MODIFY_BALANCE))
+			} else {
+				data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = utils.IntToString(int(models.TRANSACTION_TYPE_INCOME))
+			}
+
+			data[datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY] = fromAccount.Name
+			data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME] = toAccount.Name
+			data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_CURRENCY] = toCurrency
+			data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(toAmount)
+		} else if account1.AccountType == ledgerExpensesAccountType && (account2.AccountType == ledgerAssetsAccountType || account2.AccountType == ledgerLiabilitiesAccountType) ||
+			(account2.AccountType == ledgerExpensesAccountType && (account1.AccountType == ledgerAssetsAccountType || account1.AccountType == ledgerLiabilitiesAccountType)) { // expense
+			fromAccount := account1
+			fromCurrency := t.getCurrency(splitData1.Commodity)
+			fromAmount := amount1
+			toAccount := account2
+
+			if account1.AccountType == ledgerExpensesAccountType && (account2.AccountType == ledgerAssetsAccountType || account2.AccountType == ledgerLiabilitiesAccountType) {
+				fromAccount = account2
+				fromCurrency = t.getCurrency(splitData2.Commodity)
+				fromAmount = amount2
+				toAccount = account1
+			}
+
+			data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = utils.IntToString(int(models.TRANSACTION_TYPE_EXPENSE))
+			data[datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY] = toAccount.Name
+			data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME] = fromAccount.Name
+			data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_CURRENCY] = fromCurrency
+			data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(-fromAmount)
+		} else if (account1.AccountType == ledgerAssetsAccountType || account1.AccountType == ledgerLiabilitiesAccountType) &&
+			(account2.AccountType == ledgerAssetsAccountType || account2.AccountType == ledgerLiabilitiesAccountType) {
+			var fromAccount, toAccount *ledgerAccount
+			var fromAmount, toAmount int64
+			var fromCurrency, toCurrency string
+
+			if amount1 < 0 {
+				fromAccount = account1
+				fromCurrency = t.getCurrency(splitData1.Commodity)
+				fromAmount = -amount1
+				toAccount = account2
+				toCurrency = t.getCurrency(splitData2.Commodity)
+				toAmount = amount2
+			} else if amount2 < 0 {
+				fromAccount = account2
+				fromCurrency = t.getCurrency(splitData2.Commodity)
+				fromAmount = -amount2
+				toAccount = account1
+				toCurrency = t.getCurrency(splitData1.Commodity)
+				toAmount = amount1
+			} else {
+				log.Errorf(ctx, "[ledger_transaction_data_table.parseTransaction] cannot parse transfer transaction, because unexcepted account amounts \"%d\" and \"%d\"", amount1, amount2)
+				return nil, errs.ErrInvalidLedgerFile
+			}
+
+			data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = utils.IntToString(int(models.TRANSACTION_TYPE_TRANSFER))
+			data[datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY] = ""
+			data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME] = fromAccount.Name
+			data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_CURRENCY] = fromCurrency
+			data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(fromAmount)
+			data[datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME] = toAccount.Name
+			data[datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_CURRENCY] = toCurrency
+			data[datatable.TRANSACTION_DATA_TABLE_RELATED_AMOUNT] = utils.FormatAmount(toAmount)
+		} else {
+			log.Errorf(ctx, "[ledger_transaction_data_table.parseTransaction] cannot parse transaction, because unexcepted account types \"%d\" and \"%d\"", account1.AccountType, account2.AccountType)
+			return nil, errs.ErrThereAreNotSupportedTransactionType
+		}
+	} else if len(ledgerEntry.Postings) <= 1 {
+		log.Errorf(ctx, "[ledger_transaction_data_table.parseTransaction] cannot parse transaction, because postings count is %d", len(ledgerEntry.Postings))
+		return nil, errs.ErrInvalidLedgerFile
+	} else {
+		log.Errorf(ctx, "[ledger_transaction_data_table.parseTransaction] cannot parse split transaction, because postings count is %d", len(ledgerEntry.Postings))
+		return nil, errs.ErrNotSupportedSplitTransactions
+	}
+
+	data[datatable.TRANSACTION_DATA_TABLE_TAGS] = strings.Join(ledgerEntry.Tags, LEDGER_TRANSACTION_TAG_SEPARATOR)
+	data[datatable.TRANSACTION_DATA_TABLE_DESCRIPTION] = t.getDescription(ledgerEntry)
+
+	return data, nil
+}
+
+func (t *ledgerTransactionDataRowIterator) getCurrency(commodity string) string {
+	if currency, exists := ledgerCommoditySymbolCurrencies[commodity]; exists {
+		return currency
+	}
+
+	return commodity
+}
+
+func (t *ledgerTransactionDataRowIterator) getDescription(ledgerEntry *ledgerTransactionEntry) string {
+	lines := make([]string, 0, len(ledgerEntry.Notes)+1)
+
+	if ledgerEntry.Payee != "" {
+		lines = append(lines, ledgerEntry.Payee)
+	}
+
+	lines = append(lines, ledgerEntry.Notes...)
+
+	return strings.Join(lines, "\n")
+}
+
+func createNewLedgerTransactionDataTable(ledgerData *ledgerData) (*ledgerTransactionDataTable, error) {
+	if ledgerData == nil {
+		return nil, errs.ErrNotFoundTransactionDataInFile
+	}
+
+	return &ledgerTransactionDataTable{
+		allData:    ledgerData.Transactions,
+		accountMap: ledgerData.Accounts,
+	}, nil
+}
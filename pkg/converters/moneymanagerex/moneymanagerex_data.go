@@ -0,0 +1,89 @@
+package moneymanagerex
+
+const moneyManagerExWithdrawalTransactionCode = "Withdrawal"
+const moneyManagerExDepositTransactionCode = "Deposit"
+const moneyManagerExTransferTransactionCode = "Transfer"
+
+const moneyManagerExVoidTransactionStatus = "V"
+
+const moneyManagerExTransactionTagReferenceType = "Transaction"
+const moneyManagerExSplitTransactionTagReferenceType = "TransactionSplit"
+
+// moneyManagerExData defines the structure of money manager ex data
+type moneyManagerExData struct {
+	Currencies             map[int64]*moneyManagerExCurrencyData
+	Accounts               map[int64]*moneyManagerExAccountData
+	Payees                 map[int64]*moneyManagerExPayeeData
+	Categories             map[int64]*moneyManagerExCategoryData
+	SubCategories          map[int64]*moneyManagerExSubCategoryData
+	Tags                   map[int64]*moneyManagerExTagData
+	Transactions           []*moneyManagerExTransactionData
+	SplitTransactions      map[int64][]*moneyManagerExSplitTransactionData
+	TransactionTagIds      map[int64][]int64
+	SplitTransactionTagIds map[int64][]int64
+}
+
+// moneyManagerExCurrencyData defines the structure of money manager ex currency data
+type moneyManagerExCurrencyData struct {
+	Id     int64
+	Symbol string
+}
+
+// moneyManagerExAccountData defines the structure of money manager ex account data
+type moneyManagerExAccountData struct {
+	Id         int64
+	Name       string
+	CurrencyId int64
+}
+
+// moneyManagerExPayeeData defines the structure of money manager ex payee data
+type moneyManagerExPayeeData struct {
+	Id   int64
+	Name string
+}
+
+// moneyManagerExCategoryData defines the structure of money manager ex category data
+type moneyManagerExCategoryData struct {
+	Id       int64
+	Name     string
+	ParentId int64
+}
+
+// moneyManagerExSubCategoryData defines the structure of money manager ex sub category data, which only exists in older versions
+type moneyManagerExSubCategoryData struct {
+	Id         int64
+	Name       string
+	CategoryId int64
+}
+
+// moneyManagerExTagData defines the structure of money manager ex tag data
+type moneyManagerExTagData struct {
+	Id   int64
+	Name string
+}
+
+// moneyManagerExTransactionData defines the structure of money manager ex transaction data
+type moneyManagerExTransactionData struct {
+	Id              int64
+	AccountId       int64
+	ToAccountId     int64
+	PayeeId         int64
+	TransactionCode string
+	Amount          float64
+	ToAmount        float64
+	Status          string
+	Notes           string
+	CategoryId      int64
+	SubCategoryId   int64
+	Date            string
+}
+
+// moneyManagerExSplitTransactionData defines the structure of money manager ex split transaction data
+type moneyManagerExSplitTransactionData struct {
+	Id            int64
+	TransactionId int64
+	CategoryId    int64
+	SubCategoryId int64
+	Amount        float64
+	Notes         string
+}
@@ -0,0 +1,432 @@
+package moneymanagerex
+
+import (
+	"bytes"
+	"database/sql"
+	"os"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+)
+
+const moneyManagerExTempDatabaseFileNamePattern = "ezbookkeeping_money_manager_ex_*.mmb"
+
+var sqliteFileHeader = []byte("SQLite format 3\x00")
+
+// moneyManagerExDataReader defines the structure of money manager ex data reader
+type moneyManagerExDataReader struct {
+	databaseData []byte
+}
+
+// read returns the imported money manager ex data
+func (r *moneyManagerExDataReader) read(ctx core.Context) (*moneyManagerExData, error) {
+	tempFile, err := os.CreateTemp("", moneyManagerExTempDatabaseFileNamePattern)
+
+	if err != nil {
+		log.Errorf(ctx, "[moneymanagerex_data_reader.read] cannot create temp database file, because %s", err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	tempFilePath := tempFile.Name()
+	defer os.Remove(tempFilePath)
+
+	_, err = tempFile.Write(r.databaseData)
+	closeErr := tempFile.Close()
+
+	if err != nil || closeErr != nil {
+		log.Errorf(ctx, "[moneymanagerex_data_reader.read] cannot write temp database file \"%s\"", tempFilePath)
+		return nil, errs.ErrOperationFailed
+	}
+
+	db, err := sql.Open("sqlite3", "file:"+tempFilePath+"?mode=ro&immutable=1")
+
+	if err != nil {
+		log.Errorf(ctx, "[moneymanagerex_data_reader.read] cannot open database file, because %s", err.Error())
+		return nil, errs.ErrInvalidMoneyManagerExFile
+	}
+
+	defer db.Close()
+
+	data := &moneyManagerExData{}
+
+	if data.Currencies, err = r.readCurrencies(db); err != nil {
+		log.Errorf(ctx, "[moneymanagerex_data_reader.read] cannot read currencies, because %s", err.Error())
+		return nil, errs.ErrInvalidMoneyManagerExFile
+	}
+
+	if data.Accounts, err = r.readAccounts(db); err != nil {
+		log.Errorf(ctx, "[moneymanagerex_data_reader.read] cannot read accounts, because %s", err.Error())
+		return nil, errs.ErrInvalidMoneyManagerExFile
+	}
+
+	if data.Payees, err = r.readPayees(db); err != nil {
+		log.Errorf(ctx, "[moneymanagerex_data_reader.read] cannot read payees, because %s", err.Error())
+		return nil, errs.ErrInvalidMoneyManagerExFile
+	}
+
+	if data.Categories, err = r.readCategories(db); err != nil {
+		log.Errorf(ctx, "[moneymanagerex_data_reader.read] cannot read categories, because %s", err.Error())
+		return nil, errs.ErrInvalidMoneyManagerExFile
+	}
+
+	if data.SubCategories, err = r.readSubCategories(db); err != nil {
+		log.Errorf(ctx, "[moneymanagerex_data_reader.read] cannot read sub categories, because %s", err.Error())
+		return nil, errs.ErrInvalidMoneyManagerExFile
+	}
+
+	if data.Tags, err = r.readTags(db); err != nil {
+		log.Errorf(ctx, "[moneymanagerex_data_reader.read] cannot read tags, because %s", err.Error())
+		return nil, errs.ErrInvalidMoneyManagerExFile
+	}
+
+	if data.Transactions, err = r.readTransactions(db); err != nil {
+		log.Errorf(ctx, "[moneymanagerex_data_reader.read] cannot read transactions, because %s", err.Error())
+		return nil, errs.ErrInvalidMoneyManagerExFile
+	}
+
+	if data.SplitTransactions, err = r.readSplitTransactions(db); err != nil {
+		log.Errorf(ctx, "[moneymanagerex_data_reader.read] cannot read split transactions, because %s", err.Error())
+		return nil, errs.ErrInvalidMoneyManagerExFile
+	}
+
+	if data.TransactionTagIds, data.SplitTransactionTagIds, err = r.readTagLinks(db); err != nil {
+		log.Errorf(ctx, "[moneymanagerex_data_reader.read] cannot read tag links, because %s", err.Error())
+		return nil, errs.ErrInvalidMoneyManagerExFile
+	}
+
+	return data, nil
+}
+
+func (r *moneyManagerExDataReader) readCurrencies(db *sql.DB) (map[int64]*moneyManagerExCurrencyData, error) {
+	rows, err := db.Query("SELECT CURRENCYID, COALESCE(CURRENCY_SYMBOL, '') FROM CURRENCYFORMATS_V1")
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	currencies := make(map[int64]*moneyManagerExCurrencyData)
+
+	for rows.Next() {
+		currency := &moneyManagerExCurrencyData{}
+
+		if err = rows.Scan(&currency.Id, &currency.Symbol); err != nil {
+			return nil, err
+		}
+
+		currencies[currency.Id] = currency
+	}
+
+	return currencies, rows.Err()
+}
+
+func (r *moneyManagerExDataReader) readAccounts(db *sql.DB) (map[int64]*moneyManagerExAccountData, error) {
+	rows, err := db.Query("SELECT ACCOUNTID, COALESCE(ACCOUNTNAME, ''), COALESCE(CURRENCYID, -1) FROM ACCOUNTLIST_V1")
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	accounts := make(map[int64]*moneyManagerExAccountData)
+
+	for rows.Next() {
+		account := &moneyManagerExAccountData{}
+
+		if err = rows.Scan(&account.Id, &account.Name, &account.CurrencyId); err != nil {
+			return nil, err
+		}
+
+		accounts[account.Id] = account
+	}
+
+	return accounts, rows.Err()
+}
+
+func (r *moneyManagerExDataReader) readPayees(db *sql.DB) (map[int64]*moneyManagerExPayeeData, error) {
+	rows, err := db.Query("SELECT PAYEEID, COALESCE(PAYEENAME, '') FROM PAYEE_V1")
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	payees := make(map[int64]*moneyManagerExPayeeData)
+
+	for rows.Next() {
+		payee := &moneyManagerExPayeeData{}
+
+		if err = rows.Scan(&payee.Id, &payee.Name); err != nil {
+			return nil, err
+		}
+
+		payees[payee.Id] = payee
+	}
+
+	return payees, rows.Err()
+}
+
+func (r *moneyManagerExDataReader) readCategories(db *sql.DB) (map[int64]*moneyManagerExCategoryData, error) {
+	columns, err := r.getTableColumns(db, "CATEGORY_V1")
+
+	if err != nil {
+		return nil, err
+	}
+
+	// the categories are saved in one table with parent id since money manager ex 1.6.0
+	rows, err := db.Query("SELECT CATEGID, COALESCE(CATEGNAME, ''), " + getColumnExpression(columns, "PARENTID", "-1") + " FROM CATEGORY_V1")
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	categories := make(map[int64]*moneyManagerExCategoryData)
+
+	for rows.Next() {
+		category := &moneyManagerExCategoryData{}
+
+		if err = rows.Scan(&category.Id, &category.Name, &category.ParentId); err != nil {
+			return nil, err
+		}
+
+		categories[category.Id] = category
+	}
+
+	return categories, rows.Err()
+}
+
+func (r *moneyManagerExDataReader) readSubCategories(db *sql.DB) (map[int64]*moneyManagerExSubCategoryData, error) {
+	subCategories := make(map[int64]*moneyManagerExSubCategoryData)
+	columns, err := r.getTableColumns(db, "SUBCATEGORY_V1")
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(columns) < 1 {
+		return subCategories, nil
+	}
+
+	rows, err := db.Query("SELECT SUBCATEGID, COALESCE(SUBCATEGNAME, ''), COALESCE(CATEGID, -1) FROM SUBCATEGORY_V1")
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		subCategory := &moneyManagerExSubCategoryData{}
+
+		if err = rows.Scan(&subCategory.Id, &subCategory.Name, &subCategory.CategoryId); err != nil {
+			return nil, err
+		}
+
+		subCategories[subCategory.Id] = subCategory
+	}
+
+	return subCategories, rows.Err()
+}
+
+func (r *moneyManagerExDataReader) readTags(db *sql.DB) (map[int64]*moneyManagerExTagData, error) {
+	tags := make(map[int64]*moneyManagerExTagData)
+	columns, err := r.getTableColumns(db, "TAG_V1")
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(columns) < 1 {
+		return tags, nil
+	}
+
+	rows, err := db.Query("SELECT TAGID, COALESCE(TAGNAME, '') FROM TAG_V1")
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		tag := &moneyManagerExTagData{}
+
+		if err = rows.Scan(&tag.Id, &tag.Name); err != nil {
+			return nil, err
+		}
+
+		tags[tag.Id] = tag
+	}
+
+	return tags, rows.Err()
+}
+
+func (r *moneyManagerExDataReader) readTransactions(db *sql.DB) ([]*moneyManagerExTransactionData, error) {
+	columns, err := r.getTableColumns(db, "CHECKINGACCOUNT_V1")
+
+	if err != nil {
+		return nil, err
+	}
+
+	query := "SELECT TRANSID, COALESCE(ACCOUNTID, -1), COALESCE(TOACCOUNTID, -1), COALESCE(PAYEEID, -1), COALESCE(TRANSCODE, ''), COALESCE(TRANSAMOUNT, 0), " +
+		getColumnExpression(columns, "TOTRANSAMOUNT", "0") + ", COALESCE(STATUS, ''), COALESCE(NOTES, ''), COALESCE(CATEGID, -1), " +
+		getColumnExpression(columns, "SUBCATEGID", "-1") + ", COALESCE(TRANSDATE, '') FROM CHECKINGACCOUNT_V1"
+
+	if columns["DELETEDTIME"] {
+		query += " WHERE COALESCE(DELETEDTIME, '') = ''"
+	}
+
+	rows, err := db.Query(query + " ORDER BY TRANSDATE, TRANSID")
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	transactions := make([]*moneyManagerExTransactionData, 0)
+
+	for rows.Next() {
+		transaction := &moneyManagerExTransactionData{}
+
+		err = rows.Scan(&transaction.Id, &transaction.AccountId, &transaction.ToAccountId, &transaction.PayeeId, &transaction.TransactionCode, &transaction.Amount,
+			&transaction.ToAmount, &transaction.Status, &transaction.Notes, &transaction.CategoryId, &transaction.SubCategoryId, &transaction.Date)
+
+		if err != nil {
+			return nil, err
+		}
+
+		transactions = append(transactions, transaction)
+	}
+
+	return transactions, rows.Err()
+}
+
+func (r *moneyManagerExDataReader) readSplitTransactions(db *sql.DB) (map[int64][]*moneyManagerExSplitTransactionData, error) {
+	splitTransactions := make(map[int64][]*moneyManagerExSplitTransactionData)
+	columns, err := r.getTableColumns(db, "SPLITTRANSACTIONS_V1")
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(columns) < 1 {
+		return splitTransactions, nil
+	}
+
+	rows, err := db.Query("SELECT SPLITTRANSID, COALESCE(TRANSID, -1), COALESCE(CATEGID, -1), " + getColumnExpression(columns, "SUBCATEGID", "-1") + ", " +
+		"COALESCE(SPLITTRANSAMOUNT, 0), " + getColumnExpression(columns, "NOTES", "''") + " FROM SPLITTRANSACTIONS_V1 ORDER BY SPLITTRANSID")
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		splitTransaction := &moneyManagerExSplitTransactionData{}
+
+		if err = rows.Scan(&splitTransaction.Id, &splitTransaction.TransactionId, &splitTransaction.CategoryId, &splitTransaction.SubCategoryId, &splitTransaction.Amount, &splitTransaction.Notes); err != nil {
+			return nil, err
+		}
+
+		splitTransactions[splitTransaction.TransactionId] = append(splitTransactions[splitTransaction.TransactionId], splitTransaction)
+	}
+
+	return splitTransactions, rows.Err()
+}
+
+func (r *moneyManagerExDataReader) readTagLinks(db *sql.DB) (map[int64][]int64, map[int64][]int64, error) {
+	transactionTagIds := make(map[int64][]int64)
+	splitTransactionTagIds := make(map[int64][]int64)
+	columns, err := r.getTableColumns(db, "TAGLINK_V1")
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(columns) < 1 {
+		return transactionTagIds, splitTransactionTagIds, nil
+	}
+
+	rows, err := db.Query("SELECT COALESCE(REFTYPE, ''), COALESCE(REFID, -1), COALESCE(TAGID, -1) FROM TAGLINK_V1 ORDER BY TAGLINKID")
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var referenceType string
+		var referenceId int64
+		var tagId int64
+
+		if err = rows.Scan(&referenceType, &referenceId, &tagId); err != nil {
+			return nil, nil, err
+		}
+
+		if referenceType == moneyManagerExTransactionTagReferenceType {
+			transactionTagIds[referenceId] = append(transactionTagIds[referenceId], tagId)
+		} else if referenceType == moneyManagerExSplitTransactionTagReferenceType {
+			splitTransactionTagIds[referenceId] = append(splitTransactionTagIds[referenceId], tagId)
+		}
+	}
+
+	return transactionTagIds, splitTransactionTagIds, rows.Err()
+}
+
+func (r *moneyManagerExDataReader) getTableColumns(db *sql.DB, tableName string) (map[string]bool, error) {
+	rows, err := db.Query("PRAGMA table_info(" + tableName + ")")
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	columns := make(map[string]bool)
+
+	for rows.Next() {
+		var columnIndex int
+		var columnName string
+		var columnType sql.NullString
+		var notNull int
+		var defaultValue sql.NullString
+		var primaryKey int
+
+		if err = rows.Scan(&columnIndex, &columnName, &columnType, &notNull, &defaultValue, &primaryKey); err != nil {
+			return nil, err
+		}
+
+		columns[columnName] = true
+	}
+
+	return columns, rows.Err()
+}
+
+func getColumnExpression(columns map[string]bool, columnName string, defaultValue string) string {
+	if columns[columnName] {
+		return "COALESCE(" + columnName + ", " + defaultValue + ")"
+	}
+
+	return defaultValue
+}
+
+func createNewMoneyManagerExDataReader(data []byte) (*moneyManagerExDataReader, error) {
+	if !bytes.HasPrefix(data, sqliteFileHeader) {
+		return nil, errs.ErrInvalidMoneyManagerExFile
+	}
+
+	return &moneyManagerExDataReader{
+		databaseData: data,
+	}, nil
+}
@@ -0,0 +1,236 @@
+package moneymanagerex
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/converter"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+func TestMoneyManagerExTransactionDataImporterParseImportedData_DatabaseFile(t *testing.T) {
+	importer := MoneyManagerExTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	testdata, err := os.ReadFile("../../../testdata/moneymanagerex_test_file.mmb")
+	assert.Nil(t, err)
+
+	allNewTransactions, allNewAccounts, allNewSubExpenseCategories, allNewSubIncomeCategories, allNewSubTransferCategories, allNewTags, err := importer.ParseImportedData(context, user, testdata, time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 7, len(allNewTransactions))
+	assert.Equal(t, 3, len(allNewAccounts))
+	assert.Equal(t, 2, len(allNewSubExpenseCategories))
+	assert.Equal(t, 2, len(allNewSubIncomeCategories))
+	assert.Equal(t, 1, len(allNewSubTransferCategories))
+	assert.Equal(t, 3, len(allNewTags))
+
+	assert.Equal(t, int64(1234567890), allNewTransactions[0].Uid)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[0].Type)
+	assert.Equal(t, "2024-09-02 10:30:00", utils.FormatUnixTimeToLongDateTime(utils.GetUnixTimeFromTransactionTime(allNewTransactions[0].TransactionTime), time.UTC))
+	assert.Equal(t, int64(12345), allNewTransactions[0].Amount)
+	assert.Equal(t, "Checking", allNewTransactions[0].OriginalSourceAccountName)
+	assert.Equal(t, "Groceries", allNewTransactions[0].OriginalCategoryName)
+	assert.Equal(t, []string{"family", "weekly"}, allNewTransactions[0].OriginalTagNames)
+	assert.Equal(t, "Weekly shopping", allNewTransactions[0].Comment)
+
+	assert.Equal(t, int64(1234567890), allNewTransactions[1].Uid)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, allNewTransactions[1].Type)
+	assert.Equal(t, "2024-09-03 00:00:00", utils.FormatUnixTimeToLongDateTime(utils.GetUnixTimeFromTransactionTime(allNewTransactions[1].TransactionTime), time.UTC))
+	assert.Equal(t, int64(250000), allNewTransactions[1].Amount)
+	assert.Equal(t, "Checking", allNewTransactions[1].OriginalSourceAccountName)
+	assert.Equal(t, "Salary", allNewTransactions[1].OriginalCategoryName)
+	assert.Equal(t, "Salary", allNewTransactions[1].Comment)
+
+	assert.Equal(t, int64(1234567890), allNewTransactions[2].Uid)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[2].Type)
+	assert.Equal(t, "2024-09-04 00:00:00", utils.FormatUnixTimeToLongDateTime(utils.GetUnixTimeFromTransactionTime(allNewTransactions[2].TransactionTime), time.UTC))
+	assert.Equal(t, int64(50000), allNewTransactions[2].Amount)
+	assert.Equal(t, int64(50000), allNewTransactions[2].RelatedAccountAmount)
+	assert.Equal(t, "Checking", allNewTransactions[2].OriginalSourceAccountName)
+	assert.Equal(t, "Savings", allNewTransactions[2].OriginalDestinationAccountName)
+	assert.Equal(t, "Move to savings", allNewTransactions[2].Comment)
+
+	assert.Equal(t, int64(1234567890), allNewTransactions[3].Uid)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[3].Type)
+	assert.Equal(t, "2024-09-05 00:00:00", utils.FormatUnixTimeToLongDateTime(utils.GetUnixTimeFromTransactionTime(allNewTransactions[3].TransactionTime), time.UTC))
+	assert.Equal(t, int64(2050), allNewTransactions[3].Amount)
+	assert.Equal(t, "Checking", allNewTransactions[3].OriginalSourceAccountName)
+	assert.Equal(t, "Cleaning", allNewTransactions[3].OriginalCategoryName)
+	assert.Equal(t, []string{"family"}, allNewTransactions[3].OriginalTagNames)
+	assert.Equal(t, "Soap", allNewTransactions[3].Comment)
+
+	assert.Equal(t, int64(1234567890), allNewTransactions[4].Uid)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[4].Type)
+	assert.Equal(t, "2024-09-05 00:00:00", utils.FormatUnixTimeToLongDateTime(utils.GetUnixTimeFromTransactionTime(allNewTransactions[4].TransactionTime), time.UTC))
+	assert.Equal(t, int64(3000), allNewTransactions[4].Amount)
+	assert.Equal(t, "Checking", allNewTransactions[4].OriginalSourceAccountName)
+	assert.Equal(t, "Groceries", allNewTransactions[4].OriginalCategoryName)
+	assert.Equal(t, []string{"family", "fruit"}, allNewTransactions[4].OriginalTagNames)
+	assert.Equal(t, "Fruit", allNewTransactions[4].Comment)
+
+	assert.Equal(t, int64(1234567890), allNewTransactions[5].Uid)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[5].Type)
+	assert.Equal(t, "2024-09-06 00:00:00", utils.FormatUnixTimeToLongDateTime(utils.GetUnixTimeFromTransactionTime(allNewTransactions[5].TransactionTime), time.UTC))
+	assert.Equal(t, int64(10000), allNewTransactions[5].Amount)
+	assert.Equal(t, int64(9250), allNewTransactions[5].RelatedAccountAmount)
+	assert.Equal(t, "Checking", allNewTransactions[5].OriginalSourceAccountName)
+	assert.Equal(t, "Euro Account", allNewTransactions[5].OriginalDestinationAccountName)
+	assert.Equal(t, "Travel money", allNewTransactions[5].Comment)
+
+	assert.Equal(t, int64(1234567890), allNewTransactions[6].Uid)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, allNewTransactions[6].Type)
+	assert.Equal(t, "2024-09-08 00:00:00", utils.FormatUnixTimeToLongDateTime(utils.GetUnixTimeFromTransactionTime(allNewTransactions[6].TransactionTime), time.UTC))
+	assert.Equal(t, int64(1200), allNewTransactions[6].Amount)
+	assert.Equal(t, "Savings", allNewTransactions[6].OriginalSourceAccountName)
+	assert.Equal(t, "Groceries", allNewTransactions[6].OriginalCategoryName)
+	assert.Equal(t, "Refund", allNewTransactions[6].Comment)
+
+	assert.Equal(t, int64(1234567890), allNewAccounts[0].Uid)
+	assert.Equal(t, "Checking", allNewAccounts[0].Name)
+	assert.Equal(t, "USD", allNewAccounts[0].Currency)
+
+	assert.Equal(t, int64(1234567890), allNewAccounts[1].Uid)
+	assert.Equal(t, "Savings", allNewAccounts[1].Name)
+	assert.Equal(t, "USD", allNewAccounts[1].Currency)
+
+	assert.Equal(t, int64(1234567890), allNewAccounts[2].Uid)
+	assert.Equal(t, "Euro Account", allNewAccounts[2].Name)
+	assert.Equal(t, "EUR", allNewAccounts[2].Currency)
+
+	assert.Equal(t, "family", allNewTags[0].Name)
+	assert.Equal(t, "weekly", allNewTags[1].Name)
+	assert.Equal(t, "fruit", allNewTags[2].Name)
+}
+
+func TestMoneyManagerExTransactionDataImporterParseImportedData_LegacyDatabaseFile(t *testing.T) {
+	importer := MoneyManagerExTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	testdata, err := os.ReadFile("../../../testdata/moneymanagerex_legacy_test_file.mmb")
+	assert.Nil(t, err)
+
+	expenseCategoryMap := map[string]map[string]*models.TransactionCategory{
+		"Groceries": {
+			"Shopping": &models.TransactionCategory{
+				CategoryId: 1001,
+				Name:       "Groceries",
+			},
+			"Food": &models.TransactionCategory{
+				CategoryId: 1002,
+				Name:       "Groceries",
+			},
+		},
+	}
+
+	allNewTransactions, allNewAccounts, allNewSubExpenseCategories, allNewSubIncomeCategories, _, allNewTags, err := importer.ParseImportedData(context, user, testdata, time.UTC, converter.DefaultImporterOptions, nil, expenseCategoryMap, nil, nil, nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 4, len(allNewTransactions))
+	assert.Equal(t, 1, len(allNewAccounts))
+	assert.Equal(t, 1, len(allNewSubExpenseCategories))
+	assert.Equal(t, 1, len(allNewSubIncomeCategories))
+	assert.Equal(t, 0, len(allNewTags))
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[0].Type)
+	assert.Equal(t, "2024-09-02 00:00:00", utils.FormatUnixTimeToLongDateTime(utils.GetUnixTimeFromTransactionTime(allNewTransactions[0].TransactionTime), time.UTC))
+	assert.Equal(t, int64(1234), allNewTransactions[0].Amount)
+	assert.Equal(t, "Wallet", allNewTransactions[0].OriginalSourceAccountName)
+	assert.Equal(t, int64(1002), allNewTransactions[0].CategoryId)
+	assert.Equal(t, "Milk", allNewTransactions[0].Comment)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, allNewTransactions[1].Type)
+	assert.Equal(t, "2024-09-03 00:00:00", utils.FormatUnixTimeToLongDateTime(utils.GetUnixTimeFromTransactionTime(allNewTransactions[1].TransactionTime), time.UTC))
+	assert.Equal(t, int64(10000), allNewTransactions[1].Amount)
+	assert.Equal(t, "Salary", allNewTransactions[1].OriginalCategoryName)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[2].Type)
+	assert.Equal(t, "2024-09-04 00:00:00", utils.FormatUnixTimeToLongDateTime(utils.GetUnixTimeFromTransactionTime(allNewTransactions[2].TransactionTime), time.UTC))
+	assert.Equal(t, int64(400), allNewTransactions[2].Amount)
+	assert.Equal(t, "Food", allNewTransactions[2].OriginalCategoryName)
+	assert.Equal(t, "Weekly shopping", allNewTransactions[2].Comment)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[3].Type)
+	assert.Equal(t, "2024-09-04 00:00:00", utils.FormatUnixTimeToLongDateTime(utils.GetUnixTimeFromTransactionTime(allNewTransactions[3].TransactionTime), time.UTC))
+	assert.Equal(t, int64(600), allNewTransactions[3].Amount)
+	assert.Equal(t, int64(1002), allNewTransactions[3].CategoryId)
+	assert.Equal(t, "Weekly shopping", allNewTransactions[3].Comment)
+
+	assert.Equal(t, "Wallet", allNewAccounts[0].Name)
+	assert.Equal(t, "USD", allNewAccounts[0].Currency)
+}
+
+func TestMoneyManagerExTransactionDataImporterParseImportedData_PayeeAsTag(t *testing.T) {
+	importer := MoneyManagerExTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "USD",
+	}
+
+	testdata, err := os.ReadFile("../../../testdata/moneymanagerex_test_file.mmb")
+	assert.Nil(t, err)
+
+	allNewTransactions, _, _, _, _, allNewTags, err := importer.ParseImportedData(context, user, testdata, time.UTC, converter.DefaultImporterOptions.WithPayeeAsTag(), nil, nil, nil, nil, nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 5, len(allNewTags))
+	assert.Equal(t, []string{"family", "weekly", "Supermarket"}, allNewTransactions[0].OriginalTagNames)
+	assert.Equal(t, []string{"Employer"}, allNewTransactions[1].OriginalTagNames)
+	assert.Equal(t, 0, len(allNewTransactions[2].OriginalTagNames))
+}
+
+func TestMoneyManagerExTransactionDataImporterParseImportedData_InvalidFile(t *testing.T) {
+	importer := MoneyManagerExTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "USD",
+	}
+
+	_, _, _, _, _, _, err := importer.ParseImportedData(context, user, []byte("not a database file"), time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrInvalidMoneyManagerExFile.Message)
+
+	_, _, _, _, _, _, err = importer.ParseImportedData(context, user, append([]byte("SQLite format 3\x00"), make([]byte, 100)...), time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrInvalidMoneyManagerExFile.Message)
+}
+
+func TestParseMoneyManagerExTransactionTime(t *testing.T) {
+	actualValue, err := parseMoneyManagerExTransactionTime("2024-09-01")
+	assert.Nil(t, err)
+	assert.Equal(t, "2024-09-01 00:00:00", actualValue)
+
+	actualValue, err = parseMoneyManagerExTransactionTime("2024-09-01T23:59:58")
+	assert.Nil(t, err)
+	assert.Equal(t, "2024-09-01 23:59:58", actualValue)
+
+	_, err = parseMoneyManagerExTransactionTime("09/01/2024")
+	assert.EqualError(t, err, errs.ErrTransactionTimeInvalid.Message)
+
+	_, err = parseMoneyManagerExTransactionTime("2024-09-01 23:59:58")
+	assert.EqualError(t, err, errs.ErrTransactionTimeInvalid.Message)
+}
+
+func TestParseMoneyManagerExAmount(t *testing.T) {
+	assert.Equal(t, int64(12345), parseMoneyManagerExAmount(123.45))
+	assert.Equal(t, int64(30), parseMoneyManagerExAmount(0.1+0.2))
+	assert.Equal(t, int64(-2050), parseMoneyManagerExAmount(-20.5))
+}
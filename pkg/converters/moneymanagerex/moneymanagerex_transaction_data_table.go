@@ -0,0 +1,336 @@
+package moneymanagerex
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/datatable"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const moneyManagerExTransactionTagSeparator = ";"
+
+var moneyManagerExTransactionSupportedColumns = map[datatable.TransactionDataTableColumn]bool{
+	datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIME:         true,
+	datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE:         true,
+	datatable.TRANSACTION_DATA_TABLE_CATEGORY:                 true,
+	datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY:             true,
+	datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME:             true,
+	datatable.TRANSACTION_DATA_TABLE_ACCOUNT_CURRENCY:         true,
+	datatable.TRANSACTION_DATA_TABLE_AMOUNT:                   true,
+	datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME:     true,
+	datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_CURRENCY: true,
+	datatable.TRANSACTION_DATA_TABLE_RELATED_AMOUNT:           true,
+	datatable.TRANSACTION_DATA_TABLE_TAGS:                     true,
+	datatable.TRANSACTION_DATA_TABLE_DESCRIPTION:              true,
+	datatable.TRANSACTION_DATA_TABLE_PAYEE:                    true,
+}
+
+// moneyManagerExTransactionDataTable defines the structure of money manager ex transaction data table
+type moneyManagerExTransactionDataTable struct {
+	allData []*moneyManagerExTransactionRowData
+	data    *moneyManagerExData
+}
+
+// moneyManagerExTransactionRowData defines the structure of the transaction data in one row, the split transaction is expanded into one row per split
+type moneyManagerExTransactionRowData struct {
+	transaction      *moneyManagerExTransactionData
+	splitTransaction *moneyManagerExSplitTransactionData
+}
+
+// moneyManagerExTransactionDataRow defines the structure of money manager ex transaction data row
+type moneyManagerExTransactionDataRow struct {
+	dataTable  *moneyManagerExTransactionDataTable
+	data       *moneyManagerExTransactionRowData
+	finalItems map[datatable.TransactionDataTableColumn]string
+	isValid    bool
+}
+
+// moneyManagerExTransactionDataRowIterator defines the structure of money manager ex transaction data row iterator
+type moneyManagerExTransactionDataRowIterator struct {
+	dataTable    *moneyManagerExTransactionDataTable
+	currentIndex int
+}
+
+// HasColumn returns whether the transaction data table has specified column
+func (t *moneyManagerExTransactionDataTable) HasColumn(column datatable.TransactionDataTableColumn) bool {
+	_, exists := moneyManagerExTransactionSupportedColumns[column]
+	return exists
+}
+
+// TransactionRowCount returns the total count of transaction data row
+func (t *moneyManagerExTransactionDataTable) TransactionRowCount() int {
+	return len(t.allData)
+}
+
+// TransactionRowIterator returns the iterator of transaction data row
+func (t *moneyManagerExTransactionDataTable) TransactionRowIterator() datatable.TransactionDataRowIterator {
+	return &moneyManagerExTransactionDataRowIterator{
+		dataTable:    t,
+		currentIndex: -1,
+	}
+}
+
+// IsValid returns whether this row is valid data for importing
+func (r *moneyManagerExTransactionDataRow) IsValid() bool {
+	return r.isValid
+}
+
+// GetData returns the data in the specified column type
+func (r *moneyManagerExTransactionDataRow) GetData(column datatable.TransactionDataTableColumn) string {
+	_, exists := moneyManagerExTransactionSupportedColumns[column]
+
+	if exists {
+		return r.finalItems[column]
+	}
+
+	return ""
+}
+
+// HasNext returns whether the iterator does not reach the end
+func (t *moneyManagerExTransactionDataRowIterator) HasNext() bool {
+	return t.currentIndex+1 < len(t.dataTable.allData)
+}
+
+// Next returns the next transaction data row
+func (t *moneyManagerExTransactionDataRowIterator) Next(ctx core.Context, user *models.User) (daraRow datatable.TransactionDataRow, err error) {
+	if t.currentIndex+1 >= len(t.dataTable.allData) {
+		return nil, nil
+	}
+
+	t.currentIndex++
+
+	data := t.dataTable.allData[t.currentIndex]
+	rowItems, isValid, err := t.parseTransaction(ctx, user, data)
+
+	if err != nil {
+		log.Errorf(ctx, "[moneymanagerex_transaction_data_table.Next] cannot parsing transaction in row#%d, because %s", t.currentIndex, err.Error())
+		return nil, err
+	}
+
+	return &moneyManagerExTransactionDataRow{
+		dataTable:  t.dataTable,
+		data:       data,
+		finalItems: rowItems,
+		isValid:    isValid,
+	}, nil
+}
+
+func (t *moneyManagerExTransactionDataRowIterator) parseTransaction(ctx core.Context, user *models.User, rowData *moneyManagerExTransactionRowData) (map[datatable.TransactionDataTableColumn]string, bool, error) {
+	data := make(map[datatable.TransactionDataTableColumn]string, len(moneyManagerExTransactionSupportedColumns))
+	transaction := rowData.transaction
+	splitTransaction := rowData.splitTransaction
+
+	if transaction.Date == "" {
+		return nil, false, errs.ErrMissingTransactionTime
+	}
+
+	transactionTime, err := parseMoneyManagerExTransactionTime(transaction.Date)
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIME] = transactionTime
+
+	amount := parseMoneyManagerExAmount(transaction.Amount)
+	categoryId := transaction.CategoryId
+	subCategoryId := transaction.SubCategoryId
+
+	if splitTransaction != nil {
+		amount = parseMoneyManagerExAmount(splitTransaction.Amount)
+		categoryId = splitTransaction.CategoryId
+		subCategoryId = splitTransaction.SubCategoryId
+	}
+
+	if amount == 0 {
+		log.Warnf(ctx, "[moneymanagerex_transaction_data_table.parseTransaction] skip parsing transaction \"id:%d\" with zero amount", transaction.Id)
+		return nil, false, nil
+	}
+
+	account := t.dataTable.data.Accounts[transaction.AccountId]
+
+	if account == nil {
+		return nil, false, errs.ErrMissingAccountData
+	}
+
+	data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME] = account.Name
+	data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_CURRENCY] = t.dataTable.getAccountCurrency(account)
+
+	if transaction.TransactionCode == moneyManagerExTransferTransactionCode {
+		toAccount := t.dataTable.data.Accounts[transaction.ToAccountId]
+
+		if toAccount == nil {
+			return nil, false, errs.ErrMissingAccountData
+		}
+
+		toAmount := parseMoneyManagerExAmount(transaction.ToAmount)
+
+		if toAmount == 0 {
+			toAmount = amount
+		}
+
+		data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = moneyManagerExTransactionTypeNameMapping[models.TRANSACTION_TYPE_TRANSFER]
+		data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(amount)
+		data[datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME] = toAccount.Name
+		data[datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_CURRENCY] = t.dataTable.getAccountCurrency(toAccount)
+		data[datatable.TRANSACTION_DATA_TABLE_RELATED_AMOUNT] = utils.FormatAmount(toAmount)
+	} else if transaction.TransactionCode == moneyManagerExWithdrawalTransactionCode || transaction.TransactionCode == moneyManagerExDepositTransactionCode {
+		if transaction.TransactionCode == moneyManagerExWithdrawalTransactionCode {
+			amount = -amount
+		}
+
+		if amount > 0 {
+			data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = moneyManagerExTransactionTypeNameMapping[models.TRANSACTION_TYPE_INCOME]
+			data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(amount)
+		} else {
+			data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = moneyManagerExTransactionTypeNameMapping[models.TRANSACTION_TYPE_EXPENSE]
+			data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(-amount)
+		}
+
+		data[datatable.TRANSACTION_DATA_TABLE_CATEGORY], data[datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY] = t.dataTable.getCategoryNames(categoryId, subCategoryId)
+	} else {
+		log.Errorf(ctx, "[moneymanagerex_transaction_data_table.parseTransaction] cannot parse transaction \"id:%d\", because unexcepted transaction code \"%s\"", transaction.Id, transaction.TransactionCode)
+		return nil, false, errs.ErrThereAreNotSupportedTransactionType
+	}
+
+	if payee := t.dataTable.data.Payees[transaction.PayeeId]; payee != nil && transaction.TransactionCode != moneyManagerExTransferTransactionCode {
+		data[datatable.TRANSACTION_DATA_TABLE_PAYEE] = payee.Name
+	}
+
+	data[datatable.TRANSACTION_DATA_TABLE_TAGS] = strings.Join(t.dataTable.getTagNames(rowData), moneyManagerExTransactionTagSeparator)
+
+	if splitTransaction != nil && splitTransaction.Notes != "" {
+		data[datatable.TRANSACTION_DATA_TABLE_DESCRIPTION] = splitTransaction.Notes
+	} else {
+		data[datatable.TRANSACTION_DATA_TABLE_DESCRIPTION] = transaction.Notes
+	}
+
+	return data, true, nil
+}
+
+func (t *moneyManagerExTransactionDataTable) getAccountCurrency(account *moneyManagerExAccountData) string {
+	if currency := t.data.Currencies[account.CurrencyId]; currency != nil {
+		return currency.Symbol
+	}
+
+	return ""
+}
+
+func (t *moneyManagerExTransactionDataTable) getCategoryNames(categoryId int64, subCategoryId int64) (string, string) {
+	if subCategory := t.data.SubCategories[subCategoryId]; subCategoryId > 0 && subCategory != nil {
+		if category := t.data.Categories[subCategory.CategoryId]; category != nil {
+			return category.Name, subCategory.Name
+		}
+
+		return "", subCategory.Name
+	}
+
+	category := t.data.Categories[categoryId]
+
+	if category == nil {
+		return "", ""
+	}
+
+	if parentCategory := t.data.Categories[category.ParentId]; category.ParentId > 0 && parentCategory != nil {
+		return parentCategory.Name, category.Name
+	}
+
+	return "", category.Name
+}
+
+func (t *moneyManagerExTransactionDataTable) getTagNames(rowData *moneyManagerExTransactionRowData) []string {
+	tagIds := t.data.TransactionTagIds[rowData.transaction.Id]
+
+	if rowData.splitTransaction != nil {
+		tagIds = append(append([]int64{}, tagIds...), t.data.SplitTransactionTagIds[rowData.splitTransaction.Id]...)
+	}
+
+	tagNames := make([]string, 0, len(tagIds))
+	tagNamesMap := make(map[string]bool, len(tagIds))
+
+	for i := 0; i < len(tagIds); i++ {
+		tag := t.data.Tags[tagIds[i]]
+
+		if tag == nil || tagNamesMap[tag.Name] {
+			continue
+		}
+
+		tagNames = append(tagNames, tag.Name)
+		tagNamesMap[tag.Name] = true
+	}
+
+	return tagNames
+}
+
+// parseMoneyManagerExTransactionTime returns the long date time of the transaction date (e.g. 2024-09-01 or 2024-09-01T10:30:00) which is used in money manager ex
+func parseMoneyManagerExTransactionTime(date string) (string, error) {
+	var transactionTime time.Time
+	var err error
+
+	if len(date) > 10 {
+		transactionTime, err = time.Parse("2006-01-02T15:04:05", date)
+	} else {
+		transactionTime, err = time.Parse("2006-01-02", date)
+	}
+
+	if err != nil {
+		return "", errs.ErrTransactionTimeInvalid
+	}
+
+	return transactionTime.Format("2006-01-02 15:04:05"), nil
+}
+
+// parseMoneyManagerExAmount returns the amount in cents of the floating number which is used in money manager ex
+func parseMoneyManagerExAmount(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
+
+// getMoneyManagerExImportedTransactionRows returns the rows of transactions which should be imported, the void transactions are skipped,
+// and the split transaction is expanded into one row per split
+func getMoneyManagerExImportedTransactionRows(ctx core.Context, data *moneyManagerExData) []*moneyManagerExTransactionRowData {
+	result := make([]*moneyManagerExTransactionRowData, 0, len(data.Transactions))
+
+	for i := 0; i < len(data.Transactions); i++ {
+		transaction := data.Transactions[i]
+
+		if transaction.Status == moneyManagerExVoidTransactionStatus {
+			log.Warnf(ctx, "[moneymanagerex_transaction_data_table.getMoneyManagerExImportedTransactionRows] skip void transaction \"id:%d\"", transaction.Id)
+			continue
+		}
+
+		splitTransactions := data.SplitTransactions[transaction.Id]
+
+		if len(splitTransactions) < 1 || transaction.TransactionCode == moneyManagerExTransferTransactionCode {
+			result = append(result, &moneyManagerExTransactionRowData{
+				transaction: transaction,
+			})
+			continue
+		}
+
+		for j := 0; j < len(splitTransactions); j++ {
+			result = append(result, &moneyManagerExTransactionRowData{
+				transaction:      transaction,
+				splitTransaction: splitTransactions[j],
+			})
+		}
+	}
+
+	return result
+}
+
+func createNewMoneyManagerExTransactionDataTable(ctx core.Context, data *moneyManagerExData) (*moneyManagerExTransactionDataTable, error) {
+	if data == nil || len(data.Transactions) < 1 {
+		return nil, errs.ErrNotFoundTransactionDataInFile
+	}
+
+	return &moneyManagerExTransactionDataTable{
+		allData: getMoneyManagerExImportedTransactionRows(ctx, data),
+		data:    data,
+	}, nil
+}
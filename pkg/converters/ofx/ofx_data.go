@@ -71,8 +71,9 @@ var ofxTransactionTypeMapping = map[ofxTransactionType]models.TransactionType{
 
 // ofxFile represents the struct of open financial exchange (ofx) file
 type ofxFile struct {
-	XMLName                     xml.Name `xml:"OFX"`
-	FileHeader                  *ofxFileHeader
+	XMLName                     xml.Name                        `xml:"OFX"`
+	FileHeader                  *ofxFileHeader                  `xml:"-"`
+	SignOnMessageResponseV1     *ofxSignOnMessageResponseV1     `xml:"SIGNONMSGSRSV1"`
 	BankMessageResponseV1       *ofxBankMessageResponseV1       `xml:"BANKMSGSRSV1"`
 	CreditCardMessageResponseV1 *ofxCreditCardMessageResponseV1 `xml:"CREDITCARDMSGSRSV1"`
 }
@@ -86,24 +87,48 @@ type ofxFileHeader struct {
 	NewFileUid            string
 }
 
+// ofxSignOnMessageResponseV1 represents the struct of open financial exchange (ofx) sign on message response v1
+type ofxSignOnMessageResponseV1 struct {
+	SignOnResponse *ofxSignOnResponse `xml:"SONRS"`
+}
+
+// ofxSignOnResponse represents the struct of open financial exchange (ofx) sign on response
+type ofxSignOnResponse struct {
+	Status     *ofxStatus `xml:"STATUS"`
+	ServerDate string     `xml:"DTSERVER"`
+	Language   string     `xml:"LANGUAGE"`
+}
+
+// ofxStatus represents the struct of open financial exchange (ofx) status
+type ofxStatus struct {
+	Code     string `xml:"CODE"`
+	Severity string `xml:"SEVERITY"`
+}
+
 // ofxBankMessageResponseV1 represents the struct of open financial exchange (ofx) bank message response v1
 type ofxBankMessageResponseV1 struct {
-	StatementTransactionResponse *ofxBankStatementTransactionResponse `xml:"STMTTRNRS"`
+	StatementTransactionResponse  *ofxBankStatementTransactionResponse   `xml:"-"`
+	StatementTransactionResponses []*ofxBankStatementTransactionResponse `xml:"STMTTRNRS"`
 }
 
 // ofxCreditCardMessageResponseV1 represents the struct of open financial exchange (ofx) credit card message response v1
 type ofxCreditCardMessageResponseV1 struct {
-	StatementTransactionResponse *ofxCreditCardStatementTransactionResponse `xml:"CCSTMTTRNRS"`
+	StatementTransactionResponse  *ofxCreditCardStatementTransactionResponse   `xml:"-"`
+	StatementTransactionResponses []*ofxCreditCardStatementTransactionResponse `xml:"CCSTMTTRNRS"`
 }
 
 // ofxBankStatementTransactionResponse represents the struct of open financial exchange (ofx) bank statement transaction response
 type ofxBankStatementTransactionResponse struct {
-	StatementResponse *ofxBankStatementResponse `xml:"STMTRS"`
+	TransactionUniqueId string                    `xml:"TRNUID"`
+	Status              *ofxStatus                `xml:"STATUS"`
+	StatementResponse   *ofxBankStatementResponse `xml:"STMTRS"`
 }
 
 // ofxCreditCardStatementTransactionResponse represents the struct of open financial exchange (ofx) credit card statement transaction response
 type ofxCreditCardStatementTransactionResponse struct {
-	StatementResponse *ofxCreditCardStatementResponse `xml:"CCSTMTRS"`
+	TransactionUniqueId string                          `xml:"TRNUID"`
+	Status              *ofxStatus                      `xml:"STATUS"`
+	StatementResponse   *ofxCreditCardStatementResponse `xml:"CCSTMTRS"`
 }
 
 // ofxBankStatementResponse represents the struct of open financial exchange (ofx) bank statement response
@@ -111,6 +136,7 @@ type ofxBankStatementResponse struct {
 	DefaultCurrency string                  `xml:"CURDEF"`
 	AccountFrom     *ofxBankAccount         `xml:"BANKACCTFROM"`
 	TransactionList *ofxBankTransactionList `xml:"BANKTRANLIST"`
+	LedgerBalance   *ofxBalance             `xml:"LEDGERBAL"`
 }
 
 // ofxCreditCardStatementResponse represents the struct of open financial exchange (ofx) credit card statement response
@@ -118,21 +144,22 @@ type ofxCreditCardStatementResponse struct {
 	DefaultCurrency string                        `xml:"CURDEF"`
 	AccountFrom     *ofxCreditCardAccount         `xml:"CCACCTFROM"`
 	TransactionList *ofxCreditCardTransactionList `xml:"BANKTRANLIST"`
+	LedgerBalance   *ofxBalance                   `xml:"LEDGERBAL"`
 }
 
 // ofxBankAccount represents the struct of open financial exchange (ofx) bank account
 type ofxBankAccount struct {
-	BankId      string         `xml:"BANKID"`
-	BranchId    string         `xml:"BRANCHID"`
+	BankId      string         `xml:"BANKID,omitempty"`
+	BranchId    string         `xml:"BRANCHID,omitempty"`
 	AccountId   string         `xml:"ACCTID"`
 	AccountType ofxAccountType `xml:"ACCTTYPE"`
-	AccountKey  string         `xml:"ACCTKEY"`
+	AccountKey  string         `xml:"ACCTKEY,omitempty"`
 }
 
 // ofxCreditCardAccount represents the struct of open financial exchange (ofx) credit card account
 type ofxCreditCardAccount struct {
 	AccountId  string `xml:"ACCTID"`
-	AccountKey string `xml:"ACCTKEY"`
+	AccountKey string `xml:"ACCTKEY,omitempty"`
 }
 
 // ofxBankTransactionList represents the struct of open financial exchange (ofx) bank transaction list
@@ -151,15 +178,15 @@ type ofxCreditCardTransactionList struct {
 
 // ofxBaseStatementTransaction represents the struct of open financial exchange (ofx) base statement transaction
 type ofxBaseStatementTransaction struct {
-	TransactionId    string             `xml:"FITID"`
 	TransactionType  ofxTransactionType `xml:"TRNTYPE"`
 	PostedDate       string             `xml:"DTPOSTED"`
 	Amount           string             `xml:"TRNAMT"`
-	Name             string             `xml:"NAME"`
+	TransactionId    string             `xml:"FITID"`
+	Name             string             `xml:"NAME,omitempty"`
 	Payee            *ofxPayee          `xml:"PAYEE"`
-	Memo             string             `xml:"MEMO"`
-	Currency         string             `xml:"CURRENCY"`
-	OriginalCurrency string             `xml:"ORIGCURRENCY"`
+	Memo             string             `xml:"MEMO,omitempty"`
+	Currency         string             `xml:"CURRENCY,omitempty"`
+	OriginalCurrency string             `xml:"ORIGCURRENCY,omitempty"`
 }
 
 // ofxBankStatementTransaction represents the struct of open financial exchange (ofx) bank statement transaction
@@ -174,6 +201,12 @@ type ofxCreditCardStatementTransaction struct {
 	AccountTo *ofxCreditCardAccount `xml:"CCACCTTO"`
 }
 
+// ofxBalance represents the struct of open financial exchange (ofx) balance
+type ofxBalance struct {
+	Amount string `xml:"BALAMT"`
+	Date   string `xml:"DTASOF"`
+}
+
 // ofxPayee represents the struct of open financial exchange (ofx) payee info
 type ofxPayee struct {
 	Name       string `xml:"NAME"`
@@ -186,3 +219,14 @@ type ofxPayee struct {
 	Country    string `xml:"COUNTRY"`
 	Phone      string `xml:"PHONE"`
 }
+
+// fillFirstStatementTransactionResponses sets the first statement transaction response of each message response after the file is decoded
+func (f *ofxFile) fillFirstStatementTransactionResponses() {
+	if f.BankMessageResponseV1 != nil && len(f.BankMessageResponseV1.StatementTransactionResponses) > 0 {
+		f.BankMessageResponseV1.StatementTransactionResponse = f.BankMessageResponseV1.StatementTransactionResponses[0]
+	}
+
+	if f.CreditCardMessageResponseV1 != nil && len(f.CreditCardMessageResponseV1.StatementTransactionResponses) > 0 {
+		f.CreditCardMessageResponseV1.StatementTransactionResponse = f.CreditCardMessageResponseV1.StatementTransactionResponses[0]
+	}
+}
@@ -60,6 +60,7 @@ func (r *ofxVersion1FileReader) read(ctx core.Context) (*ofxFile, error) {
 	}
 
 	file.FileHeader = r.fileHeader
+	file.fillFirstStatementTransactionResponses()
 
 	return file, nil
 }
@@ -76,6 +77,7 @@ func (r *ofxVersion2FileReader) read(ctx core.Context) (*ofxFile, error) {
 	}
 
 	file.FileHeader = r.fileHeader
+	file.fillFirstStatementTransactionResponses()
 
 	return file, nil
 }
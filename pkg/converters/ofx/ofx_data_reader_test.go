@@ -589,6 +589,69 @@ func TestCreateNewOFXFileReader_OFX1WithoutCharset(t *testing.T) {
 	assert.Equal(t, "NONE", ofxFile.FileHeader.NewFileUid)
 }
 
+func TestCreateNewOFXFileReader_OFX1MultipleStatements(t *testing.T) {
+	context := core.NewNullContext()
+	reader, err := createNewOFXFileReader(context, []byte(
+		"OFXHEADER:100\n"+
+			"DATA:OFXSGML\n"+
+			"VERSION:103\n"+
+			"SECURITY:NONE\n"+
+			"ENCODING:USASCII\n"+
+			"CHARSET:1252\n"+
+			"COMPRESSION:NONE\n"+
+			"OLDFILEUID:NONE\n"+
+			"NEWFILEUID:NONE\n"+
+			"\n"+
+			"<OFX>\n"+
+			"<BANKMSGSRSV1>\n"+
+			"<STMTTRNRS>\n"+
+			"<STMTRS>\n"+
+			"<CURDEF>CNY\n"+
+			"<BANKACCTFROM>\n"+
+			"<ACCTID>123\n"+
+			"</BANKACCTFROM>\n"+
+			"</STMTRS>\n"+
+			"</STMTTRNRS>\n"+
+			"<STMTTRNRS>\n"+
+			"<STMTRS>\n"+
+			"<CURDEF>USD\n"+
+			"<BANKACCTFROM>\n"+
+			"<ACCTID>456\n"+
+			"</BANKACCTFROM>\n"+
+			"</STMTRS>\n"+
+			"</STMTTRNRS>\n"+
+			"</BANKMSGSRSV1>\n"+
+			"<CREDITCARDMSGSRSV1>\n"+
+			"<CCSTMTTRNRS>\n"+
+			"<CCSTMTRS>\n"+
+			"<CURDEF>EUR\n"+
+			"<CCACCTFROM>\n"+
+			"<ACCTID>789\n"+
+			"</CCACCTFROM>\n"+
+			"</CCSTMTRS>\n"+
+			"</CCSTMTTRNRS>\n"+
+			"</CREDITCARDMSGSRSV1>\n"+
+			"</OFX>"))
+
+	assert.Nil(t, err)
+
+	ofxFile, err := reader.read(context)
+	assert.Nil(t, err)
+	assert.NotNil(t, ofxFile)
+
+	assert.NotNil(t, ofxFile.BankMessageResponseV1)
+	assert.Equal(t, "123", ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.AccountFrom.AccountId)
+	assert.Equal(t, 2, len(ofxFile.BankMessageResponseV1.StatementTransactionResponses))
+	assert.Equal(t, "CNY", ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.DefaultCurrency)
+	assert.Equal(t, "123", ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.AccountFrom.AccountId)
+	assert.Equal(t, "USD", ofxFile.BankMessageResponseV1.StatementTransactionResponses[1].StatementResponse.DefaultCurrency)
+	assert.Equal(t, "456", ofxFile.BankMessageResponseV1.StatementTransactionResponses[1].StatementResponse.AccountFrom.AccountId)
+
+	assert.NotNil(t, ofxFile.CreditCardMessageResponseV1)
+	assert.Equal(t, "789", ofxFile.CreditCardMessageResponseV1.StatementTransactionResponse.StatementResponse.AccountFrom.AccountId)
+	assert.Equal(t, 1, len(ofxFile.CreditCardMessageResponseV1.StatementTransactionResponses))
+}
+
 func TestCreateNewOFXFileReader_OFX1WithInvalidHeaderVersion(t *testing.T) {
 	context := core.NewNullContext()
 	_, err := createNewOFXFileReader(context, []byte(
@@ -763,6 +826,45 @@ func TestCreateNewOFXFileReader_OFX2WithoutBreakLine(t *testing.T) {
 	assert.Equal(t, "123.45", ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.TransactionList.StatementTransactions[0].Amount)
 }
 
+func TestCreateNewOFXFileReader_OFX2MultipleStatements(t *testing.T) {
+	context := core.NewNullContext()
+	reader, err := createNewOFXFileReader(context, []byte(
+		"<?xml version=\"1.0\" encoding=\"US-ASCII\"?>\n"+
+			"<?OFX OFXHEADER=\"200\" VERSION=\"211\" SECURITY=\"NONE\" OLDFILEUID=\"NONE\" NEWFILEUID=\"NONE\"?>\n"+
+			"<OFX>\n"+
+			"  <BANKMSGSRSV1>\n"+
+			"    <STMTTRNRS>\n"+
+			"      <STMTRS>\n"+
+			"        <CURDEF>CNY</CURDEF>\n"+
+			"        <BANKACCTFROM>\n"+
+			"          <ACCTID>123</ACCTID>\n"+
+			"        </BANKACCTFROM>\n"+
+			"      </STMTRS>\n"+
+			"    </STMTTRNRS>\n"+
+			"    <STMTTRNRS>\n"+
+			"      <STMTRS>\n"+
+			"        <CURDEF>USD</CURDEF>\n"+
+			"        <BANKACCTFROM>\n"+
+			"          <ACCTID>456</ACCTID>\n"+
+			"        </BANKACCTFROM>\n"+
+			"      </STMTRS>\n"+
+			"    </STMTTRNRS>\n"+
+			"  </BANKMSGSRSV1>\n"+
+			"</OFX>"))
+
+	assert.Nil(t, err)
+
+	ofxFile, err := reader.read(context)
+	assert.Nil(t, err)
+	assert.NotNil(t, ofxFile)
+
+	assert.NotNil(t, ofxFile.BankMessageResponseV1)
+	assert.Equal(t, "123", ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.AccountFrom.AccountId)
+	assert.Equal(t, 2, len(ofxFile.BankMessageResponseV1.StatementTransactionResponses))
+	assert.Equal(t, "123", ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.AccountFrom.AccountId)
+	assert.Equal(t, "456", ofxFile.BankMessageResponseV1.StatementTransactionResponses[1].StatementResponse.AccountFrom.AccountId)
+}
+
 func TestCreateNewOFXFileReader_OFX2WithoutOFXHeader(t *testing.T) {
 	context := core.NewNullContext()
 	_, err := createNewOFXFileReader(context, []byte(
@@ -0,0 +1,319 @@
+package ofx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/converter"
+	"github.com/mayswind/ezbookkeeping/pkg/converters/sgml"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const ofx1ExportedFileHeader = "OFXHEADER:100\n" +
+	"DATA:OFXSGML\n" +
+	"VERSION:102\n" +
+	"SECURITY:NONE\n" +
+	"ENCODING:USASCII\n" +
+	"CHARSET:1252\n" +
+	"COMPRESSION:NONE\n" +
+	"OLDFILEUID:NONE\n" +
+	"NEWFILEUID:NONE\n" +
+	"\n"
+
+const ofx2ExportedFileHeader = "<?xml version=\"1.0\" encoding=\"UTF-8\" standalone=\"no\"?>\n" +
+	"<?OFX OFXHEADER=\"200\" VERSION=\"220\" SECURITY=\"NONE\" OLDFILEUID=\"NONE\" NEWFILEUID=\"NONE\"?>\n"
+
+const ofxExportedDateTimeFormat = "20060102150405"
+const ofxExportedLanguage = "ENG"
+const ofxExportedTransactionUniqueId = "0"
+const ofxStatusCodeSuccess = "0"
+const ofxStatusSeverityInfo = "INFO"
+
+// ofxTransactionDataExporter defines the structure of open financial exchange (ofx) file exporter for transaction data
+type ofxTransactionDataExporter struct {
+	declarationVersion oFXDeclarationVersion
+}
+
+// Initialize a open financial exchange (ofx) transaction data exporter singleton instance
+var (
+	OFXTransactionDataExporter = &ofxTransactionDataExporter{
+		declarationVersion: ofxVersion2,
+	}
+	QFXTransactionDataExporter = &ofxTransactionDataExporter{
+		declarationVersion: ofxVersion1,
+	}
+)
+
+// ToExportedContent returns the exported open financial exchange (ofx) data, each account is exported as a statement,
+// the ofx 2.x file is written in xml, and the ofx 1.x (qfx) file is written in sgml
+func (c *ofxTransactionDataExporter) ToExportedContent(ctx core.Context, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64, customFields []*models.TransactionCustomField, allCustomFieldValues map[int64][]*models.TransactionCustomFieldValue, options converter.TransactionDataExporterOptions) ([]byte, error) {
+	file := c.buildOFXFile(ctx, transactions, accountMap, time.Now().Unix(), options)
+
+	if c.declarationVersion == ofxVersion1 {
+		return c.writeOFX1File(file)
+	}
+
+	return c.writeOFX2File(file)
+}
+
+func (c *ofxTransactionDataExporter) buildOFXFile(ctx core.Context, transactions []*models.Transaction, accountMap map[int64]*models.Account, currentUnixTime int64, options converter.TransactionDataExporterOptions) *ofxFile {
+	accountTransactions := make(map[int64][]*models.Transaction)
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+
+		if _, exists := accountMap[transaction.AccountId]; !exists {
+			log.Warnf(ctx, "[ofx_transaction_data_file_exporter.buildOFXFile] account \"id:%d\" of transaction \"id:%d\" does not exist", transaction.AccountId, transaction.TransactionId)
+			continue
+		}
+
+		accountTransactions[transaction.AccountId] = append(accountTransactions[transaction.AccountId], transaction)
+	}
+
+	accountIds := make([]int64, 0, len(accountTransactions))
+
+	for accountId := range accountTransactions {
+		accountIds = append(accountIds, accountId)
+	}
+
+	sort.Slice(accountIds, func(i, j int) bool {
+		return accountIds[i] < accountIds[j]
+	})
+
+	currentDateTime := formatOFXDateTime(currentUnixTime, 0)
+	file := &ofxFile{
+		SignOnMessageResponseV1: &ofxSignOnMessageResponseV1{
+			SignOnResponse: &ofxSignOnResponse{
+				Status:     createOFXSuccessStatus(),
+				ServerDate: currentDateTime,
+				Language:   ofxExportedLanguage,
+			},
+		},
+	}
+
+	for i := 0; i < len(accountIds); i++ {
+		account := accountMap[accountIds[i]]
+		allTransactions := accountTransactions[account.AccountId]
+
+		sort.SliceStable(allTransactions, func(i, j int) bool {
+			return allTransactions[i].TransactionTime < allTransactions[j].TransactionTime
+		})
+
+		startDate := getOFXTransactionPostedDate(allTransactions[0], options.GetTransactionTimezone(allTransactions[0]))
+		endDate := getOFXTransactionPostedDate(allTransactions[len(allTransactions)-1], options.GetTransactionTimezone(allTransactions[len(allTransactions)-1]))
+		ledgerBalance := &ofxBalance{
+			Amount: utils.FormatAmount(account.Balance),
+			Date:   currentDateTime,
+		}
+
+		if account.Category == models.ACCOUNT_CATEGORY_CREDIT_CARD {
+			statementTransactions := make([]*ofxCreditCardStatementTransaction, len(allTransactions))
+
+			for j := 0; j < len(allTransactions); j++ {
+				statementTransactions[j] = &ofxCreditCardStatementTransaction{
+					ofxBaseStatementTransaction: createOFXBaseStatementTransaction(allTransactions[j], options.GetTransactionTimezone(allTransactions[j])),
+				}
+
+				if relatedAccount := getOFXTransferTargetAccount(allTransactions[j], accountMap); relatedAccount != nil {
+					statementTransactions[j].AccountTo = &ofxCreditCardAccount{
+						AccountId: relatedAccount.Name,
+					}
+				}
+			}
+
+			if file.CreditCardMessageResponseV1 == nil {
+				file.CreditCardMessageResponseV1 = &ofxCreditCardMessageResponseV1{}
+			}
+
+			file.CreditCardMessageResponseV1.StatementTransactionResponses = append(file.CreditCardMessageResponseV1.StatementTransactionResponses, &ofxCreditCardStatementTransactionResponse{
+				TransactionUniqueId: ofxExportedTransactionUniqueId,
+				Status:              createOFXSuccessStatus(),
+				StatementResponse: &ofxCreditCardStatementResponse{
+					DefaultCurrency: account.Currency,
+					AccountFrom: &ofxCreditCardAccount{
+						AccountId: account.Name,
+					},
+					TransactionList: &ofxCreditCardTransactionList{
+						StartDate:             startDate,
+						EndDate:               endDate,
+						StatementTransactions: statementTransactions,
+					},
+					LedgerBalance: ledgerBalance,
+				},
+			})
+		} else {
+			statementTransactions := make([]*ofxBankStatementTransaction, len(allTransactions))
+
+			for j := 0; j < len(allTransactions); j++ {
+				statementTransactions[j] = &ofxBankStatementTransaction{
+					ofxBaseStatementTransaction: createOFXBaseStatementTransaction(allTransactions[j], options.GetTransactionTimezone(allTransactions[j])),
+				}
+
+				if relatedAccount := getOFXTransferTargetAccount(allTransactions[j], accountMap); relatedAccount != nil {
+					statementTransactions[j].AccountTo = &ofxBankAccount{
+						AccountId:   relatedAccount.Name,
+						AccountType: getOFXAccountType(relatedAccount),
+					}
+				}
+			}
+
+			if file.BankMessageResponseV1 == nil {
+				file.BankMessageResponseV1 = &ofxBankMessageResponseV1{}
+			}
+
+			file.BankMessageResponseV1.StatementTransactionResponses = append(file.BankMessageResponseV1.StatementTransactionResponses, &ofxBankStatementTransactionResponse{
+				TransactionUniqueId: ofxExportedTransactionUniqueId,
+				Status:              createOFXSuccessStatus(),
+				StatementResponse: &ofxBankStatementResponse{
+					DefaultCurrency: account.Currency,
+					AccountFrom: &ofxBankAccount{
+						AccountId:   account.Name,
+						AccountType: getOFXAccountType(account),
+					},
+					TransactionList: &ofxBankTransactionList{
+						StartDate:             startDate,
+						EndDate:               endDate,
+						StatementTransactions: statementTransactions,
+					},
+					LedgerBalance: ledgerBalance,
+				},
+			})
+		}
+	}
+
+	return file
+}
+
+func (c *ofxTransactionDataExporter) writeOFX1File(file *ofxFile) ([]byte, error) {
+	buffer := &bytes.Buffer{}
+	writer := encoding.ReplaceUnsupported(charmap.Windows1252.NewEncoder()).Writer(buffer)
+
+	_, err := writer.Write([]byte(ofx1ExportedFileHeader))
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = sgml.NewEncoder(writer).Encode(file)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func (c *ofxTransactionDataExporter) writeOFX2File(file *ofxFile) ([]byte, error) {
+	buffer := &bytes.Buffer{}
+	buffer.WriteString(ofx2ExportedFileHeader)
+
+	xmlEncoder := xml.NewEncoder(buffer)
+	xmlEncoder.Indent("", "  ")
+
+	err := xmlEncoder.Encode(file)
+
+	if err != nil {
+		return nil, err
+	}
+
+	buffer.WriteString("\n")
+
+	return buffer.Bytes(), nil
+}
+
+func createOFXSuccessStatus() *ofxStatus {
+	return &ofxStatus{
+		Code:     ofxStatusCodeSuccess,
+		Severity: ofxStatusSeverityInfo,
+	}
+}
+
+func createOFXBaseStatementTransaction(transaction *models.Transaction, timezone *time.Location) ofxBaseStatementTransaction {
+	transactionType := ofxOtherTransaction
+	amount := int64(0)
+
+	switch transaction.Type {
+	case models.TRANSACTION_DB_TYPE_MODIFY_BALANCE:
+		transactionType = ofxOtherTransaction
+		amount = transaction.RelatedAccountAmount
+	case models.TRANSACTION_DB_TYPE_INCOME:
+		transactionType = ofxDepositTransaction
+		amount = transaction.Amount
+	case models.TRANSACTION_DB_TYPE_EXPENSE:
+		transactionType = ofxGenericDebitTransaction
+		amount = -transaction.Amount
+	case models.TRANSACTION_DB_TYPE_TRANSFER_OUT:
+		transactionType = ofxTransferTransaction
+		amount = -transaction.Amount
+	case models.TRANSACTION_DB_TYPE_TRANSFER_IN:
+		transactionType = ofxTransferTransaction
+		amount = transaction.Amount
+	}
+
+	return ofxBaseStatementTransaction{
+		TransactionType: transactionType,
+		PostedDate:      getOFXTransactionPostedDate(transaction, timezone),
+		Amount:          utils.FormatAmount(amount),
+		TransactionId:   utils.Int64ToString(transaction.TransactionId),
+		Memo:            transaction.Comment,
+	}
+}
+
+// getOFXTransferTargetAccount returns the target account of the transfer out transaction,
+// the target account is not returned if it uses a different currency, because ofx cannot store the amount in the target account currency
+func getOFXTransferTargetAccount(transaction *models.Transaction, accountMap map[int64]*models.Account) *models.Account {
+	if transaction.Type != models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+		return nil
+	}
+
+	account := accountMap[transaction.AccountId]
+	relatedAccount := accountMap[transaction.RelatedAccountId]
+
+	if account == nil || relatedAccount == nil || account.Currency != relatedAccount.Currency {
+		return nil
+	}
+
+	return relatedAccount
+}
+
+func getOFXAccountType(account *models.Account) ofxAccountType {
+	switch account.Category {
+	case models.ACCOUNT_CATEGORY_SAVINGS_ACCOUNT:
+		return ofxSavingsAccount
+	case models.ACCOUNT_CATEGORY_CERTIFICATE_OF_DEPOSIT:
+		return ofxCertificateOfDepositAccount
+	case models.ACCOUNT_CATEGORY_CREDIT_CARD, models.ACCOUNT_CATEGORY_DEBT:
+		return ofxLineOfCreditAccount
+	default:
+		return ofxCheckingAccount
+	}
+}
+
+func getOFXTransactionPostedDate(transaction *models.Transaction, timezone *time.Location) string {
+	unixTime := utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime)
+	_, utcOffsetSeconds := time.Unix(unixTime, 0).In(timezone).Zone()
+
+	return formatOFXDateTime(unixTime, int16(utcOffsetSeconds/60))
+}
+
+// formatOFXDateTime returns the datetime in open financial exchange (ofx) format (YYYYMMDDHHMMSS.XXX[gmt offset])
+func formatOFXDateTime(unixTime int64, utcOffsetMinutes int16) string {
+	timezone := time.FixedZone("Transaction Timezone", int(utcOffsetMinutes)*60)
+	hoursOffset := strconv.FormatFloat(float64(utcOffsetMinutes)/60, 'f', -1, 64)
+
+	if utcOffsetMinutes >= 0 {
+		hoursOffset = "+" + hoursOffset
+	}
+
+	return fmt.Sprintf("%s.000[%s]", time.Unix(unixTime, 0).In(timezone).Format(ofxExportedDateTimeFormat), hoursOffset)
+}
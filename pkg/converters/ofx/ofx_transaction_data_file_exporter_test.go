@@ -0,0 +1,315 @@
+package ofx
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/converter"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+func TestOFXTransactionDataExporterToExportedContent_OFX2(t *testing.T) {
+	exporter := OFXTransactionDataExporter
+	context := core.NewNullContext()
+	transactions, accountMap := createTestOFXExportedTransactions()
+
+	actualContent, err := exporter.ToExportedContent(context, 123, transactions, accountMap, nil, nil, nil, nil, nil, converter.DefaultExporterOptions)
+	assert.Nil(t, err)
+
+	content := string(actualContent)
+	assert.True(t, strings.HasPrefix(content, ofx2ExportedFileHeader))
+	assert.Contains(t, content, "<BANKACCTFROM>\n          <ACCTID>Test Account</ACCTID>\n          <ACCTTYPE>CHECKING</ACCTTYPE>\n        </BANKACCTFROM>")
+	assert.Contains(t, content, "<CCACCTFROM>\n          <ACCTID>Test Credit Card</ACCTID>\n        </CCACCTFROM>")
+	assert.Contains(t, content, "<FITID>3</FITID>")
+	assert.Contains(t, content, "<FITID>4</FITID>")
+	assert.Contains(t, content, "<LEDGERBAL>\n          <BALAMT>1234.56</BALAMT>")
+	assert.Contains(t, content, "<LEDGERBAL>\n          <BALAMT>-17.35</BALAMT>")
+	assert.NotContains(t, content, "<BRANCHID>")
+
+	assertTestOFXExportedContentCanBeImported(t, actualContent)
+}
+
+func TestOFXTransactionDataExporterToExportedContent_OFX1(t *testing.T) {
+	exporter := QFXTransactionDataExporter
+	context := core.NewNullContext()
+	transactions, accountMap := createTestOFXExportedTransactions()
+
+	actualContent, err := exporter.ToExportedContent(context, 123, transactions, accountMap, nil, nil, nil, nil, nil, converter.DefaultExporterOptions)
+	assert.Nil(t, err)
+
+	content := string(actualContent)
+	assert.True(t, strings.HasPrefix(content, ofx1ExportedFileHeader))
+	assert.Contains(t, content, "<BANKACCTFROM>\n<ACCTID>Test Account\n<ACCTTYPE>CHECKING\n</BANKACCTFROM>")
+	assert.Contains(t, content, "<CCACCTFROM>\n<ACCTID>Test Credit Card\n</CCACCTFROM>")
+	assert.Contains(t, content, "<LEDGERBAL>\n<BALAMT>1234.56\n")
+	assert.Contains(t, content, "<MEMO>Foo &amp; Bar\n")
+
+	assertTestOFXExportedContentCanBeImported(t, actualContent)
+}
+
+func TestOFXTransactionDataExporterToExportedContent_EscapeSpecialCharacters(t *testing.T) {
+	context := core.NewNullContext()
+
+	transactions := []*models.Transaction{
+		{
+			TransactionId:     1,
+			TransactionTime:   1725165296000,
+			Type:              models.TRANSACTION_DB_TYPE_INCOME,
+			TimezoneUtcOffset: 480,
+			AccountId:         1,
+			Amount:            12345,
+			Comment:           "Foo & <Bar>",
+		},
+	}
+	accountMap := map[int64]*models.Account{
+		1: {AccountId: 1, Category: models.ACCOUNT_CATEGORY_CHECKING_ACCOUNT, Name: "Test & Account", Currency: "CNY"},
+	}
+
+	testCases := []struct {
+		exporter        *ofxTransactionDataExporter
+		expectedMemo    string
+		expectedAccount string
+	}{
+		{OFXTransactionDataExporter, "<MEMO>Foo &amp; &lt;Bar&gt;</MEMO>", "<ACCTID>Test &amp; Account</ACCTID>"},
+		{QFXTransactionDataExporter, "<MEMO>Foo &amp; &lt;Bar&gt;\n", "<ACCTID>Test &amp; Account\n"},
+	}
+
+	for _, testCase := range testCases {
+		actualContent, err := testCase.exporter.ToExportedContent(context, 123, transactions, accountMap, nil, nil, nil, nil, nil, converter.DefaultExporterOptions)
+		assert.Nil(t, err)
+
+		content := string(actualContent)
+		assert.Contains(t, content, testCase.expectedMemo)
+		assert.Contains(t, content, testCase.expectedAccount)
+
+		user := &models.User{
+			Uid:             1234567890,
+			DefaultCurrency: "CNY",
+		}
+
+		allNewTransactions, _, _, _, _, _, err := OFXTransactionDataImporter.ParseImportedData(context, user, actualContent, time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+		assert.Nil(t, err)
+
+		assert.Equal(t, 1, len(allNewTransactions))
+		assert.Equal(t, "Foo & <Bar>", allNewTransactions[0].Comment)
+		assert.Equal(t, "Test & Account", allNewTransactions[0].OriginalSourceAccountName)
+	}
+}
+
+func TestOFXTransactionDataExporterToExportedContent_MultiCurrencyTransfer(t *testing.T) {
+	exporter := OFXTransactionDataExporter
+	context := core.NewNullContext()
+
+	transactions := []*models.Transaction{
+		{
+			TransactionId:        1,
+			TransactionTime:      1725212156000,
+			Type:                 models.TRANSACTION_DB_TYPE_TRANSFER_OUT,
+			TimezoneUtcOffset:    -300,
+			AccountId:            1,
+			Amount:               10000,
+			RelatedId:            2,
+			RelatedAccountId:     2,
+			RelatedAccountAmount: 1400,
+		},
+		{
+			TransactionId:        2,
+			TransactionTime:      1725212156000,
+			Type:                 models.TRANSACTION_DB_TYPE_TRANSFER_IN,
+			TimezoneUtcOffset:    -300,
+			AccountId:            2,
+			Amount:               1400,
+			RelatedId:            1,
+			RelatedAccountId:     1,
+			RelatedAccountAmount: 10000,
+		},
+	}
+	accountMap := map[int64]*models.Account{
+		1: {AccountId: 1, Category: models.ACCOUNT_CATEGORY_CHECKING_ACCOUNT, Name: "Test Account", Currency: "CNY"},
+		2: {AccountId: 2, Category: models.ACCOUNT_CATEGORY_CASH, Name: "USD Cash", Currency: "USD"},
+	}
+
+	actualContent, err := exporter.ToExportedContent(context, 123, transactions, accountMap, nil, nil, nil, nil, nil, converter.DefaultExporterOptions)
+	assert.Nil(t, err)
+
+	content := string(actualContent)
+	assert.Contains(t, content, "<CURDEF>CNY</CURDEF>")
+	assert.Contains(t, content, "<CURDEF>USD</CURDEF>")
+	assert.Contains(t, content, "<TRNAMT>-100.00</TRNAMT>")
+	assert.Contains(t, content, "<TRNAMT>14.00</TRNAMT>")
+	assert.NotContains(t, content, "<BANKACCTTO>")
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	allNewTransactions, _, _, _, _, _, err := OFXTransactionDataImporter.ParseImportedData(context, user, actualContent, time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 2, len(allNewTransactions))
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[0].Type)
+	assert.Equal(t, int64(1400), allNewTransactions[0].Amount)
+	assert.Equal(t, "USD Cash", allNewTransactions[0].OriginalDestinationAccountName)
+	assert.Equal(t, "USD", allNewTransactions[0].OriginalDestinationAccountCurrency)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[1].Type)
+	assert.Equal(t, int64(10000), allNewTransactions[1].Amount)
+	assert.Equal(t, "Test Account", allNewTransactions[1].OriginalSourceAccountName)
+	assert.Equal(t, "CNY", allNewTransactions[1].OriginalSourceAccountCurrency)
+}
+
+func TestOFXTransactionDataExporterToExportedContent_EmptyTransactions(t *testing.T) {
+	exporter := OFXTransactionDataExporter
+	context := core.NewNullContext()
+
+	actualContent, err := exporter.ToExportedContent(context, 123, nil, nil, nil, nil, nil, nil, nil, converter.DefaultExporterOptions)
+	assert.Nil(t, err)
+
+	content := string(actualContent)
+	assert.Contains(t, content, "<SIGNONMSGSRSV1>")
+	assert.NotContains(t, content, "<BANKMSGSRSV1>")
+	assert.NotContains(t, content, "<CREDITCARDMSGSRSV1>")
+}
+
+func TestGetOFXTransferTargetAccount(t *testing.T) {
+	accountMap := map[int64]*models.Account{
+		1: {AccountId: 1, Name: "Test Account", Currency: "CNY"},
+		2: {AccountId: 2, Name: "Test Credit Card", Currency: "CNY"},
+		3: {AccountId: 3, Name: "USD Cash", Currency: "USD"},
+	}
+
+	assert.Equal(t, accountMap[2], getOFXTransferTargetAccount(&models.Transaction{Type: models.TRANSACTION_DB_TYPE_TRANSFER_OUT, AccountId: 1, RelatedAccountId: 2}, accountMap))
+	assert.Nil(t, getOFXTransferTargetAccount(&models.Transaction{Type: models.TRANSACTION_DB_TYPE_TRANSFER_OUT, AccountId: 1, RelatedAccountId: 3}, accountMap))
+	assert.Nil(t, getOFXTransferTargetAccount(&models.Transaction{Type: models.TRANSACTION_DB_TYPE_TRANSFER_OUT, AccountId: 1, RelatedAccountId: 4}, accountMap))
+	assert.Nil(t, getOFXTransferTargetAccount(&models.Transaction{Type: models.TRANSACTION_DB_TYPE_TRANSFER_IN, AccountId: 2, RelatedAccountId: 1}, accountMap))
+}
+
+func TestFormatOFXDateTime(t *testing.T) {
+	assert.Equal(t, "20240901123456.000[+8]", formatOFXDateTime(1725165296, 480))
+	assert.Equal(t, "20240901043456.000[+0]", formatOFXDateTime(1725165296, 0))
+	assert.Equal(t, "20240831233456.000[-5]", formatOFXDateTime(1725165296, -300))
+	assert.Equal(t, "20240901100456.000[+5.5]", formatOFXDateTime(1725165296, 330))
+}
+
+func createTestOFXExportedTransactions() ([]*models.Transaction, map[int64]*models.Account) {
+	transactions := make([]*models.Transaction, 5)
+	transactions[0] = &models.Transaction{
+		TransactionId:     1,
+		TransactionTime:   1725165296000,
+		Type:              models.TRANSACTION_DB_TYPE_INCOME,
+		TimezoneUtcOffset: 480,
+		AccountId:         1,
+		Amount:            12345,
+		Comment:           "Foo & Bar",
+	}
+	transactions[1] = &models.Transaction{
+		TransactionId:     2,
+		TransactionTime:   1725194096000,
+		Type:              models.TRANSACTION_DB_TYPE_EXPENSE,
+		TimezoneUtcOffset: 0,
+		AccountId:         1,
+		Amount:            10,
+	}
+	transactions[2] = &models.Transaction{
+		TransactionId:        3,
+		TransactionTime:      1725212096000,
+		Type:                 models.TRANSACTION_DB_TYPE_TRANSFER_OUT,
+		TimezoneUtcOffset:    -300,
+		AccountId:            1,
+		Amount:               1735,
+		RelatedId:            4,
+		RelatedAccountId:     2,
+		RelatedAccountAmount: 1735,
+	}
+	transactions[3] = &models.Transaction{
+		TransactionId:        4,
+		TransactionTime:      1725212156000,
+		Type:                 models.TRANSACTION_DB_TYPE_TRANSFER_IN,
+		TimezoneUtcOffset:    -300,
+		AccountId:            2,
+		Amount:               1735,
+		RelatedId:            3,
+		RelatedAccountId:     1,
+		RelatedAccountAmount: 1735,
+	}
+	transactions[4] = &models.Transaction{
+		TransactionId:        5,
+		TransactionTime:      1725100000000,
+		Type:                 models.TRANSACTION_DB_TYPE_MODIFY_BALANCE,
+		TimezoneUtcOffset:    480,
+		AccountId:            1,
+		Amount:               100000,
+		RelatedAccountAmount: 100000,
+	}
+
+	accountMap := make(map[int64]*models.Account, 2)
+	accountMap[1] = &models.Account{
+		AccountId: 1,
+		Category:  models.ACCOUNT_CATEGORY_CHECKING_ACCOUNT,
+		Name:      "Test Account",
+		Currency:  "CNY",
+		Balance:   123456,
+	}
+	accountMap[2] = &models.Account{
+		AccountId: 2,
+		Category:  models.ACCOUNT_CATEGORY_CREDIT_CARD,
+		Name:      "Test Credit Card",
+		Currency:  "CNY",
+		Balance:   -1735,
+	}
+
+	return transactions, accountMap
+}
+
+func assertTestOFXExportedContentCanBeImported(t *testing.T, content []byte) {
+	importer := OFXTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	allNewTransactions, allNewAccounts, _, _, _, _, err := importer.ParseImportedData(context, user, content, time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 5, len(allNewTransactions))
+	assert.Equal(t, 3, len(allNewAccounts))
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, allNewTransactions[0].Type)
+	assert.Equal(t, int64(100000), allNewTransactions[0].Amount)
+	assert.Equal(t, int64(1725100000), utils.GetUnixTimeFromTransactionTime(allNewTransactions[0].TransactionTime))
+	assert.Equal(t, "Test Account", allNewTransactions[0].OriginalSourceAccountName)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, allNewTransactions[1].Type)
+	assert.Equal(t, int64(12345), allNewTransactions[1].Amount)
+	assert.Equal(t, int64(1725165296), utils.GetUnixTimeFromTransactionTime(allNewTransactions[1].TransactionTime))
+	assert.Equal(t, int16(480), allNewTransactions[1].TimezoneUtcOffset)
+	assert.Equal(t, "Test Account", allNewTransactions[1].OriginalSourceAccountName)
+	assert.Equal(t, "CNY", allNewTransactions[1].OriginalSourceAccountCurrency)
+	assert.Equal(t, "Foo & Bar", allNewTransactions[1].Comment)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[2].Type)
+	assert.Equal(t, int64(10), allNewTransactions[2].Amount)
+	assert.Equal(t, int64(1725194096), utils.GetUnixTimeFromTransactionTime(allNewTransactions[2].TransactionTime))
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[3].Type)
+	assert.Equal(t, int64(1735), allNewTransactions[3].Amount)
+	assert.Equal(t, int64(1725212096), utils.GetUnixTimeFromTransactionTime(allNewTransactions[3].TransactionTime))
+	assert.Equal(t, int16(-300), allNewTransactions[3].TimezoneUtcOffset)
+	assert.Equal(t, "Test Account", allNewTransactions[3].OriginalSourceAccountName)
+	assert.Equal(t, "Test Credit Card", allNewTransactions[3].OriginalDestinationAccountName)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[4].Type)
+	assert.Equal(t, int64(1735), allNewTransactions[4].Amount)
+	assert.Equal(t, "", allNewTransactions[4].OriginalSourceAccountName)
+	assert.Equal(t, "Test Credit Card", allNewTransactions[4].OriginalDestinationAccountName)
+	assert.Equal(t, "CNY", allNewTransactions[4].OriginalDestinationAccountCurrency)
+}
@@ -0,0 +1,228 @@
+package qif
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/converter"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const qifBankAccountType = "Bank"
+const qifCashAccountType = "Cash"
+const qifCreditCardAccountType = "CCard"
+
+const qifYearMonthDayExportedDateFormat = "2006-01-02"
+const qifMonthDayYearExportedDateFormat = "01/02/2006"
+const qifDayMonthYearExportedDateFormat = "02/01/2006"
+
+var qifExportedTextReplacer = strings.NewReplacer("\r\n", " ", "\r", " ", "\n", " ")
+var qifExportedCategoryNameReplacer = strings.NewReplacer("\r\n", " ", "\r", " ", "\n", " ", qifSubCategorySeparator, " ", qifCategoryClassSeparator, " ")
+
+// qifTransactionDataExporter defines the structure of quicken interchange format (qif) exporter for transaction data
+type qifTransactionDataExporter struct {
+	dateFormatType qifDateFormatType
+}
+
+// Initialize a quicken interchange format (qif) transaction data exporter singleton instance
+var (
+	QifYearMonthDayTransactionDataExporter = &qifTransactionDataExporter{
+		dateFormatType: qifYearMonthDayDateFormat,
+	}
+
+	QifMonthDayYearTransactionDataExporter = &qifTransactionDataExporter{
+		dateFormatType: qifMonthDayYearDateFormat,
+	}
+
+	QifDayMonthYearTransactionDataExporter = &qifTransactionDataExporter{
+		dateFormatType: qifDayMonthYearDateFormat,
+	}
+)
+
+// ToExportedContent returns the exported quicken interchange format (qif) data, each account is exported as an account entry
+// followed by all the transactions of this account, and the transaction tags are exported as the classes
+func (c *qifTransactionDataExporter) ToExportedContent(ctx core.Context, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64, customFields []*models.TransactionCustomField, allCustomFieldValues map[int64][]*models.TransactionCustomFieldValue, options converter.TransactionDataExporterOptions) ([]byte, error) {
+	accountTransactions := make(map[int64][]*models.Transaction)
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+
+		if _, exists := accountMap[transaction.AccountId]; !exists {
+			log.Warnf(ctx, "[qif_transaction_data_file_exporter.ToExportedContent] account \"id:%d\" of transaction \"id:%d\" does not exist", transaction.AccountId, transaction.TransactionId)
+			continue
+		}
+
+		accountTransactions[transaction.AccountId] = append(accountTransactions[transaction.AccountId], transaction)
+	}
+
+	accountIds := make([]int64, 0, len(accountTransactions))
+
+	for accountId := range accountTransactions {
+		accountIds = append(accountIds, accountId)
+	}
+
+	sort.Slice(accountIds, func(i, j int) bool {
+		return accountIds[i] < accountIds[j]
+	})
+
+	var ret bytes.Buffer
+
+	for i := 0; i < len(accountIds); i++ {
+		account := accountMap[accountIds[i]]
+		allTransactions := accountTransactions[account.AccountId]
+		accountType := c.getAccountType(account)
+
+		sort.SliceStable(allTransactions, func(i, j int) bool {
+			return allTransactions[i].TransactionTime < allTransactions[j].TransactionTime
+		})
+
+		ret.WriteString(qifAccountHeader + "\n")
+		c.writeLine(&ret, 'N', c.getAccountName(account))
+		c.writeLine(&ret, 'T', accountType)
+		c.writeLine(&ret, 'D', qifExportedTextReplacer.Replace(account.Comment))
+		ret.WriteRune(qifEntryEnd)
+		ret.WriteString("\n")
+
+		ret.WriteString(qifTypeHeaderPrefix + accountType + "\n")
+
+		for j := 0; j < len(allTransactions); j++ {
+			c.writeTransaction(&ret, allTransactions[j], options.GetTransactionTimezone(allTransactions[j]), account, accountMap, categoryMap, tagMap, allTagIndexes)
+		}
+	}
+
+	return ret.Bytes(), nil
+}
+
+func (c *qifTransactionDataExporter) writeTransaction(ret *bytes.Buffer, transaction *models.Transaction, timezone *time.Location, account *models.Account, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64) {
+	amount := int64(0)
+	payee := ""
+	category := ""
+	tagTransactionId := transaction.TransactionId
+
+	switch transaction.Type {
+	case models.TRANSACTION_DB_TYPE_MODIFY_BALANCE:
+		amount = transaction.RelatedAccountAmount
+		payee = qifOpeningBalancePayeeText
+		category = "[" + c.getAccountName(account) + "]"
+	case models.TRANSACTION_DB_TYPE_INCOME:
+		amount = transaction.Amount
+		category = c.getCategoryName(transaction.CategoryId, categoryMap)
+	case models.TRANSACTION_DB_TYPE_EXPENSE:
+		amount = -transaction.Amount
+		category = c.getCategoryName(transaction.CategoryId, categoryMap)
+	case models.TRANSACTION_DB_TYPE_TRANSFER_OUT:
+		amount = -transaction.Amount
+		category = "[" + c.getAccountName(accountMap[transaction.RelatedAccountId]) + "]"
+	case models.TRANSACTION_DB_TYPE_TRANSFER_IN:
+		amount = transaction.Amount
+		category = "[" + c.getAccountName(accountMap[transaction.RelatedAccountId]) + "]"
+		tagTransactionId = transaction.RelatedId
+	}
+
+	classes := c.getClassNames(tagTransactionId, allTagIndexes, tagMap)
+
+	if classes != "" {
+		category = category + qifCategoryClassSeparator + classes
+	}
+
+	c.writeLine(ret, 'D', c.formatDate(transaction, timezone))
+	c.writeLine(ret, 'T', utils.FormatAmount(amount))
+	c.writeLine(ret, 'P', payee)
+	c.writeLine(ret, 'M', qifExportedTextReplacer.Replace(transaction.Comment))
+	c.writeLine(ret, 'L', category)
+	ret.WriteRune(qifEntryEnd)
+	ret.WriteString("\n")
+}
+
+func (c *qifTransactionDataExporter) writeLine(ret *bytes.Buffer, code rune, value string) {
+	if value == "" {
+		return
+	}
+
+	ret.WriteRune(code)
+	ret.WriteString(value)
+	ret.WriteString("\n")
+}
+
+func (c *qifTransactionDataExporter) formatDate(transaction *models.Transaction, timezone *time.Location) string {
+	transactionTime := time.Unix(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime), 0).In(timezone)
+
+	switch c.dateFormatType {
+	case qifMonthDayYearDateFormat:
+		return transactionTime.Format(qifMonthDayYearExportedDateFormat)
+	case qifDayMonthYearDateFormat:
+		return transactionTime.Format(qifDayMonthYearExportedDateFormat)
+	default:
+		return transactionTime.Format(qifYearMonthDayExportedDateFormat)
+	}
+}
+
+func (c *qifTransactionDataExporter) getAccountType(account *models.Account) string {
+	switch account.Category {
+	case models.ACCOUNT_CATEGORY_CASH:
+		return qifCashAccountType
+	case models.ACCOUNT_CATEGORY_CREDIT_CARD:
+		return qifCreditCardAccountType
+	default:
+		return qifBankAccountType
+	}
+}
+
+func (c *qifTransactionDataExporter) getAccountName(account *models.Account) string {
+	if account == nil {
+		return ""
+	}
+
+	return strings.NewReplacer("[", "(", "]", ")").Replace(qifExportedTextReplacer.Replace(account.Name))
+}
+
+func (c *qifTransactionDataExporter) getCategoryName(categoryId int64, categoryMap map[int64]*models.TransactionCategory) string {
+	category, exists := categoryMap[categoryId]
+
+	if !exists {
+		return ""
+	}
+
+	if category.ParentCategoryId == models.LevelOneTransactionCategoryParentId {
+		return qifExportedCategoryNameReplacer.Replace(category.Name)
+	}
+
+	parentCategory, exists := categoryMap[category.ParentCategoryId]
+
+	if !exists {
+		return qifExportedCategoryNameReplacer.Replace(category.Name)
+	}
+
+	return qifExportedCategoryNameReplacer.Replace(parentCategory.Name) + qifSubCategorySeparator + qifExportedCategoryNameReplacer.Replace(category.Name)
+}
+
+func (c *qifTransactionDataExporter) getClassNames(transactionId int64, allTagIndexes map[int64][]int64, tagMap map[int64]*models.TransactionTag) string {
+	tagIndexes, exists := allTagIndexes[transactionId]
+
+	if !exists {
+		return ""
+	}
+
+	var ret strings.Builder
+
+	for i := 0; i < len(tagIndexes); i++ {
+		tag, exists := tagMap[tagIndexes[i]]
+
+		if !exists {
+			continue
+		}
+
+		if ret.Len() > 0 {
+			ret.WriteString(qifClassSeparator)
+		}
+
+		ret.WriteString(qifExportedCategoryNameReplacer.Replace(tag.Name))
+	}
+
+	return ret.String()
+}
@@ -0,0 +1,369 @@
+package qif
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/converter"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+func TestQIFTransactionDataExporterToExportedContent(t *testing.T) {
+	exporter := QifYearMonthDayTransactionDataExporter
+	context := core.NewNullContext()
+	transactions, accountMap, categoryMap, tagMap, allTagIndexes := createTestQIFExportedTransactions()
+
+	actualContent, err := exporter.ToExportedContent(context, 123, transactions, accountMap, categoryMap, tagMap, allTagIndexes, nil, nil, converter.DefaultExporterOptions)
+	assert.Nil(t, err)
+
+	expectedContent := "!Account\n" +
+		"NTest Account\n" +
+		"TBank\n" +
+		"DFoo\n" +
+		"^\n" +
+		"!Type:Bank\n" +
+		"D2024-08-31\n" +
+		"T1000.00\n" +
+		"POpening Balance\n" +
+		"L[Test Account]\n" +
+		"^\n" +
+		"D2024-09-01\n" +
+		"T123.45\n" +
+		"MFoo Bar\n" +
+		"LTest Category:Test Sub Category/Tag 1:Tag2\n" +
+		"^\n" +
+		"D2024-09-01\n" +
+		"T-0.10\n" +
+		"LTest Category2\n" +
+		"^\n" +
+		"D2024-09-01\n" +
+		"T-17.35\n" +
+		"L[Test Credit Card]/Tag2\n" +
+		"^\n" +
+		"!Account\n" +
+		"NTest Credit Card\n" +
+		"TCCard\n" +
+		"^\n" +
+		"!Type:CCard\n" +
+		"D2024-09-01\n" +
+		"T17.35\n" +
+		"L[Test Account]/Tag2\n" +
+		"^\n"
+
+	assert.Equal(t, expectedContent, string(actualContent))
+}
+
+func TestQIFTransactionDataExporterToExportedContent_DateFormat(t *testing.T) {
+	context := core.NewNullContext()
+	transactions, accountMap, categoryMap, tagMap, allTagIndexes := createTestQIFExportedTransactions()
+
+	actualContent, err := QifMonthDayYearTransactionDataExporter.ToExportedContent(context, 123, transactions[:1], accountMap, categoryMap, tagMap, allTagIndexes, nil, nil, converter.DefaultExporterOptions)
+	assert.Nil(t, err)
+	assert.Contains(t, string(actualContent), "\nD09/01/2024\n")
+
+	actualContent, err = QifDayMonthYearTransactionDataExporter.ToExportedContent(context, 123, transactions[:1], accountMap, categoryMap, tagMap, allTagIndexes, nil, nil, converter.DefaultExporterOptions)
+	assert.Nil(t, err)
+	assert.Contains(t, string(actualContent), "\nD01/09/2024\n")
+}
+
+func TestQIFTransactionDataExporterToExportedContent_EscapeSpecialCharacters(t *testing.T) {
+	exporter := QifYearMonthDayTransactionDataExporter
+	context := core.NewNullContext()
+
+	transactions := []*models.Transaction{
+		{
+			TransactionId:     1,
+			TransactionTime:   1725165296000,
+			Type:              models.TRANSACTION_DB_TYPE_INCOME,
+			TimezoneUtcOffset: 480,
+			CategoryId:        12,
+			AccountId:         1,
+			Amount:            12345,
+			Comment:           "Foo\r\nBar\nBaz",
+		},
+	}
+	accountMap := map[int64]*models.Account{
+		1: {AccountId: 1, Category: models.ACCOUNT_CATEGORY_CHECKING_ACCOUNT, Name: "[Test] Account", Currency: "CNY", Comment: "Line1\nLine2"},
+	}
+	categoryMap := map[int64]*models.TransactionCategory{
+		11: {CategoryId: 11, Name: "Food:Drink", ParentCategoryId: models.LevelOneTransactionCategoryParentId},
+		12: {CategoryId: 12, Name: "Fruit/Snack", ParentCategoryId: 11},
+	}
+	tagMap := map[int64]*models.TransactionTag{
+		21: {TagId: 21, Name: "Tag/1"},
+		22: {TagId: 22, Name: "Tag:2"},
+	}
+	allTagIndexes := map[int64][]int64{
+		1: {21, 22},
+	}
+
+	actualContent, err := exporter.ToExportedContent(context, 123, transactions, accountMap, categoryMap, tagMap, allTagIndexes, nil, nil, converter.DefaultExporterOptions)
+	assert.Nil(t, err)
+
+	expectedContent := "!Account\n" +
+		"N(Test) Account\n" +
+		"TBank\n" +
+		"DLine1 Line2\n" +
+		"^\n" +
+		"!Type:Bank\n" +
+		"D2024-09-01\n" +
+		"T123.45\n" +
+		"MFoo Bar Baz\n" +
+		"LFood Drink:Fruit Snack/Tag 1:Tag 2\n" +
+		"^\n"
+
+	assert.Equal(t, expectedContent, string(actualContent))
+}
+
+func TestQIFTransactionDataExporterToExportedContent_MultiCurrencyTransfer(t *testing.T) {
+	exporter := QifYearMonthDayTransactionDataExporter
+	context := core.NewNullContext()
+
+	transactions := []*models.Transaction{
+		{
+			TransactionId:        1,
+			TransactionTime:      1725212156000,
+			Type:                 models.TRANSACTION_DB_TYPE_TRANSFER_OUT,
+			TimezoneUtcOffset:    -300,
+			AccountId:            1,
+			Amount:               10000,
+			RelatedId:            2,
+			RelatedAccountId:     2,
+			RelatedAccountAmount: 1400,
+		},
+		{
+			TransactionId:        2,
+			TransactionTime:      1725212156000,
+			Type:                 models.TRANSACTION_DB_TYPE_TRANSFER_IN,
+			TimezoneUtcOffset:    -300,
+			AccountId:            2,
+			Amount:               1400,
+			RelatedId:            1,
+			RelatedAccountId:     1,
+			RelatedAccountAmount: 10000,
+		},
+	}
+	accountMap := map[int64]*models.Account{
+		1: {AccountId: 1, Category: models.ACCOUNT_CATEGORY_CHECKING_ACCOUNT, Name: "Test Account", Currency: "CNY"},
+		2: {AccountId: 2, Category: models.ACCOUNT_CATEGORY_CASH, Name: "USD Cash", Currency: "USD"},
+	}
+
+	actualContent, err := exporter.ToExportedContent(context, 123, transactions, accountMap, nil, nil, nil, nil, nil, converter.DefaultExporterOptions)
+	assert.Nil(t, err)
+
+	expectedContent := "!Account\n" +
+		"NTest Account\n" +
+		"TBank\n" +
+		"^\n" +
+		"!Type:Bank\n" +
+		"D2024-09-01\n" +
+		"T-100.00\n" +
+		"L[USD Cash]\n" +
+		"^\n" +
+		"!Account\n" +
+		"NUSD Cash\n" +
+		"TCash\n" +
+		"^\n" +
+		"!Type:Cash\n" +
+		"D2024-09-01\n" +
+		"T14.00\n" +
+		"L[Test Account]\n" +
+		"^\n"
+
+	assert.Equal(t, expectedContent, string(actualContent))
+}
+
+func TestQIFTransactionDataExporterToExportedContent_SkipTransactionWithoutAccount(t *testing.T) {
+	exporter := QifYearMonthDayTransactionDataExporter
+	context := core.NewNullContext()
+
+	transactions := []*models.Transaction{
+		{
+			TransactionId:     1,
+			TransactionTime:   1725212216000,
+			Type:              models.TRANSACTION_DB_TYPE_EXPENSE,
+			TimezoneUtcOffset: -300,
+			AccountId:         1,
+			Amount:            520,
+		},
+	}
+
+	actualContent, err := exporter.ToExportedContent(context, 123, transactions, map[int64]*models.Account{}, nil, nil, nil, nil, nil, converter.DefaultExporterOptions)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(actualContent))
+}
+
+func TestQIFTransactionDataExporterToExportedContent_EmptyTransactions(t *testing.T) {
+	exporter := QifYearMonthDayTransactionDataExporter
+	context := core.NewNullContext()
+
+	actualContent, err := exporter.ToExportedContent(context, 123, nil, nil, nil, nil, nil, nil, nil, converter.DefaultExporterOptions)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(actualContent))
+}
+
+func TestQIFTransactionDataExporterToExportedContent_ImportExportedContent(t *testing.T) {
+	testCases := []struct {
+		exporter *qifTransactionDataExporter
+		importer *qifTransactionDataImporter
+	}{
+		{QifYearMonthDayTransactionDataExporter, QifYearMonthDayTransactionDataImporter},
+		{QifMonthDayYearTransactionDataExporter, QifMonthDayYearTransactionDataImporter},
+		{QifDayMonthYearTransactionDataExporter, QifDayMonthYearTransactionDataImporter},
+	}
+
+	for _, testCase := range testCases {
+		context := core.NewNullContext()
+		transactions, accountMap, categoryMap, tagMap, allTagIndexes := createTestQIFExportedTransactions()
+
+		actualContent, err := testCase.exporter.ToExportedContent(context, 123, transactions, accountMap, categoryMap, tagMap, allTagIndexes, nil, nil, converter.DefaultExporterOptions)
+		assert.Nil(t, err)
+
+		user := &models.User{
+			Uid:             1234567890,
+			DefaultCurrency: "CNY",
+		}
+
+		allNewTransactions, allNewAccounts, allNewSubExpenseCategories, allNewSubIncomeCategories, _, allNewTags, err := testCase.importer.ParseImportedData(context, user, actualContent, time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+		assert.Nil(t, err)
+
+		assert.Equal(t, 5, len(allNewTransactions))
+		assert.Equal(t, 2, len(allNewAccounts))
+		assert.Equal(t, 1, len(allNewSubExpenseCategories))
+		assert.Equal(t, 1, len(allNewSubIncomeCategories))
+		assert.Equal(t, 2, len(allNewTags))
+
+		assert.Equal(t, models.TRANSACTION_DB_TYPE_MODIFY_BALANCE, allNewTransactions[0].Type)
+		assert.Equal(t, int64(100000), allNewTransactions[0].Amount)
+		assert.Equal(t, "Test Account", allNewTransactions[0].OriginalSourceAccountName)
+
+		assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, allNewTransactions[1].Type)
+		assert.Equal(t, int64(12345), allNewTransactions[1].Amount)
+		assert.Equal(t, int64(1725148800), utils.GetUnixTimeFromTransactionTime(allNewTransactions[1].TransactionTime))
+		assert.Equal(t, "Test Account", allNewTransactions[1].OriginalSourceAccountName)
+		assert.Equal(t, "Test Sub Category", allNewTransactions[1].OriginalCategoryName)
+		assert.Equal(t, "Foo Bar", allNewTransactions[1].Comment)
+		assert.Equal(t, []string{"Tag 1", "Tag2"}, allNewTransactions[1].OriginalTagNames)
+
+		assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[2].Type)
+		assert.Equal(t, int64(10), allNewTransactions[2].Amount)
+		assert.Equal(t, "Test Category2", allNewTransactions[2].OriginalCategoryName)
+
+		assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[3].Type)
+		assert.Equal(t, int64(1735), allNewTransactions[3].Amount)
+		assert.Equal(t, "Test Account", allNewTransactions[3].OriginalSourceAccountName)
+		assert.Equal(t, "Test Credit Card", allNewTransactions[3].OriginalDestinationAccountName)
+		assert.Equal(t, []string{"Tag2"}, allNewTransactions[3].OriginalTagNames)
+
+		assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[4].Type)
+		assert.Equal(t, int64(1735), allNewTransactions[4].Amount)
+		assert.Equal(t, "Test Account", allNewTransactions[4].OriginalSourceAccountName)
+		assert.Equal(t, "Test Credit Card", allNewTransactions[4].OriginalDestinationAccountName)
+	}
+}
+
+func createTestQIFExportedTransactions() ([]*models.Transaction, map[int64]*models.Account, map[int64]*models.TransactionCategory, map[int64]*models.TransactionTag, map[int64][]int64) {
+	transactions := make([]*models.Transaction, 5)
+	transactions[0] = &models.Transaction{
+		TransactionId:     1,
+		TransactionTime:   1725165296000,
+		Type:              models.TRANSACTION_DB_TYPE_INCOME,
+		TimezoneUtcOffset: 480,
+		CategoryId:        12,
+		AccountId:         1,
+		Amount:            12345,
+		Comment:           "Foo\nBar",
+	}
+	transactions[1] = &models.Transaction{
+		TransactionId:     2,
+		TransactionTime:   1725194096000,
+		Type:              models.TRANSACTION_DB_TYPE_EXPENSE,
+		TimezoneUtcOffset: 0,
+		CategoryId:        13,
+		AccountId:         1,
+		Amount:            10,
+	}
+	transactions[2] = &models.Transaction{
+		TransactionId:        3,
+		TransactionTime:      1725212096000,
+		Type:                 models.TRANSACTION_DB_TYPE_TRANSFER_OUT,
+		TimezoneUtcOffset:    -300,
+		AccountId:            1,
+		Amount:               1735,
+		RelatedId:            4,
+		RelatedAccountId:     2,
+		RelatedAccountAmount: 1735,
+	}
+	transactions[3] = &models.Transaction{
+		TransactionId:        4,
+		TransactionTime:      1725212096000,
+		Type:                 models.TRANSACTION_DB_TYPE_TRANSFER_IN,
+		TimezoneUtcOffset:    -300,
+		AccountId:            2,
+		Amount:               1735,
+		RelatedId:            3,
+		RelatedAccountId:     1,
+		RelatedAccountAmount: 1735,
+	}
+	transactions[4] = &models.Transaction{
+		TransactionId:        5,
+		TransactionTime:      1725100000000,
+		Type:                 models.TRANSACTION_DB_TYPE_MODIFY_BALANCE,
+		TimezoneUtcOffset:    480,
+		AccountId:            1,
+		Amount:               100000,
+		RelatedAccountAmount: 100000,
+	}
+
+	accountMap := make(map[int64]*models.Account, 2)
+	accountMap[1] = &models.Account{
+		AccountId: 1,
+		Category:  models.ACCOUNT_CATEGORY_CHECKING_ACCOUNT,
+		Name:      "Test Account",
+		Currency:  "CNY",
+		Comment:   "Foo",
+	}
+	accountMap[2] = &models.Account{
+		AccountId: 2,
+		Category:  models.ACCOUNT_CATEGORY_CREDIT_CARD,
+		Name:      "Test Credit Card",
+		Currency:  "CNY",
+	}
+
+	categoryMap := make(map[int64]*models.TransactionCategory, 3)
+	categoryMap[11] = &models.TransactionCategory{
+		CategoryId:       11,
+		Name:             "Test Category",
+		ParentCategoryId: models.LevelOneTransactionCategoryParentId,
+	}
+	categoryMap[12] = &models.TransactionCategory{
+		CategoryId:       12,
+		Name:             "Test Sub Category",
+		ParentCategoryId: 11,
+	}
+	categoryMap[13] = &models.TransactionCategory{
+		CategoryId:       13,
+		Name:             "Test Category2",
+		ParentCategoryId: models.LevelOneTransactionCategoryParentId,
+	}
+
+	tagMap := make(map[int64]*models.TransactionTag, 2)
+	tagMap[21] = &models.TransactionTag{
+		TagId: 21,
+		Name:  "Tag/1",
+	}
+	tagMap[22] = &models.TransactionTag{
+		TagId: 22,
+		Name:  "Tag2",
+	}
+
+	allTagIndexes := make(map[int64][]int64, 2)
+	allTagIndexes[1] = []int64{21, 22}
+	allTagIndexes[3] = []int64{22}
+
+	return transactions, accountMap, categoryMap, tagMap, allTagIndexes
+}
@@ -51,7 +51,7 @@ func (c *qifTransactionDataImporter) ParseImportedData(ctx core.Context, user *m
 		return nil, nil, nil, nil, nil, nil, err
 	}
 
-	dataTableImporter := converter.CreateNewSimpleImporterWithTypeNameMapping(qifTransactionTypeNameMapping)
+	dataTableImporter := converter.CreateNewImporterWithTypeNameMapping(qifTransactionTypeNameMapping, "", "", qifClassSeparator)
 
 	return dataTableImporter.ParseImportedData(ctx, user, transactionDataTable, defaultTimezone, additionalOptions, accountMap, expenseCategoryMap, incomeCategoryMap, transferCategoryMap, tagMap)
 }
@@ -459,6 +459,43 @@ func TestQIFTransactionDataFileParseImportedData_ParseSubCategory(t *testing.T)
 	assert.Equal(t, "Sub Category", allNewSubExpenseCategories[0].Name)
 }
 
+func TestQIFTransactionDataFileParseImportedData_ParseClass(t *testing.T) {
+	importer := QifYearMonthDayTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	allNewTransactions, _, allNewSubExpenseCategories, _, _, allNewTags, err := importer.ParseImportedData(context, user, []byte(
+		"!Type:Bank\n"+
+			"D2024-09-01\n"+
+			"T-123.45\n"+
+			"LTest Category:Sub Category/Class1:Class2\n"+
+			"^\n"+
+			"D2024-09-02\n"+
+			"T-0.12\n"+
+			"L[Test Account2]/Class1\n"+
+			"^\n"), time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(allNewTransactions))
+	assert.Equal(t, 1, len(allNewSubExpenseCategories))
+	assert.Equal(t, 2, len(allNewTags))
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[0].Type)
+	assert.Equal(t, "Sub Category", allNewTransactions[0].OriginalCategoryName)
+	assert.Equal(t, []string{"Class1", "Class2"}, allNewTransactions[0].OriginalTagNames)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[1].Type)
+	assert.Equal(t, "Test Account2", allNewTransactions[1].OriginalDestinationAccountName)
+	assert.Equal(t, []string{"Class1"}, allNewTransactions[1].OriginalTagNames)
+
+	assert.Equal(t, "Class1", allNewTags[0].Name)
+	assert.Equal(t, "Class2", allNewTags[1].Name)
+}
+
 func TestQIFTransactionDataFileParseImportedData_ParseDescription(t *testing.T) {
 	importer := QifYearMonthDayTransactionDataImporter
 	context := core.NewNullContext()
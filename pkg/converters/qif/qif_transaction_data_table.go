@@ -12,6 +12,9 @@ import (
 )
 
 const qifOpeningBalancePayeeText = "Opening Balance"
+const qifSubCategorySeparator = ":"
+const qifCategoryClassSeparator = "/"
+const qifClassSeparator = ":"
 
 var qifTransactionSupportedColumns = map[datatable.TransactionDataTableColumn]bool{
 	datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIME:     true,
@@ -23,6 +26,7 @@ var qifTransactionSupportedColumns = map[datatable.TransactionDataTableColumn]bo
 	datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME: true,
 	datatable.TRANSACTION_DATA_TABLE_DESCRIPTION:          true,
 	datatable.TRANSACTION_DATA_TABLE_PAYEE:                true,
+	datatable.TRANSACTION_DATA_TABLE_TAGS:                 true,
 }
 
 // qifDateFormatType represents the quicken interchange format (qif) date format type
@@ -147,21 +151,28 @@ func (t *qifTransactionDataRowIterator) parseTransaction(ctx core.Context, user
 		data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME] = ""
 	}
 
-	if len(qifTransaction.Category) > 0 && qifTransaction.Category[0] == '[' && qifTransaction.Category[len(qifTransaction.Category)-1] == ']' {
+	category := qifTransaction.Category
+
+	if classIndex := strings.Index(category, qifCategoryClassSeparator); classIndex >= 0 { // category/class
+		data[datatable.TRANSACTION_DATA_TABLE_TAGS] = category[classIndex+1:]
+		category = category[:classIndex]
+	}
+
+	if len(category) > 0 && category[0] == '[' && category[len(category)-1] == ']' {
 		if qifTransaction.Payee == qifOpeningBalancePayeeText { // balance modification
 			data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = qifTransactionTypeNameMapping[models.TRANSACTION_TYPE_MODIFY_BALANCE]
 			data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(amount)
-			data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME] = qifTransaction.Category[1 : len(qifTransaction.Category)-1]
+			data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME] = category[1 : len(category)-1]
 		} else { // transfer
 			data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = qifTransactionTypeNameMapping[models.TRANSACTION_TYPE_TRANSFER]
 
 			if amount >= 0 { // transfer from [account name]
 				data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(amount)
 				data[datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME] = data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME]
-				data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME] = qifTransaction.Category[1 : len(qifTransaction.Category)-1]
+				data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME] = category[1 : len(category)-1]
 			} else { // transfer to [account name]
 				data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(-amount)
-				data[datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME] = qifTransaction.Category[1 : len(qifTransaction.Category)-1]
+				data[datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME] = category[1 : len(category)-1]
 			}
 		}
 	} else { // income/expense
@@ -173,13 +184,13 @@ func (t *qifTransactionDataRowIterator) parseTransaction(ctx core.Context, user
 			data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(-amount)
 		}
 
-		if strings.Index(qifTransaction.Category, ":") > 0 { // category:subcategory
-			categories := strings.Split(qifTransaction.Category, ":")
+		if strings.Index(category, qifSubCategorySeparator) > 0 { // category:subcategory
+			categories := strings.Split(category, qifSubCategorySeparator)
 			data[datatable.TRANSACTION_DATA_TABLE_CATEGORY] = categories[0]
 			data[datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY] = categories[len(categories)-1]
 		} else {
 			data[datatable.TRANSACTION_DATA_TABLE_CATEGORY] = ""
-			data[datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY] = qifTransaction.Category
+			data[datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY] = category
 		}
 	}
 
@@ -4,6 +4,7 @@ import (
 	"encoding/xml"
 	"io"
 	"reflect"
+	"strings"
 	"sync"
 
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
@@ -28,6 +29,7 @@ const (
 // sgmlTypeInfo represents the struct of SGML type reflection info
 type sgmlTypeInfo struct {
 	supportedFields map[string]*sgmlFieldInfo
+	orderedFields   []*sgmlFieldInfo
 }
 
 // sgmlFieldInfo represents the struct of SGML field info
@@ -64,11 +66,7 @@ func (d *Decoder) Decode(v any) error {
 		return nil
 	}
 
-	rootElementName := rootNameField.Tag.Get(sgmlTagName)
-
-	if rootElementName == "" {
-		rootElementName = rootNameField.Tag.Get(xmlTagName)
-	}
+	rootElementName := getFieldElementName(rootNameField)
 
 	for {
 		token, err := d.xmlDecoder.RawToken()
@@ -89,7 +87,7 @@ func (d *Decoder) Decode(v any) error {
 }
 
 func (d *Decoder) unmarshal(element reflect.Value, elementName string) error {
-	typeInfo, err := d.getStructTypeInfo(element.Type())
+	typeInfo, err := getStructTypeInfo(element.Type())
 
 	if err != nil {
 		return err
@@ -202,7 +200,7 @@ func (d *Decoder) unmarshal(element reflect.Value, elementName string) error {
 	return nil
 }
 
-func (d *Decoder) getStructTypeInfo(reflectType reflect.Type) (*sgmlTypeInfo, error) {
+func getStructTypeInfo(reflectType reflect.Type) (*sgmlTypeInfo, error) {
 	if reflectType.Kind() != reflect.Struct {
 		return nil, nil
 	}
@@ -224,14 +222,16 @@ func (d *Decoder) getStructTypeInfo(reflectType reflect.Type) (*sgmlTypeInfo, er
 			fieldType := field.Type
 
 			if fieldType.Kind() == reflect.Struct {
-				fieldSgmlTypeInfo, err := d.getStructTypeInfo(fieldType)
+				fieldSgmlTypeInfo, err := getStructTypeInfo(fieldType)
 
 				if err != nil {
 					return nil, err
 				}
 
-				for sgmlFieldName, fieldInfo := range fieldSgmlTypeInfo.supportedFields {
-					newTypeInfo.supportedFields[sgmlFieldName] = fieldInfo
+				for i := 0; i < len(fieldSgmlTypeInfo.orderedFields); i++ {
+					fieldInfo := fieldSgmlTypeInfo.orderedFields[i]
+					newTypeInfo.supportedFields[fieldInfo.sgmlFieldName] = fieldInfo
+					newTypeInfo.orderedFields = append(newTypeInfo.orderedFields, fieldInfo)
 				}
 			}
 
@@ -240,11 +240,7 @@ func (d *Decoder) getStructTypeInfo(reflectType reflect.Type) (*sgmlTypeInfo, er
 			continue
 		}
 
-		sgmlFieldName := field.Tag.Get(sgmlTagName)
-
-		if sgmlFieldName == "" {
-			sgmlFieldName = field.Tag.Get(xmlTagName)
-		}
+		sgmlFieldName := getFieldElementName(field)
 
 		if sgmlFieldName == "" || field.Name == sgmlNameFieldName || field.Name == xmlNameFieldName {
 			continue
@@ -280,11 +276,14 @@ func (d *Decoder) getStructTypeInfo(reflectType reflect.Type) (*sgmlTypeInfo, er
 			return nil, errs.ErrInvalidSGMLFile
 		}
 
-		newTypeInfo.supportedFields[sgmlFieldName] = &sgmlFieldInfo{
+		fieldInfo := &sgmlFieldInfo{
 			sgmlFieldName:   sgmlFieldName,
 			sgmlFieldType:   sgmlFieldType,
 			structFieldName: field.Name,
 		}
+
+		newTypeInfo.supportedFields[sgmlFieldName] = fieldInfo
+		newTypeInfo.orderedFields = append(newTypeInfo.orderedFields, fieldInfo)
 	}
 
 	typeInfo, _ = sgmlTypeInfoMap.LoadOrStore(reflectType, newTypeInfo)
@@ -292,6 +291,26 @@ func (d *Decoder) getStructTypeInfo(reflectType reflect.Type) (*sgmlTypeInfo, er
 	return typeInfo.(*sgmlTypeInfo), nil
 }
 
+// getFieldElementName returns the element name in the sgml tag or the xml tag of the specified struct field,
+// the options after comma in the tag are ignored, and empty string is returned if the field should be skipped
+func getFieldElementName(field reflect.StructField) string {
+	tagValue := field.Tag.Get(sgmlTagName)
+
+	if tagValue == "" {
+		tagValue = field.Tag.Get(xmlTagName)
+	}
+
+	if commaIndex := strings.Index(tagValue, ","); commaIndex >= 0 {
+		tagValue = tagValue[0:commaIndex]
+	}
+
+	if tagValue == "-" {
+		return ""
+	}
+
+	return tagValue
+}
+
 func (d *Decoder) getActualFieldValue(fieldName string, fieldValue string, textualFieldWithoutEndElementNames map[string]bool) string {
 	_, notHasEndElement := textualFieldWithoutEndElementNames[fieldName]
 
@@ -0,0 +1,156 @@
+package sgml
+
+import (
+	"bufio"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+)
+
+var sgmlTextEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+type Encoder struct {
+	writer *bufio.Writer
+	indent string
+}
+
+// Indent sets the encoder to write each element in a new line with the specified indent string for each nesting level
+func (e *Encoder) Indent(indent string) {
+	e.indent = indent
+}
+
+// Encode marshals the specified struct instance and returns whether error occurs,
+// the textual element is written without end element, and the empty textual element or nil struct element is omitted
+func (e *Encoder) Encode(v any) error {
+	value := reflect.ValueOf(v)
+
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return nil
+		}
+
+		value = value.Elem()
+	}
+
+	if value.Kind() != reflect.Struct {
+		return errs.ErrInvalidSGMLFile
+	}
+
+	rootNameField, exists := value.Type().FieldByName(sgmlNameFieldName)
+
+	if !exists {
+		rootNameField, exists = value.Type().FieldByName(xmlNameFieldName)
+	}
+
+	if !exists {
+		return errs.ErrInvalidSGMLFile
+	}
+
+	rootElementName := getFieldElementName(rootNameField)
+
+	if rootElementName == "" {
+		return errs.ErrInvalidSGMLFile
+	}
+
+	err := e.marshal(value, rootElementName, 0)
+
+	if err != nil {
+		return err
+	}
+
+	return e.writer.Flush()
+}
+
+func (e *Encoder) marshal(element reflect.Value, elementName string, depth int) error {
+	typeInfo, err := getStructTypeInfo(element.Type())
+
+	if err != nil {
+		return err
+	}
+
+	if typeInfo == nil {
+		return errs.ErrInvalidSGMLFile
+	}
+
+	e.writeStartElement(elementName, depth)
+	e.writeLineBreak()
+
+	for i := 0; i < len(typeInfo.orderedFields); i++ {
+		fieldInfo := typeInfo.orderedFields[i]
+		field := element.FieldByName(fieldInfo.structFieldName)
+
+		if fieldInfo.sgmlFieldType == sgmlTextualField {
+			for field.Kind() == reflect.Pointer {
+				if field.IsNil() {
+					break
+				}
+
+				field = field.Elem()
+			}
+
+			if field.Kind() != reflect.String || field.String() == "" {
+				continue
+			}
+
+			e.writeStartElement(fieldInfo.sgmlFieldName, depth+1)
+			_, _ = e.writer.WriteString(sgmlTextEscaper.Replace(field.String()))
+			e.writeLineBreak()
+		} else if fieldInfo.sgmlFieldType == sgmlStructField {
+			err = e.marshalStructField(field, fieldInfo.sgmlFieldName, depth+1)
+		} else if fieldInfo.sgmlFieldType == sgmlStructSliceField {
+			for j := 0; j < field.Len() && err == nil; j++ {
+				err = e.marshalStructField(field.Index(j), fieldInfo.sgmlFieldName, depth+1)
+			}
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	e.writeIndent(depth)
+	_, _ = e.writer.WriteString("</" + elementName + ">")
+	e.writeLineBreak()
+
+	return nil
+}
+
+func (e *Encoder) marshalStructField(field reflect.Value, elementName string, depth int) error {
+	for field.Kind() == reflect.Pointer {
+		if field.IsNil() {
+			return nil
+		}
+
+		field = field.Elem()
+	}
+
+	return e.marshal(field, elementName, depth)
+}
+
+func (e *Encoder) writeStartElement(elementName string, depth int) {
+	e.writeIndent(depth)
+	_, _ = e.writer.WriteString("<" + elementName + ">")
+}
+
+func (e *Encoder) writeIndent(depth int) {
+	if e.indent == "" {
+		return
+	}
+
+	for i := 0; i < depth; i++ {
+		_, _ = e.writer.WriteString(e.indent)
+	}
+}
+
+func (e *Encoder) writeLineBreak() {
+	_, _ = e.writer.WriteString("\n")
+}
+
+// NewEncoder creates a new SGML encoder writing to specified io writer
+func NewEncoder(writer io.Writer) *Encoder {
+	return &Encoder{
+		writer: bufio.NewWriter(writer),
+	}
+}
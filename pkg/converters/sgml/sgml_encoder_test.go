@@ -0,0 +1,160 @@
+package sgml
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+)
+
+type TestStructWithTagOptions struct {
+	XMLName xml.Name `xml:"Root"`
+	Text1   string   `xml:"Text1,omitempty"`
+	Text2   string   `xml:"-"`
+}
+
+func TestEncoderEncode(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	err := NewEncoder(buffer).Encode(&TestSimpleStruct{
+		Text1: "Foo",
+		Text2: "Bar",
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t,
+		"<Root>\n"+
+			"<Text1>Foo\n"+
+			"<Text2>Bar\n"+
+			"</Root>\n", buffer.String())
+}
+
+func TestEncoderEncode_WithIndent(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	sgmlEncoder := NewEncoder(buffer)
+	sgmlEncoder.Indent("  ")
+	err := sgmlEncoder.Encode(&TestNestedStruct2{
+		Child: &TestSimpleStruct{
+			Text1: "Hello",
+			Text2: "World",
+		},
+		Text3: "Foo",
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t,
+		"<Root>\n"+
+			"  <Child>\n"+
+			"    <Text1>Hello\n"+
+			"    <Text2>World\n"+
+			"  </Child>\n"+
+			"  <Text3>Foo\n"+
+			"</Root>\n", buffer.String())
+}
+
+func TestEncoderEncode_OmitEmptyFields(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	err := NewEncoder(buffer).Encode(&TestNestedStruct2{
+		Text4: "Bar",
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t,
+		"<Root>\n"+
+			"<Text4>Bar\n"+
+			"</Root>\n", buffer.String())
+}
+
+func TestEncoderEncode_EmbeddedStruct(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	err := NewEncoder(buffer).Encode(&TestEmbeddedStruct{
+		TestSimpleStruct: TestSimpleStruct{
+			Text1: "Hello",
+			Text2: "World",
+		},
+		Text5: "Foo",
+		Text6: "Bar",
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t,
+		"<Root>\n"+
+			"<Text1>Hello\n"+
+			"<Text2>World\n"+
+			"<Text5>Foo\n"+
+			"<Text6>Bar\n"+
+			"</Root>\n", buffer.String())
+}
+
+func TestEncoderEncode_StructSlice(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	err := NewEncoder(buffer).Encode(&TestSliceStruct2{
+		Children: []*TestSimpleStruct{
+			{Text1: "Hello"},
+			nil,
+			{Text2: "World2"},
+		},
+		Text7: "Foo",
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t,
+		"<Root>\n"+
+			"<Child>\n"+
+			"<Text1>Hello\n"+
+			"</Child>\n"+
+			"<Child>\n"+
+			"<Text2>World2\n"+
+			"</Child>\n"+
+			"<Text7>Foo\n"+
+			"</Root>\n", buffer.String())
+}
+
+func TestEncoderEncode_UsingXMLTagWithOptions(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	err := NewEncoder(buffer).Encode(&TestStructWithTagOptions{
+		Text1: "Foo & <Bar>",
+		Text2: "Ignored",
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t,
+		"<Root>\n"+
+			"<Text1>Foo &amp; &lt;Bar&gt;\n"+
+			"</Root>\n", buffer.String())
+}
+
+func TestEncoderEncode_DecodeEncodedData(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	err := NewEncoder(buffer).Encode(&TestSliceStruct1{
+		Children: []TestSimpleStruct{
+			{Text1: "Hello", Text2: "World & Earth"},
+			{Text1: "Hello2", Text2: "World2"},
+		},
+		Text7: "Foo",
+	})
+	assert.Nil(t, err)
+
+	testStruct := &TestSliceStruct1{}
+	err = NewDecoder(strings.NewReader(buffer.String())).Decode(&testStruct)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(testStruct.Children))
+	assert.Equal(t, "Hello", testStruct.Children[0].Text1)
+	assert.Equal(t, "World & Earth", testStruct.Children[0].Text2)
+	assert.Equal(t, "Hello2", testStruct.Children[1].Text1)
+	assert.Equal(t, "World2", testStruct.Children[1].Text2)
+	assert.Equal(t, "Foo", testStruct.Text7)
+}
+
+func TestEncoderEncode_WithNotSupportedField(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	err := NewEncoder(buffer).Encode(&TestUnsupportedStruct{
+		Number: 1,
+	})
+
+	assert.EqualError(t, err, errs.ErrInvalidSGMLFile.Message)
+}
@@ -1,6 +1,7 @@
 package converters
 
 import (
+	"github.com/mayswind/ezbookkeeping/pkg/converters/actualbudget"
 	"github.com/mayswind/ezbookkeeping/pkg/converters/alipay"
 	"github.com/mayswind/ezbookkeeping/pkg/converters/beancount"
 	"github.com/mayswind/ezbookkeeping/pkg/converters/camt"
@@ -8,21 +9,57 @@ import (
 	"github.com/mayswind/ezbookkeeping/pkg/converters/datatable"
 	"github.com/mayswind/ezbookkeeping/pkg/converters/default"
 	"github.com/mayswind/ezbookkeeping/pkg/converters/dsv"
+	"github.com/mayswind/ezbookkeeping/pkg/converters/excel"
 	"github.com/mayswind/ezbookkeeping/pkg/converters/feidee"
 	"github.com/mayswind/ezbookkeeping/pkg/converters/fireflyIII"
 	"github.com/mayswind/ezbookkeeping/pkg/converters/gnucash"
+	"github.com/mayswind/ezbookkeeping/pkg/converters/homebank"
 	"github.com/mayswind/ezbookkeeping/pkg/converters/iif"
 	"github.com/mayswind/ezbookkeeping/pkg/converters/jdcom"
+	"github.com/mayswind/ezbookkeeping/pkg/converters/kmymoney"
+	"github.com/mayswind/ezbookkeeping/pkg/converters/ledger"
+	"github.com/mayswind/ezbookkeeping/pkg/converters/moneymanagerex"
 	"github.com/mayswind/ezbookkeeping/pkg/converters/mt"
 	"github.com/mayswind/ezbookkeeping/pkg/converters/ofx"
 	"github.com/mayswind/ezbookkeeping/pkg/converters/qif"
 	"github.com/mayswind/ezbookkeeping/pkg/converters/wechat"
+	"github.com/mayswind/ezbookkeeping/pkg/converters/ynab"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
 	"github.com/mayswind/ezbookkeeping/pkg/models"
 )
 
 // GetTransactionDataExporter returns the transaction data exporter according to the file type
 func GetTransactionDataExporter(fileType string) converter.TransactionDataExporter {
+	if fileType == "csv" {
+		return _default.DefaultTransactionDataCSVFileConverter
+	} else if fileType == "tsv" {
+		return _default.DefaultTransactionDataTSVFileConverter
+	} else if fileType == "ofx" {
+		return ofx.OFXTransactionDataExporter
+	} else if fileType == "qfx" {
+		return ofx.QFXTransactionDataExporter
+	} else if fileType == "qif_ymd" {
+		return qif.QifYearMonthDayTransactionDataExporter
+	} else if fileType == "qif_mdy" {
+		return qif.QifMonthDayYearTransactionDataExporter
+	} else if fileType == "qif_dmy" {
+		return qif.QifDayMonthYearTransactionDataExporter
+	} else if fileType == "beancount" {
+		return beancount.BeancountTransactionDataExporter
+	} else if fileType == "ledger" {
+		return ledger.LedgerTransactionDataExporter
+	} else if fileType == "gnucash" {
+		return gnucash.GnuCashTransactionDataExporter
+	} else if fileType == "xlsx" {
+		return excel.ExcelOOXMLTransactionDataExporter
+	} else {
+		return nil
+	}
+}
+
+// GetTransactionDataStreamExporter returns the transaction data exporter which supports writing the exported data page by page according to the file type,
+// returns nil if the file type does not support stream exporting, because the exporter needs all the transactions before writing any data
+func GetTransactionDataStreamExporter(fileType string) converter.TransactionDataStreamExporter {
 	if fileType == "csv" {
 		return _default.DefaultTransactionDataCSVFileConverter
 	} else if fileType == "tsv" {
@@ -60,8 +97,20 @@ func GetTransactionDataImporter(fileType string) (converter.TransactionDataImpor
 		return gnucash.GnuCashTransactionDataImporter, nil
 	} else if fileType == "firefly_iii_csv" {
 		return fireflyIII.FireflyIIITransactionDataCsvFileImporter, nil
+	} else if fileType == "ynab" {
+		return ynab.YnabTransactionDataImporter, nil
+	} else if fileType == "actual_budget" {
+		return actualbudget.ActualBudgetTransactionDataImporter, nil
+	} else if fileType == "homebank" {
+		return homebank.HomeBankTransactionDataImporter, nil
+	} else if fileType == "kmymoney" {
+		return kmymoney.KMyMoneyTransactionDataImporter, nil
+	} else if fileType == "money_manager_ex" {
+		return moneymanagerex.MoneyManagerExTransactionDataImporter, nil
 	} else if fileType == "beancount" {
 		return beancount.BeancountTransactionDataImporter, nil
+	} else if fileType == "ledger" {
+		return ledger.LedgerTransactionDataImporter, nil
 	} else if fileType == "feidee_mymoney_csv" {
 		return feidee.FeideeMymoneyAppTransactionDataCsvFileImporter, nil
 	} else if fileType == "feidee_mymoney_xls" {
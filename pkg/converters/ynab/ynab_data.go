@@ -0,0 +1,38 @@
+package ynab
+
+const ynabTransferPayeePrefix = "Transfer : "
+const ynabStartingBalancePayee = "Starting Balance"
+const ynabCategoryGroupAndCategorySeparator = ": "
+
+// ynabData defines the structure of ynab data
+type ynabData struct {
+	Transactions     []*ynabTransactionData
+	CategoryGroupMap map[string]string
+}
+
+// ynabTransactionData defines the structure of ynab register transaction data
+type ynabTransactionData struct {
+	Account       string
+	Flag          string
+	Date          string
+	Payee         string
+	CategoryGroup string
+	Category      string
+	Memo          string
+	Outflow       string
+	Inflow        string
+}
+
+// IsTransfer returns whether the transaction is a transfer between two ynab accounts
+func (t *ynabTransactionData) IsTransfer() bool {
+	return len(t.Payee) > len(ynabTransferPayeePrefix) && t.Payee[0:len(ynabTransferPayeePrefix)] == ynabTransferPayeePrefix
+}
+
+// GetTransferAccountName returns the name of the other account if the transaction is a transfer
+func (t *ynabTransactionData) GetTransferAccountName() string {
+	if !t.IsTransfer() {
+		return ""
+	}
+
+	return t.Payee[len(ynabTransferPayeePrefix):]
+}
@@ -0,0 +1,231 @@
+package ynab
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/csv"
+	"github.com/mayswind/ezbookkeeping/pkg/converters/datatable"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+)
+
+const ynabRegisterFileNameSuffix = "Register.csv"
+const ynabPlanFileNameSuffix = "Plan.csv"
+const ynabBudgetFileNameSuffix = "Budget.csv"
+
+const ynabAccountColumnName = "Account"
+const ynabFlagColumnName = "Flag"
+const ynabDateColumnName = "Date"
+const ynabPayeeColumnName = "Payee"
+const ynabCategoryGroupAndCategoryColumnName = "Category Group/Category"
+const ynabCategoryGroupColumnName = "Category Group"
+const ynabCategoryColumnName = "Category"
+const ynabMemoColumnName = "Memo"
+const ynabOutflowColumnName = "Outflow"
+const ynabInflowColumnName = "Inflow"
+
+// ynabDataReader defines the structure of ynab data reader
+type ynabDataReader struct {
+	registerData []byte
+	budgetData   []byte
+}
+
+// read returns the imported ynab data
+func (r *ynabDataReader) read(ctx core.Context) (*ynabData, error) {
+	transactions, err := r.readRegisterTransactions(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	categoryGroupMap := make(map[string]string)
+
+	if len(r.budgetData) > 0 {
+		categoryGroupMap, err = r.readBudgetCategoryGroups(ctx)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &ynabData{
+		Transactions:     transactions,
+		CategoryGroupMap: categoryGroupMap,
+	}, nil
+}
+
+func (r *ynabDataReader) readRegisterTransactions(ctx core.Context) ([]*ynabTransactionData, error) {
+	dataTable, err := r.createCsvDataTable(ctx, r.registerData)
+
+	if err != nil {
+		return nil, err
+	}
+
+	columnIndexes := r.getColumnIndexes(dataTable.HeaderColumnNames())
+
+	for _, columnName := range []string{ynabAccountColumnName, ynabDateColumnName, ynabPayeeColumnName, ynabOutflowColumnName, ynabInflowColumnName} {
+		if _, exists := columnIndexes[columnName]; !exists {
+			log.Errorf(ctx, "[ynab_data_reader.readRegisterTransactions] missing required column \"%s\" in register file", columnName)
+			return nil, errs.ErrMissingRequiredFieldInHeaderRow
+		}
+	}
+
+	transactions := make([]*ynabTransactionData, 0, dataTable.DataRowCount())
+	iterator := dataTable.DataRowIterator()
+
+	for iterator.HasNext() {
+		dataRow := iterator.Next()
+
+		if dataRow.ColumnCount() < len(dataTable.HeaderColumnNames()) {
+			log.Errorf(ctx, "[ynab_data_reader.readRegisterTransactions] cannot parse row \"%s\", because it has %d columns but the header has %d columns", iterator.CurrentRowId(), dataRow.ColumnCount(), len(dataTable.HeaderColumnNames()))
+			return nil, errs.ErrFewerFieldsInDataRowThanInHeaderRow
+		}
+
+		transaction := &ynabTransactionData{
+			Account:       r.getColumnData(dataRow, columnIndexes, ynabAccountColumnName),
+			Flag:          r.getColumnData(dataRow, columnIndexes, ynabFlagColumnName),
+			Date:          r.getColumnData(dataRow, columnIndexes, ynabDateColumnName),
+			Payee:         r.getColumnData(dataRow, columnIndexes, ynabPayeeColumnName),
+			CategoryGroup: r.getColumnData(dataRow, columnIndexes, ynabCategoryGroupColumnName),
+			Category:      r.getColumnData(dataRow, columnIndexes, ynabCategoryColumnName),
+			Memo:          r.getColumnData(dataRow, columnIndexes, ynabMemoColumnName),
+			Outflow:       r.getColumnData(dataRow, columnIndexes, ynabOutflowColumnName),
+			Inflow:        r.getColumnData(dataRow, columnIndexes, ynabInflowColumnName),
+		}
+
+		if transaction.CategoryGroup == "" && transaction.Category == "" {
+			transaction.CategoryGroup, transaction.Category = r.splitCategoryGroupAndCategory(r.getColumnData(dataRow, columnIndexes, ynabCategoryGroupAndCategoryColumnName))
+		}
+
+		transactions = append(transactions, transaction)
+	}
+
+	return transactions, nil
+}
+
+func (r *ynabDataReader) readBudgetCategoryGroups(ctx core.Context) (map[string]string, error) {
+	dataTable, err := r.createCsvDataTable(ctx, r.budgetData)
+
+	if err != nil {
+		return nil, err
+	}
+
+	columnIndexes := r.getColumnIndexes(dataTable.HeaderColumnNames())
+	categoryGroupMap := make(map[string]string)
+	iterator := dataTable.DataRowIterator()
+
+	for iterator.HasNext() {
+		dataRow := iterator.Next()
+		categoryGroup := r.getColumnData(dataRow, columnIndexes, ynabCategoryGroupColumnName)
+		category := r.getColumnData(dataRow, columnIndexes, ynabCategoryColumnName)
+
+		if categoryGroup == "" && category == "" {
+			categoryGroup, category = r.splitCategoryGroupAndCategory(r.getColumnData(dataRow, columnIndexes, ynabCategoryGroupAndCategoryColumnName))
+		}
+
+		if categoryGroup != "" && category != "" {
+			categoryGroupMap[category] = categoryGroup
+		}
+	}
+
+	return categoryGroupMap, nil
+}
+
+func (r *ynabDataReader) createCsvDataTable(ctx core.Context, data []byte) (datatable.BasicDataTable, error) {
+	fallback := unicode.UTF8.NewDecoder()
+	reader := transform.NewReader(bytes.NewReader(data), unicode.BOMOverride(fallback))
+
+	return csv.CreateNewCsvBasicDataTable(ctx, reader, true)
+}
+
+func (r *ynabDataReader) getColumnIndexes(headerColumnNames []string) map[string]int {
+	columnIndexes := make(map[string]int, len(headerColumnNames))
+
+	for i := 0; i < len(headerColumnNames); i++ {
+		columnIndexes[strings.TrimSpace(headerColumnNames[i])] = i
+	}
+
+	return columnIndexes
+}
+
+func (r *ynabDataReader) getColumnData(dataRow datatable.BasicDataTableRow, columnIndexes map[string]int, columnName string) string {
+	columnIndex, exists := columnIndexes[columnName]
+
+	if !exists || columnIndex >= dataRow.ColumnCount() {
+		return ""
+	}
+
+	return strings.TrimSpace(dataRow.GetData(columnIndex))
+}
+
+func (r *ynabDataReader) splitCategoryGroupAndCategory(categoryGroupAndCategory string) (string, string) {
+	separatorIndex := strings.Index(categoryGroupAndCategory, ynabCategoryGroupAndCategorySeparator)
+
+	if separatorIndex < 0 {
+		return "", categoryGroupAndCategory
+	}
+
+	return categoryGroupAndCategory[0:separatorIndex], categoryGroupAndCategory[separatorIndex+len(ynabCategoryGroupAndCategorySeparator):]
+}
+
+func createNewYnabDataReader(ctx core.Context, data []byte) (*ynabDataReader, error) {
+	if len(data) > 4 && data[0] == 0x50 && data[1] == 0x4B && data[2] == 0x03 && data[3] == 0x04 { // zip magic number
+		zipReader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+
+		if err != nil {
+			log.Errorf(ctx, "[ynab_data_reader.createNewYnabDataReader] cannot open zip file, because %s", err.Error())
+			return nil, errs.ErrInvalidYNABFile
+		}
+
+		reader := &ynabDataReader{}
+
+		for i := 0; i < len(zipReader.File); i++ {
+			file := zipReader.File[i]
+
+			if file.FileInfo().IsDir() {
+				continue
+			}
+
+			if strings.HasSuffix(file.Name, ynabRegisterFileNameSuffix) {
+				reader.registerData, err = readZipFileContent(file)
+			} else if strings.HasSuffix(file.Name, ynabPlanFileNameSuffix) || strings.HasSuffix(file.Name, ynabBudgetFileNameSuffix) {
+				reader.budgetData, err = readZipFileContent(file)
+			}
+
+			if err != nil {
+				log.Errorf(ctx, "[ynab_data_reader.createNewYnabDataReader] cannot read \"%s\" in zip file, because %s", file.Name, err.Error())
+				return nil, errs.ErrInvalidYNABFile
+			}
+		}
+
+		if len(reader.registerData) < 1 {
+			log.Errorf(ctx, "[ynab_data_reader.createNewYnabDataReader] there is no register file in zip file")
+			return nil, errs.ErrInvalidYNABFile
+		}
+
+		return reader, nil
+	}
+
+	return &ynabDataReader{
+		registerData: data,
+	}, nil
+}
+
+func readZipFileContent(file *zip.File) ([]byte, error) {
+	fileReader, err := file.Open()
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer fileReader.Close()
+
+	return io.ReadAll(fileReader)
+}
@@ -0,0 +1,261 @@
+package ynab
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/converter"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+func TestYnabTransactionDataImporterParseImportedData_ExportZipFile(t *testing.T) {
+	importer := YnabTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "USD",
+	}
+
+	testdata, err := os.ReadFile("../../../testdata/ynab_test_file.zip")
+	assert.Nil(t, err)
+
+	allNewTransactions, allNewAccounts, allNewSubExpenseCategories, allNewSubIncomeCategories, allNewSubTransferCategories, allNewTags, err := importer.ParseImportedData(context, user, testdata, time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 6, len(allNewTransactions))
+	assert.Equal(t, 2, len(allNewAccounts))
+	assert.Equal(t, 3, len(allNewSubExpenseCategories))
+	assert.Equal(t, 1, len(allNewSubIncomeCategories))
+	assert.Equal(t, 1, len(allNewSubTransferCategories))
+	assert.Equal(t, 1, len(allNewTags))
+
+	assert.Equal(t, int64(1234567890), allNewTransactions[0].Uid)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_MODIFY_BALANCE, allNewTransactions[0].Type)
+	assert.Equal(t, "2024-09-01 00:00:00", utils.FormatUnixTimeToLongDateTime(utils.GetUnixTimeFromTransactionTime(allNewTransactions[0].TransactionTime), time.UTC))
+	assert.Equal(t, int64(100000), allNewTransactions[0].Amount)
+	assert.Equal(t, "Checking", allNewTransactions[0].OriginalSourceAccountName)
+	assert.Equal(t, "", allNewTransactions[0].OriginalCategoryName)
+
+	assert.Equal(t, int64(1234567890), allNewTransactions[1].Uid)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[1].Type)
+	assert.Equal(t, "2024-09-02 00:00:00", utils.FormatUnixTimeToLongDateTime(utils.GetUnixTimeFromTransactionTime(allNewTransactions[1].TransactionTime), time.UTC))
+	assert.Equal(t, int64(12345), allNewTransactions[1].Amount)
+	assert.Equal(t, "Checking", allNewTransactions[1].OriginalSourceAccountName)
+	assert.Equal(t, "Groceries", allNewTransactions[1].OriginalCategoryName)
+	assert.Equal(t, []string{"Red"}, allNewTransactions[1].OriginalTagNames)
+	assert.Equal(t, "Weekly shopping", allNewTransactions[1].Comment)
+
+	assert.Equal(t, int64(1234567890), allNewTransactions[2].Uid)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, allNewTransactions[2].Type)
+	assert.Equal(t, "2024-09-03 00:00:00", utils.FormatUnixTimeToLongDateTime(utils.GetUnixTimeFromTransactionTime(allNewTransactions[2].TransactionTime), time.UTC))
+	assert.Equal(t, int64(250000), allNewTransactions[2].Amount)
+	assert.Equal(t, "Checking", allNewTransactions[2].OriginalSourceAccountName)
+	assert.Equal(t, "Ready to Assign", allNewTransactions[2].OriginalCategoryName)
+	assert.Equal(t, "Salary", allNewTransactions[2].Comment)
+
+	assert.Equal(t, int64(1234567890), allNewTransactions[3].Uid)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[3].Type)
+	assert.Equal(t, "2024-09-04 00:00:00", utils.FormatUnixTimeToLongDateTime(utils.GetUnixTimeFromTransactionTime(allNewTransactions[3].TransactionTime), time.UTC))
+	assert.Equal(t, int64(50000), allNewTransactions[3].Amount)
+	assert.Equal(t, int64(50000), allNewTransactions[3].RelatedAccountAmount)
+	assert.Equal(t, "Checking", allNewTransactions[3].OriginalSourceAccountName)
+	assert.Equal(t, "Savings", allNewTransactions[3].OriginalDestinationAccountName)
+	assert.Equal(t, "Move to savings", allNewTransactions[3].Comment)
+
+	assert.Equal(t, int64(1234567890), allNewTransactions[4].Uid)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[4].Type)
+	assert.Equal(t, "2024-09-05 00:00:00", utils.FormatUnixTimeToLongDateTime(utils.GetUnixTimeFromTransactionTime(allNewTransactions[4].TransactionTime), time.UTC))
+	assert.Equal(t, int64(3000), allNewTransactions[4].Amount)
+	assert.Equal(t, "Checking", allNewTransactions[4].OriginalSourceAccountName)
+	assert.Equal(t, "Clothing", allNewTransactions[4].OriginalCategoryName)
+	assert.Equal(t, "Shirt", allNewTransactions[4].Comment)
+
+	assert.Equal(t, int64(1234567890), allNewTransactions[5].Uid)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[5].Type)
+	assert.Equal(t, "2024-09-05 00:00:00", utils.FormatUnixTimeToLongDateTime(utils.GetUnixTimeFromTransactionTime(allNewTransactions[5].TransactionTime), time.UTC))
+	assert.Equal(t, int64(2050), allNewTransactions[5].Amount)
+	assert.Equal(t, "Checking", allNewTransactions[5].OriginalSourceAccountName)
+	assert.Equal(t, "Household", allNewTransactions[5].OriginalCategoryName)
+	assert.Equal(t, "Lamp", allNewTransactions[5].Comment)
+
+	assert.Equal(t, int64(1234567890), allNewAccounts[0].Uid)
+	assert.Equal(t, "Checking", allNewAccounts[0].Name)
+	assert.Equal(t, "USD", allNewAccounts[0].Currency)
+
+	assert.Equal(t, int64(1234567890), allNewAccounts[1].Uid)
+	assert.Equal(t, "Savings", allNewAccounts[1].Name)
+	assert.Equal(t, "USD", allNewAccounts[1].Currency)
+
+	assert.Equal(t, int64(1234567890), allNewTags[0].Uid)
+	assert.Equal(t, "Red", allNewTags[0].Name)
+}
+
+func TestYnabTransactionDataImporterParseImportedData_CategoryGroupFromPlanFile(t *testing.T) {
+	importer := YnabTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "USD",
+	}
+
+	testdata, err := os.ReadFile("../../../testdata/ynab_test_file.zip")
+	assert.Nil(t, err)
+
+	expenseCategoryMap := map[string]map[string]*models.TransactionCategory{
+		"Household": {
+			"Monthly Bills": &models.TransactionCategory{
+				CategoryId: 1001,
+				Name:       "Household",
+			},
+			"Home": &models.TransactionCategory{
+				CategoryId: 1002,
+				Name:       "Household",
+			},
+		},
+	}
+
+	allNewTransactions, _, allNewSubExpenseCategories, _, _, _, err := importer.ParseImportedData(context, user, testdata, time.UTC, converter.DefaultImporterOptions, nil, expenseCategoryMap, nil, nil, nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 2, len(allNewSubExpenseCategories))
+	assert.Equal(t, "Household", allNewTransactions[5].OriginalCategoryName)
+	assert.Equal(t, int64(1001), allNewTransactions[5].CategoryId)
+}
+
+func TestYnabTransactionDataImporterParseImportedData_RegisterCsvFile(t *testing.T) {
+	importer := YnabTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "EUR",
+	}
+
+	allNewTransactions, allNewAccounts, allNewSubExpenseCategories, allNewSubIncomeCategories, allNewSubTransferCategories, allNewTags, err := importer.ParseImportedData(context, user, []byte(
+		"\"Account\",\"Flag\",\"Date\",\"Payee\",\"Category Group/Category\",\"Category Group\",\"Category\",\"Memo\",\"Outflow\",\"Inflow\",\"Cleared\"\n"+
+			"\"Girokonto\",\"\",\"13.09.2024\",\"Supermarkt\",\"Alltag: Lebensmittel\",\"Alltag\",\"Lebensmittel\",\"\",\"1.234,56€\",\"0,00€\",\"Cleared\"\n"+
+			"\"Girokonto\",\"\",\"01.09.2024\",\"Transfer : Sparkonto\",\"\",\"\",\"\",\"\",\"0,00€\",\"50,00€\",\"Cleared\"\n"+
+			"\"Girokonto\",\"\",\"02.09.2024\",\"Bank\",\"\",\"\",\"\",\"\",\"0,00€\",\"0,00€\",\"Cleared\"\n"), time.UTC, converter.DefaultImporterOptions.WithPayeeAsTag(), nil, nil, nil, nil, nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 2, len(allNewTransactions))
+	assert.Equal(t, 2, len(allNewAccounts))
+	assert.Equal(t, 1, len(allNewSubExpenseCategories))
+	assert.Equal(t, 0, len(allNewSubIncomeCategories))
+	assert.Equal(t, 1, len(allNewSubTransferCategories))
+	assert.Equal(t, 2, len(allNewTags))
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[0].Type)
+	assert.Equal(t, "2024-09-01 00:00:00", utils.FormatUnixTimeToLongDateTime(utils.GetUnixTimeFromTransactionTime(allNewTransactions[0].TransactionTime), time.UTC))
+	assert.Equal(t, int64(5000), allNewTransactions[0].Amount)
+	assert.Equal(t, "Sparkonto", allNewTransactions[0].OriginalSourceAccountName)
+	assert.Equal(t, "Girokonto", allNewTransactions[0].OriginalDestinationAccountName)
+	assert.Equal(t, []string{"Transfer : Sparkonto"}, allNewTransactions[0].OriginalTagNames)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[1].Type)
+	assert.Equal(t, "2024-09-13 00:00:00", utils.FormatUnixTimeToLongDateTime(utils.GetUnixTimeFromTransactionTime(allNewTransactions[1].TransactionTime), time.UTC))
+	assert.Equal(t, int64(123456), allNewTransactions[1].Amount)
+	assert.Equal(t, "Girokonto", allNewTransactions[1].OriginalSourceAccountName)
+	assert.Equal(t, "Lebensmittel", allNewTransactions[1].OriginalCategoryName)
+	assert.Equal(t, []string{"Supermarkt"}, allNewTransactions[1].OriginalTagNames)
+	assert.Equal(t, "EUR", allNewAccounts[0].Currency)
+}
+
+func TestYnabTransactionDataImporterParseImportedData_PayeeAsDescription(t *testing.T) {
+	importer := YnabTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "USD",
+	}
+
+	allNewTransactions, _, _, _, _, _, err := importer.ParseImportedData(context, user, []byte(
+		"\"Account\",\"Flag\",\"Date\",\"Payee\",\"Category Group/Category\",\"Category Group\",\"Category\",\"Memo\",\"Outflow\",\"Inflow\",\"Cleared\"\n"+
+			"\"Checking\",\"\",\"2024-09-01\",\"Coffee Shop\",\"Fun: Dining\",\"Fun\",\"Dining\",\"\",\"$4.50\",\"$0.00\",\"Cleared\"\n"+
+			"\"Checking\",\"\",\"2024-09-02\",\"Bakery\",\"Fun: Dining\",\"Fun\",\"Dining\",\"Bread\",\"$3.00\",\"$0.00\",\"Cleared\"\n"), time.UTC, converter.DefaultImporterOptions.WithPayeeAsDescription(), nil, nil, nil, nil, nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 2, len(allNewTransactions))
+	assert.Equal(t, "Coffee Shop", allNewTransactions[0].Comment)
+	assert.Equal(t, "Bread", allNewTransactions[1].Comment)
+}
+
+func TestYnabTransactionDataImporterParseImportedData_MissingRequiredColumn(t *testing.T) {
+	importer := YnabTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "USD",
+	}
+
+	_, _, _, _, _, _, err := importer.ParseImportedData(context, user, []byte(
+		"\"Account\",\"Date\",\"Payee\",\"Outflow\"\n"+
+			"\"Checking\",\"2024-09-01\",\"Coffee Shop\",\"$4.50\"\n"), time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrMissingRequiredFieldInHeaderRow.Message)
+}
+
+func TestYnabTransactionDataImporterParseImportedData_InvalidDate(t *testing.T) {
+	importer := YnabTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "USD",
+	}
+
+	_, _, _, _, _, _, err := importer.ParseImportedData(context, user, []byte(
+		"\"Account\",\"Flag\",\"Date\",\"Payee\",\"Category Group/Category\",\"Category Group\",\"Category\",\"Memo\",\"Outflow\",\"Inflow\",\"Cleared\"\n"+
+			"\"Checking\",\"\",\"Sep 1, 2024\",\"Coffee Shop\",\"Fun: Dining\",\"Fun\",\"Dining\",\"\",\"$4.50\",\"$0.00\",\"Cleared\"\n"), time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrTransactionTimeInvalid.Message)
+}
+
+func TestYnabTransactionDataImporterParseImportedData_ZipFileWithoutRegisterFile(t *testing.T) {
+	importer := YnabTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "USD",
+	}
+
+	_, _, _, _, _, _, err := importer.ParseImportedData(context, user, []byte{0x50, 0x4B, 0x03, 0x04, 0x00}, time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrInvalidYNABFile.Message)
+}
+
+func TestParseYnabAmount(t *testing.T) {
+	testCases := map[string]int64{
+		"":           0,
+		"$0.00":      0,
+		"$1,234.56":  123456,
+		"-$1,234.56": -123456,
+		"1.234,56€":  123456,
+		"1 234,5 kr": 123450,
+		"¥1,234":     123400,
+		"($12.30)":   -1230,
+		"12":         1200,
+	}
+
+	for amount, expectedValue := range testCases {
+		actualValue, err := parseYnabAmount(amount)
+		assert.Nil(t, err, amount)
+		assert.Equal(t, expectedValue, actualValue, amount)
+	}
+}
+
+func TestDetectYnabDateFormatType(t *testing.T) {
+	assert.Equal(t, ynabYearMonthDayDateFormat, detectYnabDateFormatType([]*ynabTransactionData{{Date: "2024-09-13"}, {Date: "2024/09/01"}}))
+	assert.Equal(t, ynabMonthDayYearDateFormat, detectYnabDateFormatType([]*ynabTransactionData{{Date: "09/01/2024"}, {Date: "09/13/2024"}}))
+	assert.Equal(t, ynabMonthDayYearDateFormat, detectYnabDateFormatType([]*ynabTransactionData{{Date: "09/01/2024"}, {Date: "09/02/2024"}}))
+	assert.Equal(t, ynabDayMonthYearDateFormat, detectYnabDateFormatType([]*ynabTransactionData{{Date: "01/09/2024"}, {Date: "13/09/2024"}}))
+	assert.Equal(t, ynabDayMonthYearDateFormat, detectYnabDateFormatType([]*ynabTransactionData{{Date: "01.09.2024"}, {Date: "02.09.2024"}}))
+}
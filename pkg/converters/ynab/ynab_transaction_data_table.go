@@ -0,0 +1,398 @@
+package ynab
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/datatable"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+var ynabSplitMemoPrefixPattern = regexp.MustCompile(`^Split \(\d+/\d+\)\s*`)
+
+var ynabTransactionSupportedColumns = map[datatable.TransactionDataTableColumn]bool{
+	datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIME:     true,
+	datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE:     true,
+	datatable.TRANSACTION_DATA_TABLE_CATEGORY:             true,
+	datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY:         true,
+	datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME:         true,
+	datatable.TRANSACTION_DATA_TABLE_AMOUNT:               true,
+	datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME: true,
+	datatable.TRANSACTION_DATA_TABLE_RELATED_AMOUNT:       true,
+	datatable.TRANSACTION_DATA_TABLE_TAGS:                 true,
+	datatable.TRANSACTION_DATA_TABLE_DESCRIPTION:          true,
+	datatable.TRANSACTION_DATA_TABLE_PAYEE:                true,
+}
+
+// ynabDateFormatType represents the date format type in ynab register file, which depends on the user settings in ynab
+type ynabDateFormatType byte
+
+const (
+	ynabYearMonthDayDateFormat ynabDateFormatType = 0
+	ynabMonthDayYearDateFormat ynabDateFormatType = 1
+	ynabDayMonthYearDateFormat ynabDateFormatType = 2
+)
+
+// ynabTransactionDataTable defines the structure of ynab transaction data table
+type ynabTransactionDataTable struct {
+	dateFormatType   ynabDateFormatType
+	allData          []*ynabTransactionData
+	categoryGroupMap map[string]string
+}
+
+// ynabTransactionDataRow defines the structure of ynab transaction data row
+type ynabTransactionDataRow struct {
+	dataTable  *ynabTransactionDataTable
+	data       *ynabTransactionData
+	finalItems map[datatable.TransactionDataTableColumn]string
+	isValid    bool
+}
+
+// ynabTransactionDataRowIterator defines the structure of ynab transaction data row iterator
+type ynabTransactionDataRowIterator struct {
+	dataTable    *ynabTransactionDataTable
+	currentIndex int
+}
+
+// HasColumn returns whether the transaction data table has specified column
+func (t *ynabTransactionDataTable) HasColumn(column datatable.TransactionDataTableColumn) bool {
+	_, exists := ynabTransactionSupportedColumns[column]
+	return exists
+}
+
+// TransactionRowCount returns the total count of transaction data row
+func (t *ynabTransactionDataTable) TransactionRowCount() int {
+	return len(t.allData)
+}
+
+// TransactionRowIterator returns the iterator of transaction data row
+func (t *ynabTransactionDataTable) TransactionRowIterator() datatable.TransactionDataRowIterator {
+	return &ynabTransactionDataRowIterator{
+		dataTable:    t,
+		currentIndex: -1,
+	}
+}
+
+// IsValid returns whether this row is valid data for importing
+func (r *ynabTransactionDataRow) IsValid() bool {
+	return r.isValid
+}
+
+// GetData returns the data in the specified column type
+func (r *ynabTransactionDataRow) GetData(column datatable.TransactionDataTableColumn) string {
+	_, exists := ynabTransactionSupportedColumns[column]
+
+	if exists {
+		return r.finalItems[column]
+	}
+
+	return ""
+}
+
+// HasNext returns whether the iterator does not reach the end
+func (t *ynabTransactionDataRowIterator) HasNext() bool {
+	return t.currentIndex+1 < len(t.dataTable.allData)
+}
+
+// Next returns the next transaction data row
+func (t *ynabTransactionDataRowIterator) Next(ctx core.Context, user *models.User) (daraRow datatable.TransactionDataRow, err error) {
+	if t.currentIndex+1 >= len(t.dataTable.allData) {
+		return nil, nil
+	}
+
+	t.currentIndex++
+
+	data := t.dataTable.allData[t.currentIndex]
+	rowItems, isValid, err := t.parseTransaction(ctx, user, data)
+
+	if err != nil {
+		log.Errorf(ctx, "[ynab_transaction_data_table.Next] cannot parsing transaction in row#%d, because %s", t.currentIndex, err.Error())
+		return nil, err
+	}
+
+	return &ynabTransactionDataRow{
+		dataTable:  t.dataTable,
+		data:       data,
+		finalItems: rowItems,
+		isValid:    isValid,
+	}, nil
+}
+
+func (t *ynabTransactionDataRowIterator) parseTransaction(ctx core.Context, user *models.User, ynabTransaction *ynabTransactionData) (map[datatable.TransactionDataTableColumn]string, bool, error) {
+	data := make(map[datatable.TransactionDataTableColumn]string, len(ynabTransactionSupportedColumns))
+
+	if ynabTransaction.Date == "" {
+		return nil, false, errs.ErrMissingTransactionTime
+	}
+
+	transactionTime, err := parseYnabTransactionTime(ynabTransaction.Date, t.dataTable.dateFormatType)
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIME] = transactionTime
+
+	outflow, err := parseYnabAmount(ynabTransaction.Outflow)
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	inflow, err := parseYnabAmount(ynabTransaction.Inflow)
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	amount := inflow - outflow
+
+	if amount == 0 {
+		log.Warnf(ctx, "[ynab_transaction_data_table.parseTransaction] skip parsing transaction in account \"%s\" at \"%s\" with zero amount", ynabTransaction.Account, ynabTransaction.Date)
+		return nil, false, nil
+	}
+
+	data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME] = ynabTransaction.Account
+
+	if ynabTransaction.IsTransfer() {
+		data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = ynabTransactionTypeNameMapping[models.TRANSACTION_TYPE_TRANSFER]
+
+		if amount < 0 { // transfer to the account in payee
+			data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(-amount)
+			data[datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME] = ynabTransaction.GetTransferAccountName()
+		} else { // transfer from the account in payee, only exists when the outflow side is not in the file
+			data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(amount)
+			data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME] = ynabTransaction.GetTransferAccountName()
+			data[datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME] = ynabTransaction.Account
+		}
+
+		data[datatable.TRANSACTION_DATA_TABLE_RELATED_AMOUNT] = data[datatable.TRANSACTION_DATA_TABLE_AMOUNT]
+	} else if ynabTransaction.Payee == ynabStartingBalancePayee {
+		data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = ynabTransactionTypeNameMapping[models.TRANSACTION_TYPE_MODIFY_BALANCE]
+		data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(amount)
+	} else {
+		if amount > 0 {
+			data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = ynabTransactionTypeNameMapping[models.TRANSACTION_TYPE_INCOME]
+			data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(amount)
+		} else {
+			data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = ynabTransactionTypeNameMapping[models.TRANSACTION_TYPE_EXPENSE]
+			data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(-amount)
+		}
+
+		categoryGroup := ynabTransaction.CategoryGroup
+
+		if categoryGroup == "" {
+			categoryGroup = t.dataTable.categoryGroupMap[ynabTransaction.Category]
+		}
+
+		data[datatable.TRANSACTION_DATA_TABLE_CATEGORY] = categoryGroup
+		data[datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY] = ynabTransaction.Category
+	}
+
+	data[datatable.TRANSACTION_DATA_TABLE_PAYEE] = ynabTransaction.Payee
+	data[datatable.TRANSACTION_DATA_TABLE_TAGS] = ynabTransaction.Flag
+	data[datatable.TRANSACTION_DATA_TABLE_DESCRIPTION] = ynabSplitMemoPrefixPattern.ReplaceAllString(ynabTransaction.Memo, "")
+
+	return data, true, nil
+}
+
+func parseYnabTransactionTime(date string, dateFormatType ynabDateFormatType) (string, error) {
+	items := splitYnabDate(date)
+
+	if len(items) != 3 {
+		return "", errs.ErrTransactionTimeInvalid
+	}
+
+	year, month, day := items[0], items[1], items[2]
+
+	if len(items[0]) != 4 {
+		if dateFormatType == ynabMonthDayYearDateFormat {
+			month, day, year = items[0], items[1], items[2]
+		} else if dateFormatType == ynabDayMonthYearDateFormat {
+			day, month, year = items[0], items[1], items[2]
+		} else {
+			return "", errs.ErrTransactionTimeInvalid
+		}
+	}
+
+	if len(year) != 4 || len(month) < 1 || len(month) > 2 || len(day) < 1 || len(day) > 2 {
+		return "", errs.ErrTransactionTimeInvalid
+	}
+
+	if len(month) == 1 {
+		month = "0" + month
+	}
+
+	if len(day) == 1 {
+		day = "0" + day
+	}
+
+	return year + "-" + month + "-" + day + " 00:00:00", nil
+}
+
+func splitYnabDate(date string) []string {
+	return strings.FieldsFunc(date, func(r rune) bool {
+		return r == '/' || r == '-' || r == '.'
+	})
+}
+
+// detectYnabDateFormatType returns the date format type of all dates, the day-first or month-first format is detected by the dates whose day is greater than 12,
+// and the month-first format is preferred for slash separated dates and the day-first format is preferred for the others if it cannot be detected
+func detectYnabDateFormatType(allData []*ynabTransactionData) ynabDateFormatType {
+	slashSeparated := false
+
+	for i := 0; i < len(allData); i++ {
+		items := splitYnabDate(allData[i].Date)
+
+		if len(items) != 3 || len(items[0]) == 4 {
+			continue
+		}
+
+		slashSeparated = strings.Contains(allData[i].Date, "/")
+
+		first, err := utils.StringToInt(items[0])
+
+		if err == nil && first > 12 {
+			return ynabDayMonthYearDateFormat
+		}
+
+		second, err := utils.StringToInt(items[1])
+
+		if err == nil && second > 12 {
+			return ynabMonthDayYearDateFormat
+		}
+	}
+
+	if slashSeparated {
+		return ynabMonthDayYearDateFormat
+	}
+
+	for i := 0; i < len(allData); i++ {
+		items := splitYnabDate(allData[i].Date)
+
+		if len(items) == 3 && len(items[0]) != 4 {
+			return ynabDayMonthYearDateFormat
+		}
+	}
+
+	return ynabYearMonthDayDateFormat
+}
+
+// parseYnabAmount returns the amount in cents, the amount in ynab file is formatted by the currency settings in ynab (e.g. "$1,234.56", "1.234,56€" or "-¥1,234")
+func parseYnabAmount(amount string) (int64, error) {
+	negative := false
+	numbers := make([]byte, 0, len(amount))
+
+	for i := 0; i < len(amount); i++ {
+		ch := amount[i]
+
+		if '0' <= ch && ch <= '9' || ch == '.' || ch == ',' {
+			numbers = append(numbers, ch)
+		} else if ch == '-' || ch == '(' {
+			negative = true
+		}
+	}
+
+	if len(numbers) < 1 {
+		return 0, nil
+	}
+
+	integer := string(numbers)
+	decimals := ""
+	decimalSeparatorIndex := strings.LastIndexAny(integer, ".,")
+
+	// the last separator is the decimal separator only if there are at most two digits after it, otherwise it is the digit grouping symbol
+	if decimalSeparatorIndex >= 0 && len(integer)-decimalSeparatorIndex-1 <= 2 {
+		decimals = integer[decimalSeparatorIndex+1:]
+		integer = integer[0:decimalSeparatorIndex]
+	}
+
+	integer = strings.ReplaceAll(strings.ReplaceAll(integer, ",", ""), ".", "")
+
+	if integer == "" {
+		integer = "0"
+	}
+
+	if decimals != "" {
+		integer = integer + "." + decimals
+	}
+
+	value, err := utils.ParseAmount(integer)
+
+	if err != nil {
+		return 0, errs.ErrAmountInvalid
+	}
+
+	if negative {
+		return -value, nil
+	}
+
+	return value, nil
+}
+
+// removeYnabDuplicatedTransfers returns the transactions without the inflow side of the transfers,
+// the inflow side is kept only when the outflow side of the same transfer does not exist in the file
+func removeYnabDuplicatedTransfers(ctx core.Context, allData []*ynabTransactionData) []*ynabTransactionData {
+	outflowTransferCounts := make(map[string]int)
+
+	for i := 0; i < len(allData); i++ {
+		transaction := allData[i]
+
+		if !transaction.IsTransfer() {
+			continue
+		}
+
+		outflow, err := parseYnabAmount(transaction.Outflow)
+
+		if err == nil && outflow > 0 {
+			outflowTransferCounts[getYnabTransferKey(transaction.Date, transaction.Account, transaction.GetTransferAccountName(), outflow)]++
+		}
+	}
+
+	result := make([]*ynabTransactionData, 0, len(allData))
+
+	for i := 0; i < len(allData); i++ {
+		transaction := allData[i]
+
+		if transaction.IsTransfer() {
+			inflow, err := parseYnabAmount(transaction.Inflow)
+
+			if err == nil && inflow > 0 {
+				transferKey := getYnabTransferKey(transaction.Date, transaction.GetTransferAccountName(), transaction.Account, inflow)
+
+				if outflowTransferCounts[transferKey] > 0 {
+					outflowTransferCounts[transferKey]--
+					continue
+				}
+
+				log.Warnf(ctx, "[ynab_transaction_data_table.removeYnabDuplicatedTransfers] cannot find the outflow side of the transfer from \"%s\" to \"%s\" at \"%s\"", transaction.GetTransferAccountName(), transaction.Account, transaction.Date)
+			}
+		}
+
+		result = append(result, transaction)
+	}
+
+	return result
+}
+
+func getYnabTransferKey(date string, fromAccountName string, toAccountName string, amount int64) string {
+	return date + "\n" + fromAccountName + "\n" + toAccountName + "\n" + utils.Int64ToString(amount)
+}
+
+func createNewYnabTransactionDataTable(ctx core.Context, ynabData *ynabData) (*ynabTransactionDataTable, error) {
+	if ynabData == nil || len(ynabData.Transactions) < 1 {
+		return nil, errs.ErrNotFoundTransactionDataInFile
+	}
+
+	allData := removeYnabDuplicatedTransfers(ctx, ynabData.Transactions)
+
+	return &ynabTransactionDataTable{
+		dateFormatType:   detectYnabDateFormatType(allData),
+		allData:          allData,
+		categoryGroupMap: ynabData.CategoryGroupMap,
+	}, nil
+}
@@ -12,6 +12,7 @@ type CronContext struct {
 	context.Context
 	contextId       string
 	cronJobInterval time.Duration
+	result          string
 }
 
 // GetContextId returns the current context id
@@ -29,6 +30,16 @@ func (c *CronContext) GetInterval() time.Duration {
 	return c.cronJobInterval
 }
 
+// SetResult sets the result summary of the current cron job, which would be logged after the cron job finishes
+func (c *CronContext) SetResult(result string) {
+	c.result = result
+}
+
+// GetResult returns the result summary of the current cron job
+func (c *CronContext) GetResult() string {
+	return c.result
+}
+
 // NewCronJobContext returns a new cron job context
 func NewCronJobContext(cronJobName string, cronJobInterval time.Duration) *CronContext {
 	return &CronContext{
@@ -16,6 +16,9 @@ const webContextTextualTokenFieldKey = "TOKEN_STRING"
 const webContextTokenClaimsFieldKey = "TOKEN_CLAIMS"
 const webContextTokenContextFieldKey = "TOKEN_CONTEXT"
 const webContextResponseErrorFieldKey = "RESPONSE_ERROR"
+const webContextCurrentLedgerIdFieldKey = "CURRENT_LEDGER_ID"
+const webContextCurrentLedgerOwnerUidFieldKey = "CURRENT_LEDGER_OWNER_UID"
+const webContextCurrentLedgerRoleFieldKey = "CURRENT_LEDGER_ROLE"
 
 // AcceptLanguageHeaderName represents the header name of accept language
 const AcceptLanguageHeaderName = "Accept-Language"
@@ -29,10 +32,14 @@ const ClientTimezoneOffsetHeaderName = "X-Timezone-Offset"
 // ClientTimezoneNameHeaderName represents the header name of client timezone name
 const ClientTimezoneNameHeaderName = "X-Timezone-Name"
 
+// LedgerIdHeaderName represents the header name of current ledger id
+const LedgerIdHeaderName = "X-Ledger-Id"
+
 const tokenHeaderName = "Authorization"
 const tokenHeaderValuePrefix = "bearer "
 const tokenQueryStringParam = "token"
 const tokenCookieParam = "ebk_auth_token"
+const ledgerIdQueryStringParam = "ledger_id"
 
 // WebContext represents the request and response context
 type WebContext struct {
@@ -145,6 +152,69 @@ func (c *WebContext) GetCurrentUid() int64 {
 	return claims.Uid
 }
 
+// GetRequestedLedgerId returns the ledger id which is specified in the request header or query string, or 0 if not specified
+func (c *WebContext) GetRequestedLedgerId() (int64, error) {
+	value := c.GetHeader(LedgerIdHeaderName)
+
+	if value == "" {
+		value = c.Query(ledgerIdQueryStringParam)
+	}
+
+	if value == "" {
+		return 0, nil
+	}
+
+	return strconv.ParseInt(value, 10, 64)
+}
+
+// SetCurrentLedgerId sets the given ledger id to context
+func (c *WebContext) SetCurrentLedgerId(ledgerId int64) {
+	c.Set(webContextCurrentLedgerIdFieldKey, ledgerId)
+}
+
+// GetCurrentLedgerId returns the current ledger id of the request
+func (c *WebContext) GetCurrentLedgerId() int64 {
+	ledgerId, exists := c.Get(webContextCurrentLedgerIdFieldKey)
+
+	if !exists {
+		return 0
+	}
+
+	return ledgerId.(int64)
+}
+
+// SetCurrentLedgerOwnerUid sets the owner uid of current ledger to context
+func (c *WebContext) SetCurrentLedgerOwnerUid(ownerUid int64) {
+	c.Set(webContextCurrentLedgerOwnerUidFieldKey, ownerUid)
+}
+
+// GetCurrentLedgerOwnerUid returns the owner uid of current ledger, or the current uid if it is not set
+func (c *WebContext) GetCurrentLedgerOwnerUid() int64 {
+	ownerUid, exists := c.Get(webContextCurrentLedgerOwnerUidFieldKey)
+
+	if !exists {
+		return c.GetCurrentUid()
+	}
+
+	return ownerUid.(int64)
+}
+
+// SetCurrentLedgerRole sets the role of current user in current ledger to context
+func (c *WebContext) SetCurrentLedgerRole(role byte) {
+	c.Set(webContextCurrentLedgerRoleFieldKey, role)
+}
+
+// GetCurrentLedgerRole returns the role of current user in current ledger, or 0 if it is not set
+func (c *WebContext) GetCurrentLedgerRole() byte {
+	role, exists := c.Get(webContextCurrentLedgerRoleFieldKey)
+
+	if !exists {
+		return 0
+	}
+
+	return role.(byte)
+}
+
 // GetTokenStringFromHeader returns the token string from the request header
 func (c *WebContext) GetTokenStringFromHeader() string {
 	tokenHeader := c.GetHeader(tokenHeaderName)
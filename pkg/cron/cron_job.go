@@ -44,10 +44,20 @@ func (j *CronJob) doRun() {
 	now := time.Now()
 
 	if err != nil {
-		log.Errorf(c, "[cron_job.doRun] failed to run job \"%s\", because %s", j.Name, err.Error())
+		if c.GetResult() != "" {
+			log.Errorf(c, "[cron_job.doRun] failed to run job \"%s\", because %s, result: %s", j.Name, err.Error(), c.GetResult())
+		} else {
+			log.Errorf(c, "[cron_job.doRun] failed to run job \"%s\", because %s", j.Name, err.Error())
+		}
+
 		return
 	}
 
 	cost := now.Sub(start).Nanoseconds() / 1e6
-	log.Infof(c, "[cron_job.doRun] run job \"%s\" successfully, cost %dms", j.Name, cost)
+
+	if c.GetResult() != "" {
+		log.Infof(c, "[cron_job.doRun] run job \"%s\" successfully, cost %dms, result: %s", j.Name, cost, c.GetResult())
+	} else {
+		log.Infof(c, "[cron_job.doRun] run job \"%s\" successfully, cost %dms", j.Name, cost)
+	}
 }
@@ -1,10 +1,12 @@
 package cron
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
 )
 
 // RemoveExpiredTokensJob represents the cron job which periodically remove expired user tokens from the database
@@ -30,3 +32,22 @@ var CreateScheduledTransactionJob = &CronJob{
 		return services.Transactions.CreateScheduledTransactions(c, time.Now().Unix(), c.GetInterval())
 	},
 }
+
+// RemoveOrphanedTransactionPicturesJob represents the cron job which periodically remove transaction pictures not saved with any transaction and unreferenced stored pictures
+var RemoveOrphanedTransactionPicturesJob = &CronJob{
+	Name:        "RemoveOrphanedTransactionPictures",
+	Description: "Periodically remove transaction pictures not saved with any transaction and unreferenced stored pictures.",
+	Period: CronJobFixedHourPeriod{
+		Hour: 1,
+	},
+	Run: func(c *core.CronContext) error {
+		gracePeriod := int64(settings.Container.GetCurrentConfig().OrphanedTransactionPictureGracePeriod)
+		result, err := services.TransactionPictures.RemoveAllOrphanedPictures(c, time.Now().Unix()-gracePeriod)
+
+		if result != nil {
+			c.SetResult(fmt.Sprintf("%d orphaned transaction pictures removed, %d unreferenced stored pictures deleted, %d stored picture files without records deleted", result.RemovedPictureCount, result.DeletedObjectCount, result.DeletedFileCount))
+		}
+
+		return err
+	},
+}
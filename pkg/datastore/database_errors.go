@@ -0,0 +1,39 @@
+package datastore
+
+import (
+	"errors"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	"github.com/mattn/go-sqlite3"
+)
+
+const mysqlDuplicateEntryErrorNumber = 1062
+const postgresUniqueViolationErrorCode = "23505"
+
+// IsUniqueConstraintViolation returns whether the error is caused by violating the unique constraint or the primary key of table
+func IsUniqueConstraintViolation(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var mysqlError *mysql.MySQLError
+
+	if errors.As(err, &mysqlError) {
+		return mysqlError.Number == mysqlDuplicateEntryErrorNumber
+	}
+
+	var postgresError *pq.Error
+
+	if errors.As(err, &postgresError) {
+		return postgresError.Code == postgresUniqueViolationErrorCode
+	}
+
+	var sqliteError sqlite3.Error
+
+	if errors.As(err, &sqliteError) {
+		return sqliteError.ExtendedCode == sqlite3.ErrConstraintUnique || sqliteError.ExtendedCode == sqlite3.ErrConstraintPrimaryKey
+	}
+
+	return false
+}
@@ -26,4 +26,5 @@ var (
 	ErrNotSupportedChangeCurrency             = NewNormalError(NormalSubcategoryAccount, 20, http.StatusBadRequest, "not supported to modify account currency")
 	ErrNotSupportedChangeBalance              = NewNormalError(NormalSubcategoryAccount, 21, http.StatusBadRequest, "not supported to modify account balance")
 	ErrNotSupportedChangeBalanceTime          = NewNormalError(NormalSubcategoryAccount, 22, http.StatusBadRequest, "not supported to modify account balance time")
+	ErrCannotMergeAccountIntoItself           = NewNormalError(NormalSubcategoryAccount, 23, http.StatusBadRequest, "cannot merge account into itself")
 )
@@ -0,0 +1,22 @@
+package errs
+
+import "net/http"
+
+// Error codes related to contacts
+var (
+	ErrContactIdInvalid                      = NewNormalError(NormalSubcategoryContact, 0, http.StatusBadRequest, "contact id is invalid")
+	ErrContactNotFound                       = NewNormalError(NormalSubcategoryContact, 1, http.StatusBadRequest, "contact not found")
+	ErrContactNameIsEmpty                    = NewNormalError(NormalSubcategoryContact, 2, http.StatusBadRequest, "contact name is empty")
+	ErrContactNameAlreadyExists              = NewNormalError(NormalSubcategoryContact, 3, http.StatusBadRequest, "contact name already exists")
+	ErrContactInUseCannotBeDeleted           = NewNormalError(NormalSubcategoryContact, 4, http.StatusBadRequest, "contact has unsettled balance and cannot be deleted")
+	ErrSplitMethodInvalid                    = NewNormalError(NormalSubcategoryContact, 5, http.StatusBadRequest, "split method is invalid")
+	ErrSplitParticipantsEmpty                = NewNormalError(NormalSubcategoryContact, 6, http.StatusBadRequest, "split participants are empty")
+	ErrSplitSharesInvalid                    = NewNormalError(NormalSubcategoryContact, 7, http.StatusBadRequest, "split shares are invalid")
+	ErrSplitAmountsNotEqualToTotalAmount     = NewNormalError(NormalSubcategoryContact, 8, http.StatusBadRequest, "sum of split amounts is not equal to total amount")
+	ErrSplitParticipantDuplicated            = NewNormalError(NormalSubcategoryContact, 9, http.StatusBadRequest, "split participant is duplicated")
+	ErrSplitPayerInvalid                     = NewNormalError(NormalSubcategoryContact, 10, http.StatusBadRequest, "split payer is invalid")
+	ErrContactAccountCategoryInvalid         = NewNormalError(NormalSubcategoryContact, 11, http.StatusBadRequest, "account must be a receivables or debt account")
+	ErrSplitAmountInvalid                    = NewNormalError(NormalSubcategoryContact, 12, http.StatusBadRequest, "split amount is invalid")
+	ErrContactAccountsCurrencyNotEqual       = NewNormalError(NormalSubcategoryContact, 13, http.StatusBadRequest, "currencies of accounts are not the same")
+	ErrCannotChangeContactTransactionAccount = NewNormalError(NormalSubcategoryContact, 14, http.StatusBadRequest, "cannot change receivables or debt account of contact transaction")
+)
@@ -31,4 +31,11 @@ var (
 	ErrInvalidXmlFile                      = NewNormalError(NormalSubcategoryConverter, 24, http.StatusBadRequest, "invalid xml file")
 	ErrInvalidMT940File                    = NewNormalError(NormalSubcategoryConverter, 25, http.StatusBadRequest, "invalid mt940 file")
 	ErrInvalidJSONFile                     = NewNormalError(NormalSubcategoryConverter, 26, http.StatusBadRequest, "invalid json file")
+	ErrInvalidLedgerFile                   = NewNormalError(NormalSubcategoryConverter, 27, http.StatusBadRequest, "invalid ledger file")
+	ErrLedgerFileNotSupportInclude         = NewNormalError(NormalSubcategoryConverter, 28, http.StatusBadRequest, "not support include directive for ledger file")
+	ErrInvalidYNABFile                     = NewNormalError(NormalSubcategoryConverter, 29, http.StatusBadRequest, "invalid ynab file")
+	ErrInvalidActualBudgetFile             = NewNormalError(NormalSubcategoryConverter, 30, http.StatusBadRequest, "invalid actual budget file")
+	ErrInvalidHomeBankFile                 = NewNormalError(NormalSubcategoryConverter, 31, http.StatusBadRequest, "invalid homebank file")
+	ErrInvalidKMyMoneyFile                 = NewNormalError(NormalSubcategoryConverter, 32, http.StatusBadRequest, "invalid kmymoney file")
+	ErrInvalidMoneyManagerExFile           = NewNormalError(NormalSubcategoryConverter, 33, http.StatusBadRequest, "invalid money manager ex file")
 )
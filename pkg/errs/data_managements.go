@@ -6,7 +6,14 @@ import (
 
 // Error codes related to data management
 var (
-	ErrDataExportNotAllowed     = NewNormalError(NormalSubcategoryDataManagement, 1, http.StatusBadRequest, "data export not allowed")
-	ErrDataImportNotAllowed     = NewNormalError(NormalSubcategoryDataManagement, 2, http.StatusBadRequest, "data import not allowed")
-	ErrImportTooManyTransaction = NewNormalError(NormalSubcategoryDataManagement, 3, http.StatusBadRequest, "import too many transactions")
+	ErrDataExportNotAllowed      = NewNormalError(NormalSubcategoryDataManagement, 1, http.StatusBadRequest, "data export not allowed")
+	ErrDataImportNotAllowed      = NewNormalError(NormalSubcategoryDataManagement, 2, http.StatusBadRequest, "data import not allowed")
+	ErrImportTooManyTransaction  = NewNormalError(NormalSubcategoryDataManagement, 3, http.StatusBadRequest, "import too many transactions")
+	ErrBackupFileInvalid         = NewNormalError(NormalSubcategoryDataManagement, 4, http.StatusBadRequest, "backup file is invalid")
+	ErrBackupVersionNotSupported = NewNormalError(NormalSubcategoryDataManagement, 5, http.StatusBadRequest, "backup file version is not supported")
+	ErrUserDataNotEmpty          = NewNormalError(NormalSubcategoryDataManagement, 6, http.StatusBadRequest, "user data is not empty")
+	ErrBackupFileStorageDisabled = NewNormalError(NormalSubcategoryDataManagement, 7, http.StatusBadRequest, "backup file contains pictures or attachments that are not enabled")
+	ErrExportTimezoneInvalid     = NewNormalError(NormalSubcategoryDataManagement, 8, http.StatusBadRequest, "export time zone is invalid")
+	ErrExportColumnsInvalid      = NewNormalError(NormalSubcategoryDataManagement, 9, http.StatusBadRequest, "export columns are invalid")
+	ErrBackupFileTooLarge        = NewNormalError(NormalSubcategoryDataManagement, 10, http.StatusBadRequest, "backup file is too large")
 )
@@ -21,6 +21,7 @@ const (
 	SystemSubcategoryMail     = 3
 	SystemSubcategoryLogging  = 4
 	SystemSubcategoryCron     = 5
+	SystemSubcategoryStorage  = 6
 )
 
 // Sub categories of normal error
@@ -43,6 +44,12 @@ const (
 	NormalSubcategoryLargeLanguageModel     = 15
 	NormalSubcategoryUserExternalAuth       = 16
 	NormalSubcategoryOAuth2                 = 17
+	NormalSubcategoryLedger                 = 18
+	NormalSubcategoryContact                = 19
+	NormalSubcategoryEvent                  = 20
+	NormalSubcategoryTagGroup               = 21
+	NormalSubcategoryCustomField            = 22
+	NormalSubcategoryAttachment             = 23
 )
 
 // Error represents the specific error returned to user
@@ -0,0 +1,17 @@
+package errs
+
+import "net/http"
+
+// Error codes related to events
+var (
+	ErrEventIdInvalid                               = NewNormalError(NormalSubcategoryEvent, 0, http.StatusBadRequest, "event id is invalid")
+	ErrEventNotFound                                = NewNormalError(NormalSubcategoryEvent, 1, http.StatusBadRequest, "event not found")
+	ErrEventNameIsEmpty                             = NewNormalError(NormalSubcategoryEvent, 2, http.StatusBadRequest, "event name is empty")
+	ErrEventNameAlreadyExists                       = NewNormalError(NormalSubcategoryEvent, 3, http.StatusBadRequest, "event name already exists")
+	ErrEventTimeRangeInvalid                        = NewNormalError(NormalSubcategoryEvent, 4, http.StatusBadRequest, "event end time cannot be earlier than start time")
+	ErrEventBudgetCurrencyIsEmpty                   = NewNormalError(NormalSubcategoryEvent, 5, http.StatusBadRequest, "event budget currency is empty")
+	ErrEventParticipantInvalid                      = NewNormalError(NormalSubcategoryEvent, 6, http.StatusBadRequest, "event participant is invalid")
+	ErrCannotUseHiddenEvent                         = NewNormalError(NormalSubcategoryEvent, 7, http.StatusBadRequest, "cannot use hidden event")
+	ErrEventParticipantsTooMany                     = NewNormalError(NormalSubcategoryEvent, 8, http.StatusBadRequest, "too many event participants")
+	ErrBalanceModificationTransactionCannotSetEvent = NewNormalError(NormalSubcategoryEvent, 9, http.StatusBadRequest, "balance modification transaction cannot be assigned to event")
+)
@@ -0,0 +1,23 @@
+package errs
+
+import "net/http"
+
+// Error codes related to ledgers
+var (
+	ErrLedgerIdInvalid                    = NewNormalError(NormalSubcategoryLedger, 0, http.StatusBadRequest, "ledger id is invalid")
+	ErrLedgerNotFound                     = NewNormalError(NormalSubcategoryLedger, 1, http.StatusBadRequest, "ledger not found")
+	ErrLedgerNameIsEmpty                  = NewNormalError(NormalSubcategoryLedger, 2, http.StatusBadRequest, "ledger name is empty")
+	ErrLedgerNameAlreadyExists            = NewNormalError(NormalSubcategoryLedger, 3, http.StatusBadRequest, "ledger name already exists")
+	ErrLedgerInUseCannotBeDeleted         = NewNormalError(NormalSubcategoryLedger, 4, http.StatusBadRequest, "ledger is in use and cannot be deleted")
+	ErrDefaultLedgerCannotBeDeleted       = NewNormalError(NormalSubcategoryLedger, 5, http.StatusBadRequest, "default ledger cannot be deleted")
+	ErrCannotUseDataInDifferentLedger     = NewNormalError(NormalSubcategoryLedger, 6, http.StatusBadRequest, "cannot use data in different ledger")
+	ErrCannotMoveTransactionToOtherLedger = NewNormalError(NormalSubcategoryLedger, 7, http.StatusBadRequest, "cannot move transaction to other ledger")
+	ErrLedgerPermissionDenied             = NewNormalError(NormalSubcategoryLedger, 8, http.StatusForbidden, "permission denied in this ledger")
+	ErrLedgerMemberRoleInvalid            = NewNormalError(NormalSubcategoryLedger, 9, http.StatusBadRequest, "ledger member role is invalid")
+	ErrLedgerMemberNotFound               = NewNormalError(NormalSubcategoryLedger, 10, http.StatusBadRequest, "ledger member not found")
+	ErrLedgerMemberAlreadyExists          = NewNormalError(NormalSubcategoryLedger, 11, http.StatusBadRequest, "user is already a member of this ledger")
+	ErrCannotInviteYourselfToLedger       = NewNormalError(NormalSubcategoryLedger, 12, http.StatusBadRequest, "cannot invite yourself to your own ledger")
+	ErrLedgerInvitationNotFound           = NewNormalError(NormalSubcategoryLedger, 13, http.StatusBadRequest, "ledger invitation not found")
+	ErrInvitedUserNotFound                = NewNormalError(NormalSubcategoryLedger, 14, http.StatusBadRequest, "invited user not found")
+	ErrOnlyOwnLedgerCanBeShared           = NewNormalError(NormalSubcategoryLedger, 15, http.StatusBadRequest, "only your own ledger can be shared")
+)
@@ -30,4 +30,7 @@ var (
 	ErrInvalidOAuth2UserIdentifier                    = NewSystemError(SystemSubcategorySetting, 23, http.StatusInternalServerError, "invalid oauth 2.0 user identifier")
 	ErrInvalidOAuth2Provider                          = NewSystemError(SystemSubcategorySetting, 24, http.StatusInternalServerError, "invalid oauth 2.0 provider")
 	ErrInvalidOAuth2StateExpiredTime                  = NewSystemError(SystemSubcategorySetting, 25, http.StatusInternalServerError, "invalid oauth 2.0 state expired time")
+	ErrInvalidTransactionPictureConvertFormat         = NewSystemError(SystemSubcategorySetting, 26, http.StatusInternalServerError, "invalid transaction picture convert format")
+	ErrInvalidStorageEncryptionMasterKey              = NewSystemError(SystemSubcategorySetting, 27, http.StatusInternalServerError, "invalid storage encryption master key")
+	ErrInvalidOrphanedTransactionPictureGracePeriod   = NewSystemError(SystemSubcategorySetting, 28, http.StatusInternalServerError, "invalid orphaned transaction picture grace period")
 )
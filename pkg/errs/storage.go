@@ -0,0 +1,15 @@
+package errs
+
+import "net/http"
+
+// Error codes related to object storage
+var (
+	ErrStorageObjectChecksumMismatch      = NewSystemError(SystemSubcategoryStorage, 0, http.StatusInternalServerError, "storage object checksum mismatch")
+	ErrSourceAndTargetStorageTypeEqual    = NewSystemError(SystemSubcategoryStorage, 1, http.StatusInternalServerError, "source and target storage type cannot be the same")
+	ErrSomeStorageObjectsMigrateFailed    = NewSystemError(SystemSubcategoryStorage, 2, http.StatusInternalServerError, "some storage objects failed to migrate")
+	ErrStorageEncryptionNotEnabled        = NewSystemError(SystemSubcategoryStorage, 3, http.StatusInternalServerError, "storage encryption is not enabled")
+	ErrStorageEncryptionMasterKeyNotFound = NewSystemError(SystemSubcategoryStorage, 4, http.StatusInternalServerError, "master key of encrypted storage object not found")
+	ErrEncryptedStorageObjectInvalid      = NewSystemError(SystemSubcategoryStorage, 5, http.StatusInternalServerError, "encrypted storage object is invalid")
+	ErrSomeStorageObjectsEncryptFailed    = NewSystemError(SystemSubcategoryStorage, 6, http.StatusInternalServerError, "some storage objects failed to encrypt")
+	ErrStorageObjectNotEncrypted          = NewSystemError(SystemSubcategoryStorage, 7, http.StatusInternalServerError, "storage object is not encrypted")
+)
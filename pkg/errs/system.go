@@ -11,4 +11,5 @@ var (
 	ErrSystemIsBusy          = NewSystemError(SystemSubcategoryDefault, 4, http.StatusServiceUnavailable, "system is busy")
 	ErrNotSupported          = NewSystemError(SystemSubcategoryDefault, 5, http.StatusBadRequest, "not supported")
 	ErrImageTypeNotSupported = NewSystemError(SystemSubcategoryDefault, 6, http.StatusBadRequest, "image type not supported")
+	ErrImageTooLarge         = NewSystemError(SystemSubcategoryDefault, 7, http.StatusBadRequest, "image is too large")
 )
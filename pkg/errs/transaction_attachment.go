@@ -0,0 +1,19 @@
+package errs
+
+import "net/http"
+
+// Error codes related to transaction attachments
+var (
+	ErrTransactionAttachmentIdInvalid         = NewNormalError(NormalSubcategoryAttachment, 0, http.StatusBadRequest, "transaction attachment id is invalid")
+	ErrTransactionAttachmentNotFound          = NewNormalError(NormalSubcategoryAttachment, 1, http.StatusBadRequest, "transaction attachment not found")
+	ErrNoTransactionAttachment                = NewNormalError(NormalSubcategoryAttachment, 2, http.StatusBadRequest, "no transaction attachment")
+	ErrTransactionAttachmentIsEmpty           = NewNormalError(NormalSubcategoryAttachment, 3, http.StatusBadRequest, "transaction attachment is empty")
+	ErrTransactionAttachmentNoExists          = NewNormalError(NormalSubcategoryAttachment, 4, http.StatusNotFound, "transaction attachment not exists")
+	ErrTransactionAttachmentExtensionInvalid  = NewNormalError(NormalSubcategoryAttachment, 5, http.StatusNotFound, "transaction attachment file extension invalid")
+	ErrExceedMaxTransactionAttachmentFileSize = NewNormalError(NormalSubcategoryAttachment, 6, http.StatusBadRequest, "exceed the maximum size of transaction attachment file")
+	ErrTransactionAttachmentTypeNotSupported  = NewNormalError(NormalSubcategoryAttachment, 7, http.StatusBadRequest, "transaction attachment type is not supported")
+	ErrTransactionAttachmentContentMismatch   = NewNormalError(NormalSubcategoryAttachment, 8, http.StatusBadRequest, "transaction attachment content does not match its file type")
+	ErrTransactionAttachmentThumbnailNoExists = NewNormalError(NormalSubcategoryAttachment, 9, http.StatusNotFound, "transaction attachment thumbnail not exists")
+	ErrTransactionHasTooManyAttachments       = NewNormalError(NormalSubcategoryAttachment, 10, http.StatusBadRequest, "transaction has too many attachments")
+	ErrTransactionAttachmentFileNameInvalid   = NewNormalError(NormalSubcategoryAttachment, 11, http.StatusBadRequest, "transaction attachment file name is invalid")
+)
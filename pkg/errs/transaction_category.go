@@ -15,4 +15,7 @@ var (
 	ErrNotAllowChangeSecondaryTransactionCategoryToPrimary = NewNormalError(NormalSubcategoryCategory, 8, http.StatusBadRequest, "not allow to change secondary category to primary category")
 	ErrNotAllowChangePrimaryTransactionType                = NewNormalError(NormalSubcategoryCategory, 9, http.StatusBadRequest, "not allow to change primary category with different type")
 	ErrNotAllowUseSecondaryTransactionAsPrimaryCategory    = NewNormalError(NormalSubcategoryCategory, 10, http.StatusBadRequest, "not allow to use secondary category as primary category")
+	ErrCannotMergeTransactionCategoryIntoItself            = NewNormalError(NormalSubcategoryCategory, 11, http.StatusBadRequest, "cannot merge transaction category into itself")
+	ErrCannotMergePrimaryAndSecondaryTransactionCategory   = NewNormalError(NormalSubcategoryCategory, 12, http.StatusBadRequest, "cannot merge primary category and secondary category")
+	ErrCannotMergeTransactionCategoriesWithDifferentTypes  = NewNormalError(NormalSubcategoryCategory, 13, http.StatusBadRequest, "cannot merge transaction categories with different types")
 )
@@ -0,0 +1,17 @@
+package errs
+
+import "net/http"
+
+// Error codes related to transaction custom fields
+var (
+	ErrTransactionCustomFieldIdInvalid         = NewNormalError(NormalSubcategoryCustomField, 0, http.StatusBadRequest, "transaction custom field id is invalid")
+	ErrTransactionCustomFieldNotFound          = NewNormalError(NormalSubcategoryCustomField, 1, http.StatusBadRequest, "transaction custom field not found")
+	ErrTransactionCustomFieldNameIsEmpty       = NewNormalError(NormalSubcategoryCustomField, 2, http.StatusBadRequest, "transaction custom field name is empty")
+	ErrTransactionCustomFieldNameAlreadyExists = NewNormalError(NormalSubcategoryCustomField, 3, http.StatusBadRequest, "transaction custom field name already exists")
+	ErrTransactionCustomFieldTypeInvalid       = NewNormalError(NormalSubcategoryCustomField, 4, http.StatusBadRequest, "transaction custom field type is invalid")
+	ErrTransactionCustomFieldOptionsInvalid    = NewNormalError(NormalSubcategoryCustomField, 5, http.StatusBadRequest, "transaction custom field options are invalid")
+	ErrTransactionCustomFieldValueInvalid      = NewNormalError(NormalSubcategoryCustomField, 6, http.StatusBadRequest, "transaction custom field value is invalid")
+	ErrTooManyTransactionCustomFields          = NewNormalError(NormalSubcategoryCustomField, 7, http.StatusBadRequest, "there are too many transaction custom fields")
+	ErrTransactionCustomFieldFilterInvalid     = NewNormalError(NormalSubcategoryCustomField, 8, http.StatusBadRequest, "transaction custom field filter is invalid")
+	ErrTransactionCustomFieldInDifferentLedger = NewNormalError(NormalSubcategoryCustomField, 9, http.StatusBadRequest, "transaction custom field is in different ledger")
+)
@@ -0,0 +1,11 @@
+package errs
+
+import "net/http"
+
+// Error codes related to transaction tag groups
+var (
+	ErrTransactionTagGroupIdInvalid         = NewNormalError(NormalSubcategoryTagGroup, 0, http.StatusBadRequest, "transaction tag group id is invalid")
+	ErrTransactionTagGroupNotFound          = NewNormalError(NormalSubcategoryTagGroup, 1, http.StatusBadRequest, "transaction tag group not found")
+	ErrTransactionTagGroupNameIsEmpty       = NewNormalError(NormalSubcategoryTagGroup, 2, http.StatusBadRequest, "transaction tag group name is empty")
+	ErrTransactionTagGroupNameAlreadyExists = NewNormalError(NormalSubcategoryTagGroup, 3, http.StatusBadRequest, "transaction tag group name already exists")
+)
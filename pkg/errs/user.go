@@ -41,4 +41,7 @@ var (
 	ErrCannotLoginByPassword                               = NewNormalError(NormalSubcategoryUser, 32, http.StatusBadRequest, "cannot login by password")
 	ErrUserNameIsInvalid                                   = NewNormalError(NormalSubcategoryUser, 33, http.StatusBadRequest, "user name is invalid")
 	ErrNickNameIsInvalid                                   = NewNormalError(NormalSubcategoryUser, 34, http.StatusBadRequest, "nick name is invalid")
+	ErrUserStorageQuotaExceeded                            = NewNormalError(NormalSubcategoryUser, 35, http.StatusBadRequest, "user storage quota exceeded")
+	ErrUserTransactionQuotaExceeded                        = NewNormalError(NormalSubcategoryUser, 36, http.StatusBadRequest, "user transaction quota exceeded")
+	ErrUserQuotaInvalid                                    = NewNormalError(NormalSubcategoryUser, 37, http.StatusBadRequest, "user quota is invalid")
 )
@@ -6,11 +6,12 @@ import (
 
 // LocaleTextItems represents all text items need to be translated
 type LocaleTextItems struct {
-	GlobalTextItems             *GlobalTextItems
-	DefaultTypes                *DefaultTypes
-	DataConverterTextItems      *DataConverterTextItems
-	VerifyEmailTextItems        *VerifyEmailTextItems
-	ForgetPasswordMailTextItems *ForgetPasswordMailTextItems
+	GlobalTextItems               *GlobalTextItems
+	DefaultTypes                  *DefaultTypes
+	DataConverterTextItems        *DataConverterTextItems
+	VerifyEmailTextItems          *VerifyEmailTextItems
+	ForgetPasswordMailTextItems   *ForgetPasswordMailTextItems
+	LedgerInvitationMailTextItems *LedgerInvitationMailTextItems
 }
 
 // GlobalTextItems represents global text items need to be translated
@@ -47,3 +48,12 @@ type ForgetPasswordMailTextItems struct {
 	ResetPassword             string
 	DescriptionBelowBtnFormat string
 }
+
+// LedgerInvitationMailTextItems represents text items need to be translated in ledger invitation mail
+type LedgerInvitationMailTextItems struct {
+	Title               string
+	SalutationFormat    string
+	DescriptionFormat   string
+	ViewInvitation      string
+	DescriptionBelowBtn string
+}
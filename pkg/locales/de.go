@@ -30,4 +30,11 @@ var de = &LocaleTextItems{
 		ResetPassword:             "Passwort zurücksetzen",
 		DescriptionBelowBtnFormat: "Wenn Sie nicht angefordert haben, Ihr Passwort zurückzusetzen, ignorieren Sie bitte diese E-Mail. Wenn Sie den obigen Link nicht anklicken können, kopieren Sie bitte die obige URL und fügen Sie sie in Ihren Browser ein. Der Link zum Zurücksetzen des Passworts wird nach %v Minuten ablaufen.",
 	},
+	LedgerInvitationMailTextItems: &LedgerInvitationMailTextItems{
+		Title:               "Einladung zum Kassenbuch",
+		SalutationFormat:    "Hallo %s,",
+		DescriptionFormat:   "%s hat Sie eingeladen, dem geteilten Kassenbuch \"%s\" beizutreten. Sie können auf den untenstehenden Link klicken, um die Einladung anzusehen und anzunehmen.",
+		ViewInvitation:      "Einladung ansehen",
+		DescriptionBelowBtn: "Wenn Sie diesem Kassenbuch nicht beitreten möchten, ignorieren Sie bitte diese E-Mail oder lehnen Sie die Einladung ab. Wenn Sie den obigen Link nicht anklicken können, kopieren Sie bitte die obige URL und fügen Sie sie in Ihren Browser ein.",
+	},
 }
@@ -30,4 +30,11 @@ var en = &LocaleTextItems{
 		ResetPassword:             "Reset Password",
 		DescriptionBelowBtnFormat: "If you did not request to reset your password, please simply disregard this email. If you cannot click the link above, please copy the above url and paste it into your browser. The password reset link will be expired after %v minutes.",
 	},
+	LedgerInvitationMailTextItems: &LedgerInvitationMailTextItems{
+		Title:               "Ledger Invitation",
+		SalutationFormat:    "Hi %s,",
+		DescriptionFormat:   "%s has invited you to join the shared ledger \"%s\". You can click the link below to view and accept the invitation.",
+		ViewInvitation:      "View Invitation",
+		DescriptionBelowBtn: "If you do not want to join this ledger, please simply disregard this email or decline the invitation. If you cannot click the link above, please copy the above url and paste it into your browser.",
+	},
 }
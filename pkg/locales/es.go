@@ -30,4 +30,11 @@ var es = &LocaleTextItems{
 		ResetPassword:             "Restablecer Contraseña",
 		DescriptionBelowBtnFormat: "Si no solicitó un restablecimiento de contraseña, simplemente descarte este correo. Si no puede hacer click en el link anterior, copie la url arriba mostrada y péguela en su navegadror. El enlace de restablecimiento de contraseña expira pasados %v minutos.",
 	},
+	LedgerInvitationMailTextItems: &LedgerInvitationMailTextItems{
+		Title:               "Invitación al libro contable",
+		SalutationFormat:    "Hola %s,",
+		DescriptionFormat:   "%s te ha invitado a unirte al libro contable compartido \"%s\". Puedes hacer clic en el enlace de abajo para ver y aceptar la invitación.",
+		ViewInvitation:      "Ver invitación",
+		DescriptionBelowBtn: "Si no deseas unirte a este libro contable, simplemente ignora este correo o rechaza la invitación. Si no puedes hacer clic en el enlace de arriba, copia la URL anterior y pégala en tu navegador.",
+	},
 }
@@ -30,4 +30,11 @@ var fr = &LocaleTextItems{
 		ResetPassword:             "Réinitialiser le mot de passe",
 		DescriptionBelowBtnFormat: "Si vous n'avez pas demandé la réinitialisation de votre mot de passe, vous pouvez ignorer cet e-mail. Si vous ne pouvez pas cliquer sur le lien ci-dessus, copiez l'URL ci-dessus et collez-la dans votre navigateur. Le lien de réinitialisation du mot de passe expire après %v minutes.",
 	},
+	LedgerInvitationMailTextItems: &LedgerInvitationMailTextItems{
+		Title:               "Invitation au registre",
+		SalutationFormat:    "Bonjour %s,",
+		DescriptionFormat:   "%s vous a invité à rejoindre le registre partagé \"%s\". Vous pouvez cliquer sur le lien ci-dessous pour consulter et accepter l'invitation.",
+		ViewInvitation:      "Voir l'invitation",
+		DescriptionBelowBtn: "Si vous ne souhaitez pas rejoindre ce registre, veuillez simplement ignorer cet e-mail ou refuser l'invitation. Si vous ne pouvez pas cliquer sur le lien ci-dessus, veuillez copier l'URL ci-dessus et la coller dans votre navigateur.",
+	},
 }
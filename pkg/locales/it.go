@@ -30,4 +30,11 @@ var it = &LocaleTextItems{
 		ResetPassword:             "Reimposta password",
 		DescriptionBelowBtnFormat: "Se non hai chiesto alcun cambio della password, puoi ignorare questa mail. Se non riesci a cliccare il link, copia l'indirizzo URL qui sopra e incollalo nel tuo browser preferito. Il link di verifica scadrà tra %v minuti.",
 	},
+	LedgerInvitationMailTextItems: &LedgerInvitationMailTextItems{
+		Title:               "Invito al registro",
+		SalutationFormat:    "Ciao %s,",
+		DescriptionFormat:   "%s ti ha invitato a unirti al registro condiviso \"%s\". Puoi cliccare sul link qui sotto per visualizzare e accettare l'invito.",
+		ViewInvitation:      "Visualizza invito",
+		DescriptionBelowBtn: "Se non desideri unirti a questo registro, ignora semplicemente questa email o rifiuta l'invito. Se non riesci a cliccare sul link sopra, copia l'URL sopra e incollalo nel tuo browser.",
+	},
 }
@@ -30,4 +30,11 @@ var ja = &LocaleTextItems{
 		ResetPassword:             "パスワードをリセット",
 		DescriptionBelowBtnFormat: "パスワードのリセットをリクエストしていない場合はこのメールを無視してください。上記のリンクをクリックできない場合は、上記のURLをコピーしてブラウザに貼り付けてください。パスワードリセットのリンクは%v分後に期限切れになります。",
 	},
+	LedgerInvitationMailTextItems: &LedgerInvitationMailTextItems{
+		Title:               "帳簿への招待",
+		SalutationFormat:    "こんにちは%s,",
+		DescriptionFormat:   "%s さんから共有帳簿「%s」への招待が届いています。下のリンクをクリックして招待を確認し、承諾できます。",
+		ViewInvitation:      "招待を表示",
+		DescriptionBelowBtn: "この帳簿に参加しない場合は、このメールを無視するか招待を辞退してください。上のリンクをクリックできない場合は、上記のURLをコピーしてブラウザに貼り付けてください。",
+	},
 }
@@ -30,4 +30,11 @@ var kn = &LocaleTextItems{
 		ResetPassword:             "Reset Password",
 		DescriptionBelowBtnFormat: "If you did not request to reset your password, please simply disregard this email. If you cannot click the link above, please copy the above url and paste it into your browser. The password reset link will be expired after %v minutes.",
 	},
+	LedgerInvitationMailTextItems: &LedgerInvitationMailTextItems{
+		Title:               "ಲೆಡ್ಜರ್ ಆಹ್ವಾನ",
+		SalutationFormat:    "ಹಲೋ %s,",
+		DescriptionFormat:   "%s ಅವರು ನಿಮ್ಮನ್ನು ಹಂಚಿದ ಲೆಡ್ಜರ್ \"%s\" ಗೆ ಸೇರಲು ಆಹ್ವಾನಿಸಿದ್ದಾರೆ. ಆಹ್ವಾನವನ್ನು ನೋಡಲು ಮತ್ತು ಸ್ವೀಕರಿಸಲು ಕೆಳಗಿನ ಲಿಂಕ್ ಅನ್ನು ಕ್ಲಿಕ್ ಮಾಡಿ.",
+		ViewInvitation:      "ಆಹ್ವಾನವನ್ನು ನೋಡಿ",
+		DescriptionBelowBtn: "ನೀವು ಈ ಲೆಡ್ಜರ್‌ಗೆ ಸೇರಲು ಬಯಸದಿದ್ದರೆ, ಈ ಇಮೇಲ್ ಅನ್ನು ನಿರ್ಲಕ್ಷಿಸಿ ಅಥವಾ ಆಹ್ವಾನವನ್ನು ತಿರಸ್ಕರಿಸಿ. ಮೇಲಿನ ಲಿಂಕ್ ಅನ್ನು ಕ್ಲಿಕ್ ಮಾಡಲು ಸಾಧ್ಯವಾಗದಿದ್ದರೆ, ಮೇಲಿನ URL ಅನ್ನು ನಕಲಿಸಿ ನಿಮ್ಮ ಬ್ರೌಸರ್‌ನಲ್ಲಿ ಅಂಟಿಸಿ.",
+	},
 }
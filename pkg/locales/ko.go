@@ -30,4 +30,11 @@ var ko = &LocaleTextItems{
 		ResetPassword:             "비밀번호 재설정",
 		DescriptionBelowBtnFormat: "비밀번호 재설정을 요청하지 않으셨다면 이 이메일을 무시해주세요. 위 링크를 클릭할 수 없는 경우, 위 URL을 복사하여 브라우저에 붙여넣어 주세요. 비밀번호 재설정 링크는 %v분 후에 만료됩니다.",
 	},
+	LedgerInvitationMailTextItems: &LedgerInvitationMailTextItems{
+		Title:               "장부 초대",
+		SalutationFormat:    "안녕하세요 %s님,",
+		DescriptionFormat:   "%s 님이 공유 장부 \"%s\"에 참여하도록 초대했습니다. 아래 링크를 클릭하여 초대를 확인하고 수락할 수 있습니다.",
+		ViewInvitation:      "초대 보기",
+		DescriptionBelowBtn: "이 장부에 참여하지 않으려면 이 이메일을 무시하거나 초대를 거절하세요. 위 링크를 클릭할 수 없는 경우 위 URL을 복사하여 브라우저에 붙여 넣으세요.",
+	},
 }
@@ -30,4 +30,11 @@ var nl = &LocaleTextItems{
 		ResetPassword:             "Wachtwoord opnieuw instellen",
 		DescriptionBelowBtnFormat: "Als je geen verzoek hebt gedaan om je wachtwoord te resetten, kun je deze e-mail negeren. Als je niet op de bovenstaande link kunt klikken, kopieer dan de URL hierboven en plak deze in je browser. De link voor het opnieuw instellen van het wachtwoord verloopt na  %v minuten.",
 	},
+	LedgerInvitationMailTextItems: &LedgerInvitationMailTextItems{
+		Title:               "Uitnodiging voor grootboek",
+		SalutationFormat:    "Hallo %s,",
+		DescriptionFormat:   "%s heeft je uitgenodigd om lid te worden van het gedeelde grootboek \"%s\". Klik op de onderstaande link om de uitnodiging te bekijken en te accepteren.",
+		ViewInvitation:      "Uitnodiging bekijken",
+		DescriptionBelowBtn: "Als je niet wilt deelnemen aan dit grootboek, negeer deze e-mail dan of wijs de uitnodiging af. Als je niet op de bovenstaande link kunt klikken, kopieer dan de bovenstaande URL en plak deze in je browser.",
+	},
 }
@@ -30,4 +30,11 @@ var ptBR = &LocaleTextItems{
 		ResetPassword:             "Redefinir Senha",
 		DescriptionBelowBtnFormat: "Se você não solicitou a redefinição de senha, basta ignorar este e-mail. Se não conseguir clicar no link acima, copie a URL acima e cole no seu navegador. O link de redefinição de senha expirará após %v minutos.",
 	},
+	LedgerInvitationMailTextItems: &LedgerInvitationMailTextItems{
+		Title:               "Convite para livro-caixa",
+		SalutationFormat:    "Olá %s,",
+		DescriptionFormat:   "%s convidou você para participar do livro-caixa compartilhado \"%s\". Você pode clicar no link abaixo para ver e aceitar o convite.",
+		ViewInvitation:      "Ver convite",
+		DescriptionBelowBtn: "Se você não deseja participar deste livro-caixa, simplesmente ignore este e-mail ou recuse o convite. Se não conseguir clicar no link acima, copie a URL acima e cole-a no seu navegador.",
+	},
 }
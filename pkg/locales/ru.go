@@ -30,4 +30,11 @@ var ru = &LocaleTextItems{
 		ResetPassword:             "Сбросить пароль",
 		DescriptionBelowBtnFormat: "Если вы не запрашивали сброс пароля, просто проигнорируйте это письмо. Если вы не можете нажать на ссылку выше, скопируйте указанный выше URL и вставьте его в браузер. Ссылка для сброса пароля истечет через %v минут.",
 	},
+	LedgerInvitationMailTextItems: &LedgerInvitationMailTextItems{
+		Title:               "Приглашение в книгу учёта",
+		SalutationFormat:    "Здравствуйте %s,",
+		DescriptionFormat:   "%s приглашает вас присоединиться к общей книге учёта \"%s\". Нажмите на ссылку ниже, чтобы просмотреть и принять приглашение.",
+		ViewInvitation:      "Просмотреть приглашение",
+		DescriptionBelowBtn: "Если вы не хотите присоединяться к этой книге учёта, просто проигнорируйте это письмо или отклоните приглашение. Если вы не можете нажать на ссылку выше, скопируйте указанный выше адрес и вставьте его в браузер.",
+	},
 }
@@ -30,4 +30,11 @@ var th = &LocaleTextItems{
 		ResetPassword:             "ตั้งรหัสผ่านใหม่",
 		DescriptionBelowBtnFormat: "หากคุณไม่ได้ร้องขอให้รีเซ็ตรหัสผ่าน โปรดละเว้นอีเมลนี้ หากคุณไม่สามารถคลิกลิงก์ด้านบน โปรดคัดลอก URL ด้านบนและวางลงในเบราว์เซอร์ของคุณ ลิงก์รีเซ็ตรหัสผ่านจะหมดอายุหลังจาก %v นาที",
 	},
+	LedgerInvitationMailTextItems: &LedgerInvitationMailTextItems{
+		Title:               "คำเชิญเข้าร่วมสมุดบัญชี",
+		SalutationFormat:    "สวัสดี %s,",
+		DescriptionFormat:   "%s ได้เชิญคุณเข้าร่วมสมุดบัญชีที่แชร์ \"%s\" คุณสามารถคลิกลิงก์ด้านล่างเพื่อดูและยอมรับคำเชิญ",
+		ViewInvitation:      "ดูคำเชิญ",
+		DescriptionBelowBtn: "หากคุณไม่ต้องการเข้าร่วมสมุดบัญชีนี้ โปรดเพิกเฉยต่ออีเมลนี้หรือปฏิเสธคำเชิญ หากคุณไม่สามารถคลิกลิงก์ด้านบนได้ โปรดคัดลอก URL ด้านบนและวางลงในเบราว์เซอร์ของคุณ",
+	},
 }
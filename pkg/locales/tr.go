@@ -30,4 +30,11 @@ var tr = &LocaleTextItems{
 		ResetPassword:             "Şifreyi Sıfırla",
 		DescriptionBelowBtnFormat: "Eğer şifre sıfırlama talebinde bulunmadıysanız, lütfen bu e-postayı dikkate almayın. Eğer yukarıdaki bağlantıya tıklayamıyorsanız, lütfen adresi kopyalayıp tarayıcınıza yapıştırın. Şifre sıfırlama bağlantısının süresi %v dakika sonra dolacaktır.",
 	},
+	LedgerInvitationMailTextItems: &LedgerInvitationMailTextItems{
+		Title:               "Defter Daveti",
+		SalutationFormat:    "Merhaba %s,",
+		DescriptionFormat:   "%s sizi paylaşılan \"%s\" defterine katılmaya davet etti. Daveti görüntülemek ve kabul etmek için aşağıdaki bağlantıya tıklayabilirsiniz.",
+		ViewInvitation:      "Daveti Görüntüle",
+		DescriptionBelowBtn: "Bu deftere katılmak istemiyorsanız, lütfen bu e-postayı dikkate almayın veya daveti reddedin. Yukarıdaki bağlantıya tıklayamıyorsanız, lütfen yukarıdaki URL'yi kopyalayıp tarayıcınıza yapıştırın.",
+	},
 }
@@ -30,4 +30,11 @@ var uk = &LocaleTextItems{
 		ResetPassword:             "Скинути пароль",
 		DescriptionBelowBtnFormat: "Якщо ви не надсилали запит на скидання пароля, просто проігноруйте цей лист. Якщо ви не можете натиснути на посилання вище, скопіюйте вказану URL-адресу та вставте її у свій браузер. Посилання для скидання пароля буде дійсне протягом %v хвилин.",
 	},
+	LedgerInvitationMailTextItems: &LedgerInvitationMailTextItems{
+		Title:               "Запрошення до книги обліку",
+		SalutationFormat:    "Вітаємо, %s!",
+		DescriptionFormat:   "%s запрошує вас приєднатися до спільної книги обліку \"%s\". Натисніть на посилання нижче, щоб переглянути та прийняти запрошення.",
+		ViewInvitation:      "Переглянути запрошення",
+		DescriptionBelowBtn: "Якщо ви не бажаєте приєднуватися до цієї книги обліку, просто проігноруйте цей лист або відхиліть запрошення. Якщо ви не можете натиснути на посилання вище, скопіюйте вказану вище адресу та вставте її у браузер.",
+	},
 }
@@ -30,4 +30,11 @@ var vi = &LocaleTextItems{
 		ResetPassword:             "Đặt lại Mật khẩu",
 		DescriptionBelowBtnFormat: "Nếu bạn không yêu cầu đặt lại mật khẩu, vui lòng bỏ qua email này. Nếu bạn không thể nhấp vào liên kết trên, hãy sao chép và dán liên kết vào trình duyệt của bạn. Liên kết đặt lại mật khẩu sẽ hết hạn sau %v phút.",
 	},
+	LedgerInvitationMailTextItems: &LedgerInvitationMailTextItems{
+		Title:               "Lời mời tham gia sổ sách",
+		SalutationFormat:    "Chào %s,",
+		DescriptionFormat:   "%s đã mời bạn tham gia sổ sách dùng chung \"%s\". Bạn có thể nhấp vào liên kết bên dưới để xem và chấp nhận lời mời.",
+		ViewInvitation:      "Xem lời mời",
+		DescriptionBelowBtn: "Nếu bạn không muốn tham gia sổ sách này, vui lòng bỏ qua email này hoặc từ chối lời mời. Nếu bạn không thể nhấp vào liên kết ở trên, vui lòng sao chép URL ở trên và dán vào trình duyệt của bạn.",
+	},
 }
@@ -30,4 +30,11 @@ var zhHans = &LocaleTextItems{
 		ResetPassword:             "重置密码",
 		DescriptionBelowBtnFormat: "如果您没有请求重置密码，请直接忽略本邮件。如果您无法点击上述链接，请复制下方的地址然后在您的浏览器中粘贴。重置密码链接将在 %v 分钟后过期。",
 	},
+	LedgerInvitationMailTextItems: &LedgerInvitationMailTextItems{
+		Title:               "账本邀请",
+		SalutationFormat:    "%s 您好，",
+		DescriptionFormat:   "%s 邀请您加入共享账本“%s”。您可以点击下方链接查看并接受邀请。",
+		ViewInvitation:      "查看邀请",
+		DescriptionBelowBtn: "如果您不想加入该账本，请直接忽略本邮件或拒绝邀请。如果您无法点击上述链接，请复制下方的地址然后在您的浏览器中粘贴。",
+	},
 }
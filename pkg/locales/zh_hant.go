@@ -30,4 +30,11 @@ var zhHant = &LocaleTextItems{
 		ResetPassword:             "重設密碼",
 		DescriptionBelowBtnFormat: "如果您沒有請求重設密碼，請直接忽略本郵件。如果您無法點擊上述連結，請複製下方的地址然後在您的瀏覽器中貼上。重設密碼連結將在 %v 分鐘後過期。",
 	},
+	LedgerInvitationMailTextItems: &LedgerInvitationMailTextItems{
+		Title:               "帳本邀請",
+		SalutationFormat:    "%s 您好，",
+		DescriptionFormat:   "%s 邀請您加入共享帳本「%s」。您可以點擊下方連結查看並接受邀請。",
+		ViewInvitation:      "查看邀請",
+		DescriptionBelowBtn: "如果您不想加入該帳本，請直接忽略本郵件或拒絕邀請。如果您無法點擊上述連結，請複製下方的網址然後在您的瀏覽器中貼上。",
+	},
 }
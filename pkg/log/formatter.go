@@ -7,10 +7,10 @@ import (
 	"time"
 
 	"github.com/sirupsen/logrus"
-
-	"github.com/mayswind/ezbookkeeping/pkg/utils"
 )
 
+const logTimeFormat = "2006-01-02 15:04:05"
+
 // LogFormatter represents a log formatter
 type LogFormatter struct {
 	Prefix       string
@@ -27,7 +27,7 @@ func (f *LogFormatter) Format(entry *logrus.Entry) ([]byte, error) {
 		b = &bytes.Buffer{}
 	}
 
-	b.WriteString(utils.FormatUnixTimeToLongDateTimeInServerTimezone(time.Now().Unix()))
+	b.WriteString(time.Now().Format(logTimeFormat))
 	b.WriteString(" ")
 
 	if f.Prefix != "" {
@@ -86,7 +86,7 @@ func (h *mcpAddTransactionToolHandler) Handle(c *core.WebContext, callToolReq *M
 	}
 
 	uid := user.Uid
-	allAccounts, err := services.GetAccountService().GetAllAccountsByUid(c, uid)
+	allAccounts, err := services.GetAccountService().GetAllAccountsByUid(c, services.GetLedgerAccessService().GetAllLedgersAccessOfOwner(uid))
 
 	if err != nil {
 		log.Warnf(c, "[add_transaction.Handle] get account error, because %s", err.Error())
@@ -115,7 +115,7 @@ func (h *mcpAddTransactionToolHandler) Handle(c *core.WebContext, callToolReq *M
 		destinationAccountId = destinationAccount.AccountId
 	}
 
-	allCategories, err := services.GetTransactionCategoryService().GetAllCategoriesByUid(c, uid, 0, -1)
+	allCategories, err := services.GetTransactionCategoryService().GetAllCategoriesByUid(c, services.GetLedgerAccessService().GetAllLedgersAccessOfOwner(uid), 0, -1)
 
 	if err != nil {
 		log.Warnf(c, "[add_transaction.Handle] get transaction category error, because %s", err.Error())
@@ -153,7 +153,7 @@ func (h *mcpAddTransactionToolHandler) Handle(c *core.WebContext, callToolReq *M
 	var tagIds []int64
 
 	if len(addTransactionRequest.Tags) > 0 {
-		allTags, err := services.GetTransactionTagService().GetAllTagsByUid(c, uid)
+		allTags, err := services.GetTransactionTagService().GetAllTagsByUid(c, services.GetLedgerAccessService().GetAllLedgersAccessOfOwner(uid))
 
 		if err != nil {
 			log.Warnf(c, "[add_transaction.Handle] get transaction tag ids error, because %s", err.Error())
@@ -185,7 +185,7 @@ func (h *mcpAddTransactionToolHandler) Handle(c *core.WebContext, callToolReq *M
 	}
 
 	if !addTransactionRequest.DryRun {
-		err = services.GetTransactionService().CreateTransaction(c, transaction, tagIds, nil)
+		err = services.GetTransactionService().CreateTransaction(c, transaction, tagIds, nil, nil, nil)
 
 		if err != nil {
 			log.Errorf(c, "[add_transaction.Handle] failed to create transaction \"id:%d\" for user \"uid:%d\", because %s", transaction.TransactionId, uid, err.Error())
@@ -15,6 +15,7 @@ type MCPAvailableServices interface {
 	GetTransactionCategoryService() *services.TransactionCategoryService
 	GetTransactionTagService() *services.TransactionTagService
 	GetAccountService() *services.AccountService
+	GetLedgerAccessService() *services.LedgerAccessService
 	GetUserService() *services.UserService
 }
 
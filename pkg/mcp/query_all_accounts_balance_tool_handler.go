@@ -60,7 +60,7 @@ func (h *mcpQueryAllAccountsBalanceToolHandler) OutputType() reflect.Type {
 // Handle processes the MCP call tool request and returns the response
 func (h *mcpQueryAllAccountsBalanceToolHandler) Handle(c *core.WebContext, callToolReq *MCPCallToolRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, []*MCPTextContent, error) {
 	uid := user.Uid
-	accounts, err := services.GetAccountService().GetAllAccountsByUid(c, uid)
+	accounts, err := services.GetAccountService().GetAllAccountsByUid(c, services.GetLedgerAccessService().GetAllLedgersAccessOfOwner(uid))
 
 	if err != nil {
 		log.Errorf(c, "[query_all_accounts_balance_tool_handler.Handle] failed to get all accounts for user \"uid:%d\", because %s", uid, err.Error())
@@ -42,7 +42,7 @@ func (h *mcpQueryAllTransactionTagsToolHandler) OutputType() reflect.Type {
 // Handle processes the MCP call tool request and returns the response
 func (h *mcpQueryAllTransactionTagsToolHandler) Handle(c *core.WebContext, callToolReq *MCPCallToolRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, []*MCPTextContent, error) {
 	uid := user.Uid
-	tags, err := services.GetTransactionTagService().GetAllTagsByUid(c, uid)
+	tags, err := services.GetTransactionTagService().GetAllTagsByUid(c, services.GetLedgerAccessService().GetAllLedgersAccessOfOwner(uid))
 
 	if err != nil {
 		log.Errorf(c, "[query_all_transaction_tags.Handle] failed to get tags for user \"uid:%d\", because %s", uid, err.Error())
@@ -119,7 +119,7 @@ func (h *mcpQueryTransactionsToolHandler) Handle(c *core.WebContext, callToolReq
 		transactionType = models.TRANSACTION_TYPE_TRANSFER
 	}
 
-	allAccounts, err := services.GetAccountService().GetAllAccountsByUid(c, uid)
+	allAccounts, err := services.GetAccountService().GetAllAccountsByUid(c, services.GetLedgerAccessService().GetAllLedgersAccessOfOwner(uid))
 
 	if err != nil {
 		log.Warnf(c, "[add_transaction.Handle] get account error, because %s", err.Error())
@@ -136,7 +136,7 @@ func (h *mcpQueryTransactionsToolHandler) Handle(c *core.WebContext, callToolReq
 		}
 	}
 
-	allCategories, err := services.GetTransactionCategoryService().GetAllCategoriesByUid(c, uid, 0, -1)
+	allCategories, err := services.GetTransactionCategoryService().GetAllCategoriesByUid(c, services.GetLedgerAccessService().GetAllLedgersAccessOfOwner(uid), 0, -1)
 
 	if err != nil {
 		log.Warnf(c, "[add_transaction.Handle] get transaction category error, because %s", err.Error())
@@ -153,14 +153,14 @@ func (h *mcpQueryTransactionsToolHandler) Handle(c *core.WebContext, callToolReq
 		}
 	}
 
-	totalCount, err := services.GetTransactionService().GetTransactionCount(c, uid, maxTransactionTime, minTransactionTime, transactionType, filterCategoryIds, filterAccountIds, nil, false, "", queryTransactionsRequest.Keyword)
+	totalCount, err := services.GetTransactionService().GetTransactionCount(c, services.GetLedgerAccessService().GetAllLedgersAccessOfOwner(uid), maxTransactionTime, minTransactionTime, transactionType, filterCategoryIds, filterAccountIds, nil, false, nil, "", queryTransactionsRequest.Keyword)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionListHandler] failed to get transaction count for user \"uid:%d\", because %s", uid, err.Error())
 		return nil, nil, err
 	}
 
-	transactions, err := services.GetTransactionService().GetTransactionsByMaxTime(c, uid, maxTransactionTime, minTransactionTime, transactionType, filterCategoryIds, filterAccountIds, nil, false, "", queryTransactionsRequest.Keyword, queryTransactionsRequest.Page, queryTransactionsRequest.Count, false, true)
+	transactions, err := services.GetTransactionService().GetTransactionsByMaxTime(c, services.GetLedgerAccessService().GetAllLedgersAccessOfOwner(uid), maxTransactionTime, minTransactionTime, transactionType, filterCategoryIds, filterAccountIds, nil, false, nil, "", queryTransactionsRequest.Keyword, queryTransactionsRequest.Page, queryTransactionsRequest.Count, false, true)
 	structuredResponse, response, err := h.createNewMCPQueryTransactionsResponse(c, &queryTransactionsRequest, transactions, totalCount, services.GetAccountService().GetAccountMapByList(allAccounts), services.GetTransactionCategoryService().GetCategoryMapByList(allCategories))
 
 	if err != nil {
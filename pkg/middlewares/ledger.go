@@ -0,0 +1,58 @@
+package middlewares
+
+import (
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// CurrentLedger resolves the ledger which current request works on and the role of current user in it, it uses the ledger specified in request header or query string (which can be owned by or shared to current user), or the default ledger of current user,
+// the default ledger is only read here, it is created when the user is created or the database is updated
+func CurrentLedger(c *core.WebContext) {
+	uid := c.GetCurrentUid()
+
+	if uid <= 0 {
+		c.Next()
+		return
+	}
+
+	ledgerId, err := c.GetRequestedLedgerId()
+
+	if err != nil {
+		log.Warnf(c, "[ledger.CurrentLedger] user \"uid:%d\" requested ledger id is invalid, because %s", uid, err.Error())
+		utils.PrintJsonErrorResult(c, errs.ErrLedgerIdInvalid)
+		return
+	}
+
+	if ledgerId > 0 {
+		access, err := services.LedgerAccesses.GetLedgerAccess(c, uid, ledgerId)
+
+		if err != nil {
+			log.Warnf(c, "[ledger.CurrentLedger] failed to get ledger \"id:%d\" for user \"uid:%d\", because %s", ledgerId, uid, err.Error())
+			utils.PrintJsonErrorResult(c, errs.Or(err, errs.ErrOperationFailed))
+			return
+		}
+
+		c.SetCurrentLedgerId(access.LedgerId)
+		c.SetCurrentLedgerOwnerUid(access.OwnerUid)
+		c.SetCurrentLedgerRole(byte(access.Role))
+		c.Next()
+		return
+	}
+
+	defaultLedger, err := services.Ledgers.GetDefaultLedger(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[ledger.CurrentLedger] failed to get default ledger for user \"uid:%d\", because %s", uid, err.Error())
+		utils.PrintJsonErrorResult(c, errs.Or(err, errs.ErrOperationFailed))
+		return
+	}
+
+	c.SetCurrentLedgerId(defaultLedger.LedgerId)
+	c.SetCurrentLedgerOwnerUid(uid)
+	c.SetCurrentLedgerRole(byte(models.LEDGER_MEMBER_ROLE_OWNER))
+	c.Next()
+}
@@ -0,0 +1,129 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/testutils"
+)
+
+func TestCurrentLedger_RequestedLedgerOwnedByCurrentUser(t *testing.T) {
+	testutils.InitializeTestDataStore(t)
+	testutils.InsertTestData(t, 1, &models.Ledger{LedgerId: 1001, Uid: 1, Name: "Personal", IsDefault: true})
+
+	c := createTestLedgerWebContext(1, "1001")
+	CurrentLedger(c)
+
+	assert.False(t, c.IsAborted())
+	assert.Equal(t, int64(1001), c.GetCurrentLedgerId())
+	assert.Equal(t, int64(1), c.GetCurrentLedgerOwnerUid())
+	assert.Equal(t, byte(models.LEDGER_MEMBER_ROLE_OWNER), c.GetCurrentLedgerRole())
+}
+
+func TestCurrentLedger_RequestedLedgerSharedToCurrentUser(t *testing.T) {
+	testutils.InitializeTestDataStore(t)
+	testutils.InsertTestData(t, 1, &models.Ledger{LedgerId: 1001, Uid: 1, Name: "Family", IsDefault: true})
+	testutils.InsertTestData(t, 1, &models.LedgerMember{MemberId: 2001, LedgerId: 1001, OwnerUid: 1, MemberUid: 2, Role: models.LEDGER_MEMBER_ROLE_VIEWER, Status: models.LEDGER_MEMBER_STATUS_ACCEPTED})
+
+	c := createTestLedgerWebContext(2, "1001")
+	CurrentLedger(c)
+
+	assert.False(t, c.IsAborted())
+	assert.Equal(t, int64(1001), c.GetCurrentLedgerId())
+	assert.Equal(t, int64(1), c.GetCurrentLedgerOwnerUid())
+	assert.Equal(t, byte(models.LEDGER_MEMBER_ROLE_VIEWER), c.GetCurrentLedgerRole())
+}
+
+func TestCurrentLedger_RequestedLedgerNotOwnedByCurrentUser(t *testing.T) {
+	testutils.InitializeTestDataStore(t)
+	testutils.InsertTestData(t, 1, &models.Ledger{LedgerId: 1001, Uid: 1, Name: "Personal", IsDefault: true})
+
+	c := createTestLedgerWebContext(2, "1001")
+	CurrentLedger(c)
+
+	assert.True(t, c.IsAborted())
+	assert.Equal(t, errs.ErrLedgerNotFound, c.GetResponseError())
+	assert.Equal(t, int64(0), c.GetCurrentLedgerId())
+}
+
+func TestCurrentLedger_RequestedLedgerInvitedButNotAccepted(t *testing.T) {
+	testutils.InitializeTestDataStore(t)
+	testutils.InsertTestData(t, 1, &models.Ledger{LedgerId: 1001, Uid: 1, Name: "Family", IsDefault: true})
+	testutils.InsertTestData(t, 1, &models.LedgerMember{MemberId: 2001, LedgerId: 1001, OwnerUid: 1, MemberUid: 2, Role: models.LEDGER_MEMBER_ROLE_EDITOR, Status: models.LEDGER_MEMBER_STATUS_INVITED})
+
+	c := createTestLedgerWebContext(2, "1001")
+	CurrentLedger(c)
+
+	assert.True(t, c.IsAborted())
+	assert.Equal(t, errs.ErrLedgerNotFound, c.GetResponseError())
+}
+
+func TestCurrentLedger_RequestedLedgerDeleted(t *testing.T) {
+	testutils.InitializeTestDataStore(t)
+	testutils.InsertTestData(t, 1, &models.Ledger{LedgerId: 1001, Uid: 1, Name: "Personal", Deleted: true})
+
+	c := createTestLedgerWebContext(1, "1001")
+	CurrentLedger(c)
+
+	assert.True(t, c.IsAborted())
+	assert.Equal(t, errs.ErrLedgerNotFound, c.GetResponseError())
+}
+
+func TestCurrentLedger_InvalidRequestedLedgerId(t *testing.T) {
+	testutils.InitializeTestDataStore(t)
+
+	c := createTestLedgerWebContext(1, "abc")
+	CurrentLedger(c)
+
+	assert.True(t, c.IsAborted())
+	assert.Equal(t, errs.ErrLedgerIdInvalid, c.GetResponseError())
+}
+
+func TestCurrentLedger_NoRequestedLedgerUseDefaultLedger(t *testing.T) {
+	testutils.InitializeTestDataStore(t)
+	testutils.InsertTestData(t, 1, &models.Ledger{LedgerId: 1001, Uid: 1, Name: "Personal", IsDefault: true})
+
+	c := createTestLedgerWebContext(1, "")
+	CurrentLedger(c)
+
+	assert.False(t, c.IsAborted())
+	assert.Equal(t, int64(1001), c.GetCurrentLedgerId())
+	assert.Equal(t, int64(1), c.GetCurrentLedgerOwnerUid())
+}
+
+func TestCurrentLedger_NoRequestedLedgerAndNoDefaultLedger(t *testing.T) {
+	testutils.InitializeTestDataStore(t)
+
+	c := createTestLedgerWebContext(1, "")
+	CurrentLedger(c)
+
+	assert.True(t, c.IsAborted())
+	assert.Equal(t, errs.ErrLedgerNotFound, c.GetResponseError())
+
+	_, err := services.Ledgers.GetDefaultLedger(core.NewNullContext(), 1)
+	assert.Equal(t, errs.ErrLedgerNotFound, err)
+}
+
+func createTestLedgerWebContext(uid int64, ledgerId string) *core.WebContext {
+	ginContext, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ginContext.Request = httptest.NewRequest(http.MethodGet, "/api/v1/accounts/list.json", nil)
+
+	if ledgerId != "" {
+		ginContext.Request.Header.Set(core.LedgerIdHeaderName, ledgerId)
+	}
+
+	c := core.WrapWebContext(ginContext)
+	c.SetTokenClaims(&core.UserTokenClaims{
+		Uid: uid,
+	})
+
+	return c
+}
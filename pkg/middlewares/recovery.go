@@ -3,6 +3,7 @@ package middlewares
 import (
 	"bytes"
 	"fmt"
+	"net/http"
 	"os"
 	"runtime"
 
@@ -23,6 +24,10 @@ var (
 func Recovery(c *core.WebContext) {
 	defer func() {
 		if err := recover(); err != nil {
+			if err == http.ErrAbortHandler { // let the http server close the connection silently
+				panic(err)
+			}
+
 			stack := stack(3)
 
 			log.ErrorfWithExtra(c, string(stack), "System Error! because %s", err)
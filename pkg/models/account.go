@@ -69,8 +69,9 @@ var defaultCreditCardAccountStatementDate = 0
 // Account represents account data stored in database
 type Account struct {
 	AccountId       int64           `xorm:"PK"`
-	Uid             int64           `xorm:"INDEX(IDX_account_uid_deleted_parent_account_id_order) NOT NULL"`
-	Deleted         bool            `xorm:"INDEX(IDX_account_uid_deleted_parent_account_id_order) NOT NULL"`
+	Uid             int64           `xorm:"INDEX(IDX_account_uid_deleted_parent_account_id_order) INDEX(IDX_account_uid_deleted_ledger_id) NOT NULL"`
+	Deleted         bool            `xorm:"INDEX(IDX_account_uid_deleted_parent_account_id_order) INDEX(IDX_account_uid_deleted_ledger_id) NOT NULL"`
+	LedgerId        int64           `xorm:"INDEX(IDX_account_uid_deleted_ledger_id) NOT NULL DEFAULT 0"`
 	Category        AccountCategory `xorm:"NOT NULL"`
 	Type            AccountType     `xorm:"NOT NULL"`
 	ParentAccountId int64           `xorm:"INDEX(IDX_account_uid_deleted_parent_account_id_order) NOT NULL"`
@@ -158,9 +159,16 @@ type AccountDeleteRequest struct {
 	Id int64 `json:"id,string" binding:"required,min=1"`
 }
 
+// AccountMergeRequest represents all parameters of account merging request
+type AccountMergeRequest struct {
+	FromAccountId int64 `json:"fromAccountId,string" binding:"required,min=1"`
+	ToAccountId   int64 `json:"toAccountId,string" binding:"required,min=1"`
+}
+
 // AccountInfoResponse represents a view-object of account
 type AccountInfoResponse struct {
 	Id                      int64                    `json:"id,string"`
+	LedgerId                int64                    `json:"ledgerId,string"`
 	Name                    string                   `json:"name"`
 	ParentId                int64                    `json:"parentId,string"`
 	Category                AccountCategory          `json:"category"`
@@ -192,6 +200,7 @@ func (a *Account) ToAccountInfoResponse() *AccountInfoResponse {
 
 	return &AccountInfoResponse{
 		Id:                      a.AccountId,
+		LedgerId:                a.LedgerId,
 		Name:                    a.Name,
 		ParentId:                a.ParentAccountId,
 		Category:                a.Category,
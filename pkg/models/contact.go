@@ -0,0 +1,252 @@
+package models
+
+import (
+	"sort"
+
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// ContactTransactionType represents the type of contact transaction
+type ContactTransactionType byte
+
+// Contact transaction types
+const (
+	CONTACT_TRANSACTION_TYPE_SPLIT  ContactTransactionType = 1
+	CONTACT_TRANSACTION_TYPE_SETTLE ContactTransactionType = 2
+)
+
+// SplitMethod represents the method of splitting an expense
+type SplitMethod byte
+
+// Split methods
+const (
+	SPLIT_METHOD_EQUAL  SplitMethod = 1
+	SPLIT_METHOD_SHARES SplitMethod = 2
+	SPLIT_METHOD_EXACT  SplitMethod = 3
+)
+
+// Contact represents contact data stored in database, a contact is a person who is not necessarily an ezbookkeeping user
+type Contact struct {
+	ContactId       int64  `xorm:"PK"`
+	Uid             int64  `xorm:"INDEX(IDX_contact_uid_deleted_ledger_id_order) NOT NULL"`
+	Deleted         bool   `xorm:"INDEX(IDX_contact_uid_deleted_ledger_id_order) NOT NULL"`
+	LedgerId        int64  `xorm:"INDEX(IDX_contact_uid_deleted_ledger_id_order) NOT NULL DEFAULT 0"`
+	Name            string `xorm:"VARCHAR(64) NOT NULL"`
+	DisplayOrder    int32  `xorm:"INDEX(IDX_contact_uid_deleted_ledger_id_order) NOT NULL"`
+	Hidden          bool   `xorm:"NOT NULL"`
+	Comment         string `xorm:"VARCHAR(255) NOT NULL"`
+	CreatedUnixTime int64
+	UpdatedUnixTime int64
+	DeletedUnixTime int64
+}
+
+// ContactTransaction represents a receivable or payable movement between user and contact stored in database,
+// positive amount means the contact owes the user, and negative amount means the user owes the contact
+type ContactTransaction struct {
+	TransactionId   int64                  `xorm:"PK"`
+	Uid             int64                  `xorm:"INDEX(IDX_contact_transaction_uid_deleted_contact_id_time) INDEX(IDX_contact_transaction_uid_deleted_ledger_id) NOT NULL"`
+	Deleted         bool                   `xorm:"INDEX(IDX_contact_transaction_uid_deleted_contact_id_time) INDEX(IDX_contact_transaction_uid_deleted_ledger_id) NOT NULL"`
+	ContactId       int64                  `xorm:"INDEX(IDX_contact_transaction_uid_deleted_contact_id_time) NOT NULL"`
+	TransactionTime int64                  `xorm:"INDEX(IDX_contact_transaction_uid_deleted_contact_id_time) NOT NULL"`
+	LedgerId        int64                  `xorm:"INDEX(IDX_contact_transaction_uid_deleted_ledger_id) NOT NULL DEFAULT 0"`
+	Type            ContactTransactionType `xorm:"TINYINT NOT NULL"`
+	AccountId       int64                  `xorm:"NOT NULL"`
+	Currency        string                 `xorm:"VARCHAR(3) NOT NULL"`
+	Amount          int64                  `xorm:"NOT NULL"`
+	CreatedUnixTime int64
+	UpdatedUnixTime int64
+	DeletedUnixTime int64
+}
+
+// ContactGetRequest represents all parameters of contact getting request
+type ContactGetRequest struct {
+	Id int64 `form:"id,string" binding:"required,min=1"`
+}
+
+// ContactCreateRequest represents all parameters of contact creation request
+type ContactCreateRequest struct {
+	Name    string `json:"name" binding:"required,notBlank,max=64"`
+	Comment string `json:"comment" binding:"max=255"`
+}
+
+// ContactModifyRequest represents all parameters of contact modification request
+type ContactModifyRequest struct {
+	Id      int64  `json:"id,string" binding:"required,min=1"`
+	Name    string `json:"name" binding:"required,notBlank,max=64"`
+	Comment string `json:"comment" binding:"max=255"`
+}
+
+// ContactHideRequest represents all parameters of contact hiding request
+type ContactHideRequest struct {
+	Id     int64 `json:"id,string" binding:"required,min=1"`
+	Hidden bool  `json:"hidden"`
+}
+
+// ContactDeleteRequest represents all parameters of contact deleting request
+type ContactDeleteRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// ContactSplitParticipantRequest represents a participant of splitting request, zero contact id means the current user
+type ContactSplitParticipantRequest struct {
+	ContactId int64 `json:"contactId,string" binding:"min=0"`
+	Shares    int64 `json:"shares" binding:"min=0,max=10000"`
+	Amount    int64 `json:"amount" binding:"min=0,max=99999999999"`
+}
+
+// ContactSplitRequest represents all parameters of expense splitting request
+type ContactSplitRequest struct {
+	TransferCategoryId  int64                             `json:"transferCategoryId,string" binding:"min=0"`
+	CategoryId          int64                             `json:"categoryId,string" binding:"required,min=1"`
+	Time                int64                             `json:"time" binding:"required,min=1"`
+	UtcOffset           int16                             `json:"utcOffset" binding:"min=-720,max=840"`
+	Amount              int64                             `json:"amount" binding:"required,min=1,max=99999999999"`
+	Method              SplitMethod                       `json:"method" binding:"required"`
+	PaidByContactId     int64                             `json:"paidByContactId,string" binding:"min=0"`
+	SourceAccountId     int64                             `json:"sourceAccountId,string" binding:"min=0"`
+	ReceivableAccountId int64                             `json:"receivableAccountId,string" binding:"min=0"`
+	DebtAccountId       int64                             `json:"debtAccountId,string" binding:"min=0"`
+	Participants        []*ContactSplitParticipantRequest `json:"participants" binding:"required,min=1"`
+	EventId             int64                             `json:"eventId,string" binding:"min=0"`
+	Comment             string                            `json:"comment" binding:"max=255"`
+}
+
+// ContactSettleRequest represents all parameters of settle-up request
+type ContactSettleRequest struct {
+	ContactId        int64  `json:"contactId,string" binding:"required,min=1"`
+	AccountId        int64  `json:"accountId,string" binding:"required,min=1"`
+	ContactAccountId int64  `json:"contactAccountId,string" binding:"required,min=1"`
+	CategoryId       int64  `json:"categoryId,string" binding:"required,min=1"`
+	Time             int64  `json:"time" binding:"required,min=1"`
+	UtcOffset        int16  `json:"utcOffset" binding:"min=-720,max=840"`
+	Amount           int64  `json:"amount" binding:"required,min=1,max=99999999999"`
+	Comment          string `json:"comment" binding:"max=255"`
+}
+
+// ContactStatementRequest represents all parameters of contact statement request
+type ContactStatementRequest struct {
+	Id int64 `form:"id,string" binding:"required,min=1"`
+}
+
+// ContactBalanceResponse represents a view-object of contact balance in one currency
+type ContactBalanceResponse struct {
+	Currency string `json:"currency"`
+	Balance  int64  `json:"balance"`
+}
+
+// ContactInfoResponse represents a view-object of contact
+type ContactInfoResponse struct {
+	Id           int64                       `json:"id,string"`
+	LedgerId     int64                       `json:"ledgerId,string"`
+	Name         string                      `json:"name"`
+	DisplayOrder int32                       `json:"displayOrder"`
+	Hidden       bool                        `json:"hidden"`
+	Comment      string                      `json:"comment"`
+	Balances     ContactBalanceResponseSlice `json:"balances"`
+}
+
+// ContactSplitResponse represents a view-object of expense splitting result
+type ContactSplitResponse struct {
+	TransactionIds []string `json:"transactionIds"`
+}
+
+// ContactStatementItemResponse represents a view-object of contact statement item
+type ContactStatementItemResponse struct {
+	TransactionId int64                  `json:"transactionId,string"`
+	Time          int64                  `json:"time"`
+	Type          ContactTransactionType `json:"type"`
+	AccountId     int64                  `json:"accountId,string"`
+	Currency      string                 `json:"currency"`
+	Amount        int64                  `json:"amount"`
+	Balance       int64                  `json:"balance"`
+}
+
+// ContactStatementResponse represents a view-object of contact statement
+type ContactStatementResponse struct {
+	Contact *ContactInfoResponse            `json:"contact"`
+	Items   []*ContactStatementItemResponse `json:"items"`
+}
+
+// ToContactInfoResponse returns a view-object according to database model
+func (c *Contact) ToContactInfoResponse(balances map[string]int64) *ContactInfoResponse {
+	balanceResps := make(ContactBalanceResponseSlice, 0, len(balances))
+
+	for currency, balance := range balances {
+		balanceResps = append(balanceResps, &ContactBalanceResponse{
+			Currency: currency,
+			Balance:  balance,
+		})
+	}
+
+	sort.Sort(balanceResps)
+
+	return &ContactInfoResponse{
+		Id:           c.ContactId,
+		LedgerId:     c.LedgerId,
+		Name:         c.Name,
+		DisplayOrder: c.DisplayOrder,
+		Hidden:       c.Hidden,
+		Comment:      c.Comment,
+		Balances:     balanceResps,
+	}
+}
+
+// ToContactStatementItemResponses returns view-objects with running balance of each currency,
+// the given contact transactions must be ordered by transaction time ascending
+func ToContactStatementItemResponses(contactTransactions []*ContactTransaction) []*ContactStatementItemResponse {
+	items := make([]*ContactStatementItemResponse, len(contactTransactions))
+	balances := make(map[string]int64)
+
+	for i := 0; i < len(contactTransactions); i++ {
+		contactTransaction := contactTransactions[i]
+		balances[contactTransaction.Currency] += contactTransaction.Amount
+
+		items[i] = &ContactStatementItemResponse{
+			TransactionId: contactTransaction.TransactionId,
+			Time:          utils.GetUnixTimeFromTransactionTime(contactTransaction.TransactionTime),
+			Type:          contactTransaction.Type,
+			AccountId:     contactTransaction.AccountId,
+			Currency:      contactTransaction.Currency,
+			Amount:        contactTransaction.Amount,
+			Balance:       balances[contactTransaction.Currency],
+		}
+	}
+
+	return items
+}
+
+// ContactBalanceResponseSlice represents the slice data structure of ContactBalanceResponse
+type ContactBalanceResponseSlice []*ContactBalanceResponse
+
+// Len returns the count of items
+func (s ContactBalanceResponseSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s ContactBalanceResponseSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s ContactBalanceResponseSlice) Less(i, j int) bool {
+	return s[i].Currency < s[j].Currency
+}
+
+// ContactInfoResponseSlice represents the slice data structure of ContactInfoResponse
+type ContactInfoResponseSlice []*ContactInfoResponse
+
+// Len returns the count of items
+func (s ContactInfoResponseSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s ContactInfoResponseSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s ContactInfoResponseSlice) Less(i, j int) bool {
+	return s[i].DisplayOrder < s[j].DisplayOrder
+}
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContactInfoResponseSliceLess(t *testing.T) {
+	var contactRespSlice ContactInfoResponseSlice
+	contactRespSlice = append(contactRespSlice, &ContactInfoResponse{
+		Id:           1,
+		DisplayOrder: 3,
+	})
+	contactRespSlice = append(contactRespSlice, &ContactInfoResponse{
+		Id:           2,
+		DisplayOrder: 1,
+	})
+	contactRespSlice = append(contactRespSlice, &ContactInfoResponse{
+		Id:           3,
+		DisplayOrder: 2,
+	})
+
+	sort.Sort(contactRespSlice)
+
+	assert.Equal(t, int64(2), contactRespSlice[0].Id)
+	assert.Equal(t, int64(3), contactRespSlice[1].Id)
+	assert.Equal(t, int64(1), contactRespSlice[2].Id)
+}
+
+func TestContactToContactInfoResponse_BalancesSortedByCurrency(t *testing.T) {
+	contact := &Contact{
+		ContactId: 1,
+		Name:      "Alice",
+	}
+
+	contactResp := contact.ToContactInfoResponse(map[string]int64{
+		"USD": 1500,
+		"EUR": -200,
+	})
+
+	assert.Equal(t, 2, len(contactResp.Balances))
+	assert.Equal(t, "EUR", contactResp.Balances[0].Currency)
+	assert.Equal(t, int64(-200), contactResp.Balances[0].Balance)
+	assert.Equal(t, "USD", contactResp.Balances[1].Currency)
+	assert.Equal(t, int64(1500), contactResp.Balances[1].Balance)
+}
+
+func TestToContactStatementItemResponses_RunningBalance(t *testing.T) {
+	contactTransactions := []*ContactTransaction{
+		{TransactionId: 1, Type: CONTACT_TRANSACTION_TYPE_SPLIT, Currency: "USD", Amount: 3000},
+		{TransactionId: 2, Type: CONTACT_TRANSACTION_TYPE_SPLIT, Currency: "EUR", Amount: -500},
+		{TransactionId: 3, Type: CONTACT_TRANSACTION_TYPE_SETTLE, Currency: "USD", Amount: -1000},
+	}
+
+	items := ToContactStatementItemResponses(contactTransactions)
+
+	assert.Equal(t, 3, len(items))
+	assert.Equal(t, int64(3000), items[0].Balance)
+	assert.Equal(t, int64(-500), items[1].Balance)
+	assert.Equal(t, int64(2000), items[2].Balance)
+	assert.Equal(t, CONTACT_TRANSACTION_TYPE_SETTLE, items[2].Type)
+}
@@ -13,13 +13,18 @@ type ClearAccountTransactionsRequest struct {
 
 // DataStatisticsResponse represents a view-object of user data statistic
 type DataStatisticsResponse struct {
-	TotalAccountCount              int64 `json:"totalAccountCount,string"`
-	TotalTransactionCategoryCount  int64 `json:"totalTransactionCategoryCount,string"`
-	TotalTransactionTagCount       int64 `json:"totalTransactionTagCount,string"`
-	TotalTransactionCount          int64 `json:"totalTransactionCount,string"`
-	TotalTransactionPictureCount   int64 `json:"totalTransactionPictureCount,string"`
-	TotalTransactionTemplateCount  int64 `json:"totalTransactionTemplateCount,string"`
-	TotalScheduledTransactionCount int64 `json:"totalScheduledTransactionCount,string"`
+	TotalAccountCount               int64 `json:"totalAccountCount,string"`
+	TotalTransactionCategoryCount   int64 `json:"totalTransactionCategoryCount,string"`
+	TotalTransactionTagCount        int64 `json:"totalTransactionTagCount,string"`
+	TotalTransactionCount           int64 `json:"totalTransactionCount,string"`
+	TotalTransactionPictureCount    int64 `json:"totalTransactionPictureCount,string"`
+	TotalTransactionAttachmentCount int64 `json:"totalTransactionAttachmentCount,string"`
+	TotalTransactionTemplateCount   int64 `json:"totalTransactionTemplateCount,string"`
+	TotalScheduledTransactionCount  int64 `json:"totalScheduledTransactionCount,string"`
+	StorageUsedBytes                int64 `json:"storageUsedBytes,string"`
+	StorageQuota                    int64 `json:"storageQuota,string"`
+	TransactionQuotaUsedCount       int64 `json:"transactionQuotaUsedCount,string"`
+	TransactionQuota                int64 `json:"transactionQuota,string"`
 }
 
 // ExportTransactionDataRequest represents export transaction request
@@ -32,4 +37,6 @@ type ExportTransactionDataRequest struct {
 	Keyword      string          `form:"keyword"`
 	MaxTime      int64           `form:"max_time" binding:"min=0"` // Unix timestamp in seconds
 	MinTime      int64           `form:"min_time" binding:"min=0"` // Unix timestamp in seconds
+	Columns      string          `form:"columns"`
+	Timezone     string          `form:"timezone"`
 }
@@ -0,0 +1,260 @@
+package models
+
+import (
+	"math"
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const earthRadiusInMeters = 6371000.0
+
+// Event represents trip, project or event data stored in database, transactions can be assigned to an event manually or automatically
+type Event struct {
+	EventId               int64   `xorm:"PK"`
+	Uid                   int64   `xorm:"INDEX(IDX_event_uid_deleted_ledger_id_start_time) NOT NULL"`
+	Deleted               bool    `xorm:"INDEX(IDX_event_uid_deleted_ledger_id_start_time) NOT NULL"`
+	LedgerId              int64   `xorm:"INDEX(IDX_event_uid_deleted_ledger_id_start_time) NOT NULL DEFAULT 0"`
+	Name                  string  `xorm:"VARCHAR(64) NOT NULL"`
+	StartTime             int64   `xorm:"INDEX(IDX_event_uid_deleted_ledger_id_start_time) NOT NULL"`
+	EndTime               int64   `xorm:"NOT NULL"`
+	BudgetCurrency        string  `xorm:"VARCHAR(3) NOT NULL"`
+	BudgetAmount          int64   `xorm:"NOT NULL"`
+	ParticipantContactIds string  `xorm:"VARCHAR(255) NOT NULL"`
+	AutoAssign            bool    `xorm:"NOT NULL"`
+	GeoLongitude          float64 `xorm:"NOT NULL DEFAULT 0"`
+	GeoLatitude           float64 `xorm:"NOT NULL DEFAULT 0"`
+	GeoRadius             int32   `xorm:"NOT NULL DEFAULT 0"`
+	Comment               string  `xorm:"VARCHAR(255) NOT NULL"`
+	DisplayOrder          int32   `xorm:"NOT NULL"`
+	Hidden                bool    `xorm:"NOT NULL"`
+	CreatedUnixTime       int64
+	UpdatedUnixTime       int64
+	DeletedUnixTime       int64
+}
+
+// EventGeoLocationRequest represents all parameters of event geographic area in request
+type EventGeoLocationRequest struct {
+	Latitude  float64 `json:"latitude" binding:"required,min=-90,max=90"`
+	Longitude float64 `json:"longitude" binding:"required,min=-180,max=180"`
+	Radius    int32   `json:"radius" binding:"required,min=1,max=20000000"`
+}
+
+// EventGetRequest represents all parameters of event getting request
+type EventGetRequest struct {
+	Id int64 `form:"id,string" binding:"required,min=1"`
+}
+
+// EventCreateRequest represents all parameters of event creation request
+type EventCreateRequest struct {
+	Name                  string                   `json:"name" binding:"required,notBlank,max=64"`
+	StartTime             int64                    `json:"startTime" binding:"required,min=1"`
+	EndTime               int64                    `json:"endTime" binding:"required,min=1"`
+	BudgetCurrency        string                   `json:"budgetCurrency" binding:"omitempty,len=3,validCurrency"`
+	BudgetAmount          int64                    `json:"budgetAmount" binding:"min=0,max=99999999999"`
+	ParticipantContactIds []string                 `json:"participantContactIds"`
+	AutoAssign            bool                     `json:"autoAssign"`
+	GeoLocation           *EventGeoLocationRequest `json:"geoLocation" binding:"omitempty"`
+	Comment               string                   `json:"comment" binding:"max=255"`
+}
+
+// EventModifyRequest represents all parameters of event modification request
+type EventModifyRequest struct {
+	Id                    int64                    `json:"id,string" binding:"required,min=1"`
+	Name                  string                   `json:"name" binding:"required,notBlank,max=64"`
+	StartTime             int64                    `json:"startTime" binding:"required,min=1"`
+	EndTime               int64                    `json:"endTime" binding:"required,min=1"`
+	BudgetCurrency        string                   `json:"budgetCurrency" binding:"omitempty,len=3,validCurrency"`
+	BudgetAmount          int64                    `json:"budgetAmount" binding:"min=0,max=99999999999"`
+	ParticipantContactIds []string                 `json:"participantContactIds"`
+	AutoAssign            bool                     `json:"autoAssign"`
+	GeoLocation           *EventGeoLocationRequest `json:"geoLocation" binding:"omitempty"`
+	Comment               string                   `json:"comment" binding:"max=255"`
+}
+
+// EventHideRequest represents all parameters of event hiding request
+type EventHideRequest struct {
+	Id     int64 `json:"id,string" binding:"required,min=1"`
+	Hidden bool  `json:"hidden"`
+}
+
+// EventDeleteRequest represents all parameters of event deleting request
+type EventDeleteRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// EventAssignTransactionsRequest represents all parameters of assigning transactions to event request, zero event id means removing transactions from their events
+type EventAssignTransactionsRequest struct {
+	Id             int64    `json:"id,string" binding:"min=0"`
+	TransactionIds []string `json:"transactionIds" binding:"required,min=1"`
+}
+
+// EventAutoAssignTransactionsRequest represents all parameters of assigning existed transactions to event by its date range and geographic area request
+type EventAutoAssignTransactionsRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// EventStatisticRequest represents all parameters of event statistic request
+type EventStatisticRequest struct {
+	Id int64 `form:"id,string" binding:"required,min=1"`
+}
+
+// EventGeoLocationResponse represents a view-object of event geographic area
+type EventGeoLocationResponse struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Radius    int32   `json:"radius"`
+}
+
+// EventInfoResponse represents a view-object of event
+type EventInfoResponse struct {
+	Id                    int64                     `json:"id,string"`
+	LedgerId              int64                     `json:"ledgerId,string"`
+	Name                  string                    `json:"name"`
+	StartTime             int64                     `json:"startTime"`
+	EndTime               int64                     `json:"endTime"`
+	BudgetCurrency        string                    `json:"budgetCurrency,omitempty"`
+	BudgetAmount          int64                     `json:"budgetAmount"`
+	ParticipantContactIds []string                  `json:"participantContactIds"`
+	AutoAssign            bool                      `json:"autoAssign"`
+	GeoLocation           *EventGeoLocationResponse `json:"geoLocation,omitempty"`
+	Comment               string                    `json:"comment"`
+	DisplayOrder          int32                     `json:"displayOrder"`
+	Hidden                bool                      `json:"hidden"`
+}
+
+// EventCurrencyStatisticResponse represents a view-object of event total amounts in one currency
+type EventCurrencyStatisticResponse struct {
+	Currency      string `json:"currency"`
+	ExpenseAmount int64  `json:"expenseAmount"`
+	IncomeAmount  int64  `json:"incomeAmount"`
+}
+
+// EventTotalStatisticResponse represents a view-object of event total amounts converted to the default currency of user
+type EventTotalStatisticResponse struct {
+	Currency              string   `json:"currency"`
+	ExpenseAmount         int64    `json:"expenseAmount"`
+	IncomeAmount          int64    `json:"incomeAmount"`
+	UnconvertedCurrencies []string `json:"unconvertedCurrencies,omitempty"`
+}
+
+// EventCategoryStatisticResponse represents a view-object of event total amount of one category in one currency
+type EventCategoryStatisticResponse struct {
+	CategoryId int64  `json:"categoryId,string"`
+	Currency   string `json:"currency"`
+	Amount     int64  `json:"amount"`
+}
+
+// EventParticipantStatisticResponse represents a view-object of event expense share of one participant in one currency, zero contact id means the current user
+type EventParticipantStatisticResponse struct {
+	ContactId int64  `json:"contactId,string"`
+	Currency  string `json:"currency"`
+	Amount    int64  `json:"amount"`
+}
+
+// EventStatisticResponse represents a view-object of event statistic
+type EventStatisticResponse struct {
+	Event            *EventInfoResponse                   `json:"event"`
+	TransactionCount int64                                `json:"transactionCount"`
+	Currencies       []*EventCurrencyStatisticResponse    `json:"currencies"`
+	Total            *EventTotalStatisticResponse         `json:"total,omitempty"`
+	Categories       []*EventCategoryStatisticResponse    `json:"categories"`
+	Participants     []*EventParticipantStatisticResponse `json:"participants"`
+}
+
+// GetParticipantContactIds returns all participant contact ids of the event
+func (e *Event) GetParticipantContactIds() []int64 {
+	contactIds := make([]string, 0)
+
+	if e.ParticipantContactIds != "" {
+		contactIds = strings.Split(e.ParticipantContactIds, ",")
+	}
+
+	result, _ := utils.StringArrayToInt64Array(contactIds)
+
+	return result
+}
+
+// HasGeoArea returns whether the event has geographic area
+func (e *Event) HasGeoArea() bool {
+	return e.GeoRadius > 0 && (e.GeoLongitude != 0 || e.GeoLatitude != 0)
+}
+
+// IsTransactionMatched returns whether the transaction is in the date range and geographic area (if both event and transaction have geographic info) of the event
+func (e *Event) IsTransactionMatched(transaction *Transaction) bool {
+	transactionUnixTime := utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime)
+
+	if transactionUnixTime < e.StartTime || transactionUnixTime > e.EndTime {
+		return false
+	}
+
+	if !e.HasGeoArea() || (transaction.GeoLongitude == 0 && transaction.GeoLatitude == 0) {
+		return true
+	}
+
+	return getDistanceInMeters(e.GeoLatitude, e.GeoLongitude, transaction.GeoLatitude, transaction.GeoLongitude) <= float64(e.GeoRadius)
+}
+
+// ToEventInfoResponse returns a view-object according to database model
+func (e *Event) ToEventInfoResponse() *EventInfoResponse {
+	var geoLocation *EventGeoLocationResponse
+
+	if e.HasGeoArea() {
+		geoLocation = &EventGeoLocationResponse{
+			Latitude:  e.GeoLatitude,
+			Longitude: e.GeoLongitude,
+			Radius:    e.GeoRadius,
+		}
+	}
+
+	return &EventInfoResponse{
+		Id:                    e.EventId,
+		LedgerId:              e.LedgerId,
+		Name:                  e.Name,
+		StartTime:             e.StartTime,
+		EndTime:               e.EndTime,
+		BudgetCurrency:        e.BudgetCurrency,
+		BudgetAmount:          e.BudgetAmount,
+		ParticipantContactIds: utils.Int64ArrayToStringArray(e.GetParticipantContactIds()),
+		AutoAssign:            e.AutoAssign,
+		GeoLocation:           geoLocation,
+		Comment:               e.Comment,
+		DisplayOrder:          e.DisplayOrder,
+		Hidden:                e.Hidden,
+	}
+}
+
+// getDistanceInMeters returns the great-circle distance between two points by haversine formula
+func getDistanceInMeters(latitude1 float64, longitude1 float64, latitude2 float64, longitude2 float64) float64 {
+	radianLatitude1 := latitude1 * math.Pi / 180
+	radianLatitude2 := latitude2 * math.Pi / 180
+	deltaLatitude := (latitude2 - latitude1) * math.Pi / 180
+	deltaLongitude := (longitude2 - longitude1) * math.Pi / 180
+
+	a := math.Sin(deltaLatitude/2)*math.Sin(deltaLatitude/2) +
+		math.Cos(radianLatitude1)*math.Cos(radianLatitude2)*math.Sin(deltaLongitude/2)*math.Sin(deltaLongitude/2)
+
+	return earthRadiusInMeters * 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
+
+// EventInfoResponseSlice represents the slice data structure of EventInfoResponse
+type EventInfoResponseSlice []*EventInfoResponse
+
+// Len returns the count of items
+func (s EventInfoResponseSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s EventInfoResponseSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s EventInfoResponseSlice) Less(i, j int) bool {
+	if s[i].StartTime != s[j].StartTime {
+		return s[i].StartTime > s[j].StartTime
+	}
+
+	return s[i].Id < s[j].Id
+}
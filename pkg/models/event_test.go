@@ -0,0 +1,68 @@
+package models
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+func TestEventIsTransactionMatched_DateRange(t *testing.T) {
+	event := &Event{
+		StartTime: 1700000000,
+		EndTime:   1700086400,
+	}
+
+	assert.True(t, event.IsTransactionMatched(&Transaction{TransactionTime: utils.GetMinTransactionTimeFromUnixTime(1700000000)}))
+	assert.True(t, event.IsTransactionMatched(&Transaction{TransactionTime: utils.GetMinTransactionTimeFromUnixTime(1700086400)}))
+	assert.False(t, event.IsTransactionMatched(&Transaction{TransactionTime: utils.GetMinTransactionTimeFromUnixTime(1699999999)}))
+	assert.False(t, event.IsTransactionMatched(&Transaction{TransactionTime: utils.GetMinTransactionTimeFromUnixTime(1700086401)}))
+}
+
+func TestEventIsTransactionMatched_GeoArea(t *testing.T) {
+	event := &Event{
+		StartTime:    1700000000,
+		EndTime:      1700086400,
+		GeoLatitude:  48.8584,
+		GeoLongitude: 2.2945,
+		GeoRadius:    5000,
+	}
+
+	transactionTime := utils.GetMinTransactionTimeFromUnixTime(1700040000)
+
+	assert.True(t, event.IsTransactionMatched(&Transaction{TransactionTime: transactionTime, GeoLatitude: 48.8606, GeoLongitude: 2.3376}))
+	assert.False(t, event.IsTransactionMatched(&Transaction{TransactionTime: transactionTime, GeoLatitude: 51.5007, GeoLongitude: -0.1246}))
+	assert.True(t, event.IsTransactionMatched(&Transaction{TransactionTime: transactionTime}))
+}
+
+func TestEventGetParticipantContactIds(t *testing.T) {
+	event := &Event{}
+	assert.Equal(t, 0, len(event.GetParticipantContactIds()))
+
+	event.ParticipantContactIds = "1001,1002"
+	assert.Equal(t, []int64{1001, 1002}, event.GetParticipantContactIds())
+}
+
+func TestEventInfoResponseSliceLess(t *testing.T) {
+	var eventRespSlice EventInfoResponseSlice
+	eventRespSlice = append(eventRespSlice, &EventInfoResponse{
+		Id:        3,
+		StartTime: 1700000000,
+	})
+	eventRespSlice = append(eventRespSlice, &EventInfoResponse{
+		Id:        2,
+		StartTime: 1700086400,
+	})
+	eventRespSlice = append(eventRespSlice, &EventInfoResponse{
+		Id:        1,
+		StartTime: 1700000000,
+	})
+
+	sort.Sort(eventRespSlice)
+
+	assert.Equal(t, int64(2), eventRespSlice[0].Id)
+	assert.Equal(t, int64(1), eventRespSlice[1].Id)
+	assert.Equal(t, int64(3), eventRespSlice[2].Id)
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"math"
 	"strings"
 
 	"github.com/mayswind/ezbookkeeping/pkg/utils"
@@ -114,3 +115,47 @@ func (s LatestExchangeRateSlice) Swap(i, j int) {
 func (s LatestExchangeRateSlice) Less(i, j int) bool {
 	return strings.Compare(s[i].Currency, s[j].Currency) < 0
 }
+
+// ConvertAmount returns the amount converted from one currency to another currency according to the exchange rates,
+// the second return value is false if the exchange rate of either currency is not available
+func (r *LatestExchangeRateResponse) ConvertAmount(amount int64, fromCurrency string, toCurrency string) (int64, bool) {
+	if fromCurrency == toCurrency {
+		return amount, true
+	}
+
+	fromRate, exists := r.getExchangeRate(fromCurrency)
+
+	if !exists {
+		return 0, false
+	}
+
+	toRate, exists := r.getExchangeRate(toCurrency)
+
+	if !exists {
+		return 0, false
+	}
+
+	return int64(math.Round(float64(amount) / fromRate * toRate)), true
+}
+
+func (r *LatestExchangeRateResponse) getExchangeRate(currency string) (float64, bool) {
+	if currency == r.BaseCurrency {
+		return 1, true
+	}
+
+	for i := 0; i < len(r.ExchangeRates); i++ {
+		if r.ExchangeRates[i].Currency != currency {
+			continue
+		}
+
+		rate, err := utils.StringToFloat64(r.ExchangeRates[i].Rate)
+
+		if err != nil || rate <= 0 {
+			return 0, false
+		}
+
+		return rate, true
+	}
+
+	return 0, false
+}
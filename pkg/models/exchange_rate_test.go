@@ -25,3 +25,36 @@ func TestLatestExchangeRateSliceLess(t *testing.T) {
 	assert.Equal(t, "EUR", latestExchangeRateSlice[1].Currency)
 	assert.Equal(t, "USD", latestExchangeRateSlice[2].Currency)
 }
+
+func TestLatestExchangeRateResponseConvertAmount(t *testing.T) {
+	exchangeRates := &LatestExchangeRateResponse{
+		BaseCurrency: "EUR",
+		ExchangeRates: []*LatestExchangeRate{
+			{Currency: "USD", Rate: "1.25"},
+			{Currency: "JPY", Rate: "150"},
+			{Currency: "GBP", Rate: "0"},
+		},
+	}
+
+	amount, converted := exchangeRates.ConvertAmount(1000, "EUR", "USD")
+	assert.True(t, converted)
+	assert.Equal(t, int64(1250), amount)
+
+	amount, converted = exchangeRates.ConvertAmount(1250, "USD", "EUR")
+	assert.True(t, converted)
+	assert.Equal(t, int64(1000), amount)
+
+	amount, converted = exchangeRates.ConvertAmount(15000, "JPY", "USD")
+	assert.True(t, converted)
+	assert.Equal(t, int64(125), amount)
+
+	amount, converted = exchangeRates.ConvertAmount(100, "CNY", "CNY")
+	assert.True(t, converted)
+	assert.Equal(t, int64(100), amount)
+
+	_, converted = exchangeRates.ConvertAmount(100, "CNY", "USD")
+	assert.False(t, converted)
+
+	_, converted = exchangeRates.ConvertAmount(100, "GBP", "USD")
+	assert.False(t, converted)
+}
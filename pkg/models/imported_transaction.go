@@ -12,6 +12,7 @@ type ImportTransaction struct {
 	OriginalDestinationAccountName     string
 	OriginalDestinationAccountCurrency string
 	OriginalTagNames                   []string
+	CustomFieldValues                  []*TransactionCustomFieldValueResponse
 }
 
 // ImportTransactionRequest represents all parameters of the imported transaction data
@@ -36,23 +37,24 @@ type ImportTransactionRequestItem struct {
 
 // ImportTransactionResponse represents a view-object of the imported transaction data
 type ImportTransactionResponse struct {
-	Type                               TransactionType                 `json:"type"`
-	CategoryId                         int64                           `json:"categoryId,string"`
-	OriginalCategoryName               string                          `json:"originalCategoryName"`
-	Time                               int64                           `json:"time"`
-	UtcOffset                          int16                           `json:"utcOffset"`
-	SourceAccountId                    int64                           `json:"sourceAccountId,string"`
-	OriginalSourceAccountName          string                          `json:"originalSourceAccountName"`
-	OriginalSourceAccountCurrency      string                          `json:"originalSourceAccountCurrency"`
-	DestinationAccountId               int64                           `json:"destinationAccountId,string,omitempty"`
-	OriginalDestinationAccountName     string                          `json:"originalDestinationAccountName,omitempty"`
-	OriginalDestinationAccountCurrency string                          `json:"originalDestinationAccountCurrency,omitempty"`
-	SourceAmount                       int64                           `json:"sourceAmount"`
-	DestinationAmount                  int64                           `json:"destinationAmount,omitempty"`
-	TagIds                             []string                        `json:"tagIds"`
-	OriginalTagNames                   []string                        `json:"originalTagNames"`
-	Comment                            string                          `json:"comment"`
-	GeoLocation                        *TransactionGeoLocationResponse `json:"geoLocation,omitempty"`
+	Type                               TransactionType                        `json:"type"`
+	CategoryId                         int64                                  `json:"categoryId,string"`
+	OriginalCategoryName               string                                 `json:"originalCategoryName"`
+	Time                               int64                                  `json:"time"`
+	UtcOffset                          int16                                  `json:"utcOffset"`
+	SourceAccountId                    int64                                  `json:"sourceAccountId,string"`
+	OriginalSourceAccountName          string                                 `json:"originalSourceAccountName"`
+	OriginalSourceAccountCurrency      string                                 `json:"originalSourceAccountCurrency"`
+	DestinationAccountId               int64                                  `json:"destinationAccountId,string,omitempty"`
+	OriginalDestinationAccountName     string                                 `json:"originalDestinationAccountName,omitempty"`
+	OriginalDestinationAccountCurrency string                                 `json:"originalDestinationAccountCurrency,omitempty"`
+	SourceAmount                       int64                                  `json:"sourceAmount"`
+	DestinationAmount                  int64                                  `json:"destinationAmount,omitempty"`
+	TagIds                             []string                               `json:"tagIds"`
+	OriginalTagNames                   []string                               `json:"originalTagNames"`
+	Comment                            string                                 `json:"comment"`
+	GeoLocation                        *TransactionGeoLocationResponse        `json:"geoLocation,omitempty"`
+	CustomFieldValues                  []*TransactionCustomFieldValueResponse `json:"customFieldValues,omitempty"`
 }
 
 // ImportTransactionResponsePageWrapper represents a response of imported transaction which contains items and count
@@ -96,6 +98,7 @@ func (t ImportTransaction) ToImportTransactionResponse() *ImportTransactionRespo
 		OriginalTagNames:                   t.OriginalTagNames,
 		Comment:                            t.Comment,
 		GeoLocation:                        geoLocation,
+		CustomFieldValues:                  t.CustomFieldValues,
 	}
 }
 
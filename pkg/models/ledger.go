@@ -0,0 +1,113 @@
+package models
+
+// LedgerDefaultName represents the name of the default ledger which is created automatically
+const LedgerDefaultName = "Default"
+
+// Ledger represents ledger (book) data stored in database
+type Ledger struct {
+	LedgerId        int64  `xorm:"PK"`
+	Uid             int64  `xorm:"INDEX(IDX_ledger_uid_deleted_order) NOT NULL"`
+	Deleted         bool   `xorm:"INDEX(IDX_ledger_uid_deleted_order) NOT NULL"`
+	Name            string `xorm:"VARCHAR(64) NOT NULL"`
+	DisplayOrder    int32  `xorm:"INDEX(IDX_ledger_uid_deleted_order) NOT NULL"`
+	IsDefault       bool   `xorm:"NOT NULL"`
+	Comment         string `xorm:"VARCHAR(255) NOT NULL"`
+	CreatedUnixTime int64
+	UpdatedUnixTime int64
+	DeletedUnixTime int64
+}
+
+// LedgerGetRequest represents all parameters of ledger getting request
+type LedgerGetRequest struct {
+	Id int64 `form:"id,string" binding:"required,min=1"`
+}
+
+// LedgerCreateRequest represents all parameters of ledger creation request
+type LedgerCreateRequest struct {
+	Name    string `json:"name" binding:"required,notBlank,max=64"`
+	Comment string `json:"comment" binding:"max=255"`
+}
+
+// LedgerModifyRequest represents all parameters of ledger modification request
+type LedgerModifyRequest struct {
+	Id      int64  `json:"id,string" binding:"required,min=1"`
+	Name    string `json:"name" binding:"required,notBlank,max=64"`
+	Comment string `json:"comment" binding:"max=255"`
+}
+
+// LedgerSetDefaultRequest represents all parameters of default ledger setting request
+type LedgerSetDefaultRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// LedgerMoveRequest represents all parameters of ledger moving request
+type LedgerMoveRequest struct {
+	NewDisplayOrders []*LedgerNewDisplayOrderRequest `json:"newDisplayOrders" binding:"required,min=1"`
+}
+
+// LedgerNewDisplayOrderRequest represents a data pair of id and display order
+type LedgerNewDisplayOrderRequest struct {
+	Id           int64 `json:"id,string" binding:"required,min=1"`
+	DisplayOrder int32 `json:"displayOrder"`
+}
+
+// LedgerDeleteRequest represents all parameters of ledger deleting request
+type LedgerDeleteRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// LedgerInfoResponse represents a view-object of ledger
+type LedgerInfoResponse struct {
+	Id           int64            `json:"id,string"`
+	Name         string           `json:"name"`
+	Comment      string           `json:"comment"`
+	DisplayOrder int32            `json:"displayOrder"`
+	IsDefault    bool             `json:"isDefault"`
+	Shared       bool             `json:"shared"`
+	Role         LedgerMemberRole `json:"role"`
+}
+
+// ToLedgerInfoResponse returns a view-object according to database model
+func (l *Ledger) ToLedgerInfoResponse() *LedgerInfoResponse {
+	return &LedgerInfoResponse{
+		Id:           l.LedgerId,
+		Name:         l.Name,
+		Comment:      l.Comment,
+		DisplayOrder: l.DisplayOrder,
+		IsDefault:    l.IsDefault,
+		Shared:       false,
+		Role:         LEDGER_MEMBER_ROLE_OWNER,
+	}
+}
+
+// ToSharedLedgerInfoResponse returns a view-object of the ledger which is shared with current user
+func (l *Ledger) ToSharedLedgerInfoResponse(role LedgerMemberRole) *LedgerInfoResponse {
+	resp := l.ToLedgerInfoResponse()
+	resp.IsDefault = false
+	resp.Shared = true
+	resp.Role = role
+
+	return resp
+}
+
+// LedgerInfoResponseSlice represents the slice data structure of LedgerInfoResponse
+type LedgerInfoResponseSlice []*LedgerInfoResponse
+
+// Len returns the count of items
+func (s LedgerInfoResponseSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s LedgerInfoResponseSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s LedgerInfoResponseSlice) Less(i, j int) bool {
+	if s[i].Shared != s[j].Shared {
+		return !s[i].Shared
+	}
+
+	return s[i].DisplayOrder < s[j].DisplayOrder
+}
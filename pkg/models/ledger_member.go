@@ -0,0 +1,262 @@
+package models
+
+import "fmt"
+
+// LedgerMemberRole represents the role of a member in a ledger
+type LedgerMemberRole byte
+
+// Ledger member roles
+const (
+	LEDGER_MEMBER_ROLE_OWNER      LedgerMemberRole = 1
+	LEDGER_MEMBER_ROLE_EDITOR     LedgerMemberRole = 2
+	LEDGER_MEMBER_ROLE_ENTRY_ONLY LedgerMemberRole = 3
+	LEDGER_MEMBER_ROLE_VIEWER     LedgerMemberRole = 4
+)
+
+// String returns a textual representation of the ledger member role enum
+func (r LedgerMemberRole) String() string {
+	switch r {
+	case LEDGER_MEMBER_ROLE_OWNER:
+		return "Owner"
+	case LEDGER_MEMBER_ROLE_EDITOR:
+		return "Editor"
+	case LEDGER_MEMBER_ROLE_ENTRY_ONLY:
+		return "Entry Only"
+	case LEDGER_MEMBER_ROLE_VIEWER:
+		return "Viewer"
+	default:
+		return fmt.Sprintf("Invalid(%d)", int(r))
+	}
+}
+
+// HasPermission returns whether the role has the given permission
+func (r LedgerMemberRole) HasPermission(permission LedgerPermission) bool {
+	switch r {
+	case LEDGER_MEMBER_ROLE_OWNER:
+		return true
+	case LEDGER_MEMBER_ROLE_EDITOR:
+		return permission != LEDGER_PERMISSION_MANAGE_LEDGER
+	case LEDGER_MEMBER_ROLE_ENTRY_ONLY:
+		return permission == LEDGER_PERMISSION_VIEW_BASIC_DATA ||
+			permission == LEDGER_PERMISSION_CREATE_TRANSACTIONS ||
+			permission == LEDGER_PERMISSION_MODIFY_OWN_TRANSACTIONS
+	case LEDGER_MEMBER_ROLE_VIEWER:
+		return permission == LEDGER_PERMISSION_VIEW_BASIC_DATA ||
+			permission == LEDGER_PERMISSION_VIEW_TRANSACTIONS
+	default:
+		return false
+	}
+}
+
+// IsValidMemberRole returns whether the role can be assigned to an invited member
+func (r LedgerMemberRole) IsValidMemberRole() bool {
+	return r == LEDGER_MEMBER_ROLE_EDITOR || r == LEDGER_MEMBER_ROLE_ENTRY_ONLY || r == LEDGER_MEMBER_ROLE_VIEWER
+}
+
+// LedgerPermission represents the permission of an operation in a ledger
+type LedgerPermission byte
+
+// Ledger permissions
+const (
+	LEDGER_PERMISSION_VIEW_BASIC_DATA         LedgerPermission = 1
+	LEDGER_PERMISSION_VIEW_TRANSACTIONS       LedgerPermission = 2
+	LEDGER_PERMISSION_CREATE_TRANSACTIONS     LedgerPermission = 3
+	LEDGER_PERMISSION_MODIFY_OWN_TRANSACTIONS LedgerPermission = 4
+	LEDGER_PERMISSION_MODIFY_ALL_TRANSACTIONS LedgerPermission = 5
+	LEDGER_PERMISSION_MANAGE_BASIC_DATA       LedgerPermission = 6
+	LEDGER_PERMISSION_MANAGE_LEDGER           LedgerPermission = 7
+)
+
+// LedgerMemberStatus represents the status of a ledger member
+type LedgerMemberStatus byte
+
+// Ledger member statuses
+const (
+	LEDGER_MEMBER_STATUS_INVITED  LedgerMemberStatus = 1
+	LEDGER_MEMBER_STATUS_ACCEPTED LedgerMemberStatus = 2
+)
+
+// LedgerMember represents ledger member data stored in database
+type LedgerMember struct {
+	MemberId        int64              `xorm:"PK"`
+	LedgerId        int64              `xorm:"UNIQUE(UQE_ledger_member_ledger_id_member_uid) NOT NULL"`
+	OwnerUid        int64              `xorm:"INDEX(IDX_ledger_member_owner_uid) NOT NULL"`
+	MemberUid       int64              `xorm:"UNIQUE(UQE_ledger_member_ledger_id_member_uid) INDEX(IDX_ledger_member_member_uid_status) NOT NULL"`
+	Role            LedgerMemberRole   `xorm:"TINYINT NOT NULL"`
+	Status          LedgerMemberStatus `xorm:"INDEX(IDX_ledger_member_member_uid_status) TINYINT NOT NULL"`
+	CreatedUnixTime int64
+	UpdatedUnixTime int64
+}
+
+// LedgerAccess represents the access of a user to a ledger
+type LedgerAccess struct {
+	LedgerId int64
+	OwnerUid int64
+	Uid      int64
+	Role     LedgerMemberRole
+}
+
+// IsOwner returns whether the user is the owner of the ledger
+func (a *LedgerAccess) IsOwner() bool {
+	return a.Role == LEDGER_MEMBER_ROLE_OWNER && a.OwnerUid == a.Uid
+}
+
+// HasPermission returns whether the user has the given permission in the ledger
+func (a *LedgerAccess) HasPermission(permission LedgerPermission) bool {
+	return a.Role.HasPermission(permission)
+}
+
+// CanModifyTransaction returns whether the user can modify or delete the given transaction in the ledger
+func (a *LedgerAccess) CanModifyTransaction(transaction *Transaction) bool {
+	if a.HasPermission(LEDGER_PERMISSION_MODIFY_ALL_TRANSACTIONS) {
+		return true
+	}
+
+	if !a.HasPermission(LEDGER_PERMISSION_MODIFY_OWN_TRANSACTIONS) {
+		return false
+	}
+
+	return transaction.CreatorUid == a.Uid
+}
+
+// LedgerMemberTransactionTotalAmount represents the total income and expense amounts of transactions created by a ledger member
+type LedgerMemberTransactionTotalAmount struct {
+	CreatorUid       int64
+	TransactionCount int64
+	IncomeAmounts    map[int64]int64
+	ExpenseAmounts   map[int64]int64
+}
+
+// LedgerMemberListRequest represents all parameters of ledger member listing request
+type LedgerMemberListRequest struct {
+	LedgerId int64 `form:"ledger_id,string" binding:"required,min=1"`
+}
+
+// LedgerMemberInviteRequest represents all parameters of ledger member invitation request
+type LedgerMemberInviteRequest struct {
+	LedgerId int64            `json:"ledgerId,string" binding:"required,min=1"`
+	Email    string           `json:"email" binding:"required,notBlank,max=100,validEmail"`
+	Role     LedgerMemberRole `json:"role" binding:"required"`
+}
+
+// LedgerMemberModifyRequest represents all parameters of ledger member role modification request
+type LedgerMemberModifyRequest struct {
+	LedgerId  int64            `json:"ledgerId,string" binding:"required,min=1"`
+	MemberUid int64            `json:"memberUid,string" binding:"required,min=1"`
+	Role      LedgerMemberRole `json:"role" binding:"required"`
+}
+
+// LedgerMemberRemoveRequest represents all parameters of ledger member removing request
+type LedgerMemberRemoveRequest struct {
+	LedgerId  int64 `json:"ledgerId,string" binding:"required,min=1"`
+	MemberUid int64 `json:"memberUid,string" binding:"required,min=1"`
+}
+
+// LedgerInvitationReplyRequest represents all parameters of ledger invitation accepting or declining request
+type LedgerInvitationReplyRequest struct {
+	LedgerId int64 `json:"ledgerId,string" binding:"required,min=1"`
+}
+
+// LedgerLeaveRequest represents all parameters of leaving a shared ledger request
+type LedgerLeaveRequest struct {
+	LedgerId int64 `json:"ledgerId,string" binding:"required,min=1"`
+}
+
+// LedgerMemberStatisticsRequest represents all parameters of ledger member statistics request
+type LedgerMemberStatisticsRequest struct {
+	LedgerId  int64 `form:"ledger_id,string" binding:"required,min=1"`
+	StartTime int64 `form:"start_time" binding:"min=0"`
+	EndTime   int64 `form:"end_time" binding:"min=0"`
+}
+
+// LedgerMemberInfoResponse represents a view-object of ledger member
+type LedgerMemberInfoResponse struct {
+	Uid      int64              `json:"uid,string"`
+	Username string             `json:"username"`
+	Nickname string             `json:"nickname"`
+	Role     LedgerMemberRole   `json:"role"`
+	Status   LedgerMemberStatus `json:"status"`
+}
+
+// LedgerInvitationInfoResponse represents a view-object of ledger invitation
+type LedgerInvitationInfoResponse struct {
+	LedgerId      int64            `json:"ledgerId,string"`
+	LedgerName    string           `json:"ledgerName"`
+	OwnerNickname string           `json:"ownerNickname"`
+	Role          LedgerMemberRole `json:"role"`
+}
+
+// LedgerMemberStatisticResponse represents a view-object of transaction statistics of a ledger member
+type LedgerMemberStatisticResponse struct {
+	Uid                   int64                                    `json:"uid,string"`
+	Nickname              string                                   `json:"nickname"`
+	TotalTransactionCount int64                                    `json:"totalTransactionCount"`
+	Amounts               []*LedgerMemberCurrencyStatisticResponse `json:"amounts"`
+}
+
+// LedgerMemberCurrencyStatisticResponse represents a view-object of total income and expense of a ledger member in one currency
+type LedgerMemberCurrencyStatisticResponse struct {
+	Currency      string `json:"currency"`
+	IncomeAmount  int64  `json:"incomeAmount"`
+	ExpenseAmount int64  `json:"expenseAmount"`
+}
+
+// ToLedgerMemberInfoResponse returns a view-object according to database model
+func (m *LedgerMember) ToLedgerMemberInfoResponse(user *User) *LedgerMemberInfoResponse {
+	resp := &LedgerMemberInfoResponse{
+		Uid:    m.MemberUid,
+		Role:   m.Role,
+		Status: m.Status,
+	}
+
+	if user != nil {
+		resp.Username = user.Username
+		resp.Nickname = user.Nickname
+	}
+
+	return resp
+}
+
+// LedgerMemberInfoResponseSlice represents the slice data structure of LedgerMemberInfoResponse
+type LedgerMemberInfoResponseSlice []*LedgerMemberInfoResponse
+
+// Len returns the count of items
+func (s LedgerMemberInfoResponseSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s LedgerMemberInfoResponseSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s LedgerMemberInfoResponseSlice) Less(i, j int) bool {
+	if s[i].Role != s[j].Role {
+		return s[i].Role < s[j].Role
+	}
+
+	return s[i].Uid < s[j].Uid
+}
+
+// LedgerMemberStatisticResponseSlice represents the slice data structure of LedgerMemberStatisticResponse
+type LedgerMemberStatisticResponseSlice []*LedgerMemberStatisticResponse
+
+// Len returns the count of items
+func (s LedgerMemberStatisticResponseSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s LedgerMemberStatisticResponseSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s LedgerMemberStatisticResponseSlice) Less(i, j int) bool {
+	if s[i].TotalTransactionCount != s[j].TotalTransactionCount {
+		return s[i].TotalTransactionCount > s[j].TotalTransactionCount
+	}
+
+	return s[i].Uid < s[j].Uid
+}
@@ -0,0 +1,139 @@
+package models
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLedgerMemberRoleHasPermission_Owner(t *testing.T) {
+	role := LEDGER_MEMBER_ROLE_OWNER
+
+	assert.True(t, role.HasPermission(LEDGER_PERMISSION_VIEW_BASIC_DATA))
+	assert.True(t, role.HasPermission(LEDGER_PERMISSION_VIEW_TRANSACTIONS))
+	assert.True(t, role.HasPermission(LEDGER_PERMISSION_CREATE_TRANSACTIONS))
+	assert.True(t, role.HasPermission(LEDGER_PERMISSION_MODIFY_OWN_TRANSACTIONS))
+	assert.True(t, role.HasPermission(LEDGER_PERMISSION_MODIFY_ALL_TRANSACTIONS))
+	assert.True(t, role.HasPermission(LEDGER_PERMISSION_MANAGE_BASIC_DATA))
+	assert.True(t, role.HasPermission(LEDGER_PERMISSION_MANAGE_LEDGER))
+}
+
+func TestLedgerMemberRoleHasPermission_Editor(t *testing.T) {
+	role := LEDGER_MEMBER_ROLE_EDITOR
+
+	assert.True(t, role.HasPermission(LEDGER_PERMISSION_VIEW_BASIC_DATA))
+	assert.True(t, role.HasPermission(LEDGER_PERMISSION_VIEW_TRANSACTIONS))
+	assert.True(t, role.HasPermission(LEDGER_PERMISSION_CREATE_TRANSACTIONS))
+	assert.True(t, role.HasPermission(LEDGER_PERMISSION_MODIFY_OWN_TRANSACTIONS))
+	assert.True(t, role.HasPermission(LEDGER_PERMISSION_MODIFY_ALL_TRANSACTIONS))
+	assert.True(t, role.HasPermission(LEDGER_PERMISSION_MANAGE_BASIC_DATA))
+	assert.False(t, role.HasPermission(LEDGER_PERMISSION_MANAGE_LEDGER))
+}
+
+func TestLedgerMemberRoleHasPermission_EntryOnly(t *testing.T) {
+	role := LEDGER_MEMBER_ROLE_ENTRY_ONLY
+
+	assert.True(t, role.HasPermission(LEDGER_PERMISSION_VIEW_BASIC_DATA))
+	assert.False(t, role.HasPermission(LEDGER_PERMISSION_VIEW_TRANSACTIONS))
+	assert.True(t, role.HasPermission(LEDGER_PERMISSION_CREATE_TRANSACTIONS))
+	assert.True(t, role.HasPermission(LEDGER_PERMISSION_MODIFY_OWN_TRANSACTIONS))
+	assert.False(t, role.HasPermission(LEDGER_PERMISSION_MODIFY_ALL_TRANSACTIONS))
+	assert.False(t, role.HasPermission(LEDGER_PERMISSION_MANAGE_BASIC_DATA))
+	assert.False(t, role.HasPermission(LEDGER_PERMISSION_MANAGE_LEDGER))
+}
+
+func TestLedgerMemberRoleHasPermission_Viewer(t *testing.T) {
+	role := LEDGER_MEMBER_ROLE_VIEWER
+
+	assert.True(t, role.HasPermission(LEDGER_PERMISSION_VIEW_BASIC_DATA))
+	assert.True(t, role.HasPermission(LEDGER_PERMISSION_VIEW_TRANSACTIONS))
+	assert.False(t, role.HasPermission(LEDGER_PERMISSION_CREATE_TRANSACTIONS))
+	assert.False(t, role.HasPermission(LEDGER_PERMISSION_MODIFY_OWN_TRANSACTIONS))
+	assert.False(t, role.HasPermission(LEDGER_PERMISSION_MODIFY_ALL_TRANSACTIONS))
+	assert.False(t, role.HasPermission(LEDGER_PERMISSION_MANAGE_BASIC_DATA))
+	assert.False(t, role.HasPermission(LEDGER_PERMISSION_MANAGE_LEDGER))
+}
+
+func TestLedgerMemberRoleIsValidMemberRole(t *testing.T) {
+	assert.False(t, LedgerMemberRole(0).IsValidMemberRole())
+	assert.False(t, LEDGER_MEMBER_ROLE_OWNER.IsValidMemberRole())
+	assert.True(t, LEDGER_MEMBER_ROLE_EDITOR.IsValidMemberRole())
+	assert.True(t, LEDGER_MEMBER_ROLE_ENTRY_ONLY.IsValidMemberRole())
+	assert.True(t, LEDGER_MEMBER_ROLE_VIEWER.IsValidMemberRole())
+	assert.False(t, LedgerMemberRole(5).IsValidMemberRole())
+}
+
+func TestLedgerAccessCanModifyTransaction(t *testing.T) {
+	ownTransaction := &Transaction{CreatorUid: 2}
+	otherTransaction := &Transaction{CreatorUid: 1}
+
+	editorAccess := &LedgerAccess{LedgerId: 100, OwnerUid: 1, Uid: 2, Role: LEDGER_MEMBER_ROLE_EDITOR}
+	assert.True(t, editorAccess.CanModifyTransaction(ownTransaction))
+	assert.True(t, editorAccess.CanModifyTransaction(otherTransaction))
+
+	entryOnlyAccess := &LedgerAccess{LedgerId: 100, OwnerUid: 1, Uid: 2, Role: LEDGER_MEMBER_ROLE_ENTRY_ONLY}
+	assert.True(t, entryOnlyAccess.CanModifyTransaction(ownTransaction))
+	assert.False(t, entryOnlyAccess.CanModifyTransaction(otherTransaction))
+
+	viewerAccess := &LedgerAccess{LedgerId: 100, OwnerUid: 1, Uid: 2, Role: LEDGER_MEMBER_ROLE_VIEWER}
+	assert.False(t, viewerAccess.CanModifyTransaction(ownTransaction))
+	assert.False(t, viewerAccess.CanModifyTransaction(otherTransaction))
+}
+
+func TestLedgerAccessIsOwner(t *testing.T) {
+	ownerAccess := &LedgerAccess{LedgerId: 100, OwnerUid: 1, Uid: 1, Role: LEDGER_MEMBER_ROLE_OWNER}
+	assert.True(t, ownerAccess.IsOwner())
+
+	memberAccess := &LedgerAccess{LedgerId: 100, OwnerUid: 1, Uid: 2, Role: LEDGER_MEMBER_ROLE_EDITOR}
+	assert.False(t, memberAccess.IsOwner())
+}
+
+func TestLedgerMemberInfoResponseSliceLess(t *testing.T) {
+	var memberRespSlice LedgerMemberInfoResponseSlice
+	memberRespSlice = append(memberRespSlice, &LedgerMemberInfoResponse{
+		Uid:  3,
+		Role: LEDGER_MEMBER_ROLE_VIEWER,
+	})
+	memberRespSlice = append(memberRespSlice, &LedgerMemberInfoResponse{
+		Uid:  4,
+		Role: LEDGER_MEMBER_ROLE_EDITOR,
+	})
+	memberRespSlice = append(memberRespSlice, &LedgerMemberInfoResponse{
+		Uid:  1,
+		Role: LEDGER_MEMBER_ROLE_OWNER,
+	})
+	memberRespSlice = append(memberRespSlice, &LedgerMemberInfoResponse{
+		Uid:  2,
+		Role: LEDGER_MEMBER_ROLE_EDITOR,
+	})
+
+	sort.Sort(memberRespSlice)
+
+	assert.Equal(t, int64(1), memberRespSlice[0].Uid)
+	assert.Equal(t, int64(2), memberRespSlice[1].Uid)
+	assert.Equal(t, int64(4), memberRespSlice[2].Uid)
+	assert.Equal(t, int64(3), memberRespSlice[3].Uid)
+}
+
+func TestLedgerMemberStatisticResponseSliceLess(t *testing.T) {
+	var statisticRespSlice LedgerMemberStatisticResponseSlice
+	statisticRespSlice = append(statisticRespSlice, &LedgerMemberStatisticResponse{
+		Uid:                   3,
+		TotalTransactionCount: 5,
+	})
+	statisticRespSlice = append(statisticRespSlice, &LedgerMemberStatisticResponse{
+		Uid:                   2,
+		TotalTransactionCount: 10,
+	})
+	statisticRespSlice = append(statisticRespSlice, &LedgerMemberStatisticResponse{
+		Uid:                   1,
+		TotalTransactionCount: 5,
+	})
+
+	sort.Sort(statisticRespSlice)
+
+	assert.Equal(t, int64(2), statisticRespSlice[0].Uid)
+	assert.Equal(t, int64(1), statisticRespSlice[1].Uid)
+	assert.Equal(t, int64(3), statisticRespSlice[2].Uid)
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLedgerInfoResponseSliceLess(t *testing.T) {
+	var ledgerRespSlice LedgerInfoResponseSlice
+	ledgerRespSlice = append(ledgerRespSlice, &LedgerInfoResponse{
+		Id:           1,
+		DisplayOrder: 3,
+	})
+	ledgerRespSlice = append(ledgerRespSlice, &LedgerInfoResponse{
+		Id:           2,
+		DisplayOrder: 1,
+	})
+	ledgerRespSlice = append(ledgerRespSlice, &LedgerInfoResponse{
+		Id:           3,
+		DisplayOrder: 2,
+	})
+
+	sort.Sort(ledgerRespSlice)
+
+	assert.Equal(t, int64(2), ledgerRespSlice[0].Id)
+	assert.Equal(t, int64(3), ledgerRespSlice[1].Id)
+	assert.Equal(t, int64(1), ledgerRespSlice[2].Id)
+}
+
+func TestLedgerInfoResponseSliceLess_SharedLedgers(t *testing.T) {
+	var ledgerRespSlice LedgerInfoResponseSlice
+	ledgerRespSlice = append(ledgerRespSlice, &LedgerInfoResponse{
+		Id:           1,
+		DisplayOrder: 1,
+		Shared:       true,
+	})
+	ledgerRespSlice = append(ledgerRespSlice, &LedgerInfoResponse{
+		Id:           2,
+		DisplayOrder: 2,
+	})
+	ledgerRespSlice = append(ledgerRespSlice, &LedgerInfoResponse{
+		Id:           3,
+		DisplayOrder: 1,
+	})
+
+	sort.Sort(ledgerRespSlice)
+
+	assert.Equal(t, int64(3), ledgerRespSlice[0].Id)
+	assert.Equal(t, int64(2), ledgerRespSlice[1].Id)
+	assert.Equal(t, int64(1), ledgerRespSlice[2].Id)
+}
@@ -108,6 +108,13 @@ func (t TransactionDbType) ToTransactionRelatedAccountType() (TransactionRelated
 // TransactionTagFilterValue represents transaction tag filter value for no tag
 const TransactionNoTagFilterValue = "none"
 
+// Transaction tag filter item prefixes, the item with tag group prefix matches all tags in the tag group,
+// and the item with tag subtree prefix matches the tag and all its child tags
+const (
+	TransactionTagFilterTagGroupPrefix   = "g"
+	TransactionTagFilterTagSubtreePrefix = "s"
+)
+
 // TransactionTagFilterType represents transaction tag filter type
 type TransactionTagFilterType byte
 
@@ -122,12 +129,13 @@ const (
 // Transaction represents transaction data stored in database
 type Transaction struct {
 	TransactionId        int64             `xorm:"PK"`
-	Uid                  int64             `xorm:"UNIQUE(UQE_transaction_uid_time) INDEX(IDX_transaction_uid_deleted_time) INDEX(IDX_transaction_uid_deleted_type_time) INDEX(IDX_transaction_uid_deleted_type_account_id_time) INDEX(IDX_transaction_uid_deleted_category_id_time) INDEX(IDX_transaction_uid_deleted_account_id_time) INDEX(IDX_transaction_uid_deleted_time_longitude_latitude) NOT NULL"`
-	Deleted              bool              `xorm:"INDEX(IDX_transaction_uid_deleted_time) INDEX(IDX_transaction_uid_deleted_type_time) INDEX(IDX_transaction_uid_deleted_type_account_id_time) INDEX(IDX_transaction_uid_deleted_category_id_time) INDEX(IDX_transaction_uid_deleted_account_id_time) INDEX(IDX_transaction_uid_deleted_time_longitude_latitude) NOT NULL"`
+	Uid                  int64             `xorm:"UNIQUE(UQE_transaction_uid_time) INDEX(IDX_transaction_uid_deleted_time) INDEX(IDX_transaction_uid_deleted_type_time) INDEX(IDX_transaction_uid_deleted_type_account_id_time) INDEX(IDX_transaction_uid_deleted_category_id_time) INDEX(IDX_transaction_uid_deleted_account_id_time) INDEX(IDX_transaction_uid_deleted_time_longitude_latitude) INDEX(IDX_transaction_uid_deleted_ledger_id_time) INDEX(IDX_transaction_uid_deleted_event_id_time) NOT NULL"`
+	Deleted              bool              `xorm:"INDEX(IDX_transaction_uid_deleted_time) INDEX(IDX_transaction_uid_deleted_type_time) INDEX(IDX_transaction_uid_deleted_type_account_id_time) INDEX(IDX_transaction_uid_deleted_category_id_time) INDEX(IDX_transaction_uid_deleted_account_id_time) INDEX(IDX_transaction_uid_deleted_time_longitude_latitude) INDEX(IDX_transaction_uid_deleted_ledger_id_time) INDEX(IDX_transaction_uid_deleted_event_id_time) NOT NULL"`
+	LedgerId             int64             `xorm:"INDEX(IDX_transaction_uid_deleted_ledger_id_time) NOT NULL DEFAULT 0"`
 	Type                 TransactionDbType `xorm:"INDEX(IDX_transaction_uid_deleted_type_time) INDEX(IDX_transaction_uid_deleted_type_account_id_time) NOT NULL"`
 	CategoryId           int64             `xorm:"INDEX(IDX_transaction_uid_deleted_category_id_time) NOT NULL"`
 	AccountId            int64             `xorm:"INDEX(IDX_transaction_uid_deleted_account_id_time) INDEX(IDX_transaction_uid_deleted_type_account_id_time) NOT NULL"`
-	TransactionTime      int64             `xorm:"UNIQUE(UQE_transaction_uid_time) INDEX(IDX_transaction_uid_deleted_time) INDEX(IDX_transaction_uid_deleted_type_time) INDEX(IDX_transaction_uid_deleted_type_account_id_time) INDEX(IDX_transaction_uid_deleted_category_id_time) INDEX(IDX_transaction_uid_deleted_account_id_time) NOT NULL"`
+	TransactionTime      int64             `xorm:"UNIQUE(UQE_transaction_uid_time) INDEX(IDX_transaction_uid_deleted_time) INDEX(IDX_transaction_uid_deleted_type_time) INDEX(IDX_transaction_uid_deleted_type_account_id_time) INDEX(IDX_transaction_uid_deleted_category_id_time) INDEX(IDX_transaction_uid_deleted_account_id_time) INDEX(IDX_transaction_uid_deleted_ledger_id_time) INDEX(IDX_transaction_uid_deleted_event_id_time) NOT NULL"`
 	TimezoneUtcOffset    int16             `xorm:"NOT NULL"`
 	Amount               int64             `xorm:"NOT NULL"`
 	RelatedId            int64             `xorm:"NOT NULL"`
@@ -138,6 +146,8 @@ type Transaction struct {
 	GeoLongitude         float64           `xorm:"INDEX(IDX_transaction_uid_deleted_time_longitude_latitude)"`
 	GeoLatitude          float64           `xorm:"INDEX(IDX_transaction_uid_deleted_time_longitude_latitude)"`
 	CreatedIp            string            `xorm:"VARCHAR(39)"`
+	CreatorUid           int64             `xorm:"NOT NULL DEFAULT 0"`
+	EventId              int64             `xorm:"INDEX(IDX_transaction_uid_deleted_event_id_time) NOT NULL DEFAULT 0"`
 	ScheduledCreated     bool
 	CreatedUnixTime      int64
 	UpdatedUnixTime      int64
@@ -159,37 +169,43 @@ type TransactionGeoLocationRequest struct {
 
 // TransactionCreateRequest represents all parameters of transaction creation request
 type TransactionCreateRequest struct {
-	Type                 TransactionType                `json:"type" binding:"required"`
-	CategoryId           int64                          `json:"categoryId,string"`
-	Time                 int64                          `json:"time" binding:"required,min=1"`
-	UtcOffset            int16                          `json:"utcOffset" binding:"min=-720,max=840"`
-	SourceAccountId      int64                          `json:"sourceAccountId,string" binding:"required,min=1"`
-	DestinationAccountId int64                          `json:"destinationAccountId,string" binding:"min=0"`
-	SourceAmount         int64                          `json:"sourceAmount" binding:"min=-99999999999,max=99999999999"`
-	DestinationAmount    int64                          `json:"destinationAmount" binding:"min=-99999999999,max=99999999999"`
-	HideAmount           bool                           `json:"hideAmount"`
-	TagIds               []string                       `json:"tagIds"`
-	PictureIds           []string                       `json:"pictureIds"`
-	Comment              string                         `json:"comment" binding:"max=255"`
-	GeoLocation          *TransactionGeoLocationRequest `json:"geoLocation" binding:"omitempty"`
-	ClientSessionId      string                         `json:"clientSessionId"`
+	Type                 TransactionType                       `json:"type" binding:"required"`
+	CategoryId           int64                                 `json:"categoryId,string"`
+	Time                 int64                                 `json:"time" binding:"required,min=1"`
+	UtcOffset            int16                                 `json:"utcOffset" binding:"min=-720,max=840"`
+	SourceAccountId      int64                                 `json:"sourceAccountId,string" binding:"required,min=1"`
+	DestinationAccountId int64                                 `json:"destinationAccountId,string" binding:"min=0"`
+	SourceAmount         int64                                 `json:"sourceAmount" binding:"min=-99999999999,max=99999999999"`
+	DestinationAmount    int64                                 `json:"destinationAmount" binding:"min=-99999999999,max=99999999999"`
+	HideAmount           bool                                  `json:"hideAmount"`
+	TagIds               []string                              `json:"tagIds"`
+	PictureIds           []string                              `json:"pictureIds"`
+	AttachmentIds        []string                              `json:"attachmentIds"`
+	Comment              string                                `json:"comment" binding:"max=255"`
+	GeoLocation          *TransactionGeoLocationRequest        `json:"geoLocation" binding:"omitempty"`
+	EventId              int64                                 `json:"eventId,string" binding:"min=0"`
+	CustomFieldValues    []*TransactionCustomFieldValueRequest `json:"customFieldValues" binding:"omitempty,max=50,dive"`
+	ClientSessionId      string                                `json:"clientSessionId"`
 }
 
 // TransactionModifyRequest represents all parameters of transaction modification request
 type TransactionModifyRequest struct {
-	Id                   int64                          `json:"id,string" binding:"required,min=1"`
-	CategoryId           int64                          `json:"categoryId,string"`
-	Time                 int64                          `json:"time" binding:"required,min=1"`
-	UtcOffset            int16                          `json:"utcOffset" binding:"min=-720,max=840"`
-	SourceAccountId      int64                          `json:"sourceAccountId,string" binding:"required,min=1"`
-	DestinationAccountId int64                          `json:"destinationAccountId,string" binding:"min=0"`
-	SourceAmount         int64                          `json:"sourceAmount" binding:"min=-99999999999,max=99999999999"`
-	DestinationAmount    int64                          `json:"destinationAmount" binding:"min=-99999999999,max=99999999999"`
-	HideAmount           bool                           `json:"hideAmount"`
-	TagIds               []string                       `json:"tagIds"`
-	PictureIds           []string                       `json:"pictureIds"`
-	Comment              string                         `json:"comment" binding:"max=255"`
-	GeoLocation          *TransactionGeoLocationRequest `json:"geoLocation" binding:"omitempty"`
+	Id                   int64                                 `json:"id,string" binding:"required,min=1"`
+	CategoryId           int64                                 `json:"categoryId,string"`
+	Time                 int64                                 `json:"time" binding:"required,min=1"`
+	UtcOffset            int16                                 `json:"utcOffset" binding:"min=-720,max=840"`
+	SourceAccountId      int64                                 `json:"sourceAccountId,string" binding:"required,min=1"`
+	DestinationAccountId int64                                 `json:"destinationAccountId,string" binding:"min=0"`
+	SourceAmount         int64                                 `json:"sourceAmount" binding:"min=-99999999999,max=99999999999"`
+	DestinationAmount    int64                                 `json:"destinationAmount" binding:"min=-99999999999,max=99999999999"`
+	HideAmount           bool                                  `json:"hideAmount"`
+	TagIds               []string                              `json:"tagIds"`
+	PictureIds           []string                              `json:"pictureIds"`
+	AttachmentIds        []string                              `json:"attachmentIds"` // Attachments are kept unchanged when this field is absent
+	Comment              string                                `json:"comment" binding:"max=255"`
+	GeoLocation          *TransactionGeoLocationRequest        `json:"geoLocation" binding:"omitempty"`
+	EventId              *int64                                `json:"eventId,string" binding:"omitempty,min=0"`
+	CustomFieldValues    []*TransactionCustomFieldValueRequest `json:"customFieldValues" binding:"omitempty,max=50,dive"` // Values are kept unchanged when this field is absent
 }
 
 // TransactionImportRequest represents all parameters of transaction import request
@@ -204,71 +220,77 @@ type TransactionImportProcessRequest struct {
 }
 
 type TransactionTagFilter struct {
-	TagIds []int64
-	Type   TransactionTagFilterType
+	TagIds        []int64
+	TagGroupIds   []int64
+	SubtreeTagIds []int64
+	Type          TransactionTagFilterType
 }
 
 // TransactionCountRequest represents transaction count request
 type TransactionCountRequest struct {
-	Type         TransactionType `form:"type" binding:"min=0,max=4"`
-	CategoryIds  string          `form:"category_ids"`
-	AccountIds   string          `form:"account_ids"`
-	TagFilter    string          `form:"tag_filter" binding:"validTagFilter"`
-	AmountFilter string          `form:"amount_filter" binding:"validAmountFilter"`
-	Keyword      string          `form:"keyword"`
-	MaxTime      int64           `form:"max_time" binding:"min=0"` // Transaction time sequence id
-	MinTime      int64           `form:"min_time" binding:"min=0"` // Transaction time sequence id
+	Type              TransactionType `form:"type" binding:"min=0,max=4"`
+	CategoryIds       string          `form:"category_ids"`
+	AccountIds        string          `form:"account_ids"`
+	TagFilter         string          `form:"tag_filter" binding:"validTagFilter"`
+	AmountFilter      string          `form:"amount_filter" binding:"validAmountFilter"`
+	Keyword           string          `form:"keyword"`
+	CustomFieldFilter string          `form:"custom_field_filter"`
+	MaxTime           int64           `form:"max_time" binding:"min=0"` // Transaction time sequence id
+	MinTime           int64           `form:"min_time" binding:"min=0"` // Transaction time sequence id
 }
 
 // TransactionListByMaxTimeRequest represents all parameters of transaction listing by max time request
 type TransactionListByMaxTimeRequest struct {
-	Type         TransactionType `form:"type" binding:"min=0,max=4"`
-	CategoryIds  string          `form:"category_ids"`
-	AccountIds   string          `form:"account_ids"`
-	TagFilter    string          `form:"tag_filter" binding:"validTagFilter"`
-	AmountFilter string          `form:"amount_filter" binding:"validAmountFilter"`
-	Keyword      string          `form:"keyword"`
-	MaxTime      int64           `form:"max_time" binding:"min=0"` // Transaction time sequence id
-	MinTime      int64           `form:"min_time" binding:"min=0"` // Transaction time sequence id
-	Page         int32           `form:"page" binding:"min=0"`
-	Count        int32           `form:"count" binding:"required,min=1,max=50"`
-	WithCount    bool            `form:"with_count"`
-	WithPictures bool            `form:"with_pictures"`
-	TrimAccount  bool            `form:"trim_account"`
-	TrimCategory bool            `form:"trim_category"`
-	TrimTag      bool            `form:"trim_tag"`
+	Type              TransactionType `form:"type" binding:"min=0,max=4"`
+	CategoryIds       string          `form:"category_ids"`
+	AccountIds        string          `form:"account_ids"`
+	TagFilter         string          `form:"tag_filter" binding:"validTagFilter"`
+	AmountFilter      string          `form:"amount_filter" binding:"validAmountFilter"`
+	Keyword           string          `form:"keyword"`
+	CustomFieldFilter string          `form:"custom_field_filter"`
+	MaxTime           int64           `form:"max_time" binding:"min=0"` // Transaction time sequence id
+	MinTime           int64           `form:"min_time" binding:"min=0"` // Transaction time sequence id
+	Page              int32           `form:"page" binding:"min=0"`
+	Count             int32           `form:"count" binding:"required,min=1,max=50"`
+	WithCount         bool            `form:"with_count"`
+	WithPictures      bool            `form:"with_pictures"`
+	TrimAccount       bool            `form:"trim_account"`
+	TrimCategory      bool            `form:"trim_category"`
+	TrimTag           bool            `form:"trim_tag"`
 }
 
 // TransactionListInMonthByPageRequest represents all parameters of transaction listing by month request
 type TransactionListInMonthByPageRequest struct {
-	Year         int32           `form:"year" binding:"required,min=1"`
-	Month        int32           `form:"month" binding:"required,min=1"`
-	Type         TransactionType `form:"type" binding:"min=0,max=4"`
-	CategoryIds  string          `form:"category_ids"`
-	AccountIds   string          `form:"account_ids"`
-	TagFilter    string          `form:"tag_filter" binding:"validTagFilter"`
-	AmountFilter string          `form:"amount_filter" binding:"validAmountFilter"`
-	Keyword      string          `form:"keyword"`
-	WithPictures bool            `form:"with_pictures"`
-	TrimAccount  bool            `form:"trim_account"`
-	TrimCategory bool            `form:"trim_category"`
-	TrimTag      bool            `form:"trim_tag"`
+	Year              int32           `form:"year" binding:"required,min=1"`
+	Month             int32           `form:"month" binding:"required,min=1"`
+	Type              TransactionType `form:"type" binding:"min=0,max=4"`
+	CategoryIds       string          `form:"category_ids"`
+	AccountIds        string          `form:"account_ids"`
+	TagFilter         string          `form:"tag_filter" binding:"validTagFilter"`
+	AmountFilter      string          `form:"amount_filter" binding:"validAmountFilter"`
+	Keyword           string          `form:"keyword"`
+	CustomFieldFilter string          `form:"custom_field_filter"`
+	WithPictures      bool            `form:"with_pictures"`
+	TrimAccount       bool            `form:"trim_account"`
+	TrimCategory      bool            `form:"trim_category"`
+	TrimTag           bool            `form:"trim_tag"`
 }
 
 // TransactionAllListRequest represents all parameters of all transaction listing request
 type TransactionAllListRequest struct {
-	Type         TransactionType `form:"type" binding:"min=0,max=4"`
-	CategoryIds  string          `form:"category_ids"`
-	AccountIds   string          `form:"account_ids"`
-	TagFilter    string          `form:"tag_filter" binding:"validTagFilter"`
-	AmountFilter string          `form:"amount_filter" binding:"validAmountFilter"`
-	Keyword      string          `form:"keyword"`
-	StartTime    int64           `form:"start_time" binding:"min=0"`
-	EndTime      int64           `form:"end_time" binding:"min=0"`
-	WithPictures bool            `form:"with_pictures"`
-	TrimAccount  bool            `form:"trim_account"`
-	TrimCategory bool            `form:"trim_category"`
-	TrimTag      bool            `form:"trim_tag"`
+	Type              TransactionType `form:"type" binding:"min=0,max=4"`
+	CategoryIds       string          `form:"category_ids"`
+	AccountIds        string          `form:"account_ids"`
+	TagFilter         string          `form:"tag_filter" binding:"validTagFilter"`
+	AmountFilter      string          `form:"amount_filter" binding:"validAmountFilter"`
+	Keyword           string          `form:"keyword"`
+	CustomFieldFilter string          `form:"custom_field_filter"`
+	StartTime         int64           `form:"start_time" binding:"min=0"`
+	EndTime           int64           `form:"end_time" binding:"min=0"`
+	WithPictures      bool            `form:"with_pictures"`
+	TrimAccount       bool            `form:"trim_account"`
+	TrimCategory      bool            `form:"trim_category"`
+	TrimTag           bool            `form:"trim_tag"`
 }
 
 // TransactionReconciliationStatementRequest represents all parameters of transaction reconciliation statement request
@@ -318,11 +340,12 @@ type TransactionAmountsRequestItem struct {
 
 // TransactionGetRequest represents all parameters of transaction getting request
 type TransactionGetRequest struct {
-	Id           int64 `form:"id,string" binding:"required,min=1"`
-	WithPictures bool  `form:"with_pictures"`
-	TrimAccount  bool  `form:"trim_account"`
-	TrimCategory bool  `form:"trim_category"`
-	TrimTag      bool  `form:"trim_tag"`
+	Id              int64 `form:"id,string" binding:"required,min=1"`
+	WithPictures    bool  `form:"with_pictures"`
+	WithAttachments bool  `form:"with_attachments"`
+	TrimAccount     bool  `form:"trim_account"`
+	TrimCategory    bool  `form:"trim_category"`
+	TrimTag         bool  `form:"trim_tag"`
 }
 
 // TransactionMoveBetweenAccountsRequest represents all parameters of moving all transactions between accounts request
@@ -350,26 +373,31 @@ type TransactionGeoLocationResponse struct {
 
 // TransactionInfoResponse represents a view-object of transaction
 type TransactionInfoResponse struct {
-	Id                   int64                                    `json:"id,string"`
-	TimeSequenceId       int64                                    `json:"timeSequenceId,string"`
-	Type                 TransactionType                          `json:"type"`
-	CategoryId           int64                                    `json:"categoryId,string"`
-	Category             *TransactionCategoryInfoResponse         `json:"category,omitempty"`
-	Time                 int64                                    `json:"time"`
-	UtcOffset            int16                                    `json:"utcOffset"`
-	SourceAccountId      int64                                    `json:"sourceAccountId,string"`
-	SourceAccount        *AccountInfoResponse                     `json:"sourceAccount,omitempty"`
-	DestinationAccountId int64                                    `json:"destinationAccountId,string,omitempty"`
-	DestinationAccount   *AccountInfoResponse                     `json:"destinationAccount,omitempty"`
-	SourceAmount         int64                                    `json:"sourceAmount"`
-	DestinationAmount    int64                                    `json:"destinationAmount,omitempty"`
-	HideAmount           bool                                     `json:"hideAmount"`
-	TagIds               []string                                 `json:"tagIds"`
-	Tags                 []*TransactionTagInfoResponse            `json:"tags,omitempty"`
-	Pictures             TransactionPictureInfoBasicResponseSlice `json:"pictures,omitempty"`
-	Comment              string                                   `json:"comment"`
-	GeoLocation          *TransactionGeoLocationResponse          `json:"geoLocation,omitempty"`
-	Editable             bool                                     `json:"editable"`
+	Id                   int64                                       `json:"id,string"`
+	LedgerId             int64                                       `json:"ledgerId,string"`
+	CreatorUid           int64                                       `json:"creatorUid,string"`
+	TimeSequenceId       int64                                       `json:"timeSequenceId,string"`
+	Type                 TransactionType                             `json:"type"`
+	CategoryId           int64                                       `json:"categoryId,string"`
+	Category             *TransactionCategoryInfoResponse            `json:"category,omitempty"`
+	Time                 int64                                       `json:"time"`
+	UtcOffset            int16                                       `json:"utcOffset"`
+	SourceAccountId      int64                                       `json:"sourceAccountId,string"`
+	SourceAccount        *AccountInfoResponse                        `json:"sourceAccount,omitempty"`
+	DestinationAccountId int64                                       `json:"destinationAccountId,string,omitempty"`
+	DestinationAccount   *AccountInfoResponse                        `json:"destinationAccount,omitempty"`
+	SourceAmount         int64                                       `json:"sourceAmount"`
+	DestinationAmount    int64                                       `json:"destinationAmount,omitempty"`
+	HideAmount           bool                                        `json:"hideAmount"`
+	TagIds               []string                                    `json:"tagIds"`
+	Tags                 []*TransactionTagInfoResponse               `json:"tags,omitempty"`
+	Pictures             TransactionPictureInfoBasicResponseSlice    `json:"pictures,omitempty"`
+	Attachments          TransactionAttachmentInfoBasicResponseSlice `json:"attachments,omitempty"`
+	Comment              string                                      `json:"comment"`
+	GeoLocation          *TransactionGeoLocationResponse             `json:"geoLocation,omitempty"`
+	EventId              int64                                       `json:"eventId,string,omitempty"`
+	CustomFieldValues    []*TransactionCustomFieldValueResponse      `json:"customFieldValues,omitempty"`
+	Editable             bool                                        `json:"editable"`
 }
 
 // TransactionCountResponse represents transaction count response
@@ -490,7 +518,32 @@ func ParseTransactionTagFilter(tagFilterStr string) ([]*TransactionTagFilter, er
 		textualTagIds := strings.Split(tagFilterItem[1], ",")
 		tagIds := make([]int64, 0, len(textualTagIds))
 
+		var tagGroupIds []int64
+		var subtreeTagIds []int64
+
 		for _, tagIdStr := range textualTagIds {
+			if strings.HasPrefix(tagIdStr, TransactionTagFilterTagGroupPrefix) {
+				tagGroupId, err := utils.StringToInt64(tagIdStr[len(TransactionTagFilterTagGroupPrefix):])
+
+				if err != nil {
+					return nil, errs.ErrTransactionTagGroupIdInvalid
+				}
+
+				tagGroupIds = append(tagGroupIds, tagGroupId)
+				continue
+			}
+
+			if strings.HasPrefix(tagIdStr, TransactionTagFilterTagSubtreePrefix) {
+				tagId, err := utils.StringToInt64(tagIdStr[len(TransactionTagFilterTagSubtreePrefix):])
+
+				if err != nil {
+					return nil, errs.ErrTransactionTagIdInvalid
+				}
+
+				subtreeTagIds = append(subtreeTagIds, tagId)
+				continue
+			}
+
 			tagId, err := utils.StringToInt64(tagIdStr)
 
 			if err != nil {
@@ -501,8 +554,10 @@ func ParseTransactionTagFilter(tagFilterStr string) ([]*TransactionTagFilter, er
 		}
 
 		transactionTagFilter := &TransactionTagFilter{
-			TagIds: tagIds,
-			Type:   TransactionTagFilterType(tagFilterType),
+			TagIds:        tagIds,
+			TagGroupIds:   tagGroupIds,
+			SubtreeTagIds: subtreeTagIds,
+			Type:          TransactionTagFilterType(tagFilterType),
 		}
 
 		transactionTagFilters = append(transactionTagFilters, transactionTagFilter)
@@ -566,6 +621,8 @@ func (t *Transaction) ToTransactionInfoResponse(tagIds []int64, editable bool) *
 
 	return &TransactionInfoResponse{
 		Id:                   t.TransactionId,
+		LedgerId:             t.LedgerId,
+		CreatorUid:           t.CreatorUid,
 		TimeSequenceId:       t.TransactionTime,
 		Type:                 transactionType,
 		CategoryId:           t.CategoryId,
@@ -579,6 +636,7 @@ func (t *Transaction) ToTransactionInfoResponse(tagIds []int64, editable bool) *
 		TagIds:               utils.Int64ArrayToStringArray(tagIds),
 		Comment:              t.Comment,
 		GeoLocation:          geoLocation,
+		EventId:              t.EventId,
 		Editable:             editable,
 	}
 }
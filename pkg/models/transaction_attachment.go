@@ -0,0 +1,192 @@
+package models
+
+import (
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const TransactionAttachmentNewAttachmentTransactionId = int64(0)
+const MaximumAttachmentsCountOfTransaction = 10
+const TransactionAttachmentThumbnailFileExtension = "thumbnail.jpg"
+
+const maximumTransactionAttachmentFileNameLength = 255
+
+// TransactionAttachmentFileType represents the file type of transaction attachment
+type TransactionAttachmentFileType byte
+
+// Transaction attachment file types
+const (
+	TRANSACTION_ATTACHMENT_FILE_TYPE_IMAGE    TransactionAttachmentFileType = 1
+	TRANSACTION_ATTACHMENT_FILE_TYPE_PDF      TransactionAttachmentFileType = 2
+	TRANSACTION_ATTACHMENT_FILE_TYPE_DOCUMENT TransactionAttachmentFileType = 3
+	TRANSACTION_ATTACHMENT_FILE_TYPE_TEXT     TransactionAttachmentFileType = 4
+	TRANSACTION_ATTACHMENT_FILE_TYPE_ARCHIVE  TransactionAttachmentFileType = 5
+)
+
+type transactionAttachmentFileFormat struct {
+	fileType            TransactionAttachmentFileType
+	contentType         string
+	sniffedContentTypes []string
+}
+
+var transactionAttachmentFileFormats = map[string]*transactionAttachmentFileFormat{
+	"jpg":  {TRANSACTION_ATTACHMENT_FILE_TYPE_IMAGE, "image/jpeg", []string{"image/jpeg"}},
+	"jpeg": {TRANSACTION_ATTACHMENT_FILE_TYPE_IMAGE, "image/jpeg", []string{"image/jpeg"}},
+	"png":  {TRANSACTION_ATTACHMENT_FILE_TYPE_IMAGE, "image/png", []string{"image/png"}},
+	"gif":  {TRANSACTION_ATTACHMENT_FILE_TYPE_IMAGE, "image/gif", []string{"image/gif"}},
+	"webp": {TRANSACTION_ATTACHMENT_FILE_TYPE_IMAGE, "image/webp", []string{"image/webp"}},
+	"pdf":  {TRANSACTION_ATTACHMENT_FILE_TYPE_PDF, "application/pdf", []string{"application/pdf"}},
+	"doc":  {TRANSACTION_ATTACHMENT_FILE_TYPE_DOCUMENT, "application/msword", []string{utils.OleCompoundFileContentType}},
+	"xls":  {TRANSACTION_ATTACHMENT_FILE_TYPE_DOCUMENT, "application/vnd.ms-excel", []string{utils.OleCompoundFileContentType}},
+	"ppt":  {TRANSACTION_ATTACHMENT_FILE_TYPE_DOCUMENT, "application/vnd.ms-powerpoint", []string{utils.OleCompoundFileContentType}},
+	"docx": {TRANSACTION_ATTACHMENT_FILE_TYPE_DOCUMENT, "application/vnd.openxmlformats-officedocument.wordprocessingml.document", []string{"application/zip"}},
+	"xlsx": {TRANSACTION_ATTACHMENT_FILE_TYPE_DOCUMENT, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", []string{"application/zip"}},
+	"pptx": {TRANSACTION_ATTACHMENT_FILE_TYPE_DOCUMENT, "application/vnd.openxmlformats-officedocument.presentationml.presentation", []string{"application/zip"}},
+	"odt":  {TRANSACTION_ATTACHMENT_FILE_TYPE_DOCUMENT, "application/vnd.oasis.opendocument.text", []string{"application/zip"}},
+	"ods":  {TRANSACTION_ATTACHMENT_FILE_TYPE_DOCUMENT, "application/vnd.oasis.opendocument.spreadsheet", []string{"application/zip"}},
+	"txt":  {TRANSACTION_ATTACHMENT_FILE_TYPE_TEXT, "text/plain; charset=utf-8", []string{"text/plain"}},
+	"csv":  {TRANSACTION_ATTACHMENT_FILE_TYPE_TEXT, "text/csv; charset=utf-8", []string{"text/plain"}},
+	"zip":  {TRANSACTION_ATTACHMENT_FILE_TYPE_ARCHIVE, "application/zip", []string{"application/zip"}},
+}
+
+// TransactionAttachment represents transaction attachment file info stored in database
+type TransactionAttachment struct {
+	Uid             int64                         `xorm:"INDEX(IDX_transaction_attachment_uid_deleted_transaction_id_attachment_id) INDEX(IDX_transaction_attachment_uid_deleted_attachment_id) NOT NULL"`
+	Deleted         bool                          `xorm:"INDEX(IDX_transaction_attachment_uid_deleted_transaction_id_attachment_id) INDEX(IDX_transaction_attachment_uid_deleted_attachment_id) NOT NULL"`
+	TransactionId   int64                         `xorm:"INDEX(IDX_transaction_attachment_uid_deleted_transaction_id_attachment_id) NOT NULL"`
+	AttachmentId    int64                         `xorm:"PK INDEX(IDX_transaction_attachment_uid_deleted_transaction_id_attachment_id) INDEX(IDX_transaction_attachment_uid_deleted_attachment_id)"`
+	FileType        TransactionAttachmentFileType `xorm:"NOT NULL"`
+	FileName        string                        `xorm:"VARCHAR(255) NOT NULL"`
+	FileExtension   string                        `xorm:"VARCHAR(10) NOT NULL"`
+	FileSize        int64                         `xorm:"NOT NULL"`
+	HasThumbnail    bool                          `xorm:"NOT NULL"`
+	CreatedIp       string                        `xorm:"VARCHAR(39)"`
+	CreatedUnixTime int64
+	UpdatedUnixTime int64
+	DeletedUnixTime int64
+}
+
+// TransactionAttachmentUnusedDeleteRequest represents all parameters of unused transaction attachment deleting request
+type TransactionAttachmentUnusedDeleteRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// TransactionAttachmentInfoBasicResponse represents a view-object of transaction attachment basic info
+type TransactionAttachmentInfoBasicResponse struct {
+	AttachmentId int64                         `json:"attachmentId,string"`
+	FileName     string                        `json:"fileName"`
+	FileType     TransactionAttachmentFileType `json:"fileType"`
+	ContentType  string                        `json:"contentType"`
+	FileSize     int64                         `json:"fileSize"`
+	OriginalUrl  string                        `json:"originalUrl"`
+	ThumbnailUrl string                        `json:"thumbnailUrl,omitempty"`
+}
+
+// GetContentType returns the content type of the transaction attachment
+func (a *TransactionAttachment) GetContentType() string {
+	_, contentType := GetTransactionAttachmentFileTypeAndContentType(a.FileExtension)
+	return contentType
+}
+
+// ToTransactionAttachmentInfoBasicResponse returns a view-object according to database model
+func (a *TransactionAttachment) ToTransactionAttachmentInfoBasicResponse(originalUrl string, thumbnailUrl string) *TransactionAttachmentInfoBasicResponse {
+	return &TransactionAttachmentInfoBasicResponse{
+		AttachmentId: a.AttachmentId,
+		FileName:     a.FileName,
+		FileType:     a.FileType,
+		ContentType:  a.GetContentType(),
+		FileSize:     a.FileSize,
+		OriginalUrl:  originalUrl,
+		ThumbnailUrl: thumbnailUrl,
+	}
+}
+
+// GetTransactionAttachmentFileTypeAndContentType returns the attachment file type and content type of specified file extension,
+// or returns zero and empty when the file extension is not supported
+func GetTransactionAttachmentFileTypeAndContentType(fileExtension string) (TransactionAttachmentFileType, string) {
+	fileFormat, exists := transactionAttachmentFileFormats[strings.ToLower(fileExtension)]
+
+	if !exists {
+		return 0, ""
+	}
+
+	return fileFormat.fileType, fileFormat.contentType
+}
+
+// IsTransactionAttachmentContentMatched returns whether the content type sniffed from the file data matches the file extension
+func IsTransactionAttachmentContentMatched(fileExtension string, sniffedContentType string) bool {
+	fileFormat, exists := transactionAttachmentFileFormats[strings.ToLower(fileExtension)]
+
+	if !exists {
+		return false
+	}
+
+	for i := 0; i < len(fileFormat.sniffedContentTypes); i++ {
+		if fileFormat.sniffedContentTypes[i] == sniffedContentType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetTransactionAttachmentFileName returns the file name which can be stored and returned to client safely,
+// the directory part, control characters and quotation marks are removed, and the length is limited
+func GetTransactionAttachmentFileName(fileName string) string {
+	fileName = filepath.Base(strings.ReplaceAll(fileName, "\\", "/"))
+
+	if fileName == "." || fileName == "/" {
+		return ""
+	}
+
+	fileName = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == '"' || r == utf8.RuneError {
+			return -1
+		}
+
+		return r
+	}, fileName)
+
+	fileName = strings.TrimSpace(fileName)
+
+	if len(fileName) <= maximumTransactionAttachmentFileNameLength {
+		return fileName
+	}
+
+	extension := filepath.Ext(fileName)
+
+	if len(extension) > 11 {
+		extension = ""
+	}
+
+	baseName := fileName[:len(fileName)-len(extension)]
+
+	for len(baseName)+len(extension) > maximumTransactionAttachmentFileNameLength {
+		_, size := utf8.DecodeLastRuneInString(baseName)
+		baseName = baseName[:len(baseName)-size]
+	}
+
+	return baseName + extension
+}
+
+// TransactionAttachmentInfoBasicResponseSlice represents the slice data structure of TransactionAttachmentInfoBasicResponse
+type TransactionAttachmentInfoBasicResponseSlice []*TransactionAttachmentInfoBasicResponse
+
+// Len returns the count of items
+func (s TransactionAttachmentInfoBasicResponseSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s TransactionAttachmentInfoBasicResponseSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s TransactionAttachmentInfoBasicResponseSlice) Less(i, j int) bool {
+	return s[i].AttachmentId < s[j].AttachmentId
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"sort"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+func TestGetTransactionAttachmentFileTypeAndContentType(t *testing.T) {
+	fileType, contentType := GetTransactionAttachmentFileTypeAndContentType("PDF")
+	assert.Equal(t, TRANSACTION_ATTACHMENT_FILE_TYPE_PDF, fileType)
+	assert.Equal(t, "application/pdf", contentType)
+
+	fileType, contentType = GetTransactionAttachmentFileTypeAndContentType("xlsx")
+	assert.Equal(t, TRANSACTION_ATTACHMENT_FILE_TYPE_DOCUMENT, fileType)
+	assert.Equal(t, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", contentType)
+
+	fileType, contentType = GetTransactionAttachmentFileTypeAndContentType("exe")
+	assert.Equal(t, TransactionAttachmentFileType(0), fileType)
+	assert.Equal(t, "", contentType)
+}
+
+func TestIsTransactionAttachmentContentMatched(t *testing.T) {
+	assert.True(t, IsTransactionAttachmentContentMatched("pdf", "application/pdf"))
+	assert.True(t, IsTransactionAttachmentContentMatched("docx", "application/zip"))
+	assert.True(t, IsTransactionAttachmentContentMatched("XLS", utils.OleCompoundFileContentType))
+	assert.True(t, IsTransactionAttachmentContentMatched("csv", "text/plain"))
+
+	assert.False(t, IsTransactionAttachmentContentMatched("pdf", "application/zip"))
+	assert.False(t, IsTransactionAttachmentContentMatched("txt", "application/octet-stream"))
+	assert.False(t, IsTransactionAttachmentContentMatched("exe", "application/octet-stream"))
+}
+
+func TestGetTransactionAttachmentFileName(t *testing.T) {
+	assert.Equal(t, "invoice.pdf", GetTransactionAttachmentFileName("invoice.pdf"))
+	assert.Equal(t, "invoice.pdf", GetTransactionAttachmentFileName("../../etc/invoice.pdf"))
+	assert.Equal(t, "invoice.pdf", GetTransactionAttachmentFileName("C:\\Users\\test\\invoice.pdf"))
+	assert.Equal(t, "invoice 2024.pdf", GetTransactionAttachmentFileName(" \"invoice\r\n 2024\".pdf"))
+	assert.Equal(t, "", GetTransactionAttachmentFileName(""))
+
+	longFileName := GetTransactionAttachmentFileName(strings.Repeat("发票", 100) + ".pdf")
+	assert.True(t, len(longFileName) <= 255)
+	assert.True(t, strings.HasSuffix(longFileName, ".pdf"))
+	assert.True(t, utf8.ValidString(longFileName))
+}
+
+func TestTransactionAttachmentInfoBasicResponseSliceLess(t *testing.T) {
+	var attachmentInfoSlice TransactionAttachmentInfoBasicResponseSlice
+	attachmentInfoSlice = append(attachmentInfoSlice, &TransactionAttachmentInfoBasicResponse{
+		AttachmentId: 2,
+	})
+	attachmentInfoSlice = append(attachmentInfoSlice, &TransactionAttachmentInfoBasicResponse{
+		AttachmentId: 3,
+	})
+	attachmentInfoSlice = append(attachmentInfoSlice, &TransactionAttachmentInfoBasicResponse{
+		AttachmentId: 1,
+	})
+
+	sort.Sort(attachmentInfoSlice)
+
+	assert.Equal(t, int64(1), attachmentInfoSlice[0].AttachmentId)
+	assert.Equal(t, int64(2), attachmentInfoSlice[1].AttachmentId)
+	assert.Equal(t, int64(3), attachmentInfoSlice[2].AttachmentId)
+}
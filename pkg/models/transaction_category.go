@@ -16,8 +16,9 @@ const (
 // TransactionCategory represents transaction category data stored in database
 type TransactionCategory struct {
 	CategoryId       int64                   `xorm:"PK"`
-	Uid              int64                   `xorm:"INDEX(IDX_category_uid_deleted_type_parent_category_id_order) NOT NULL"`
-	Deleted          bool                    `xorm:"INDEX(IDX_category_uid_deleted_type_parent_category_id_order) NOT NULL"`
+	Uid              int64                   `xorm:"INDEX(IDX_category_uid_deleted_type_parent_category_id_order) INDEX(IDX_category_uid_deleted_ledger_id) NOT NULL"`
+	Deleted          bool                    `xorm:"INDEX(IDX_category_uid_deleted_type_parent_category_id_order) INDEX(IDX_category_uid_deleted_ledger_id) NOT NULL"`
+	LedgerId         int64                   `xorm:"INDEX(IDX_category_uid_deleted_ledger_id) NOT NULL DEFAULT 0"`
 	Type             TransactionCategoryType `xorm:"INDEX(IDX_category_uid_deleted_type_parent_category_id_order) NOT NULL"`
 	ParentCategoryId int64                   `xorm:"INDEX(IDX_category_uid_deleted_type_parent_category_id_order) NOT NULL"`
 	Name             string                  `xorm:"VARCHAR(64) NOT NULL"`
@@ -101,9 +102,16 @@ type TransactionCategoryDeleteRequest struct {
 	Id int64 `json:"id,string" binding:"required,min=1"`
 }
 
+// TransactionCategoryMergeRequest represents all parameters of transaction category merging request
+type TransactionCategoryMergeRequest struct {
+	FromCategoryId int64 `json:"fromCategoryId,string" binding:"required,min=1"`
+	ToCategoryId   int64 `json:"toCategoryId,string" binding:"required,min=1"`
+}
+
 // TransactionCategoryInfoResponse represents a view-object of transaction category
 type TransactionCategoryInfoResponse struct {
 	Id            int64                                `json:"id,string"`
+	LedgerId      int64                                `json:"ledgerId,string"`
 	Name          string                               `json:"name"`
 	ParentId      int64                                `json:"parentId,string"`
 	Type          TransactionCategoryType              `json:"type"`
@@ -119,6 +127,7 @@ type TransactionCategoryInfoResponse struct {
 func (c *TransactionCategory) ToTransactionCategoryInfoResponse() *TransactionCategoryInfoResponse {
 	return &TransactionCategoryInfoResponse{
 		Id:           c.CategoryId,
+		LedgerId:     c.LedgerId,
 		Name:         c.Name,
 		ParentId:     c.ParentCategoryId,
 		Type:         c.Type,
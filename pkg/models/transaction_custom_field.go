@@ -0,0 +1,375 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// MaximumCustomFieldsCountPerLedger represents the maximum count of transaction custom fields in one ledger
+const MaximumCustomFieldsCountPerLedger = 50
+
+// MaximumCustomFieldOptionsCount represents the maximum count of options of an enum transaction custom field
+const MaximumCustomFieldOptionsCount = 50
+
+// TransactionCustomFieldOptionSeparator represents the separator of options stored in database
+const TransactionCustomFieldOptionSeparator = "\n"
+
+// TransactionCustomFieldDateFormat represents the textual format of date custom field value
+const TransactionCustomFieldDateFormat = "2006-01-02"
+
+// TransactionCustomFieldType represents transaction custom field type
+type TransactionCustomFieldType byte
+
+// Transaction custom field types
+const (
+	TRANSACTION_CUSTOM_FIELD_TYPE_TEXT    TransactionCustomFieldType = 1
+	TRANSACTION_CUSTOM_FIELD_TYPE_NUMBER  TransactionCustomFieldType = 2
+	TRANSACTION_CUSTOM_FIELD_TYPE_DATE    TransactionCustomFieldType = 3
+	TRANSACTION_CUSTOM_FIELD_TYPE_ENUM    TransactionCustomFieldType = 4
+	TRANSACTION_CUSTOM_FIELD_TYPE_BOOLEAN TransactionCustomFieldType = 5
+)
+
+// IsValid returns whether this custom field type is valid
+func (t TransactionCustomFieldType) IsValid() bool {
+	return t >= TRANSACTION_CUSTOM_FIELD_TYPE_TEXT && t <= TRANSACTION_CUSTOM_FIELD_TYPE_BOOLEAN
+}
+
+// IsNumeric returns whether the values of this custom field type are compared by number value
+func (t TransactionCustomFieldType) IsNumeric() bool {
+	return t == TRANSACTION_CUSTOM_FIELD_TYPE_NUMBER || t == TRANSACTION_CUSTOM_FIELD_TYPE_DATE || t == TRANSACTION_CUSTOM_FIELD_TYPE_BOOLEAN
+}
+
+// TransactionCustomFieldFilterOperator represents the operator of transaction custom field filter
+type TransactionCustomFieldFilterOperator string
+
+// Transaction custom field filter operators
+const (
+	TRANSACTION_CUSTOM_FIELD_FILTER_EQUAL            TransactionCustomFieldFilterOperator = "eq"
+	TRANSACTION_CUSTOM_FIELD_FILTER_NOT_EQUAL        TransactionCustomFieldFilterOperator = "ne"
+	TRANSACTION_CUSTOM_FIELD_FILTER_GREATER          TransactionCustomFieldFilterOperator = "gt"
+	TRANSACTION_CUSTOM_FIELD_FILTER_GREATER_OR_EQUAL TransactionCustomFieldFilterOperator = "ge"
+	TRANSACTION_CUSTOM_FIELD_FILTER_LESS             TransactionCustomFieldFilterOperator = "lt"
+	TRANSACTION_CUSTOM_FIELD_FILTER_LESS_OR_EQUAL    TransactionCustomFieldFilterOperator = "le"
+	TRANSACTION_CUSTOM_FIELD_FILTER_CONTAINS         TransactionCustomFieldFilterOperator = "contains"
+	TRANSACTION_CUSTOM_FIELD_FILTER_HAS_VALUE        TransactionCustomFieldFilterOperator = "has"
+	TRANSACTION_CUSTOM_FIELD_FILTER_HAS_NO_VALUE     TransactionCustomFieldFilterOperator = "none"
+)
+
+// TransactionCustomField represents transaction custom field definition stored in database
+type TransactionCustomField struct {
+	FieldId         int64                      `xorm:"PK"`
+	Uid             int64                      `xorm:"INDEX(IDX_custom_field_uid_deleted_ledger_id_order) NOT NULL"`
+	Deleted         bool                       `xorm:"INDEX(IDX_custom_field_uid_deleted_ledger_id_order) NOT NULL"`
+	LedgerId        int64                      `xorm:"INDEX(IDX_custom_field_uid_deleted_ledger_id_order) NOT NULL DEFAULT 0"`
+	Name            string                     `xorm:"VARCHAR(64) NOT NULL"`
+	Type            TransactionCustomFieldType `xorm:"NOT NULL"`
+	Options         string                     `xorm:"VARCHAR(4096) NOT NULL"`
+	DisplayOrder    int32                      `xorm:"INDEX(IDX_custom_field_uid_deleted_ledger_id_order) NOT NULL"`
+	Hidden          bool                       `xorm:"NOT NULL"`
+	CreatedUnixTime int64
+	UpdatedUnixTime int64
+	DeletedUnixTime int64
+}
+
+// TransactionCustomFieldValue represents the value of a transaction custom field stored in database,
+// number value stores the amount in hundredths for number field, yyyymmdd for date field, 1 or 0 for boolean field
+// and the index (starting from 1) of the option for enum field
+type TransactionCustomFieldValue struct {
+	TransactionId   int64  `xorm:"PK INDEX(IDX_custom_field_value_uid_deleted_transaction_id)"`
+	FieldId         int64  `xorm:"PK INDEX(IDX_custom_field_value_uid_deleted_field_id_number_value)"`
+	Uid             int64  `xorm:"INDEX(IDX_custom_field_value_uid_deleted_transaction_id) INDEX(IDX_custom_field_value_uid_deleted_field_id_number_value) NOT NULL"`
+	Deleted         bool   `xorm:"INDEX(IDX_custom_field_value_uid_deleted_transaction_id) INDEX(IDX_custom_field_value_uid_deleted_field_id_number_value) NOT NULL"`
+	TextValue       string `xorm:"VARCHAR(255) NOT NULL"`
+	NumberValue     int64  `xorm:"INDEX(IDX_custom_field_value_uid_deleted_field_id_number_value) NOT NULL"`
+	CreatedUnixTime int64
+	UpdatedUnixTime int64
+	DeletedUnixTime int64
+}
+
+// TransactionCustomFieldGetRequest represents all parameters of transaction custom field getting request
+type TransactionCustomFieldGetRequest struct {
+	Id int64 `form:"id,string" binding:"required,min=1"`
+}
+
+// TransactionCustomFieldCreateRequest represents all parameters of transaction custom field creation request
+type TransactionCustomFieldCreateRequest struct {
+	Name    string                     `json:"name" binding:"required,notBlank,max=64"`
+	Type    TransactionCustomFieldType `json:"type" binding:"required"`
+	Options []string                   `json:"options"`
+}
+
+// TransactionCustomFieldModifyRequest represents all parameters of transaction custom field modification request
+type TransactionCustomFieldModifyRequest struct {
+	Id      int64    `json:"id,string" binding:"required,min=1"`
+	Name    string   `json:"name" binding:"required,notBlank,max=64"`
+	Options []string `json:"options"`
+	Hidden  bool     `json:"hidden"`
+}
+
+// TransactionCustomFieldMoveRequest represents all parameters of transaction custom field moving request
+type TransactionCustomFieldMoveRequest struct {
+	NewDisplayOrders []*TransactionCustomFieldNewDisplayOrderRequest `json:"newDisplayOrders" binding:"required,min=1"`
+}
+
+// TransactionCustomFieldNewDisplayOrderRequest represents a data pair of id and display order
+type TransactionCustomFieldNewDisplayOrderRequest struct {
+	Id           int64 `json:"id,string" binding:"required,min=1"`
+	DisplayOrder int32 `json:"displayOrder"`
+}
+
+// TransactionCustomFieldDeleteRequest represents all parameters of transaction custom field deleting request
+type TransactionCustomFieldDeleteRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// TransactionCustomFieldValueRequest represents the value of one custom field in transaction creation or modification request
+type TransactionCustomFieldValueRequest struct {
+	FieldId int64  `json:"fieldId,string" binding:"required,min=1"`
+	Value   string `json:"value" binding:"max=255"`
+}
+
+// TransactionCustomFieldInfoResponse represents a view-object of transaction custom field
+type TransactionCustomFieldInfoResponse struct {
+	Id           int64                      `json:"id,string"`
+	LedgerId     int64                      `json:"ledgerId,string"`
+	Name         string                     `json:"name"`
+	Type         TransactionCustomFieldType `json:"type"`
+	Options      []string                   `json:"options,omitempty"`
+	DisplayOrder int32                      `json:"displayOrder"`
+	Hidden       bool                       `json:"hidden"`
+}
+
+// TransactionCustomFieldValueResponse represents a view-object of transaction custom field value
+type TransactionCustomFieldValueResponse struct {
+	FieldId int64  `json:"fieldId,string"`
+	Value   string `json:"value"`
+}
+
+// TransactionCustomFieldFilter represents a filter condition of transaction custom field,
+// the text value and number value are filled by Resolve according to the field definition
+type TransactionCustomFieldFilter struct {
+	FieldId     int64
+	Operator    TransactionCustomFieldFilterOperator
+	Value       string
+	IsNumeric   bool
+	TextValue   string
+	NumberValue int64
+}
+
+// GetOptions returns the options of enum custom field
+func (f *TransactionCustomField) GetOptions() []string {
+	if f.Options == "" {
+		return nil
+	}
+
+	return strings.Split(f.Options, TransactionCustomFieldOptionSeparator)
+}
+
+// SetOptions validates and sets the options of custom field, only enum custom field can have options
+func (f *TransactionCustomField) SetOptions(options []string) error {
+	if f.Type != TRANSACTION_CUSTOM_FIELD_TYPE_ENUM {
+		if len(options) > 0 {
+			return errs.ErrTransactionCustomFieldOptionsInvalid
+		}
+
+		f.Options = ""
+		return nil
+	}
+
+	if len(options) < 1 || len(options) > MaximumCustomFieldOptionsCount {
+		return errs.ErrTransactionCustomFieldOptionsInvalid
+	}
+
+	existedOptions := make(map[string]bool, len(options))
+	trimmedOptions := make([]string, 0, len(options))
+
+	for i := 0; i < len(options); i++ {
+		option := strings.TrimSpace(options[i])
+
+		if option == "" || len(option) > 255 || strings.Contains(option, TransactionCustomFieldOptionSeparator) || existedOptions[option] {
+			return errs.ErrTransactionCustomFieldOptionsInvalid
+		}
+
+		existedOptions[option] = true
+		trimmedOptions = append(trimmedOptions, option)
+	}
+
+	joinedOptions := strings.Join(trimmedOptions, TransactionCustomFieldOptionSeparator)
+
+	if len(joinedOptions) > 4096 {
+		return errs.ErrTransactionCustomFieldOptionsInvalid
+	}
+
+	f.Options = joinedOptions
+	return nil
+}
+
+// ParseValue parses the textual value to the normalized text value and the number value according to the field type
+func (f *TransactionCustomField) ParseValue(value string) (string, int64, error) {
+	value = strings.TrimSpace(value)
+
+	switch f.Type {
+	case TRANSACTION_CUSTOM_FIELD_TYPE_TEXT:
+		if len(value) > 255 {
+			return "", 0, errs.ErrTransactionCustomFieldValueInvalid
+		}
+
+		return value, 0, nil
+	case TRANSACTION_CUSTOM_FIELD_TYPE_NUMBER:
+		numberValue, err := utils.ParseAmount(value)
+
+		if err != nil || value == "" {
+			return "", 0, errs.ErrTransactionCustomFieldValueInvalid
+		}
+
+		return utils.FormatAmount(numberValue), numberValue, nil
+	case TRANSACTION_CUSTOM_FIELD_TYPE_DATE:
+		date, err := time.Parse(TransactionCustomFieldDateFormat, value)
+
+		if err != nil {
+			return "", 0, errs.ErrTransactionCustomFieldValueInvalid
+		}
+
+		return date.Format(TransactionCustomFieldDateFormat), int64(date.Year()*10000 + int(date.Month())*100 + date.Day()), nil
+	case TRANSACTION_CUSTOM_FIELD_TYPE_ENUM:
+		options := f.GetOptions()
+
+		for i := 0; i < len(options); i++ {
+			if options[i] == value {
+				return value, int64(i + 1), nil
+			}
+		}
+
+		return "", 0, errs.ErrTransactionCustomFieldValueInvalid
+	case TRANSACTION_CUSTOM_FIELD_TYPE_BOOLEAN:
+		switch strings.ToLower(value) {
+		case "true", "1", "yes":
+			return "true", 1, nil
+		case "false", "0", "no":
+			return "false", 0, nil
+		}
+
+		return "", 0, errs.ErrTransactionCustomFieldValueInvalid
+	}
+
+	return "", 0, errs.ErrTransactionCustomFieldTypeInvalid
+}
+
+// ToTransactionCustomFieldInfoResponse returns a view-object according to database model
+func (f *TransactionCustomField) ToTransactionCustomFieldInfoResponse() *TransactionCustomFieldInfoResponse {
+	return &TransactionCustomFieldInfoResponse{
+		Id:           f.FieldId,
+		LedgerId:     f.LedgerId,
+		Name:         f.Name,
+		Type:         f.Type,
+		Options:      f.GetOptions(),
+		DisplayOrder: f.DisplayOrder,
+		Hidden:       f.Hidden,
+	}
+}
+
+// ToTransactionCustomFieldValueResponse returns a view-object according to database model
+func (v *TransactionCustomFieldValue) ToTransactionCustomFieldValueResponse() *TransactionCustomFieldValueResponse {
+	return &TransactionCustomFieldValueResponse{
+		FieldId: v.FieldId,
+		Value:   v.TextValue,
+	}
+}
+
+// Resolve validates the operator and fills the typed value of the filter according to the field definition
+func (f *TransactionCustomFieldFilter) Resolve(field *TransactionCustomField) error {
+	if field == nil || field.FieldId != f.FieldId {
+		return errs.ErrTransactionCustomFieldNotFound
+	}
+
+	f.IsNumeric = field.Type.IsNumeric()
+
+	switch f.Operator {
+	case TRANSACTION_CUSTOM_FIELD_FILTER_HAS_VALUE, TRANSACTION_CUSTOM_FIELD_FILTER_HAS_NO_VALUE:
+		return nil
+	case TRANSACTION_CUSTOM_FIELD_FILTER_CONTAINS:
+		if field.Type != TRANSACTION_CUSTOM_FIELD_TYPE_TEXT || f.Value == "" {
+			return errs.ErrTransactionCustomFieldFilterInvalid
+		}
+
+		f.TextValue = f.Value
+		return nil
+	case TRANSACTION_CUSTOM_FIELD_FILTER_GREATER, TRANSACTION_CUSTOM_FIELD_FILTER_GREATER_OR_EQUAL,
+		TRANSACTION_CUSTOM_FIELD_FILTER_LESS, TRANSACTION_CUSTOM_FIELD_FILTER_LESS_OR_EQUAL:
+		if field.Type != TRANSACTION_CUSTOM_FIELD_TYPE_NUMBER && field.Type != TRANSACTION_CUSTOM_FIELD_TYPE_DATE {
+			return errs.ErrTransactionCustomFieldFilterInvalid
+		}
+	case TRANSACTION_CUSTOM_FIELD_FILTER_EQUAL, TRANSACTION_CUSTOM_FIELD_FILTER_NOT_EQUAL:
+	default:
+		return errs.ErrTransactionCustomFieldFilterInvalid
+	}
+
+	textValue, numberValue, err := field.ParseValue(f.Value)
+
+	if err != nil {
+		return errs.ErrTransactionCustomFieldFilterInvalid
+	}
+
+	f.TextValue = textValue
+	f.NumberValue = numberValue
+
+	return nil
+}
+
+// ParseTransactionCustomFieldFilter parses transaction custom field filters from string like "fieldId:operator:value;..."
+func ParseTransactionCustomFieldFilter(customFieldFilterStr string) ([]*TransactionCustomFieldFilter, error) {
+	if customFieldFilterStr == "" {
+		return []*TransactionCustomFieldFilter{}, nil
+	}
+
+	filters := strings.Split(customFieldFilterStr, ";")
+	customFieldFilters := make([]*TransactionCustomFieldFilter, 0, len(filters))
+
+	for _, filter := range filters {
+		filterItems := strings.SplitN(filter, ":", 3)
+
+		if len(filterItems) < 2 {
+			return nil, errs.ErrTransactionCustomFieldFilterInvalid
+		}
+
+		fieldId, err := utils.StringToInt64(filterItems[0])
+
+		if err != nil || fieldId <= 0 {
+			return nil, errs.ErrTransactionCustomFieldIdInvalid
+		}
+
+		customFieldFilter := &TransactionCustomFieldFilter{
+			FieldId:  fieldId,
+			Operator: TransactionCustomFieldFilterOperator(filterItems[1]),
+		}
+
+		if len(filterItems) == 3 {
+			customFieldFilter.Value = filterItems[2]
+		}
+
+		customFieldFilters = append(customFieldFilters, customFieldFilter)
+	}
+
+	return customFieldFilters, nil
+}
+
+// TransactionCustomFieldInfoResponseSlice represents the slice data structure of TransactionCustomFieldInfoResponse
+type TransactionCustomFieldInfoResponseSlice []*TransactionCustomFieldInfoResponse
+
+// Len returns the count of items
+func (s TransactionCustomFieldInfoResponseSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s TransactionCustomFieldInfoResponseSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s TransactionCustomFieldInfoResponseSlice) Less(i, j int) bool {
+	return s[i].DisplayOrder < s[j].DisplayOrder
+}
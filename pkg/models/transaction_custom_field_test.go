@@ -0,0 +1,153 @@
+package models
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+)
+
+func TestTransactionCustomFieldSetOptions_EnumField(t *testing.T) {
+	field := &TransactionCustomField{Type: TRANSACTION_CUSTOM_FIELD_TYPE_ENUM}
+
+	err := field.SetOptions([]string{" Alpha ", "Beta"})
+	assert.Nil(t, err)
+	assert.Equal(t, "Alpha\nBeta", field.Options)
+	assert.Equal(t, []string{"Alpha", "Beta"}, field.GetOptions())
+}
+
+func TestTransactionCustomFieldSetOptions_InvalidOptions(t *testing.T) {
+	field := &TransactionCustomField{Type: TRANSACTION_CUSTOM_FIELD_TYPE_ENUM}
+
+	assert.EqualError(t, field.SetOptions(nil), errs.ErrTransactionCustomFieldOptionsInvalid.Message)
+	assert.EqualError(t, field.SetOptions([]string{"Alpha", " "}), errs.ErrTransactionCustomFieldOptionsInvalid.Message)
+	assert.EqualError(t, field.SetOptions([]string{"Alpha", "Alpha "}), errs.ErrTransactionCustomFieldOptionsInvalid.Message)
+	assert.EqualError(t, field.SetOptions([]string{"Al\npha"}), errs.ErrTransactionCustomFieldOptionsInvalid.Message)
+
+	field = &TransactionCustomField{Type: TRANSACTION_CUSTOM_FIELD_TYPE_TEXT}
+	assert.EqualError(t, field.SetOptions([]string{"Alpha"}), errs.ErrTransactionCustomFieldOptionsInvalid.Message)
+	assert.Nil(t, field.SetOptions(nil))
+	assert.Nil(t, field.GetOptions())
+}
+
+func TestTransactionCustomFieldParseValue_Number(t *testing.T) {
+	field := &TransactionCustomField{Type: TRANSACTION_CUSTOM_FIELD_TYPE_NUMBER}
+
+	textValue, numberValue, err := field.ParseValue(" 12.3 ")
+	assert.Nil(t, err)
+	assert.Equal(t, "12.30", textValue)
+	assert.Equal(t, int64(1230), numberValue)
+
+	_, _, err = field.ParseValue("abc")
+	assert.EqualError(t, err, errs.ErrTransactionCustomFieldValueInvalid.Message)
+}
+
+func TestTransactionCustomFieldParseValue_Date(t *testing.T) {
+	field := &TransactionCustomField{Type: TRANSACTION_CUSTOM_FIELD_TYPE_DATE}
+
+	textValue, numberValue, err := field.ParseValue("2024-03-05")
+	assert.Nil(t, err)
+	assert.Equal(t, "2024-03-05", textValue)
+	assert.Equal(t, int64(20240305), numberValue)
+
+	_, _, err = field.ParseValue("2024-13-05")
+	assert.EqualError(t, err, errs.ErrTransactionCustomFieldValueInvalid.Message)
+}
+
+func TestTransactionCustomFieldParseValue_Enum(t *testing.T) {
+	field := &TransactionCustomField{Type: TRANSACTION_CUSTOM_FIELD_TYPE_ENUM, Options: "Alpha\nBeta"}
+
+	textValue, numberValue, err := field.ParseValue("Beta")
+	assert.Nil(t, err)
+	assert.Equal(t, "Beta", textValue)
+	assert.Equal(t, int64(2), numberValue)
+
+	_, _, err = field.ParseValue("Gamma")
+	assert.EqualError(t, err, errs.ErrTransactionCustomFieldValueInvalid.Message)
+}
+
+func TestTransactionCustomFieldParseValue_Boolean(t *testing.T) {
+	field := &TransactionCustomField{Type: TRANSACTION_CUSTOM_FIELD_TYPE_BOOLEAN}
+
+	textValue, numberValue, err := field.ParseValue("Yes")
+	assert.Nil(t, err)
+	assert.Equal(t, "true", textValue)
+	assert.Equal(t, int64(1), numberValue)
+
+	textValue, numberValue, err = field.ParseValue("0")
+	assert.Nil(t, err)
+	assert.Equal(t, "false", textValue)
+	assert.Equal(t, int64(0), numberValue)
+
+	_, _, err = field.ParseValue("maybe")
+	assert.EqualError(t, err, errs.ErrTransactionCustomFieldValueInvalid.Message)
+}
+
+func TestParseTransactionCustomFieldFilter(t *testing.T) {
+	filters, err := ParseTransactionCustomFieldFilter("1001:eq:a:b;1002:has")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(filters))
+	assert.Equal(t, int64(1001), filters[0].FieldId)
+	assert.Equal(t, TRANSACTION_CUSTOM_FIELD_FILTER_EQUAL, filters[0].Operator)
+	assert.Equal(t, "a:b", filters[0].Value)
+	assert.Equal(t, int64(1002), filters[1].FieldId)
+	assert.Equal(t, TRANSACTION_CUSTOM_FIELD_FILTER_HAS_VALUE, filters[1].Operator)
+
+	filters, err = ParseTransactionCustomFieldFilter("")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(filters))
+
+	_, err = ParseTransactionCustomFieldFilter("1001")
+	assert.EqualError(t, err, errs.ErrTransactionCustomFieldFilterInvalid.Message)
+
+	_, err = ParseTransactionCustomFieldFilter("abc:eq:1")
+	assert.EqualError(t, err, errs.ErrTransactionCustomFieldIdInvalid.Message)
+}
+
+func TestTransactionCustomFieldFilterResolve(t *testing.T) {
+	numberField := &TransactionCustomField{FieldId: 1001, Type: TRANSACTION_CUSTOM_FIELD_TYPE_NUMBER}
+	textField := &TransactionCustomField{FieldId: 1002, Type: TRANSACTION_CUSTOM_FIELD_TYPE_TEXT}
+
+	filter := &TransactionCustomFieldFilter{FieldId: 1001, Operator: TRANSACTION_CUSTOM_FIELD_FILTER_GREATER, Value: "1.5"}
+	assert.Nil(t, filter.Resolve(numberField))
+	assert.True(t, filter.IsNumeric)
+	assert.Equal(t, int64(150), filter.NumberValue)
+
+	filter = &TransactionCustomFieldFilter{FieldId: 1002, Operator: TRANSACTION_CUSTOM_FIELD_FILTER_CONTAINS, Value: "abc"}
+	assert.Nil(t, filter.Resolve(textField))
+	assert.False(t, filter.IsNumeric)
+	assert.Equal(t, "abc", filter.TextValue)
+
+	filter = &TransactionCustomFieldFilter{FieldId: 1002, Operator: TRANSACTION_CUSTOM_FIELD_FILTER_GREATER, Value: "abc"}
+	assert.EqualError(t, filter.Resolve(textField), errs.ErrTransactionCustomFieldFilterInvalid.Message)
+
+	filter = &TransactionCustomFieldFilter{FieldId: 1001, Operator: TRANSACTION_CUSTOM_FIELD_FILTER_EQUAL, Value: "abc"}
+	assert.EqualError(t, filter.Resolve(numberField), errs.ErrTransactionCustomFieldFilterInvalid.Message)
+
+	filter = &TransactionCustomFieldFilter{FieldId: 1001, Operator: TRANSACTION_CUSTOM_FIELD_FILTER_EQUAL, Value: "1"}
+	assert.EqualError(t, filter.Resolve(textField), errs.ErrTransactionCustomFieldNotFound.Message)
+}
+
+func TestTransactionCustomFieldInfoResponseSliceLess(t *testing.T) {
+	var customFieldRespSlice TransactionCustomFieldInfoResponseSlice
+	customFieldRespSlice = append(customFieldRespSlice, &TransactionCustomFieldInfoResponse{
+		Id:           1,
+		DisplayOrder: 3,
+	})
+	customFieldRespSlice = append(customFieldRespSlice, &TransactionCustomFieldInfoResponse{
+		Id:           2,
+		DisplayOrder: 1,
+	})
+	customFieldRespSlice = append(customFieldRespSlice, &TransactionCustomFieldInfoResponse{
+		Id:           3,
+		DisplayOrder: 2,
+	})
+
+	sort.Sort(customFieldRespSlice)
+
+	assert.Equal(t, int64(2), customFieldRespSlice[0].Id)
+	assert.Equal(t, int64(3), customFieldRespSlice[1].Id)
+	assert.Equal(t, int64(1), customFieldRespSlice[2].Id)
+}
@@ -1,6 +1,28 @@
 package models
 
 const TransactionPictureNewPictureTransactionId = int64(0)
+const TransactionPictureThumbnailFileExtension = "jpg"
+
+// TransactionPictureSize represents the size variant of transaction picture
+type TransactionPictureSize string
+
+// Transaction picture sizes
+const (
+	TRANSACTION_PICTURE_SIZE_ORIGINAL TransactionPictureSize = ""
+	TRANSACTION_PICTURE_SIZE_SMALL    TransactionPictureSize = "small"
+	TRANSACTION_PICTURE_SIZE_MEDIUM   TransactionPictureSize = "medium"
+)
+
+// TransactionPictureThumbnailSizes represents all the thumbnail sizes of transaction picture
+var TransactionPictureThumbnailSizes = []TransactionPictureSize{
+	TRANSACTION_PICTURE_SIZE_SMALL,
+	TRANSACTION_PICTURE_SIZE_MEDIUM,
+}
+
+var transactionPictureThumbnailMaxDimensions = map[TransactionPictureSize]int{
+	TRANSACTION_PICTURE_SIZE_SMALL:  256,
+	TRANSACTION_PICTURE_SIZE_MEDIUM: 1024,
+}
 
 // TransactionPictureInfo represents transaction picture file info stored in database
 type TransactionPictureInfo struct {
@@ -9,28 +31,87 @@ type TransactionPictureInfo struct {
 	TransactionId    int64  `xorm:"INDEX(IDX_transaction_picture_uid_deleted_transaction_id_picture_id) NOT NULL"`
 	PictureId        int64  `xorm:"PK INDEX(IDX_transaction_picture_uid_deleted_transaction_id_picture_id) INDEX(IDX_transaction_picture_uid_deleted_picture_id)"`
 	PictureExtension string `xorm:"VARCHAR(10) NOT NULL"`
+	ContentHash      string `xorm:"VARCHAR(64)"`
+	StoredPictureId  int64
 	CreatedIp        string `xorm:"VARCHAR(39)"`
 	CreatedUnixTime  int64
 	UpdatedUnixTime  int64
 	DeletedUnixTime  int64
 }
 
+// TransactionPictureObject represents the stored transaction picture file which is shared by all transaction pictures with the same content
+type TransactionPictureObject struct {
+	Uid              int64  `xorm:"UNIQUE(UQE_transaction_picture_object_uid_content_hash_picture_extension) INDEX(IDX_transaction_picture_object_uid_reference_count) NOT NULL"`
+	ObjectId         int64  `xorm:"PK"`
+	ContentHash      string `xorm:"UNIQUE(UQE_transaction_picture_object_uid_content_hash_picture_extension) VARCHAR(64) NOT NULL"`
+	PictureExtension string `xorm:"UNIQUE(UQE_transaction_picture_object_uid_content_hash_picture_extension) VARCHAR(10) NOT NULL"`
+	FileSize         int64  `xorm:"NOT NULL"`
+	ReferenceCount   int64  `xorm:"INDEX(IDX_transaction_picture_object_uid_reference_count) NOT NULL"`
+	CreatedUnixTime  int64
+	UpdatedUnixTime  int64
+}
+
+// TransactionPictureCleanupResult represents the result of removing orphaned transaction pictures and stored picture files
+type TransactionPictureCleanupResult struct {
+	RemovedPictureCount int
+	DeletedObjectCount  int
+	DeletedFileCount    int
+}
+
 // TransactionPictureUnusedDeleteRequest represents all parameters of unused transaction picture deleting request
 type TransactionPictureUnusedDeleteRequest struct {
 	Id int64 `json:"id,string" binding:"required,min=1"`
 }
 
+// TransactionPictureGetRequest represents all parameters of transaction picture getting request
+type TransactionPictureGetRequest struct {
+	Size TransactionPictureSize `form:"size" binding:"omitempty,oneof=small medium original"`
+}
+
 // TransactionPictureInfoBasicResponse represents a view-object of transaction picture basic info
 type TransactionPictureInfoBasicResponse struct {
-	PictureId   int64  `json:"pictureId,string"`
-	OriginalUrl string `json:"originalUrl"`
+	PictureId    int64  `json:"pictureId,string"`
+	OriginalUrl  string `json:"originalUrl"`
+	ThumbnailUrl string `json:"thumbnailUrl,omitempty"`
+}
+
+// IsThumbnail returns whether the picture size is a thumbnail size
+func (s TransactionPictureSize) IsThumbnail() bool {
+	_, exists := transactionPictureThumbnailMaxDimensions[s]
+	return exists
+}
+
+// GetMaxDimension returns the maximum width and height of the thumbnail size, or returns zero for the original size
+func (s TransactionPictureSize) GetMaxDimension() int {
+	return transactionPictureThumbnailMaxDimensions[s]
+}
+
+// GetFileExtension returns the stored file extension of the thumbnail size (e.g. "small.jpg")
+func (s TransactionPictureSize) GetFileExtension() string {
+	return string(s) + "." + TransactionPictureThumbnailFileExtension
+}
+
+// GetStoredPictureId returns the picture id which the stored picture file is named by,
+// the pictures uploaded before content deduplication always use their own picture id
+func (p *TransactionPictureInfo) GetStoredPictureId() int64 {
+	if p.StoredPictureId > 0 {
+		return p.StoredPictureId
+	}
+
+	return p.PictureId
+}
+
+// IsContentDeduplicated returns whether the stored picture file is managed by reference counted picture object
+func (p *TransactionPictureInfo) IsContentDeduplicated() bool {
+	return p.ContentHash != "" && p.StoredPictureId > 0
 }
 
 // ToTransactionPictureInfoBasicResponse returns a view-object according to database model
-func (p *TransactionPictureInfo) ToTransactionPictureInfoBasicResponse(originalUrl string) *TransactionPictureInfoBasicResponse {
+func (p *TransactionPictureInfo) ToTransactionPictureInfoBasicResponse(originalUrl string, thumbnailUrl string) *TransactionPictureInfoBasicResponse {
 	return &TransactionPictureInfoBasicResponse{
-		PictureId:   p.PictureId,
-		OriginalUrl: originalUrl,
+		PictureId:    p.PictureId,
+		OriginalUrl:  originalUrl,
+		ThumbnailUrl: thumbnailUrl,
 	}
 }
 
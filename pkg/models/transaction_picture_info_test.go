@@ -25,3 +25,27 @@ func TestTransactionPictureInfoBasicResponseSliceLess(t *testing.T) {
 	assert.Equal(t, int64(2), pictureInfoSlice[1].PictureId)
 	assert.Equal(t, int64(3), pictureInfoSlice[2].PictureId)
 }
+
+func TestTransactionPictureInfoGetStoredPictureId(t *testing.T) {
+	pictureInfo := &TransactionPictureInfo{PictureId: 1001}
+	assert.Equal(t, int64(1001), pictureInfo.GetStoredPictureId())
+	assert.False(t, pictureInfo.IsContentDeduplicated())
+
+	pictureInfo = &TransactionPictureInfo{PictureId: 1002, ContentHash: "c3ab8ff13720e8ad9047dd39466b3c8974e592c2fa383d4a3960714caef0c4f2", StoredPictureId: 1001}
+	assert.Equal(t, int64(1001), pictureInfo.GetStoredPictureId())
+	assert.True(t, pictureInfo.IsContentDeduplicated())
+}
+
+func TestTransactionPictureSize(t *testing.T) {
+	assert.False(t, TRANSACTION_PICTURE_SIZE_ORIGINAL.IsThumbnail())
+	assert.False(t, TransactionPictureSize("original").IsThumbnail())
+	assert.Equal(t, 0, TRANSACTION_PICTURE_SIZE_ORIGINAL.GetMaxDimension())
+
+	assert.True(t, TRANSACTION_PICTURE_SIZE_SMALL.IsThumbnail())
+	assert.Equal(t, 256, TRANSACTION_PICTURE_SIZE_SMALL.GetMaxDimension())
+	assert.Equal(t, "small.jpg", TRANSACTION_PICTURE_SIZE_SMALL.GetFileExtension())
+
+	assert.True(t, TRANSACTION_PICTURE_SIZE_MEDIUM.IsThumbnail())
+	assert.Equal(t, 1024, TRANSACTION_PICTURE_SIZE_MEDIUM.GetMaxDimension())
+	assert.Equal(t, "medium.jpg", TRANSACTION_PICTURE_SIZE_MEDIUM.GetFileExtension())
+}
@@ -3,8 +3,11 @@ package models
 // TransactionTag represents transaction tag data stored in database
 type TransactionTag struct {
 	TagId           int64  `xorm:"PK"`
-	Uid             int64  `xorm:"INDEX(IDX_tag_uid_deleted_order) NOT NULL"`
-	Deleted         bool   `xorm:"INDEX(IDX_tag_uid_deleted_order) NOT NULL"`
+	Uid             int64  `xorm:"INDEX(IDX_tag_uid_deleted_order) INDEX(IDX_tag_uid_deleted_ledger_id) NOT NULL"`
+	Deleted         bool   `xorm:"INDEX(IDX_tag_uid_deleted_order) INDEX(IDX_tag_uid_deleted_ledger_id) NOT NULL"`
+	LedgerId        int64  `xorm:"INDEX(IDX_tag_uid_deleted_ledger_id) NOT NULL DEFAULT 0"`
+	TagGroupId      int64  `xorm:"NOT NULL DEFAULT 0"`
+	ParentTagId     int64  `xorm:"NOT NULL DEFAULT 0"`
 	Name            string `xorm:"VARCHAR(64) NOT NULL"`
 	DisplayOrder    int32  `xorm:"INDEX(IDX_tag_uid_deleted_order) NOT NULL"`
 	Hidden          bool   `xorm:"NOT NULL"`
@@ -20,19 +23,24 @@ type TransactionTagGetRequest struct {
 
 // TransactionTagCreateRequest represents all parameters of transaction tag creation request
 type TransactionTagCreateRequest struct {
-	Name string `json:"name" binding:"required,notBlank,max=64"`
+	Name        string `json:"name" binding:"required,notBlank,max=64"`
+	TagGroupId  int64  `json:"tagGroupId,string" binding:"min=0"`
+	ParentTagId int64  `json:"parentTagId,string" binding:"min=0"`
 }
 
 // TransactionTagCreateBatchRequest represents all parameters of transaction tag batch creation request
 type TransactionTagCreateBatchRequest struct {
 	Tags       []*TransactionTagCreateRequest `json:"tags" binding:"required"`
+	TagGroupId int64                          `json:"tagGroupId,string" binding:"min=0"`
 	SkipExists bool                           `json:"skipExists"`
 }
 
 // TransactionTagModifyRequest represents all parameters of transaction tag modification request
 type TransactionTagModifyRequest struct {
-	Id   int64  `json:"id,string" binding:"required,min=1"`
-	Name string `json:"name" binding:"required,notBlank,max=64"`
+	Id          int64  `json:"id,string" binding:"required,min=1"`
+	Name        string `json:"name" binding:"required,notBlank,max=64"`
+	TagGroupId  int64  `json:"tagGroupId,string" binding:"min=0"`
+	ParentTagId int64  `json:"parentTagId,string" binding:"min=0"`
 }
 
 // TransactionTagHideRequest represents all parameters of transaction tag hiding request
@@ -57,9 +65,18 @@ type TransactionTagDeleteRequest struct {
 	Id int64 `json:"id,string" binding:"required,min=1"`
 }
 
+// TransactionTagMergeRequest represents all parameters of transaction tag merging request
+type TransactionTagMergeRequest struct {
+	FromTagId int64 `json:"fromTagId,string" binding:"required,min=1"`
+	ToTagId   int64 `json:"toTagId,string" binding:"required,min=1"`
+}
+
 // TransactionTagInfoResponse represents a view-object of transaction tag
 type TransactionTagInfoResponse struct {
 	Id           int64  `json:"id,string"`
+	LedgerId     int64  `json:"ledgerId,string"`
+	TagGroupId   int64  `json:"tagGroupId,string"`
+	ParentTagId  int64  `json:"parentTagId,string"`
 	Name         string `json:"name"`
 	DisplayOrder int32  `json:"displayOrder"`
 	Hidden       bool   `json:"hidden"`
@@ -69,7 +86,10 @@ type TransactionTagInfoResponse struct {
 func (t *TransactionTag) FillFromOtherTag(tag *TransactionTag) {
 	t.TagId = tag.TagId
 	t.Uid = tag.Uid
+	t.LedgerId = tag.LedgerId
 	t.Deleted = tag.Deleted
+	t.TagGroupId = tag.TagGroupId
+	t.ParentTagId = tag.ParentTagId
 	t.Name = tag.Name
 	t.DisplayOrder = tag.DisplayOrder
 	t.Hidden = tag.Hidden
@@ -82,6 +102,9 @@ func (t *TransactionTag) FillFromOtherTag(tag *TransactionTag) {
 func (t *TransactionTag) ToTransactionTagInfoResponse() *TransactionTagInfoResponse {
 	return &TransactionTagInfoResponse{
 		Id:           t.TagId,
+		LedgerId:     t.LedgerId,
+		TagGroupId:   t.TagGroupId,
+		ParentTagId:  t.ParentTagId,
 		Name:         t.Name,
 		DisplayOrder: t.DisplayOrder,
 		Hidden:       t.Hidden,
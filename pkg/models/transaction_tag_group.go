@@ -0,0 +1,119 @@
+package models
+
+import "sort"
+
+// TransactionTagGroup represents transaction tag group data stored in database
+type TransactionTagGroup struct {
+	TagGroupId      int64  `xorm:"PK"`
+	Uid             int64  `xorm:"INDEX(IDX_tag_group_uid_deleted_ledger_id_order) NOT NULL"`
+	Deleted         bool   `xorm:"INDEX(IDX_tag_group_uid_deleted_ledger_id_order) NOT NULL"`
+	LedgerId        int64  `xorm:"INDEX(IDX_tag_group_uid_deleted_ledger_id_order) NOT NULL DEFAULT 0"`
+	Name            string `xorm:"VARCHAR(64) NOT NULL"`
+	DisplayOrder    int32  `xorm:"INDEX(IDX_tag_group_uid_deleted_ledger_id_order) NOT NULL"`
+	CreatedUnixTime int64
+	UpdatedUnixTime int64
+	DeletedUnixTime int64
+}
+
+// TransactionTagGroupGetRequest represents all parameters of transaction tag group getting request
+type TransactionTagGroupGetRequest struct {
+	Id int64 `form:"id,string" binding:"required,min=1"`
+}
+
+// TransactionTagGroupCreateRequest represents all parameters of transaction tag group creation request
+type TransactionTagGroupCreateRequest struct {
+	Name string `json:"name" binding:"required,notBlank,max=64"`
+}
+
+// TransactionTagGroupModifyRequest represents all parameters of transaction tag group modification request
+type TransactionTagGroupModifyRequest struct {
+	Id   int64  `json:"id,string" binding:"required,min=1"`
+	Name string `json:"name" binding:"required,notBlank,max=64"`
+}
+
+// TransactionTagGroupMoveRequest represents all parameters of transaction tag group moving request
+type TransactionTagGroupMoveRequest struct {
+	NewDisplayOrders []*TransactionTagGroupNewDisplayOrderRequest `json:"newDisplayOrders" binding:"required,min=1"`
+}
+
+// TransactionTagGroupNewDisplayOrderRequest represents a data pair of id and display order
+type TransactionTagGroupNewDisplayOrderRequest struct {
+	Id           int64 `json:"id,string" binding:"required,min=1"`
+	DisplayOrder int32 `json:"displayOrder"`
+}
+
+// TransactionTagGroupDeleteRequest represents all parameters of transaction tag group deleting request
+type TransactionTagGroupDeleteRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// TransactionTagGroupStatisticRequest represents all parameters of transaction tag group statistic request
+type TransactionTagGroupStatisticRequest struct {
+	Id        int64 `form:"id,string" binding:"required,min=1"`
+	StartTime int64 `form:"start_time" binding:"min=0"`
+	EndTime   int64 `form:"end_time" binding:"min=0"`
+}
+
+// TransactionTagGroupInfoResponse represents a view-object of transaction tag group
+type TransactionTagGroupInfoResponse struct {
+	Id           int64  `json:"id,string"`
+	LedgerId     int64  `json:"ledgerId,string"`
+	Name         string `json:"name"`
+	DisplayOrder int32  `json:"displayOrder"`
+}
+
+// TransactionTagStatisticItemResponse represents a view-object of total amounts of one tag in one currency
+type TransactionTagStatisticItemResponse struct {
+	TagId            int64  `json:"tagId,string"`
+	Currency         string `json:"currency"`
+	IncomeAmount     int64  `json:"incomeAmount"`
+	ExpenseAmount    int64  `json:"expenseAmount"`
+	TransactionCount int64  `json:"transactionCount"`
+}
+
+// TransactionTagGroupStatisticResponse represents a view-object of transaction tag group statistic,
+// the totals count every transaction only once even if it has more than one tag in the group
+type TransactionTagGroupStatisticResponse struct {
+	TagGroup *TransactionTagGroupInfoResponse       `json:"tagGroup"`
+	Items    []*TransactionTagStatisticItemResponse `json:"items"`
+	Totals   []*TransactionTagStatisticItemResponse `json:"totals"`
+}
+
+// ToTransactionTagGroupInfoResponse returns a view-object according to database model
+func (g *TransactionTagGroup) ToTransactionTagGroupInfoResponse() *TransactionTagGroupInfoResponse {
+	return &TransactionTagGroupInfoResponse{
+		Id:           g.TagGroupId,
+		LedgerId:     g.LedgerId,
+		Name:         g.Name,
+		DisplayOrder: g.DisplayOrder,
+	}
+}
+
+// SortTransactionTagStatisticItems sorts the statistic items by tag id and currency
+func SortTransactionTagStatisticItems(items []*TransactionTagStatisticItemResponse) {
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].TagId != items[j].TagId {
+			return items[i].TagId < items[j].TagId
+		}
+
+		return items[i].Currency < items[j].Currency
+	})
+}
+
+// TransactionTagGroupInfoResponseSlice represents the slice data structure of TransactionTagGroupInfoResponse
+type TransactionTagGroupInfoResponseSlice []*TransactionTagGroupInfoResponse
+
+// Len returns the count of items
+func (s TransactionTagGroupInfoResponseSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s TransactionTagGroupInfoResponseSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s TransactionTagGroupInfoResponseSlice) Less(i, j int) bool {
+	return s[i].DisplayOrder < s[j].DisplayOrder
+}
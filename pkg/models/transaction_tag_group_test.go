@@ -0,0 +1,46 @@
+package models
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactionTagGroupInfoResponseSliceLess(t *testing.T) {
+	var tagGroupRespSlice TransactionTagGroupInfoResponseSlice
+	tagGroupRespSlice = append(tagGroupRespSlice, &TransactionTagGroupInfoResponse{
+		Id:           1,
+		DisplayOrder: 3,
+	})
+	tagGroupRespSlice = append(tagGroupRespSlice, &TransactionTagGroupInfoResponse{
+		Id:           2,
+		DisplayOrder: 1,
+	})
+	tagGroupRespSlice = append(tagGroupRespSlice, &TransactionTagGroupInfoResponse{
+		Id:           3,
+		DisplayOrder: 2,
+	})
+
+	sort.Sort(tagGroupRespSlice)
+
+	assert.Equal(t, int64(2), tagGroupRespSlice[0].Id)
+	assert.Equal(t, int64(3), tagGroupRespSlice[1].Id)
+	assert.Equal(t, int64(1), tagGroupRespSlice[2].Id)
+}
+
+func TestSortTransactionTagStatisticItems(t *testing.T) {
+	items := []*TransactionTagStatisticItemResponse{
+		{TagId: 2, Currency: "USD"},
+		{TagId: 1, Currency: "USD"},
+		{TagId: 1, Currency: "EUR"},
+	}
+
+	SortTransactionTagStatisticItems(items)
+
+	assert.Equal(t, int64(1), items[0].TagId)
+	assert.Equal(t, "EUR", items[0].Currency)
+	assert.Equal(t, int64(1), items[1].TagId)
+	assert.Equal(t, "USD", items[1].Currency)
+	assert.Equal(t, int64(2), items[2].TagId)
+}
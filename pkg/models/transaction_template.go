@@ -29,8 +29,9 @@ const (
 // TransactionTemplate represents transaction template stored in database
 type TransactionTemplate struct {
 	TemplateId                 int64                            `xorm:"PK"`
-	Uid                        int64                            `xorm:"INDEX(IDX_transaction_template_uid_deleted_template_type_order) NOT NULL"`
-	Deleted                    bool                             `xorm:"INDEX(IDX_transaction_template_uid_deleted_template_type_order) INDEX(IDX_transaction_template_deleted_type_freqtype_scheduled_time) NOT NULL"`
+	Uid                        int64                            `xorm:"INDEX(IDX_transaction_template_uid_deleted_template_type_order) INDEX(IDX_transaction_template_uid_deleted_ledger_id) NOT NULL"`
+	Deleted                    bool                             `xorm:"INDEX(IDX_transaction_template_uid_deleted_template_type_order) INDEX(IDX_transaction_template_deleted_type_freqtype_scheduled_time) INDEX(IDX_transaction_template_uid_deleted_ledger_id) NOT NULL"`
+	LedgerId                   int64                            `xorm:"INDEX(IDX_transaction_template_uid_deleted_ledger_id) NOT NULL DEFAULT 0"`
 	TemplateType               TransactionTemplateType          `xorm:"INDEX(IDX_transaction_template_uid_deleted_template_type_order) INDEX(IDX_transaction_template_deleted_type_freqtype_scheduled_time) NOT NULL"`
 	Name                       string                           `xorm:"VARCHAR(64) NOT NULL"`
 	Type                       TransactionType                  `xorm:"NOT NULL"`
@@ -206,6 +207,7 @@ func (t *TransactionTemplate) toTransactionInfoResponse(utcOffset int16) *Transa
 
 	return &TransactionInfoResponse{
 		Id:                   t.TemplateId,
+		LedgerId:             t.LedgerId,
 		TimeSequenceId:       utils.GetMinTransactionTimeFromUnixTime(t.CreatedUnixTime),
 		Type:                 t.Type,
 		CategoryId:           t.CategoryId,
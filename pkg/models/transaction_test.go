@@ -104,6 +104,32 @@ func TestParseTransactionTagFilter_ValidTwoFilterInTagFilters(t *testing.T) {
 	assert.Equal(t, []int64{4, 5, 6}, actualValue[1].TagIds)
 }
 
+func TestParseTransactionTagFilter_TagGroupsAndSubtreesInFilter(t *testing.T) {
+	actualValue, err := ParseTransactionTagFilter("0:1,g2,s3")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(actualValue))
+	assert.Equal(t, TRANSACTION_TAG_FILTER_HAS_ANY, actualValue[0].Type)
+	assert.Equal(t, []int64{1}, actualValue[0].TagIds)
+	assert.Equal(t, []int64{2}, actualValue[0].TagGroupIds)
+	assert.Equal(t, []int64{3}, actualValue[0].SubtreeTagIds)
+
+	actualValue, err = ParseTransactionTagFilter("1:g4,g5")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(actualValue))
+	assert.Equal(t, TRANSACTION_TAG_FILTER_HAS_ALL, actualValue[0].Type)
+	assert.Equal(t, 0, len(actualValue[0].TagIds))
+	assert.Equal(t, []int64{4, 5}, actualValue[0].TagGroupIds)
+	assert.Equal(t, 0, len(actualValue[0].SubtreeTagIds))
+}
+
+func TestParseTransactionTagFilter_InvalidTagGroupsAndSubtreesInFilter(t *testing.T) {
+	_, err := ParseTransactionTagFilter("0:gabc")
+	assert.EqualError(t, err, errs.ErrTransactionTagGroupIdInvalid.Message)
+
+	_, err = ParseTransactionTagFilter("0:sabc")
+	assert.EqualError(t, err, errs.ErrTransactionTagIdInvalid.Message)
+}
+
 func TestTransactionAmountsRequestGetTransactionAmountsRequestItems(t *testing.T) {
 	transactionAmountsRequest := &TransactionAmountsRequest{
 		Query: "name1_1234567890_1234567891|name2_1234567900_1234567901",
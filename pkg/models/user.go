@@ -111,6 +111,8 @@ type User struct {
 	ExpenseAmountColor    AmountColorType            `xorm:"TINYINT"`
 	IncomeAmountColor     AmountColorType            `xorm:"TINYINT"`
 	FeatureRestriction    core.UserFeatureRestrictions
+	StorageQuota          int64
+	TransactionQuota      int64
 	Disabled              bool
 	Deleted               bool `xorm:"NOT NULL"`
 	EmailVerified         bool `xorm:"NOT NULL"`
@@ -0,0 +1,42 @@
+package models
+
+// UserQuotaUseDefault represents the user quota which uses the default quota in settings
+const UserQuotaUseDefault = int64(0)
+
+// UserQuotaUnlimited represents the user quota which has no limit
+const UserQuotaUnlimited = int64(-1)
+
+// UserQuotaUsage represents the usage and the effective quota of user storage and transactions
+type UserQuotaUsage struct {
+	StorageUsedBytes     int64
+	StorageQuota         int64
+	TransactionUsedCount int64
+	TransactionQuota     int64
+}
+
+// GetEffectiveUserQuota returns the effective quota according to the user quota and the default quota, zero means unlimited
+func GetEffectiveUserQuota(userQuota int64, defaultQuota uint64) int64 {
+	if userQuota == UserQuotaUseDefault {
+		if defaultQuota > uint64(1<<63-1) {
+			return 0
+		}
+
+		return int64(defaultQuota)
+	}
+
+	if userQuota < 0 {
+		return 0
+	}
+
+	return userQuota
+}
+
+// IsStorageQuotaExceeded returns whether the storage quota would be exceeded after adding the specified bytes
+func (u *UserQuotaUsage) IsStorageQuotaExceeded(additionalBytes int64) bool {
+	return u.StorageQuota > 0 && u.StorageUsedBytes+additionalBytes > u.StorageQuota
+}
+
+// IsTransactionQuotaExceeded returns whether the transaction quota would be exceeded after adding the specified count of transactions
+func (u *UserQuotaUsage) IsTransactionQuotaExceeded(additionalCount int64) bool {
+	return u.TransactionQuota > 0 && u.TransactionUsedCount+additionalCount > u.TransactionQuota
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetEffectiveUserQuota(t *testing.T) {
+	assert.Equal(t, int64(0), GetEffectiveUserQuota(UserQuotaUseDefault, 0))
+	assert.Equal(t, int64(1024), GetEffectiveUserQuota(UserQuotaUseDefault, 1024))
+	assert.Equal(t, int64(0), GetEffectiveUserQuota(UserQuotaUseDefault, 1<<64-1))
+	assert.Equal(t, int64(0), GetEffectiveUserQuota(UserQuotaUnlimited, 1024))
+	assert.Equal(t, int64(2048), GetEffectiveUserQuota(2048, 1024))
+}
+
+func TestUserQuotaUsageIsStorageQuotaExceeded(t *testing.T) {
+	usage := &UserQuotaUsage{StorageUsedBytes: 900, StorageQuota: 1000}
+	assert.False(t, usage.IsStorageQuotaExceeded(100))
+	assert.True(t, usage.IsStorageQuotaExceeded(101))
+
+	usage = &UserQuotaUsage{StorageUsedBytes: 900, StorageQuota: 0}
+	assert.False(t, usage.IsStorageQuotaExceeded(1<<40))
+}
+
+func TestUserQuotaUsageIsTransactionQuotaExceeded(t *testing.T) {
+	usage := &UserQuotaUsage{TransactionUsedCount: 9, TransactionQuota: 10}
+	assert.False(t, usage.IsTransactionQuotaExceeded(1))
+	assert.True(t, usage.IsTransactionQuotaExceeded(2))
+
+	usage = &UserQuotaUsage{TransactionUsedCount: 9, TransactionQuota: 0}
+	assert.False(t, usage.IsTransactionQuotaExceeded(100))
+}
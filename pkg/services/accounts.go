@@ -45,14 +45,24 @@ func (s *AccountService) GetTotalAccountCountByUid(c core.Context, uid int64) (i
 	return count, err
 }
 
-// GetAllAccountsByUid returns all account models of user
-func (s *AccountService) GetAllAccountsByUid(c core.Context, uid int64) ([]*models.Account, error) {
-	if uid <= 0 {
-		return nil, errs.ErrUserIdInvalid
+// GetAllAccountsByUid returns all account models in the ledger of given access, or all the accounts of the ledger owner if the access is for all ledgers
+func (s *AccountService) GetAllAccountsByUid(c core.Context, access *models.LedgerAccess) ([]*models.Account, error) {
+	uid, ledgerId, err := LedgerAccesses.GetLedgerOwnerUid(c, access, models.LEDGER_PERMISSION_VIEW_BASIC_DATA)
+
+	if err != nil {
+		return nil, err
+	}
+
+	condition := "uid=? AND deleted=?"
+	conditionParams := []any{uid, false}
+
+	if ledgerId > 0 {
+		condition = condition + " AND ledger_id=?"
+		conditionParams = append(conditionParams, ledgerId)
 	}
 
 	var accounts []*models.Account
-	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).OrderBy("parent_account_id asc, display_order asc").Find(&accounts)
+	err = s.UserDataDB(uid).NewSession(c).Where(condition, conditionParams...).OrderBy("parent_account_id asc, display_order asc").Find(&accounts)
 
 	return accounts, err
 }
@@ -282,6 +292,7 @@ func (s *AccountService) CreateAccounts(c core.Context, mainAccount *models.Acco
 				TransactionId:        transactionId,
 				Uid:                  allAccounts[i].Uid,
 				Deleted:              false,
+				LedgerId:             allAccounts[i].LedgerId,
 				Type:                 models.TRANSACTION_DB_TYPE_MODIFY_BALANCE,
 				TransactionTime:      transactionTime,
 				TimezoneUtcOffset:    transactionUtcOffset,
@@ -423,6 +434,7 @@ func (s *AccountService) ModifyAccounts(c core.Context, mainAccount *models.Acco
 					TransactionId:        transactionId,
 					Uid:                  childAccount.Uid,
 					Deleted:              false,
+					LedgerId:             childAccount.LedgerId,
 					Type:                 models.TRANSACTION_DB_TYPE_MODIFY_BALANCE,
 					TransactionTime:      transactionTime,
 					TimezoneUtcOffset:    transactionUtcOffset,
@@ -133,6 +133,31 @@ func (s *ServiceUsingStorage) DeleteTransactionPicture(ctx core.Context, uid int
 	return s.container.DeleteTransactionPicture(ctx, s.getTransactionPicturePath(uid, pictureId, fileExtension))
 }
 
+// WalkTransactionPictures calls the specified function for each file in the current transaction picture object storage
+func (s *ServiceUsingStorage) WalkTransactionPictures(ctx core.Context, fn storage.ObjectWalkFunc) error {
+	return s.container.WalkTransactionPictures(ctx, fn)
+}
+
+// ExistsTransactionAttachment returns whether the transaction attachment exists from the current transaction attachment object storage
+func (s *ServiceUsingStorage) ExistsTransactionAttachment(ctx core.Context, uid int64, attachmentId int64, fileExtension string) (bool, error) {
+	return s.container.ExistsTransactionAttachment(ctx, s.getTransactionAttachmentPath(uid, attachmentId, fileExtension))
+}
+
+// ReadTransactionAttachment returns the transaction attachment from the current transaction attachment object storage
+func (s *ServiceUsingStorage) ReadTransactionAttachment(ctx core.Context, uid int64, attachmentId int64, fileExtension string) (storage.ObjectInStorage, error) {
+	return s.container.ReadTransactionAttachment(ctx, s.getTransactionAttachmentPath(uid, attachmentId, fileExtension))
+}
+
+// SaveTransactionAttachment returns whether save the transaction attachment into the current transaction attachment object storage successfully
+func (s *ServiceUsingStorage) SaveTransactionAttachment(ctx core.Context, uid int64, attachmentId int64, object storage.ObjectInStorage, fileExtension string) error {
+	return s.container.SaveTransactionAttachment(ctx, s.getTransactionAttachmentPath(uid, attachmentId, fileExtension), object)
+}
+
+// DeleteTransactionAttachment returns whether delete the transaction attachment from the current transaction attachment object storage successfully
+func (s *ServiceUsingStorage) DeleteTransactionAttachment(ctx core.Context, uid int64, attachmentId int64, fileExtension string) error {
+	return s.container.DeleteTransactionAttachment(ctx, s.getTransactionAttachmentPath(uid, attachmentId, fileExtension))
+}
+
 func (s *ServiceUsingStorage) getUserAvatarPath(uid int64, fileExtension string) string {
 	return fmt.Sprintf("%d.%s", uid, fileExtension)
 }
@@ -140,3 +165,7 @@ func (s *ServiceUsingStorage) getUserAvatarPath(uid int64, fileExtension string)
 func (s *ServiceUsingStorage) getTransactionPicturePath(uid int64, pictureId int64, fileExtension string) string {
 	return filepath.Join(utils.Int64ToString(uid), fmt.Sprintf("%d.%s", pictureId, fileExtension))
 }
+
+func (s *ServiceUsingStorage) getTransactionAttachmentPath(uid int64, attachmentId int64, fileExtension string) string {
+	return filepath.Join(utils.Int64ToString(uid), fmt.Sprintf("%d.%s", attachmentId, fileExtension))
+}
@@ -0,0 +1,476 @@
+package services
+
+import (
+	"sort"
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/uuid"
+)
+
+// ContactService represents contact service
+type ContactService struct {
+	ServiceUsingDB
+	ServiceUsingUuid
+}
+
+// Initialize a contact service singleton instance
+var (
+	Contacts = &ContactService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+	}
+)
+
+// GetAllContactsByUid returns all contact models in the ledger of given access, or all the contacts of the ledger owner if the access is for all ledgers
+func (s *ContactService) GetAllContactsByUid(c core.Context, access *models.LedgerAccess) ([]*models.Contact, error) {
+	uid, ledgerId, err := LedgerAccesses.GetLedgerOwnerUid(c, access, models.LEDGER_PERMISSION_VIEW_BASIC_DATA)
+
+	if err != nil {
+		return nil, err
+	}
+
+	condition := "uid=? AND deleted=?"
+	conditionParams := []any{uid, false}
+
+	if ledgerId > 0 {
+		condition = condition + " AND ledger_id=?"
+		conditionParams = append(conditionParams, ledgerId)
+	}
+
+	var contacts []*models.Contact
+	err = s.UserDataDB(uid).NewSession(c).Where(condition, conditionParams...).OrderBy("display_order asc").Find(&contacts)
+
+	return contacts, err
+}
+
+// GetContactByContactId returns a contact model according to contact id
+func (s *ContactService) GetContactByContactId(c core.Context, uid int64, contactId int64) (*models.Contact, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if contactId <= 0 {
+		return nil, errs.ErrContactIdInvalid
+	}
+
+	contact := &models.Contact{}
+	has, err := s.UserDataDB(uid).NewSession(c).ID(contactId).Where("uid=? AND deleted=?", uid, false).Get(contact)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrContactNotFound
+	}
+
+	return contact, nil
+}
+
+// GetContactsByContactIds returns contact models according to contact ids
+func (s *ContactService) GetContactsByContactIds(c core.Context, uid int64, contactIds []int64) (map[int64]*models.Contact, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if contactIds == nil {
+		return nil, errs.ErrContactIdInvalid
+	}
+
+	var contacts []*models.Contact
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).In("contact_id", contactIds).Find(&contacts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	contactMap := make(map[int64]*models.Contact, len(contacts))
+
+	for i := 0; i < len(contacts); i++ {
+		contactMap[contacts[i].ContactId] = contacts[i]
+	}
+
+	return contactMap, nil
+}
+
+// GetMaxDisplayOrder returns the max display order
+func (s *ContactService) GetMaxDisplayOrder(c core.Context, uid int64) (int32, error) {
+	if uid <= 0 {
+		return 0, errs.ErrUserIdInvalid
+	}
+
+	contact := &models.Contact{}
+	has, err := s.UserDataDB(uid).NewSession(c).Cols("uid", "deleted", "display_order").Where("uid=? AND deleted=?", uid, false).OrderBy("display_order desc").Limit(1).Get(contact)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if has {
+		return contact.DisplayOrder, nil
+	} else {
+		return 0, nil
+	}
+}
+
+// GetAllContactBalances returns the balances of all contacts grouped by contact id and currency in the ledger of given access, or in all ledgers of the ledger owner if the access is for all ledgers
+func (s *ContactService) GetAllContactBalances(c core.Context, access *models.LedgerAccess) (map[int64]map[string]int64, error) {
+	uid, ledgerId, err := LedgerAccesses.GetLedgerOwnerUid(c, access, models.LEDGER_PERMISSION_VIEW_BASIC_DATA)
+
+	if err != nil {
+		return nil, err
+	}
+
+	condition := "uid=? AND deleted=?"
+	conditionParams := []any{uid, false}
+
+	if ledgerId > 0 {
+		condition = condition + " AND ledger_id=?"
+		conditionParams = append(conditionParams, ledgerId)
+	}
+
+	var contactTransactions []*models.ContactTransaction
+	err = s.UserDataDB(uid).NewSession(c).Cols("contact_id", "currency", "amount").Where(condition, conditionParams...).Find(&contactTransactions)
+
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make(map[int64]map[string]int64)
+
+	for i := 0; i < len(contactTransactions); i++ {
+		contactTransaction := contactTransactions[i]
+		contactBalances, exists := balances[contactTransaction.ContactId]
+
+		if !exists {
+			contactBalances = make(map[string]int64)
+			balances[contactTransaction.ContactId] = contactBalances
+		}
+
+		contactBalances[contactTransaction.Currency] += contactTransaction.Amount
+	}
+
+	return balances, nil
+}
+
+// GetAllContactTransactionsByContactId returns all receivable and payable movements of given contact ordered by transaction time ascending
+func (s *ContactService) GetAllContactTransactionsByContactId(c core.Context, uid int64, contactId int64) ([]*models.ContactTransaction, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if contactId <= 0 {
+		return nil, errs.ErrContactIdInvalid
+	}
+
+	var contactTransactions []*models.ContactTransaction
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=? AND contact_id=?", uid, false, contactId).OrderBy("transaction_time asc").Find(&contactTransactions)
+
+	return contactTransactions, err
+}
+
+// GetContactTransactionsByTransactionIds returns the receivable and payable movements of contacts related to the given transactions
+func (s *ContactService) GetContactTransactionsByTransactionIds(c core.Context, uid int64, transactionIds []int64) ([]*models.ContactTransaction, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if len(transactionIds) < 1 {
+		return nil, nil
+	}
+
+	var contactTransactions []*models.ContactTransaction
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).In("transaction_id", transactionIds).Find(&contactTransactions)
+
+	return contactTransactions, err
+}
+
+// CreateContact saves a new contact model to database
+func (s *ContactService) CreateContact(c core.Context, contact *models.Contact) error {
+	if contact.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	exists, err := s.ExistsContactName(c, contact.Uid, contact.LedgerId, contact.Name)
+
+	if err != nil {
+		return err
+	} else if exists {
+		return errs.ErrContactNameAlreadyExists
+	}
+
+	contact.ContactId = s.GenerateUuid(uuid.UUID_TYPE_CONTACT)
+
+	if contact.ContactId < 1 {
+		return errs.ErrSystemIsBusy
+	}
+
+	contact.Deleted = false
+	contact.CreatedUnixTime = time.Now().Unix()
+	contact.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDataDB(contact.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Insert(contact)
+		return err
+	})
+}
+
+// ModifyContact saves an existed contact model to database
+func (s *ContactService) ModifyContact(c core.Context, contact *models.Contact) error {
+	if contact.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	contact.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDataDB(contact.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		exists, err := sess.Cols("contact_id").Where("uid=? AND deleted=? AND ledger_id=? AND name=? AND contact_id<>?", contact.Uid, false, contact.LedgerId, contact.Name, contact.ContactId).Exist(&models.Contact{})
+
+		if err != nil {
+			return err
+		} else if exists {
+			return errs.ErrContactNameAlreadyExists
+		}
+
+		updatedRows, err := sess.ID(contact.ContactId).Cols("name", "comment", "updated_unix_time").Where("uid=? AND deleted=?", contact.Uid, false).Update(contact)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrContactNotFound
+		}
+
+		return err
+	})
+}
+
+// HideContact updates hidden field of given contacts
+func (s *ContactService) HideContact(c core.Context, uid int64, ids []int64, hidden bool) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.Contact{
+		Hidden:          hidden,
+		UpdatedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		updatedRows, err := sess.Cols("hidden", "updated_unix_time").Where("uid=? AND deleted=?", uid, false).In("contact_id", ids).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrContactNotFound
+		}
+
+		return err
+	})
+}
+
+// DeleteContact deletes an existed contact from database, the contact which still has unsettled balance cannot be deleted
+func (s *ContactService) DeleteContact(c core.Context, uid int64, contactId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.Contact{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	contactTransactionUpdateModel := &models.ContactTransaction{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		var contactTransactions []*models.ContactTransaction
+		err := sess.Cols("currency", "amount").Where("uid=? AND deleted=? AND contact_id=?", uid, false, contactId).Find(&contactTransactions)
+
+		if err != nil {
+			return err
+		}
+
+		balances := make(map[string]int64)
+
+		for i := 0; i < len(contactTransactions); i++ {
+			balances[contactTransactions[i].Currency] += contactTransactions[i].Amount
+		}
+
+		for _, balance := range balances {
+			if balance != 0 {
+				return errs.ErrContactInUseCannotBeDeleted
+			}
+		}
+
+		deletedRows, err := sess.ID(contactId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrContactNotFound
+		}
+
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND contact_id=?", uid, false, contactId).Update(contactTransactionUpdateModel)
+
+		return err
+	})
+}
+
+// DeleteAllContacts deletes all existed contacts and their receivable and payable movements from database
+func (s *ContactService) DeleteAllContacts(c core.Context, uid int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.Contact{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	contactTransactionUpdateModel := &models.ContactTransaction{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(contactTransactionUpdateModel)
+
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+
+		return err
+	})
+}
+
+// ExistsContactName returns whether the given contact name exists in given ledger
+func (s *ContactService) ExistsContactName(c core.Context, uid int64, ledgerId int64, name string) (bool, error) {
+	if name == "" {
+		return false, errs.ErrContactNameIsEmpty
+	}
+
+	return s.UserDataDB(uid).NewSession(c).Cols("name").Where("uid=? AND deleted=? AND ledger_id=? AND name=?", uid, false, ledgerId, name).Exist(&models.Contact{})
+}
+
+// CalculateSplitAmounts returns the amount of each participant according to the total amount and split method,
+// the remainder which cannot be divided evenly is allocated one by one to the participants with the largest fractional parts
+func (s *ContactService) CalculateSplitAmounts(totalAmount int64, method models.SplitMethod, participants []*models.ContactSplitParticipantRequest) ([]int64, error) {
+	if len(participants) < 1 {
+		return nil, errs.ErrSplitParticipantsEmpty
+	}
+
+	if totalAmount <= 0 {
+		return nil, errs.ErrSplitAmountInvalid
+	}
+
+	participantIds := make(map[int64]bool, len(participants))
+
+	for i := 0; i < len(participants); i++ {
+		if participantIds[participants[i].ContactId] {
+			return nil, errs.ErrSplitParticipantDuplicated
+		}
+
+		participantIds[participants[i].ContactId] = true
+	}
+
+	amounts := make([]int64, len(participants))
+
+	switch method {
+	case models.SPLIT_METHOD_EQUAL:
+		shares := make([]int64, len(participants))
+
+		for i := 0; i < len(shares); i++ {
+			shares[i] = 1
+		}
+
+		return s.allocateAmountByShares(totalAmount, shares)
+	case models.SPLIT_METHOD_SHARES:
+		shares := make([]int64, len(participants))
+
+		for i := 0; i < len(participants); i++ {
+			if participants[i].Shares < 0 {
+				return nil, errs.ErrSplitSharesInvalid
+			}
+
+			shares[i] = participants[i].Shares
+		}
+
+		return s.allocateAmountByShares(totalAmount, shares)
+	case models.SPLIT_METHOD_EXACT:
+		sum := int64(0)
+
+		for i := 0; i < len(participants); i++ {
+			if participants[i].Amount < 0 {
+				return nil, errs.ErrSplitAmountsNotEqualToTotalAmount
+			}
+
+			amounts[i] = participants[i].Amount
+			sum += participants[i].Amount
+		}
+
+		if sum != totalAmount {
+			return nil, errs.ErrSplitAmountsNotEqualToTotalAmount
+		}
+
+		return amounts, nil
+	default:
+		return nil, errs.ErrSplitMethodInvalid
+	}
+}
+
+func (s *ContactService) allocateAmountByShares(totalAmount int64, shares []int64) ([]int64, error) {
+	totalShares := int64(0)
+
+	for i := 0; i < len(shares); i++ {
+		totalShares += shares[i]
+	}
+
+	if totalShares <= 0 {
+		return nil, errs.ErrSplitSharesInvalid
+	}
+
+	amounts := make([]int64, len(shares))
+	remainders := make([]int64, len(shares))
+	allocatedAmount := int64(0)
+
+	for i := 0; i < len(shares); i++ {
+		amounts[i] = totalAmount * shares[i] / totalShares
+		remainders[i] = totalAmount * shares[i] % totalShares
+		allocatedAmount += amounts[i]
+	}
+
+	indexes := make([]int, len(shares))
+
+	for i := 0; i < len(indexes); i++ {
+		indexes[i] = i
+	}
+
+	sort.SliceStable(indexes, func(i, j int) bool {
+		return remainders[indexes[i]] > remainders[indexes[j]]
+	})
+
+	for i := 0; allocatedAmount < totalAmount; i++ {
+		amounts[indexes[i%len(indexes)]]++
+		allocatedAmount++
+	}
+
+	return amounts, nil
+}
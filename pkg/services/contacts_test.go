@@ -0,0 +1,96 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+func TestCalculateSplitAmounts_EqualMethod(t *testing.T) {
+	participants := []*models.ContactSplitParticipantRequest{
+		{ContactId: 0},
+		{ContactId: 1001},
+		{ContactId: 1002},
+	}
+
+	actualAmounts, err := Contacts.CalculateSplitAmounts(10000, models.SPLIT_METHOD_EQUAL, participants)
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{3334, 3333, 3333}, actualAmounts)
+}
+
+func TestCalculateSplitAmounts_EqualMethodWithMultipleRemainders(t *testing.T) {
+	participants := []*models.ContactSplitParticipantRequest{
+		{ContactId: 0},
+		{ContactId: 1001},
+		{ContactId: 1002},
+	}
+
+	actualAmounts, err := Contacts.CalculateSplitAmounts(101, models.SPLIT_METHOD_EQUAL, participants)
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{34, 34, 33}, actualAmounts)
+}
+
+func TestCalculateSplitAmounts_SharesMethod(t *testing.T) {
+	participants := []*models.ContactSplitParticipantRequest{
+		{ContactId: 0, Shares: 2},
+		{ContactId: 1001, Shares: 1},
+		{ContactId: 1002, Shares: 0},
+	}
+
+	actualAmounts, err := Contacts.CalculateSplitAmounts(1000, models.SPLIT_METHOD_SHARES, participants)
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{667, 333, 0}, actualAmounts)
+}
+
+func TestCalculateSplitAmounts_SharesMethodWithZeroTotalShares(t *testing.T) {
+	participants := []*models.ContactSplitParticipantRequest{
+		{ContactId: 0, Shares: 0},
+		{ContactId: 1001, Shares: 0},
+	}
+
+	_, err := Contacts.CalculateSplitAmounts(1000, models.SPLIT_METHOD_SHARES, participants)
+	assert.Equal(t, errs.ErrSplitSharesInvalid, err)
+}
+
+func TestCalculateSplitAmounts_ExactMethod(t *testing.T) {
+	participants := []*models.ContactSplitParticipantRequest{
+		{ContactId: 0, Amount: 1200},
+		{ContactId: 1001, Amount: 800},
+	}
+
+	actualAmounts, err := Contacts.CalculateSplitAmounts(2000, models.SPLIT_METHOD_EXACT, participants)
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1200, 800}, actualAmounts)
+}
+
+func TestCalculateSplitAmounts_ExactMethodWithMismatchedSum(t *testing.T) {
+	participants := []*models.ContactSplitParticipantRequest{
+		{ContactId: 0, Amount: 1200},
+		{ContactId: 1001, Amount: 700},
+	}
+
+	_, err := Contacts.CalculateSplitAmounts(2000, models.SPLIT_METHOD_EXACT, participants)
+	assert.Equal(t, errs.ErrSplitAmountsNotEqualToTotalAmount, err)
+}
+
+func TestCalculateSplitAmounts_DuplicatedParticipants(t *testing.T) {
+	participants := []*models.ContactSplitParticipantRequest{
+		{ContactId: 1001},
+		{ContactId: 1001},
+	}
+
+	_, err := Contacts.CalculateSplitAmounts(2000, models.SPLIT_METHOD_EQUAL, participants)
+	assert.Equal(t, errs.ErrSplitParticipantDuplicated, err)
+}
+
+func TestCalculateSplitAmounts_InvalidMethod(t *testing.T) {
+	participants := []*models.ContactSplitParticipantRequest{
+		{ContactId: 0},
+	}
+
+	_, err := Contacts.CalculateSplitAmounts(2000, 0, participants)
+	assert.Equal(t, errs.ErrSplitMethodInvalid, err)
+}
@@ -0,0 +1,522 @@
+package services
+
+import (
+	"sort"
+	"time"
+
+	"xorm.io/builder"
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+	"github.com/mayswind/ezbookkeeping/pkg/uuid"
+)
+
+const pageCountForAutoAssignEventTransactions = 1000
+
+// EventService represents event service
+type EventService struct {
+	ServiceUsingDB
+	ServiceUsingUuid
+}
+
+// Initialize an event service singleton instance
+var (
+	Events = &EventService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+	}
+)
+
+// GetAllEventsByUid returns all event models in the ledger of given access, or all the events of the ledger owner if the access is for all ledgers
+func (s *EventService) GetAllEventsByUid(c core.Context, access *models.LedgerAccess) ([]*models.Event, error) {
+	uid, ledgerId, err := LedgerAccesses.GetLedgerOwnerUid(c, access, models.LEDGER_PERMISSION_VIEW_BASIC_DATA)
+
+	if err != nil {
+		return nil, err
+	}
+
+	condition := "uid=? AND deleted=?"
+	conditionParams := []any{uid, false}
+
+	if ledgerId > 0 {
+		condition = condition + " AND ledger_id=?"
+		conditionParams = append(conditionParams, ledgerId)
+	}
+
+	var events []*models.Event
+	err = s.UserDataDB(uid).NewSession(c).Where(condition, conditionParams...).OrderBy("start_time desc").Find(&events)
+
+	return events, err
+}
+
+// GetEventByEventId returns an event model according to event id
+func (s *EventService) GetEventByEventId(c core.Context, uid int64, eventId int64) (*models.Event, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if eventId <= 0 {
+		return nil, errs.ErrEventIdInvalid
+	}
+
+	event := &models.Event{}
+	has, err := s.UserDataDB(uid).NewSession(c).ID(eventId).Where("uid=? AND deleted=?", uid, false).Get(event)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrEventNotFound
+	}
+
+	return event, nil
+}
+
+// GetMaxDisplayOrder returns the max display order
+func (s *EventService) GetMaxDisplayOrder(c core.Context, uid int64) (int32, error) {
+	if uid <= 0 {
+		return 0, errs.ErrUserIdInvalid
+	}
+
+	event := &models.Event{}
+	has, err := s.UserDataDB(uid).NewSession(c).Cols("uid", "deleted", "display_order").Where("uid=? AND deleted=?", uid, false).OrderBy("display_order desc").Limit(1).Get(event)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if has {
+		return event.DisplayOrder, nil
+	} else {
+		return 0, nil
+	}
+}
+
+// CreateEvent saves a new event model to database
+func (s *EventService) CreateEvent(c core.Context, event *models.Event) error {
+	if event.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	exists, err := s.ExistsEventName(c, event.Uid, event.LedgerId, event.Name)
+
+	if err != nil {
+		return err
+	} else if exists {
+		return errs.ErrEventNameAlreadyExists
+	}
+
+	event.EventId = s.GenerateUuid(uuid.UUID_TYPE_EVENT)
+
+	if event.EventId < 1 {
+		return errs.ErrSystemIsBusy
+	}
+
+	event.Deleted = false
+	event.CreatedUnixTime = time.Now().Unix()
+	event.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDataDB(event.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Insert(event)
+		return err
+	})
+}
+
+// ModifyEvent saves an existed event model to database
+func (s *EventService) ModifyEvent(c core.Context, event *models.Event) error {
+	if event.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	event.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDataDB(event.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		exists, err := sess.Cols("event_id").Where("uid=? AND deleted=? AND ledger_id=? AND name=? AND event_id<>?", event.Uid, false, event.LedgerId, event.Name, event.EventId).Exist(&models.Event{})
+
+		if err != nil {
+			return err
+		} else if exists {
+			return errs.ErrEventNameAlreadyExists
+		}
+
+		updatedRows, err := sess.ID(event.EventId).Cols("name", "start_time", "end_time", "budget_currency", "budget_amount", "participant_contact_ids", "auto_assign", "geo_longitude", "geo_latitude", "geo_radius", "comment", "updated_unix_time").Where("uid=? AND deleted=?", event.Uid, false).Update(event)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrEventNotFound
+		}
+
+		return err
+	})
+}
+
+// HideEvent updates hidden field of given events
+func (s *EventService) HideEvent(c core.Context, uid int64, ids []int64, hidden bool) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.Event{
+		Hidden:          hidden,
+		UpdatedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		updatedRows, err := sess.Cols("hidden", "updated_unix_time").Where("uid=? AND deleted=?", uid, false).In("event_id", ids).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrEventNotFound
+		}
+
+		return err
+	})
+}
+
+// DeleteEvent deletes an existed event from database, and removes all transactions from the event
+func (s *EventService) DeleteEvent(c core.Context, uid int64, eventId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.Event{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	transactionUpdateModel := &models.Transaction{
+		EventId:         0,
+		UpdatedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		deletedRows, err := sess.ID(eventId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrEventNotFound
+		}
+
+		_, err = sess.Cols("event_id", "updated_unix_time").Where("uid=? AND deleted=? AND event_id=?", uid, false, eventId).Update(transactionUpdateModel)
+
+		return err
+	})
+}
+
+// DeleteAllEvents deletes all existed events from database
+func (s *EventService) DeleteAllEvents(c core.Context, uid int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	updateModel := &models.Event{
+		Deleted:         true,
+		DeletedUnixTime: time.Now().Unix(),
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+		return err
+	})
+}
+
+// AssignTransactionsToEvent assigns the given transactions (and their related transfer transactions) in the ledger of given access to the event, or removes them from their events if event id is zero
+func (s *EventService) AssignTransactionsToEvent(c core.Context, access *models.LedgerAccess, eventId int64, transactionIds []int64) (int64, error) {
+	uid, ledgerId, err := LedgerAccesses.GetLedgerOwnerUid(c, access, models.LEDGER_PERMISSION_MODIFY_ALL_TRANSACTIONS)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return s.assignTransactionsToEvent(c, uid, ledgerId, eventId, transactionIds)
+}
+
+func (s *EventService) assignTransactionsToEvent(c core.Context, uid int64, ledgerId int64, eventId int64, transactionIds []int64) (int64, error) {
+	if uid <= 0 {
+		return 0, errs.ErrUserIdInvalid
+	}
+
+	if len(transactionIds) < 1 {
+		return 0, errs.ErrTransactionIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	transactionUpdateModel := &models.Transaction{
+		EventId:         eventId,
+		UpdatedUnixTime: now,
+	}
+
+	var updatedRows int64
+
+	err := s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		if eventId > 0 {
+			event := &models.Event{}
+			has, err := sess.ID(eventId).Where("uid=? AND deleted=?", uid, false).Get(event)
+
+			if err != nil {
+				return err
+			} else if !has {
+				return errs.ErrEventNotFound
+			} else if event.Hidden {
+				return errs.ErrCannotUseHiddenEvent
+			} else if event.LedgerId != ledgerId {
+				return errs.ErrCannotUseDataInDifferentLedger
+			}
+		}
+
+		var err error
+		updatedRows, err = sess.Cols("event_id", "updated_unix_time").Where("uid=? AND deleted=? AND ledger_id=? AND type<>?", uid, false, ledgerId, models.TRANSACTION_DB_TYPE_MODIFY_BALANCE).And(builder.Or(builder.In("transaction_id", transactionIds), builder.In("related_id", transactionIds))).Update(transactionUpdateModel)
+
+		return err
+	})
+
+	return updatedRows, err
+}
+
+// AutoAssignTransactionsToEvent assigns all existed transactions which are not in any event and match the date range and geographic area of the event to the event
+func (s *EventService) AutoAssignTransactionsToEvent(c core.Context, uid int64, event *models.Event) (int64, error) {
+	if uid <= 0 {
+		return 0, errs.ErrUserIdInvalid
+	}
+
+	minTransactionTime := utils.GetMinTransactionTimeFromUnixTime(event.StartTime)
+	maxTransactionTime := utils.GetMaxTransactionTimeFromUnixTime(event.EndTime)
+	matchedTransactionIds := make([]int64, 0)
+
+	for maxTransactionTime > 0 {
+		var transactions []*models.Transaction
+		err := s.UserDataDB(uid).NewSession(c).Select("transaction_id, type, transaction_time, related_id, geo_longitude, geo_latitude").Where("uid=? AND deleted=? AND ledger_id=? AND event_id=? AND type<>? AND transaction_time>=? AND transaction_time<=?", uid, false, event.LedgerId, 0, models.TRANSACTION_DB_TYPE_MODIFY_BALANCE, minTransactionTime, maxTransactionTime).Limit(pageCountForAutoAssignEventTransactions, 0).OrderBy("transaction_time desc").Find(&transactions)
+
+		if err != nil {
+			return 0, err
+		}
+
+		for i := 0; i < len(transactions); i++ {
+			if event.IsTransactionMatched(transactions[i]) {
+				matchedTransactionIds = append(matchedTransactionIds, transactions[i].TransactionId)
+			}
+		}
+
+		if len(transactions) < pageCountForAutoAssignEventTransactions {
+			maxTransactionTime = 0
+			break
+		}
+
+		maxTransactionTime = transactions[len(transactions)-1].TransactionTime - 1
+	}
+
+	if len(matchedTransactionIds) < 1 {
+		return 0, nil
+	}
+
+	totalUpdatedRows := int64(0)
+
+	for i := 0; i < len(matchedTransactionIds); i += pageCountForAutoAssignEventTransactions {
+		end := i + pageCountForAutoAssignEventTransactions
+
+		if end > len(matchedTransactionIds) {
+			end = len(matchedTransactionIds)
+		}
+
+		updatedRows, err := s.assignTransactionsToEvent(c, uid, event.LedgerId, event.EventId, matchedTransactionIds[i:end])
+
+		if err != nil {
+			return totalUpdatedRows, err
+		}
+
+		totalUpdatedRows += updatedRows
+	}
+
+	return totalUpdatedRows, nil
+}
+
+// ExistsEventName returns whether the given event name exists in given ledger
+func (s *EventService) ExistsEventName(c core.Context, uid int64, ledgerId int64, name string) (bool, error) {
+	if name == "" {
+		return false, errs.ErrEventNameIsEmpty
+	}
+
+	return s.UserDataDB(uid).NewSession(c).Cols("name").Where("uid=? AND deleted=? AND ledger_id=? AND name=?", uid, false, ledgerId, name).Exist(&models.Event{})
+}
+
+// GetEventStatistic returns the statistic of event by category, currency and participant according to the transactions assigned to the event,
+// the participant amounts of contacts come from the split movements, and the participant amount of current user is the total expense amount,
+// the total amounts are converted to the default currency if the exchange rates are available
+func (s *EventService) GetEventStatistic(event *models.Event, transactions []*models.Transaction, accountMap map[int64]*models.Account, contactTransactions []*models.ContactTransaction, defaultCurrency string, exchangeRates *models.LatestExchangeRateResponse) *models.EventStatisticResponse {
+	currencyStatistics := make(map[string]*models.EventCurrencyStatisticResponse)
+	categoryStatistics := make(map[int64]map[string]*models.EventCategoryStatisticResponse)
+	participantStatistics := make(map[int64]map[string]*models.EventParticipantStatisticResponse)
+	transactionIds := make(map[int64]bool, len(transactions))
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+		transactionIds[transaction.TransactionId] = true
+
+		if transaction.Type != models.TRANSACTION_DB_TYPE_INCOME && transaction.Type != models.TRANSACTION_DB_TYPE_EXPENSE {
+			continue
+		}
+
+		account, exists := accountMap[transaction.AccountId]
+
+		if !exists {
+			continue
+		}
+
+		currencyStatistic, exists := currencyStatistics[account.Currency]
+
+		if !exists {
+			currencyStatistic = &models.EventCurrencyStatisticResponse{
+				Currency: account.Currency,
+			}
+			currencyStatistics[account.Currency] = currencyStatistic
+		}
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_INCOME {
+			currencyStatistic.IncomeAmount += transaction.Amount
+		} else {
+			currencyStatistic.ExpenseAmount += transaction.Amount
+			s.getParticipantStatistic(participantStatistics, 0, account.Currency).Amount += transaction.Amount
+		}
+
+		categoryCurrencyStatistics, exists := categoryStatistics[transaction.CategoryId]
+
+		if !exists {
+			categoryCurrencyStatistics = make(map[string]*models.EventCategoryStatisticResponse)
+			categoryStatistics[transaction.CategoryId] = categoryCurrencyStatistics
+		}
+
+		categoryStatistic, exists := categoryCurrencyStatistics[account.Currency]
+
+		if !exists {
+			categoryStatistic = &models.EventCategoryStatisticResponse{
+				CategoryId: transaction.CategoryId,
+				Currency:   account.Currency,
+			}
+			categoryCurrencyStatistics[account.Currency] = categoryStatistic
+		}
+
+		categoryStatistic.Amount += transaction.Amount
+	}
+
+	for i := 0; i < len(contactTransactions); i++ {
+		contactTransaction := contactTransactions[i]
+
+		if !transactionIds[contactTransaction.TransactionId] || contactTransaction.Type != models.CONTACT_TRANSACTION_TYPE_SPLIT || contactTransaction.Amount <= 0 {
+			continue
+		}
+
+		s.getParticipantStatistic(participantStatistics, contactTransaction.ContactId, contactTransaction.Currency).Amount += contactTransaction.Amount
+	}
+
+	statistic := &models.EventStatisticResponse{
+		Event:            event.ToEventInfoResponse(),
+		TransactionCount: int64(len(transactions)),
+		Currencies:       make([]*models.EventCurrencyStatisticResponse, 0, len(currencyStatistics)),
+		Categories:       make([]*models.EventCategoryStatisticResponse, 0, len(categoryStatistics)),
+		Participants:     make([]*models.EventParticipantStatisticResponse, 0, len(participantStatistics)),
+	}
+
+	for _, currencyStatistic := range currencyStatistics {
+		statistic.Currencies = append(statistic.Currencies, currencyStatistic)
+	}
+
+	for _, categoryCurrencyStatistics := range categoryStatistics {
+		for _, categoryStatistic := range categoryCurrencyStatistics {
+			statistic.Categories = append(statistic.Categories, categoryStatistic)
+		}
+	}
+
+	for _, participantCurrencyStatistics := range participantStatistics {
+		for _, participantStatistic := range participantCurrencyStatistics {
+			statistic.Participants = append(statistic.Participants, participantStatistic)
+		}
+	}
+
+	sort.Slice(statistic.Currencies, func(i, j int) bool {
+		return statistic.Currencies[i].Currency < statistic.Currencies[j].Currency
+	})
+
+	if defaultCurrency != "" && exchangeRates != nil {
+		statistic.Total = s.getTotalStatistic(statistic.Currencies, defaultCurrency, exchangeRates)
+	}
+
+	sort.Slice(statistic.Categories, func(i, j int) bool {
+		if statistic.Categories[i].Amount != statistic.Categories[j].Amount {
+			return statistic.Categories[i].Amount > statistic.Categories[j].Amount
+		}
+
+		if statistic.Categories[i].CategoryId != statistic.Categories[j].CategoryId {
+			return statistic.Categories[i].CategoryId < statistic.Categories[j].CategoryId
+		}
+
+		return statistic.Categories[i].Currency < statistic.Categories[j].Currency
+	})
+
+	sort.Slice(statistic.Participants, func(i, j int) bool {
+		if statistic.Participants[i].ContactId != statistic.Participants[j].ContactId {
+			return statistic.Participants[i].ContactId < statistic.Participants[j].ContactId
+		}
+
+		return statistic.Participants[i].Currency < statistic.Participants[j].Currency
+	})
+
+	return statistic
+}
+
+func (s *EventService) getParticipantStatistic(participantStatistics map[int64]map[string]*models.EventParticipantStatisticResponse, contactId int64, currency string) *models.EventParticipantStatisticResponse {
+	participantCurrencyStatistics, exists := participantStatistics[contactId]
+
+	if !exists {
+		participantCurrencyStatistics = make(map[string]*models.EventParticipantStatisticResponse)
+		participantStatistics[contactId] = participantCurrencyStatistics
+	}
+
+	participantStatistic, exists := participantCurrencyStatistics[currency]
+
+	if !exists {
+		participantStatistic = &models.EventParticipantStatisticResponse{
+			ContactId: contactId,
+			Currency:  currency,
+		}
+		participantCurrencyStatistics[currency] = participantStatistic
+	}
+
+	return participantStatistic
+}
+
+func (s *EventService) getTotalStatistic(currencyStatistics []*models.EventCurrencyStatisticResponse, defaultCurrency string, exchangeRates *models.LatestExchangeRateResponse) *models.EventTotalStatisticResponse {
+	totalStatistic := &models.EventTotalStatisticResponse{
+		Currency: defaultCurrency,
+	}
+
+	for i := 0; i < len(currencyStatistics); i++ {
+		currencyStatistic := currencyStatistics[i]
+		expenseAmount, expenseConverted := exchangeRates.ConvertAmount(currencyStatistic.ExpenseAmount, currencyStatistic.Currency, defaultCurrency)
+		incomeAmount, incomeConverted := exchangeRates.ConvertAmount(currencyStatistic.IncomeAmount, currencyStatistic.Currency, defaultCurrency)
+
+		if !expenseConverted || !incomeConverted {
+			totalStatistic.UnconvertedCurrencies = append(totalStatistic.UnconvertedCurrencies, currencyStatistic.Currency)
+			continue
+		}
+
+		totalStatistic.ExpenseAmount += expenseAmount
+		totalStatistic.IncomeAmount += incomeAmount
+	}
+
+	return totalStatistic
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+func TestGetEventStatistic(t *testing.T) {
+	event := &models.Event{
+		EventId:   1,
+		Name:      "Trip",
+		StartTime: 1700000000,
+		EndTime:   1700086400,
+	}
+
+	accountMap := map[int64]*models.Account{
+		101: {AccountId: 101, Currency: "USD"},
+		102: {AccountId: 102, Currency: "EUR"},
+	}
+
+	transactions := []*models.Transaction{
+		{TransactionId: 1, Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 11, AccountId: 101, Amount: 3000},
+		{TransactionId: 2, Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 12, AccountId: 101, Amount: 1000},
+		{TransactionId: 3, Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 11, AccountId: 102, Amount: 500},
+		{TransactionId: 4, Type: models.TRANSACTION_DB_TYPE_INCOME, CategoryId: 13, AccountId: 101, Amount: 200},
+		{TransactionId: 5, Type: models.TRANSACTION_DB_TYPE_TRANSFER_OUT, CategoryId: 14, AccountId: 101, RelatedAccountId: 103, Amount: 2000},
+	}
+
+	contactTransactions := []*models.ContactTransaction{
+		{TransactionId: 5, ContactId: 1001, Type: models.CONTACT_TRANSACTION_TYPE_SPLIT, Currency: "USD", Amount: 2000},
+		{TransactionId: 6, ContactId: 1002, Type: models.CONTACT_TRANSACTION_TYPE_SPLIT, Currency: "USD", Amount: 800},
+	}
+
+	statistic := Events.GetEventStatistic(event, transactions, accountMap, contactTransactions, "", nil)
+
+	assert.Equal(t, int64(1), statistic.Event.Id)
+	assert.Equal(t, int64(5), statistic.TransactionCount)
+
+	assert.Equal(t, 2, len(statistic.Currencies))
+	assert.Equal(t, "EUR", statistic.Currencies[0].Currency)
+	assert.Equal(t, int64(500), statistic.Currencies[0].ExpenseAmount)
+	assert.Equal(t, "USD", statistic.Currencies[1].Currency)
+	assert.Equal(t, int64(4000), statistic.Currencies[1].ExpenseAmount)
+	assert.Equal(t, int64(200), statistic.Currencies[1].IncomeAmount)
+	assert.Nil(t, statistic.Total)
+
+	assert.Equal(t, 4, len(statistic.Categories))
+	assert.Equal(t, int64(11), statistic.Categories[0].CategoryId)
+	assert.Equal(t, "USD", statistic.Categories[0].Currency)
+	assert.Equal(t, int64(3000), statistic.Categories[0].Amount)
+
+	assert.Equal(t, 3, len(statistic.Participants))
+	assert.Equal(t, int64(0), statistic.Participants[0].ContactId)
+	assert.Equal(t, "EUR", statistic.Participants[0].Currency)
+	assert.Equal(t, int64(500), statistic.Participants[0].Amount)
+	assert.Equal(t, int64(0), statistic.Participants[1].ContactId)
+	assert.Equal(t, "USD", statistic.Participants[1].Currency)
+	assert.Equal(t, int64(4000), statistic.Participants[1].Amount)
+	assert.Equal(t, int64(1001), statistic.Participants[2].ContactId)
+	assert.Equal(t, int64(2000), statistic.Participants[2].Amount)
+}
+
+func TestGetEventStatistic_TotalConvertedToDefaultCurrency(t *testing.T) {
+	event := &models.Event{EventId: 1, Name: "Trip"}
+
+	accountMap := map[int64]*models.Account{
+		101: {AccountId: 101, Currency: "USD"},
+		102: {AccountId: 102, Currency: "EUR"},
+		103: {AccountId: 103, Currency: "JPY"},
+		104: {AccountId: 104, Currency: "XXX"},
+	}
+
+	transactions := []*models.Transaction{
+		{TransactionId: 1, Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 11, AccountId: 101, Amount: 4000},
+		{TransactionId: 2, Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 11, AccountId: 102, Amount: 500},
+		{TransactionId: 3, Type: models.TRANSACTION_DB_TYPE_INCOME, CategoryId: 12, AccountId: 101, Amount: 200},
+		{TransactionId: 4, Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 11, AccountId: 103, Amount: 150000},
+		{TransactionId: 5, Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 11, AccountId: 104, Amount: 100},
+	}
+
+	exchangeRates := &models.LatestExchangeRateResponse{
+		BaseCurrency: "EUR",
+		ExchangeRates: []*models.LatestExchangeRate{
+			{Currency: "USD", Rate: "1.25"},
+			{Currency: "JPY", Rate: "150"},
+		},
+	}
+
+	statistic := Events.GetEventStatistic(event, transactions, accountMap, nil, "USD", exchangeRates)
+
+	assert.Equal(t, 4, len(statistic.Currencies))
+	assert.NotNil(t, statistic.Total)
+	assert.Equal(t, "USD", statistic.Total.Currency)
+	assert.Equal(t, int64(4000+625+1250), statistic.Total.ExpenseAmount)
+	assert.Equal(t, int64(200), statistic.Total.IncomeAmount)
+	assert.Equal(t, []string{"XXX"}, statistic.Total.UnconvertedCurrencies)
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+// LedgerAccessService represents ledger access service, all the ledger-scoped operations resolve the owner and check the permission through it
+type LedgerAccessService struct {
+	ServiceUsingDB
+}
+
+// Initialize a ledger access service singleton instance
+var (
+	LedgerAccesses = &LedgerAccessService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+	}
+)
+
+// GetLedgerAccess returns the access of user to the specified ledger, the ledger can be owned by the user or shared to the user
+func (s *LedgerAccessService) GetLedgerAccess(c core.Context, uid int64, ledgerId int64) (*models.LedgerAccess, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if ledgerId <= 0 {
+		return nil, errs.ErrLedgerIdInvalid
+	}
+
+	exists, err := s.UserDataDB(uid).NewSession(c).Cols("ledger_id").ID(ledgerId).Where("uid=? AND deleted=?", uid, false).Exist(&models.Ledger{})
+
+	if err != nil {
+		return nil, err
+	} else if exists {
+		return &models.LedgerAccess{
+			LedgerId: ledgerId,
+			OwnerUid: uid,
+			Uid:      uid,
+			Role:     models.LEDGER_MEMBER_ROLE_OWNER,
+		}, nil
+	}
+
+	member := &models.LedgerMember{}
+	has, err := s.UserDB().NewSession(c).Where("ledger_id=? AND member_uid=? AND status=?", ledgerId, uid, models.LEDGER_MEMBER_STATUS_ACCEPTED).Get(member)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrLedgerNotFound
+	}
+
+	exists, err = s.UserDataDB(member.OwnerUid).NewSession(c).Cols("ledger_id").ID(ledgerId).Where("uid=? AND deleted=?", member.OwnerUid, false).Exist(&models.Ledger{})
+
+	if err != nil {
+		return nil, err
+	} else if !exists {
+		return nil, errs.ErrLedgerNotFound
+	}
+
+	return &models.LedgerAccess{
+		LedgerId: ledgerId,
+		OwnerUid: member.OwnerUid,
+		Uid:      uid,
+		Role:     member.Role,
+	}, nil
+}
+
+// GetAllLedgersAccessOfOwner returns the access of user to all the ledgers owned by the user, it is used by the operations which are not scoped to a single ledger (e.g. command line and mcp tools)
+func (s *LedgerAccessService) GetAllLedgersAccessOfOwner(uid int64) *models.LedgerAccess {
+	return &models.LedgerAccess{
+		LedgerId: 0,
+		OwnerUid: uid,
+		Uid:      uid,
+		Role:     models.LEDGER_MEMBER_ROLE_OWNER,
+	}
+}
+
+// GetLedgerOwnerUid returns the owner uid and the ledger id of the access if the access has the specified permission, the ledger id is zero only if the user accesses all the ledgers owned by the user
+func (s *LedgerAccessService) GetLedgerOwnerUid(c core.Context, access *models.LedgerAccess, permission models.LedgerPermission) (int64, int64, error) {
+	if access == nil || access.Uid <= 0 || access.OwnerUid <= 0 {
+		return 0, 0, errs.ErrUserIdInvalid
+	}
+
+	if access.LedgerId < 0 || (access.LedgerId == 0 && !access.IsOwner()) {
+		log.Warnf(c, "[ledger_accesses.GetLedgerOwnerUid] user \"uid:%d\" cannot access all ledgers of user \"uid:%d\"", access.Uid, access.OwnerUid)
+		return 0, 0, errs.ErrLedgerIdInvalid
+	}
+
+	if !access.HasPermission(permission) {
+		log.Warnf(c, "[ledger_accesses.GetLedgerOwnerUid] user \"uid:%d\" does not have permission \"%d\" in ledger \"id:%d\"", access.Uid, permission, access.LedgerId)
+		return 0, 0, errs.ErrLedgerPermissionDenied
+	}
+
+	return access.OwnerUid, access.LedgerId, nil
+}
+
+// CheckLedgerPermission returns ErrLedgerPermissionDenied if the access does not have the specified permission
+func (s *LedgerAccessService) CheckLedgerPermission(access *models.LedgerAccess, permission models.LedgerPermission) error {
+	if access == nil || !access.HasPermission(permission) {
+		return errs.ErrLedgerPermissionDenied
+	}
+
+	return nil
+}
+
+// CheckDataInLedger returns ErrLedgerPermissionDenied if the data does not belong to the ledger of the access,
+// only the owner accessing all the ledgers owned by the owner can access the data in any ledger
+func (s *LedgerAccessService) CheckDataInLedger(access *models.LedgerAccess, ledgerId int64) error {
+	if access == nil {
+		return errs.ErrLedgerPermissionDenied
+	}
+
+	if access.LedgerId == 0 && access.IsOwner() {
+		return nil
+	}
+
+	if ledgerId == access.LedgerId {
+		return nil
+	}
+
+	return errs.ErrLedgerPermissionDenied
+}
+
+// CheckTransactionModifiable returns ErrLedgerPermissionDenied if the access cannot modify or delete the specified transaction
+func (s *LedgerAccessService) CheckTransactionModifiable(access *models.LedgerAccess, transaction *models.Transaction) error {
+	if transaction == nil {
+		return errs.ErrLedgerPermissionDenied
+	}
+
+	if err := s.CheckDataInLedger(access, transaction.LedgerId); err != nil {
+		return err
+	}
+
+	if !access.CanModifyTransaction(transaction) {
+		return errs.ErrLedgerPermissionDenied
+	}
+
+	return nil
+}
+
+// CheckTransactionViewable returns ErrLedgerPermissionDenied if the access cannot view the specified transaction
+func (s *LedgerAccessService) CheckTransactionViewable(access *models.LedgerAccess, transaction *models.Transaction) error {
+	if transaction == nil {
+		return errs.ErrLedgerPermissionDenied
+	}
+
+	if err := s.CheckDataInLedger(access, transaction.LedgerId); err != nil {
+		return err
+	}
+
+	if !access.HasPermission(models.LEDGER_PERMISSION_VIEW_TRANSACTIONS) && transaction.CreatorUid != access.Uid {
+		return errs.ErrLedgerPermissionDenied
+	}
+
+	return nil
+}
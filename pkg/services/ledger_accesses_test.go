@@ -0,0 +1,141 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/testutils"
+)
+
+func TestGetLedgerOwnerUid_OwnerAccess(t *testing.T) {
+	c := core.NewNullContext()
+	access := &models.LedgerAccess{LedgerId: 1001, OwnerUid: 1, Uid: 1, Role: models.LEDGER_MEMBER_ROLE_OWNER}
+
+	ownerUid, ledgerId, err := LedgerAccesses.GetLedgerOwnerUid(c, access, models.LEDGER_PERMISSION_MANAGE_LEDGER)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), ownerUid)
+	assert.Equal(t, int64(1001), ledgerId)
+}
+
+func TestGetLedgerOwnerUid_MemberAccess(t *testing.T) {
+	c := core.NewNullContext()
+	access := &models.LedgerAccess{LedgerId: 1001, OwnerUid: 1, Uid: 2, Role: models.LEDGER_MEMBER_ROLE_VIEWER}
+
+	ownerUid, ledgerId, err := LedgerAccesses.GetLedgerOwnerUid(c, access, models.LEDGER_PERMISSION_VIEW_TRANSACTIONS)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), ownerUid)
+	assert.Equal(t, int64(1001), ledgerId)
+
+	_, _, err = LedgerAccesses.GetLedgerOwnerUid(c, access, models.LEDGER_PERMISSION_CREATE_TRANSACTIONS)
+	assert.Equal(t, errs.ErrLedgerPermissionDenied, err)
+}
+
+func TestGetLedgerOwnerUid_AllLedgersAccess(t *testing.T) {
+	c := core.NewNullContext()
+
+	ownerUid, ledgerId, err := LedgerAccesses.GetLedgerOwnerUid(c, LedgerAccesses.GetAllLedgersAccessOfOwner(1), models.LEDGER_PERMISSION_VIEW_TRANSACTIONS)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), ownerUid)
+	assert.Equal(t, int64(0), ledgerId)
+
+	_, _, err = LedgerAccesses.GetLedgerOwnerUid(c, &models.LedgerAccess{LedgerId: 0, OwnerUid: 1, Uid: 2, Role: models.LEDGER_MEMBER_ROLE_EDITOR}, models.LEDGER_PERMISSION_VIEW_TRANSACTIONS)
+	assert.Equal(t, errs.ErrLedgerIdInvalid, err)
+}
+
+func TestGetLedgerOwnerUid_InvalidAccess(t *testing.T) {
+	c := core.NewNullContext()
+
+	_, _, err := LedgerAccesses.GetLedgerOwnerUid(c, nil, models.LEDGER_PERMISSION_VIEW_BASIC_DATA)
+	assert.Equal(t, errs.ErrUserIdInvalid, err)
+
+	_, _, err = LedgerAccesses.GetLedgerOwnerUid(c, &models.LedgerAccess{LedgerId: 1001, Uid: 2, Role: models.LEDGER_MEMBER_ROLE_VIEWER}, models.LEDGER_PERMISSION_VIEW_BASIC_DATA)
+	assert.Equal(t, errs.ErrUserIdInvalid, err)
+}
+
+func TestCheckTransactionModifiable(t *testing.T) {
+	ownTransaction := &models.Transaction{TransactionId: 1, LedgerId: 1001, CreatorUid: 2}
+	otherTransaction := &models.Transaction{TransactionId: 2, LedgerId: 1001, CreatorUid: 1}
+	otherLedgerTransaction := &models.Transaction{TransactionId: 3, LedgerId: 1002, CreatorUid: 2}
+
+	entryOnlyAccess := &models.LedgerAccess{LedgerId: 1001, OwnerUid: 1, Uid: 2, Role: models.LEDGER_MEMBER_ROLE_ENTRY_ONLY}
+	assert.Nil(t, LedgerAccesses.CheckTransactionModifiable(entryOnlyAccess, ownTransaction))
+	assert.Equal(t, errs.ErrLedgerPermissionDenied, LedgerAccesses.CheckTransactionModifiable(entryOnlyAccess, otherTransaction))
+	assert.Equal(t, errs.ErrLedgerPermissionDenied, LedgerAccesses.CheckTransactionModifiable(entryOnlyAccess, otherLedgerTransaction))
+
+	viewerAccess := &models.LedgerAccess{LedgerId: 1001, OwnerUid: 1, Uid: 2, Role: models.LEDGER_MEMBER_ROLE_VIEWER}
+	assert.Equal(t, errs.ErrLedgerPermissionDenied, LedgerAccesses.CheckTransactionModifiable(viewerAccess, ownTransaction))
+	assert.Nil(t, LedgerAccesses.CheckTransactionViewable(viewerAccess, otherTransaction))
+
+	ownerAccess := &models.LedgerAccess{LedgerId: 1001, OwnerUid: 1, Uid: 1, Role: models.LEDGER_MEMBER_ROLE_OWNER}
+	assert.Nil(t, LedgerAccesses.CheckTransactionModifiable(ownerAccess, otherTransaction))
+	assert.Equal(t, errs.ErrLedgerPermissionDenied, LedgerAccesses.CheckTransactionModifiable(ownerAccess, otherLedgerTransaction))
+}
+
+func TestCheckDataInLedger_OwnerAccessOtherLedger(t *testing.T) {
+	ownerAccess := &models.LedgerAccess{LedgerId: 1001, OwnerUid: 1, Uid: 1, Role: models.LEDGER_MEMBER_ROLE_OWNER}
+	assert.Nil(t, LedgerAccesses.CheckDataInLedger(ownerAccess, 1001))
+	assert.Equal(t, errs.ErrLedgerPermissionDenied, LedgerAccesses.CheckDataInLedger(ownerAccess, 1002))
+
+	allLedgersAccess := LedgerAccesses.GetAllLedgersAccessOfOwner(1)
+	assert.Nil(t, LedgerAccesses.CheckDataInLedger(allLedgersAccess, 1001))
+	assert.Nil(t, LedgerAccesses.CheckDataInLedger(allLedgersAccess, 1002))
+
+	memberAccess := &models.LedgerAccess{LedgerId: 1001, OwnerUid: 1, Uid: 2, Role: models.LEDGER_MEMBER_ROLE_EDITOR}
+	assert.Nil(t, LedgerAccesses.CheckDataInLedger(memberAccess, 1001))
+	assert.Equal(t, errs.ErrLedgerPermissionDenied, LedgerAccesses.CheckDataInLedger(memberAccess, 1002))
+	assert.Equal(t, errs.ErrLedgerPermissionDenied, LedgerAccesses.CheckDataInLedger(nil, 1001))
+}
+
+func TestCheckTransactionViewable(t *testing.T) {
+	ownTransaction := &models.Transaction{TransactionId: 1, LedgerId: 1001, CreatorUid: 2}
+	otherTransaction := &models.Transaction{TransactionId: 2, LedgerId: 1001, CreatorUid: 1}
+
+	entryOnlyAccess := &models.LedgerAccess{LedgerId: 1001, OwnerUid: 1, Uid: 2, Role: models.LEDGER_MEMBER_ROLE_ENTRY_ONLY}
+	assert.Nil(t, LedgerAccesses.CheckTransactionViewable(entryOnlyAccess, ownTransaction))
+	assert.Equal(t, errs.ErrLedgerPermissionDenied, LedgerAccesses.CheckTransactionViewable(entryOnlyAccess, otherTransaction))
+	assert.Equal(t, errs.ErrLedgerPermissionDenied, LedgerAccesses.CheckTransactionViewable(entryOnlyAccess, nil))
+}
+
+func TestLedgerScopedQuery_MemberQueriesOwnerData(t *testing.T) {
+	c := testutils.InitializeTestDataStore(t)
+
+	testutils.InsertTestData(t, 1,
+		&models.Ledger{LedgerId: 1001, Uid: 1, Name: "Family", IsDefault: true},
+		&models.Ledger{LedgerId: 1002, Uid: 1, Name: "Personal"},
+		&models.LedgerMember{MemberId: 2001, LedgerId: 1001, OwnerUid: 1, MemberUid: 2, Role: models.LEDGER_MEMBER_ROLE_VIEWER, Status: models.LEDGER_MEMBER_STATUS_ACCEPTED},
+		&models.LedgerMember{MemberId: 2002, LedgerId: 1001, OwnerUid: 1, MemberUid: 3, Role: models.LEDGER_MEMBER_ROLE_ENTRY_ONLY, Status: models.LEDGER_MEMBER_STATUS_ACCEPTED},
+		&models.Account{AccountId: 101, Uid: 1, LedgerId: 1001, Name: "Family Cash"},
+		&models.Account{AccountId: 102, Uid: 1, LedgerId: 1002, Name: "Personal Cash"},
+		&models.Transaction{TransactionId: 501, Uid: 1, LedgerId: 1001, AccountId: 101, TransactionTime: 1000},
+		&models.Transaction{TransactionId: 502, Uid: 1, LedgerId: 1002, AccountId: 102, TransactionTime: 2000},
+	)
+
+	viewerAccess, err := LedgerAccesses.GetLedgerAccess(c, 2, 1001)
+	assert.Nil(t, err)
+
+	accounts, err := Accounts.GetAllAccountsByUid(c, viewerAccess)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(accounts))
+	assert.Equal(t, int64(101), accounts[0].AccountId)
+
+	transactions, err := Transactions.GetTransactionsByMaxTime(c, viewerAccess, 0, 0, 0, nil, nil, nil, false, nil, "", "", 1, 10, false, false)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(transactions))
+	assert.Equal(t, int64(501), transactions[0].TransactionId)
+
+	entryOnlyAccess, err := LedgerAccesses.GetLedgerAccess(c, 3, 1001)
+	assert.Nil(t, err)
+
+	_, err = Accounts.GetAllAccountsByUid(c, entryOnlyAccess)
+	assert.Nil(t, err)
+
+	_, err = Transactions.GetTransactionsByMaxTime(c, entryOnlyAccess, 0, 0, 0, nil, nil, nil, false, nil, "", "", 1, 10, false, false)
+	assert.Equal(t, errs.ErrLedgerPermissionDenied, err)
+
+	_, err = LedgerAccesses.GetLedgerAccess(c, 2, 1002)
+	assert.Equal(t, errs.ErrLedgerNotFound, err)
+}
@@ -0,0 +1,324 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/locales"
+	"github.com/mayswind/ezbookkeeping/pkg/mail"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/templates"
+	"github.com/mayswind/ezbookkeeping/pkg/uuid"
+)
+
+const ledgerInvitationUrlFormat = "%sdesktop#/ledger/list"
+
+// LedgerMemberService represents ledger member service
+type LedgerMemberService struct {
+	ServiceUsingDB
+	ServiceUsingConfig
+	ServiceUsingMailer
+	ServiceUsingUuid
+}
+
+// Initialize a ledger member service singleton instance
+var (
+	LedgerMembers = &LedgerMemberService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingConfig: ServiceUsingConfig{
+			container: settings.Container,
+		},
+		ServiceUsingMailer: ServiceUsingMailer{
+			container: mail.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+	}
+)
+
+// GetAllMembersByLedgerId returns all member models of the specified ledger
+func (s *LedgerMemberService) GetAllMembersByLedgerId(c core.Context, ownerUid int64, ledgerId int64) ([]*models.LedgerMember, error) {
+	if ownerUid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if ledgerId <= 0 {
+		return nil, errs.ErrLedgerIdInvalid
+	}
+
+	var members []*models.LedgerMember
+	err := s.UserDB().NewSession(c).Where("ledger_id=? AND owner_uid=?", ledgerId, ownerUid).Find(&members)
+
+	return members, err
+}
+
+// GetAllSharedLedgerMembersByMemberUid returns all member models of ledgers shared to the user in the specified status
+func (s *LedgerMemberService) GetAllSharedLedgerMembersByMemberUid(c core.Context, memberUid int64, status models.LedgerMemberStatus) ([]*models.LedgerMember, error) {
+	if memberUid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var members []*models.LedgerMember
+	err := s.UserDB().NewSession(c).Where("member_uid=? AND status=?", memberUid, status).OrderBy("created_unix_time asc").Find(&members)
+
+	return members, err
+}
+
+// GetSharedLedgers returns the ledger models of the specified member models, the ledgers which have been deleted are ignored
+func (s *LedgerMemberService) GetSharedLedgers(c core.Context, members []*models.LedgerMember) (map[int64]*models.Ledger, error) {
+	ledgerMap := make(map[int64]*models.Ledger, len(members))
+
+	for i := 0; i < len(members); i++ {
+		member := members[i]
+		ledger := &models.Ledger{}
+		has, err := s.UserDataDB(member.OwnerUid).NewSession(c).ID(member.LedgerId).Where("uid=? AND deleted=?", member.OwnerUid, false).Get(ledger)
+
+		if err != nil {
+			return nil, err
+		} else if !has {
+			continue
+		}
+
+		ledgerMap[ledger.LedgerId] = ledger
+	}
+
+	return ledgerMap, nil
+}
+
+// InviteMember saves a new invited member of the ledger to database
+func (s *LedgerMemberService) InviteMember(c core.Context, member *models.LedgerMember) error {
+	if member.OwnerUid <= 0 || member.MemberUid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if member.LedgerId <= 0 {
+		return errs.ErrLedgerIdInvalid
+	}
+
+	if member.OwnerUid == member.MemberUid {
+		return errs.ErrCannotInviteYourselfToLedger
+	}
+
+	if !member.Role.IsValidMemberRole() {
+		return errs.ErrLedgerMemberRoleInvalid
+	}
+
+	exists, err := s.UserDataDB(member.OwnerUid).NewSession(c).Cols("ledger_id").ID(member.LedgerId).Where("uid=? AND deleted=?", member.OwnerUid, false).Exist(&models.Ledger{})
+
+	if err != nil {
+		return err
+	} else if !exists {
+		return errs.ErrOnlyOwnLedgerCanBeShared
+	}
+
+	member.MemberId = s.GenerateUuid(uuid.UUID_TYPE_LEDGER_MEMBER)
+
+	if member.MemberId < 1 {
+		return errs.ErrSystemIsBusy
+	}
+
+	member.Status = models.LEDGER_MEMBER_STATUS_INVITED
+	member.CreatedUnixTime = time.Now().Unix()
+	member.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		exists, err := sess.Cols("member_id").Where("ledger_id=? AND member_uid=?", member.LedgerId, member.MemberUid).Exist(&models.LedgerMember{})
+
+		if err != nil {
+			return err
+		} else if exists {
+			return errs.ErrLedgerMemberAlreadyExists
+		}
+
+		_, err = sess.Insert(member)
+		return err
+	})
+}
+
+// AcceptInvitation accepts the invitation of the specified ledger for the user
+func (s *LedgerMemberService) AcceptInvitation(c core.Context, memberUid int64, ledgerId int64) error {
+	if memberUid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if ledgerId <= 0 {
+		return errs.ErrLedgerIdInvalid
+	}
+
+	updateModel := &models.LedgerMember{
+		Status:          models.LEDGER_MEMBER_STATUS_ACCEPTED,
+		UpdatedUnixTime: time.Now().Unix(),
+	}
+
+	return s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		updatedRows, err := sess.Cols("status", "updated_unix_time").Where("ledger_id=? AND member_uid=? AND status=?", ledgerId, memberUid, models.LEDGER_MEMBER_STATUS_INVITED).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrLedgerInvitationNotFound
+		}
+
+		return err
+	})
+}
+
+// DeclineInvitation declines the invitation of the specified ledger for the user
+func (s *LedgerMemberService) DeclineInvitation(c core.Context, memberUid int64, ledgerId int64) error {
+	return s.deleteMember(c, "ledger_id=? AND member_uid=? AND status=?", []any{ledgerId, memberUid, models.LEDGER_MEMBER_STATUS_INVITED}, memberUid, ledgerId, errs.ErrLedgerInvitationNotFound)
+}
+
+// LeaveLedger removes the user from the specified shared ledger
+func (s *LedgerMemberService) LeaveLedger(c core.Context, memberUid int64, ledgerId int64) error {
+	return s.deleteMember(c, "ledger_id=? AND member_uid=? AND status=?", []any{ledgerId, memberUid, models.LEDGER_MEMBER_STATUS_ACCEPTED}, memberUid, ledgerId, errs.ErrLedgerNotFound)
+}
+
+// ModifyMemberRole updates the role of the specified member in the ledger
+func (s *LedgerMemberService) ModifyMemberRole(c core.Context, ownerUid int64, ledgerId int64, memberUid int64, role models.LedgerMemberRole) error {
+	if ownerUid <= 0 || memberUid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if ledgerId <= 0 {
+		return errs.ErrLedgerIdInvalid
+	}
+
+	if !role.IsValidMemberRole() {
+		return errs.ErrLedgerMemberRoleInvalid
+	}
+
+	updateModel := &models.LedgerMember{
+		Role:            role,
+		UpdatedUnixTime: time.Now().Unix(),
+	}
+
+	return s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		updatedRows, err := sess.Cols("role", "updated_unix_time").Where("ledger_id=? AND owner_uid=? AND member_uid=?", ledgerId, ownerUid, memberUid).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrLedgerMemberNotFound
+		}
+
+		return err
+	})
+}
+
+// RemoveMember removes the specified member or invitation from the ledger
+func (s *LedgerMemberService) RemoveMember(c core.Context, ownerUid int64, ledgerId int64, memberUid int64) error {
+	if ownerUid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	return s.deleteMember(c, "ledger_id=? AND owner_uid=? AND member_uid=?", []any{ledgerId, ownerUid, memberUid}, memberUid, ledgerId, errs.ErrLedgerMemberNotFound)
+}
+
+// DeleteAllMembersOfLedger deletes all members and invitations of the specified ledger
+func (s *LedgerMemberService) DeleteAllMembersOfLedger(c core.Context, ownerUid int64, ledgerId int64) error {
+	if ownerUid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	return s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Where("ledger_id=? AND owner_uid=?", ledgerId, ownerUid).Delete(&models.LedgerMember{})
+		return err
+	})
+}
+
+// DeleteAllMembersByOwnerUid deletes all members and invitations of all ledgers owned by the user
+func (s *LedgerMemberService) DeleteAllMembersByOwnerUid(c core.Context, ownerUid int64) error {
+	if ownerUid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	return s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Where("owner_uid=?", ownerUid).Delete(&models.LedgerMember{})
+		return err
+	})
+}
+
+// SendLedgerInvitationEmail sends ledger invitation email to the invited user
+func (s *LedgerMemberService) SendLedgerInvitationEmail(c core.Context, owner *models.User, invitedUser *models.User, ledger *models.Ledger, backupLocale string) error {
+	if !s.CurrentConfig().EnableSMTP {
+		return errs.ErrSMTPServerNotEnabled
+	}
+
+	locale := invitedUser.Language
+
+	if locale == "" {
+		locale = backupLocale
+	}
+
+	localeTextItems := locales.GetLocaleTextItems(locale)
+	ledgerInvitationTextItems := localeTextItems.LedgerInvitationMailTextItems
+
+	invitationUrl := fmt.Sprintf(ledgerInvitationUrlFormat, s.CurrentConfig().RootUrl)
+
+	tmpl, err := templates.GetTemplate(templates.TEMPLATE_LEDGER_INVITATION)
+
+	if err != nil {
+		return err
+	}
+
+	templateParams := map[string]any{
+		"AppName": localeTextItems.GlobalTextItems.AppName,
+		"LedgerInvitationMail": map[string]any{
+			"Title":               ledgerInvitationTextItems.Title,
+			"Salutation":          fmt.Sprintf(ledgerInvitationTextItems.SalutationFormat, invitedUser.Nickname),
+			"Description":         fmt.Sprintf(ledgerInvitationTextItems.DescriptionFormat, owner.Nickname, ledger.Name),
+			"InvitationUrl":       invitationUrl,
+			"ViewInvitation":      ledgerInvitationTextItems.ViewInvitation,
+			"DescriptionBelowBtn": ledgerInvitationTextItems.DescriptionBelowBtn,
+		},
+	}
+
+	var bodyBuffer bytes.Buffer
+	err = tmpl.Execute(&bodyBuffer, templateParams)
+
+	if err != nil {
+		return err
+	}
+
+	message := &mail.MailMessage{
+		To:      invitedUser.Email,
+		Subject: ledgerInvitationTextItems.Title,
+		Body:    bodyBuffer.String(),
+	}
+
+	err = s.SendMail(message)
+
+	return err
+}
+
+func (s *LedgerMemberService) deleteMember(c core.Context, condition string, conditionParams []any, memberUid int64, ledgerId int64, notFoundErr error) error {
+	if memberUid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if ledgerId <= 0 {
+		return errs.ErrLedgerIdInvalid
+	}
+
+	return s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		deletedRows, err := sess.Where(condition, conditionParams...).Delete(&models.LedgerMember{})
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return notFoundErr
+		}
+
+		return err
+	})
+}
@@ -0,0 +1,432 @@
+package services
+
+import (
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/uuid"
+)
+
+const pageCountForMigrateUsersDefaultLedger = 100
+
+// LedgerService represents ledger service
+type LedgerService struct {
+	ServiceUsingDB
+	ServiceUsingUuid
+}
+
+// Initialize a ledger service singleton instance
+var (
+	Ledgers = &LedgerService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+	}
+)
+
+// GetAllLedgersByUid returns all ledger models of user
+func (s *LedgerService) GetAllLedgersByUid(c core.Context, uid int64) ([]*models.Ledger, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var ledgers []*models.Ledger
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).OrderBy("display_order asc").Find(&ledgers)
+
+	return ledgers, err
+}
+
+// GetLedgerByLedgerId returns a ledger model according to ledger id
+func (s *LedgerService) GetLedgerByLedgerId(c core.Context, uid int64, ledgerId int64) (*models.Ledger, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if ledgerId <= 0 {
+		return nil, errs.ErrLedgerIdInvalid
+	}
+
+	ledger := &models.Ledger{}
+	has, err := s.UserDataDB(uid).NewSession(c).ID(ledgerId).Where("uid=? AND deleted=?", uid, false).Get(ledger)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrLedgerNotFound
+	}
+
+	return ledger, nil
+}
+
+// GetDefaultLedger returns the default ledger model of user, it returns ErrLedgerNotFound if the user does not have any ledger
+func (s *LedgerService) GetDefaultLedger(c core.Context, uid int64) (*models.Ledger, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	ledger := &models.Ledger{}
+	has, err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=? AND is_default=?", uid, false, true).Get(ledger)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrLedgerNotFound
+	}
+
+	return ledger, nil
+}
+
+// GetOrCreateDefaultLedger returns the default ledger model of user, and creates it if the user does not have one,
+// it should only be called when creating user, clearing user data or updating database, rather than in every request
+func (s *LedgerService) GetOrCreateDefaultLedger(c core.Context, uid int64) (*models.Ledger, error) {
+	ledger, err := s.GetDefaultLedger(c, uid)
+
+	if err == nil {
+		return ledger, nil
+	} else if err != errs.ErrLedgerNotFound {
+		return nil, err
+	}
+
+	maxOrderId, err := s.GetMaxDisplayOrder(c, uid)
+
+	if err != nil {
+		return nil, err
+	}
+
+	ledger = &models.Ledger{
+		Uid:          uid,
+		Name:         models.LedgerDefaultName,
+		DisplayOrder: maxOrderId + 1,
+		IsDefault:    true,
+	}
+
+	ledger.LedgerId = s.GenerateUuid(uuid.UUID_TYPE_LEDGER)
+
+	if ledger.LedgerId < 1 {
+		return nil, errs.ErrSystemIsBusy
+	}
+
+	ledger.Deleted = false
+	ledger.CreatedUnixTime = time.Now().Unix()
+	ledger.UpdatedUnixTime = time.Now().Unix()
+
+	err = s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Insert(ledger)
+
+		if err != nil {
+			return err
+		}
+
+		return s.moveDataWithoutLedgerToLedger(sess, uid, ledger.LedgerId)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ledger, nil
+}
+
+// GetMaxDisplayOrder returns the max display order
+func (s *LedgerService) GetMaxDisplayOrder(c core.Context, uid int64) (int32, error) {
+	if uid <= 0 {
+		return 0, errs.ErrUserIdInvalid
+	}
+
+	ledger := &models.Ledger{}
+	has, err := s.UserDataDB(uid).NewSession(c).Cols("uid", "deleted", "display_order").Where("uid=? AND deleted=?", uid, false).OrderBy("display_order desc").Limit(1).Get(ledger)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if has {
+		return ledger.DisplayOrder, nil
+	} else {
+		return 0, nil
+	}
+}
+
+// CreateLedger saves a new ledger model to database
+func (s *LedgerService) CreateLedger(c core.Context, ledger *models.Ledger) error {
+	if ledger.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	exists, err := s.ExistsLedgerName(c, ledger.Uid, ledger.Name)
+
+	if err != nil {
+		return err
+	} else if exists {
+		return errs.ErrLedgerNameAlreadyExists
+	}
+
+	ledger.LedgerId = s.GenerateUuid(uuid.UUID_TYPE_LEDGER)
+
+	if ledger.LedgerId < 1 {
+		return errs.ErrSystemIsBusy
+	}
+
+	ledger.Deleted = false
+	ledger.IsDefault = false
+	ledger.CreatedUnixTime = time.Now().Unix()
+	ledger.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDataDB(ledger.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Insert(ledger)
+		return err
+	})
+}
+
+// ModifyLedger saves an existed ledger model to database
+func (s *LedgerService) ModifyLedger(c core.Context, ledger *models.Ledger) error {
+	if ledger.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	ledger.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDataDB(ledger.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		exists, err := sess.Cols("ledger_id").Where("uid=? AND deleted=? AND name=? AND ledger_id<>?", ledger.Uid, false, ledger.Name, ledger.LedgerId).Exist(&models.Ledger{})
+
+		if err != nil {
+			return err
+		} else if exists {
+			return errs.ErrLedgerNameAlreadyExists
+		}
+
+		updatedRows, err := sess.ID(ledger.LedgerId).Cols("name", "comment", "updated_unix_time").Where("uid=? AND deleted=?", ledger.Uid, false).Update(ledger)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrLedgerNotFound
+		}
+
+		return err
+	})
+}
+
+// SetDefaultLedger sets the given ledger as the default ledger of user
+func (s *LedgerService) SetDefaultLedger(c core.Context, uid int64, ledgerId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if ledgerId <= 0 {
+		return errs.ErrLedgerIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		exists, err := sess.ID(ledgerId).Where("uid=? AND deleted=?", uid, false).Exist(&models.Ledger{})
+
+		if err != nil {
+			return err
+		} else if !exists {
+			return errs.ErrLedgerNotFound
+		}
+
+		_, err = sess.Cols("is_default", "updated_unix_time").Where("uid=? AND deleted=? AND is_default=?", uid, false, true).Update(&models.Ledger{IsDefault: false, UpdatedUnixTime: now})
+
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.ID(ledgerId).Cols("is_default", "updated_unix_time").Where("uid=? AND deleted=?", uid, false).Update(&models.Ledger{IsDefault: true, UpdatedUnixTime: now})
+
+		return err
+	})
+}
+
+// ModifyLedgerDisplayOrders updates display order of given ledgers
+func (s *LedgerService) ModifyLedgerDisplayOrders(c core.Context, uid int64, ledgers []*models.Ledger) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	for i := 0; i < len(ledgers); i++ {
+		ledgers[i].UpdatedUnixTime = time.Now().Unix()
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		for i := 0; i < len(ledgers); i++ {
+			ledger := ledgers[i]
+			updatedRows, err := sess.ID(ledger.LedgerId).Cols("display_order", "updated_unix_time").Where("uid=? AND deleted=?", uid, false).Update(ledger)
+
+			if err != nil {
+				return err
+			} else if updatedRows < 1 {
+				return errs.ErrLedgerNotFound
+			}
+		}
+
+		return nil
+	})
+}
+
+// DeleteLedger deletes an existed ledger from database
+func (s *LedgerService) DeleteLedger(c core.Context, uid int64, ledgerId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.Ledger{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		ledger := &models.Ledger{}
+		has, err := sess.ID(ledgerId).Where("uid=? AND deleted=?", uid, false).Get(ledger)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrLedgerNotFound
+		} else if ledger.IsDefault {
+			return errs.ErrDefaultLedgerCannotBeDeleted
+		}
+
+		exists, err := sess.Cols("uid", "deleted", "ledger_id").Where("uid=? AND deleted=? AND ledger_id=?", uid, false, ledgerId).Limit(1).Exist(&models.Account{})
+
+		if err != nil {
+			return err
+		} else if exists {
+			return errs.ErrLedgerInUseCannotBeDeleted
+		}
+
+		exists, err = sess.Cols("uid", "deleted", "ledger_id").Where("uid=? AND deleted=? AND ledger_id=?", uid, false, ledgerId).Limit(1).Exist(&models.Transaction{})
+
+		if err != nil {
+			return err
+		} else if exists {
+			return errs.ErrLedgerInUseCannotBeDeleted
+		}
+
+		deletedRows, err := sess.ID(ledgerId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrLedgerNotFound
+		}
+
+		return err
+	})
+}
+
+// DeleteAllLedgers deletes all existed ledgers from database
+func (s *LedgerService) DeleteAllLedgers(c core.Context, uid int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	updateModel := &models.Ledger{
+		Deleted:         true,
+		DeletedUnixTime: time.Now().Unix(),
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+		return err
+	})
+}
+
+// ExistsLedgerName returns whether the given ledger name exists
+func (s *LedgerService) ExistsLedgerName(c core.Context, uid int64, name string) (bool, error) {
+	if name == "" {
+		return false, errs.ErrLedgerNameIsEmpty
+	}
+
+	return s.UserDataDB(uid).NewSession(c).Cols("name").Where("uid=? AND deleted=? AND name=?", uid, false, name).Exist(&models.Ledger{})
+}
+
+// CreateDefaultLedgersForAllUsers creates default ledger for all users who do not have one and moves their existing data into it
+func (s *LedgerService) CreateDefaultLedgersForAllUsers(c core.Context) (int, error) {
+	maxUid := int64(0)
+	createdCount := 0
+
+	for {
+		var users []*models.User
+		err := s.UserDB().NewSession(c).Cols("uid").Where("uid>? AND deleted=?", maxUid, false).OrderBy("uid asc").Limit(pageCountForMigrateUsersDefaultLedger, 0).Find(&users)
+
+		if err != nil {
+			return createdCount, err
+		}
+
+		for i := 0; i < len(users); i++ {
+			uid := users[i].Uid
+			_, err := s.GetDefaultLedger(c, uid)
+
+			if err == nil {
+				continue
+			} else if err != errs.ErrLedgerNotFound {
+				return createdCount, err
+			}
+
+			_, err = s.GetOrCreateDefaultLedger(c, uid)
+
+			if err != nil {
+				return createdCount, err
+			}
+
+			createdCount++
+		}
+
+		if len(users) < pageCountForMigrateUsersDefaultLedger {
+			break
+		}
+
+		maxUid = users[len(users)-1].Uid
+	}
+
+	return createdCount, nil
+}
+
+// GetLedgerMapByList returns a ledger map by a list
+func (s *LedgerService) GetLedgerMapByList(ledgers []*models.Ledger) map[int64]*models.Ledger {
+	ledgerMap := make(map[int64]*models.Ledger)
+
+	for i := 0; i < len(ledgers); i++ {
+		ledger := ledgers[i]
+		ledgerMap[ledger.LedgerId] = ledger
+	}
+
+	return ledgerMap
+}
+
+func (s *LedgerService) moveDataWithoutLedgerToLedger(sess *xorm.Session, uid int64, ledgerId int64) error {
+	if _, err := sess.Cols("ledger_id").Where("uid=? AND ledger_id=?", uid, 0).Update(&models.Account{LedgerId: ledgerId}); err != nil {
+		return err
+	}
+
+	if _, err := sess.Cols("ledger_id").Where("uid=? AND ledger_id=?", uid, 0).Update(&models.Transaction{LedgerId: ledgerId}); err != nil {
+		return err
+	}
+
+	if _, err := sess.Cols("ledger_id").Where("uid=? AND ledger_id=?", uid, 0).Update(&models.TransactionCategory{LedgerId: ledgerId}); err != nil {
+		return err
+	}
+
+	if _, err := sess.Cols("ledger_id").Where("uid=? AND ledger_id=?", uid, 0).Update(&models.TransactionTag{LedgerId: ledgerId}); err != nil {
+		return err
+	}
+
+	if _, err := sess.Cols("ledger_id").Where("uid=? AND ledger_id=?", uid, 0).Update(&models.TransactionTemplate{LedgerId: ledgerId}); err != nil {
+		return err
+	}
+
+	return nil
+}
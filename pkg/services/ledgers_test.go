@@ -0,0 +1,136 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/testutils"
+)
+
+func TestGetLedgerMapByList_EmptyList(t *testing.T) {
+	ledgers := make([]*models.Ledger, 0)
+	actualLedgerMap := Ledgers.GetLedgerMapByList(ledgers)
+
+	assert.NotNil(t, actualLedgerMap)
+	assert.Equal(t, 0, len(actualLedgerMap))
+}
+
+func TestGetLedgerMapByList_MultipleLedgers(t *testing.T) {
+	ledgers := []*models.Ledger{
+		{
+			LedgerId:  1001,
+			Name:      "Personal",
+			IsDefault: true,
+		},
+		{
+			LedgerId:  1002,
+			Name:      "Business",
+			IsDefault: false,
+		},
+	}
+	actualLedgerMap := Ledgers.GetLedgerMapByList(ledgers)
+
+	assert.Equal(t, 2, len(actualLedgerMap))
+	assert.Contains(t, actualLedgerMap, int64(1001))
+	assert.Equal(t, "Personal", actualLedgerMap[1001].Name)
+	assert.Equal(t, true, actualLedgerMap[1001].IsDefault)
+	assert.Contains(t, actualLedgerMap, int64(1002))
+	assert.Equal(t, "Business", actualLedgerMap[1002].Name)
+	assert.Equal(t, false, actualLedgerMap[1002].IsDefault)
+}
+
+func TestCreateDefaultLedgersForAllUsers_CreateLedgerOnlyForUsersWithoutLedger(t *testing.T) {
+	c := testutils.InitializeTestDataStore(t)
+
+	testutils.InsertTestUser(t, 1)
+	testutils.InsertTestUser(t, 2)
+	testutils.InsertTestUser(t, 3)
+	testutils.InsertTestData(t, 2, &models.Ledger{LedgerId: 1001, Uid: 2, Name: "Existed", IsDefault: true})
+
+	createdCount, err := Ledgers.CreateDefaultLedgersForAllUsers(c)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, createdCount)
+
+	for uid := int64(1); uid <= 3; uid++ {
+		ledgers, err := Ledgers.GetAllLedgersByUid(c, uid)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(ledgers))
+		assert.True(t, ledgers[0].IsDefault)
+	}
+
+	ledger, err := Ledgers.GetDefaultLedger(c, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1001), ledger.LedgerId)
+	assert.Equal(t, "Existed", ledger.Name)
+
+	createdCount, err = Ledgers.CreateDefaultLedgersForAllUsers(c)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, createdCount)
+}
+
+func TestCreateDefaultLedgersForAllUsers_MoveDataWithoutLedgerToDefaultLedger(t *testing.T) {
+	c := testutils.InitializeTestDataStore(t)
+
+	testutils.InsertTestUser(t, 1)
+	testutils.InsertTestUser(t, 2)
+	testutils.InsertTestData(t, 1,
+		&models.Account{AccountId: 101, Uid: 1, Name: "Cash"},
+		&models.TransactionCategory{CategoryId: 201, Uid: 1, Name: "Food"},
+		&models.TransactionTag{TagId: 301, Uid: 1, Name: "Trip"},
+		&models.TransactionTemplate{TemplateId: 401, Uid: 1, TemplateType: models.TRANSACTION_TEMPLATE_TYPE_NORMAL, Name: "Lunch"},
+		&models.Transaction{TransactionId: 501, Uid: 1, AccountId: 101, CategoryId: 201, TransactionTime: 1},
+		&models.Account{AccountId: 102, Uid: 2, Name: "Other User Cash"},
+		&models.Account{AccountId: 103, Uid: 1, LedgerId: 9999, Name: "Other Ledger Cash"},
+	)
+
+	_, err := Ledgers.CreateDefaultLedgersForAllUsers(c)
+	assert.Nil(t, err)
+
+	ledger, err := Ledgers.GetDefaultLedger(c, 1)
+	assert.Nil(t, err)
+
+	access, err := LedgerAccesses.GetLedgerAccess(c, 1, ledger.LedgerId)
+	assert.Nil(t, err)
+
+	accounts, err := Accounts.GetAllAccountsByUid(c, access)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(accounts))
+	assert.Equal(t, int64(101), accounts[0].AccountId)
+
+	categories, err := TransactionCategories.GetAllCategoriesByUid(c, access, 0, -1)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(categories))
+	assert.Equal(t, int64(201), categories[0].CategoryId)
+
+	tags, err := TransactionTags.GetAllTagsByUid(c, access)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(tags))
+	assert.Equal(t, int64(301), tags[0].TagId)
+
+	templates, err := TransactionTemplates.GetAllTemplatesByUid(c, access, models.TRANSACTION_TEMPLATE_TYPE_NORMAL)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(templates))
+	assert.Equal(t, int64(401), templates[0].TemplateId)
+
+	transaction, err := Transactions.GetTransactionByTransactionId(c, 1, 501)
+	assert.Nil(t, err)
+	assert.Equal(t, ledger.LedgerId, transaction.LedgerId)
+
+	otherLedgerAccounts, err := Accounts.GetAllAccountsByUid(c, &models.LedgerAccess{LedgerId: 9999, OwnerUid: 1, Uid: 1, Role: models.LEDGER_MEMBER_ROLE_OWNER})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(otherLedgerAccounts))
+	assert.Equal(t, int64(103), otherLedgerAccounts[0].AccountId)
+
+	otherUserLedger, err := Ledgers.GetDefaultLedger(c, 2)
+	assert.Nil(t, err)
+
+	otherUserAccess, err := LedgerAccesses.GetLedgerAccess(c, 2, otherUserLedger.LedgerId)
+	assert.Nil(t, err)
+
+	otherUserAccounts, err := Accounts.GetAllAccountsByUid(c, otherUserAccess)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(otherUserAccounts))
+	assert.Equal(t, int64(102), otherUserAccounts[0].AccountId)
+}
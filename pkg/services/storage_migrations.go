@@ -0,0 +1,186 @@
+package services
+
+import (
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/storage"
+)
+
+// StorageMigrationService represents object storage migration service
+type StorageMigrationService struct {
+	ServiceUsingDB
+	ServiceUsingStorage
+}
+
+// Initialize a object storage migration service singleton instance
+var (
+	StorageMigrations = &StorageMigrationService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingStorage: ServiceUsingStorage{
+			container: storage.Container,
+		},
+	}
+)
+
+// MigrateAvatars copies all user custom avatars from the source storage container to the target storage container
+func (s *StorageMigrationService) MigrateAvatars(c core.Context, from *storage.StorageContainer, to *storage.StorageContainer, dryRun bool, summary *storage.ObjectMigrationSummary) error {
+	return s.migrateObjects(c, storage.STORAGE_OBJECT_TYPE_AVATAR, from, to, dryRun, summary)
+}
+
+// MigrateTransactionPictures copies all transaction pictures and their thumbnails from the source storage container to the target storage container
+func (s *StorageMigrationService) MigrateTransactionPictures(c core.Context, from *storage.StorageContainer, to *storage.StorageContainer, dryRun bool, summary *storage.ObjectMigrationSummary) error {
+	return s.migrateObjects(c, storage.STORAGE_OBJECT_TYPE_TRANSACTION_PICTURE, from, to, dryRun, summary)
+}
+
+// MigrateTransactionAttachments copies all transaction attachments and their thumbnails from the source storage container to the target storage container
+func (s *StorageMigrationService) MigrateTransactionAttachments(c core.Context, from *storage.StorageContainer, to *storage.StorageContainer, dryRun bool, summary *storage.ObjectMigrationSummary) error {
+	return s.migrateObjects(c, storage.STORAGE_OBJECT_TYPE_TRANSACTION_ATTACHMENT, from, to, dryRun, summary)
+}
+
+// GetAllObjectPaths returns the paths of all objects of the specified object type which are referenced in database
+func (s *StorageMigrationService) GetAllObjectPaths(c core.Context, objectType storage.StorageObjectType) ([]*storage.StorageObjectPath, error) {
+	switch objectType {
+	case storage.STORAGE_OBJECT_TYPE_AVATAR:
+		return s.getAllAvatarPaths(c)
+	case storage.STORAGE_OBJECT_TYPE_TRANSACTION_PICTURE:
+		return s.getAllTransactionPicturePaths(c)
+	case storage.STORAGE_OBJECT_TYPE_TRANSACTION_ATTACHMENT:
+		return s.getAllTransactionAttachmentPaths(c)
+	default:
+		return nil, errs.ErrNotSupported
+	}
+}
+
+func (s *StorageMigrationService) getAllAvatarPaths(c core.Context) ([]*storage.StorageObjectPath, error) {
+	var users []*models.User
+	err := s.UserDB().NewSession(c).Cols("uid", "custom_avatar_type").Where("deleted=? AND custom_avatar_type<>?", false, "").Find(&users)
+
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]*storage.StorageObjectPath, 0, len(users))
+
+	for i := 0; i < len(users); i++ {
+		paths = append(paths, &storage.StorageObjectPath{
+			Path: s.getUserAvatarPath(users[i].Uid, users[i].CustomAvatarType),
+		})
+	}
+
+	return paths, nil
+}
+
+func (s *StorageMigrationService) getAllTransactionPicturePaths(c core.Context) ([]*storage.StorageObjectPath, error) {
+	var paths []*storage.StorageObjectPath
+
+	for i := 0; i < s.UserDataDBCount(); i++ {
+		var pictureInfos []*models.TransactionPictureInfo
+		err := s.UserDataDBByIndex(i).NewSession(c).Cols("uid", "picture_id", "picture_extension", "stored_picture_id").Where("deleted=?", false).Find(&pictureInfos)
+
+		if err != nil {
+			return nil, err
+		}
+
+		storedPictureIds := make(map[int64]bool, len(pictureInfos))
+
+		for j := 0; j < len(pictureInfos); j++ {
+			pictureInfo := pictureInfos[j]
+			storedPictureId := pictureInfo.GetStoredPictureId()
+
+			if storedPictureIds[storedPictureId] {
+				continue
+			}
+
+			storedPictureIds[storedPictureId] = true
+			paths = append(paths, &storage.StorageObjectPath{
+				Path: s.getTransactionPicturePath(pictureInfo.Uid, storedPictureId, pictureInfo.PictureExtension),
+			})
+
+			// thumbnails are generated on demand, so it is normal that some thumbnails do not exist
+			for _, size := range models.TransactionPictureThumbnailSizes {
+				paths = append(paths, &storage.StorageObjectPath{
+					Path:     s.getTransactionPicturePath(pictureInfo.Uid, storedPictureId, size.GetFileExtension()),
+					Optional: true,
+				})
+			}
+		}
+	}
+
+	return paths, nil
+}
+
+func (s *StorageMigrationService) getAllTransactionAttachmentPaths(c core.Context) ([]*storage.StorageObjectPath, error) {
+	var paths []*storage.StorageObjectPath
+
+	for i := 0; i < s.UserDataDBCount(); i++ {
+		var attachments []*models.TransactionAttachment
+		err := s.UserDataDBByIndex(i).NewSession(c).Cols("uid", "attachment_id", "file_extension", "has_thumbnail").Where("deleted=?", false).Find(&attachments)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for j := 0; j < len(attachments); j++ {
+			attachment := attachments[j]
+			paths = append(paths, &storage.StorageObjectPath{
+				Path: s.getTransactionAttachmentPath(attachment.Uid, attachment.AttachmentId, attachment.FileExtension),
+			})
+
+			if attachment.HasThumbnail {
+				paths = append(paths, &storage.StorageObjectPath{
+					Path: s.getTransactionAttachmentPath(attachment.Uid, attachment.AttachmentId, models.TransactionAttachmentThumbnailFileExtension),
+				})
+			}
+		}
+	}
+
+	return paths, nil
+}
+
+func (s *StorageMigrationService) migrateObjects(c core.Context, objectType storage.StorageObjectType, from *storage.StorageContainer, to *storage.StorageContainer, dryRun bool, summary *storage.ObjectMigrationSummary) error {
+	fromStorage := from.GetObjectStorage(objectType)
+	toStorage := to.GetObjectStorage(objectType)
+
+	if fromStorage == nil || toStorage == nil {
+		return errs.ErrSystemError
+	}
+
+	paths, err := s.GetAllObjectPaths(c, objectType)
+
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < len(paths); i++ {
+		path := paths[i]
+		result, err := storage.MigrateObject(c, fromStorage, toStorage, path.Path, dryRun)
+
+		if result == storage.OBJECT_MIGRATION_RESULT_SOURCE_NOT_EXISTS && path.Optional {
+			continue
+		}
+
+		s.logMigrationResult(c, objectType, path.Path, result, err)
+		summary.Add(result)
+	}
+
+	return nil
+}
+
+func (s *StorageMigrationService) logMigrationResult(c core.Context, objectType storage.StorageObjectType, path string, result storage.ObjectMigrationResult, err error) {
+	if err != nil {
+		log.Errorf(c, "[storage_migrations.logMigrationResult] failed to migrate %s object \"%s\", because %s", objectType, path, err.Error())
+	} else if result == storage.OBJECT_MIGRATION_RESULT_MIGRATED {
+		log.Infof(c, "[storage_migrations.logMigrationResult] %s object \"%s\" has been migrated", objectType, path)
+	} else if result == storage.OBJECT_MIGRATION_RESULT_ALREADY_MIGRATED {
+		log.Infof(c, "[storage_migrations.logMigrationResult] %s object \"%s\" already exists in target storage with the same checksum, skip it", objectType, path)
+	} else if result == storage.OBJECT_MIGRATION_RESULT_PENDING {
+		log.Infof(c, "[storage_migrations.logMigrationResult] %s object \"%s\" would be migrated", objectType, path)
+	} else if result == storage.OBJECT_MIGRATION_RESULT_SOURCE_NOT_EXISTS {
+		log.Warnf(c, "[storage_migrations.logMigrationResult] %s object \"%s\" does not exist in source storage", objectType, path)
+	}
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/storage"
+	"github.com/mayswind/ezbookkeeping/pkg/testutils"
+)
+
+func TestMigrateTransactionPictures_SharedStoredPictureAndMissingThumbnails(t *testing.T) {
+	c := testutils.InitializeTestDataStore(t)
+	from := createTestStorageContainer(t)
+	to := createTestStorageContainer(t)
+
+	testutils.InsertTestData(t, 1,
+		&models.TransactionPictureInfo{PictureId: 101, Uid: 1, PictureExtension: "jpg"},
+		&models.TransactionPictureInfo{PictureId: 102, Uid: 1, PictureExtension: "jpg", StoredPictureId: 101},
+	)
+
+	err := from.SaveTransactionPicture(c, "1/101.jpg", storage.NewByteSliceObject([]byte("picture data")))
+	assert.Nil(t, err)
+
+	summary := &storage.ObjectMigrationSummary{}
+	err = StorageMigrations.MigrateTransactionPictures(c, from, to, false, summary)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, summary.TotalCount)
+	assert.Equal(t, 1, summary.MigratedCount)
+	assert.Equal(t, "picture data", readTestTransactionPicture(t, c, to, "1/101.jpg"))
+
+	summary = &storage.ObjectMigrationSummary{}
+	err = StorageMigrations.MigrateTransactionPictures(c, from, to, false, summary)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, summary.TotalCount)
+	assert.Equal(t, 1, summary.AlreadyMigratedCount)
+}
+
+func TestMigrateTransactionPictures_DryRun(t *testing.T) {
+	c := testutils.InitializeTestDataStore(t)
+	from := createTestStorageContainer(t)
+	to := createTestStorageContainer(t)
+
+	testutils.InsertTestData(t, 1,
+		&models.TransactionPictureInfo{PictureId: 101, Uid: 1, PictureExtension: "jpg"},
+		&models.TransactionPictureInfo{PictureId: 102, Uid: 1, PictureExtension: "png"},
+	)
+
+	err := from.SaveTransactionPicture(c, "1/101.jpg", storage.NewByteSliceObject([]byte("picture data")))
+	assert.Nil(t, err)
+
+	summary := &storage.ObjectMigrationSummary{}
+	err = StorageMigrations.MigrateTransactionPictures(c, from, to, true, summary)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, summary.TotalCount)
+	assert.Equal(t, 1, summary.PendingCount)
+	assert.Equal(t, 1, summary.SourceNotExistsCount)
+
+	exists, err := to.ExistsTransactionPicture(c, "1/101.jpg")
+	assert.Nil(t, err)
+	assert.False(t, exists)
+}
+
+func createTestStorageContainer(t *testing.T) *storage.StorageContainer {
+	container, err := storage.NewStorageContainer(&settings.Config{
+		LocalFileSystemPath:          t.TempDir(),
+		AvatarProvider:               core.USER_AVATAR_PROVIDER_INTERNAL,
+		EnableTransactionPictures:    true,
+		EnableTransactionAttachments: true,
+	}, settings.LocalFileSystemObjectStorageType)
+	assert.Nil(t, err)
+
+	return container
+}
+
+func readTestTransactionPicture(t *testing.T, c core.Context, container *storage.StorageContainer, path string) string {
+	object, err := container.ReadTransactionPicture(c, path)
+	assert.Nil(t, err)
+
+	defer object.Close()
+
+	data, err := io.ReadAll(object)
+	assert.Nil(t, err)
+
+	return string(data)
+}
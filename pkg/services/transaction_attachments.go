@@ -0,0 +1,273 @@
+package services
+
+import (
+	"io"
+	"os"
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/storage"
+	"github.com/mayswind/ezbookkeeping/pkg/uuid"
+)
+
+// TransactionAttachmentService represents transaction attachment service
+type TransactionAttachmentService struct {
+	ServiceUsingDB
+	ServiceUsingUuid
+	ServiceUsingStorage
+}
+
+// Initialize a transaction attachment service singleton instance
+var (
+	TransactionAttachments = &TransactionAttachmentService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+		ServiceUsingStorage: ServiceUsingStorage{
+			container: storage.Container,
+		},
+	}
+)
+
+// GetTotalTransactionAttachmentsCountByUid returns total transaction attachments count of user
+func (s *TransactionAttachmentService) GetTotalTransactionAttachmentsCountByUid(c core.Context, uid int64) (int64, error) {
+	if uid <= 0 {
+		return 0, errs.ErrUserIdInvalid
+	}
+
+	count, err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).Count(&models.TransactionAttachment{})
+
+	return count, err
+}
+
+// GetAttachmentByAttachmentId returns a transaction attachment model according to transaction attachment id
+func (s *TransactionAttachmentService) GetAttachmentByAttachmentId(c core.Context, uid int64, attachmentId int64) (*models.TransactionAttachment, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if attachmentId <= 0 {
+		return nil, errs.ErrTransactionAttachmentIdInvalid
+	}
+
+	attachment := &models.TransactionAttachment{}
+	has, err := s.UserDataDB(uid).NewSession(c).ID(attachmentId).Where("uid=? AND deleted=?", uid, false).Get(attachment)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrTransactionAttachmentNotFound
+	}
+
+	return attachment, nil
+}
+
+// GetNewAttachmentsByAttachmentIds returns new transaction attachment models which are not used by any transaction according to transaction attachment ids
+func (s *TransactionAttachmentService) GetNewAttachmentsByAttachmentIds(c core.Context, uid int64, attachmentIds []int64) ([]*models.TransactionAttachment, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if attachmentIds == nil {
+		return nil, errs.ErrTransactionAttachmentIdInvalid
+	}
+
+	var attachments []*models.TransactionAttachment
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=? AND transaction_id=?", uid, false, models.TransactionAttachmentNewAttachmentTransactionId).In("attachment_id", attachmentIds).OrderBy("attachment_id asc").Find(&attachments)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return attachments, nil
+}
+
+// GetAttachmentsByTransactionId returns transaction attachment models according to transaction id
+func (s *TransactionAttachmentService) GetAttachmentsByTransactionId(c core.Context, uid int64, transactionId int64) ([]*models.TransactionAttachment, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if transactionId <= 0 {
+		return nil, errs.ErrTransactionIdInvalid
+	}
+
+	var attachments []*models.TransactionAttachment
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=? AND transaction_id=?", uid, false, transactionId).OrderBy("attachment_id asc").Find(&attachments)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return attachments, nil
+}
+
+// GetAttachmentDataByAttachmentId returns the transaction attachment model and the file data (or thumbnail data) according to transaction attachment id
+func (s *TransactionAttachmentService) GetAttachmentDataByAttachmentId(c core.Context, uid int64, attachmentId int64, fileExtension string, thumbnail bool) (*models.TransactionAttachment, []byte, error) {
+	attachment, err := s.GetAttachmentByAttachmentId(c, uid, attachmentId)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if attachment.FileExtension == "" {
+		return nil, nil, errs.ErrTransactionAttachmentNotFound
+	}
+
+	if thumbnail {
+		if !attachment.HasThumbnail {
+			return nil, nil, errs.ErrTransactionAttachmentThumbnailNoExists
+		}
+
+		fileExtension = models.TransactionAttachmentThumbnailFileExtension
+	} else {
+		if attachment.FileExtension != fileExtension {
+			return nil, nil, errs.ErrTransactionAttachmentExtensionInvalid
+		}
+	}
+
+	attachmentFile, err := s.ReadTransactionAttachment(c, attachment.Uid, attachment.AttachmentId, fileExtension)
+
+	if os.IsNotExist(err) {
+		if thumbnail {
+			return nil, nil, errs.ErrTransactionAttachmentThumbnailNoExists
+		}
+
+		return nil, nil, errs.ErrTransactionAttachmentNoExists
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer attachmentFile.Close()
+
+	attachmentData, err := io.ReadAll(attachmentFile)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return attachment, attachmentData, nil
+}
+
+// UploadAttachment saves the transaction attachment file and its thumbnail (if any) for specified user
+func (s *TransactionAttachmentService) UploadAttachment(c core.Context, attachment *models.TransactionAttachment, attachmentData []byte, thumbnailData []byte) error {
+	if attachment.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	attachment.AttachmentId = s.GenerateUuid(uuid.UUID_TYPE_ATTACHMENT)
+
+	if attachment.AttachmentId < 1 {
+		return errs.ErrSystemIsBusy
+	}
+
+	attachment.TransactionId = models.TransactionAttachmentNewAttachmentTransactionId
+	attachment.FileSize = int64(len(attachmentData))
+	attachment.HasThumbnail = len(thumbnailData) > 0
+	attachment.Deleted = false
+	attachment.CreatedUnixTime = time.Now().Unix()
+	attachment.UpdatedUnixTime = time.Now().Unix()
+
+	err := s.SaveTransactionAttachment(c, attachment.Uid, attachment.AttachmentId, storage.NewByteSliceObject(attachmentData), attachment.FileExtension)
+
+	if err != nil {
+		return err
+	}
+
+	if attachment.HasThumbnail {
+		err = s.SaveTransactionAttachment(c, attachment.Uid, attachment.AttachmentId, storage.NewByteSliceObject(thumbnailData), models.TransactionAttachmentThumbnailFileExtension)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return s.UserDataDB(attachment.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Insert(attachment)
+		return err
+	})
+}
+
+// RemoveUnusedTransactionAttachment removes the unused transaction attachment of specified user
+func (s *TransactionAttachmentService) RemoveUnusedTransactionAttachment(c core.Context, uid int64, attachmentId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if attachmentId <= 0 {
+		return errs.ErrTransactionAttachmentIdInvalid
+	}
+
+	updateModel := &models.TransactionAttachment{
+		Deleted:         true,
+		DeletedUnixTime: time.Now().Unix(),
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		deletedRows, err := sess.ID(attachmentId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND transaction_id=?", uid, false, models.TransactionAttachmentNewAttachmentTransactionId).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrTransactionAttachmentNotFound
+		}
+
+		return err
+	})
+}
+
+// GetTransactionAttachmentIds returns transaction attachment ids list
+func (s *TransactionAttachmentService) GetTransactionAttachmentIds(attachments []*models.TransactionAttachment) []int64 {
+	attachmentIds := make([]int64, len(attachments))
+
+	for i := 0; i < len(attachments); i++ {
+		attachmentIds[i] = attachments[i].AttachmentId
+	}
+
+	return attachmentIds
+}
+
+// updateTransactionAttachments links the new transaction attachments to the specified transaction and removes the given attachments from it in the same session
+func updateTransactionAttachments(sess *xorm.Session, uid int64, transactionId int64, addAttachmentIds []int64, removeAttachmentIds []int64, now int64) error {
+	if len(removeAttachmentIds) > 0 {
+		removeUpdateModel := &models.TransactionAttachment{
+			Deleted:         true,
+			DeletedUnixTime: now,
+		}
+
+		deletedRows, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND transaction_id=?", uid, false, transactionId).In("attachment_id", removeAttachmentIds).Update(removeUpdateModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < int64(len(removeAttachmentIds)) {
+			return errs.ErrTransactionAttachmentNotFound
+		}
+	}
+
+	if len(addAttachmentIds) > 0 {
+		addUpdateModel := &models.TransactionAttachment{
+			TransactionId:   transactionId,
+			UpdatedUnixTime: now,
+		}
+
+		updatedRows, err := sess.Cols("transaction_id", "updated_unix_time").Where("uid=? AND deleted=? AND transaction_id=?", uid, false, models.TransactionAttachmentNewAttachmentTransactionId).In("attachment_id", addAttachmentIds).Update(addUpdateModel)
+
+		if err != nil {
+			return err
+		} else if updatedRows < int64(len(addAttachmentIds)) {
+			return errs.ErrTransactionAttachmentIdInvalid
+		}
+	}
+
+	return nil
+}
@@ -43,10 +43,12 @@ func (s *TransactionCategoryService) GetTotalCategoryCountByUid(c core.Context,
 	return count, err
 }
 
-// GetAllCategoriesByUid returns all transaction category models of user
-func (s *TransactionCategoryService) GetAllCategoriesByUid(c core.Context, uid int64, categoryType models.TransactionCategoryType, parentCategoryId int64) ([]*models.TransactionCategory, error) {
-	if uid <= 0 {
-		return nil, errs.ErrUserIdInvalid
+// GetAllCategoriesByUid returns all transaction category models in the ledger of given access, or all the categories of the ledger owner if the access is for all ledgers
+func (s *TransactionCategoryService) GetAllCategoriesByUid(c core.Context, access *models.LedgerAccess, categoryType models.TransactionCategoryType, parentCategoryId int64) ([]*models.TransactionCategory, error) {
+	uid, ledgerId, err := LedgerAccesses.GetLedgerOwnerUid(c, access, models.LEDGER_PERMISSION_VIEW_BASIC_DATA)
+
+	if err != nil {
+		return nil, err
 	}
 
 	condition := "uid=? AND deleted=?"
@@ -54,6 +56,11 @@ func (s *TransactionCategoryService) GetAllCategoriesByUid(c core.Context, uid i
 	conditionParams = append(conditionParams, uid)
 	conditionParams = append(conditionParams, false)
 
+	if ledgerId > 0 {
+		condition = condition + " AND ledger_id=?"
+		conditionParams = append(conditionParams, ledgerId)
+	}
+
 	if categoryType > 0 {
 		condition = condition + " AND type=?"
 		conditionParams = append(conditionParams, categoryType)
@@ -65,7 +72,7 @@ func (s *TransactionCategoryService) GetAllCategoriesByUid(c core.Context, uid i
 	}
 
 	var categories []*models.TransactionCategory
-	err := s.UserDataDB(uid).NewSession(c).Where(condition, conditionParams...).OrderBy("type asc, parent_category_id asc, display_order asc").Find(&categories)
+	err = s.UserDataDB(uid).NewSession(c).Where(condition, conditionParams...).OrderBy("type asc, parent_category_id asc, display_order asc").Find(&categories)
 
 	return categories, err
 }
@@ -417,6 +424,127 @@ func (s *TransactionCategoryService) DeleteCategory(c core.Context, uid int64, c
 	})
 }
 
+// MergeCategory moves all transactions and transaction templates of the source category to the target category and then deletes the source category,
+// when merging primary categories, the secondary categories of the source category are merged into the target secondary categories with the same name, or moved into the target category if no same name secondary category exists
+func (s *TransactionCategoryService) MergeCategory(c core.Context, uid int64, fromCategoryId int64, toCategoryId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if fromCategoryId <= 0 || toCategoryId <= 0 {
+		return errs.ErrTransactionCategoryIdInvalid
+	}
+
+	if fromCategoryId == toCategoryId {
+		return errs.ErrCannotMergeTransactionCategoryIntoItself
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		fromCategory := &models.TransactionCategory{}
+		has, err := sess.ID(fromCategoryId).Where("uid=? AND deleted=?", uid, false).Get(fromCategory)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrTransactionCategoryNotFound
+		}
+
+		toCategory := &models.TransactionCategory{}
+		has, err = sess.ID(toCategoryId).Where("uid=? AND deleted=?", uid, false).Get(toCategory)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrTransactionCategoryNotFound
+		}
+
+		if fromCategory.LedgerId != toCategory.LedgerId {
+			return errs.ErrCannotUseDataInDifferentLedger
+		}
+
+		if fromCategory.Type != toCategory.Type {
+			return errs.ErrCannotMergeTransactionCategoriesWithDifferentTypes
+		}
+
+		if (fromCategory.ParentCategoryId == models.LevelOneTransactionCategoryParentId) != (toCategory.ParentCategoryId == models.LevelOneTransactionCategoryParentId) {
+			return errs.ErrCannotMergePrimaryAndSecondaryTransactionCategory
+		}
+
+		now := time.Now().Unix()
+
+		if fromCategory.ParentCategoryId != models.LevelOneTransactionCategoryParentId {
+			return s.mergeSecondaryCategory(sess, uid, fromCategoryId, toCategoryId, now)
+		}
+
+		var fromSubCategories []*models.TransactionCategory
+		err = sess.Where("uid=? AND deleted=? AND parent_category_id=?", uid, false, fromCategoryId).OrderBy("display_order asc").Find(&fromSubCategories)
+
+		if err != nil {
+			return err
+		}
+
+		var toSubCategories []*models.TransactionCategory
+		err = sess.Where("uid=? AND deleted=? AND parent_category_id=?", uid, false, toCategoryId).Find(&toSubCategories)
+
+		if err != nil {
+			return err
+		}
+
+		toSubCategoryNameMap := make(map[string]*models.TransactionCategory, len(toSubCategories))
+		maxDisplayOrder := int32(0)
+
+		for i := 0; i < len(toSubCategories); i++ {
+			toSubCategoryNameMap[toSubCategories[i].Name] = toSubCategories[i]
+
+			if toSubCategories[i].DisplayOrder > maxDisplayOrder {
+				maxDisplayOrder = toSubCategories[i].DisplayOrder
+			}
+		}
+
+		for i := 0; i < len(fromSubCategories); i++ {
+			fromSubCategory := fromSubCategories[i]
+
+			if toSubCategory, exists := toSubCategoryNameMap[fromSubCategory.Name]; exists {
+				err = s.mergeSecondaryCategory(sess, uid, fromSubCategory.CategoryId, toSubCategory.CategoryId, now)
+
+				if err != nil {
+					return err
+				}
+
+				continue
+			}
+
+			maxDisplayOrder++
+			fromSubCategory.ParentCategoryId = toCategoryId
+			fromSubCategory.DisplayOrder = maxDisplayOrder
+			fromSubCategory.UpdatedUnixTime = now
+
+			updatedRows, err := sess.ID(fromSubCategory.CategoryId).Cols("parent_category_id", "display_order", "updated_unix_time").Where("uid=? AND deleted=?", uid, false).Update(fromSubCategory)
+
+			if err != nil {
+				return err
+			} else if updatedRows < 1 {
+				return errs.ErrTransactionCategoryNotFound
+			}
+		}
+
+		deleteModel := &models.TransactionCategory{
+			Deleted:         true,
+			DeletedUnixTime: now,
+		}
+
+		deletedRows, err := sess.ID(fromCategoryId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(deleteModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrTransactionCategoryNotFound
+		}
+
+		return nil
+	})
+}
+
 // DeleteAllCategories deletes all existed transaction categories from database
 func (s *TransactionCategoryService) DeleteAllCategories(c core.Context, uid int64) error {
 	if uid <= 0 {
@@ -449,6 +577,45 @@ func (s *TransactionCategoryService) DeleteAllCategories(c core.Context, uid int
 	})
 }
 
+func (s *TransactionCategoryService) mergeSecondaryCategory(sess *xorm.Session, uid int64, fromCategoryId int64, toCategoryId int64, now int64) error {
+	transactionUpdateModel := &models.Transaction{
+		CategoryId:      toCategoryId,
+		UpdatedUnixTime: now,
+	}
+
+	_, err := sess.Cols("category_id", "updated_unix_time").Where("uid=? AND deleted=? AND category_id=?", uid, false, fromCategoryId).Update(transactionUpdateModel)
+
+	if err != nil {
+		return err
+	}
+
+	templateUpdateModel := &models.TransactionTemplate{
+		CategoryId:      toCategoryId,
+		UpdatedUnixTime: now,
+	}
+
+	_, err = sess.Cols("category_id", "updated_unix_time").Where("uid=? AND deleted=? AND category_id=?", uid, false, fromCategoryId).Update(templateUpdateModel)
+
+	if err != nil {
+		return err
+	}
+
+	deleteModel := &models.TransactionCategory{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	deletedRows, err := sess.ID(fromCategoryId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(deleteModel)
+
+	if err != nil {
+		return err
+	} else if deletedRows < 1 {
+		return errs.ErrTransactionCategoryNotFound
+	}
+
+	return nil
+}
+
 // GetCategoryMapByList returns a transaction category map by a list
 func (s *TransactionCategoryService) GetCategoryMapByList(categories []*models.TransactionCategory) map[int64]*models.TransactionCategory {
 	categoryMap := make(map[int64]*models.TransactionCategory)
@@ -5,7 +5,9 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
 	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/testutils"
 )
 
 func TestGetCategoryMapByList_EmptyList(t *testing.T) {
@@ -299,3 +301,39 @@ func TestGetCategoryOrSubCategoryIdsByCategoryName_BothParentAndSubCategory(t *t
 	assert.Contains(t, actualIds, int64(2002))
 	assert.Contains(t, actualIds, int64(2003))
 }
+
+func TestMergeCategory_CategoriesInDifferentLedgers(t *testing.T) {
+	c := testutils.InitializeTestDataStore(t)
+
+	testutils.InsertTestData(t, 1,
+		&models.TransactionCategory{CategoryId: 101, Uid: 1, LedgerId: 1001, Name: "Food", Type: models.CATEGORY_TYPE_EXPENSE, ParentCategoryId: models.LevelOneTransactionCategoryParentId},
+		&models.TransactionCategory{CategoryId: 102, Uid: 1, LedgerId: 1002, Name: "Food", Type: models.CATEGORY_TYPE_EXPENSE, ParentCategoryId: models.LevelOneTransactionCategoryParentId},
+		&models.Transaction{TransactionId: 501, Uid: 1, LedgerId: 1001, CategoryId: 101, TransactionTime: 1000},
+	)
+
+	err := TransactionCategories.MergeCategory(c, 1, 101, 102)
+	assert.Equal(t, errs.ErrCannotUseDataInDifferentLedger, err)
+
+	category, err := TransactionCategories.GetCategoryByCategoryId(c, 1, 101)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(101), category.CategoryId)
+
+	transaction, err := Transactions.GetTransactionByTransactionId(c, 1, 501)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(101), transaction.CategoryId)
+}
+
+func TestMergeCategory_CategoriesInSameLedger(t *testing.T) {
+	c := testutils.InitializeTestDataStore(t)
+
+	testutils.InsertTestData(t, 1,
+		&models.TransactionCategory{CategoryId: 101, Uid: 1, LedgerId: 1001, Name: "Food", Type: models.CATEGORY_TYPE_EXPENSE, ParentCategoryId: models.LevelOneTransactionCategoryParentId},
+		&models.TransactionCategory{CategoryId: 102, Uid: 1, LedgerId: 1001, Name: "Meal", Type: models.CATEGORY_TYPE_EXPENSE, ParentCategoryId: models.LevelOneTransactionCategoryParentId},
+	)
+
+	err := TransactionCategories.MergeCategory(c, 1, 101, 102)
+	assert.Nil(t, err)
+
+	_, err = TransactionCategories.GetCategoryByCategoryId(c, 1, 101)
+	assert.Equal(t, errs.ErrTransactionCategoryNotFound, err)
+}
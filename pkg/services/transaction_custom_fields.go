@@ -0,0 +1,475 @@
+package services
+
+import (
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+	"github.com/mayswind/ezbookkeeping/pkg/uuid"
+)
+
+// TransactionCustomFieldService represents transaction custom field service
+type TransactionCustomFieldService struct {
+	ServiceUsingDB
+	ServiceUsingUuid
+}
+
+// Initialize a transaction custom field service singleton instance
+var (
+	TransactionCustomFields = &TransactionCustomFieldService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+	}
+)
+
+// GetAllCustomFieldsByUid returns all transaction custom field models in the ledger of given access, or all the custom fields of the ledger owner if the access is for all ledgers
+func (s *TransactionCustomFieldService) GetAllCustomFieldsByUid(c core.Context, access *models.LedgerAccess) ([]*models.TransactionCustomField, error) {
+	uid, ledgerId, err := LedgerAccesses.GetLedgerOwnerUid(c, access, models.LEDGER_PERMISSION_VIEW_BASIC_DATA)
+
+	if err != nil {
+		return nil, err
+	}
+
+	condition := "uid=? AND deleted=?"
+	conditionParams := []any{uid, false}
+
+	if ledgerId > 0 {
+		condition = condition + " AND ledger_id=?"
+		conditionParams = append(conditionParams, ledgerId)
+	}
+
+	var customFields []*models.TransactionCustomField
+	err = s.UserDataDB(uid).NewSession(c).Where(condition, conditionParams...).OrderBy("display_order asc").Find(&customFields)
+
+	return customFields, err
+}
+
+// GetCustomFieldByFieldId returns a transaction custom field model according to custom field id
+func (s *TransactionCustomFieldService) GetCustomFieldByFieldId(c core.Context, uid int64, fieldId int64) (*models.TransactionCustomField, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if fieldId <= 0 {
+		return nil, errs.ErrTransactionCustomFieldIdInvalid
+	}
+
+	customField := &models.TransactionCustomField{}
+	has, err := s.UserDataDB(uid).NewSession(c).ID(fieldId).Where("uid=? AND deleted=?", uid, false).Get(customField)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrTransactionCustomFieldNotFound
+	}
+
+	return customField, nil
+}
+
+// GetCustomFieldsByFieldIds returns transaction custom field models according to custom field ids
+func (s *TransactionCustomFieldService) GetCustomFieldsByFieldIds(c core.Context, uid int64, fieldIds []int64) (map[int64]*models.TransactionCustomField, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if len(fieldIds) <= 0 {
+		return make(map[int64]*models.TransactionCustomField), nil
+	}
+
+	var customFields []*models.TransactionCustomField
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).In("field_id", fieldIds).Find(&customFields)
+
+	if err != nil {
+		return nil, err
+	}
+
+	customFieldMap := make(map[int64]*models.TransactionCustomField, len(customFields))
+
+	for i := 0; i < len(customFields); i++ {
+		customFieldMap[customFields[i].FieldId] = customFields[i]
+	}
+
+	return customFieldMap, nil
+}
+
+// GetTotalCustomFieldCountByLedgerId returns total custom field count in given ledger
+func (s *TransactionCustomFieldService) GetTotalCustomFieldCountByLedgerId(c core.Context, uid int64, ledgerId int64) (int64, error) {
+	if uid <= 0 {
+		return 0, errs.ErrUserIdInvalid
+	}
+
+	count, err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=? AND ledger_id=?", uid, false, ledgerId).Count(&models.TransactionCustomField{})
+
+	return count, err
+}
+
+// GetMaxDisplayOrder returns the max display order in given ledger
+func (s *TransactionCustomFieldService) GetMaxDisplayOrder(c core.Context, uid int64, ledgerId int64) (int32, error) {
+	if uid <= 0 {
+		return 0, errs.ErrUserIdInvalid
+	}
+
+	customField := &models.TransactionCustomField{}
+	has, err := s.UserDataDB(uid).NewSession(c).Cols("uid", "deleted", "ledger_id", "display_order").Where("uid=? AND deleted=? AND ledger_id=?", uid, false, ledgerId).OrderBy("display_order desc").Limit(1).Get(customField)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if has {
+		return customField.DisplayOrder, nil
+	} else {
+		return 0, nil
+	}
+}
+
+// GetCustomFieldValuesByTransactionIds returns the custom field values of given transactions, the key of map is transaction id
+func (s *TransactionCustomFieldService) GetCustomFieldValuesByTransactionIds(c core.Context, uid int64, transactionIds []int64) (map[int64][]*models.TransactionCustomFieldValue, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	allValues := make(map[int64][]*models.TransactionCustomFieldValue)
+
+	if len(transactionIds) <= 0 {
+		return allValues, nil
+	}
+
+	var values []*models.TransactionCustomFieldValue
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).In("transaction_id", transactionIds).Find(&values)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < len(values); i++ {
+		allValues[values[i].TransactionId] = append(allValues[values[i].TransactionId], values[i])
+	}
+
+	return allValues, nil
+}
+
+// GetAllCustomFieldValuesByUid returns all the custom field values of user, the key of map is transaction id
+func (s *TransactionCustomFieldService) GetAllCustomFieldValuesByUid(c core.Context, uid int64) (map[int64][]*models.TransactionCustomFieldValue, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var values []*models.TransactionCustomFieldValue
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).Find(&values)
+
+	if err != nil {
+		return nil, err
+	}
+
+	allValues := make(map[int64][]*models.TransactionCustomFieldValue)
+
+	for i := 0; i < len(values); i++ {
+		allValues[values[i].TransactionId] = append(allValues[values[i].TransactionId], values[i])
+	}
+
+	return allValues, nil
+}
+
+// CreateCustomField saves a new transaction custom field model to database
+func (s *TransactionCustomFieldService) CreateCustomField(c core.Context, customField *models.TransactionCustomField) error {
+	if customField.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	exists, err := s.ExistsCustomFieldName(c, customField.Uid, customField.LedgerId, customField.Name)
+
+	if err != nil {
+		return err
+	} else if exists {
+		return errs.ErrTransactionCustomFieldNameAlreadyExists
+	}
+
+	customField.FieldId = s.GenerateUuid(uuid.UUID_TYPE_CUSTOM_FIELD)
+
+	if customField.FieldId < 1 {
+		return errs.ErrSystemIsBusy
+	}
+
+	customField.Deleted = false
+	customField.CreatedUnixTime = time.Now().Unix()
+	customField.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDataDB(customField.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Insert(customField)
+		return err
+	})
+}
+
+// ModifyCustomField saves an existed transaction custom field model to database,
+// the option indexes of existed enum values will be updated if the options are changed
+func (s *TransactionCustomFieldService) ModifyCustomField(c core.Context, customField *models.TransactionCustomField, nameChanged bool) error {
+	if customField.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if nameChanged {
+		exists, err := s.ExistsCustomFieldName(c, customField.Uid, customField.LedgerId, customField.Name)
+
+		if err != nil {
+			return err
+		} else if exists {
+			return errs.ErrTransactionCustomFieldNameAlreadyExists
+		}
+	}
+
+	now := time.Now().Unix()
+	customField.UpdatedUnixTime = now
+
+	return s.UserDataDB(customField.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		updatedRows, err := sess.ID(customField.FieldId).Cols("name", "options", "hidden", "updated_unix_time").Where("uid=? AND deleted=?", customField.Uid, false).Update(customField)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrTransactionCustomFieldNotFound
+		}
+
+		if customField.Type != models.TRANSACTION_CUSTOM_FIELD_TYPE_ENUM {
+			return nil
+		}
+
+		valueUpdateModel := &models.TransactionCustomFieldValue{
+			NumberValue:     0,
+			UpdatedUnixTime: now,
+		}
+
+		_, err = sess.Cols("number_value", "updated_unix_time").Where("uid=? AND deleted=? AND field_id=?", customField.Uid, false, customField.FieldId).Update(valueUpdateModel)
+
+		if err != nil {
+			return err
+		}
+
+		options := customField.GetOptions()
+
+		for i := 0; i < len(options); i++ {
+			valueUpdateModel.NumberValue = int64(i + 1)
+			_, err = sess.Cols("number_value", "updated_unix_time").Where("uid=? AND deleted=? AND field_id=? AND text_value=?", customField.Uid, false, customField.FieldId, options[i]).Update(valueUpdateModel)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// ModifyCustomFieldDisplayOrders updates display order of given transaction custom fields
+func (s *TransactionCustomFieldService) ModifyCustomFieldDisplayOrders(c core.Context, uid int64, customFields []*models.TransactionCustomField) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	for i := 0; i < len(customFields); i++ {
+		customFields[i].UpdatedUnixTime = time.Now().Unix()
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		for i := 0; i < len(customFields); i++ {
+			customField := customFields[i]
+			updatedRows, err := sess.ID(customField.FieldId).Cols("display_order", "updated_unix_time").Where("uid=? AND deleted=?", uid, false).Update(customField)
+
+			if err != nil {
+				return err
+			} else if updatedRows < 1 {
+				return errs.ErrTransactionCustomFieldNotFound
+			}
+		}
+
+		return nil
+	})
+}
+
+// SetTransactionsCustomFieldValues replaces all the custom field values of given transactions, the key of map is transaction id
+func (s *TransactionCustomFieldService) SetTransactionsCustomFieldValues(c core.Context, uid int64, allValues map[int64][]*models.TransactionCustomFieldValue) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if len(allValues) < 1 {
+		return nil
+	}
+
+	now := time.Now().Unix()
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		for transactionId, values := range allValues {
+			err := replaceTransactionCustomFieldValues(sess, uid, transactionId, values, now)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// DeleteCustomField deletes an existed transaction custom field and all its values from database
+func (s *TransactionCustomFieldService) DeleteCustomField(c core.Context, uid int64, fieldId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.TransactionCustomField{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	valueUpdateModel := &models.TransactionCustomFieldValue{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		deletedRows, err := sess.ID(fieldId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrTransactionCustomFieldNotFound
+		}
+
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND field_id=?", uid, false, fieldId).Update(valueUpdateModel)
+
+		return err
+	})
+}
+
+// DeleteAllCustomFields deletes all existed transaction custom fields and their values from database
+func (s *TransactionCustomFieldService) DeleteAllCustomFields(c core.Context, uid int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.TransactionCustomField{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	valueUpdateModel := &models.TransactionCustomFieldValue{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(valueUpdateModel)
+
+		return err
+	})
+}
+
+// ExistsCustomFieldName returns whether the given custom field name exists in given ledger
+func (s *TransactionCustomFieldService) ExistsCustomFieldName(c core.Context, uid int64, ledgerId int64, name string) (bool, error) {
+	if name == "" {
+		return false, errs.ErrTransactionCustomFieldNameIsEmpty
+	}
+
+	return s.UserDataDB(uid).NewSession(c).Cols("name").Where("uid=? AND deleted=? AND ledger_id=? AND name=?", uid, false, ledgerId, name).Exist(&models.TransactionCustomField{})
+}
+
+// ParseCustomFieldValues validates the custom field values in request and returns the value models,
+// all the custom fields must exist in given ledger and every field can only have one value
+func (s *TransactionCustomFieldService) ParseCustomFieldValues(ledgerId int64, customFieldMap map[int64]*models.TransactionCustomField, valueRequests []*models.TransactionCustomFieldValueRequest) ([]*models.TransactionCustomFieldValue, error) {
+	values := make([]*models.TransactionCustomFieldValue, 0, len(valueRequests))
+	existedFieldIds := make(map[int64]bool, len(valueRequests))
+
+	for i := 0; i < len(valueRequests); i++ {
+		valueRequest := valueRequests[i]
+		customField, exists := customFieldMap[valueRequest.FieldId]
+
+		if !exists {
+			return nil, errs.ErrTransactionCustomFieldNotFound
+		}
+
+		if customField.LedgerId != ledgerId {
+			return nil, errs.ErrTransactionCustomFieldInDifferentLedger
+		}
+
+		if existedFieldIds[customField.FieldId] {
+			return nil, errs.ErrTransactionCustomFieldValueInvalid
+		}
+
+		existedFieldIds[customField.FieldId] = true
+
+		if valueRequest.Value == "" {
+			continue
+		}
+
+		textValue, numberValue, err := customField.ParseValue(valueRequest.Value)
+
+		if err != nil {
+			return nil, err
+		}
+
+		values = append(values, &models.TransactionCustomFieldValue{
+			FieldId:     customField.FieldId,
+			TextValue:   textValue,
+			NumberValue: numberValue,
+		})
+	}
+
+	return values, nil
+}
+
+// GetCustomFieldIdsFromValueRequests returns the custom field ids in given value requests
+func (s *TransactionCustomFieldService) GetCustomFieldIdsFromValueRequests(valueRequests []*models.TransactionCustomFieldValueRequest) []int64 {
+	fieldIds := make([]int64, 0, len(valueRequests))
+
+	for i := 0; i < len(valueRequests); i++ {
+		fieldIds = append(fieldIds, valueRequests[i].FieldId)
+	}
+
+	return utils.ToUniqueInt64Slice(fieldIds)
+}
+
+// replaceTransactionCustomFieldValues deletes all the custom field values of the specified transaction and saves the given values in the same session
+func replaceTransactionCustomFieldValues(sess *xorm.Session, uid int64, transactionId int64, values []*models.TransactionCustomFieldValue, now int64) error {
+	_, err := sess.Where("uid=? AND transaction_id=?", uid, transactionId).Delete(&models.TransactionCustomFieldValue{})
+
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < len(values); i++ {
+		value := values[i]
+		value.TransactionId = transactionId
+		value.Uid = uid
+		value.Deleted = false
+		value.CreatedUnixTime = now
+		value.UpdatedUnixTime = now
+
+		_, err = sess.Insert(value)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -1,9 +1,10 @@
 package services
 
 import (
+	"errors"
 	"io"
-	"mime/multipart"
 	"os"
+	"strings"
 	"time"
 
 	"xorm.io/xorm"
@@ -11,11 +12,19 @@ import (
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/datastore"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
 	"github.com/mayswind/ezbookkeeping/pkg/models"
 	"github.com/mayswind/ezbookkeeping/pkg/storage"
+	"github.com/mayswind/ezbookkeeping/pkg/thumbnails"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
 	"github.com/mayswind/ezbookkeeping/pkg/uuid"
 )
 
+const maxUploadPictureRetryCount = 3
+
+var errTransactionPictureObjectChanged = errors.New("transaction picture object changed")
+var errTransactionPictureFileNotSaved = errors.New("transaction picture file not saved")
+
 // TransactionPictureService represents transaction picture service
 type TransactionPictureService struct {
 	ServiceUsingDB
@@ -132,86 +141,214 @@ func (s *TransactionPictureService) GetPictureInfosByTransactionIds(c core.Conte
 	return pictureInfoMap, err
 }
 
-// GetPictureByPictureId returns the transaction picture data according to transaction picture id
-func (s *TransactionPictureService) GetPictureByPictureId(c core.Context, uid int64, pictureId int64, fileExtension string) ([]byte, error) {
+// GetPictureByPictureId returns the transaction picture data and its content type according to transaction picture id,
+// the thumbnail of specified size is generated and cached if it does not exist, and the original picture data is returned if the picture cannot be decoded
+func (s *TransactionPictureService) GetPictureByPictureId(c core.Context, uid int64, pictureId int64, fileExtension string, size models.TransactionPictureSize) ([]byte, string, error) {
 	if uid <= 0 {
-		return nil, errs.ErrUserIdInvalid
+		return nil, "", errs.ErrUserIdInvalid
 	}
 
 	if pictureId <= 0 {
-		return nil, errs.ErrTransactionPictureIdInvalid
+		return nil, "", errs.ErrTransactionPictureIdInvalid
 	}
 
 	pictureInfo := &models.TransactionPictureInfo{}
 	has, err := s.UserDataDB(uid).NewSession(c).ID(pictureId).Where("uid=? AND deleted=?", uid, false).Get(pictureInfo)
 
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	} else if !has {
-		return nil, errs.ErrTransactionPictureNotFound
+		return nil, "", errs.ErrTransactionPictureNotFound
 	}
 
 	if pictureInfo.PictureExtension == "" {
-		return nil, errs.ErrTransactionPictureNotFound
+		return nil, "", errs.ErrTransactionPictureNotFound
 	}
 
 	if pictureInfo.PictureExtension != fileExtension {
-		return nil, errs.ErrTransactionPictureExtensionInvalid
+		return nil, "", errs.ErrTransactionPictureExtensionInvalid
 	}
 
-	pictureFile, err := s.ReadTransactionPicture(c, pictureInfo.Uid, pictureInfo.PictureId, pictureInfo.PictureExtension)
+	if size.IsThumbnail() {
+		thumbnailData, err := s.readPictureFile(c, pictureInfo.Uid, pictureInfo.GetStoredPictureId(), size.GetFileExtension())
 
-	if os.IsNotExist(err) {
-		return nil, errs.ErrTransactionPictureNoExists
+		if err == nil {
+			return thumbnailData, utils.GetImageContentType(models.TransactionPictureThumbnailFileExtension), nil
+		} else if err != errs.ErrTransactionPictureNoExists {
+			return nil, "", err
+		}
 	}
 
+	pictureData, err := s.readPictureFile(c, pictureInfo.Uid, pictureInfo.GetStoredPictureId(), pictureInfo.PictureExtension)
+
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
-	defer pictureFile.Close()
+	if !size.IsThumbnail() {
+		return pictureData, utils.GetImageContentType(pictureInfo.PictureExtension), nil
+	}
+
+	thumbnailDatas := s.CreatePictureThumbnails(c, pictureData)
+	thumbnailData, exists := thumbnailDatas[size]
+
+	if !exists {
+		return pictureData, utils.GetImageContentType(pictureInfo.PictureExtension), nil
+	}
 
-	pictureData, err := io.ReadAll(pictureFile)
+	err = s.savePictureThumbnails(c, pictureInfo.Uid, pictureInfo.GetStoredPictureId(), thumbnailDatas)
 
 	if err != nil {
-		return nil, err
+		log.Warnf(c, "[transaction_pictures.GetPictureByPictureId] failed to save thumbnails of transaction picture \"id:%d\" for user \"uid:%d\", because %s", pictureInfo.PictureId, pictureInfo.Uid, err.Error())
+	}
+
+	return thumbnailData, utils.GetImageContentType(models.TransactionPictureThumbnailFileExtension), nil
+}
+
+// CreatePictureThumbnails returns the jpeg encoded thumbnail data of all thumbnail sizes of the specified picture data,
+// or returns empty map if the picture cannot be decoded
+func (s *TransactionPictureService) CreatePictureThumbnails(c core.Context, pictureData []byte) map[models.TransactionPictureSize][]byte {
+	thumbnailDatas := make(map[models.TransactionPictureSize][]byte, len(models.TransactionPictureThumbnailSizes))
+	img, err := thumbnails.DecodeImage(pictureData)
+
+	if err != nil {
+		log.Debugf(c, "[transaction_pictures.CreatePictureThumbnails] cannot decode the picture to create thumbnails, because %s", err.Error())
+		return thumbnailDatas
 	}
 
-	return pictureData, nil
+	for i := 0; i < len(models.TransactionPictureThumbnailSizes); i++ {
+		size := models.TransactionPictureThumbnailSizes[i]
+		thumbnailData, err := thumbnails.CreateImageThumbnail(img, size.GetMaxDimension())
+
+		if err != nil {
+			log.Warnf(c, "[transaction_pictures.CreatePictureThumbnails] failed to create %s thumbnail, because %s", size, err.Error())
+			continue
+		}
+
+		thumbnailDatas[size] = thumbnailData
+	}
+
+	return thumbnailDatas
 }
 
-// UploadPicture uploads the transaction picture for specified user
-func (s *TransactionPictureService) UploadPicture(c core.Context, pictureInfo *models.TransactionPictureInfo, pictureFile multipart.File) error {
+// UploadPicture uploads the transaction picture and its thumbnails for specified user,
+// and the stored picture file is reused if the user has uploaded a picture with the same content before
+func (s *TransactionPictureService) UploadPicture(c core.Context, pictureInfo *models.TransactionPictureInfo, pictureData []byte, thumbnailDatas map[models.TransactionPictureSize][]byte) error {
 	if pictureInfo.Uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
 
-	defer pictureFile.Close()
-
 	pictureInfo.PictureId = s.GenerateUuid(uuid.UUID_TYPE_USER)
 
 	if pictureInfo.PictureId < 1 {
 		return errs.ErrSystemIsBusy
 	}
 
+	now := time.Now().Unix()
+
 	pictureInfo.TransactionId = 0
+	pictureInfo.ContentHash = utils.SHA256EncodeToString(pictureData)
 	pictureInfo.Deleted = false
-	pictureInfo.CreatedUnixTime = time.Now().Unix()
-	pictureInfo.UpdatedUnixTime = time.Now().Unix()
+	pictureInfo.CreatedUnixTime = now
+	pictureInfo.UpdatedUnixTime = now
+
+	pictureFileSaved := false
+	var err error
+
+	// the content hash lookup and the reference count update run in one database transaction, and the whole transaction is retried
+	// if the stored picture is deleted between them, or another picture with the same content is stored concurrently
+	for retryCount := 0; retryCount <= maxUploadPictureRetryCount; {
+		err = s.UserDataDB(pictureInfo.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+			existedObject := &models.TransactionPictureObject{}
+			has, err := sess.Where("uid=? AND content_hash=? AND picture_extension=?", pictureInfo.Uid, pictureInfo.ContentHash, pictureInfo.PictureExtension).Get(existedObject)
+
+			if err != nil {
+				return err
+			}
+
+			if has {
+				updatedRows, err := sess.ID(existedObject.ObjectId).Where("uid=?", pictureInfo.Uid).Incr("reference_count").Update(&models.TransactionPictureObject{UpdatedUnixTime: now})
+
+				if err != nil {
+					return err
+				} else if updatedRows < 1 {
+					return errTransactionPictureObjectChanged
+				}
+
+				pictureInfo.StoredPictureId = existedObject.ObjectId
+			} else if !pictureFileSaved {
+				return errTransactionPictureFileNotSaved
+			} else {
+				pictureObject := &models.TransactionPictureObject{
+					Uid:              pictureInfo.Uid,
+					ObjectId:         pictureInfo.PictureId,
+					ContentHash:      pictureInfo.ContentHash,
+					PictureExtension: pictureInfo.PictureExtension,
+					FileSize:         int64(len(pictureData)),
+					ReferenceCount:   1,
+					CreatedUnixTime:  now,
+					UpdatedUnixTime:  now,
+				}
+
+				_, err := sess.Insert(pictureObject)
+
+				if err != nil {
+					return err
+				}
+
+				pictureInfo.StoredPictureId = pictureInfo.PictureId
+			}
+
+			_, err = sess.Insert(pictureInfo)
+			return err
+		})
+
+		if err == errTransactionPictureFileNotSaved {
+			err = s.SaveTransactionPicture(c, pictureInfo.Uid, pictureInfo.PictureId, storage.NewByteSliceObject(pictureData), pictureInfo.PictureExtension)
+
+			if err == nil {
+				err = s.savePictureThumbnails(c, pictureInfo.Uid, pictureInfo.PictureId, thumbnailDatas)
+			}
+
+			if err != nil {
+				break
+			}
+
+			pictureFileSaved = true
+			continue
+		}
 
-	err := s.SaveTransactionPicture(c, pictureInfo.Uid, pictureInfo.PictureId, pictureFile, pictureInfo.PictureExtension)
+		if err == errTransactionPictureObjectChanged || datastore.IsUniqueConstraintViolation(err) {
+			log.Warnf(c, "[transaction_pictures.UploadPicture] the stored picture with the same content of new picture \"id:%d\" of user \"uid:%d\" has been changed concurrently, retrying", pictureInfo.PictureId, pictureInfo.Uid)
+			retryCount++
+			continue
+		}
+
+		break
+	}
+
+	if err == errTransactionPictureObjectChanged || datastore.IsUniqueConstraintViolation(err) {
+		err = errs.ErrSystemIsBusy
+	}
+
+	// delete the saved picture file if it is not used, because the stored picture with the same content is reused or the uploading is failed
+	if pictureFileSaved && (err != nil || pictureInfo.StoredPictureId != pictureInfo.PictureId) {
+		s.deletePictureFiles(c, pictureInfo.Uid, pictureInfo.PictureId, pictureInfo.PictureExtension)
+	}
 
 	if err != nil {
 		return err
 	}
 
-	return s.UserDataDB(pictureInfo.Uid).DoTransaction(c, func(sess *xorm.Session) error {
-		_, err := sess.Insert(pictureInfo)
-		return err
-	})
+	if pictureInfo.StoredPictureId != pictureInfo.PictureId {
+		log.Infof(c, "[transaction_pictures.UploadPicture] reuse the stored picture \"id:%d\" for new picture \"id:%d\" of user \"uid:%d\"", pictureInfo.StoredPictureId, pictureInfo.PictureId, pictureInfo.Uid)
+	}
+
+	return nil
 }
 
-// RemoveUnusedTransactionPicture removes the unused transaction picture of specified user
+// RemoveUnusedTransactionPicture removes the unused transaction picture of specified user,
+// and the stored picture file is deleted if it is not referenced by any other transaction picture
 func (s *TransactionPictureService) RemoveUnusedTransactionPicture(c core.Context, uid int64, pictureId int64) error {
 	if uid <= 0 {
 		return errs.ErrUserIdInvalid
@@ -228,7 +365,18 @@ func (s *TransactionPictureService) RemoveUnusedTransactionPicture(c core.Contex
 		DeletedUnixTime: now,
 	}
 
-	return s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+	pictureInfo := &models.TransactionPictureInfo{}
+	var unreferencedObjects []*models.TransactionPictureObject
+
+	err := s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		has, err := sess.ID(pictureId).Where("uid=? AND deleted=? AND transaction_id=?", uid, false, models.TransactionPictureNewPictureTransactionId).Get(pictureInfo)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrTransactionPictureNotFound
+		}
+
 		deletedRows, err := sess.ID(pictureId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND transaction_id=?", uid, false, models.TransactionPictureNewPictureTransactionId).Update(updateModel)
 
 		if err != nil {
@@ -237,8 +385,333 @@ func (s *TransactionPictureService) RemoveUnusedTransactionPicture(c core.Contex
 			return errs.ErrTransactionPictureNotFound
 		}
 
+		unreferencedObjects, err = decreaseTransactionPictureObjectReferences(sess, uid, []*models.TransactionPictureInfo{pictureInfo}, now)
+
+		if err != nil {
+			return err
+		}
+
+		return deleteTransactionPictureObjects(sess, uid, unreferencedObjects)
+	})
+
+	if err != nil {
 		return err
+	}
+
+	if !pictureInfo.IsContentDeduplicated() {
+		s.deletePictureFiles(c, uid, pictureInfo.GetStoredPictureId(), pictureInfo.PictureExtension)
+	}
+
+	s.deletePictureObjectFiles(c, uid, unreferencedObjects)
+
+	return nil
+}
+
+// DeleteAllPictures deletes all transaction pictures and all the stored picture files of specified user
+func (s *TransactionPictureService) DeleteAllPictures(c core.Context, uid int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.TransactionPictureInfo{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	var pictureInfos []*models.TransactionPictureInfo
+	var unreferencedObjects []*models.TransactionPictureObject
+
+	err := s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		err := sess.Where("uid=? AND deleted=?", uid, false).Find(&pictureInfos)
+
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		}
+
+		_, err = decreaseTransactionPictureObjectReferences(sess, uid, pictureInfos, now)
+
+		if err != nil {
+			return err
+		}
+
+		err = sess.Where("uid=? AND reference_count<=?", uid, 0).Find(&unreferencedObjects)
+
+		if err != nil {
+			return err
+		}
+
+		return deleteTransactionPictureObjects(sess, uid, unreferencedObjects)
 	})
+
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < len(pictureInfos); i++ {
+		if !pictureInfos[i].IsContentDeduplicated() {
+			s.deletePictureFiles(c, uid, pictureInfos[i].GetStoredPictureId(), pictureInfos[i].PictureExtension)
+		}
+	}
+
+	s.deletePictureObjectFiles(c, uid, unreferencedObjects)
+
+	return nil
+}
+
+// RemoveAllOrphanedPictures removes all the transaction pictures which are uploaded before the specified time but not saved with any transaction,
+// deletes all the stored pictures which are not referenced by any transaction picture since the specified time,
+// and deletes all the files modified before the specified time in the transaction picture storage which have no records in database
+func (s *TransactionPictureService) RemoveAllOrphanedPictures(c core.Context, beforeUnixTime int64) (*models.TransactionPictureCleanupResult, error) {
+	var errors []error
+	result := &models.TransactionPictureCleanupResult{}
+
+	for i := 0; i < s.UserDataDBCount(); i++ {
+		var pictureInfos []*models.TransactionPictureInfo
+		err := s.UserDataDBByIndex(i).NewSession(c).Cols("picture_id", "uid").Where("deleted=? AND transaction_id=? AND created_unix_time<?", false, models.TransactionPictureNewPictureTransactionId, beforeUnixTime).Find(&pictureInfos)
+
+		if err != nil {
+			errors = append(errors, err)
+			continue
+		}
+
+		for j := 0; j < len(pictureInfos); j++ {
+			err = s.RemoveUnusedTransactionPicture(c, pictureInfos[j].Uid, pictureInfos[j].PictureId)
+
+			if err == errs.ErrTransactionPictureNotFound {
+				continue
+			} else if err != nil {
+				log.Errorf(c, "[transaction_pictures.RemoveAllOrphanedPictures] failed to remove unused picture \"id:%d\" of user \"uid:%d\", because %s", pictureInfos[j].PictureId, pictureInfos[j].Uid, err.Error())
+				errors = append(errors, err)
+				continue
+			}
+
+			result.RemovedPictureCount++
+		}
+
+		var pictureObjects []*models.TransactionPictureObject
+		err = s.UserDataDBByIndex(i).NewSession(c).Where("reference_count<=? AND updated_unix_time<?", 0, beforeUnixTime).Find(&pictureObjects)
+
+		if err != nil {
+			errors = append(errors, err)
+			continue
+		}
+
+		for j := 0; j < len(pictureObjects); j++ {
+			pictureObject := pictureObjects[j]
+			deletedRows, err := s.UserDataDB(pictureObject.Uid).NewSession(c).ID(pictureObject.ObjectId).Where("uid=? AND reference_count<=?", pictureObject.Uid, 0).Delete(&models.TransactionPictureObject{})
+
+			if err != nil {
+				log.Errorf(c, "[transaction_pictures.RemoveAllOrphanedPictures] failed to delete unreferenced picture object \"id:%d\" of user \"uid:%d\", because %s", pictureObject.ObjectId, pictureObject.Uid, err.Error())
+				errors = append(errors, err)
+				continue
+			} else if deletedRows < 1 {
+				continue
+			}
+
+			s.deletePictureFiles(c, pictureObject.Uid, pictureObject.ObjectId, pictureObject.PictureExtension)
+			result.DeletedObjectCount++
+		}
+	}
+
+	// do not scan the stored picture files if any error occurs above, because the records may be in an unknown state
+	if len(errors) > 0 {
+		return result, errs.NewMultiErrorOrNil(errors...)
+	}
+
+	deletedFileCount, err := s.deleteAllPictureFilesWithoutRecords(c, beforeUnixTime)
+	result.DeletedFileCount = deletedFileCount
+
+	if err != nil {
+		errors = append(errors, err)
+	}
+
+	return result, errs.NewMultiErrorOrNil(errors...)
+}
+
+func (s *TransactionPictureService) readPictureFile(c core.Context, uid int64, pictureId int64, fileExtension string) ([]byte, error) {
+	pictureFile, err := s.ReadTransactionPicture(c, uid, pictureId, fileExtension)
+
+	if os.IsNotExist(err) {
+		return nil, errs.ErrTransactionPictureNoExists
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer pictureFile.Close()
+
+	return io.ReadAll(pictureFile)
+}
+
+func (s *TransactionPictureService) savePictureThumbnails(c core.Context, uid int64, storedPictureId int64, thumbnailDatas map[models.TransactionPictureSize][]byte) error {
+	for size, thumbnailData := range thumbnailDatas {
+		err := s.SaveTransactionPicture(c, uid, storedPictureId, storage.NewByteSliceObject(thumbnailData), size.GetFileExtension())
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *TransactionPictureService) deletePictureFiles(c core.Context, uid int64, storedPictureId int64, pictureExtension string) {
+	fileExtensions := []string{pictureExtension}
+
+	for i := 0; i < len(models.TransactionPictureThumbnailSizes); i++ {
+		fileExtensions = append(fileExtensions, models.TransactionPictureThumbnailSizes[i].GetFileExtension())
+	}
+
+	for i := 0; i < len(fileExtensions); i++ {
+		err := s.DeleteTransactionPicture(c, uid, storedPictureId, fileExtensions[i])
+
+		if err != nil && !os.IsNotExist(err) {
+			log.Warnf(c, "[transaction_pictures.deletePictureFiles] failed to delete stored picture file \"%d.%s\" of user \"uid:%d\", because %s", storedPictureId, fileExtensions[i], uid, err.Error())
+		}
+	}
+}
+
+// deleteAllPictureFilesWithoutRecords deletes all the files modified before the specified time in the transaction picture storage,
+// which are not named by any stored picture object or any transaction picture uploaded before content deduplication
+func (s *TransactionPictureService) deleteAllPictureFilesWithoutRecords(c core.Context, beforeUnixTime int64) (int, error) {
+	referencedPictureIds := make(map[int64]bool)
+
+	for i := 0; i < s.UserDataDBCount(); i++ {
+		var pictureObjects []*models.TransactionPictureObject
+		err := s.UserDataDBByIndex(i).NewSession(c).Cols("object_id").Find(&pictureObjects)
+
+		if err != nil {
+			return 0, err
+		}
+
+		for j := 0; j < len(pictureObjects); j++ {
+			referencedPictureIds[pictureObjects[j].ObjectId] = true
+		}
+
+		var pictureInfos []*models.TransactionPictureInfo
+		err = s.UserDataDBByIndex(i).NewSession(c).Cols("picture_id").Where("deleted=? AND stored_picture_id=?", false, 0).Find(&pictureInfos)
+
+		if err != nil {
+			return 0, err
+		}
+
+		for j := 0; j < len(pictureInfos); j++ {
+			referencedPictureIds[pictureInfos[j].PictureId] = true
+		}
+	}
+
+	var unreferencedPaths []string
+
+	// the files which are saved after loading records (e.g. uploading now) are modified after the specified time, so they would not be deleted
+	err := s.WalkTransactionPictures(c, func(objectInfo *storage.ObjectInfo) error {
+		if objectInfo.LastModifiedUnixTime >= beforeUnixTime {
+			return nil
+		}
+
+		_, pictureId, _, ok := parseTransactionPicturePath(objectInfo.Path)
+
+		if !ok {
+			log.Warnf(c, "[transaction_pictures.deleteAllPictureFilesWithoutRecords] skip unknown file \"%s\" in transaction picture storage", objectInfo.Path)
+			return nil
+		}
+
+		if !referencedPictureIds[pictureId] {
+			unreferencedPaths = append(unreferencedPaths, objectInfo.Path)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	var errors []error
+	deletedFileCount := 0
+
+	for i := 0; i < len(unreferencedPaths); i++ {
+		uid, pictureId, fileExtension, _ := parseTransactionPicturePath(unreferencedPaths[i])
+		err = s.DeleteTransactionPicture(c, uid, pictureId, fileExtension)
+
+		if err != nil && !os.IsNotExist(err) {
+			log.Errorf(c, "[transaction_pictures.deleteAllPictureFilesWithoutRecords] failed to delete file \"%s\" without records, because %s", unreferencedPaths[i], err.Error())
+			errors = append(errors, err)
+			continue
+		}
+
+		deletedFileCount++
+	}
+
+	return deletedFileCount, errs.NewMultiErrorOrNil(errors...)
+}
+
+// deletePictureObjectFiles deletes the stored picture files of the picture objects which have been deleted from database
+func (s *TransactionPictureService) deletePictureObjectFiles(c core.Context, uid int64, pictureObjects []*models.TransactionPictureObject) {
+	for i := 0; i < len(pictureObjects); i++ {
+		s.deletePictureFiles(c, uid, pictureObjects[i].ObjectId, pictureObjects[i].PictureExtension)
+	}
+}
+
+// decreaseTransactionPictureObjectReferences decreases the reference counts of the stored picture objects used by the specified deleted transaction pictures,
+// and returns the picture objects which are not referenced by any transaction picture
+func decreaseTransactionPictureObjectReferences(sess *xorm.Session, uid int64, pictureInfos []*models.TransactionPictureInfo, now int64) ([]*models.TransactionPictureObject, error) {
+	referenceCounts := make(map[int64]int64)
+
+	for i := 0; i < len(pictureInfos); i++ {
+		if pictureInfos[i].IsContentDeduplicated() {
+			referenceCounts[pictureInfos[i].StoredPictureId]++
+		}
+	}
+
+	if len(referenceCounts) < 1 {
+		return nil, nil
+	}
+
+	objectIds := make([]int64, 0, len(referenceCounts))
+
+	for objectId, referenceCount := range referenceCounts {
+		_, err := sess.ID(objectId).Where("uid=?", uid).Decr("reference_count", referenceCount).Update(&models.TransactionPictureObject{UpdatedUnixTime: now})
+
+		if err != nil {
+			return nil, err
+		}
+
+		objectIds = append(objectIds, objectId)
+	}
+
+	var unreferencedObjects []*models.TransactionPictureObject
+	err := sess.Where("uid=? AND reference_count<=?", uid, 0).In("object_id", objectIds).Find(&unreferencedObjects)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return unreferencedObjects, nil
+}
+
+func deleteTransactionPictureObjects(sess *xorm.Session, uid int64, pictureObjects []*models.TransactionPictureObject) error {
+	if len(pictureObjects) < 1 {
+		return nil
+	}
+
+	objectIds := make([]int64, len(pictureObjects))
+
+	for i := 0; i < len(pictureObjects); i++ {
+		objectIds[i] = pictureObjects[i].ObjectId
+	}
+
+	_, err := sess.Where("uid=? AND reference_count<=?", uid, 0).In("object_id", objectIds).Delete(&models.TransactionPictureObject{})
+
+	return err
 }
 
 // GetPictureInfoMapByList returns a transaction picture info list map by a list
@@ -277,3 +750,33 @@ func (s *TransactionPictureService) GetTransactionPictureIds(pictureInfos []*mod
 
 	return pictureIds
 }
+
+// parseTransactionPicturePath returns the user id, picture id and file extension of the stored picture file path (e.g. "1/100.small.jpg"),
+// and returns false if the path is not generated by transaction picture service
+func parseTransactionPicturePath(path string) (int64, int64, string, bool) {
+	items := strings.Split(path, "/")
+
+	if len(items) != 2 {
+		return 0, 0, "", false
+	}
+
+	fileNameItems := strings.SplitN(items[1], ".", 2)
+
+	if len(fileNameItems) != 2 || fileNameItems[1] == "" {
+		return 0, 0, "", false
+	}
+
+	uid, err := utils.StringToInt64(items[0])
+
+	if err != nil || uid <= 0 || utils.Int64ToString(uid) != items[0] {
+		return 0, 0, "", false
+	}
+
+	pictureId, err := utils.StringToInt64(fileNameItems[0])
+
+	if err != nil || pictureId <= 0 || utils.Int64ToString(pictureId) != fileNameItems[0] {
+		return 0, 0, "", false
+	}
+
+	return uid, pictureId, fileNameItems[1], true
+}
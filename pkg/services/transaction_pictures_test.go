@@ -0,0 +1,234 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/storage"
+	"github.com/mayswind/ezbookkeeping/pkg/testutils"
+)
+
+func TestUploadPicture_ReuseStoredPictureWithSameContent(t *testing.T) {
+	c := testutils.InitializeTestDataStore(t)
+	testutils.InitializeTestStorage(t)
+
+	firstPicture := &models.TransactionPictureInfo{Uid: 1, PictureExtension: "png"}
+	err := TransactionPictures.UploadPicture(c, firstPicture, []byte("picture content"), nil)
+	assert.Nil(t, err)
+	assert.Equal(t, firstPicture.PictureId, firstPicture.StoredPictureId)
+
+	secondPicture := &models.TransactionPictureInfo{Uid: 1, PictureExtension: "png"}
+	err = TransactionPictures.UploadPicture(c, secondPicture, []byte("picture content"), nil)
+	assert.Nil(t, err)
+	assert.Equal(t, firstPicture.PictureId, secondPicture.StoredPictureId)
+
+	pictureObject := getTestTransactionPictureObject(t, c, 1, firstPicture.PictureId)
+	assert.Equal(t, int64(2), pictureObject.ReferenceCount)
+
+	exists, err := TransactionPictures.ExistsTransactionPicture(c, 1, firstPicture.PictureId, "png")
+	assert.Nil(t, err)
+	assert.True(t, exists)
+
+	exists, err = TransactionPictures.ExistsTransactionPicture(c, 1, secondPicture.PictureId, "png")
+	assert.Nil(t, err)
+	assert.False(t, exists)
+}
+
+func TestUploadPicture_StoredPictureDeletedBeforeUpload(t *testing.T) {
+	c := testutils.InitializeTestDataStore(t)
+	testutils.InitializeTestStorage(t)
+
+	firstPicture := &models.TransactionPictureInfo{Uid: 1, PictureExtension: "png"}
+	err := TransactionPictures.UploadPicture(c, firstPicture, []byte("picture content"), nil)
+	assert.Nil(t, err)
+
+	err = TransactionPictures.RemoveUnusedTransactionPicture(c, 1, firstPicture.PictureId)
+	assert.Nil(t, err)
+
+	secondPicture := &models.TransactionPictureInfo{Uid: 1, PictureExtension: "png"}
+	err = TransactionPictures.UploadPicture(c, secondPicture, []byte("picture content"), nil)
+	assert.Nil(t, err)
+	assert.Equal(t, secondPicture.PictureId, secondPicture.StoredPictureId)
+
+	pictureObject := getTestTransactionPictureObject(t, c, 1, secondPicture.PictureId)
+	assert.Equal(t, int64(1), pictureObject.ReferenceCount)
+
+	exists, err := TransactionPictures.ExistsTransactionPicture(c, 1, secondPicture.PictureId, "png")
+	assert.Nil(t, err)
+	assert.True(t, exists)
+}
+
+func TestUploadPicture_DuplicatePictureObjectIsUniqueConstraintViolation(t *testing.T) {
+	c := testutils.InitializeTestDataStore(t)
+
+	testutils.InsertTestData(t, 1, &models.TransactionPictureObject{Uid: 1, ObjectId: 101, ContentHash: "hash", PictureExtension: "png", ReferenceCount: 1})
+
+	_, err := datastore.Container.UserDataStore.Choose(1).NewSession(c).Insert(&models.TransactionPictureObject{Uid: 1, ObjectId: 102, ContentHash: "hash", PictureExtension: "png", ReferenceCount: 1})
+	assert.NotNil(t, err)
+	assert.True(t, datastore.IsUniqueConstraintViolation(err))
+}
+
+func TestDeleteTransaction_DeleteUnreferencedStoredPicture(t *testing.T) {
+	c := testutils.InitializeTestDataStore(t)
+	testutils.InitializeTestStorage(t)
+
+	testutils.InsertTestData(t, 1,
+		&models.Account{AccountId: 101, Uid: 1, LedgerId: 1001, Name: "Cash", Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Currency: "USD"},
+		&models.Transaction{TransactionId: 501, Uid: 1, LedgerId: 1001, Type: models.TRANSACTION_DB_TYPE_EXPENSE, AccountId: 101, TransactionTime: 1000},
+		&models.Transaction{TransactionId: 502, Uid: 1, LedgerId: 1001, Type: models.TRANSACTION_DB_TYPE_EXPENSE, AccountId: 101, TransactionTime: 2000},
+	)
+
+	firstPicture := &models.TransactionPictureInfo{Uid: 1, PictureExtension: "png"}
+	err := TransactionPictures.UploadPicture(c, firstPicture, []byte("picture content"), nil)
+	assert.Nil(t, err)
+
+	secondPicture := &models.TransactionPictureInfo{Uid: 1, PictureExtension: "png"}
+	err = TransactionPictures.UploadPicture(c, secondPicture, []byte("picture content"), nil)
+	assert.Nil(t, err)
+
+	_, err = datastore.Container.UserDataStore.Choose(1).NewSession(c).ID(firstPicture.PictureId).Cols("transaction_id").Update(&models.TransactionPictureInfo{TransactionId: 501})
+	assert.Nil(t, err)
+	_, err = datastore.Container.UserDataStore.Choose(1).NewSession(c).ID(secondPicture.PictureId).Cols("transaction_id").Update(&models.TransactionPictureInfo{TransactionId: 502})
+	assert.Nil(t, err)
+
+	err = Transactions.DeleteTransaction(c, 1, 501)
+	assert.Nil(t, err)
+
+	pictureObject := getTestTransactionPictureObject(t, c, 1, firstPicture.PictureId)
+	assert.Equal(t, int64(1), pictureObject.ReferenceCount)
+
+	exists, err := TransactionPictures.ExistsTransactionPicture(c, 1, firstPicture.PictureId, "png")
+	assert.Nil(t, err)
+	assert.True(t, exists)
+
+	err = Transactions.DeleteTransaction(c, 1, 502)
+	assert.Nil(t, err)
+
+	assert.Nil(t, getTestTransactionPictureObject(t, c, 1, firstPicture.PictureId))
+
+	exists, err = TransactionPictures.ExistsTransactionPicture(c, 1, firstPicture.PictureId, "png")
+	assert.Nil(t, err)
+	assert.False(t, exists)
+}
+
+func TestRemoveAllOrphanedPictures_RemoveOrphanedRecords(t *testing.T) {
+	c := testutils.InitializeTestDataStore(t)
+	testutils.InitializeTestStorage(t)
+
+	orphanedPicture := &models.TransactionPictureInfo{Uid: 1, PictureExtension: "png"}
+	err := TransactionPictures.UploadPicture(c, orphanedPicture, []byte("orphaned picture content"), nil)
+	assert.Nil(t, err)
+
+	testutils.InsertTestData(t, 1, &models.TransactionPictureObject{Uid: 1, ObjectId: 301, ContentHash: "hash", PictureExtension: "jpg", ReferenceCount: 0, UpdatedUnixTime: 1000})
+	err = TransactionPictures.SaveTransactionPicture(c, 1, 301, storage.NewByteSliceObject([]byte("unreferenced picture content")), "jpg")
+	assert.Nil(t, err)
+
+	result, err := TransactionPictures.RemoveAllOrphanedPictures(c, 500)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, result.RemovedPictureCount)
+	assert.Equal(t, 0, result.DeletedObjectCount)
+
+	result, err = TransactionPictures.RemoveAllOrphanedPictures(c, time.Now().Unix()+10)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, result.RemovedPictureCount)
+	assert.Equal(t, 1, result.DeletedObjectCount)
+	assert.Equal(t, 0, result.DeletedFileCount)
+
+	assert.Nil(t, getTestTransactionPictureObject(t, c, 1, 301))
+
+	exists, err := TransactionPictures.ExistsTransactionPicture(c, 1, orphanedPicture.PictureId, "png")
+	assert.Nil(t, err)
+	assert.False(t, exists)
+
+	exists, err = TransactionPictures.ExistsTransactionPicture(c, 1, 301, "jpg")
+	assert.Nil(t, err)
+	assert.False(t, exists)
+}
+
+func TestRemoveAllOrphanedPictures_DeleteStoredFilesWithoutRecords(t *testing.T) {
+	c := testutils.InitializeTestDataStore(t)
+	testutils.InitializeTestStorage(t)
+
+	testutils.InsertTestData(t, 1,
+		&models.TransactionPictureObject{Uid: 1, ObjectId: 402, ContentHash: "hash", PictureExtension: "jpg", ReferenceCount: 1},
+		&models.TransactionPictureInfo{PictureId: 403, Uid: 1, TransactionId: 501, PictureExtension: "png"},
+	)
+
+	err := TransactionPictures.SaveTransactionPicture(c, 1, 401, storage.NewByteSliceObject([]byte("picture without records")), "jpg")
+	assert.Nil(t, err)
+	err = TransactionPictures.SaveTransactionPicture(c, 1, 401, storage.NewByteSliceObject([]byte("thumbnail without records")), models.TRANSACTION_PICTURE_SIZE_SMALL.GetFileExtension())
+	assert.Nil(t, err)
+	err = TransactionPictures.SaveTransactionPicture(c, 1, 402, storage.NewByteSliceObject([]byte("stored picture")), "jpg")
+	assert.Nil(t, err)
+	err = TransactionPictures.SaveTransactionPicture(c, 1, 403, storage.NewByteSliceObject([]byte("picture uploaded before deduplication")), "png")
+	assert.Nil(t, err)
+	err = storage.Container.SaveTransactionPicture(c, "1/unknown.txt", storage.NewByteSliceObject([]byte("unknown file")))
+	assert.Nil(t, err)
+
+	// the files modified within the grace period would not be deleted
+	result, err := TransactionPictures.RemoveAllOrphanedPictures(c, time.Now().Unix()-3600)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, result.DeletedFileCount)
+
+	exists, err := TransactionPictures.ExistsTransactionPicture(c, 1, 401, "jpg")
+	assert.Nil(t, err)
+	assert.True(t, exists)
+
+	result, err = TransactionPictures.RemoveAllOrphanedPictures(c, time.Now().Unix()+10)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, result.DeletedFileCount)
+
+	exists, err = TransactionPictures.ExistsTransactionPicture(c, 1, 401, "jpg")
+	assert.Nil(t, err)
+	assert.False(t, exists)
+
+	exists, err = TransactionPictures.ExistsTransactionPicture(c, 1, 401, models.TRANSACTION_PICTURE_SIZE_SMALL.GetFileExtension())
+	assert.Nil(t, err)
+	assert.False(t, exists)
+
+	exists, err = TransactionPictures.ExistsTransactionPicture(c, 1, 402, "jpg")
+	assert.Nil(t, err)
+	assert.True(t, exists)
+
+	exists, err = TransactionPictures.ExistsTransactionPicture(c, 1, 403, "png")
+	assert.Nil(t, err)
+	assert.True(t, exists)
+
+	exists, err = storage.Container.ExistsTransactionPicture(c, "1/unknown.txt")
+	assert.Nil(t, err)
+	assert.True(t, exists)
+}
+
+func TestParseTransactionPicturePath(t *testing.T) {
+	uid, pictureId, fileExtension, ok := parseTransactionPicturePath("1/100.small.jpg")
+	assert.True(t, ok)
+	assert.Equal(t, int64(1), uid)
+	assert.Equal(t, int64(100), pictureId)
+	assert.Equal(t, "small.jpg", fileExtension)
+
+	_, _, _, ok = parseTransactionPicturePath("1/100")
+	assert.False(t, ok)
+
+	_, _, _, ok = parseTransactionPicturePath("01/100.jpg")
+	assert.False(t, ok)
+
+	_, _, _, ok = parseTransactionPicturePath("1/2/100.jpg")
+	assert.False(t, ok)
+}
+
+func getTestTransactionPictureObject(t *testing.T, c core.Context, uid int64, objectId int64) *models.TransactionPictureObject {
+	pictureObject := &models.TransactionPictureObject{}
+	has, err := datastore.Container.UserDataStore.Choose(uid).NewSession(c).ID(objectId).Where("uid=?", uid).Get(pictureObject)
+	assert.Nil(t, err)
+
+	if !has {
+		return nil
+	}
+
+	return pictureObject
+}
@@ -0,0 +1,326 @@
+package services
+
+import (
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/uuid"
+)
+
+// TransactionTagGroupService represents transaction tag group service
+type TransactionTagGroupService struct {
+	ServiceUsingDB
+	ServiceUsingUuid
+}
+
+// Initialize a transaction tag group service singleton instance
+var (
+	TransactionTagGroups = &TransactionTagGroupService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+	}
+)
+
+// GetAllTagGroupsByUid returns all transaction tag group models in the ledger of given access, or all the tag groups of the ledger owner if the access is for all ledgers
+func (s *TransactionTagGroupService) GetAllTagGroupsByUid(c core.Context, access *models.LedgerAccess) ([]*models.TransactionTagGroup, error) {
+	uid, ledgerId, err := LedgerAccesses.GetLedgerOwnerUid(c, access, models.LEDGER_PERMISSION_VIEW_BASIC_DATA)
+
+	if err != nil {
+		return nil, err
+	}
+
+	condition := "uid=? AND deleted=?"
+	conditionParams := []any{uid, false}
+
+	if ledgerId > 0 {
+		condition = condition + " AND ledger_id=?"
+		conditionParams = append(conditionParams, ledgerId)
+	}
+
+	var tagGroups []*models.TransactionTagGroup
+	err = s.UserDataDB(uid).NewSession(c).Where(condition, conditionParams...).Find(&tagGroups)
+
+	return tagGroups, err
+}
+
+// GetTagGroupByTagGroupId returns a transaction tag group model according to transaction tag group id
+func (s *TransactionTagGroupService) GetTagGroupByTagGroupId(c core.Context, uid int64, tagGroupId int64) (*models.TransactionTagGroup, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if tagGroupId <= 0 {
+		return nil, errs.ErrTransactionTagGroupIdInvalid
+	}
+
+	tagGroup := &models.TransactionTagGroup{}
+	has, err := s.UserDataDB(uid).NewSession(c).ID(tagGroupId).Where("uid=? AND deleted=?", uid, false).Get(tagGroup)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrTransactionTagGroupNotFound
+	}
+
+	return tagGroup, nil
+}
+
+// GetMaxDisplayOrder returns the max display order
+func (s *TransactionTagGroupService) GetMaxDisplayOrder(c core.Context, uid int64) (int32, error) {
+	if uid <= 0 {
+		return 0, errs.ErrUserIdInvalid
+	}
+
+	tagGroup := &models.TransactionTagGroup{}
+	has, err := s.UserDataDB(uid).NewSession(c).Cols("uid", "deleted", "display_order").Where("uid=? AND deleted=?", uid, false).OrderBy("display_order desc").Limit(1).Get(tagGroup)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if has {
+		return tagGroup.DisplayOrder, nil
+	} else {
+		return 0, nil
+	}
+}
+
+// CreateTagGroup saves a new transaction tag group model to database
+func (s *TransactionTagGroupService) CreateTagGroup(c core.Context, tagGroup *models.TransactionTagGroup) error {
+	if tagGroup.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	exists, err := s.ExistsTagGroupName(c, tagGroup.Uid, tagGroup.LedgerId, tagGroup.Name)
+
+	if err != nil {
+		return err
+	} else if exists {
+		return errs.ErrTransactionTagGroupNameAlreadyExists
+	}
+
+	tagGroup.TagGroupId = s.GenerateUuid(uuid.UUID_TYPE_TAG_GROUP)
+
+	if tagGroup.TagGroupId < 1 {
+		return errs.ErrSystemIsBusy
+	}
+
+	tagGroup.Deleted = false
+	tagGroup.CreatedUnixTime = time.Now().Unix()
+	tagGroup.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDataDB(tagGroup.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Insert(tagGroup)
+		return err
+	})
+}
+
+// ModifyTagGroup saves an existed transaction tag group model to database
+func (s *TransactionTagGroupService) ModifyTagGroup(c core.Context, tagGroup *models.TransactionTagGroup) error {
+	if tagGroup.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	exists, err := s.ExistsTagGroupName(c, tagGroup.Uid, tagGroup.LedgerId, tagGroup.Name)
+
+	if err != nil {
+		return err
+	} else if exists {
+		return errs.ErrTransactionTagGroupNameAlreadyExists
+	}
+
+	tagGroup.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDataDB(tagGroup.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		updatedRows, err := sess.ID(tagGroup.TagGroupId).Cols("name", "updated_unix_time").Where("uid=? AND deleted=?", tagGroup.Uid, false).Update(tagGroup)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrTransactionTagGroupNotFound
+		}
+
+		return err
+	})
+}
+
+// ModifyTagGroupDisplayOrders updates display order of given transaction tag groups
+func (s *TransactionTagGroupService) ModifyTagGroupDisplayOrders(c core.Context, uid int64, tagGroups []*models.TransactionTagGroup) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	for i := 0; i < len(tagGroups); i++ {
+		tagGroups[i].UpdatedUnixTime = time.Now().Unix()
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		for i := 0; i < len(tagGroups); i++ {
+			tagGroup := tagGroups[i]
+			updatedRows, err := sess.ID(tagGroup.TagGroupId).Cols("display_order", "updated_unix_time").Where("uid=? AND deleted=?", uid, false).Update(tagGroup)
+
+			if err != nil {
+				return err
+			} else if updatedRows < 1 {
+				return errs.ErrTransactionTagGroupNotFound
+			}
+		}
+
+		return nil
+	})
+}
+
+// DeleteTagGroup deletes an existed transaction tag group from database, and all tags in this group will become ungrouped
+func (s *TransactionTagGroupService) DeleteTagGroup(c core.Context, uid int64, tagGroupId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.TransactionTagGroup{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	tagUpdateModel := &models.TransactionTag{
+		TagGroupId:      0,
+		UpdatedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		deletedRows, err := sess.ID(tagGroupId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrTransactionTagGroupNotFound
+		}
+
+		_, err = sess.Cols("tag_group_id", "updated_unix_time").Where("uid=? AND deleted=? AND tag_group_id=?", uid, false, tagGroupId).Update(tagUpdateModel)
+
+		return err
+	})
+}
+
+// DeleteAllTagGroups deletes all existed transaction tag groups from database
+func (s *TransactionTagGroupService) DeleteAllTagGroups(c core.Context, uid int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	updateModel := &models.TransactionTagGroup{
+		Deleted:         true,
+		DeletedUnixTime: time.Now().Unix(),
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+		return err
+	})
+}
+
+// ExistsTagGroupName returns whether the given tag group name exists in given ledger
+func (s *TransactionTagGroupService) ExistsTagGroupName(c core.Context, uid int64, ledgerId int64, name string) (bool, error) {
+	if name == "" {
+		return false, errs.ErrTransactionTagGroupNameIsEmpty
+	}
+
+	return s.UserDataDB(uid).NewSession(c).Cols("name").Where("uid=? AND deleted=? AND ledger_id=? AND name=?", uid, false, ledgerId, name).Exist(&models.TransactionTagGroup{})
+}
+
+// GetTagGroupStatistic returns the income and expense amounts of each tag in the tag group and the totals of the whole tag group by currency,
+// only income and expense transactions are counted, and transactions having more than one tag in the group are counted only once in totals
+func (s *TransactionTagGroupService) GetTagGroupStatistic(tagGroup *models.TransactionTagGroup, tagIndexes []*models.TransactionTagIndex, transactionMap map[int64]*models.Transaction, accountMap map[int64]*models.Account) *models.TransactionTagGroupStatisticResponse {
+	tagItems := make(map[int64]map[string]*models.TransactionTagStatisticItemResponse)
+	totalItems := make(map[string]*models.TransactionTagStatisticItemResponse)
+	countedTransactionIds := make(map[int64]bool)
+	countedTagTransactionIds := make(map[int64]map[int64]bool)
+
+	for i := 0; i < len(tagIndexes); i++ {
+		tagIndex := tagIndexes[i]
+		transaction, exists := transactionMap[tagIndex.TransactionId]
+
+		if !exists || (transaction.Type != models.TRANSACTION_DB_TYPE_INCOME && transaction.Type != models.TRANSACTION_DB_TYPE_EXPENSE) {
+			continue
+		}
+
+		account, exists := accountMap[transaction.AccountId]
+
+		if !exists {
+			continue
+		}
+
+		if countedTagTransactionIds[tagIndex.TagId] == nil {
+			countedTagTransactionIds[tagIndex.TagId] = make(map[int64]bool)
+		}
+
+		if !countedTagTransactionIds[tagIndex.TagId][transaction.TransactionId] {
+			countedTagTransactionIds[tagIndex.TagId][transaction.TransactionId] = true
+
+			currencyItems, exists := tagItems[tagIndex.TagId]
+
+			if !exists {
+				currencyItems = make(map[string]*models.TransactionTagStatisticItemResponse)
+				tagItems[tagIndex.TagId] = currencyItems
+			}
+
+			s.addTransactionToStatisticItem(currencyItems, tagIndex.TagId, account.Currency, transaction)
+		}
+
+		if !countedTransactionIds[transaction.TransactionId] {
+			countedTransactionIds[transaction.TransactionId] = true
+			s.addTransactionToStatisticItem(totalItems, 0, account.Currency, transaction)
+		}
+	}
+
+	statistic := &models.TransactionTagGroupStatisticResponse{
+		TagGroup: tagGroup.ToTransactionTagGroupInfoResponse(),
+		Items:    make([]*models.TransactionTagStatisticItemResponse, 0),
+		Totals:   make([]*models.TransactionTagStatisticItemResponse, 0, len(totalItems)),
+	}
+
+	for _, currencyItems := range tagItems {
+		for _, item := range currencyItems {
+			statistic.Items = append(statistic.Items, item)
+		}
+	}
+
+	for _, item := range totalItems {
+		statistic.Totals = append(statistic.Totals, item)
+	}
+
+	models.SortTransactionTagStatisticItems(statistic.Items)
+	models.SortTransactionTagStatisticItems(statistic.Totals)
+
+	return statistic
+}
+
+func (s *TransactionTagGroupService) addTransactionToStatisticItem(currencyItems map[string]*models.TransactionTagStatisticItemResponse, tagId int64, currency string, transaction *models.Transaction) {
+	item, exists := currencyItems[currency]
+
+	if !exists {
+		item = &models.TransactionTagStatisticItemResponse{
+			TagId:    tagId,
+			Currency: currency,
+		}
+		currencyItems[currency] = item
+	}
+
+	if transaction.Type == models.TRANSACTION_DB_TYPE_INCOME {
+		item.IncomeAmount += transaction.Amount
+	} else {
+		item.ExpenseAmount += transaction.Amount
+	}
+
+	item.TransactionCount++
+}
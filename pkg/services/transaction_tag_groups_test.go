@@ -0,0 +1,66 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+func TestGetTagGroupStatistic(t *testing.T) {
+	tagGroup := &models.TransactionTagGroup{
+		TagGroupId: 1,
+		Name:       "Person",
+	}
+
+	accountMap := map[int64]*models.Account{
+		101: {AccountId: 101, Currency: "USD"},
+		102: {AccountId: 102, Currency: "EUR"},
+	}
+
+	transactionMap := map[int64]*models.Transaction{
+		1: {TransactionId: 1, Type: models.TRANSACTION_DB_TYPE_EXPENSE, AccountId: 101, Amount: 3000},
+		2: {TransactionId: 2, Type: models.TRANSACTION_DB_TYPE_INCOME, AccountId: 101, Amount: 1000},
+		3: {TransactionId: 3, Type: models.TRANSACTION_DB_TYPE_EXPENSE, AccountId: 102, Amount: 500},
+		4: {TransactionId: 4, Type: models.TRANSACTION_DB_TYPE_TRANSFER_OUT, AccountId: 101, Amount: 2000},
+	}
+
+	tagIndexes := []*models.TransactionTagIndex{
+		{TagId: 11, TransactionId: 1},
+		{TagId: 12, TransactionId: 1},
+		{TagId: 11, TransactionId: 2},
+		{TagId: 12, TransactionId: 3},
+		{TagId: 11, TransactionId: 4},
+		{TagId: 11, TransactionId: 5},
+	}
+
+	statistic := TransactionTagGroups.GetTagGroupStatistic(tagGroup, tagIndexes, transactionMap, accountMap)
+
+	assert.Equal(t, int64(1), statistic.TagGroup.Id)
+	assert.Equal(t, 3, len(statistic.Items))
+
+	assert.Equal(t, int64(11), statistic.Items[0].TagId)
+	assert.Equal(t, "USD", statistic.Items[0].Currency)
+	assert.Equal(t, int64(1000), statistic.Items[0].IncomeAmount)
+	assert.Equal(t, int64(3000), statistic.Items[0].ExpenseAmount)
+	assert.Equal(t, int64(2), statistic.Items[0].TransactionCount)
+
+	assert.Equal(t, int64(12), statistic.Items[1].TagId)
+	assert.Equal(t, "EUR", statistic.Items[1].Currency)
+	assert.Equal(t, int64(500), statistic.Items[1].ExpenseAmount)
+	assert.Equal(t, int64(1), statistic.Items[1].TransactionCount)
+
+	assert.Equal(t, int64(12), statistic.Items[2].TagId)
+	assert.Equal(t, "USD", statistic.Items[2].Currency)
+	assert.Equal(t, int64(3000), statistic.Items[2].ExpenseAmount)
+	assert.Equal(t, int64(1), statistic.Items[2].TransactionCount)
+
+	assert.Equal(t, 2, len(statistic.Totals))
+	assert.Equal(t, "EUR", statistic.Totals[0].Currency)
+	assert.Equal(t, int64(500), statistic.Totals[0].ExpenseAmount)
+	assert.Equal(t, "USD", statistic.Totals[1].Currency)
+	assert.Equal(t, int64(1000), statistic.Totals[1].IncomeAmount)
+	assert.Equal(t, int64(3000), statistic.Totals[1].ExpenseAmount)
+	assert.Equal(t, int64(2), statistic.Totals[1].TransactionCount)
+}
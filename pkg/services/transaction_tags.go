@@ -45,14 +45,24 @@ func (s *TransactionTagService) GetTotalTagCountByUid(c core.Context, uid int64)
 	return count, err
 }
 
-// GetAllTagsByUid returns all transaction tag models of user
-func (s *TransactionTagService) GetAllTagsByUid(c core.Context, uid int64) ([]*models.TransactionTag, error) {
-	if uid <= 0 {
-		return nil, errs.ErrUserIdInvalid
+// GetAllTagsByUid returns all transaction tag models in the ledger of given access, or all the tags of the ledger owner if the access is for all ledgers
+func (s *TransactionTagService) GetAllTagsByUid(c core.Context, access *models.LedgerAccess) ([]*models.TransactionTag, error) {
+	uid, ledgerId, err := LedgerAccesses.GetLedgerOwnerUid(c, access, models.LEDGER_PERMISSION_VIEW_BASIC_DATA)
+
+	if err != nil {
+		return nil, err
+	}
+
+	condition := "uid=? AND deleted=?"
+	conditionParams := []any{uid, false}
+
+	if ledgerId > 0 {
+		condition = condition + " AND ledger_id=?"
+		conditionParams = append(conditionParams, ledgerId)
 	}
 
 	var tags []*models.TransactionTag
-	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).Find(&tags)
+	err = s.UserDataDB(uid).NewSession(c).Where(condition, conditionParams...).Find(&tags)
 
 	return tags, err
 }
@@ -193,13 +203,51 @@ func (s *TransactionTagService) GetAllTagIdsOfTransactions(c core.Context, uid i
 	return allTransactionTagIds, err
 }
 
+// GetTagIndexesByTagIds returns transaction tag indexes of given tags in the time range, the time range is not limited if max or min transaction time is zero
+func (s *TransactionTagService) GetTagIndexesByTagIds(c core.Context, uid int64, tagIds []int64, maxTransactionTime int64, minTransactionTime int64) ([]*models.TransactionTagIndex, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if len(tagIds) < 1 {
+		return nil, nil
+	}
+
+	condition := "uid=? AND deleted=?"
+	conditionParams := []any{uid, false}
+
+	if maxTransactionTime > 0 {
+		condition = condition + " AND transaction_time<=?"
+		conditionParams = append(conditionParams, maxTransactionTime)
+	}
+
+	if minTransactionTime > 0 {
+		condition = condition + " AND transaction_time>=?"
+		conditionParams = append(conditionParams, minTransactionTime)
+	}
+
+	var tagIndexes []*models.TransactionTagIndex
+	err := s.UserDataDB(uid).NewSession(c).Where(condition, conditionParams...).In("tag_id", tagIds).Find(&tagIndexes)
+
+	return tagIndexes, err
+}
+
+// ExistsChildTags returns whether the given tag has child tags
+func (s *TransactionTagService) ExistsChildTags(c core.Context, uid int64, tagId int64) (bool, error) {
+	if uid <= 0 {
+		return false, errs.ErrUserIdInvalid
+	}
+
+	return s.UserDataDB(uid).NewSession(c).Cols("uid", "parent_tag_id").Where("uid=? AND deleted=? AND parent_tag_id=?", uid, false, tagId).Exist(&models.TransactionTag{})
+}
+
 // CreateTag saves a new transaction tag model to database
 func (s *TransactionTagService) CreateTag(c core.Context, tag *models.TransactionTag) error {
 	if tag.Uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
 
-	exists, err := s.ExistsTagName(c, tag.Uid, tag.Name)
+	exists, err := s.ExistsTagName(c, tag.Uid, tag.LedgerId, tag.Name)
 
 	if err != nil {
 		return err
@@ -230,13 +278,15 @@ func (s *TransactionTagService) CreateTags(c core.Context, uid int64, tags []*mo
 	}
 
 	allTagNames := make([]string, len(tags))
+	ledgerId := int64(0)
 
 	for i := 0; i < len(tags); i++ {
 		allTagNames[i] = tags[i].Name
+		ledgerId = tags[i].LedgerId
 	}
 
 	var existTags []*models.TransactionTag
-	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).In("name", allTagNames).Find(&existTags)
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=? AND ledger_id=?", uid, false, ledgerId).In("name", allTagNames).Find(&existTags)
 
 	if err != nil {
 		return err
@@ -293,24 +343,28 @@ func (s *TransactionTagService) CreateTags(c core.Context, uid int64, tags []*mo
 	})
 }
 
-// ModifyTag saves an existed transaction tag model to database
+// ModifyTag saves an existed transaction tag model to database, and moves all its child tags to the same tag group
 func (s *TransactionTagService) ModifyTag(c core.Context, tag *models.TransactionTag) error {
 	if tag.Uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
 
-	exists, err := s.ExistsTagName(c, tag.Uid, tag.Name)
-
-	if err != nil {
-		return err
-	} else if exists {
-		return errs.ErrTransactionTagNameAlreadyExists
+	if tag.Name == "" {
+		return errs.ErrTransactionTagNameIsEmpty
 	}
 
 	tag.UpdatedUnixTime = time.Now().Unix()
 
 	return s.UserDataDB(tag.Uid).DoTransaction(c, func(sess *xorm.Session) error {
-		updatedRows, err := sess.ID(tag.TagId).Cols("name", "updated_unix_time").Where("uid=? AND deleted=?", tag.Uid, false).Update(tag)
+		exists, err := sess.Cols("name").Where("uid=? AND deleted=? AND ledger_id=? AND name=? AND tag_id<>?", tag.Uid, false, tag.LedgerId, tag.Name, tag.TagId).Exist(&models.TransactionTag{})
+
+		if err != nil {
+			return err
+		} else if exists {
+			return errs.ErrTransactionTagNameAlreadyExists
+		}
+
+		updatedRows, err := sess.ID(tag.TagId).Cols("name", "tag_group_id", "parent_tag_id", "updated_unix_time").Where("uid=? AND deleted=?", tag.Uid, false).Update(tag)
 
 		if err != nil {
 			return err
@@ -318,6 +372,13 @@ func (s *TransactionTagService) ModifyTag(c core.Context, tag *models.Transactio
 			return errs.ErrTransactionTagNotFound
 		}
 
+		childTagUpdateModel := &models.TransactionTag{
+			TagGroupId:      tag.TagGroupId,
+			UpdatedUnixTime: tag.UpdatedUnixTime,
+		}
+
+		_, err = sess.Cols("tag_group_id", "updated_unix_time").Where("uid=? AND deleted=? AND parent_tag_id=? AND tag_group_id<>?", tag.Uid, false, tag.TagId, tag.TagGroupId).Update(childTagUpdateModel)
+
 		return err
 	})
 }
@@ -396,6 +457,14 @@ func (s *TransactionTagService) DeleteTag(c core.Context, uid int64, tagId int64
 			return errs.ErrTransactionTagInUseCannotBeDeleted
 		}
 
+		exists, err = sess.Cols("uid", "parent_tag_id").Where("uid=? AND deleted=? AND parent_tag_id=?", uid, false, tagId).Limit(1).Exist(&models.TransactionTag{})
+
+		if err != nil {
+			return err
+		} else if exists {
+			return errs.ErrTransactionTagHasChildTags
+		}
+
 		var relatedTransactionTemplatesByTag []*models.TransactionTemplate
 		err = sess.Cols("uid", "deleted", "tag_ids", "template_type", "scheduled_frequency_type", "scheduled_end_time").Where("uid=? AND deleted=? AND (template_type=? OR (template_type=? AND scheduled_frequency_type<>? AND (scheduled_end_time IS NULL OR scheduled_end_time>=?))) AND tag_ids LIKE ?", uid, false, models.TRANSACTION_TEMPLATE_TYPE_NORMAL, models.TRANSACTION_TEMPLATE_TYPE_SCHEDULE, models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_DISABLED, now, "%%"+utils.Int64ToString(tagId)+"%%").Find(&relatedTransactionTemplatesByTag)
 
@@ -430,6 +499,157 @@ func (s *TransactionTagService) DeleteTag(c core.Context, uid int64, tagId int64
 	})
 }
 
+// MergeTag replaces the source tag with the target tag in all transactions and transaction templates and then deletes the source tag,
+// the child tags of the source tag are moved under the target tag
+func (s *TransactionTagService) MergeTag(c core.Context, uid int64, fromTagId int64, toTagId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if fromTagId <= 0 || toTagId <= 0 {
+		return errs.ErrTransactionTagIdInvalid
+	}
+
+	if fromTagId == toTagId {
+		return errs.ErrCannotMergeTransactionTagIntoItself
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		fromTag := &models.TransactionTag{}
+		has, err := sess.ID(fromTagId).Where("uid=? AND deleted=?", uid, false).Get(fromTag)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrTransactionTagNotFound
+		}
+
+		toTag := &models.TransactionTag{}
+		has, err = sess.ID(toTagId).Where("uid=? AND deleted=?", uid, false).Get(toTag)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrTransactionTagNotFound
+		}
+
+		if fromTag.LedgerId != toTag.LedgerId {
+			return errs.ErrCannotUseDataInDifferentLedger
+		}
+
+		now := time.Now().Unix()
+
+		// move child tags
+		hasChildTags, err := sess.Cols("uid", "parent_tag_id").Where("uid=? AND deleted=? AND parent_tag_id=?", uid, false, fromTagId).Exist(&models.TransactionTag{})
+
+		if err != nil {
+			return err
+		} else if hasChildTags && toTag.ParentTagId > 0 {
+			return errs.ErrTransactionTagHasChildTags
+		} else if hasChildTags {
+			childTagUpdateModel := &models.TransactionTag{
+				TagGroupId:      toTag.TagGroupId,
+				ParentTagId:     toTagId,
+				UpdatedUnixTime: now,
+			}
+
+			_, err = sess.Cols("tag_group_id", "parent_tag_id", "updated_unix_time").Where("uid=? AND deleted=? AND parent_tag_id=?", uid, false, fromTagId).Update(childTagUpdateModel)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		// delete the source tag indexes of transactions which already have the target tag, and replace the others
+		var toTagIndexes []*models.TransactionTagIndex
+		err = sess.Cols("transaction_id").Where("uid=? AND deleted=? AND tag_id=?", uid, false, toTagId).Find(&toTagIndexes)
+
+		if err != nil {
+			return err
+		}
+
+		if len(toTagIndexes) > 0 {
+			transactionIds := make([]int64, len(toTagIndexes))
+
+			for i := 0; i < len(toTagIndexes); i++ {
+				transactionIds[i] = toTagIndexes[i].TransactionId
+			}
+
+			tagIndexDeleteModel := &models.TransactionTagIndex{
+				Deleted:         true,
+				DeletedUnixTime: now,
+			}
+
+			for i := 0; i < len(transactionIds); i += pageCountForLoadTransactionAmounts {
+				end := i + pageCountForLoadTransactionAmounts
+
+				if end > len(transactionIds) {
+					end = len(transactionIds)
+				}
+
+				_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND tag_id=?", uid, false, fromTagId).In("transaction_id", transactionIds[i:end]).Update(tagIndexDeleteModel)
+
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		tagIndexUpdateModel := &models.TransactionTagIndex{
+			TagId:           toTagId,
+			UpdatedUnixTime: now,
+		}
+
+		_, err = sess.Cols("tag_id", "updated_unix_time").Where("uid=? AND deleted=? AND tag_id=?", uid, false, fromTagId).Update(tagIndexUpdateModel)
+
+		if err != nil {
+			return err
+		}
+
+		// replace the source tag in transaction templates
+		var relatedTransactionTemplates []*models.TransactionTemplate
+		err = sess.Cols("template_id", "tag_ids").Where("uid=? AND deleted=? AND tag_ids LIKE ?", uid, false, "%%"+utils.Int64ToString(fromTagId)+"%%").Find(&relatedTransactionTemplates)
+
+		if err != nil {
+			return err
+		}
+
+		for i := 0; i < len(relatedTransactionTemplates); i++ {
+			template := relatedTransactionTemplates[i]
+			newTagIds, changed := s.replaceTagIdInTagIds(template.TagIds, fromTagId, toTagId)
+
+			if !changed {
+				continue
+			}
+
+			template.TagIds = newTagIds
+			template.UpdatedUnixTime = now
+
+			_, err = sess.ID(template.TemplateId).Cols("tag_ids", "updated_unix_time").Where("uid=? AND deleted=?", uid, false).Update(template)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		// delete the source tag
+		deleteModel := &models.TransactionTag{
+			Deleted:         true,
+			DeletedUnixTime: now,
+		}
+
+		deletedRows, err := sess.ID(fromTagId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(deleteModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrTransactionTagNotFound
+		}
+
+		return nil
+	})
+}
+
 // DeleteAllTags deletes all existed transaction tags from database
 func (s *TransactionTagService) DeleteAllTags(c core.Context, uid int64) error {
 	if uid <= 0 {
@@ -462,13 +682,13 @@ func (s *TransactionTagService) DeleteAllTags(c core.Context, uid int64) error {
 	})
 }
 
-// ExistsTagName returns whether the given tag name exists
-func (s *TransactionTagService) ExistsTagName(c core.Context, uid int64, name string) (bool, error) {
+// ExistsTagName returns whether the given tag name exists in given ledger
+func (s *TransactionTagService) ExistsTagName(c core.Context, uid int64, ledgerId int64, name string) (bool, error) {
 	if name == "" {
 		return false, errs.ErrTransactionTagNameIsEmpty
 	}
 
-	return s.UserDataDB(uid).NewSession(c).Cols("name").Where("uid=? AND deleted=? AND name=?", uid, false, name).Exist(&models.TransactionTag{})
+	return s.UserDataDB(uid).NewSession(c).Cols("name").Where("uid=? AND deleted=? AND ledger_id=? AND name=?", uid, false, ledgerId, name).Exist(&models.TransactionTag{})
 }
 
 // ModifyTagIndexTransactionTime updates transaction time of given transaction tag indexes
@@ -585,3 +805,38 @@ func (s *TransactionTagService) GetTransactionTagIds(allTransactionTagIds map[in
 
 	return allTagIds
 }
+
+func (s *TransactionTagService) replaceTagIdInTagIds(tagIds string, fromTagId int64, toTagId int64) (string, bool) {
+	if tagIds == "" {
+		return tagIds, false
+	}
+
+	fromTagIdStr := utils.Int64ToString(fromTagId)
+	toTagIdStr := utils.Int64ToString(toTagId)
+	items := strings.Split(tagIds, ",")
+	newItems := make([]string, 0, len(items))
+	existedItems := make(map[string]bool, len(items))
+	changed := false
+
+	for i := 0; i < len(items); i++ {
+		item := items[i]
+
+		if item == fromTagIdStr {
+			item = toTagIdStr
+			changed = true
+		}
+
+		if existedItems[item] {
+			continue
+		}
+
+		existedItems[item] = true
+		newItems = append(newItems, item)
+	}
+
+	if !changed {
+		return tagIds, false
+	}
+
+	return strings.Join(newItems, ","), true
+}
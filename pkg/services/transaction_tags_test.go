@@ -5,7 +5,9 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
 	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/testutils"
 )
 
 func TestGetTagMapByList_EmptyList(t *testing.T) {
@@ -218,3 +220,59 @@ func TestGetTransactionTagIds_MixedTransactionTagSlices(t *testing.T) {
 	assert.Contains(t, actualTagIds, int64(2002))
 	assert.Contains(t, actualTagIds, int64(2003))
 }
+
+func TestReplaceTagIdInTagIds(t *testing.T) {
+	actualTagIds, changed := TransactionTags.replaceTagIdInTagIds("1,2,3", 2, 4)
+	assert.True(t, changed)
+	assert.Equal(t, "1,4,3", actualTagIds)
+
+	actualTagIds, changed = TransactionTags.replaceTagIdInTagIds("1,2,3", 2, 3)
+	assert.True(t, changed)
+	assert.Equal(t, "1,3", actualTagIds)
+
+	actualTagIds, changed = TransactionTags.replaceTagIdInTagIds("1,12,3", 2, 4)
+	assert.False(t, changed)
+	assert.Equal(t, "1,12,3", actualTagIds)
+
+	actualTagIds, changed = TransactionTags.replaceTagIdInTagIds("", 2, 4)
+	assert.False(t, changed)
+	assert.Equal(t, "", actualTagIds)
+}
+
+func TestMergeTag_TagsInDifferentLedgers(t *testing.T) {
+	c := testutils.InitializeTestDataStore(t)
+
+	testutils.InsertTestData(t, 1,
+		&models.TransactionTag{TagId: 101, Uid: 1, LedgerId: 1001, Name: "Trip"},
+		&models.TransactionTag{TagId: 102, Uid: 1, LedgerId: 1002, Name: "Trip"},
+		&models.TransactionTagIndex{TagIndexId: 201, Uid: 1, TagId: 101, TransactionId: 501},
+	)
+
+	err := TransactionTags.MergeTag(c, 1, 101, 102)
+	assert.Equal(t, errs.ErrCannotUseDataInDifferentLedger, err)
+
+	tag, err := TransactionTags.GetTagByTagId(c, 1, 101)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(101), tag.TagId)
+
+	tagIds, err := TransactionTags.GetAllTagIdsOfTransactions(c, 1, []int64{501})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{101}, tagIds[501])
+}
+
+func TestMergeTag_TagsInSameLedger(t *testing.T) {
+	c := testutils.InitializeTestDataStore(t)
+
+	testutils.InsertTestData(t, 1,
+		&models.TransactionTag{TagId: 101, Uid: 1, LedgerId: 1001, Name: "Trip"},
+		&models.TransactionTag{TagId: 102, Uid: 1, LedgerId: 1001, Name: "Travel"},
+		&models.TransactionTagIndex{TagIndexId: 201, Uid: 1, TagId: 101, TransactionId: 501},
+	)
+
+	err := TransactionTags.MergeTag(c, 1, 101, 102)
+	assert.Nil(t, err)
+
+	tagIds, err := TransactionTags.GetAllTagIdsOfTransactions(c, 1, []int64{501})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{102}, tagIds[501])
+}
@@ -52,14 +52,24 @@ func (s *TransactionTemplateService) GetTotalScheduledTemplateCountByUid(c core.
 	return count, err
 }
 
-// GetAllTemplatesByUid returns all transaction template models of user
-func (s *TransactionTemplateService) GetAllTemplatesByUid(c core.Context, uid int64, templateType models.TransactionTemplateType) ([]*models.TransactionTemplate, error) {
-	if uid <= 0 {
-		return nil, errs.ErrUserIdInvalid
+// GetAllTemplatesByUid returns all transaction template models in the ledger of given access, or all the templates of the ledger owner if the access is for all ledgers
+func (s *TransactionTemplateService) GetAllTemplatesByUid(c core.Context, access *models.LedgerAccess, templateType models.TransactionTemplateType) ([]*models.TransactionTemplate, error) {
+	uid, ledgerId, err := LedgerAccesses.GetLedgerOwnerUid(c, access, models.LEDGER_PERMISSION_VIEW_BASIC_DATA)
+
+	if err != nil {
+		return nil, err
+	}
+
+	condition := "uid=? AND deleted=? AND template_type=?"
+	conditionParams := []any{uid, false, templateType}
+
+	if ledgerId > 0 {
+		condition = condition + " AND ledger_id=?"
+		conditionParams = append(conditionParams, ledgerId)
 	}
 
 	var templates []*models.TransactionTemplate
-	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=? AND template_type=?", uid, false, templateType).Find(&templates)
+	err = s.UserDataDB(uid).NewSession(c).Where(condition, conditionParams...).Find(&templates)
 
 	return templates, err
 }
@@ -9,6 +9,7 @@ import (
 	"xorm.io/builder"
 	"xorm.io/xorm"
 
+	"github.com/mayswind/ezbookkeeping/pkg/converters/converter"
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/datastore"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
@@ -76,11 +77,21 @@ func (s *TransactionService) GetAllTransactions(c core.Context, uid int64, pageC
 
 // GetAllTransactionsByMaxTime returns all transactions before given time
 func (s *TransactionService) GetAllTransactionsByMaxTime(c core.Context, uid int64, maxTransactionTime int64, count int32, noDuplicated bool) ([]*models.Transaction, error) {
-	return s.GetTransactionsByMaxTime(c, uid, maxTransactionTime, 0, 0, nil, nil, nil, false, "", "", 1, count, false, noDuplicated)
+	return s.getTransactionsByMaxTime(c, uid, 0, maxTransactionTime, 0, 0, nil, nil, nil, false, nil, "", "", 1, count, false, noDuplicated)
 }
 
 // GetAllSpecifiedTransactions returns all transactions that match given conditions
-func (s *TransactionService) GetAllSpecifiedTransactions(c core.Context, uid int64, maxTransactionTime int64, minTransactionTime int64, transactionType models.TransactionType, categoryIds []int64, accountIds []int64, tagFilters []*models.TransactionTagFilter, noTags bool, amountFilter string, keyword string, pageCount int32, noDuplicated bool) ([]*models.Transaction, error) {
+func (s *TransactionService) GetAllSpecifiedTransactions(c core.Context, access *models.LedgerAccess, maxTransactionTime int64, minTransactionTime int64, transactionType models.TransactionType, categoryIds []int64, accountIds []int64, tagFilters []*models.TransactionTagFilter, noTags bool, customFieldFilters []*models.TransactionCustomFieldFilter, amountFilter string, keyword string, pageCount int32, noDuplicated bool) ([]*models.Transaction, error) {
+	uid, ledgerId, err := LedgerAccesses.GetLedgerOwnerUid(c, access, models.LEDGER_PERMISSION_VIEW_TRANSACTIONS)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return s.getAllSpecifiedTransactions(c, uid, ledgerId, maxTransactionTime, minTransactionTime, transactionType, categoryIds, accountIds, tagFilters, noTags, customFieldFilters, amountFilter, keyword, pageCount, noDuplicated)
+}
+
+func (s *TransactionService) getAllSpecifiedTransactions(c core.Context, uid int64, ledgerId int64, maxTransactionTime int64, minTransactionTime int64, transactionType models.TransactionType, categoryIds []int64, accountIds []int64, tagFilters []*models.TransactionTagFilter, noTags bool, customFieldFilters []*models.TransactionCustomFieldFilter, amountFilter string, keyword string, pageCount int32, noDuplicated bool) ([]*models.Transaction, error) {
 	if maxTransactionTime <= 0 {
 		maxTransactionTime = utils.GetMaxTransactionTimeFromUnixTime(time.Now().Unix())
 	}
@@ -88,7 +99,7 @@ func (s *TransactionService) GetAllSpecifiedTransactions(c core.Context, uid int
 	var allTransactions []*models.Transaction
 
 	for maxTransactionTime > 0 {
-		transactions, err := s.GetTransactionsByMaxTime(c, uid, maxTransactionTime, minTransactionTime, transactionType, categoryIds, accountIds, tagFilters, noTags, amountFilter, keyword, 1, pageCount, false, noDuplicated)
+		transactions, err := s.getTransactionsByMaxTime(c, uid, ledgerId, maxTransactionTime, minTransactionTime, transactionType, categoryIds, accountIds, tagFilters, noTags, customFieldFilters, amountFilter, keyword, 1, pageCount, false, noDuplicated)
 
 		if err != nil {
 			return nil, err
@@ -116,7 +127,7 @@ func (s *TransactionService) GetAllTransactionsInOneAccountWithAccountBalanceByM
 	var allTransactions []*models.Transaction
 
 	for maxTransactionTime > 0 {
-		transactions, err := s.GetTransactionsByMaxTime(c, uid, maxTransactionTime, 0, 0, nil, []int64{accountId}, nil, false, "", "", 1, pageCount, false, true)
+		transactions, err := s.getTransactionsByMaxTime(c, uid, 0, maxTransactionTime, 0, 0, nil, []int64{accountId}, nil, false, nil, "", "", 1, pageCount, false, true)
 
 		if err != nil {
 			return nil, 0, 0, 0, 0, err
@@ -198,7 +209,13 @@ func (s *TransactionService) GetAllTransactionsInOneAccountWithAccountBalanceByM
 }
 
 // GetAllAccountsDailyOpeningAndClosingBalance returns daily opening and closing balance of all accounts within time range
-func (s *TransactionService) GetAllAccountsDailyOpeningAndClosingBalance(c core.Context, uid int64, maxTransactionTime int64, minTransactionTime int64, clientTimezone *time.Location) (map[int32][]*models.TransactionWithAccountBalance, error) {
+func (s *TransactionService) GetAllAccountsDailyOpeningAndClosingBalance(c core.Context, access *models.LedgerAccess, maxTransactionTime int64, minTransactionTime int64, clientTimezone *time.Location) (map[int32][]*models.TransactionWithAccountBalance, error) {
+	uid, ledgerId, err := LedgerAccesses.GetLedgerOwnerUid(c, access, models.LEDGER_PERMISSION_VIEW_TRANSACTIONS)
+
+	if err != nil {
+		return nil, err
+	}
+
 	if maxTransactionTime <= 0 {
 		maxTransactionTime = utils.GetMaxTransactionTimeFromUnixTime(time.Now().Unix())
 	}
@@ -206,7 +223,7 @@ func (s *TransactionService) GetAllAccountsDailyOpeningAndClosingBalance(c core.
 	var allTransactions []*models.Transaction
 
 	for maxTransactionTime > 0 {
-		transactions, err := s.GetTransactionsByMaxTime(c, uid, maxTransactionTime, 0, 0, nil, nil, nil, false, "", "", 1, pageCountForLoadTransactionAmounts, false, false)
+		transactions, err := s.getTransactionsByMaxTime(c, uid, ledgerId, maxTransactionTime, 0, 0, nil, nil, nil, false, nil, "", "", 1, pageCountForLoadTransactionAmounts, false, false)
 
 		if err != nil {
 			return nil, err
@@ -323,7 +340,84 @@ func (s *TransactionService) GetAllAccountsDailyOpeningAndClosingBalance(c core.
 }
 
 // GetTransactionsByMaxTime returns transactions before given time
-func (s *TransactionService) GetTransactionsByMaxTime(c core.Context, uid int64, maxTransactionTime int64, minTransactionTime int64, transactionType models.TransactionType, categoryIds []int64, accountIds []int64, tagFilters []*models.TransactionTagFilter, noTags bool, amountFilter string, keyword string, page int32, count int32, needOneMoreItem bool, noDuplicated bool) ([]*models.Transaction, error) {
+func (s *TransactionService) GetTransactionsByMaxTime(c core.Context, access *models.LedgerAccess, maxTransactionTime int64, minTransactionTime int64, transactionType models.TransactionType, categoryIds []int64, accountIds []int64, tagFilters []*models.TransactionTagFilter, noTags bool, customFieldFilters []*models.TransactionCustomFieldFilter, amountFilter string, keyword string, page int32, count int32, needOneMoreItem bool, noDuplicated bool) ([]*models.Transaction, error) {
+	uid, ledgerId, err := LedgerAccesses.GetLedgerOwnerUid(c, access, models.LEDGER_PERMISSION_VIEW_TRANSACTIONS)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return s.getTransactionsByMaxTime(c, uid, ledgerId, maxTransactionTime, minTransactionTime, transactionType, categoryIds, accountIds, tagFilters, noTags, customFieldFilters, amountFilter, keyword, page, count, needOneMoreItem, noDuplicated)
+}
+
+// GetExportedTransactionDataPageReader returns the reader which returns the transactions before given time page by page for exporting,
+// each page also contains the tag indexes and the custom field values (only if needed) of the transactions in the page
+func (s *TransactionService) GetExportedTransactionDataPageReader(c core.Context, access *models.LedgerAccess, maxTransactionTime int64, minTransactionTime int64, transactionType models.TransactionType, categoryIds []int64, accountIds []int64, tagFilters []*models.TransactionTagFilter, noTags bool, amountFilter string, keyword string, count int32, needCustomFieldValues bool) (converter.ExportedTransactionDataPageReader, error) {
+	uid, ledgerId, err := LedgerAccesses.GetLedgerOwnerUid(c, access, models.LEDGER_PERMISSION_VIEW_TRANSACTIONS)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if count < 1 {
+		return nil, errs.ErrPageCountInvalid
+	}
+
+	return func() (*converter.ExportedTransactionDataPage, error) {
+		if maxTransactionTime <= 0 {
+			return nil, nil
+		}
+
+		transactions, err := s.getTransactionsByMaxTime(c, uid, ledgerId, maxTransactionTime, minTransactionTime, transactionType, categoryIds, accountIds, tagFilters, noTags, nil, amountFilter, keyword, 1, count, false, true)
+
+		if err != nil {
+			log.Errorf(c, "[transactions.GetExportedTransactionDataPageReader] failed to get transactions before \"%d\" for user \"uid:%d\", because %s", maxTransactionTime, uid, err.Error())
+			return nil, err
+		}
+
+		if len(transactions) < int(count) {
+			maxTransactionTime = 0
+		} else {
+			maxTransactionTime = transactions[len(transactions)-1].TransactionTime - 1
+		}
+
+		if len(transactions) < 1 {
+			return nil, nil
+		}
+
+		transactionIds := make([]int64, len(transactions))
+
+		for i := 0; i < len(transactions); i++ {
+			transactionIds[i] = transactions[i].TransactionId
+		}
+
+		tagIndexes, err := TransactionTags.GetAllTagIdsOfTransactions(c, uid, transactionIds)
+
+		if err != nil {
+			log.Errorf(c, "[transactions.GetExportedTransactionDataPageReader] failed to get tag index for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, err
+		}
+
+		var customFieldValues map[int64][]*models.TransactionCustomFieldValue
+
+		if needCustomFieldValues {
+			customFieldValues, err = TransactionCustomFields.GetCustomFieldValuesByTransactionIds(c, uid, transactionIds)
+
+			if err != nil {
+				log.Errorf(c, "[transactions.GetExportedTransactionDataPageReader] failed to get custom field values for user \"uid:%d\", because %s", uid, err.Error())
+				return nil, err
+			}
+		}
+
+		return &converter.ExportedTransactionDataPage{
+			Transactions:         transactions,
+			AllTagIndexes:        tagIndexes,
+			AllCustomFieldValues: customFieldValues,
+		}, nil
+	}, nil
+}
+
+func (s *TransactionService) getTransactionsByMaxTime(c core.Context, uid int64, ledgerId int64, maxTransactionTime int64, minTransactionTime int64, transactionType models.TransactionType, categoryIds []int64, accountIds []int64, tagFilters []*models.TransactionTagFilter, noTags bool, customFieldFilters []*models.TransactionCustomFieldFilter, amountFilter string, keyword string, page int32, count int32, needOneMoreItem bool, noDuplicated bool) ([]*models.Transaction, error) {
 	if uid <= 0 {
 		return nil, errs.ErrUserIdInvalid
 	}
@@ -357,9 +451,10 @@ func (s *TransactionService) GetTransactionsByMaxTime(c core.Context, uid int64,
 		actualCount++
 	}
 
-	condition, conditionParams := s.buildTransactionQueryCondition(uid, maxTransactionTime, minTransactionTime, transactionDbType, categoryIds, accountIds, tagFilters, amountFilter, keyword, noDuplicated)
+	condition, conditionParams := s.buildTransactionQueryCondition(uid, ledgerId, maxTransactionTime, minTransactionTime, transactionDbType, categoryIds, accountIds, tagFilters, amountFilter, keyword, noDuplicated)
 	sess := s.UserDataDB(uid).NewSession(c).Where(condition, conditionParams...)
 	sess = s.appendFilterTagIdsConditionToQuery(sess, uid, maxTransactionTime, minTransactionTime, tagFilters, noTags)
+	sess = s.appendFilterCustomFieldsConditionToQuery(sess, uid, customFieldFilters)
 
 	err = sess.Limit(int(actualCount), int(count*(page-1))).OrderBy("transaction_time desc").Find(&transactions)
 
@@ -367,12 +462,13 @@ func (s *TransactionService) GetTransactionsByMaxTime(c core.Context, uid int64,
 }
 
 // GetTransactionsInMonthByPage returns all transactions in given year and month
-func (s *TransactionService) GetTransactionsInMonthByPage(c core.Context, uid int64, year int32, month int32, transactionType models.TransactionType, categoryIds []int64, accountIds []int64, tagFilters []*models.TransactionTagFilter, noTags bool, amountFilter string, keyword string) ([]*models.Transaction, error) {
-	if uid <= 0 {
-		return nil, errs.ErrUserIdInvalid
+func (s *TransactionService) GetTransactionsInMonthByPage(c core.Context, access *models.LedgerAccess, year int32, month int32, transactionType models.TransactionType, categoryIds []int64, accountIds []int64, tagFilters []*models.TransactionTagFilter, noTags bool, customFieldFilters []*models.TransactionCustomFieldFilter, amountFilter string, keyword string) ([]*models.Transaction, error) {
+	uid, ledgerId, err := LedgerAccesses.GetLedgerOwnerUid(c, access, models.LEDGER_PERMISSION_VIEW_TRANSACTIONS)
+
+	if err != nil {
+		return nil, err
 	}
 
-	var err error
 	var transactionDbType models.TransactionDbType = 0
 
 	if transactionType > 0 {
@@ -391,9 +487,10 @@ func (s *TransactionService) GetTransactionsInMonthByPage(c core.Context, uid in
 
 	var transactions []*models.Transaction
 
-	condition, conditionParams := s.buildTransactionQueryCondition(uid, maxTransactionTime, minTransactionTime, transactionDbType, categoryIds, accountIds, tagFilters, amountFilter, keyword, true)
+	condition, conditionParams := s.buildTransactionQueryCondition(uid, ledgerId, maxTransactionTime, minTransactionTime, transactionDbType, categoryIds, accountIds, tagFilters, amountFilter, keyword, true)
 	sess := s.UserDataDB(uid).NewSession(c).Where(condition, conditionParams...)
 	sess = s.appendFilterTagIdsConditionToQuery(sess, uid, maxTransactionTime, minTransactionTime, tagFilters, noTags)
+	sess = s.appendFilterCustomFieldsConditionToQuery(sess, uid, customFieldFilters)
 
 	err = sess.OrderBy("transaction_time desc").Find(&transactions)
 
@@ -434,13 +531,51 @@ func (s *TransactionService) GetTransactionByTransactionId(c core.Context, uid i
 	return transaction, nil
 }
 
+// GetTransactionsByTransactionIds returns the transactions of given transaction ids, only the id, type, category, account, time, amount and related account columns are loaded
+func (s *TransactionService) GetTransactionsByTransactionIds(c core.Context, uid int64, transactionIds []int64) ([]*models.Transaction, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var allTransactions []*models.Transaction
+
+	for i := 0; i < len(transactionIds); i += pageCountForLoadTransactionAmounts {
+		end := i + pageCountForLoadTransactionAmounts
+
+		if end > len(transactionIds) {
+			end = len(transactionIds)
+		}
+
+		var transactions []*models.Transaction
+		err := s.UserDataDB(uid).NewSession(c).Select("transaction_id, type, category_id, account_id, transaction_time, amount, related_account_id, related_account_amount").Where("uid=? AND deleted=?", uid, false).In("transaction_id", transactionIds[i:end]).Find(&transactions)
+
+		if err != nil {
+			return nil, err
+		}
+
+		allTransactions = append(allTransactions, transactions...)
+	}
+
+	return allTransactions, nil
+}
+
 // GetAllTransactionCount returns total count of transactions
 func (s *TransactionService) GetAllTransactionCount(c core.Context, uid int64) (int64, error) {
-	return s.GetTransactionCount(c, uid, 0, 0, 0, nil, nil, nil, false, "", "")
+	return s.getTransactionCount(c, uid, 0, 0, 0, 0, nil, nil, nil, false, nil, "", "")
 }
 
 // GetTransactionCount returns count of transactions
-func (s *TransactionService) GetTransactionCount(c core.Context, uid int64, maxTransactionTime int64, minTransactionTime int64, transactionType models.TransactionType, categoryIds []int64, accountIds []int64, tagFilters []*models.TransactionTagFilter, noTags bool, amountFilter string, keyword string) (int64, error) {
+func (s *TransactionService) GetTransactionCount(c core.Context, access *models.LedgerAccess, maxTransactionTime int64, minTransactionTime int64, transactionType models.TransactionType, categoryIds []int64, accountIds []int64, tagFilters []*models.TransactionTagFilter, noTags bool, customFieldFilters []*models.TransactionCustomFieldFilter, amountFilter string, keyword string) (int64, error) {
+	uid, ledgerId, err := LedgerAccesses.GetLedgerOwnerUid(c, access, models.LEDGER_PERMISSION_VIEW_TRANSACTIONS)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return s.getTransactionCount(c, uid, ledgerId, maxTransactionTime, minTransactionTime, transactionType, categoryIds, accountIds, tagFilters, noTags, customFieldFilters, amountFilter, keyword)
+}
+
+func (s *TransactionService) getTransactionCount(c core.Context, uid int64, ledgerId int64, maxTransactionTime int64, minTransactionTime int64, transactionType models.TransactionType, categoryIds []int64, accountIds []int64, tagFilters []*models.TransactionTagFilter, noTags bool, customFieldFilters []*models.TransactionCustomFieldFilter, amountFilter string, keyword string) (int64, error) {
 	if uid <= 0 {
 		return 0, errs.ErrUserIdInvalid
 	}
@@ -456,15 +591,16 @@ func (s *TransactionService) GetTransactionCount(c core.Context, uid int64, maxT
 		}
 	}
 
-	condition, conditionParams := s.buildTransactionQueryCondition(uid, maxTransactionTime, minTransactionTime, transactionDbType, categoryIds, accountIds, tagFilters, amountFilter, keyword, true)
+	condition, conditionParams := s.buildTransactionQueryCondition(uid, ledgerId, maxTransactionTime, minTransactionTime, transactionDbType, categoryIds, accountIds, tagFilters, amountFilter, keyword, true)
 	sess := s.UserDataDB(uid).NewSession(c).Where(condition, conditionParams...)
 	sess = s.appendFilterTagIdsConditionToQuery(sess, uid, maxTransactionTime, minTransactionTime, tagFilters, noTags)
+	sess = s.appendFilterCustomFieldsConditionToQuery(sess, uid, customFieldFilters)
 
 	return sess.Count(&models.Transaction{})
 }
 
-// CreateTransaction saves a new transaction to database
-func (s *TransactionService) CreateTransaction(c core.Context, transaction *models.Transaction, tagIds []int64, pictureIds []int64) error {
+// CreateTransaction saves a new transaction, its attachments and custom field values to database
+func (s *TransactionService) CreateTransaction(c core.Context, transaction *models.Transaction, tagIds []int64, pictureIds []int64, attachmentIds []int64, customFieldValues []*models.TransactionCustomFieldValue) error {
 	if transaction.Uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
@@ -531,7 +667,31 @@ func (s *TransactionService) CreateTransaction(c core.Context, transaction *mode
 	userDataDb := s.UserDataDB(transaction.Uid)
 
 	return userDataDb.DoTransaction(c, func(sess *xorm.Session) error {
-		return s.doCreateTransaction(c, userDataDb, sess, transaction, transactionTagIndexes, tagIds, pictureIds, pictureUpdateModel)
+		err := s.doCreateTransaction(c, userDataDb, sess, transaction, transactionTagIndexes, tagIds, pictureIds, pictureUpdateModel)
+
+		if err != nil {
+			return err
+		}
+
+		if len(attachmentIds) > 0 {
+			err = updateTransactionAttachments(sess, transaction.Uid, transaction.TransactionId, attachmentIds, nil, now)
+
+			if err != nil {
+				log.Errorf(c, "[transactions.CreateTransaction] failed to update new transaction attachments, because %s", err.Error())
+				return err
+			}
+		}
+
+		if len(customFieldValues) > 0 {
+			err = replaceTransactionCustomFieldValues(sess, transaction.Uid, transaction.TransactionId, customFieldValues, now)
+
+			if err != nil {
+				log.Errorf(c, "[transactions.CreateTransaction] failed to save transaction custom field values, because %s", err.Error())
+				return err
+			}
+		}
+
+		return nil
 	})
 }
 
@@ -663,6 +823,117 @@ func (s *TransactionService) BatchCreateTransactions(c core.Context, uid int64,
 	})
 }
 
+// CreateContactTransactions saves new transactions and their receivable or payable movements of contacts to database in one database transaction,
+// the contact transaction at the same index of each transaction can be nil if the transaction is not related to any contact
+func (s *TransactionService) CreateContactTransactions(c core.Context, uid int64, transactions []*models.Transaction, contactTransactions []*models.ContactTransaction) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if len(transactions) != len(contactTransactions) {
+		return errs.ErrOperationFailed
+	}
+
+	now := time.Now().Unix()
+	needTransactionUuidCount := uint16(0)
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+
+		if transaction.Uid != uid {
+			return errs.ErrUserIdInvalid
+		}
+
+		// Check whether account id is valid
+		err := s.isAccountIdValid(transaction)
+
+		if err != nil {
+			return err
+		}
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+			needTransactionUuidCount += 2
+		} else {
+			needTransactionUuidCount++
+		}
+
+		transaction.TransactionTime = utils.GetMinTransactionTimeFromUnixTime(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime))
+
+		transaction.CreatedUnixTime = now
+		transaction.UpdatedUnixTime = now
+	}
+
+	transactionUuids := s.GenerateUuids(uuid.UUID_TYPE_TRANSACTION, needTransactionUuidCount)
+	transactionUuidIndex := 0
+
+	if len(transactionUuids) < int(needTransactionUuidCount) {
+		return errs.ErrSystemIsBusy
+	}
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+
+		transaction.TransactionId = transactionUuids[transactionUuidIndex]
+		transactionUuidIndex++
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+			transaction.RelatedId = transactionUuids[transactionUuidIndex]
+			transactionUuidIndex++
+		}
+	}
+
+	userDataDb := s.UserDataDB(uid)
+
+	return userDataDb.DoTransaction(c, func(sess *xorm.Session) error {
+		for i := 0; i < len(transactions); i++ {
+			transaction := transactions[i]
+			err := s.doCreateTransaction(c, userDataDb, sess, transaction, nil, nil, nil, nil)
+
+			if err != nil {
+				log.Errorf(c, "[transactions.CreateContactTransactions] failed to create trasaction, because %s", err.Error())
+				return err
+			}
+
+			contactTransaction := contactTransactions[i]
+
+			if contactTransaction == nil {
+				continue
+			}
+
+			contact := &models.Contact{}
+			has, err := sess.ID(contactTransaction.ContactId).Where("uid=? AND deleted=?", uid, false).Get(contact)
+
+			if err != nil {
+				return err
+			} else if !has {
+				return errs.ErrContactNotFound
+			} else if contact.LedgerId != transaction.LedgerId {
+				return errs.ErrCannotUseDataInDifferentLedger
+			}
+
+			contactTransaction.TransactionId = transaction.TransactionId
+			contactTransaction.Uid = uid
+			contactTransaction.Deleted = false
+			contactTransaction.TransactionTime = transaction.TransactionTime
+			contactTransaction.LedgerId = transaction.LedgerId
+			contactTransaction.CreatedUnixTime = now
+			contactTransaction.UpdatedUnixTime = now
+
+			createdRows, err := sess.Insert(contactTransaction)
+
+			if err != nil {
+				log.Errorf(c, "[transactions.CreateContactTransactions] failed to add contact transaction, because %s", err.Error())
+				return err
+			} else if createdRows < 1 {
+				log.Errorf(c, "[transactions.CreateContactTransactions] failed to add contact transaction")
+				return errs.ErrDatabaseOperationFailed
+			}
+		}
+
+		return nil
+	})
+}
+
 // CreateScheduledTransactions saves all scheduled transactions that should be created now
 func (s *TransactionService) CreateScheduledTransactions(c core.Context, currentUnixTime int64, interval time.Duration) error {
 	var allTemplates []*models.TransactionTemplate
@@ -770,6 +1041,7 @@ func (s *TransactionService) CreateScheduledTransactions(c core.Context, current
 
 		transaction := &models.Transaction{
 			Uid:               template.Uid,
+			LedgerId:          template.LedgerId,
 			Type:              transactionDbType,
 			CategoryId:        template.CategoryId,
 			TransactionTime:   utils.GetMinTransactionTimeFromUnixTime(transactionTime.Unix()),
@@ -787,8 +1059,16 @@ func (s *TransactionService) CreateScheduledTransactions(c core.Context, current
 			transaction.RelatedAccountAmount = template.RelatedAccountAmount
 		}
 
+		err = UserQuotas.CheckTransactionQuotaByUid(c, template.Uid, 1)
+
+		if err != nil {
+			skipCount++
+			log.Warnf(c, "[transactions.CreateScheduledTransactions] transaction template \"id:%d\" cannot create transaction for user \"uid:%d\", because %s", template.TemplateId, template.Uid, err.Error())
+			continue
+		}
+
 		tagIds := template.GetTagIds()
-		err = s.CreateTransaction(c, transaction, tagIds, nil)
+		err = s.CreateTransaction(c, transaction, tagIds, nil, nil, nil)
 
 		if err == nil {
 			successCount++
@@ -804,8 +1084,8 @@ func (s *TransactionService) CreateScheduledTransactions(c core.Context, current
 	return nil
 }
 
-// ModifyTransaction saves an existed transaction to database
-func (s *TransactionService) ModifyTransaction(c core.Context, transaction *models.Transaction, currentTagIdsCount int, addTagIds []int64, removeTagIds []int64, addPictureIds []int64, removePictureIds []int64) error {
+// ModifyTransaction saves an existed transaction to database, the custom field values of transaction are replaced with the given values unless they are nil
+func (s *TransactionService) ModifyTransaction(c core.Context, transaction *models.Transaction, currentTagIdsCount int, addTagIds []int64, removeTagIds []int64, addPictureIds []int64, removePictureIds []int64, addAttachmentIds []int64, removeAttachmentIds []int64, customFieldValues []*models.TransactionCustomFieldValue) error {
 	if transaction.Uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
@@ -842,6 +1122,8 @@ func (s *TransactionService) ModifyTransaction(c core.Context, transaction *mode
 		}
 	}
 
+	var unreferencedPictureObjects []*models.TransactionPictureObject
+
 	err := s.UserDataDB(transaction.Uid).DoTransaction(c, func(sess *xorm.Session) error {
 		// Get and verify current transaction
 		oldTransaction := &models.Transaction{}
@@ -879,6 +1161,16 @@ func (s *TransactionService) ModifyTransaction(c core.Context, transaction *mode
 			return errs.ErrCannotModifyTransactionInHiddenAccount
 		}
 
+		if destinationAccount != nil && destinationAccount.LedgerId != sourceAccount.LedgerId {
+			return errs.ErrCannotUseDataInDifferentLedger
+		}
+
+		if sourceAccount.LedgerId != oldTransaction.LedgerId {
+			return errs.ErrCannotMoveTransactionToOtherLedger
+		}
+
+		transaction.LedgerId = oldTransaction.LedgerId
+
 		if sourceAccount.Type == models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS || (destinationAccount != nil && destinationAccount.Type == models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS) {
 			return errs.ErrCannotModifyTransactionInParentAccount
 		}
@@ -916,6 +1208,19 @@ func (s *TransactionService) ModifyTransaction(c core.Context, transaction *mode
 			updateCols = append(updateCols, "category_id")
 		}
 
+		if transaction.EventId != oldTransaction.EventId {
+			// Get and verify event
+			if transaction.EventId > 0 {
+				err = s.isEventValid(sess, transaction)
+
+				if err != nil {
+					return err
+				}
+			}
+
+			updateCols = append(updateCols, "event_id")
+		}
+
 		modifyTransactionTime := false
 
 		if utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime) != utils.GetUnixTimeFromTransactionTime(oldTransaction.TransactionTime) {
@@ -1048,6 +1353,16 @@ func (s *TransactionService) ModifyTransaction(c core.Context, transaction *mode
 			}
 		}
 
+		// Update contact transaction
+		if transaction.Amount != oldTransaction.Amount || transaction.RelatedAccountAmount != oldTransaction.RelatedAccountAmount || modifyTransactionTime ||
+			transaction.AccountId != oldTransaction.AccountId || transaction.RelatedAccountId != oldTransaction.RelatedAccountId {
+			err = s.updateContactTransactionOfTransaction(c, sess, transaction, now)
+
+			if err != nil {
+				return err
+			}
+		}
+
 		// Update transaction tag index
 		if len(removeTagIds) > 0 {
 			tagIndexUpdateModel := &models.TransactionTagIndex{
@@ -1097,6 +1412,14 @@ func (s *TransactionService) ModifyTransaction(c core.Context, transaction *mode
 				DeletedUnixTime: now,
 			}
 
+			var removePictureInfos []*models.TransactionPictureInfo
+			err := sess.Where("uid=? AND deleted=? AND transaction_id=?", transaction.Uid, false, transaction.TransactionId).In("picture_id", removePictureIds).Find(&removePictureInfos)
+
+			if err != nil {
+				log.Errorf(c, "[transactions.ModifyTransaction] failed to get old transaction picture info, because %s", err.Error())
+				return err
+			}
+
 			deletedRows, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND transaction_id=?", transaction.Uid, false, transaction.TransactionId).In("picture_id", removePictureIds).Update(pictureUpdateModel)
 
 			if err != nil {
@@ -1105,6 +1428,20 @@ func (s *TransactionService) ModifyTransaction(c core.Context, transaction *mode
 			} else if deletedRows < 1 {
 				return errs.ErrTransactionPictureNotFound
 			}
+
+			unreferencedPictureObjects, err = decreaseTransactionPictureObjectReferences(sess, transaction.Uid, removePictureInfos, now)
+
+			if err != nil {
+				log.Errorf(c, "[transactions.ModifyTransaction] failed to update transaction picture object references, because %s", err.Error())
+				return err
+			}
+
+			err = deleteTransactionPictureObjects(sess, transaction.Uid, unreferencedPictureObjects)
+
+			if err != nil {
+				log.Errorf(c, "[transactions.ModifyTransaction] failed to delete unreferenced transaction picture objects, because %s", err.Error())
+				return err
+			}
 		}
 
 		if len(addPictureIds) > 0 {
@@ -1121,6 +1458,26 @@ func (s *TransactionService) ModifyTransaction(c core.Context, transaction *mode
 			}
 		}
 
+		// Update transaction attachments
+		if len(addAttachmentIds) > 0 || len(removeAttachmentIds) > 0 {
+			err = updateTransactionAttachments(sess, transaction.Uid, transaction.TransactionId, addAttachmentIds, removeAttachmentIds, now)
+
+			if err != nil {
+				log.Errorf(c, "[transactions.ModifyTransaction] failed to update transaction attachments, because %s", err.Error())
+				return err
+			}
+		}
+
+		// Update transaction custom field values
+		if customFieldValues != nil {
+			err = replaceTransactionCustomFieldValues(sess, transaction.Uid, transaction.TransactionId, customFieldValues, now)
+
+			if err != nil {
+				log.Errorf(c, "[transactions.ModifyTransaction] failed to update transaction custom field values, because %s", err.Error())
+				return err
+			}
+		}
+
 		// Update account table
 		if oldTransaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
 			if transaction.AccountId != oldTransaction.AccountId {
@@ -1290,6 +1647,8 @@ func (s *TransactionService) ModifyTransaction(c core.Context, transaction *mode
 		return err
 	}
 
+	TransactionPictures.deletePictureObjectFiles(c, transaction.Uid, unreferencedPictureObjects)
+
 	return nil
 }
 
@@ -1307,203 +1666,303 @@ func (s *TransactionService) MoveAllTransactionsBetweenAccounts(c core.Context,
 	}
 
 	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
-		// get and verify from and to account
-		fromAccount := &models.Account{}
-		has, err := sess.ID(fromAccountId).Where("uid=? AND deleted=?", uid, false).Get(fromAccount)
-
-		if err != nil {
-			return err
-		} else if !has {
-			return errs.ErrAccountNotFound
-		}
+		return s.moveAllTransactionsBetweenAccounts(c, sess, uid, fromAccountId, toAccountId)
+	})
+}
 
-		toAccount := &models.Account{}
-		has, err = sess.ID(toAccountId).Where("uid=? AND deleted=?", uid, false).Get(toAccount)
+// MergeAccounts moves all transactions, transaction templates and contact transactions of the source account to the target account,
+// adds the balance of the source account to the target account, and then deletes the source account
+func (s *TransactionService) MergeAccounts(c core.Context, uid int64, fromAccountId int64, toAccountId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if fromAccountId <= 0 || toAccountId <= 0 {
+		return errs.ErrAccountIdInvalid
+	}
+
+	if fromAccountId == toAccountId {
+		return errs.ErrCannotMergeAccountIntoItself
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		err := s.moveAllTransactionsBetweenAccounts(c, sess, uid, fromAccountId, toAccountId)
 
 		if err != nil {
 			return err
-		} else if !has {
-			return errs.ErrAccountNotFound
 		}
 
-		if fromAccount.Hidden || toAccount.Hidden {
-			return errs.ErrCannotMoveTransactionFromOrToHiddenAccount
+		now := time.Now().Unix()
+
+		// update all transaction templates of from account
+		templateUpdateModel := &models.TransactionTemplate{
+			AccountId:       toAccountId,
+			UpdatedUnixTime: now,
 		}
 
-		if fromAccount.Type == models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS || toAccount.Type == models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS {
-			return errs.ErrCannotMoveTransactionFromOrToParentAccount
+		_, err = sess.Cols("account_id", "updated_unix_time").Where("uid=? AND deleted=? AND account_id=?", uid, false, fromAccountId).Update(templateUpdateModel)
+
+		if err != nil {
+			return err
 		}
 
-		if fromAccount.Currency != toAccount.Currency {
-			return errs.ErrCannotMoveTransactionBetweenAccountsWithDifferentCurrencies
+		templateRelatedUpdateModel := &models.TransactionTemplate{
+			RelatedAccountId: toAccountId,
+			UpdatedUnixTime:  now,
 		}
 
-		// combine balance modification transaction
-		var balanceModificationTransactions []*models.Transaction
-		err = sess.Where("uid=? AND deleted=? AND type=? AND (account_id=? OR account_id=?)", uid, false, models.TRANSACTION_DB_TYPE_MODIFY_BALANCE, fromAccountId, toAccountId).Find(&balanceModificationTransactions)
+		_, err = sess.Cols("related_account_id", "updated_unix_time").Where("uid=? AND deleted=? AND related_account_id=?", uid, false, fromAccountId).Update(templateRelatedUpdateModel)
 
 		if err != nil {
 			return err
 		}
 
-		if len(balanceModificationTransactions) > 2 {
-			log.Errorf(c, "[transactions.MoveAllTransactionsBetweenAccounts] user \"uid:%d\" has more than 2 balance modification transactions in account \"id:%d\" and account \"id:%d\", cannot combine balance modification transaction", uid, fromAccountId, toAccountId)
-			return errs.ErrOperationFailed
-		} else if len(balanceModificationTransactions) == 2 && balanceModificationTransactions[0].AccountId != balanceModificationTransactions[1].AccountId {
-			// if two balance modification transactions exist, merge the amounts into the earlier one and delete the later transaction
-			var earlierTransaction *models.Transaction
-			var laterTransaction *models.Transaction
-
-			if balanceModificationTransactions[0].TransactionTime < balanceModificationTransactions[1].TransactionTime {
-				earlierTransaction = balanceModificationTransactions[0]
-				laterTransaction = balanceModificationTransactions[1]
-			} else {
-				earlierTransaction = balanceModificationTransactions[1]
-				laterTransaction = balanceModificationTransactions[0]
-			}
+		// delete all transfer templates which related account id and account id are both
+		templateDeleteModel := &models.TransactionTemplate{
+			Deleted:         true,
+			DeletedUnixTime: now,
+		}
 
-			earlierTransaction.Amount += laterTransaction.Amount
-			earlierTransaction.RelatedAccountAmount += laterTransaction.RelatedAccountAmount
-			earlierTransaction.UpdatedUnixTime = time.Now().Unix()
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND type=? AND account_id=? AND related_account_id=?", uid, false, models.TRANSACTION_TYPE_TRANSFER, toAccountId, toAccountId).Update(templateDeleteModel)
 
-			updatedRows, err := sess.ID(earlierTransaction.TransactionId).Cols("amount", "related_account_amount", "updated_unix_time").Where("uid=? AND deleted=?", uid, false).Update(earlierTransaction)
+		if err != nil {
+			return err
+		}
 
-			if err != nil {
-				return err
-			} else if updatedRows < 1 {
-				log.Errorf(c, "[transactions.MoveAllTransactionsBetweenAccounts] failed to update earlier balance modification transaction")
-				return errs.ErrDatabaseOperationFailed
-			}
+		// update all contact transactions of from account
+		contactTransactionUpdateModel := &models.ContactTransaction{
+			AccountId:       toAccountId,
+			UpdatedUnixTime: now,
+		}
 
-			laterTransaction.Deleted = true
-			laterTransaction.DeletedUnixTime = time.Now().Unix()
+		_, err = sess.Cols("account_id", "updated_unix_time").Where("uid=? AND deleted=? AND account_id=?", uid, false, fromAccountId).Update(contactTransactionUpdateModel)
 
-			deletedRows, err := sess.ID(laterTransaction.TransactionId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(laterTransaction)
+		if err != nil {
+			return err
+		}
 
-			if err != nil {
-				return err
-			} else if deletedRows < 1 {
-				log.Errorf(c, "[transactions.MoveAllTransactionsBetweenAccounts] failed to delete later balance modification transaction")
-				return errs.ErrDatabaseOperationFailed
-			}
+		// delete from account
+		accountDeleteModel := &models.Account{
+			Balance:         0,
+			Deleted:         true,
+			DeletedUnixTime: now,
+		}
 
-			log.Infof(c, "[transactions.MoveAllTransactionsBetweenAccounts] user \"uid:%d\" has combined two balance modification transactions \"id:%d\" and \"id:%d\", retained transaction is \"id:%d\"", uid, earlierTransaction.TransactionId, laterTransaction.TransactionId, earlierTransaction.TransactionId)
-		} else if len(balanceModificationTransactions) == 1 {
-			// when merging a new balance modification transaction, if its date is later than the account's earliest transaction, update the balance modification transaction time accordingly
-			anotherAccountId := int64(0)
+		deletedRows, err := sess.ID(fromAccountId).Cols("balance", "deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(accountDeleteModel)
 
-			if balanceModificationTransactions[0].AccountId == fromAccountId {
-				anotherAccountId = toAccountId
-			} else if balanceModificationTransactions[0].AccountId == toAccountId {
-				anotherAccountId = fromAccountId
-			} else {
-				log.Errorf(c, "[transactions.MoveAllTransactionsBetweenAccounts] user \"uid:%d\" has a balance modification transaction \"id:%d\" which account id is neither \"%d\" nor \"%d\"", uid, balanceModificationTransactions[0].TransactionId, fromAccountId, toAccountId)
-				return errs.ErrOperationFailed
-			}
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrAccountNotFound
+		}
 
-			earliestTransaction := &models.Transaction{}
-			has, err := sess.Where("uid=? AND deleted=? AND account_id=?", uid, false, anotherAccountId).OrderBy("transaction_time asc").Limit(1).Get(earliestTransaction)
+		log.Infof(c, "[transactions.MergeAccounts] user \"uid:%d\" has merged account \"id:%d\" into account \"id:%d\"", uid, fromAccountId, toAccountId)
 
-			if err != nil {
-				return err
-			} else if has && balanceModificationTransactions[0].TransactionTime > earliestTransaction.TransactionTime {
-				balanceModificationTransaction := balanceModificationTransactions[0]
-				balanceModificationTransaction.TransactionTime = utils.GetMinTransactionTimeFromUnixTime(utils.GetUnixTimeFromTransactionTime(earliestTransaction.TransactionTime) - 1)
-				balanceModificationTransaction.UpdatedUnixTime = time.Now().Unix()
+		return nil
+	})
+}
 
-				if balanceModificationTransaction.TransactionTime < 0 {
-					balanceModificationTransaction.TransactionTime = 0
-				}
+func (s *TransactionService) moveAllTransactionsBetweenAccounts(c core.Context, sess *xorm.Session, uid int64, fromAccountId int64, toAccountId int64) error {
+	// get and verify from and to account
+	fromAccount := &models.Account{}
+	has, err := sess.ID(fromAccountId).Where("uid=? AND deleted=?", uid, false).Get(fromAccount)
 
-				updatedRows, err := sess.ID(balanceModificationTransaction.TransactionId).Cols("transaction_time", "updated_unix_time").Where("uid=? AND deleted=?", uid, false).Update(balanceModificationTransaction)
+	if err != nil {
+		return err
+	} else if !has {
+		return errs.ErrAccountNotFound
+	}
 
-				if err != nil {
-					return err
-				} else if updatedRows < 1 {
-					log.Errorf(c, "[transactions.MoveAllTransactionsBetweenAccounts] failed to update balance modification transaction time")
-					return errs.ErrDatabaseOperationFailed
-				}
+	toAccount := &models.Account{}
+	has, err = sess.ID(toAccountId).Where("uid=? AND deleted=?", uid, false).Get(toAccount)
 
-				log.Infof(c, "[transactions.MoveAllTransactionsBetweenAccounts] user \"uid:%d\" has updated balance modification transaction \"id:%d\" time to %d, because earliest transaction time in account \"id:%d\" is %d", uid, balanceModificationTransaction.TransactionId, balanceModificationTransaction.TransactionTime, toAccountId, earliestTransaction.TransactionTime)
-			}
-		}
+	if err != nil {
+		return err
+	} else if !has {
+		return errs.ErrAccountNotFound
+	}
 
-		// update all transactions of from account
-		updateModel := &models.Transaction{
-			AccountId:       toAccountId,
-			UpdatedUnixTime: time.Now().Unix(),
+	if fromAccount.LedgerId != toAccount.LedgerId {
+		return errs.ErrCannotUseDataInDifferentLedger
+	}
+
+	if fromAccount.Hidden || toAccount.Hidden {
+		return errs.ErrCannotMoveTransactionFromOrToHiddenAccount
+	}
+
+	if fromAccount.Type == models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS || toAccount.Type == models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS {
+		return errs.ErrCannotMoveTransactionFromOrToParentAccount
+	}
+
+	if fromAccount.Currency != toAccount.Currency {
+		return errs.ErrCannotMoveTransactionBetweenAccountsWithDifferentCurrencies
+	}
+
+	// combine balance modification transaction
+	var balanceModificationTransactions []*models.Transaction
+	err = sess.Where("uid=? AND deleted=? AND type=? AND (account_id=? OR account_id=?)", uid, false, models.TRANSACTION_DB_TYPE_MODIFY_BALANCE, fromAccountId, toAccountId).Find(&balanceModificationTransactions)
+
+	if err != nil {
+		return err
+	}
+
+	if len(balanceModificationTransactions) > 2 {
+		log.Errorf(c, "[transactions.MoveAllTransactionsBetweenAccounts] user \"uid:%d\" has more than 2 balance modification transactions in account \"id:%d\" and account \"id:%d\", cannot combine balance modification transaction", uid, fromAccountId, toAccountId)
+		return errs.ErrOperationFailed
+	} else if len(balanceModificationTransactions) == 2 && balanceModificationTransactions[0].AccountId != balanceModificationTransactions[1].AccountId {
+		// if two balance modification transactions exist, merge the amounts into the earlier one and delete the later transaction
+		var earlierTransaction *models.Transaction
+		var laterTransaction *models.Transaction
+
+		if balanceModificationTransactions[0].TransactionTime < balanceModificationTransactions[1].TransactionTime {
+			earlierTransaction = balanceModificationTransactions[0]
+			laterTransaction = balanceModificationTransactions[1]
+		} else {
+			earlierTransaction = balanceModificationTransactions[1]
+			laterTransaction = balanceModificationTransactions[0]
 		}
 
-		updatedRows, err := sess.Cols("account_id", "updated_unix_time").Where("uid=? AND deleted=? AND account_id=?", uid, false, fromAccountId).Update(updateModel)
+		earlierTransaction.Amount += laterTransaction.Amount
+		earlierTransaction.RelatedAccountAmount += laterTransaction.RelatedAccountAmount
+		earlierTransaction.UpdatedUnixTime = time.Now().Unix()
+
+		updatedRows, err := sess.ID(earlierTransaction.TransactionId).Cols("amount", "related_account_amount", "updated_unix_time").Where("uid=? AND deleted=?", uid, false).Update(earlierTransaction)
 
 		if err != nil {
 			return err
+		} else if updatedRows < 1 {
+			log.Errorf(c, "[transactions.MoveAllTransactionsBetweenAccounts] failed to update earlier balance modification transaction")
+			return errs.ErrDatabaseOperationFailed
 		}
 
-		if updatedRows > 0 {
-			log.Infof(c, "[transactions.MoveAllTransactionsBetweenAccounts] user \"uid:%d\" has moved %d transactions from account \"id:%d\" to account \"id:%d\"", uid, updatedRows, fromAccountId, toAccountId)
-		}
-
-		// update all related transactions of from account
-		updateRelatedModel := &models.Transaction{
-			RelatedAccountId: toAccountId,
-			UpdatedUnixTime:  time.Now().Unix(),
-		}
+		laterTransaction.Deleted = true
+		laterTransaction.DeletedUnixTime = time.Now().Unix()
 
-		relatedUpdatedRows, err := sess.Cols("related_account_id", "updated_unix_time").Where("uid=? AND deleted=? AND related_account_id=?", uid, false, fromAccountId).Update(updateRelatedModel)
+		deletedRows, err := sess.ID(laterTransaction.TransactionId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(laterTransaction)
 
 		if err != nil {
 			return err
+		} else if deletedRows < 1 {
+			log.Errorf(c, "[transactions.MoveAllTransactionsBetweenAccounts] failed to delete later balance modification transaction")
+			return errs.ErrDatabaseOperationFailed
 		}
 
-		if updatedRows > 0 {
-			log.Infof(c, "[transactions.MoveAllTransactionsBetweenAccounts] user \"uid:%d\" has moved %d related transactions from account \"id:%d\" to account \"id:%d\"", uid, relatedUpdatedRows, fromAccountId, toAccountId)
-		}
+		log.Infof(c, "[transactions.MoveAllTransactionsBetweenAccounts] user \"uid:%d\" has combined two balance modification transactions \"id:%d\" and \"id:%d\", retained transaction is \"id:%d\"", uid, earlierTransaction.TransactionId, laterTransaction.TransactionId, earlierTransaction.TransactionId)
+	} else if len(balanceModificationTransactions) == 1 {
+		// when merging a new balance modification transaction, if its date is later than the account's earliest transaction, update the balance modification transaction time accordingly
+		anotherAccountId := int64(0)
 
-		// delete all transfer transactions which related account id and account id are both
-		deletedModel := &models.Transaction{
-			Deleted:         true,
-			DeletedUnixTime: time.Now().Unix(),
+		if balanceModificationTransactions[0].AccountId == fromAccountId {
+			anotherAccountId = toAccountId
+		} else if balanceModificationTransactions[0].AccountId == toAccountId {
+			anotherAccountId = fromAccountId
+		} else {
+			log.Errorf(c, "[transactions.MoveAllTransactionsBetweenAccounts] user \"uid:%d\" has a balance modification transaction \"id:%d\" which account id is neither \"%d\" nor \"%d\"", uid, balanceModificationTransactions[0].TransactionId, fromAccountId, toAccountId)
+			return errs.ErrOperationFailed
 		}
 
-		deletedRows, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND (type=? OR type=?) AND account_id=? AND related_account_id=?", uid, false, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, models.TRANSACTION_DB_TYPE_TRANSFER_IN, toAccountId, toAccountId).Update(deletedModel)
+		earliestTransaction := &models.Transaction{}
+		has, err := sess.Where("uid=? AND deleted=? AND account_id=?", uid, false, anotherAccountId).OrderBy("transaction_time asc").Limit(1).Get(earliestTransaction)
 
 		if err != nil {
 			return err
-		}
+		} else if has && balanceModificationTransactions[0].TransactionTime > earliestTransaction.TransactionTime {
+			balanceModificationTransaction := balanceModificationTransactions[0]
+			balanceModificationTransaction.TransactionTime = utils.GetMinTransactionTimeFromUnixTime(utils.GetUnixTimeFromTransactionTime(earliestTransaction.TransactionTime) - 1)
+			balanceModificationTransaction.UpdatedUnixTime = time.Now().Unix()
 
-		if deletedRows > 0 {
-			log.Infof(c, "[transactions.MoveAllTransactionsBetweenAccounts] user \"uid:%d\" has deleted %d transactions which account id and related account id are both \"%d\"", uid, deletedRows, toAccountId)
-		}
+			if balanceModificationTransaction.TransactionTime < 0 {
+				balanceModificationTransaction.TransactionTime = 0
+			}
 
-		// update account balance
-		if fromAccount.Balance != 0 {
-			toAccount.UpdatedUnixTime = time.Now().Unix()
-			updatedRows, err := sess.ID(toAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)", fromAccount.Balance)).Cols("updated_unix_time").Where("uid=? AND deleted=?", uid, false).Update(toAccount)
+			updatedRows, err := sess.ID(balanceModificationTransaction.TransactionId).Cols("transaction_time", "updated_unix_time").Where("uid=? AND deleted=?", uid, false).Update(balanceModificationTransaction)
 
 			if err != nil {
 				return err
 			} else if updatedRows < 1 {
-				log.Errorf(c, "[transactions.MoveAllTransactionsBetweenAccounts] failed to update to account balance")
+				log.Errorf(c, "[transactions.MoveAllTransactionsBetweenAccounts] failed to update balance modification transaction time")
 				return errs.ErrDatabaseOperationFailed
 			}
 
-			log.Infof(c, "[transactions.MoveAllTransactionsBetweenAccounts] user \"uid:%d\" has updated account \"id:%d\" balance from %d to %d", uid, toAccountId, toAccount.Balance, toAccount.Balance+fromAccount.Balance)
+			log.Infof(c, "[transactions.MoveAllTransactionsBetweenAccounts] user \"uid:%d\" has updated balance modification transaction \"id:%d\" time to %d, because earliest transaction time in account \"id:%d\" is %d", uid, balanceModificationTransaction.TransactionId, balanceModificationTransaction.TransactionTime, toAccountId, earliestTransaction.TransactionTime)
+		}
+	}
+
+	// update all transactions of from account
+	updateModel := &models.Transaction{
+		AccountId:       toAccountId,
+		UpdatedUnixTime: time.Now().Unix(),
+	}
 
-			fromAccount.Balance = 0
-			fromAccount.UpdatedUnixTime = time.Now().Unix()
-			updatedRows, err = sess.ID(fromAccount.AccountId).Cols("balance", "updated_unix_time").Where("uid=? AND deleted=?", fromAccount.Uid, false).Update(fromAccount)
+	updatedRows, err := sess.Cols("account_id", "updated_unix_time").Where("uid=? AND deleted=? AND account_id=?", uid, false, fromAccountId).Update(updateModel)
 
-			if err != nil {
-				return err
-			} else if updatedRows < 1 {
-				log.Errorf(c, "[transactions.MoveAllTransactionsBetweenAccounts] failed to update from account balance")
-				return errs.ErrDatabaseOperationFailed
-			}
+	if err != nil {
+		return err
+	}
+
+	if updatedRows > 0 {
+		log.Infof(c, "[transactions.MoveAllTransactionsBetweenAccounts] user \"uid:%d\" has moved %d transactions from account \"id:%d\" to account \"id:%d\"", uid, updatedRows, fromAccountId, toAccountId)
+	}
+
+	// update all related transactions of from account
+	updateRelatedModel := &models.Transaction{
+		RelatedAccountId: toAccountId,
+		UpdatedUnixTime:  time.Now().Unix(),
+	}
+
+	relatedUpdatedRows, err := sess.Cols("related_account_id", "updated_unix_time").Where("uid=? AND deleted=? AND related_account_id=?", uid, false, fromAccountId).Update(updateRelatedModel)
+
+	if err != nil {
+		return err
+	}
+
+	if updatedRows > 0 {
+		log.Infof(c, "[transactions.MoveAllTransactionsBetweenAccounts] user \"uid:%d\" has moved %d related transactions from account \"id:%d\" to account \"id:%d\"", uid, relatedUpdatedRows, fromAccountId, toAccountId)
+	}
+
+	// delete all transfer transactions which related account id and account id are both
+	deletedModel := &models.Transaction{
+		Deleted:         true,
+		DeletedUnixTime: time.Now().Unix(),
+	}
+
+	deletedRows, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND (type=? OR type=?) AND account_id=? AND related_account_id=?", uid, false, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, models.TRANSACTION_DB_TYPE_TRANSFER_IN, toAccountId, toAccountId).Update(deletedModel)
+
+	if err != nil {
+		return err
+	}
+
+	if deletedRows > 0 {
+		log.Infof(c, "[transactions.MoveAllTransactionsBetweenAccounts] user \"uid:%d\" has deleted %d transactions which account id and related account id are both \"%d\"", uid, deletedRows, toAccountId)
+	}
+
+	// update account balance
+	if fromAccount.Balance != 0 {
+		toAccount.UpdatedUnixTime = time.Now().Unix()
+		updatedRows, err := sess.ID(toAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)", fromAccount.Balance)).Cols("updated_unix_time").Where("uid=? AND deleted=?", uid, false).Update(toAccount)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			log.Errorf(c, "[transactions.MoveAllTransactionsBetweenAccounts] failed to update to account balance")
+			return errs.ErrDatabaseOperationFailed
 		}
 
-		return nil
-	})
+		log.Infof(c, "[transactions.MoveAllTransactionsBetweenAccounts] user \"uid:%d\" has updated account \"id:%d\" balance from %d to %d", uid, toAccountId, toAccount.Balance, toAccount.Balance+fromAccount.Balance)
+
+		fromAccount.Balance = 0
+		fromAccount.UpdatedUnixTime = time.Now().Unix()
+		updatedRows, err = sess.ID(fromAccount.AccountId).Cols("balance", "updated_unix_time").Where("uid=? AND deleted=?", fromAccount.Uid, false).Update(fromAccount)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			log.Errorf(c, "[transactions.MoveAllTransactionsBetweenAccounts] failed to update from account balance")
+			return errs.ErrDatabaseOperationFailed
+		}
+	}
+
+	return nil
 }
 
 // DeleteTransaction deletes an existed transaction from database
@@ -1529,7 +1988,24 @@ func (s *TransactionService) DeleteTransaction(c core.Context, uid int64, transa
 		DeletedUnixTime: now,
 	}
 
-	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+	contactTransactionUpdateModel := &models.ContactTransaction{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	customFieldValueUpdateModel := &models.TransactionCustomFieldValue{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	attachmentUpdateModel := &models.TransactionAttachment{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	var unreferencedPictureObjects []*models.TransactionPictureObject
+
+	err := s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
 		// Get and verify current transaction
 		oldTransaction := &models.Transaction{}
 		has, err := sess.ID(transactionId).Where("uid=? AND deleted=?", uid, false).Get(oldTransaction)
@@ -1582,12 +2058,52 @@ func (s *TransactionService) DeleteTransaction(c core.Context, uid int64, transa
 		}
 
 		// Update transaction picture
+		var pictureInfos []*models.TransactionPictureInfo
+		err = sess.Where("uid=? AND deleted=? AND transaction_id=?", uid, false, oldTransaction.TransactionId).Find(&pictureInfos)
+
+		if err != nil {
+			return err
+		}
+
 		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND transaction_id=?", uid, false, oldTransaction.TransactionId).Update(pictureUpdateModel)
 
 		if err != nil {
 			return err
 		}
 
+		unreferencedPictureObjects, err = decreaseTransactionPictureObjectReferences(sess, uid, pictureInfos, now)
+
+		if err != nil {
+			return err
+		}
+
+		err = deleteTransactionPictureObjects(sess, uid, unreferencedPictureObjects)
+
+		if err != nil {
+			return err
+		}
+
+		// Update transaction custom field values
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND transaction_id=?", uid, false, oldTransaction.TransactionId).Update(customFieldValueUpdateModel)
+
+		if err != nil {
+			return err
+		}
+
+		// Update transaction attachments
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND transaction_id=?", uid, false, oldTransaction.TransactionId).Update(attachmentUpdateModel)
+
+		if err != nil {
+			return err
+		}
+
+		// Update contact transaction
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).In("transaction_id", oldTransaction.TransactionId, oldTransaction.RelatedId).Update(contactTransactionUpdateModel)
+
+		if err != nil {
+			return err
+		}
+
 		// Update account table
 		if oldTransaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
 			if oldTransaction.RelatedAccountAmount != 0 {
@@ -1655,6 +2171,14 @@ func (s *TransactionService) DeleteTransaction(c core.Context, uid int64, transa
 
 		return err
 	})
+
+	if err != nil {
+		return err
+	}
+
+	TransactionPictures.deletePictureObjectFiles(c, uid, unreferencedPictureObjects)
+
+	return nil
 }
 
 // DeleteAllTransactions deletes all existed transactions from database
@@ -1680,13 +2204,30 @@ func (s *TransactionService) DeleteAllTransactions(c core.Context, uid int64, de
 		DeletedUnixTime: now,
 	}
 
+	contactTransactionUpdateModel := &models.ContactTransaction{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	customFieldValueUpdateModel := &models.TransactionCustomFieldValue{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	attachmentUpdateModel := &models.TransactionAttachment{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
 	accountUpdateModel := &models.Account{
 		Balance:         0,
 		Deleted:         deleteAccount,
 		DeletedUnixTime: now,
 	}
 
-	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+	var unreferencedPictureObjects []*models.TransactionPictureObject
+
+	err := s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
 		// Update all transactions to deleted
 		_, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
 
@@ -1702,12 +2243,52 @@ func (s *TransactionService) DeleteAllTransactions(c core.Context, uid int64, de
 		}
 
 		// Update all transaction pictures to deleted
+		var pictureInfos []*models.TransactionPictureInfo
+		err = sess.Where("uid=? AND deleted=?", uid, false).Find(&pictureInfos)
+
+		if err != nil {
+			return err
+		}
+
 		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(pictureUpdateModel)
 
 		if err != nil {
 			return err
 		}
 
+		unreferencedPictureObjects, err = decreaseTransactionPictureObjectReferences(sess, uid, pictureInfos, now)
+
+		if err != nil {
+			return err
+		}
+
+		err = deleteTransactionPictureObjects(sess, uid, unreferencedPictureObjects)
+
+		if err != nil {
+			return err
+		}
+
+		// Update all transaction custom field values to deleted
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(customFieldValueUpdateModel)
+
+		if err != nil {
+			return err
+		}
+
+		// Update all transaction attachments to deleted
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(attachmentUpdateModel)
+
+		if err != nil {
+			return err
+		}
+
+		// Update all contact transactions to deleted
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(contactTransactionUpdateModel)
+
+		if err != nil {
+			return err
+		}
+
 		// Update all accounts to deleted or set amount to zero
 		_, err = sess.Cols("balance", "deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(accountUpdateModel)
 
@@ -1717,6 +2298,14 @@ func (s *TransactionService) DeleteAllTransactions(c core.Context, uid int64, de
 
 		return nil
 	})
+
+	if err != nil {
+		return err
+	}
+
+	TransactionPictures.deletePictureObjectFiles(c, uid, unreferencedPictureObjects)
+
+	return nil
 }
 
 // DeleteAllTransactionsOfAccount deletes all existed transactions of specific account from database
@@ -1729,7 +2318,7 @@ func (s *TransactionService) DeleteAllTransactionsOfAccount(c core.Context, uid
 		return errs.ErrAccountIdInvalid
 	}
 
-	transactions, err := s.GetAllSpecifiedTransactions(c, uid, 0, 0, 0, nil, []int64{accountId}, nil, false, "", "", pageCount, true)
+	transactions, err := s.getAllSpecifiedTransactions(c, uid, 0, 0, 0, 0, nil, []int64{accountId}, nil, false, nil, "", "", pageCount, true)
 
 	if err != nil {
 		return err
@@ -1775,6 +2364,7 @@ func (s *TransactionService) GetRelatedTransferTransaction(originalTransaction *
 		TransactionId:        originalTransaction.RelatedId,
 		Uid:                  originalTransaction.Uid,
 		Deleted:              originalTransaction.Deleted,
+		LedgerId:             originalTransaction.LedgerId,
 		Type:                 relatedType,
 		CategoryId:           originalTransaction.CategoryId,
 		TransactionTime:      relatedTransactionTime,
@@ -1788,6 +2378,8 @@ func (s *TransactionService) GetRelatedTransferTransaction(originalTransaction *
 		GeoLongitude:         originalTransaction.GeoLongitude,
 		GeoLatitude:          originalTransaction.GeoLatitude,
 		CreatedIp:            originalTransaction.CreatedIp,
+		CreatorUid:           originalTransaction.CreatorUid,
+		EventId:              originalTransaction.EventId,
 		CreatedUnixTime:      originalTransaction.CreatedUnixTime,
 		UpdatedUnixTime:      originalTransaction.UpdatedUnixTime,
 		DeletedUnixTime:      originalTransaction.DeletedUnixTime,
@@ -1796,10 +2388,134 @@ func (s *TransactionService) GetRelatedTransferTransaction(originalTransaction *
 	return relatedTransaction
 }
 
-// GetAccountsTotalIncomeAndExpense returns the every accounts total income and expense amount by specific date range
-func (s *TransactionService) GetAccountsTotalIncomeAndExpense(c core.Context, uid int64, startUnixTime int64, endUnixTime int64, excludeAccountIds []int64, excludeCategoryIds []int64, clientTimezone *time.Location, useTransactionTimezone bool) (map[int64]int64, map[int64]int64, error) {
+// GetAllTransactionsByEventId returns all transactions assigned to the given event, the transfer-in transactions are not included
+func (s *TransactionService) GetAllTransactionsByEventId(c core.Context, uid int64, eventId int64) ([]*models.Transaction, error) {
 	if uid <= 0 {
-		return nil, nil, errs.ErrUserIdInvalid
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if eventId <= 0 {
+		return nil, errs.ErrEventIdInvalid
+	}
+
+	condition := "uid=? AND deleted=? AND event_id=? AND type<>? AND transaction_time<=?"
+	maxTransactionTime := int64(math.MaxInt64)
+
+	var allTransactions []*models.Transaction
+
+	for maxTransactionTime > 0 {
+		var transactions []*models.Transaction
+		err := s.UserDataDB(uid).NewSession(c).Select("transaction_id, type, category_id, account_id, transaction_time, amount, related_account_id, related_account_amount, creator_uid, event_id").Where(condition, uid, false, eventId, models.TRANSACTION_DB_TYPE_TRANSFER_IN, maxTransactionTime).Limit(pageCountForLoadTransactionAmounts, 0).OrderBy("transaction_time desc").Find(&transactions)
+
+		if err != nil {
+			return nil, err
+		}
+
+		allTransactions = append(allTransactions, transactions...)
+
+		if len(transactions) < pageCountForLoadTransactionAmounts {
+			maxTransactionTime = 0
+			break
+		}
+
+		maxTransactionTime = transactions[len(transactions)-1].TransactionTime - 1
+	}
+
+	return allTransactions, nil
+}
+
+// GetLedgerMembersTotalIncomeAndExpense returns the total income and expense amounts by account of each transaction creator in the ledger
+func (s *TransactionService) GetLedgerMembersTotalIncomeAndExpense(c core.Context, access *models.LedgerAccess, startUnixTime int64, endUnixTime int64) (map[int64]*models.LedgerMemberTransactionTotalAmount, error) {
+	uid, ledgerId, err := LedgerAccesses.GetLedgerOwnerUid(c, access, models.LEDGER_PERMISSION_VIEW_TRANSACTIONS)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if ledgerId <= 0 {
+		return nil, errs.ErrLedgerIdInvalid
+	}
+
+	condition := "uid=? AND deleted=? AND ledger_id=? AND (type=? OR type=?)"
+	conditionParams := make([]any, 0, 7)
+	conditionParams = append(conditionParams, uid)
+	conditionParams = append(conditionParams, false)
+	conditionParams = append(conditionParams, ledgerId)
+	conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_INCOME)
+	conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_EXPENSE)
+
+	if startUnixTime > 0 {
+		condition = condition + " AND transaction_time>=?"
+		conditionParams = append(conditionParams, utils.GetMinTransactionTimeFromUnixTime(startUnixTime))
+	}
+
+	maxTransactionTime := int64(math.MaxInt64)
+
+	if endUnixTime > 0 {
+		maxTransactionTime = utils.GetMaxTransactionTimeFromUnixTime(endUnixTime)
+	}
+
+	condition = condition + " AND transaction_time<=?"
+	totalAmounts := make(map[int64]*models.LedgerMemberTransactionTotalAmount)
+
+	for maxTransactionTime > 0 {
+		var transactions []*models.Transaction
+
+		finalConditionParams := make([]any, 0, 7)
+		finalConditionParams = append(finalConditionParams, conditionParams...)
+		finalConditionParams = append(finalConditionParams, maxTransactionTime)
+
+		err := s.UserDataDB(uid).NewSession(c).Select("type, account_id, transaction_time, amount, creator_uid").Where(condition, finalConditionParams...).Limit(pageCountForLoadTransactionAmounts, 0).OrderBy("transaction_time desc").Find(&transactions)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for i := 0; i < len(transactions); i++ {
+			transaction := transactions[i]
+			creatorUid := transaction.CreatorUid
+
+			if creatorUid <= 0 {
+				creatorUid = uid
+			}
+
+			totalAmount, exists := totalAmounts[creatorUid]
+
+			if !exists {
+				totalAmount = &models.LedgerMemberTransactionTotalAmount{
+					CreatorUid:     creatorUid,
+					IncomeAmounts:  make(map[int64]int64),
+					ExpenseAmounts: make(map[int64]int64),
+				}
+				totalAmounts[creatorUid] = totalAmount
+			}
+
+			totalAmount.TransactionCount++
+
+			if transaction.Type == models.TRANSACTION_DB_TYPE_INCOME {
+				totalAmount.IncomeAmounts[transaction.AccountId] += transaction.Amount
+			} else if transaction.Type == models.TRANSACTION_DB_TYPE_EXPENSE {
+				totalAmount.ExpenseAmounts[transaction.AccountId] += transaction.Amount
+			}
+		}
+
+		if len(transactions) < pageCountForLoadTransactionAmounts {
+			maxTransactionTime = 0
+			break
+		}
+
+		maxTransactionTime = transactions[len(transactions)-1].TransactionTime - 1
+	}
+
+	return totalAmounts, nil
+}
+
+// GetAccountsTotalIncomeAndExpense returns the every accounts total income and expense amount by specific date range
+func (s *TransactionService) GetAccountsTotalIncomeAndExpense(c core.Context, access *models.LedgerAccess, startUnixTime int64, endUnixTime int64, excludeAccountIds []int64, excludeCategoryIds []int64, clientTimezone *time.Location, useTransactionTimezone bool) (map[int64]int64, map[int64]int64, error) {
+	uid, ledgerId, err := LedgerAccesses.GetLedgerOwnerUid(c, access, models.LEDGER_PERMISSION_VIEW_TRANSACTIONS)
+
+	if err != nil {
+		return nil, nil, err
 	}
 
 	startLocalDateTime := utils.FormatUnixTimeToNumericLocalDateTime(startUnixTime, clientTimezone)
@@ -1818,6 +2534,11 @@ func (s *TransactionService) GetAccountsTotalIncomeAndExpense(c core.Context, ui
 	conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_INCOME)
 	conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_EXPENSE)
 
+	if ledgerId > 0 {
+		condition = condition + " AND ledger_id=?"
+		conditionParams = append(conditionParams, ledgerId)
+	}
+
 	if len(excludeAccountIds) > 0 {
 		var accountIdsCondition strings.Builder
 		accountIdConditionParams := make([]any, 0, len(excludeAccountIds))
@@ -1921,9 +2642,11 @@ func (s *TransactionService) GetAccountsTotalIncomeAndExpense(c core.Context, ui
 }
 
 // GetAccountsAndCategoriesTotalInflowAndOutflow returns the every accounts and categories total inflows and outflows amount by specific date range
-func (s *TransactionService) GetAccountsAndCategoriesTotalInflowAndOutflow(c core.Context, uid int64, startUnixTime int64, endUnixTime int64, tagFilters []*models.TransactionTagFilter, noTags bool, keyword string, clientTimezone *time.Location, useTransactionTimezone bool) ([]*models.Transaction, error) {
-	if uid <= 0 {
-		return nil, errs.ErrUserIdInvalid
+func (s *TransactionService) GetAccountsAndCategoriesTotalInflowAndOutflow(c core.Context, access *models.LedgerAccess, startUnixTime int64, endUnixTime int64, tagFilters []*models.TransactionTagFilter, noTags bool, keyword string, clientTimezone *time.Location, useTransactionTimezone bool) ([]*models.Transaction, error) {
+	uid, ledgerId, err := LedgerAccesses.GetLedgerOwnerUid(c, access, models.LEDGER_PERMISSION_VIEW_TRANSACTIONS)
+
+	if err != nil {
+		return nil, err
 	}
 
 	var startLocalDateTime, endLocalDateTime, startTransactionTime, endTransactionTime int64
@@ -1949,6 +2672,11 @@ func (s *TransactionService) GetAccountsAndCategoriesTotalInflowAndOutflow(c cor
 	conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_TRANSFER_OUT)
 	conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_TRANSFER_IN)
 
+	if ledgerId > 0 {
+		condition = condition + " AND ledger_id=?"
+		conditionParams = append(conditionParams, ledgerId)
+	}
+
 	minTransactionTime := startTransactionTime
 	maxTransactionTime := endTransactionTime
 	var allTransactions []*models.Transaction
@@ -2043,13 +2771,14 @@ func (s *TransactionService) GetAccountsAndCategoriesTotalInflowAndOutflow(c cor
 }
 
 // GetAccountsAndCategoriesMonthlyInflowAndOutflow returns the every accounts monthly inflows and outflows amount by specific date range
-func (s *TransactionService) GetAccountsAndCategoriesMonthlyInflowAndOutflow(c core.Context, uid int64, startYear int32, startMonth int32, endYear int32, endMonth int32, tagFilters []*models.TransactionTagFilter, noTags bool, keyword string, clientTimezone *time.Location, useTransactionTimezone bool) (map[int32][]*models.Transaction, error) {
-	if uid <= 0 {
-		return nil, errs.ErrUserIdInvalid
+func (s *TransactionService) GetAccountsAndCategoriesMonthlyInflowAndOutflow(c core.Context, access *models.LedgerAccess, startYear int32, startMonth int32, endYear int32, endMonth int32, tagFilters []*models.TransactionTagFilter, noTags bool, keyword string, clientTimezone *time.Location, useTransactionTimezone bool) (map[int32][]*models.Transaction, error) {
+	uid, ledgerId, err := LedgerAccesses.GetLedgerOwnerUid(c, access, models.LEDGER_PERMISSION_VIEW_TRANSACTIONS)
+
+	if err != nil {
+		return nil, err
 	}
 
 	var startTransactionTime, endTransactionTime int64
-	var err error
 
 	if startYear > 0 && startMonth > 0 {
 		startTransactionTime, _, err = utils.GetTransactionTimeRangeByYearMonth(startYear, startMonth)
@@ -2076,6 +2805,11 @@ func (s *TransactionService) GetAccountsAndCategoriesMonthlyInflowAndOutflow(c c
 	conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_TRANSFER_OUT)
 	conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_TRANSFER_IN)
 
+	if ledgerId > 0 {
+		condition = condition + " AND ledger_id=?"
+		conditionParams = append(conditionParams, ledgerId)
+	}
+
 	minTransactionTime := startTransactionTime
 	maxTransactionTime := endTransactionTime
 	var allTransactions []*models.Transaction
@@ -2214,6 +2948,20 @@ func (s *TransactionService) doCreateTransaction(c core.Context, database *datas
 		return errs.ErrCannotAddTransactionToHiddenAccount
 	}
 
+	if destinationAccount != nil && destinationAccount.LedgerId != sourceAccount.LedgerId {
+		return errs.ErrCannotUseDataInDifferentLedger
+	}
+
+	if transaction.LedgerId > 0 && transaction.LedgerId != sourceAccount.LedgerId {
+		return errs.ErrCannotUseDataInDifferentLedger
+	}
+
+	transaction.LedgerId = sourceAccount.LedgerId
+
+	if transaction.CreatorUid <= 0 {
+		transaction.CreatorUid = transaction.Uid
+	}
+
 	if sourceAccount.Type == models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS || (destinationAccount != nil && destinationAccount.Type == models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS) {
 		return errs.ErrCannotAddTransactionToParentAccount
 	}
@@ -2235,6 +2983,17 @@ func (s *TransactionService) doCreateTransaction(c core.Context, database *datas
 		return err
 	}
 
+	// Get and verify event, or assign the transaction to the matched auto-assign event
+	if transaction.EventId > 0 {
+		err = s.isEventValid(sess, transaction)
+	} else {
+		err = s.autoAssignEvent(sess, transaction)
+	}
+
+	if err != nil {
+		return err
+	}
+
 	// Get and verify tags
 	err = s.isTagsValid(sess, transaction, transactionTagIndexes, tagIds)
 
@@ -2456,12 +3215,17 @@ func (s *TransactionService) doCreateTransaction(c core.Context, database *datas
 	return err
 }
 
-func (s *TransactionService) buildTransactionQueryCondition(uid int64, maxTransactionTime int64, minTransactionTime int64, transactionDbType models.TransactionDbType, categoryIds []int64, accountIds []int64, tagFilters []*models.TransactionTagFilter, amountFilter string, keyword string, noDuplicated bool) (string, []any) {
+func (s *TransactionService) buildTransactionQueryCondition(uid int64, ledgerId int64, maxTransactionTime int64, minTransactionTime int64, transactionDbType models.TransactionDbType, categoryIds []int64, accountIds []int64, tagFilters []*models.TransactionTagFilter, amountFilter string, keyword string, noDuplicated bool) (string, []any) {
 	condition := "uid=? AND deleted=?"
 	conditionParams := make([]any, 0, 16)
 	conditionParams = append(conditionParams, uid)
 	conditionParams = append(conditionParams, false)
 
+	if ledgerId > 0 {
+		condition = condition + " AND ledger_id=?"
+		conditionParams = append(conditionParams, ledgerId)
+	}
+
 	if maxTransactionTime > 0 {
 		condition = condition + " AND transaction_time<=?"
 		conditionParams = append(conditionParams, maxTransactionTime)
@@ -2645,6 +3409,11 @@ func (s *TransactionService) appendFilterTagIdsConditionToQuery(sess *xorm.Sessi
 			subQueryCondition = subQueryCondition.And(builder.Gte{"transaction_time": minTransactionTime})
 		}
 
+		if len(tagFilter.TagGroupIds) > 0 || len(tagFilter.SubtreeTagIds) > 0 {
+			s.appendFilterTagGroupsAndSubtreesConditionToQuery(sess, uid, subQueryCondition, tagFilter)
+			continue
+		}
+
 		subQueryCondition = subQueryCondition.And(builder.In("tag_id", tagFilter.TagIds))
 		subQuery := builder.Select("transaction_id").From("transaction_tag_index").Where(subQueryCondition)
 
@@ -2662,6 +3431,116 @@ func (s *TransactionService) appendFilterTagIdsConditionToQuery(sess *xorm.Sessi
 	return sess
 }
 
+// appendFilterTagGroupsAndSubtreesConditionToQuery appends the condition of tag filter which contains tag groups or tag subtrees,
+// for "has all" and "not has all" filter, every tag group or tag subtree is treated as one item which matches any tag in it
+func (s *TransactionService) appendFilterTagGroupsAndSubtreesConditionToQuery(sess *xorm.Session, uid int64, tagIndexCondition builder.Cond, tagFilter *models.TransactionTagFilter) {
+	tagGroupsTagIdsSubQuery := func(tagGroupIds []int64) *builder.Builder {
+		return builder.Select("tag_id").From("transaction_tag").Where(builder.And(builder.Eq{"uid": uid}, builder.Eq{"deleted": false}, builder.In("tag_group_id", tagGroupIds)))
+	}
+
+	subtreesTagIdsSubQuery := func(tagIds []int64) *builder.Builder {
+		return builder.Select("tag_id").From("transaction_tag").Where(builder.And(builder.Eq{"uid": uid}, builder.Eq{"deleted": false}, builder.Or(builder.In("tag_id", tagIds), builder.In("parent_tag_id", tagIds))))
+	}
+
+	hasTagsCondition := func(tagIdCondition builder.Cond, tagCount int) builder.Cond {
+		subQuery := builder.Select("transaction_id").From("transaction_tag_index").Where(builder.And(tagIndexCondition, tagIdCondition))
+
+		if tagCount > 1 {
+			subQuery = subQuery.GroupBy("transaction_id").Having(fmt.Sprintf("COUNT(DISTINCT tag_id) >= %d", tagCount))
+		}
+
+		return builder.Or(builder.In("transaction_id", subQuery), builder.In("related_id", subQuery))
+	}
+
+	if tagFilter.Type == models.TRANSACTION_TAG_FILTER_HAS_ANY || tagFilter.Type == models.TRANSACTION_TAG_FILTER_NOT_HAS_ANY {
+		tagIdCondition := builder.NewCond()
+
+		if len(tagFilter.TagIds) > 0 {
+			tagIdCondition = tagIdCondition.Or(builder.In("tag_id", tagFilter.TagIds))
+		}
+
+		if len(tagFilter.TagGroupIds) > 0 {
+			tagIdCondition = tagIdCondition.Or(builder.In("tag_id", tagGroupsTagIdsSubQuery(tagFilter.TagGroupIds)))
+		}
+
+		if len(tagFilter.SubtreeTagIds) > 0 {
+			tagIdCondition = tagIdCondition.Or(builder.In("tag_id", subtreesTagIdsSubQuery(tagFilter.SubtreeTagIds)))
+		}
+
+		if tagFilter.Type == models.TRANSACTION_TAG_FILTER_HAS_ANY {
+			sess.And(hasTagsCondition(tagIdCondition, 1))
+		} else {
+			sess.And(builder.Not{hasTagsCondition(tagIdCondition, 1)})
+		}
+
+		return
+	}
+
+	allConditions := make([]builder.Cond, 0, 1+len(tagFilter.TagGroupIds)+len(tagFilter.SubtreeTagIds))
+
+	if len(tagFilter.TagIds) > 0 {
+		allConditions = append(allConditions, hasTagsCondition(builder.In("tag_id", tagFilter.TagIds), len(tagFilter.TagIds)))
+	}
+
+	for i := 0; i < len(tagFilter.TagGroupIds); i++ {
+		allConditions = append(allConditions, hasTagsCondition(builder.In("tag_id", tagGroupsTagIdsSubQuery([]int64{tagFilter.TagGroupIds[i]})), 1))
+	}
+
+	for i := 0; i < len(tagFilter.SubtreeTagIds); i++ {
+		allConditions = append(allConditions, hasTagsCondition(builder.In("tag_id", subtreesTagIdsSubQuery([]int64{tagFilter.SubtreeTagIds[i]})), 1))
+	}
+
+	if tagFilter.Type == models.TRANSACTION_TAG_FILTER_HAS_ALL {
+		sess.And(builder.And(allConditions...))
+	} else if tagFilter.Type == models.TRANSACTION_TAG_FILTER_NOT_HAS_ALL {
+		sess.And(builder.Not{builder.And(allConditions...)})
+	}
+}
+
+func (s *TransactionService) appendFilterCustomFieldsConditionToQuery(sess *xorm.Session, uid int64, customFieldFilters []*models.TransactionCustomFieldFilter) *xorm.Session {
+	for i := 0; i < len(customFieldFilters); i++ {
+		customFieldFilter := customFieldFilters[i]
+		subQueryCondition := builder.And(builder.Eq{"uid": uid}, builder.Eq{"deleted": false}, builder.Eq{"field_id": customFieldFilter.FieldId})
+		notMatch := false
+
+		switch customFieldFilter.Operator {
+		case models.TRANSACTION_CUSTOM_FIELD_FILTER_HAS_VALUE:
+		case models.TRANSACTION_CUSTOM_FIELD_FILTER_HAS_NO_VALUE:
+			notMatch = true
+		case models.TRANSACTION_CUSTOM_FIELD_FILTER_CONTAINS:
+			subQueryCondition = subQueryCondition.And(builder.Like{"text_value", customFieldFilter.TextValue})
+		case models.TRANSACTION_CUSTOM_FIELD_FILTER_EQUAL, models.TRANSACTION_CUSTOM_FIELD_FILTER_NOT_EQUAL:
+			if customFieldFilter.IsNumeric {
+				subQueryCondition = subQueryCondition.And(builder.Eq{"number_value": customFieldFilter.NumberValue})
+			} else {
+				subQueryCondition = subQueryCondition.And(builder.Eq{"text_value": customFieldFilter.TextValue})
+			}
+
+			notMatch = customFieldFilter.Operator == models.TRANSACTION_CUSTOM_FIELD_FILTER_NOT_EQUAL
+		case models.TRANSACTION_CUSTOM_FIELD_FILTER_GREATER:
+			subQueryCondition = subQueryCondition.And(builder.Gt{"number_value": customFieldFilter.NumberValue})
+		case models.TRANSACTION_CUSTOM_FIELD_FILTER_GREATER_OR_EQUAL:
+			subQueryCondition = subQueryCondition.And(builder.Gte{"number_value": customFieldFilter.NumberValue})
+		case models.TRANSACTION_CUSTOM_FIELD_FILTER_LESS:
+			subQueryCondition = subQueryCondition.And(builder.Lt{"number_value": customFieldFilter.NumberValue})
+		case models.TRANSACTION_CUSTOM_FIELD_FILTER_LESS_OR_EQUAL:
+			subQueryCondition = subQueryCondition.And(builder.Lte{"number_value": customFieldFilter.NumberValue})
+		default:
+			continue
+		}
+
+		subQuery := builder.Select("transaction_id").From("transaction_custom_field_value").Where(subQueryCondition)
+
+		if notMatch {
+			sess.NotIn("transaction_id", subQuery).NotIn("related_id", subQuery)
+		} else {
+			sess.And(builder.Or(builder.In("transaction_id", subQuery), builder.In("related_id", subQuery)))
+		}
+	}
+
+	return sess
+}
+
 func (s *TransactionService) isAccountIdValid(transaction *models.Transaction) error {
 	if transaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
 		if transaction.RelatedAccountId != 0 && transaction.RelatedAccountId != transaction.AccountId {
@@ -2827,6 +3706,48 @@ func (s *TransactionService) getRelatedUpdateColumns(updateCols []string) []stri
 	return relatedUpdateCols
 }
 
+func (s *TransactionService) updateContactTransactionOfTransaction(c core.Context, sess *xorm.Session, transaction *models.Transaction, now int64) error {
+	contactTransaction := &models.ContactTransaction{}
+	has, err := sess.Where("uid=? AND deleted=?", transaction.Uid, false).In("transaction_id", transaction.TransactionId, transaction.RelatedId).Get(contactTransaction)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.updateContactTransactionOfTransaction] failed to get contact transaction, because %s", err.Error())
+		return err
+	} else if !has {
+		return nil
+	}
+
+	amount := int64(0)
+
+	if transaction.AccountId == contactTransaction.AccountId {
+		amount = transaction.Amount
+	} else if (transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN) && transaction.RelatedAccountId == contactTransaction.AccountId {
+		amount = transaction.RelatedAccountAmount
+	} else {
+		return errs.ErrCannotChangeContactTransactionAccount
+	}
+
+	if contactTransaction.Amount < 0 {
+		amount = -amount
+	}
+
+	contactTransaction.Amount = amount
+	contactTransaction.TransactionTime = transaction.TransactionTime
+	contactTransaction.UpdatedUnixTime = now
+
+	updatedRows, err := sess.ID(contactTransaction.TransactionId).Cols("amount", "transaction_time", "updated_unix_time").Where("uid=? AND deleted=?", transaction.Uid, false).Update(contactTransaction)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.updateContactTransactionOfTransaction] failed to update contact transaction, because %s", err.Error())
+		return err
+	} else if updatedRows < 1 {
+		log.Errorf(c, "[transactions.updateContactTransactionOfTransaction] failed to update contact transaction")
+		return errs.ErrDatabaseOperationFailed
+	}
+
+	return nil
+}
+
 func (s *TransactionService) isCategoryValid(sess *xorm.Session, transaction *models.Transaction) error {
 	if transaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
 		if transaction.CategoryId != 0 {
@@ -2846,6 +3767,10 @@ func (s *TransactionService) isCategoryValid(sess *xorm.Session, transaction *mo
 			return errs.ErrCannotUseHiddenTransactionCategory
 		}
 
+		if category.LedgerId != transaction.LedgerId {
+			return errs.ErrCannotUseDataInDifferentLedger
+		}
+
 		if category.ParentCategoryId == models.LevelOneTransactionCategoryParentId {
 			return errs.ErrCannotUsePrimaryCategoryForTransaction
 		}
@@ -2873,6 +3798,55 @@ func (s *TransactionService) isCategoryValid(sess *xorm.Session, transaction *mo
 	return nil
 }
 
+func (s *TransactionService) isEventValid(sess *xorm.Session, transaction *models.Transaction) error {
+	if transaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
+		return errs.ErrBalanceModificationTransactionCannotSetEvent
+	}
+
+	event := &models.Event{}
+	has, err := sess.ID(transaction.EventId).Where("uid=? AND deleted=?", transaction.Uid, false).Get(event)
+
+	if err != nil {
+		return err
+	} else if !has {
+		return errs.ErrEventNotFound
+	}
+
+	if event.Hidden {
+		return errs.ErrCannotUseHiddenEvent
+	}
+
+	if event.LedgerId != transaction.LedgerId {
+		return errs.ErrCannotUseDataInDifferentLedger
+	}
+
+	return nil
+}
+
+func (s *TransactionService) autoAssignEvent(sess *xorm.Session, transaction *models.Transaction) error {
+	if transaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
+		return nil
+	}
+
+	transactionUnixTime := utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime)
+
+	var events []*models.Event
+	err := sess.Where("uid=? AND deleted=? AND ledger_id=? AND hidden=? AND auto_assign=? AND start_time<=? AND end_time>=?", transaction.Uid, false, transaction.LedgerId, false, true, transactionUnixTime, transactionUnixTime).OrderBy("start_time desc, event_id asc").Find(&events)
+
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < len(events); i++ {
+		if events[i].IsTransactionMatched(transaction) {
+			transaction.EventId = events[i].EventId
+			return nil
+		}
+	}
+
+	return nil
+}
+
 func (s *TransactionService) isTagsValid(sess *xorm.Session, transaction *models.Transaction, transactionTagIndexes []*models.TransactionTagIndex, tagIds []int64) error {
 	if len(transactionTagIndexes) > 0 {
 		var tags []*models.TransactionTag
@@ -2889,6 +3863,10 @@ func (s *TransactionService) isTagsValid(sess *xorm.Session, transaction *models
 				return errs.ErrCannotUseHiddenTransactionTag
 			}
 
+			if tags[i].LedgerId != transaction.LedgerId {
+				return errs.ErrCannotUseDataInDifferentLedger
+			}
+
 			tagMap[tags[i].TagId] = tags[i]
 		}
 
@@ -0,0 +1,325 @@
+package services
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters"
+	"github.com/mayswind/ezbookkeeping/pkg/converters/converter"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/testutils"
+)
+
+func TestMergeAccounts_AccountsInDifferentLedgers(t *testing.T) {
+	c := testutils.InitializeTestDataStore(t)
+
+	testutils.InsertTestData(t, 1,
+		&models.Account{AccountId: 101, Uid: 1, LedgerId: 1001, Name: "Cash", Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Currency: "USD", Balance: 1000},
+		&models.Account{AccountId: 102, Uid: 1, LedgerId: 1002, Name: "Cash", Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Currency: "USD", Balance: 2000},
+		&models.Transaction{TransactionId: 501, Uid: 1, LedgerId: 1001, Type: models.TRANSACTION_DB_TYPE_EXPENSE, AccountId: 101, Amount: 100, TransactionTime: 1000},
+	)
+
+	err := Transactions.MergeAccounts(c, 1, 101, 102)
+	assert.Equal(t, errs.ErrCannotUseDataInDifferentLedger, err)
+
+	account, err := Accounts.GetAccountByAccountId(c, 1, 101)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1000), account.Balance)
+
+	transaction, err := Transactions.GetTransactionByTransactionId(c, 1, 501)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(101), transaction.AccountId)
+}
+
+func TestMoveAllTransactionsBetweenAccounts_AccountsInDifferentLedgers(t *testing.T) {
+	c := testutils.InitializeTestDataStore(t)
+
+	testutils.InsertTestData(t, 1,
+		&models.Account{AccountId: 101, Uid: 1, LedgerId: 1001, Name: "Cash", Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Currency: "USD"},
+		&models.Account{AccountId: 102, Uid: 1, LedgerId: 1002, Name: "Cash", Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Currency: "USD"},
+	)
+
+	err := Transactions.MoveAllTransactionsBetweenAccounts(c, 1, 101, 102)
+	assert.Equal(t, errs.ErrCannotUseDataInDifferentLedger, err)
+}
+
+func TestMergeAccounts_AccountsInSameLedger(t *testing.T) {
+	c := testutils.InitializeTestDataStore(t)
+
+	testutils.InsertTestData(t, 1,
+		&models.Account{AccountId: 101, Uid: 1, LedgerId: 1001, Name: "Cash", Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Currency: "USD", Balance: 1000},
+		&models.Account{AccountId: 102, Uid: 1, LedgerId: 1001, Name: "Wallet", Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Currency: "USD", Balance: 2000},
+		&models.Transaction{TransactionId: 501, Uid: 1, LedgerId: 1001, Type: models.TRANSACTION_DB_TYPE_EXPENSE, AccountId: 101, Amount: 100, TransactionTime: 1000},
+	)
+
+	err := Transactions.MergeAccounts(c, 1, 101, 102)
+	assert.Nil(t, err)
+
+	_, err = Accounts.GetAccountByAccountId(c, 1, 101)
+	assert.Equal(t, errs.ErrAccountNotFound, err)
+
+	account, err := Accounts.GetAccountByAccountId(c, 1, 102)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3000), account.Balance)
+
+	transaction, err := Transactions.GetTransactionByTransactionId(c, 1, 501)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(102), transaction.AccountId)
+}
+
+func TestCreateTransaction_SaveCustomFieldValues(t *testing.T) {
+	c := initializeTestCreateTransactionData(t)
+
+	transaction := &models.Transaction{Uid: 1, LedgerId: 1001, Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 202, AccountId: 101, Amount: 100, TransactionTime: 1700000000000}
+	err := Transactions.CreateTransaction(c, transaction, nil, nil, nil, []*models.TransactionCustomFieldValue{
+		{FieldId: 801, TextValue: "Foo"},
+	})
+	assert.Nil(t, err)
+
+	allValues, err := TransactionCustomFields.GetCustomFieldValuesByTransactionIds(c, 1, []int64{transaction.TransactionId})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(allValues[transaction.TransactionId]))
+	assert.Equal(t, "Foo", allValues[transaction.TransactionId][0].TextValue)
+}
+
+func TestCreateTransaction_RollbackWhenSaveCustomFieldValuesFailed(t *testing.T) {
+	c := initializeTestCreateTransactionData(t)
+
+	transaction := &models.Transaction{Uid: 1, LedgerId: 1001, Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 202, AccountId: 101, Amount: 100, TransactionTime: 1700000000000}
+	err := Transactions.CreateTransaction(c, transaction, nil, nil, nil, []*models.TransactionCustomFieldValue{
+		{FieldId: 801, TextValue: "Foo"},
+		{FieldId: 801, TextValue: "Bar"},
+	})
+	assert.NotNil(t, err)
+
+	_, err = Transactions.GetTransactionByTransactionId(c, 1, transaction.TransactionId)
+	assert.Equal(t, errs.ErrTransactionNotFound, err)
+
+	account, err := Accounts.GetAccountByAccountId(c, 1, 101)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1000), account.Balance)
+}
+
+func TestModifyTransaction_ReplaceCustomFieldValues(t *testing.T) {
+	c := initializeTestCreateTransactionData(t)
+
+	transaction := &models.Transaction{Uid: 1, LedgerId: 1001, Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 202, AccountId: 101, Amount: 100, TransactionTime: 1700000000000}
+	err := Transactions.CreateTransaction(c, transaction, nil, nil, nil, []*models.TransactionCustomFieldValue{
+		{FieldId: 801, TextValue: "Foo"},
+	})
+	assert.Nil(t, err)
+
+	newTransaction := &models.Transaction{TransactionId: transaction.TransactionId, Uid: 1, Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 202, AccountId: 101, Amount: 100, TransactionTime: transaction.TransactionTime}
+	err = Transactions.ModifyTransaction(c, newTransaction, 0, nil, nil, nil, nil, nil, nil, []*models.TransactionCustomFieldValue{
+		{FieldId: 802, TextValue: "Bar"},
+	})
+	assert.Nil(t, err)
+
+	allValues, err := TransactionCustomFields.GetCustomFieldValuesByTransactionIds(c, 1, []int64{transaction.TransactionId})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(allValues[transaction.TransactionId]))
+	assert.Equal(t, int64(802), allValues[transaction.TransactionId][0].FieldId)
+
+	err = Transactions.ModifyTransaction(c, newTransaction, 0, nil, nil, nil, nil, nil, nil, nil)
+	assert.Nil(t, err)
+
+	allValues, err = TransactionCustomFields.GetCustomFieldValuesByTransactionIds(c, 1, []int64{transaction.TransactionId})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(allValues[transaction.TransactionId]))
+	assert.Equal(t, "Bar", allValues[transaction.TransactionId][0].TextValue)
+}
+
+func TestCreateTransaction_LinkAttachments(t *testing.T) {
+	c := initializeTestCreateTransactionData(t)
+	testutils.InsertTestData(t, 1, &models.TransactionAttachment{AttachmentId: 901, Uid: 1, TransactionId: models.TransactionAttachmentNewAttachmentTransactionId, FileName: "receipt.pdf", FileExtension: "pdf"})
+
+	transaction := &models.Transaction{Uid: 1, LedgerId: 1001, Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 202, AccountId: 101, Amount: 100, TransactionTime: 1700000000000}
+	err := Transactions.CreateTransaction(c, transaction, nil, nil, []int64{901}, nil)
+	assert.Nil(t, err)
+
+	attachments, err := TransactionAttachments.GetAttachmentsByTransactionId(c, 1, transaction.TransactionId)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(attachments))
+	assert.Equal(t, int64(901), attachments[0].AttachmentId)
+}
+
+func TestCreateTransaction_RollbackWhenLinkAttachmentsFailed(t *testing.T) {
+	c := initializeTestCreateTransactionData(t)
+	testutils.InsertTestData(t, 1, &models.TransactionAttachment{AttachmentId: 901, Uid: 1, TransactionId: 501, FileName: "receipt.pdf", FileExtension: "pdf"})
+
+	transaction := &models.Transaction{Uid: 1, LedgerId: 1001, Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 202, AccountId: 101, Amount: 100, TransactionTime: 1700000000000}
+	err := Transactions.CreateTransaction(c, transaction, nil, nil, []int64{901}, []*models.TransactionCustomFieldValue{
+		{FieldId: 801, TextValue: "Foo"},
+	})
+	assert.Equal(t, errs.ErrTransactionAttachmentIdInvalid, err)
+
+	_, err = Transactions.GetTransactionByTransactionId(c, 1, transaction.TransactionId)
+	assert.Equal(t, errs.ErrTransactionNotFound, err)
+
+	allValues, err := TransactionCustomFields.GetCustomFieldValuesByTransactionIds(c, 1, []int64{transaction.TransactionId})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(allValues[transaction.TransactionId]))
+}
+
+func TestModifyTransaction_UpdateAttachments(t *testing.T) {
+	c := initializeTestCreateTransactionData(t)
+	testutils.InsertTestData(t, 1,
+		&models.TransactionAttachment{AttachmentId: 901, Uid: 1, TransactionId: models.TransactionAttachmentNewAttachmentTransactionId, FileName: "receipt.pdf", FileExtension: "pdf"},
+		&models.TransactionAttachment{AttachmentId: 902, Uid: 1, TransactionId: models.TransactionAttachmentNewAttachmentTransactionId, FileName: "invoice.pdf", FileExtension: "pdf"},
+	)
+
+	transaction := &models.Transaction{Uid: 1, LedgerId: 1001, Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 202, AccountId: 101, Amount: 100, TransactionTime: 1700000000000}
+	err := Transactions.CreateTransaction(c, transaction, nil, nil, []int64{901}, nil)
+	assert.Nil(t, err)
+
+	newTransaction := &models.Transaction{TransactionId: transaction.TransactionId, Uid: 1, Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 202, AccountId: 101, Amount: 100, TransactionTime: transaction.TransactionTime}
+	err = Transactions.ModifyTransaction(c, newTransaction, 0, nil, nil, nil, nil, []int64{902}, []int64{901}, nil)
+	assert.Nil(t, err)
+
+	attachments, err := TransactionAttachments.GetAttachmentsByTransactionId(c, 1, transaction.TransactionId)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(attachments))
+	assert.Equal(t, int64(902), attachments[0].AttachmentId)
+}
+
+func initializeTestCreateTransactionData(t *testing.T) core.Context {
+	c := testutils.InitializeTestDataStore(t)
+
+	testutils.InsertTestData(t, 1,
+		&models.Account{AccountId: 101, Uid: 1, LedgerId: 1001, Name: "Cash", Category: models.ACCOUNT_CATEGORY_CASH, Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Currency: "USD", Balance: 1000},
+		&models.TransactionCategory{CategoryId: 201, Uid: 1, LedgerId: 1001, Name: "Food", Type: models.CATEGORY_TYPE_EXPENSE, ParentCategoryId: models.LevelOneTransactionCategoryParentId},
+		&models.TransactionCategory{CategoryId: 202, Uid: 1, LedgerId: 1001, Name: "Meals", Type: models.CATEGORY_TYPE_EXPENSE, ParentCategoryId: 201},
+	)
+
+	return c
+}
+
+func TestGetExportedTransactionDataPageReader(t *testing.T) {
+	c := testutils.InitializeTestDataStore(t)
+
+	testutils.InsertTestData(t, 1,
+		&models.Transaction{TransactionId: 501, Uid: 1, LedgerId: 1001, Type: models.TRANSACTION_DB_TYPE_EXPENSE, AccountId: 101, Amount: 100, TransactionTime: 1000},
+		&models.Transaction{TransactionId: 502, Uid: 1, LedgerId: 1001, Type: models.TRANSACTION_DB_TYPE_EXPENSE, AccountId: 101, Amount: 200, TransactionTime: 2000},
+		&models.Transaction{TransactionId: 503, Uid: 1, LedgerId: 1001, Type: models.TRANSACTION_DB_TYPE_EXPENSE, AccountId: 101, Amount: 300, TransactionTime: 3000},
+		&models.Transaction{TransactionId: 504, Uid: 1, LedgerId: 1002, Type: models.TRANSACTION_DB_TYPE_EXPENSE, AccountId: 102, Amount: 400, TransactionTime: 4000},
+		&models.TransactionTagIndex{TagIndexId: 601, Uid: 1, TagId: 701, TransactionId: 503, TransactionTime: 3000},
+		&models.TransactionCustomFieldValue{Uid: 1, FieldId: 801, TransactionId: 501, TextValue: "Foo"},
+	)
+
+	access := &models.LedgerAccess{LedgerId: 1001, OwnerUid: 1, Uid: 1, Role: models.LEDGER_MEMBER_ROLE_OWNER}
+	pageReader, err := Transactions.GetExportedTransactionDataPageReader(c, access, 5000, 0, 0, nil, nil, nil, false, "", "", 2, true)
+	assert.Nil(t, err)
+
+	page, err := pageReader()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(page.Transactions))
+	assert.Equal(t, int64(503), page.Transactions[0].TransactionId)
+	assert.Equal(t, int64(502), page.Transactions[1].TransactionId)
+	assert.Equal(t, map[int64][]int64{503: {701}}, page.AllTagIndexes)
+	assert.Equal(t, 0, len(page.AllCustomFieldValues))
+
+	page, err = pageReader()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(page.Transactions))
+	assert.Equal(t, int64(501), page.Transactions[0].TransactionId)
+	assert.Equal(t, 0, len(page.AllTagIndexes))
+	assert.Equal(t, "Foo", page.AllCustomFieldValues[501][0].TextValue)
+
+	page, err = pageReader()
+	assert.Nil(t, err)
+	assert.Nil(t, page)
+}
+
+func TestGetExportedTransactionDataPageReader_PeakHeapNotGrowWithTransactionCount(t *testing.T) {
+	// the heap measured after gc can be equal to or less than the base heap, so a fixed tolerance is allowed,
+	// which is much less than the heap used by retaining all the transactions of the larger export
+	const peakHeapTolerance = 256 * 1024
+
+	peakHeapOfNTransactions := getPeakHeapOfExportingTransactions(t, 500)
+	peakHeapOf10NTransactions := getPeakHeapOfExportingTransactions(t, 5000)
+
+	assert.Less(t, peakHeapOf10NTransactions, peakHeapOfNTransactions*2+peakHeapTolerance)
+}
+
+func BenchmarkGetExportedTransactionDataPageReader(b *testing.B) {
+	for _, transactionCount := range []int{1000, 10000} {
+		b.Run(fmt.Sprintf("%d", transactionCount), func(b *testing.B) {
+			var peakHeap uint64
+
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				peakHeap = max(peakHeap, getPeakHeapOfExportingTransactions(b, transactionCount))
+			}
+
+			b.ReportMetric(float64(peakHeap)/1024/1024, "MB-heap")
+		})
+	}
+}
+
+func getPeakHeapOfExportingTransactions(t testing.TB, transactionCount int) uint64 {
+	c := testutils.InitializeTestDataStore(t)
+
+	account := &models.Account{AccountId: 101, Uid: 1, LedgerId: 1001, Name: "Cash", Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Currency: "USD"}
+	testutils.InsertTestData(t, 1, account)
+
+	for i := 0; i < transactionCount; i += 100 {
+		transactions := make([]*models.Transaction, 0, 100)
+
+		for j := i; j < i+100 && j < transactionCount; j++ {
+			transactions = append(transactions, &models.Transaction{
+				TransactionId:   int64(10000 + j),
+				Uid:             1,
+				LedgerId:        1001,
+				Type:            models.TRANSACTION_DB_TYPE_EXPENSE,
+				AccountId:       101,
+				Amount:          int64(j),
+				TransactionTime: int64(1000 * (j + 1)),
+				Comment:         "Test Transaction Comment",
+			})
+		}
+
+		testutils.InsertTestData(t, 1, &transactions)
+	}
+
+	pageReader, err := Transactions.GetExportedTransactionDataPageReader(c, LedgerAccesses.GetAllLedgersAccessOfOwner(1), int64(1000*(transactionCount+1)), 0, 0, nil, nil, nil, false, "", "", 100, false)
+	assert.Nil(t, err)
+
+	memStats := &runtime.MemStats{}
+	runtime.GC()
+	runtime.ReadMemStats(memStats)
+
+	baseHeap := memStats.HeapAlloc
+	peakHeap := uint64(0)
+	exportedCount := 0
+
+	measuredPageReader := func() (*converter.ExportedTransactionDataPage, error) {
+		page, err := pageReader()
+
+		if page != nil {
+			exportedCount += len(page.Transactions)
+		}
+
+		runtime.GC()
+		runtime.ReadMemStats(memStats)
+
+		if memStats.HeapAlloc > baseHeap && memStats.HeapAlloc-baseHeap > peakHeap {
+			peakHeap = memStats.HeapAlloc - baseHeap
+		}
+
+		return page, err
+	}
+
+	exporter := converters.GetTransactionDataStreamExporter("csv")
+	err = exporter.WriteExportedContent(core.NewNullContext(), io.Discard, 1, measuredPageReader, map[int64]*models.Account{101: account}, nil, nil, nil, converter.DefaultExporterOptions)
+	assert.Nil(t, err)
+	assert.Equal(t, transactionCount, exportedCount)
+
+	return peakHeap
+}
@@ -0,0 +1,418 @@
+package services
+
+import (
+	"io"
+	"os"
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/backup"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/storage"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+	"github.com/mayswind/ezbookkeeping/pkg/uuid"
+)
+
+// UserDataBackupService represents user data backup service
+type UserDataBackupService struct {
+	ServiceUsingDB
+	ServiceUsingUuid
+	ServiceUsingStorage
+}
+
+// Initialize a user data backup service singleton instance
+var (
+	UserDataBackups = &UserDataBackupService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+		ServiceUsingStorage: ServiceUsingStorage{
+			container: storage.Container,
+		},
+	}
+)
+
+// GetUserDataBackup returns the full backup of all data of specified user, including the files of transaction pictures and attachments,
+// the members of the shared ledgers are excluded
+func (s *UserDataBackupService) GetUserDataBackup(c core.Context, user *models.User) (*backup.UserDataBackup, error) {
+	if user == nil || user.Uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	uid := user.Uid
+	userDataBackup := backup.NewUserDataBackup(uid, user.Username, settings.Version, time.Now().Unix())
+	content := userDataBackup.Content
+	sess := s.UserDataDB(uid).NewSession(c)
+	defer sess.Close()
+
+	if err := sess.Where("uid=? AND deleted=?", uid, false).OrderBy("display_order asc").Find(&content.Ledgers); err != nil {
+		return nil, err
+	}
+
+	excludedLedgerMemberCount, err := s.UserDB().NewSession(c).Where("owner_uid=?", uid).Count(&models.LedgerMember{})
+
+	if err != nil {
+		return nil, err
+	}
+
+	userDataBackup.Manifest.ExcludedLedgerMemberCount = excludedLedgerMemberCount
+
+	if err := sess.Where("uid=? AND deleted=?", uid, false).OrderBy("parent_account_id asc, display_order asc").Find(&content.Accounts); err != nil {
+		return nil, err
+	}
+
+	if err := sess.Where("uid=? AND deleted=?", uid, false).OrderBy("type asc, parent_category_id asc, display_order asc").Find(&content.Categories); err != nil {
+		return nil, err
+	}
+
+	if err := sess.Where("uid=? AND deleted=?", uid, false).OrderBy("display_order asc").Find(&content.TagGroups); err != nil {
+		return nil, err
+	}
+
+	if err := sess.Where("uid=? AND deleted=?", uid, false).OrderBy("display_order asc").Find(&content.Tags); err != nil {
+		return nil, err
+	}
+
+	if err := sess.Where("uid=? AND deleted=?", uid, false).OrderBy("display_order asc").Find(&content.CustomFields); err != nil {
+		return nil, err
+	}
+
+	if err := sess.Where("uid=? AND deleted=?", uid, false).OrderBy("display_order asc").Find(&content.Contacts); err != nil {
+		return nil, err
+	}
+
+	if err := sess.Where("uid=? AND deleted=?", uid, false).OrderBy("display_order asc").Find(&content.Events); err != nil {
+		return nil, err
+	}
+
+	if err := sess.Where("uid=? AND deleted=?", uid, false).OrderBy("transaction_time asc").Find(&content.Transactions); err != nil {
+		return nil, err
+	}
+
+	if err := sess.Where("uid=? AND deleted=?", uid, false).OrderBy("transaction_time asc").Find(&content.TagIndexes); err != nil {
+		return nil, err
+	}
+
+	if err := sess.Where("uid=? AND deleted=?", uid, false).Find(&content.CustomFieldValues); err != nil {
+		return nil, err
+	}
+
+	if err := sess.Where("uid=? AND deleted=?", uid, false).OrderBy("transaction_time asc").Find(&content.ContactTransactions); err != nil {
+		return nil, err
+	}
+
+	if err := sess.Where("uid=? AND deleted=?", uid, false).OrderBy("template_type asc, display_order asc").Find(&content.Templates); err != nil {
+		return nil, err
+	}
+
+	if err := sess.Where("uid=? AND deleted_unix_time=?", uid, 0).Find(&content.CustomExchangeRates); err != nil {
+		return nil, err
+	}
+
+	applicationCloudSettings := &models.UserApplicationCloudSetting{}
+	has, err := sess.Where("uid=?", uid).Get(applicationCloudSettings)
+
+	if err != nil {
+		return nil, err
+	} else if has {
+		content.ApplicationCloudSettings = applicationCloudSettings
+	}
+
+	var pictureInfos []*models.TransactionPictureInfo
+
+	if err := sess.Where("uid=? AND deleted=? AND transaction_id>?", uid, false, 0).Find(&pictureInfos); err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < len(pictureInfos); i++ {
+		pictureInfo := pictureInfos[i]
+		pictureData, err := s.readStoredFile(s.ReadTransactionPicture(c, uid, pictureInfo.GetStoredPictureId(), pictureInfo.PictureExtension))
+
+		if os.IsNotExist(err) {
+			log.Warnf(c, "[user_data_backups.GetUserDataBackup] the file of transaction picture \"id:%d\" of user \"uid:%d\" does not exist, skip it", pictureInfo.PictureId, uid)
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		content.Pictures = append(content.Pictures, pictureInfo)
+		userDataBackup.PictureFiles[pictureInfo.PictureId] = pictureData
+	}
+
+	var attachments []*models.TransactionAttachment
+
+	if err := sess.Where("uid=? AND deleted=? AND transaction_id>?", uid, false, 0).Find(&attachments); err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < len(attachments); i++ {
+		attachment := attachments[i]
+		attachmentData, err := s.readStoredFile(s.ReadTransactionAttachment(c, uid, attachment.AttachmentId, attachment.FileExtension))
+
+		if os.IsNotExist(err) {
+			log.Warnf(c, "[user_data_backups.GetUserDataBackup] the file of transaction attachment \"id:%d\" of user \"uid:%d\" does not exist, skip it", attachment.AttachmentId, uid)
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		content.Attachments = append(content.Attachments, attachment)
+		userDataBackup.AttachmentFiles[attachment.AttachmentId] = attachmentData
+
+		if !attachment.HasThumbnail {
+			continue
+		}
+
+		thumbnailData, err := s.readStoredFile(s.ReadTransactionAttachment(c, uid, attachment.AttachmentId, models.TransactionAttachmentThumbnailFileExtension))
+
+		if err == nil {
+			userDataBackup.AttachmentThumbnailFiles[attachment.AttachmentId] = thumbnailData
+		} else if !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+
+	return userDataBackup, nil
+}
+
+// RestoreUserDataBackup recreates all data in the backup for specified user with new generated ids,
+// the specified user must not have any existed data
+func (s *UserDataBackupService) RestoreUserDataBackup(c core.Context, uid int64, userDataBackup *backup.UserDataBackup) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	isEmpty, err := s.isUserDataEmpty(c, uid)
+
+	if err != nil {
+		return err
+	} else if !isEmpty {
+		return errs.ErrUserDataNotEmpty
+	}
+
+	err = backup.RemapUserDataBackupIds(userDataBackup, uid, s)
+
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+	content := userDataBackup.Content
+	pictureObjects := s.getPictureObjects(content.Pictures, userDataBackup.PictureFiles, now)
+
+	err = s.saveStoredFiles(c, uid, userDataBackup, pictureObjects)
+
+	if err != nil {
+		s.deleteStoredFiles(c, uid, userDataBackup, pictureObjects)
+		return err
+	}
+
+	err = s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(&models.Ledger{Deleted: true, DeletedUnixTime: now})
+
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Cols("deleted_unix_time").Where("uid=? AND deleted_unix_time=?", uid, 0).Update(&models.UserCustomExchangeRate{DeletedUnixTime: now})
+
+		if err != nil {
+			return err
+		}
+
+		if content.ApplicationCloudSettings != nil {
+			if _, err = sess.Where("uid=?", uid).Delete(&models.UserApplicationCloudSetting{}); err != nil {
+				return err
+			}
+
+			if _, err = sess.Insert(content.ApplicationCloudSettings); err != nil {
+				return err
+			}
+		}
+
+		insertions := []func() error{
+			func() error { return insertRows(sess, content.Ledgers) },
+			func() error { return insertRows(sess, content.Accounts) },
+			func() error { return insertRows(sess, content.Categories) },
+			func() error { return insertRows(sess, content.TagGroups) },
+			func() error { return insertRows(sess, content.Tags) },
+			func() error { return insertRows(sess, content.CustomFields) },
+			func() error { return insertRows(sess, content.Contacts) },
+			func() error { return insertRows(sess, content.Events) },
+			func() error { return insertRows(sess, content.Transactions) },
+			func() error { return insertRows(sess, content.TagIndexes) },
+			func() error { return insertRows(sess, content.CustomFieldValues) },
+			func() error { return insertRows(sess, content.ContactTransactions) },
+			func() error { return insertRows(sess, content.Templates) },
+			func() error { return insertRows(sess, pictureObjects) },
+			func() error { return insertRows(sess, content.Pictures) },
+			func() error { return insertRows(sess, content.Attachments) },
+			func() error { return insertRows(sess, content.CustomExchangeRates) },
+		}
+
+		for i := 0; i < len(insertions); i++ {
+			if err = insertions[i](); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		s.deleteStoredFiles(c, uid, userDataBackup, pictureObjects)
+		return err
+	}
+
+	_, err = Ledgers.GetOrCreateDefaultLedger(c, uid)
+
+	return err
+}
+
+func (s *UserDataBackupService) isUserDataEmpty(c core.Context, uid int64) (bool, error) {
+	sess := s.UserDataDB(uid).NewSession(c)
+	defer sess.Close()
+
+	beans := []any{
+		&models.Account{},
+		&models.Transaction{},
+		&models.TransactionCategory{},
+		&models.TransactionTagGroup{},
+		&models.TransactionTag{},
+		&models.TransactionCustomField{},
+		&models.TransactionTemplate{},
+		&models.Contact{},
+		&models.Event{},
+	}
+
+	for i := 0; i < len(beans); i++ {
+		count, err := sess.Where("uid=? AND deleted=?", uid, false).Count(beans[i])
+
+		if err != nil {
+			return false, err
+		} else if count > 0 {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func (s *UserDataBackupService) getPictureObjects(pictureInfos []*models.TransactionPictureInfo, pictureFiles map[int64][]byte, now int64) []*models.TransactionPictureObject {
+	pictureObjects := make([]*models.TransactionPictureObject, 0, len(pictureInfos))
+	pictureObjectMap := make(map[string]*models.TransactionPictureObject, len(pictureInfos))
+
+	for i := 0; i < len(pictureInfos); i++ {
+		pictureInfo := pictureInfos[i]
+		pictureData := pictureFiles[pictureInfo.PictureId]
+		pictureInfo.ContentHash = utils.SHA256EncodeToString(pictureData)
+
+		key := pictureInfo.ContentHash + "." + pictureInfo.PictureExtension
+		pictureObject, exists := pictureObjectMap[key]
+
+		if exists {
+			pictureObject.ReferenceCount++
+		} else {
+			pictureObject = &models.TransactionPictureObject{
+				Uid:              pictureInfo.Uid,
+				ObjectId:         pictureInfo.PictureId,
+				ContentHash:      pictureInfo.ContentHash,
+				PictureExtension: pictureInfo.PictureExtension,
+				FileSize:         int64(len(pictureData)),
+				ReferenceCount:   1,
+				CreatedUnixTime:  now,
+				UpdatedUnixTime:  now,
+			}
+
+			pictureObjects = append(pictureObjects, pictureObject)
+			pictureObjectMap[key] = pictureObject
+		}
+
+		pictureInfo.StoredPictureId = pictureObject.ObjectId
+	}
+
+	return pictureObjects
+}
+
+func (s *UserDataBackupService) saveStoredFiles(c core.Context, uid int64, userDataBackup *backup.UserDataBackup, pictureObjects []*models.TransactionPictureObject) error {
+	for i := 0; i < len(pictureObjects); i++ {
+		pictureObject := pictureObjects[i]
+		pictureData := userDataBackup.PictureFiles[pictureObject.ObjectId]
+
+		if err := s.SaveTransactionPicture(c, uid, pictureObject.ObjectId, storage.NewByteSliceObject(pictureData), pictureObject.PictureExtension); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(userDataBackup.Content.Attachments); i++ {
+		attachment := userDataBackup.Content.Attachments[i]
+		attachmentData := userDataBackup.AttachmentFiles[attachment.AttachmentId]
+
+		if err := s.SaveTransactionAttachment(c, uid, attachment.AttachmentId, storage.NewByteSliceObject(attachmentData), attachment.FileExtension); err != nil {
+			return err
+		}
+
+		thumbnailData, exists := userDataBackup.AttachmentThumbnailFiles[attachment.AttachmentId]
+
+		if !exists {
+			attachment.HasThumbnail = false
+			continue
+		}
+
+		if err := s.SaveTransactionAttachment(c, uid, attachment.AttachmentId, storage.NewByteSliceObject(thumbnailData), models.TransactionAttachmentThumbnailFileExtension); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *UserDataBackupService) deleteStoredFiles(c core.Context, uid int64, userDataBackup *backup.UserDataBackup, pictureObjects []*models.TransactionPictureObject) {
+	for i := 0; i < len(pictureObjects); i++ {
+		pictureObject := pictureObjects[i]
+
+		if err := s.DeleteTransactionPicture(c, uid, pictureObject.ObjectId, pictureObject.PictureExtension); err != nil && !os.IsNotExist(err) {
+			log.Warnf(c, "[user_data_backups.deleteStoredFiles] failed to delete restored transaction picture \"id:%d\" for user \"uid:%d\", because %s", pictureObject.ObjectId, uid, err.Error())
+		}
+	}
+
+	for i := 0; i < len(userDataBackup.Content.Attachments); i++ {
+		attachment := userDataBackup.Content.Attachments[i]
+		fileExtensions := []string{attachment.FileExtension, models.TransactionAttachmentThumbnailFileExtension}
+
+		for j := 0; j < len(fileExtensions); j++ {
+			if err := s.DeleteTransactionAttachment(c, uid, attachment.AttachmentId, fileExtensions[j]); err != nil && !os.IsNotExist(err) {
+				log.Warnf(c, "[user_data_backups.deleteStoredFiles] failed to delete restored transaction attachment \"id:%d\" for user \"uid:%d\", because %s", attachment.AttachmentId, uid, err.Error())
+			}
+		}
+	}
+}
+
+func (s *UserDataBackupService) readStoredFile(object storage.ObjectInStorage, err error) ([]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	defer object.Close()
+
+	return io.ReadAll(object)
+}
+
+func insertRows[T any](sess *xorm.Session, rows []*T) error {
+	for i := 0; i < len(rows); i++ {
+		if _, err := sess.Insert(rows[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
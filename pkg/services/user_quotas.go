@@ -0,0 +1,178 @@
+package services
+
+import (
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+// UserQuotaService represents user quota service
+type UserQuotaService struct {
+	ServiceUsingDB
+	ServiceUsingConfig
+}
+
+// Initialize a user quota service singleton instance
+var (
+	UserQuotas = &UserQuotaService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingConfig: ServiceUsingConfig{
+			container: settings.Container,
+		},
+	}
+)
+
+// GetStorageUsedBytes returns the total size of all stored transaction pictures and attachments of user,
+// the picture files with the same content are only counted once
+func (s *UserQuotaService) GetStorageUsedBytes(c core.Context, uid int64) (int64, error) {
+	if uid <= 0 {
+		return 0, errs.ErrUserIdInvalid
+	}
+
+	pictureBytes, err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND reference_count>?", uid, 0).SumInt(&models.TransactionPictureObject{}, "file_size")
+
+	if err != nil {
+		return 0, err
+	}
+
+	attachmentBytes, err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).SumInt(&models.TransactionAttachment{}, "file_size")
+
+	if err != nil {
+		return 0, err
+	}
+
+	return pictureBytes + attachmentBytes, nil
+}
+
+// GetTransactionUsedCount returns the count of transactions of user which are counted in quota, a transfer transaction is counted once
+func (s *UserQuotaService) GetTransactionUsedCount(c core.Context, uid int64) (int64, error) {
+	if uid <= 0 {
+		return 0, errs.ErrUserIdInvalid
+	}
+
+	return s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=? AND type<>?", uid, false, models.TRANSACTION_DB_TYPE_TRANSFER_IN).Count(&models.Transaction{})
+}
+
+// GetUserQuotaUsage returns the usage and the effective quota of the specified user
+func (s *UserQuotaService) GetUserQuotaUsage(c core.Context, user *models.User) (*models.UserQuotaUsage, error) {
+	if user == nil {
+		return nil, errs.ErrUserNotFound
+	}
+
+	storageUsedBytes, err := s.GetStorageUsedBytes(c, user.Uid)
+
+	if err != nil {
+		return nil, err
+	}
+
+	transactionUsedCount, err := s.GetTransactionUsedCount(c, user.Uid)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.UserQuotaUsage{
+		StorageUsedBytes:     storageUsedBytes,
+		StorageQuota:         models.GetEffectiveUserQuota(user.StorageQuota, s.CurrentConfig().DefaultUserStorageQuota),
+		TransactionUsedCount: transactionUsedCount,
+		TransactionQuota:     models.GetEffectiveUserQuota(user.TransactionQuota, s.CurrentConfig().DefaultUserTransactionQuota),
+	}, nil
+}
+
+// CheckStorageQuota returns error if the storage quota of the specified user would be exceeded after adding the specified bytes
+func (s *UserQuotaService) CheckStorageQuota(c core.Context, user *models.User, additionalBytes int64) error {
+	if user == nil {
+		return errs.ErrUserNotFound
+	}
+
+	if models.GetEffectiveUserQuota(user.StorageQuota, s.CurrentConfig().DefaultUserStorageQuota) <= 0 {
+		return nil
+	}
+
+	usage, err := s.GetUserQuotaUsage(c, user)
+
+	if err != nil {
+		return err
+	}
+
+	if usage.IsStorageQuotaExceeded(additionalBytes) {
+		return errs.ErrUserStorageQuotaExceeded
+	}
+
+	return nil
+}
+
+// CheckTransactionQuota returns error if the transaction quota of the specified user would be exceeded after adding the specified count of transactions
+func (s *UserQuotaService) CheckTransactionQuota(c core.Context, user *models.User, additionalCount int64) error {
+	if user == nil {
+		return errs.ErrUserNotFound
+	}
+
+	if models.GetEffectiveUserQuota(user.TransactionQuota, s.CurrentConfig().DefaultUserTransactionQuota) <= 0 {
+		return nil
+	}
+
+	usage, err := s.GetUserQuotaUsage(c, user)
+
+	if err != nil {
+		return err
+	}
+
+	if usage.IsTransactionQuotaExceeded(additionalCount) {
+		return errs.ErrUserTransactionQuotaExceeded
+	}
+
+	return nil
+}
+
+// CheckTransactionQuotaByUid returns error if the transaction quota of the specified user would be exceeded after adding the specified count of transactions
+func (s *UserQuotaService) CheckTransactionQuotaByUid(c core.Context, uid int64, additionalCount int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	user := &models.User{}
+	has, err := s.UserDB().NewSession(c).ID(uid).Where("deleted=?", false).Get(user)
+
+	if err != nil {
+		return err
+	} else if !has {
+		return errs.ErrUserNotFound
+	}
+
+	return s.CheckTransactionQuota(c, user, additionalCount)
+}
+
+// UpdateUserQuotas sets the storage quota and the transaction quota of the specified user,
+// zero means using the default quota and negative value means unlimited
+func (s *UserQuotaService) UpdateUserQuotas(c core.Context, username string, storageQuota int64, transactionQuota int64) error {
+	if username == "" {
+		return errs.ErrUsernameIsEmpty
+	}
+
+	if storageQuota < models.UserQuotaUnlimited || transactionQuota < models.UserQuotaUnlimited {
+		return errs.ErrUserQuotaInvalid
+	}
+
+	updateModel := &models.User{
+		StorageQuota:     storageQuota,
+		TransactionQuota: transactionQuota,
+		UpdatedUnixTime:  time.Now().Unix(),
+	}
+
+	updatedRows, err := s.UserDB().NewSession(c).Cols("storage_quota", "transaction_quota", "updated_unix_time").Where("username=? AND deleted=?", username, false).Update(updateModel)
+
+	if err != nil {
+		return err
+	} else if updatedRows < 1 {
+		return errs.ErrUserNotFound
+	}
+
+	return nil
+}
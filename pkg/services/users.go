@@ -103,6 +103,28 @@ func (s *UserService) GetUserById(c core.Context, uid int64) (*models.User, erro
 	return user, nil
 }
 
+// GetUsersByUids returns the user models according to user uids
+func (s *UserService) GetUsersByUids(c core.Context, uids []int64) (map[int64]*models.User, error) {
+	if len(uids) < 1 {
+		return make(map[int64]*models.User), nil
+	}
+
+	var users []*models.User
+	err := s.UserDB().NewSession(c).Where("deleted=?", false).In("uid", uids).Find(&users)
+
+	if err != nil {
+		return nil, err
+	}
+
+	userMap := make(map[int64]*models.User, len(users))
+
+	for i := 0; i < len(users); i++ {
+		userMap[users[i].Uid] = users[i]
+	}
+
+	return userMap, nil
+}
+
 // GetUserByUsername returns the user model according to user name
 func (s *UserService) GetUserByUsername(c core.Context, username string) (*models.User, error) {
 	if username == "" {
@@ -1,6 +1,7 @@
 package settings
 
 import (
+	"encoding/base64"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -53,6 +54,15 @@ const (
 	LOGLEVEL_ERROR Level = "error"
 )
 
+// TransactionPictureConvertFormat represents the image format which the uploaded transaction pictures are converted to
+type TransactionPictureConvertFormat string
+
+// Transaction picture convert formats
+const (
+	TRANSACTION_PICTURE_CONVERT_FORMAT_NONE TransactionPictureConvertFormat = ""
+	TRANSACTION_PICTURE_CONVERT_FORMAT_JPEG TransactionPictureConvertFormat = "jpeg"
+)
+
 // Database types
 const (
 	MySqlDbType    string = "mysql"
@@ -161,12 +171,16 @@ const (
 
 	defaultWebDAVRequestTimeout uint32 = 10000 // 10 seconds
 
+	storageEncryptionMasterKeyLength int = 32
+
 	defaultAIRecognitionPictureMaxSize         uint32 = 10485760 // 10MB
 	defaultLargeLanguageModelAPIRequestTimeout uint32 = 60000    // 60 seconds
 
 	defaultInMemoryDuplicateCheckerCleanupInterval uint32 = 60  // 1 minutes
 	defaultDuplicateSubmissionsInterval            uint32 = 300 // 5 minutes
 
+	defaultOrphanedTransactionPictureGracePeriod uint32 = 86400 // 1 day
+
 	defaultSecretKey                     string = "ezbookkeeping"
 	defaultTokenExpiredTime              uint32 = 2592000 // 30 days
 	defaultTokenMinRefreshInterval       uint32 = 86400   // 1 day
@@ -179,10 +193,13 @@ const (
 	defaultOAuth2StateExpiredTime uint32 = 300   // 5 minutes
 	defaultOAuth2RequestTimeout   uint32 = 10000 // 10 seconds
 
-	defaultTransactionPictureFileMaxSize uint32 = 10485760 // 10MB
-	defaultUserAvatarFileMaxSize         uint32 = 1048576  // 1MB
+	defaultTransactionPictureFileMaxSize    uint32 = 10485760 // 10MB
+	defaultTransactionAttachmentFileMaxSize uint32 = 10485760 // 10MB
+	defaultUserAvatarFileMaxSize            uint32 = 1048576  // 1MB
 
-	defaultImportFileMaxSize uint32 = 10485760 // 10MB
+	defaultImportFileMaxSize         uint32 = 10485760   // 10MB
+	defaultBackupArchiveEntryMaxSize uint32 = 104857600  // 100MB
+	defaultBackupArchiveTotalMaxSize uint64 = 1073741824 // 1GB
 
 	defaultExchangeRatesDataRequestTimeout uint32 = 10000 // 10 seconds
 )
@@ -319,6 +336,10 @@ type Config struct {
 	MinIOConfig         *MinIOConfig
 	WebDAVConfig        *WebDAVConfig
 
+	StorageEncryptionMasterKey             []byte
+	StorageEncryptionPreviousMasterKey     []byte
+	StorageEncryptionAllowPlaintextObjects bool
+
 	// Large Language Model
 	TransactionFromAIImageRecognition bool
 	MaxAIRecognitionPictureFileSize   uint32
@@ -339,8 +360,10 @@ type Config struct {
 	DuplicateSubmissionsIntervalDuration            time.Duration
 
 	// Cron
-	EnableRemoveExpiredTokens        bool
-	EnableCreateScheduledTransaction bool
+	EnableRemoveExpiredTokens               bool
+	EnableCreateScheduledTransaction        bool
+	EnableRemoveOrphanedTransactionPictures bool
+	OrphanedTransactionPictureGracePeriod   uint32
 
 	// Secret
 	SecretKeyNoSet                        bool
@@ -382,20 +405,34 @@ type Config struct {
 	OAuth2GiteaBaseUrl                string
 
 	// User
-	EnableUserRegister            bool
-	EnableUserVerifyEmail         bool
-	EnableUserForceVerifyEmail    bool
-	EnableTransactionPictures     bool
-	MaxTransactionPictureFileSize uint32
-	EnableScheduledTransaction    bool
-	AvatarProvider                core.UserAvatarProviderType
-	MaxAvatarFileSize             uint32
-	DefaultFeatureRestrictions    core.UserFeatureRestrictions
+	EnableUserRegister                       bool
+	EnableUserVerifyEmail                    bool
+	EnableUserForceVerifyEmail               bool
+	EnableTransactionPictures                bool
+	MaxTransactionPictureFileSize            uint32
+	EnableTransactionPictureThumbnails       bool
+	StripTransactionPictureGpsMetadata       bool
+	TransactionPictureConvertFormat          TransactionPictureConvertFormat
+	EnableTransactionAttachments             bool
+	MaxTransactionAttachmentFileSize         uint32
+	MaxTransactionAttachmentImageFileSize    uint32
+	MaxTransactionAttachmentPdfFileSize      uint32
+	MaxTransactionAttachmentDocumentFileSize uint32
+	MaxTransactionAttachmentTextFileSize     uint32
+	MaxTransactionAttachmentArchiveFileSize  uint32
+	EnableScheduledTransaction               bool
+	AvatarProvider                           core.UserAvatarProviderType
+	MaxAvatarFileSize                        uint32
+	DefaultFeatureRestrictions               core.UserFeatureRestrictions
+	DefaultUserStorageQuota                  uint64
+	DefaultUserTransactionQuota              uint64
 
 	// Data
-	EnableDataExport  bool
-	EnableDataImport  bool
-	MaxImportFileSize uint32
+	EnableDataExport          bool
+	EnableDataImport          bool
+	MaxImportFileSize         uint32
+	MaxBackupArchiveEntrySize uint32
+	MaxBackupArchiveTotalSize uint64
 
 	// Tip
 	LoginPageTips MultiLanguageContentConfig
@@ -830,6 +867,24 @@ func loadStorageConfiguration(config *Config, configFile *ini.File, sectionName
 	webDAVConfig.SkipTLSVerify = getConfigItemBoolValue(configFile, sectionName, "webdav_skip_tls_verify", false)
 	config.WebDAVConfig = webDAVConfig
 
+	config.StorageEncryptionMasterKey, err = getStorageEncryptionMasterKey(config.WorkingPath, configFile, sectionName, "encryption_master_key", "encryption_master_key_file")
+
+	if err != nil {
+		return err
+	}
+
+	config.StorageEncryptionPreviousMasterKey, err = getStorageEncryptionMasterKey(config.WorkingPath, configFile, sectionName, "encryption_previous_master_key", "encryption_previous_master_key_file")
+
+	if err != nil {
+		return err
+	}
+
+	if len(config.StorageEncryptionMasterKey) < 1 && len(config.StorageEncryptionPreviousMasterKey) > 0 {
+		return errs.ErrInvalidStorageEncryptionMasterKey
+	}
+
+	config.StorageEncryptionAllowPlaintextObjects = getConfigItemBoolValue(configFile, sectionName, "encryption_allow_plaintext_objects", false)
+
 	return nil
 }
 
@@ -927,6 +982,12 @@ func loadDuplicateCheckerConfiguration(config *Config, configFile *ini.File, sec
 func loadCronConfiguration(config *Config, configFile *ini.File, sectionName string) error {
 	config.EnableRemoveExpiredTokens = getConfigItemBoolValue(configFile, sectionName, "enable_remove_expired_tokens", false)
 	config.EnableCreateScheduledTransaction = getConfigItemBoolValue(configFile, sectionName, "enable_create_scheduled_transaction", false)
+	config.EnableRemoveOrphanedTransactionPictures = getConfigItemBoolValue(configFile, sectionName, "enable_remove_orphaned_transaction_pictures", false)
+	config.OrphanedTransactionPictureGracePeriod = getConfigItemUint32Value(configFile, sectionName, "orphaned_transaction_picture_grace_period", defaultOrphanedTransactionPictureGracePeriod)
+
+	if config.OrphanedTransactionPictureGracePeriod < 3600 {
+		return errs.ErrInvalidOrphanedTransactionPictureGracePeriod
+	}
 
 	return nil
 }
@@ -1046,6 +1107,24 @@ func loadUserConfiguration(config *Config, configFile *ini.File, sectionName str
 	config.EnableUserForceVerifyEmail = getConfigItemBoolValue(configFile, sectionName, "enable_force_email_verify", false)
 	config.EnableTransactionPictures = getConfigItemBoolValue(configFile, sectionName, "enable_transaction_picture", false)
 	config.MaxTransactionPictureFileSize = getConfigItemUint32Value(configFile, sectionName, "max_transaction_picture_size", defaultTransactionPictureFileMaxSize)
+	config.EnableTransactionPictureThumbnails = getConfigItemBoolValue(configFile, sectionName, "enable_transaction_picture_thumbnail", true)
+	config.StripTransactionPictureGpsMetadata = getConfigItemBoolValue(configFile, sectionName, "strip_transaction_picture_gps_metadata", true)
+
+	if getConfigItemStringValue(configFile, sectionName, "transaction_picture_convert_format") == string(TRANSACTION_PICTURE_CONVERT_FORMAT_JPEG) {
+		config.TransactionPictureConvertFormat = TRANSACTION_PICTURE_CONVERT_FORMAT_JPEG
+	} else if getConfigItemStringValue(configFile, sectionName, "transaction_picture_convert_format") == "" {
+		config.TransactionPictureConvertFormat = TRANSACTION_PICTURE_CONVERT_FORMAT_NONE
+	} else {
+		return errs.ErrInvalidTransactionPictureConvertFormat
+	}
+
+	config.EnableTransactionAttachments = getConfigItemBoolValue(configFile, sectionName, "enable_transaction_attachment", false)
+	config.MaxTransactionAttachmentFileSize = getConfigItemUint32Value(configFile, sectionName, "max_transaction_attachment_size", defaultTransactionAttachmentFileMaxSize)
+	config.MaxTransactionAttachmentImageFileSize = getConfigItemUint32Value(configFile, sectionName, "max_transaction_attachment_image_size", config.MaxTransactionAttachmentFileSize)
+	config.MaxTransactionAttachmentPdfFileSize = getConfigItemUint32Value(configFile, sectionName, "max_transaction_attachment_pdf_size", config.MaxTransactionAttachmentFileSize)
+	config.MaxTransactionAttachmentDocumentFileSize = getConfigItemUint32Value(configFile, sectionName, "max_transaction_attachment_document_size", config.MaxTransactionAttachmentFileSize)
+	config.MaxTransactionAttachmentTextFileSize = getConfigItemUint32Value(configFile, sectionName, "max_transaction_attachment_text_size", config.MaxTransactionAttachmentFileSize)
+	config.MaxTransactionAttachmentArchiveFileSize = getConfigItemUint32Value(configFile, sectionName, "max_transaction_attachment_archive_size", config.MaxTransactionAttachmentFileSize)
 	config.EnableScheduledTransaction = getConfigItemBoolValue(configFile, sectionName, "enable_scheduled_transaction", false)
 
 	if getConfigItemStringValue(configFile, sectionName, "avatar_provider") == string(core.USER_AVATAR_PROVIDER_INTERNAL) {
@@ -1060,6 +1139,8 @@ func loadUserConfiguration(config *Config, configFile *ini.File, sectionName str
 
 	config.MaxAvatarFileSize = getConfigItemUint32Value(configFile, sectionName, "max_user_avatar_size", defaultUserAvatarFileMaxSize)
 	config.DefaultFeatureRestrictions = core.ParseUserFeatureRestrictions(getConfigItemStringValue(configFile, sectionName, "default_feature_restrictions", ""))
+	config.DefaultUserStorageQuota = getConfigItemUint64Value(configFile, sectionName, "default_storage_quota", 0)
+	config.DefaultUserTransactionQuota = getConfigItemUint64Value(configFile, sectionName, "default_transaction_quota", 0)
 
 	return nil
 }
@@ -1068,6 +1149,8 @@ func loadDataConfiguration(config *Config, configFile *ini.File, sectionName str
 	config.EnableDataExport = getConfigItemBoolValue(configFile, sectionName, "enable_export", false)
 	config.EnableDataImport = getConfigItemBoolValue(configFile, sectionName, "enable_import", false)
 	config.MaxImportFileSize = getConfigItemUint32Value(configFile, sectionName, "max_import_file_size", defaultImportFileMaxSize)
+	config.MaxBackupArchiveEntrySize = getConfigItemUint32Value(configFile, sectionName, "max_backup_archive_entry_size", defaultBackupArchiveEntryMaxSize)
+	config.MaxBackupArchiveTotalSize = getConfigItemUint64Value(configFile, sectionName, "max_backup_archive_total_size", defaultBackupArchiveTotalMaxSize)
 
 	return nil
 }
@@ -1334,6 +1417,75 @@ func getConfigItemUint32Value(configFile *ini.File, sectionName string, itemName
 	return defaultValue
 }
 
+func getConfigItemUint64Value(configFile *ini.File, sectionName string, itemName string, defaultValue uint64) uint64 {
+	environmentValue := getConfigItemValueFromEnvironment(sectionName, itemName)
+
+	if len(environmentValue) > 0 {
+		value, err := strconv.ParseUint(environmentValue, 10, 64)
+
+		if err == nil {
+			return value
+		}
+	}
+
+	section := configFile.Section(sectionName)
+	value, err := strconv.ParseUint(section.Key(itemName).String(), 10, 64)
+
+	if err == nil {
+		return value
+	}
+
+	return defaultValue
+}
+
+func getStorageEncryptionMasterKey(workingPath string, configFile *ini.File, sectionName string, itemName string, fileItemName string) ([]byte, error) {
+	value, err := getConfigItemStringValueOrFileContent(workingPath, configFile, sectionName, itemName, fileItemName)
+
+	if err != nil {
+		return nil, errs.ErrInvalidStorageEncryptionMasterKey
+	}
+
+	if value == "" {
+		return nil, nil
+	}
+
+	masterKey, err := base64.StdEncoding.DecodeString(value)
+
+	if err != nil || len(masterKey) != storageEncryptionMasterKeyLength {
+		return nil, errs.ErrInvalidStorageEncryptionMasterKey
+	}
+
+	return masterKey, nil
+}
+
+func getConfigItemStringValueOrFileContent(workingPath string, configFile *ini.File, sectionName string, itemName string, fileItemName string) (string, error) {
+	value := getConfigItemStringValue(configFile, sectionName, itemName)
+
+	if value != "" {
+		return value, nil
+	}
+
+	filePath := getConfigItemStringValue(configFile, sectionName, fileItemName)
+
+	if filePath == "" {
+		return "", nil
+	}
+
+	finalFilePath, err := getFinalPath(workingPath, filePath)
+
+	if err != nil {
+		return "", err
+	}
+
+	content, err := os.ReadFile(finalFilePath)
+
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(content)), nil
+}
+
 func getConfigItemBoolValue(configFile *ini.File, sectionName string, itemName string, defaultValue bool) bool {
 	environmentValue := getConfigItemValueFromEnvironment(sectionName, itemName)
 
@@ -0,0 +1,410 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// The layout of encrypted object is:
+// magic header (8 bytes) | master key id (8 bytes) | wrapped data key length (2 bytes, big endian) | wrapped data key | encrypted content
+// the data key is randomly generated for each object and wrapped by the key derived from the master key with aes-gcm, and the content is encrypted by the data key with aes-gcm,
+// the object path is authenticated as additional data of both, so the encrypted object cannot be swapped with another object in storage
+const (
+	encryptedObjectMagicHeader         = "EBKENC\x00\x02"
+	encryptedObjectMasterKeyIdLength   = 8
+	encryptedObjectDataKeyLength       = 32
+	encryptedObjectWrappedKeyLenLength = 2
+	encryptedObjectHeaderMinLength     = len(encryptedObjectMagicHeader) + encryptedObjectMasterKeyIdLength + encryptedObjectWrappedKeyLenLength
+
+	// StorageEncryptionMasterKeyLength is the length of the master key of encrypted object storage
+	StorageEncryptionMasterKeyLength = 32
+
+	encryptedObjectKeyWrappingKeyInfo = "ezbookkeeping storage encryption key wrapping key"
+	encryptedObjectMasterKeyIdInfo    = "ezbookkeeping storage encryption master key id"
+)
+
+// EncryptedObjectStorage represents an object storage wrapper which encrypts objects before saving them into the underlying object storage
+type EncryptedObjectStorage struct {
+	objectStorage         ObjectStorage
+	masterKey             []byte
+	masterKeyId           []byte
+	previousMasterKey     []byte
+	previousMasterKeyId   []byte
+	allowPlaintextObjects bool
+}
+
+// NewEncryptedObjectStorage returns an encrypted object storage which wraps the specified object storage,
+// the master keys must be 32 random bytes, and the previous master key is only used to read objects which are encrypted before rotating the master key.
+// The objects which are not encrypted can be read only if allow plaintext objects is enabled, which should only be used while encrypting existing objects
+func NewEncryptedObjectStorage(objectStorage ObjectStorage, masterKey []byte, previousMasterKey []byte, allowPlaintextObjects bool) (*EncryptedObjectStorage, error) {
+	if len(masterKey) != StorageEncryptionMasterKeyLength || (len(previousMasterKey) > 0 && len(previousMasterKey) != StorageEncryptionMasterKeyLength) {
+		return nil, errs.ErrInvalidStorageEncryptionMasterKey
+	}
+
+	storage := &EncryptedObjectStorage{
+		objectStorage:         objectStorage,
+		allowPlaintextObjects: allowPlaintextObjects,
+	}
+
+	var err error
+	storage.masterKey, storage.masterKeyId, err = getKeyWrappingKeyAndMasterKeyId(masterKey)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(previousMasterKey) > 0 {
+		storage.previousMasterKey, storage.previousMasterKeyId, err = getKeyWrappingKeyAndMasterKeyId(previousMasterKey)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return storage, nil
+}
+
+// Exists returns whether the file exists
+func (s *EncryptedObjectStorage) Exists(ctx core.Context, path string) (bool, error) {
+	return s.objectStorage.Exists(ctx, path)
+}
+
+// Read returns the decrypted object instance according to specified the file path,
+// the object which is not encrypted would be returned directly only if allow plaintext objects is enabled
+func (s *EncryptedObjectStorage) Read(ctx core.Context, path string) (ObjectInStorage, error) {
+	object, err := s.objectStorage.Read(ctx, path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, len(encryptedObjectMagicHeader))
+	readBytes, err := io.ReadFull(object, header)
+
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		object.Close()
+		return nil, err
+	}
+
+	if readBytes < len(header) || string(header) != encryptedObjectMagicHeader {
+		if !s.allowPlaintextObjects {
+			object.Close()
+			return nil, errs.ErrStorageObjectNotEncrypted
+		}
+
+		if _, err = object.Seek(0, io.SeekStart); err != nil {
+			object.Close()
+			return nil, err
+		}
+
+		return object, nil
+	}
+
+	defer object.Close()
+
+	data, err := io.ReadAll(object)
+
+	if err != nil {
+		return nil, err
+	}
+
+	plainText, err := s.decrypt(path, append(header, data...))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return NewByteSliceObject(plainText), nil
+}
+
+// Save returns whether encrypt and save the object instance successfully
+func (s *EncryptedObjectStorage) Save(ctx core.Context, path string, object ObjectInStorage) error {
+	data, err := io.ReadAll(object)
+
+	if err != nil {
+		return err
+	}
+
+	encryptedData, err := s.encrypt(path, data)
+
+	if err != nil {
+		return err
+	}
+
+	return s.objectStorage.Save(ctx, path, NewByteSliceObject(encryptedData))
+}
+
+// Delete returns whether delete the object according to specified the file path successfully
+func (s *EncryptedObjectStorage) Delete(ctx core.Context, path string) error {
+	return s.objectStorage.Delete(ctx, path)
+}
+
+// Walk calls the specified function for each object in the underlying object storage
+func (s *EncryptedObjectStorage) Walk(ctx core.Context, fn ObjectWalkFunc) error {
+	return s.objectStorage.Walk(ctx, fn)
+}
+
+// EncryptExistingObject encrypts the object which is not encrypted in the underlying object storage in place
+func (s *EncryptedObjectStorage) EncryptExistingObject(ctx core.Context, path string, dryRun bool) (ObjectEncryptionResult, error) {
+	data, exists, err := s.readRawObject(ctx, path)
+
+	if err != nil {
+		return OBJECT_ENCRYPTION_RESULT_FAILED, err
+	} else if !exists {
+		return OBJECT_ENCRYPTION_RESULT_NOT_EXISTS, nil
+	}
+
+	if isEncryptedObjectData(data) {
+		return OBJECT_ENCRYPTION_RESULT_SKIPPED, nil
+	}
+
+	if dryRun {
+		return OBJECT_ENCRYPTION_RESULT_PENDING, nil
+	}
+
+	encryptedData, err := s.encrypt(path, data)
+
+	if err != nil {
+		return OBJECT_ENCRYPTION_RESULT_FAILED, err
+	}
+
+	return s.saveAndVerifyRawObject(ctx, path, encryptedData, data, OBJECT_ENCRYPTION_RESULT_ENCRYPTED)
+}
+
+// RotateObjectMasterKey re-wraps the data key of the object which is encrypted by the previous master key with the current master key,
+// the encrypted content would not be changed
+func (s *EncryptedObjectStorage) RotateObjectMasterKey(ctx core.Context, path string, dryRun bool) (ObjectEncryptionResult, error) {
+	data, exists, err := s.readRawObject(ctx, path)
+
+	if err != nil {
+		return OBJECT_ENCRYPTION_RESULT_FAILED, err
+	} else if !exists {
+		return OBJECT_ENCRYPTION_RESULT_NOT_EXISTS, nil
+	}
+
+	if !isEncryptedObjectData(data) {
+		return OBJECT_ENCRYPTION_RESULT_SKIPPED, nil
+	}
+
+	masterKeyId, wrappedDataKey, encryptedContent, err := parseEncryptedObjectData(data)
+
+	if err != nil {
+		return OBJECT_ENCRYPTION_RESULT_FAILED, err
+	}
+
+	if bytes.Equal(masterKeyId, s.masterKeyId) {
+		return OBJECT_ENCRYPTION_RESULT_SKIPPED, nil
+	}
+
+	masterKey, err := s.getMasterKey(masterKeyId)
+
+	if err != nil {
+		return OBJECT_ENCRYPTION_RESULT_FAILED, err
+	}
+
+	dataKey, err := utils.AESGCMDecryptWithAdditionalData(masterKey, wrappedDataKey, getWrappedDataKeyAdditionalData(masterKeyId, path))
+
+	if err != nil {
+		return OBJECT_ENCRYPTION_RESULT_FAILED, err
+	}
+
+	plainText, err := utils.AESGCMDecryptWithAdditionalData(dataKey, encryptedContent, getEncryptedContentAdditionalData(path))
+
+	if err != nil {
+		return OBJECT_ENCRYPTION_RESULT_FAILED, err
+	}
+
+	if dryRun {
+		return OBJECT_ENCRYPTION_RESULT_PENDING, nil
+	}
+
+	newWrappedDataKey, err := utils.AESGCMEncryptWithAdditionalData(s.masterKey, dataKey, getWrappedDataKeyAdditionalData(s.masterKeyId, path))
+
+	if err != nil {
+		return OBJECT_ENCRYPTION_RESULT_FAILED, err
+	}
+
+	return s.saveAndVerifyRawObject(ctx, path, buildEncryptedObjectData(s.masterKeyId, newWrappedDataKey, encryptedContent), plainText, OBJECT_ENCRYPTION_RESULT_ROTATED)
+}
+
+func (s *EncryptedObjectStorage) readRawObject(ctx core.Context, path string) ([]byte, bool, error) {
+	exists, err := s.objectStorage.Exists(ctx, path)
+
+	if err != nil || !exists {
+		return nil, false, err
+	}
+
+	object, err := s.objectStorage.Read(ctx, path)
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	defer object.Close()
+
+	data, err := io.ReadAll(object)
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	return data, true, nil
+}
+
+func (s *EncryptedObjectStorage) saveAndVerifyRawObject(ctx core.Context, path string, data []byte, expectedPlainText []byte, successResult ObjectEncryptionResult) (ObjectEncryptionResult, error) {
+	if err := s.objectStorage.Save(ctx, path, NewByteSliceObject(data)); err != nil {
+		return OBJECT_ENCRYPTION_RESULT_FAILED, err
+	}
+
+	savedData, _, err := s.readRawObject(ctx, path)
+
+	if err != nil {
+		return OBJECT_ENCRYPTION_RESULT_FAILED, err
+	}
+
+	plainText, err := s.decrypt(path, savedData)
+
+	if err != nil {
+		return OBJECT_ENCRYPTION_RESULT_FAILED, err
+	}
+
+	if !bytes.Equal(plainText, expectedPlainText) {
+		return OBJECT_ENCRYPTION_RESULT_FAILED, errs.ErrStorageObjectChecksumMismatch
+	}
+
+	return successResult, nil
+}
+
+func (s *EncryptedObjectStorage) encrypt(path string, plainText []byte) ([]byte, error) {
+	dataKey := make([]byte, encryptedObjectDataKeyLength)
+
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, err
+	}
+
+	wrappedDataKey, err := utils.AESGCMEncryptWithAdditionalData(s.masterKey, dataKey, getWrappedDataKeyAdditionalData(s.masterKeyId, path))
+
+	if err != nil {
+		return nil, err
+	}
+
+	encryptedContent, err := utils.AESGCMEncryptWithAdditionalData(dataKey, plainText, getEncryptedContentAdditionalData(path))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return buildEncryptedObjectData(s.masterKeyId, wrappedDataKey, encryptedContent), nil
+}
+
+func (s *EncryptedObjectStorage) decrypt(path string, data []byte) ([]byte, error) {
+	masterKeyId, wrappedDataKey, encryptedContent, err := parseEncryptedObjectData(data)
+
+	if err != nil {
+		return nil, err
+	}
+
+	masterKey, err := s.getMasterKey(masterKeyId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	dataKey, err := utils.AESGCMDecryptWithAdditionalData(masterKey, wrappedDataKey, getWrappedDataKeyAdditionalData(masterKeyId, path))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return utils.AESGCMDecryptWithAdditionalData(dataKey, encryptedContent, getEncryptedContentAdditionalData(path))
+}
+
+func (s *EncryptedObjectStorage) getMasterKey(masterKeyId []byte) ([]byte, error) {
+	if bytes.Equal(masterKeyId, s.masterKeyId) {
+		return s.masterKey, nil
+	} else if s.previousMasterKey != nil && bytes.Equal(masterKeyId, s.previousMasterKeyId) {
+		return s.previousMasterKey, nil
+	}
+
+	return nil, errs.ErrStorageEncryptionMasterKeyNotFound
+}
+
+// getKeyWrappingKeyAndMasterKeyId derives the key which is used to wrap data keys and the master key id from the master key,
+// the master key id is stored in plain text in the object header, so it is derived separately and cannot be used to verify the key wrapping key
+func getKeyWrappingKeyAndMasterKeyId(masterKey []byte) ([]byte, []byte, error) {
+	keyWrappingKey, err := hkdf.Key(sha256.New, masterKey, nil, encryptedObjectKeyWrappingKeyInfo, encryptedObjectDataKeyLength)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	masterKeyId, err := hkdf.Key(sha256.New, masterKey, nil, encryptedObjectMasterKeyIdInfo, encryptedObjectMasterKeyIdLength)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return keyWrappingKey, masterKeyId, nil
+}
+
+func getWrappedDataKeyAdditionalData(masterKeyId []byte, path string) []byte {
+	additionalData := make([]byte, 0, len(encryptedObjectMagicHeader)+len(masterKeyId)+len(path))
+	additionalData = append(additionalData, encryptedObjectMagicHeader...)
+	additionalData = append(additionalData, masterKeyId...)
+	additionalData = append(additionalData, path...)
+
+	return additionalData
+}
+
+func getEncryptedContentAdditionalData(path string) []byte {
+	additionalData := make([]byte, 0, len(encryptedObjectMagicHeader)+len(path))
+	additionalData = append(additionalData, encryptedObjectMagicHeader...)
+	additionalData = append(additionalData, path...)
+
+	return additionalData
+}
+
+func isEncryptedObjectData(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(encryptedObjectMagicHeader))
+}
+
+func buildEncryptedObjectData(masterKeyId []byte, wrappedDataKey []byte, encryptedContent []byte) []byte {
+	data := make([]byte, 0, encryptedObjectHeaderMinLength+len(wrappedDataKey)+len(encryptedContent))
+	data = append(data, encryptedObjectMagicHeader...)
+	data = append(data, masterKeyId...)
+	data = binary.BigEndian.AppendUint16(data, uint16(len(wrappedDataKey)))
+	data = append(data, wrappedDataKey...)
+	data = append(data, encryptedContent...)
+
+	return data
+}
+
+func parseEncryptedObjectData(data []byte) ([]byte, []byte, []byte, error) {
+	if len(data) < encryptedObjectHeaderMinLength || !isEncryptedObjectData(data) {
+		return nil, nil, nil, errs.ErrEncryptedStorageObjectInvalid
+	}
+
+	offset := len(encryptedObjectMagicHeader)
+	masterKeyId := data[offset : offset+encryptedObjectMasterKeyIdLength]
+	offset += encryptedObjectMasterKeyIdLength
+
+	wrappedDataKeyLength := int(binary.BigEndian.Uint16(data[offset:]))
+	offset += encryptedObjectWrappedKeyLenLength
+
+	if offset+wrappedDataKeyLength > len(data) {
+		return nil, nil, nil, errs.ErrEncryptedStorageObjectInvalid
+	}
+
+	wrappedDataKey := data[offset : offset+wrappedDataKeyLength]
+	encryptedContent := data[offset+wrappedDataKeyLength:]
+
+	return masterKeyId, wrappedDataKey, encryptedContent, nil
+}
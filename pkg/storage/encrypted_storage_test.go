@@ -0,0 +1,204 @@
+package storage
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+var testMasterKey = bytes.Repeat([]byte{0x01}, StorageEncryptionMasterKeyLength)
+var testNewMasterKey = bytes.Repeat([]byte{0x02}, StorageEncryptionMasterKeyLength)
+
+func TestEncryptedObjectStorage_SaveAndRead(t *testing.T) {
+	context := core.NewNullContext()
+	rawStorage := createTestLocalFileSystemObjectStorage(t)
+	encryptedStorage := createTestEncryptedObjectStorage(t, rawStorage, testMasterKey, nil, false)
+
+	err := encryptedStorage.Save(context, "1/100.jpg", NewByteSliceObject([]byte("card number 4111111111111111")))
+	assert.Nil(t, err)
+
+	rawData := []byte(readTestObject(t, rawStorage, "1/100.jpg"))
+	assert.True(t, isEncryptedObjectData(rawData))
+	assert.False(t, bytes.Contains(rawData, []byte("4111111111111111")))
+
+	assert.Equal(t, "card number 4111111111111111", readTestObject(t, encryptedStorage, "1/100.jpg"))
+}
+
+func TestEncryptedObjectStorage_ReadNotEncryptedObject(t *testing.T) {
+	context := core.NewNullContext()
+	rawStorage := createTestLocalFileSystemObjectStorage(t)
+
+	err := rawStorage.Save(context, "1/100.jpg", NewByteSliceObject([]byte("plain data")))
+	assert.Nil(t, err)
+
+	_, err = createTestEncryptedObjectStorage(t, rawStorage, testMasterKey, nil, false).Read(context, "1/100.jpg")
+	assert.Equal(t, errs.ErrStorageObjectNotEncrypted, err)
+}
+
+func TestEncryptedObjectStorage_ReadNotEncryptedObjectWhenAllowPlaintextObjects(t *testing.T) {
+	context := core.NewNullContext()
+	rawStorage := createTestLocalFileSystemObjectStorage(t)
+	encryptedStorage := createTestEncryptedObjectStorage(t, rawStorage, testMasterKey, nil, true)
+
+	err := rawStorage.Save(context, "1/100.jpg", NewByteSliceObject([]byte("plain data")))
+	assert.Nil(t, err)
+	assert.Equal(t, "plain data", readTestObject(t, encryptedStorage, "1/100.jpg"))
+
+	err = rawStorage.Save(context, "1/101.jpg", NewByteSliceObject([]byte("tiny")))
+	assert.Nil(t, err)
+	assert.Equal(t, "tiny", readTestObject(t, encryptedStorage, "1/101.jpg"))
+}
+
+func TestEncryptedObjectStorage_ReadObjectMovedToAnotherPath(t *testing.T) {
+	context := core.NewNullContext()
+	rawStorage := createTestLocalFileSystemObjectStorage(t)
+	encryptedStorage := createTestEncryptedObjectStorage(t, rawStorage, testMasterKey, nil, false)
+
+	err := encryptedStorage.Save(context, "1/100.jpg", NewByteSliceObject([]byte("data")))
+	assert.Nil(t, err)
+
+	err = rawStorage.Save(context, "2/200.jpg", NewByteSliceObject([]byte(readTestObject(t, rawStorage, "1/100.jpg"))))
+	assert.Nil(t, err)
+
+	_, err = encryptedStorage.Read(context, "2/200.jpg")
+	assert.NotNil(t, err)
+}
+
+func TestNewEncryptedObjectStorage_InvalidMasterKey(t *testing.T) {
+	rawStorage := createTestLocalFileSystemObjectStorage(t)
+
+	_, err := NewEncryptedObjectStorage(rawStorage, []byte("master-key"), nil, false)
+	assert.Equal(t, errs.ErrInvalidStorageEncryptionMasterKey, err)
+
+	_, err = NewEncryptedObjectStorage(rawStorage, testMasterKey, []byte("previous-master-key"), false)
+	assert.Equal(t, errs.ErrInvalidStorageEncryptionMasterKey, err)
+}
+
+func TestGetKeyWrappingKeyAndMasterKeyId(t *testing.T) {
+	keyWrappingKey, masterKeyId, err := getKeyWrappingKeyAndMasterKeyId(testMasterKey)
+	assert.Nil(t, err)
+	assert.Equal(t, encryptedObjectDataKeyLength, len(keyWrappingKey))
+	assert.Equal(t, encryptedObjectMasterKeyIdLength, len(masterKeyId))
+	assert.NotEqual(t, testMasterKey, keyWrappingKey)
+	assert.False(t, bytes.HasPrefix(keyWrappingKey, masterKeyId))
+}
+
+func TestEncryptedObjectStorage_ReadWithWrongMasterKey(t *testing.T) {
+	context := core.NewNullContext()
+	rawStorage := createTestLocalFileSystemObjectStorage(t)
+
+	err := createTestEncryptedObjectStorage(t, rawStorage, testMasterKey, nil, false).Save(context, "1/100.jpg", NewByteSliceObject([]byte("data")))
+	assert.Nil(t, err)
+
+	_, err = createTestEncryptedObjectStorage(t, rawStorage, testNewMasterKey, nil, false).Read(context, "1/100.jpg")
+	assert.Equal(t, errs.ErrStorageEncryptionMasterKeyNotFound, err)
+}
+
+func TestEncryptedObjectStorage_EncryptExistingObject(t *testing.T) {
+	context := core.NewNullContext()
+	rawStorage := createTestLocalFileSystemObjectStorage(t)
+	encryptedStorage := createTestEncryptedObjectStorage(t, rawStorage, testMasterKey, nil, false)
+
+	err := rawStorage.Save(context, "1/100.jpg", NewByteSliceObject([]byte("plain data")))
+	assert.Nil(t, err)
+
+	result, err := encryptedStorage.EncryptExistingObject(context, "1/100.jpg", true)
+	assert.Nil(t, err)
+	assert.Equal(t, OBJECT_ENCRYPTION_RESULT_PENDING, result)
+	assert.Equal(t, "plain data", readTestObject(t, rawStorage, "1/100.jpg"))
+
+	result, err = encryptedStorage.EncryptExistingObject(context, "1/100.jpg", false)
+	assert.Nil(t, err)
+	assert.Equal(t, OBJECT_ENCRYPTION_RESULT_ENCRYPTED, result)
+	assert.True(t, isEncryptedObjectData([]byte(readTestObject(t, rawStorage, "1/100.jpg"))))
+	assert.Equal(t, "plain data", readTestObject(t, encryptedStorage, "1/100.jpg"))
+
+	result, err = encryptedStorage.EncryptExistingObject(context, "1/100.jpg", false)
+	assert.Nil(t, err)
+	assert.Equal(t, OBJECT_ENCRYPTION_RESULT_SKIPPED, result)
+
+	result, err = encryptedStorage.EncryptExistingObject(context, "1/200.jpg", false)
+	assert.Nil(t, err)
+	assert.Equal(t, OBJECT_ENCRYPTION_RESULT_NOT_EXISTS, result)
+}
+
+func TestEncryptedObjectStorage_RotateObjectMasterKey(t *testing.T) {
+	context := core.NewNullContext()
+	rawStorage := createTestLocalFileSystemObjectStorage(t)
+
+	err := createTestEncryptedObjectStorage(t, rawStorage, testMasterKey, nil, false).Save(context, "1/100.jpg", NewByteSliceObject([]byte("data")))
+	assert.Nil(t, err)
+
+	encryptedStorage := createTestEncryptedObjectStorage(t, rawStorage, testNewMasterKey, testMasterKey, false)
+	assert.Equal(t, "data", readTestObject(t, encryptedStorage, "1/100.jpg"))
+
+	oldRawData := []byte(readTestObject(t, rawStorage, "1/100.jpg"))
+
+	result, err := encryptedStorage.RotateObjectMasterKey(context, "1/100.jpg", true)
+	assert.Nil(t, err)
+	assert.Equal(t, OBJECT_ENCRYPTION_RESULT_PENDING, result)
+
+	result, err = encryptedStorage.RotateObjectMasterKey(context, "1/100.jpg", false)
+	assert.Nil(t, err)
+	assert.Equal(t, OBJECT_ENCRYPTION_RESULT_ROTATED, result)
+
+	newRawData := []byte(readTestObject(t, rawStorage, "1/100.jpg"))
+	_, _, oldEncryptedContent, err := parseEncryptedObjectData(oldRawData)
+	assert.Nil(t, err)
+	_, _, newEncryptedContent, err := parseEncryptedObjectData(newRawData)
+	assert.Nil(t, err)
+	assert.Equal(t, oldEncryptedContent, newEncryptedContent)
+
+	assert.Equal(t, "data", readTestObject(t, createTestEncryptedObjectStorage(t, rawStorage, testNewMasterKey, nil, false), "1/100.jpg"))
+
+	result, err = encryptedStorage.RotateObjectMasterKey(context, "1/100.jpg", false)
+	assert.Nil(t, err)
+	assert.Equal(t, OBJECT_ENCRYPTION_RESULT_SKIPPED, result)
+}
+
+func TestEncryptedObjectStorage_MigrateObjectToEncryptedStorage(t *testing.T) {
+	context := core.NewNullContext()
+	from, to := createTestLocalFileSystemObjectStorages(t)
+	encryptedStorage := createTestEncryptedObjectStorage(t, to, testMasterKey, nil, false)
+
+	err := from.Save(context, "1/100.jpg", NewByteSliceObject([]byte("picture data")))
+	assert.Nil(t, err)
+
+	result, err := MigrateObject(context, from, encryptedStorage, "1/100.jpg", false)
+	assert.Nil(t, err)
+	assert.Equal(t, OBJECT_MIGRATION_RESULT_MIGRATED, result)
+	assert.True(t, isEncryptedObjectData([]byte(readTestObject(t, to, "1/100.jpg"))))
+
+	result, err = MigrateObject(context, from, encryptedStorage, "1/100.jpg", false)
+	assert.Nil(t, err)
+	assert.Equal(t, OBJECT_MIGRATION_RESULT_ALREADY_MIGRATED, result)
+}
+
+func TestParseEncryptedObjectData_InvalidData(t *testing.T) {
+	_, _, _, err := parseEncryptedObjectData([]byte(encryptedObjectMagicHeader))
+	assert.Equal(t, errs.ErrEncryptedStorageObjectInvalid, err)
+
+	data := append([]byte(encryptedObjectMagicHeader), make([]byte, encryptedObjectMasterKeyIdLength)...)
+	data = append(data, 0xFF, 0xFF)
+	_, _, _, err = parseEncryptedObjectData(data)
+	assert.Equal(t, errs.ErrEncryptedStorageObjectInvalid, err)
+}
+
+func createTestEncryptedObjectStorage(t *testing.T, objectStorage ObjectStorage, masterKey []byte, previousMasterKey []byte, allowPlaintextObjects bool) *EncryptedObjectStorage {
+	encryptedStorage, err := NewEncryptedObjectStorage(objectStorage, masterKey, previousMasterKey, allowPlaintextObjects)
+	assert.Nil(t, err)
+
+	return encryptedStorage
+}
+
+func createTestLocalFileSystemObjectStorage(t *testing.T) ObjectStorage {
+	objectStorage, err := NewLocalFileSystemObjectStorage(&settings.Config{LocalFileSystemPath: t.TempDir()}, "transaction")
+	assert.Nil(t, err)
+
+	return objectStorage
+}
@@ -2,6 +2,7 @@ package storage
 
 import (
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
 
@@ -64,6 +65,36 @@ func (s *LocalFileSystemObjectStorage) Delete(ctx core.Context, path string) err
 	return os.Remove(s.getFinalPath(path))
 }
 
+// Walk calls the specified function for each object in the object storage
+func (s *LocalFileSystemObjectStorage) Walk(ctx core.Context, fn ObjectWalkFunc) error {
+	return filepath.WalkDir(s.rootPath, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			return nil
+		}
+
+		fileInfo, err := entry.Info()
+
+		if err != nil {
+			return err
+		}
+
+		relativePath, err := filepath.Rel(s.rootPath, path)
+
+		if err != nil {
+			return err
+		}
+
+		return fn(&ObjectInfo{
+			Path:                 filepath.ToSlash(relativePath),
+			LastModifiedUnixTime: fileInfo.ModTime().Unix(),
+		})
+	})
+}
+
 func (s *LocalFileSystemObjectStorage) getFinalPath(path string) string {
 	return filepath.Join(s.rootPath, path)
 }
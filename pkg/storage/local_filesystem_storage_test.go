@@ -0,0 +1,46 @@
+package storage
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+)
+
+func TestLocalFileSystemObjectStorage_Walk(t *testing.T) {
+	context := core.NewNullContext()
+	objectStorage := createTestLocalFileSystemObjectStorage(t)
+
+	err := objectStorage.Save(context, "1/100.jpg", NewByteSliceObject([]byte("picture data")))
+	assert.Nil(t, err)
+	err = objectStorage.Save(context, "1/100.small.jpg", NewByteSliceObject([]byte("thumbnail data")))
+	assert.Nil(t, err)
+	err = objectStorage.Save(context, "2/200.png", NewByteSliceObject([]byte("picture data")))
+	assert.Nil(t, err)
+
+	objectInfos := walkTestObjectStorage(t, objectStorage)
+	assert.Equal(t, 3, len(objectInfos))
+	assert.Equal(t, "1/100.jpg", objectInfos[0].Path)
+	assert.Equal(t, "1/100.small.jpg", objectInfos[1].Path)
+	assert.Equal(t, "2/200.png", objectInfos[2].Path)
+	assert.InDelta(t, time.Now().Unix(), objectInfos[0].LastModifiedUnixTime, 60)
+}
+
+func walkTestObjectStorage(t *testing.T, objectStorage ObjectStorage) []*ObjectInfo {
+	var objectInfos []*ObjectInfo
+
+	err := objectStorage.Walk(core.NewNullContext(), func(objectInfo *ObjectInfo) error {
+		objectInfos = append(objectInfos, objectInfo)
+		return nil
+	})
+	assert.Nil(t, err)
+
+	sort.Slice(objectInfos, func(i, j int) bool {
+		return objectInfos[i].Path < objectInfos[j].Path
+	})
+
+	return objectInfos
+}
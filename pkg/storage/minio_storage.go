@@ -92,6 +92,39 @@ func (s *MinIOObjectStorage) Delete(ctx core.Context, path string) error {
 	return s.minIOClient.RemoveObject(ctx, s.minIOConfig.Bucket, s.getFinalPath(path), minio.RemoveObjectOptions{})
 }
 
+// Walk calls the specified function for each object in the object storage
+func (s *MinIOObjectStorage) Walk(ctx core.Context, fn ObjectWalkFunc) error {
+	prefix := s.getFinalPath("")
+	listCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	objects := s.minIOClient.ListObjects(listCtx, s.minIOConfig.Bucket, minio.ListObjectsOptions{
+		Prefix:    prefix,
+		Recursive: true,
+	})
+
+	for objectInfo := range objects {
+		if objectInfo.Err != nil {
+			return objectInfo.Err
+		}
+
+		if objectInfo.IsDeleteMarker || !strings.HasPrefix(objectInfo.Key, prefix) || strings.HasSuffix(objectInfo.Key, "/") {
+			continue
+		}
+
+		err := fn(&ObjectInfo{
+			Path:                 objectInfo.Key[len(prefix):],
+			LastModifiedUnixTime: objectInfo.LastModified.Unix(),
+		})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (s *MinIOObjectStorage) getFinalPath(path string) string {
 	rootPath := s.rootPath
 
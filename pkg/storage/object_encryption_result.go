@@ -0,0 +1,71 @@
+package storage
+
+// ObjectEncryptionResult represents the result of encrypting an object or rotating the master key of an object in object storage
+type ObjectEncryptionResult byte
+
+// Object encryption results
+const (
+	OBJECT_ENCRYPTION_RESULT_FAILED     ObjectEncryptionResult = 0
+	OBJECT_ENCRYPTION_RESULT_ENCRYPTED  ObjectEncryptionResult = 1
+	OBJECT_ENCRYPTION_RESULT_SKIPPED    ObjectEncryptionResult = 2
+	OBJECT_ENCRYPTION_RESULT_PENDING    ObjectEncryptionResult = 3
+	OBJECT_ENCRYPTION_RESULT_NOT_EXISTS ObjectEncryptionResult = 4
+	OBJECT_ENCRYPTION_RESULT_ROTATED    ObjectEncryptionResult = 5
+)
+
+// ObjectEncryptionSummary represents the summary of encrypting objects in object storage
+type ObjectEncryptionSummary struct {
+	TotalCount     int
+	EncryptedCount int
+	RotatedCount   int
+	SkippedCount   int
+	PendingCount   int
+	NotExistsCount int
+	FailedCount    int
+}
+
+// StorageEncryptionSummary represents the summary of encrypting all types of objects in object storage container
+type StorageEncryptionSummary struct {
+	Avatar                ObjectEncryptionSummary
+	TransactionPicture    ObjectEncryptionSummary
+	TransactionAttachment ObjectEncryptionSummary
+}
+
+// Add counts the specified object encryption result into the summary
+func (s *ObjectEncryptionSummary) Add(result ObjectEncryptionResult) {
+	s.TotalCount++
+
+	switch result {
+	case OBJECT_ENCRYPTION_RESULT_ENCRYPTED:
+		s.EncryptedCount++
+	case OBJECT_ENCRYPTION_RESULT_ROTATED:
+		s.RotatedCount++
+	case OBJECT_ENCRYPTION_RESULT_SKIPPED:
+		s.SkippedCount++
+	case OBJECT_ENCRYPTION_RESULT_PENDING:
+		s.PendingCount++
+	case OBJECT_ENCRYPTION_RESULT_NOT_EXISTS:
+		s.NotExistsCount++
+	default:
+		s.FailedCount++
+	}
+}
+
+// Get returns the summary of the specified object type
+func (s *StorageEncryptionSummary) Get(objectType StorageObjectType) *ObjectEncryptionSummary {
+	switch objectType {
+	case STORAGE_OBJECT_TYPE_AVATAR:
+		return &s.Avatar
+	case STORAGE_OBJECT_TYPE_TRANSACTION_PICTURE:
+		return &s.TransactionPicture
+	case STORAGE_OBJECT_TYPE_TRANSACTION_ATTACHMENT:
+		return &s.TransactionAttachment
+	default:
+		return nil
+	}
+}
+
+// HasFailed returns whether any object failed to encrypt
+func (s *StorageEncryptionSummary) HasFailed() bool {
+	return s.Avatar.FailedCount > 0 || s.TransactionPicture.FailedCount > 0 || s.TransactionAttachment.FailedCount > 0
+}
@@ -0,0 +1,37 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObjectEncryptionSummaryAdd(t *testing.T) {
+	summary := &ObjectEncryptionSummary{}
+	summary.Add(OBJECT_ENCRYPTION_RESULT_ENCRYPTED)
+	summary.Add(OBJECT_ENCRYPTION_RESULT_ENCRYPTED)
+	summary.Add(OBJECT_ENCRYPTION_RESULT_ROTATED)
+	summary.Add(OBJECT_ENCRYPTION_RESULT_SKIPPED)
+	summary.Add(OBJECT_ENCRYPTION_RESULT_PENDING)
+	summary.Add(OBJECT_ENCRYPTION_RESULT_NOT_EXISTS)
+	summary.Add(OBJECT_ENCRYPTION_RESULT_FAILED)
+
+	assert.Equal(t, 7, summary.TotalCount)
+	assert.Equal(t, 2, summary.EncryptedCount)
+	assert.Equal(t, 1, summary.RotatedCount)
+	assert.Equal(t, 1, summary.SkippedCount)
+	assert.Equal(t, 1, summary.PendingCount)
+	assert.Equal(t, 1, summary.NotExistsCount)
+	assert.Equal(t, 1, summary.FailedCount)
+}
+
+func TestStorageEncryptionSummaryHasFailed(t *testing.T) {
+	summary := &StorageEncryptionSummary{}
+	summary.Get(STORAGE_OBJECT_TYPE_AVATAR).Add(OBJECT_ENCRYPTION_RESULT_ENCRYPTED)
+	summary.Get(STORAGE_OBJECT_TYPE_TRANSACTION_PICTURE).Add(OBJECT_ENCRYPTION_RESULT_SKIPPED)
+	assert.False(t, summary.HasFailed())
+
+	summary.Get(STORAGE_OBJECT_TYPE_TRANSACTION_ATTACHMENT).Add(OBJECT_ENCRYPTION_RESULT_FAILED)
+	assert.True(t, summary.HasFailed())
+	assert.Equal(t, 1, summary.TransactionAttachment.FailedCount)
+}
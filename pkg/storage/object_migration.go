@@ -0,0 +1,149 @@
+package storage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+)
+
+// ObjectMigrationResult represents the result of migrating an object between two object storages
+type ObjectMigrationResult byte
+
+// Object migration results
+const (
+	OBJECT_MIGRATION_RESULT_FAILED            ObjectMigrationResult = 0
+	OBJECT_MIGRATION_RESULT_MIGRATED          ObjectMigrationResult = 1
+	OBJECT_MIGRATION_RESULT_ALREADY_MIGRATED  ObjectMigrationResult = 2
+	OBJECT_MIGRATION_RESULT_PENDING           ObjectMigrationResult = 3
+	OBJECT_MIGRATION_RESULT_SOURCE_NOT_EXISTS ObjectMigrationResult = 4
+)
+
+// ObjectMigrationSummary represents the summary of migrating objects between two object storages
+type ObjectMigrationSummary struct {
+	TotalCount           int
+	MigratedCount        int
+	AlreadyMigratedCount int
+	PendingCount         int
+	SourceNotExistsCount int
+	FailedCount          int
+}
+
+// StorageMigrationSummary represents the summary of migrating all kinds of objects between two object storage containers
+type StorageMigrationSummary struct {
+	Avatar                ObjectMigrationSummary
+	TransactionPicture    ObjectMigrationSummary
+	TransactionAttachment ObjectMigrationSummary
+}
+
+// Add counts the specified object migration result into the summary
+func (s *ObjectMigrationSummary) Add(result ObjectMigrationResult) {
+	s.TotalCount++
+
+	switch result {
+	case OBJECT_MIGRATION_RESULT_MIGRATED:
+		s.MigratedCount++
+	case OBJECT_MIGRATION_RESULT_ALREADY_MIGRATED:
+		s.AlreadyMigratedCount++
+	case OBJECT_MIGRATION_RESULT_PENDING:
+		s.PendingCount++
+	case OBJECT_MIGRATION_RESULT_SOURCE_NOT_EXISTS:
+		s.SourceNotExistsCount++
+	default:
+		s.FailedCount++
+	}
+}
+
+// MigrateObject copies the object in the specified path from the source storage to the target storage and verifies the checksum of the copied object,
+// the object which already exists in the target storage with the same checksum would be skipped, so the migration can be resumed after interruption,
+// and the target storage would not be changed if dry run is enabled
+func MigrateObject(ctx core.Context, from ObjectStorage, to ObjectStorage, path string, dryRun bool) (ObjectMigrationResult, error) {
+	exists, err := from.Exists(ctx, path)
+
+	if err != nil {
+		return OBJECT_MIGRATION_RESULT_FAILED, err
+	} else if !exists {
+		return OBJECT_MIGRATION_RESULT_SOURCE_NOT_EXISTS, nil
+	}
+
+	sourceObject, err := from.Read(ctx, path)
+
+	if err != nil {
+		return OBJECT_MIGRATION_RESULT_FAILED, err
+	}
+
+	defer sourceObject.Close()
+
+	sourceChecksum, err := getObjectChecksum(sourceObject)
+
+	if err != nil {
+		return OBJECT_MIGRATION_RESULT_FAILED, err
+	}
+
+	targetChecksum, err := getObjectChecksumInStorage(ctx, to, path)
+
+	if err != nil {
+		return OBJECT_MIGRATION_RESULT_FAILED, err
+	}
+
+	if targetChecksum == sourceChecksum {
+		return OBJECT_MIGRATION_RESULT_ALREADY_MIGRATED, nil
+	}
+
+	if dryRun {
+		return OBJECT_MIGRATION_RESULT_PENDING, nil
+	}
+
+	if _, err = sourceObject.Seek(0, io.SeekStart); err != nil {
+		return OBJECT_MIGRATION_RESULT_FAILED, err
+	}
+
+	if err = to.Save(ctx, path, sourceObject); err != nil {
+		return OBJECT_MIGRATION_RESULT_FAILED, err
+	}
+
+	targetChecksum, err = getObjectChecksumInStorage(ctx, to, path)
+
+	if err != nil {
+		return OBJECT_MIGRATION_RESULT_FAILED, err
+	}
+
+	if targetChecksum != sourceChecksum {
+		return OBJECT_MIGRATION_RESULT_FAILED, errs.ErrStorageObjectChecksumMismatch
+	}
+
+	return OBJECT_MIGRATION_RESULT_MIGRATED, nil
+}
+
+// getObjectChecksumInStorage returns the checksum of the object in the specified path, or returns empty string if the object does not exist
+func getObjectChecksumInStorage(ctx core.Context, objectStorage ObjectStorage, path string) (string, error) {
+	exists, err := objectStorage.Exists(ctx, path)
+
+	if err != nil {
+		return "", err
+	} else if !exists {
+		return "", nil
+	}
+
+	object, err := objectStorage.Read(ctx, path)
+
+	if err != nil {
+		return "", err
+	}
+
+	defer object.Close()
+
+	return getObjectChecksum(object)
+}
+
+func getObjectChecksum(object io.Reader) (string, error) {
+	hash := sha256.New()
+
+	if _, err := io.Copy(hash, object); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,135 @@
+package storage
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+func TestMigrateObject(t *testing.T) {
+	context := core.NewNullContext()
+	from, to := createTestLocalFileSystemObjectStorages(t)
+
+	err := from.Save(context, "1/100.jpg", NewByteSliceObject([]byte("picture data")))
+	assert.Nil(t, err)
+
+	result, err := MigrateObject(context, from, to, "1/100.jpg", false)
+	assert.Nil(t, err)
+	assert.Equal(t, OBJECT_MIGRATION_RESULT_MIGRATED, result)
+	assert.Equal(t, "picture data", readTestObject(t, to, "1/100.jpg"))
+
+	result, err = MigrateObject(context, from, to, "1/100.jpg", false)
+	assert.Nil(t, err)
+	assert.Equal(t, OBJECT_MIGRATION_RESULT_ALREADY_MIGRATED, result)
+}
+
+func TestMigrateObject_DryRun(t *testing.T) {
+	context := core.NewNullContext()
+	from, to := createTestLocalFileSystemObjectStorages(t)
+
+	err := from.Save(context, "1/100.jpg", NewByteSliceObject([]byte("picture data")))
+	assert.Nil(t, err)
+
+	result, err := MigrateObject(context, from, to, "1/100.jpg", true)
+	assert.Nil(t, err)
+	assert.Equal(t, OBJECT_MIGRATION_RESULT_PENDING, result)
+
+	exists, err := to.Exists(context, "1/100.jpg")
+	assert.Nil(t, err)
+	assert.False(t, exists)
+}
+
+func TestMigrateObject_OverwriteDifferentTargetObject(t *testing.T) {
+	context := core.NewNullContext()
+	from, to := createTestLocalFileSystemObjectStorages(t)
+
+	err := from.Save(context, "1/100.jpg", NewByteSliceObject([]byte("picture data")))
+	assert.Nil(t, err)
+
+	err = to.Save(context, "1/100.jpg", NewByteSliceObject([]byte("partial")))
+	assert.Nil(t, err)
+
+	result, err := MigrateObject(context, from, to, "1/100.jpg", false)
+	assert.Nil(t, err)
+	assert.Equal(t, OBJECT_MIGRATION_RESULT_MIGRATED, result)
+	assert.Equal(t, "picture data", readTestObject(t, to, "1/100.jpg"))
+}
+
+func TestMigrateObject_SourceNotExists(t *testing.T) {
+	context := core.NewNullContext()
+	from, to := createTestLocalFileSystemObjectStorages(t)
+
+	result, err := MigrateObject(context, from, to, "1/100.jpg", false)
+	assert.Nil(t, err)
+	assert.Equal(t, OBJECT_MIGRATION_RESULT_SOURCE_NOT_EXISTS, result)
+}
+
+func TestMigrateObject_ChecksumMismatch(t *testing.T) {
+	context := core.NewNullContext()
+	from, to := createTestLocalFileSystemObjectStorages(t)
+
+	err := from.Save(context, "1/100.jpg", NewByteSliceObject([]byte("picture data")))
+	assert.Nil(t, err)
+
+	result, err := MigrateObject(context, from, &truncatedObjectStorage{ObjectStorage: to}, "1/100.jpg", false)
+	assert.Equal(t, errs.ErrStorageObjectChecksumMismatch, err)
+	assert.Equal(t, OBJECT_MIGRATION_RESULT_FAILED, result)
+}
+
+func TestObjectMigrationSummaryAdd(t *testing.T) {
+	summary := &ObjectMigrationSummary{}
+	summary.Add(OBJECT_MIGRATION_RESULT_MIGRATED)
+	summary.Add(OBJECT_MIGRATION_RESULT_MIGRATED)
+	summary.Add(OBJECT_MIGRATION_RESULT_ALREADY_MIGRATED)
+	summary.Add(OBJECT_MIGRATION_RESULT_PENDING)
+	summary.Add(OBJECT_MIGRATION_RESULT_SOURCE_NOT_EXISTS)
+	summary.Add(OBJECT_MIGRATION_RESULT_FAILED)
+
+	assert.Equal(t, 6, summary.TotalCount)
+	assert.Equal(t, 2, summary.MigratedCount)
+	assert.Equal(t, 1, summary.AlreadyMigratedCount)
+	assert.Equal(t, 1, summary.PendingCount)
+	assert.Equal(t, 1, summary.SourceNotExistsCount)
+	assert.Equal(t, 1, summary.FailedCount)
+}
+
+type truncatedObjectStorage struct {
+	ObjectStorage
+}
+
+func (s *truncatedObjectStorage) Save(ctx core.Context, path string, object ObjectInStorage) error {
+	data, err := io.ReadAll(object)
+
+	if err != nil {
+		return err
+	}
+
+	return s.ObjectStorage.Save(ctx, path, NewByteSliceObject(data[:len(data)/2]))
+}
+
+func createTestLocalFileSystemObjectStorages(t *testing.T) (ObjectStorage, ObjectStorage) {
+	from, err := NewLocalFileSystemObjectStorage(&settings.Config{LocalFileSystemPath: t.TempDir()}, "transaction")
+	assert.Nil(t, err)
+
+	to, err := NewLocalFileSystemObjectStorage(&settings.Config{LocalFileSystemPath: t.TempDir()}, "transaction")
+	assert.Nil(t, err)
+
+	return from, to
+}
+
+func readTestObject(t *testing.T, objectStorage ObjectStorage, path string) string {
+	object, err := objectStorage.Read(core.NewNullContext(), path)
+	assert.Nil(t, err)
+
+	defer object.Close()
+
+	data, err := io.ReadAll(object)
+	assert.Nil(t, err)
+
+	return string(data)
+}
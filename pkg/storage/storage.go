@@ -8,4 +8,15 @@ type ObjectStorage interface {
 	Read(ctx core.Context, path string) (ObjectInStorage, error)
 	Save(ctx core.Context, path string, object ObjectInStorage) error
 	Delete(ctx core.Context, path string) error
+	Walk(ctx core.Context, fn ObjectWalkFunc) error
 }
+
+// ObjectInfo represents the information of an object in object storage
+type ObjectInfo struct {
+	Path                 string
+	LastModifiedUnixTime int64
+}
+
+// ObjectWalkFunc represents the function which is called for each object when walking the object storage,
+// the path of object is the same as the path used in other methods of object storage
+type ObjectWalkFunc func(objectInfo *ObjectInfo) error
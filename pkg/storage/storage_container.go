@@ -8,11 +8,50 @@ import (
 
 const avatarPathPrefix = "avatar"
 const transactionPicturePathPrefix = "transaction"
+const transactionAttachmentPathPrefix = "attachment"
+
+// StorageObjectType represents the type of objects in object storage
+type StorageObjectType byte
+
+// Storage object types
+const (
+	STORAGE_OBJECT_TYPE_AVATAR                 StorageObjectType = 1
+	STORAGE_OBJECT_TYPE_TRANSACTION_PICTURE    StorageObjectType = 2
+	STORAGE_OBJECT_TYPE_TRANSACTION_ATTACHMENT StorageObjectType = 3
+)
+
+// AllStorageObjectTypes contains all storage object types
+var AllStorageObjectTypes = []StorageObjectType{
+	STORAGE_OBJECT_TYPE_AVATAR,
+	STORAGE_OBJECT_TYPE_TRANSACTION_PICTURE,
+	STORAGE_OBJECT_TYPE_TRANSACTION_ATTACHMENT,
+}
+
+// String returns a textual representation of the storage object type
+func (t StorageObjectType) String() string {
+	switch t {
+	case STORAGE_OBJECT_TYPE_AVATAR:
+		return "Avatar"
+	case STORAGE_OBJECT_TYPE_TRANSACTION_PICTURE:
+		return "TransactionPicture"
+	case STORAGE_OBJECT_TYPE_TRANSACTION_ATTACHMENT:
+		return "TransactionAttachment"
+	default:
+		return "Unknown"
+	}
+}
+
+// StorageObjectPath represents the path of an object in object storage
+type StorageObjectPath struct {
+	Path     string
+	Optional bool
+}
 
 // StorageContainer contains the current object storage
 type StorageContainer struct {
-	avatarCurrentStorage             ObjectStorage
-	transactionPictureCurrentStorage ObjectStorage
+	avatarCurrentStorage                ObjectStorage
+	transactionPictureCurrentStorage    ObjectStorage
+	transactionAttachmentCurrentStorage ObjectStorage
 }
 
 // Initialize a object storage container singleton instance
@@ -22,27 +61,54 @@ var (
 
 // InitializeStorageContainer initializes the current object storage according to the config
 func InitializeStorageContainer(config *settings.Config) error {
+	container, err := NewStorageContainer(config, config.StorageType)
+
+	if err != nil {
+		return err
+	}
+
+	Container.avatarCurrentStorage = container.avatarCurrentStorage
+	Container.transactionPictureCurrentStorage = container.transactionPictureCurrentStorage
+	Container.transactionAttachmentCurrentStorage = container.transactionAttachmentCurrentStorage
+
+	return nil
+}
+
+// NewStorageContainer returns a new object storage container which uses the specified storage type and the storage settings in the config
+func NewStorageContainer(config *settings.Config, storageType string) (*StorageContainer, error) {
+	container := &StorageContainer{}
+
 	if config.AvatarProvider == core.USER_AVATAR_PROVIDER_INTERNAL {
-		avatarStorage, err := newObjectStorage(config, avatarPathPrefix)
+		avatarStorage, err := newObjectStorage(config, storageType, avatarPathPrefix)
 
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		Container.avatarCurrentStorage = avatarStorage
+		container.avatarCurrentStorage = avatarStorage
 	}
 
 	if config.EnableTransactionPictures {
-		transactionPictureStorage, err := newObjectStorage(config, transactionPicturePathPrefix)
+		transactionPictureStorage, err := newObjectStorage(config, storageType, transactionPicturePathPrefix)
 
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		Container.transactionPictureCurrentStorage = transactionPictureStorage
+		container.transactionPictureCurrentStorage = transactionPictureStorage
 	}
 
-	return nil
+	if config.EnableTransactionAttachments {
+		transactionAttachmentStorage, err := newObjectStorage(config, storageType, transactionAttachmentPathPrefix)
+
+		if err != nil {
+			return nil, err
+		}
+
+		container.transactionAttachmentCurrentStorage = transactionAttachmentStorage
+	}
+
+	return container, nil
 }
 
 // ExistsAvatar returns whether the avatar file exists from the current avatar object storage
@@ -117,14 +183,86 @@ func (s *StorageContainer) DeleteTransactionPicture(ctx core.Context, path strin
 	return s.transactionPictureCurrentStorage.Delete(ctx, path)
 }
 
-func newObjectStorage(config *settings.Config, pathPrefix string) (ObjectStorage, error) {
-	if config.StorageType == settings.LocalFileSystemObjectStorageType {
-		return NewLocalFileSystemObjectStorage(config, pathPrefix)
-	} else if config.StorageType == settings.MinIOStorageType {
-		return NewMinIOObjectStorage(config, pathPrefix)
-	} else if config.StorageType == settings.WebDAVStorageType {
-		return NewWebDAVObjectStorage(config, pathPrefix)
+// WalkTransactionPictures calls the specified function for each file in the current transaction picture object storage
+func (s *StorageContainer) WalkTransactionPictures(ctx core.Context, fn ObjectWalkFunc) error {
+	if s.transactionPictureCurrentStorage == nil {
+		return errs.ErrSystemError
+	}
+
+	return s.transactionPictureCurrentStorage.Walk(ctx, fn)
+}
+
+// ExistsTransactionAttachment returns whether the transaction attachment file exists from the current transaction attachment object storage
+func (s *StorageContainer) ExistsTransactionAttachment(ctx core.Context, path string) (bool, error) {
+	if s.transactionAttachmentCurrentStorage == nil {
+		return false, errs.ErrSystemError
+	}
+
+	return s.transactionAttachmentCurrentStorage.Exists(ctx, path)
+}
+
+// ReadTransactionAttachment returns the transaction attachment file from the current transaction attachment object storage
+func (s *StorageContainer) ReadTransactionAttachment(ctx core.Context, path string) (ObjectInStorage, error) {
+	if s.transactionAttachmentCurrentStorage == nil {
+		return nil, errs.ErrSystemError
+	}
+
+	return s.transactionAttachmentCurrentStorage.Read(ctx, path)
+}
+
+// SaveTransactionAttachment returns whether save the transaction attachment file into the current transaction attachment object storage successfully
+func (s *StorageContainer) SaveTransactionAttachment(ctx core.Context, path string, object ObjectInStorage) error {
+	if s.transactionAttachmentCurrentStorage == nil {
+		return errs.ErrSystemError
+	}
+
+	return s.transactionAttachmentCurrentStorage.Save(ctx, path, object)
+}
+
+// DeleteTransactionAttachment returns whether delete the transaction attachment file from the current transaction attachment object storage successfully
+func (s *StorageContainer) DeleteTransactionAttachment(ctx core.Context, path string) error {
+	if s.transactionAttachmentCurrentStorage == nil {
+		return errs.ErrSystemError
+	}
+
+	return s.transactionAttachmentCurrentStorage.Delete(ctx, path)
+}
+
+// GetObjectStorage returns the object storage of the specified object type in this container, or returns nil if the object type is not enabled
+func (s *StorageContainer) GetObjectStorage(objectType StorageObjectType) ObjectStorage {
+	switch objectType {
+	case STORAGE_OBJECT_TYPE_AVATAR:
+		return s.avatarCurrentStorage
+	case STORAGE_OBJECT_TYPE_TRANSACTION_PICTURE:
+		return s.transactionPictureCurrentStorage
+	case STORAGE_OBJECT_TYPE_TRANSACTION_ATTACHMENT:
+		return s.transactionAttachmentCurrentStorage
+	default:
+		return nil
+	}
+}
+
+func newObjectStorage(config *settings.Config, storageType string, pathPrefix string) (ObjectStorage, error) {
+	var objectStorage ObjectStorage
+	var err error
+
+	if storageType == settings.LocalFileSystemObjectStorageType {
+		objectStorage, err = NewLocalFileSystemObjectStorage(config, pathPrefix)
+	} else if storageType == settings.MinIOStorageType {
+		objectStorage, err = NewMinIOObjectStorage(config, pathPrefix)
+	} else if storageType == settings.WebDAVStorageType {
+		objectStorage, err = NewWebDAVObjectStorage(config, pathPrefix)
+	} else {
+		return nil, errs.ErrInvalidStorageType
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(config.StorageEncryptionMasterKey) > 0 {
+		return NewEncryptedObjectStorage(objectStorage, config.StorageEncryptionMasterKey, config.StorageEncryptionPreviousMasterKey, config.StorageEncryptionAllowPlaintextObjects)
 	}
 
-	return nil, errs.ErrInvalidStorageType
+	return objectStorage, nil
 }
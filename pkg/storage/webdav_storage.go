@@ -2,10 +2,13 @@ package storage
 
 import (
 	"bytes"
+	"encoding/xml"
 	"io"
 	"net/http"
+	"net/url"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
@@ -14,6 +17,26 @@ import (
 	"github.com/mayswind/ezbookkeeping/pkg/utils"
 )
 
+const webDAVPropFindRequestBody = `<?xml version="1.0" encoding="utf-8"?><d:propfind xmlns:d="DAV:"><d:prop><d:resourcetype/><d:getlastmodified/></d:prop></d:propfind>`
+
+type webDAVMultiStatus struct {
+	Responses []*webDAVResponse `xml:"DAV: response"`
+}
+
+type webDAVResponse struct {
+	Href      string            `xml:"DAV: href"`
+	PropStats []*webDAVPropStat `xml:"DAV: propstat"`
+}
+
+type webDAVPropStat struct {
+	LastModified string              `xml:"DAV: prop>getlastmodified"`
+	ResourceType *webDAVResourceType `xml:"DAV: prop>resourcetype"`
+}
+
+type webDAVResourceType struct {
+	Collection *struct{} `xml:"DAV: collection"`
+}
+
 // WebDAVObjectStorage represents WebDAV object storage
 type WebDAVObjectStorage struct {
 	httpClient   *http.Client
@@ -107,7 +130,7 @@ func (s *WebDAVObjectStorage) Read(ctx core.Context, path string) (ObjectInStora
 		return nil, errs.ErrSystemError
 	}
 
-	return newByteSliceObject(body), nil
+	return NewByteSliceObject(body), nil
 }
 
 // Save returns whether save the object instance successfully
@@ -211,6 +234,111 @@ func (s *WebDAVObjectStorage) Delete(ctx core.Context, path string) error {
 	return nil
 }
 
+// Walk calls the specified function for each object in the object storage
+func (s *WebDAVObjectStorage) Walk(ctx core.Context, fn ObjectWalkFunc) error {
+	return s.walkDirectory(ctx, "", fn)
+}
+
+func (s *WebDAVObjectStorage) walkDirectory(ctx core.Context, relativeDir string, fn ObjectWalkFunc) error {
+	directoryUrl := s.getFinalDirectoryUrl(s.getFinalPath(relativeDir))
+	directoryUrlInfo, err := url.Parse(directoryUrl)
+
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("PROPFIND", directoryUrl, strings.NewReader(webDAVPropFindRequestBody))
+
+	if err != nil {
+		return err
+	}
+
+	req.SetBasicAuth(s.webDavConfig.Username, s.webDavConfig.Password)
+	req.Header.Set("Depth", "1")
+	req.Header.Set("Content-Type", "application/xml; charset=utf-8")
+	resp, err := s.httpClient.Do(req)
+
+	if err != nil {
+		log.Errorf(ctx, "[webdav_storage.walkDirectory] cannot list directory, because %s", err.Error())
+		return err
+	}
+
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+
+	if err != nil {
+		log.Errorf(ctx, "[webdav_storage.walkDirectory] cannot read response (http status code %d) body, because %s", resp.StatusCode, err.Error())
+		return err
+	}
+
+	if resp.StatusCode != http.StatusMultiStatus {
+		log.Errorf(ctx, "[webdav_storage.walkDirectory] cannot list directory, http status code is %d, response is %s", resp.StatusCode, string(body))
+		return errs.ErrSystemError
+	}
+
+	multiStatus := &webDAVMultiStatus{}
+
+	if err = xml.Unmarshal(body, multiStatus); err != nil {
+		log.Errorf(ctx, "[webdav_storage.walkDirectory] cannot parse response, because %s", err.Error())
+		return err
+	}
+
+	for i := 0; i < len(multiStatus.Responses); i++ {
+		response := multiStatus.Responses[i]
+		hrefUrlInfo, err := url.Parse(response.Href)
+
+		if err != nil || !strings.HasPrefix(hrefUrlInfo.Path, directoryUrlInfo.Path) {
+			continue
+		}
+
+		name := strings.Trim(hrefUrlInfo.Path[len(directoryUrlInfo.Path):], "/")
+
+		// the first response is the directory itself
+		if name == "" {
+			continue
+		}
+
+		isCollection := false
+		lastModifiedUnixTime := int64(0)
+
+		for j := 0; j < len(response.PropStats); j++ {
+			propStat := response.PropStats[j]
+
+			if propStat.ResourceType != nil && propStat.ResourceType.Collection != nil {
+				isCollection = true
+			}
+
+			if lastModified, err := http.ParseTime(propStat.LastModified); err == nil {
+				lastModifiedUnixTime = lastModified.Unix()
+			}
+		}
+
+		if isCollection {
+			if err = s.walkDirectory(ctx, relativeDir+name+"/", fn); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		// the object without last modified time is considered as just modified, so that it would not be treated as an outdated object
+		if lastModifiedUnixTime == 0 {
+			lastModifiedUnixTime = time.Now().Unix()
+		}
+
+		err = fn(&ObjectInfo{
+			Path:                 relativeDir + name,
+			LastModifiedUnixTime: lastModifiedUnixTime,
+		})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (s *WebDAVObjectStorage) directoryExists(ctx core.Context, path string) (bool, error) {
 	req, err := http.NewRequest("PROPFIND", s.getFinalDirectoryUrl(path), nil)
 
@@ -0,0 +1,47 @@
+package storage
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/webdav"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+func TestWebDAVObjectStorage_Walk(t *testing.T) {
+	server := httptest.NewServer(&webdav.Handler{
+		Prefix:     "/dav",
+		FileSystem: webdav.NewMemFS(),
+		LockSystem: webdav.NewMemLS(),
+	})
+	defer server.Close()
+
+	context := core.NewNullContext()
+	objectStorage, err := NewWebDAVObjectStorage(&settings.Config{
+		WebDAVConfig: &settings.WebDAVConfig{
+			Url:            server.URL + "/dav",
+			RootPath:       "ezbookkeeping",
+			RequestTimeout: 10000,
+			Proxy:          "none",
+		},
+	}, "transaction")
+	assert.Nil(t, err)
+
+	err = objectStorage.Save(context, "1/100.jpg", NewByteSliceObject([]byte("picture data")))
+	assert.Nil(t, err)
+	err = objectStorage.Save(context, "1/100.small.jpg", NewByteSliceObject([]byte("thumbnail data")))
+	assert.Nil(t, err)
+	err = objectStorage.Save(context, "2/200 copy.png", NewByteSliceObject([]byte("picture data")))
+	assert.Nil(t, err)
+
+	objectInfos := walkTestObjectStorage(t, objectStorage)
+	assert.Equal(t, 3, len(objectInfos))
+	assert.Equal(t, "1/100.jpg", objectInfos[0].Path)
+	assert.Equal(t, "1/100.small.jpg", objectInfos[1].Path)
+	assert.Equal(t, "2/200 copy.png", objectInfos[2].Path)
+	assert.InDelta(t, time.Now().Unix(), objectInfos[0].LastModifiedUnixTime, 60)
+}
@@ -6,5 +6,6 @@ type KnownTemplate string
 const (
 	TEMPLATE_VERIFY_EMAIL                   KnownTemplate = "email/verify_email"
 	TEMPLATE_PASSWORD_RESET                 KnownTemplate = "email/password_reset"
+	TEMPLATE_LEDGER_INVITATION              KnownTemplate = "email/ledger_invitation"
 	SYSTEM_PROMPT_RECEIPT_IMAGE_RECOGNITION KnownTemplate = "prompt/receipt_image_recognition"
 )
@@ -0,0 +1,64 @@
+// Package testutils provides the helpers shared by the tests of different packages
+package testutils
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/uuid"
+)
+
+// InitializeTestDataStore initializes a sqlite database in a temporary directory and all tables in it for the tests which need to use db
+func InitializeTestDataStore(t testing.TB) core.Context {
+	config := &settings.Config{
+		DatabaseConfig: &settings.DatabaseConfig{
+			DatabaseType:      settings.Sqlite3DbType,
+			DatabasePath:      filepath.Join(t.TempDir(), "ezbookkeeping.db"),
+			MaxOpenConnection: 1,
+		},
+		UuidGeneratorType: settings.InternalUuidGeneratorType,
+	}
+
+	settings.SetCurrentConfig(config)
+
+	err := datastore.InitializeDataStore(config)
+	assert.Nil(t, err)
+
+	err = uuid.InitializeUuidGenerator(config)
+	assert.Nil(t, err)
+
+	err = datastore.Container.UserStore.SyncStructs(new(models.User), new(models.LedgerMember))
+	assert.Nil(t, err)
+
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.Ledger), new(models.Account), new(models.Transaction), new(models.TransactionCategory),
+		new(models.TransactionTag), new(models.TransactionTagGroup), new(models.TransactionTagIndex), new(models.TransactionTemplate),
+		new(models.TransactionCustomField), new(models.TransactionCustomFieldValue), new(models.TransactionAttachment),
+		new(models.TransactionPictureInfo), new(models.TransactionPictureObject), new(models.Contact), new(models.ContactTransaction), new(models.Event))
+	assert.Nil(t, err)
+
+	return core.NewNullContext()
+}
+
+// InsertTestUser inserts a user with the specified uid into the database for tests
+func InsertTestUser(t testing.TB, uid int64) {
+	_, err := datastore.Container.UserStore.Choose(uid).NewSession(core.NewNullContext()).Insert(&models.User{
+		Uid:      uid,
+		Username: fmt.Sprintf("user%d", uid),
+		Email:    fmt.Sprintf("user%d@example.com", uid),
+	})
+
+	assert.Nil(t, err)
+}
+
+// InsertTestData inserts the specified models into the user data database for tests
+func InsertTestData(t testing.TB, uid int64, beans ...any) {
+	_, err := datastore.Container.UserDataStore.Choose(uid).NewSession(core.NewNullContext()).Insert(beans...)
+	assert.Nil(t, err)
+}
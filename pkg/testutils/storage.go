@@ -0,0 +1,28 @@
+package testutils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/storage"
+)
+
+// InitializeTestStorage initializes the local file system object storage in a temporary directory for the tests which need to use object storage,
+// and returns the root path of the object storage
+func InitializeTestStorage(t *testing.T) string {
+	config := &settings.Config{
+		StorageType:                  settings.LocalFileSystemObjectStorageType,
+		LocalFileSystemPath:          t.TempDir(),
+		AvatarProvider:               core.USER_AVATAR_PROVIDER_INTERNAL,
+		EnableTransactionPictures:    true,
+		EnableTransactionAttachments: true,
+	}
+
+	err := storage.InitializeStorageContainer(config)
+	assert.Nil(t, err)
+
+	return config.LocalFileSystemPath
+}
@@ -0,0 +1,303 @@
+package thumbnails
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+const (
+	jpegMarkerStartOfImage = 0xD8
+	jpegMarkerStartOfScan  = 0xDA
+	jpegMarkerApp1         = 0xE1
+
+	tiffTagOrientation  = 0x0112
+	tiffTagGpsIfdOffset = 0x8825
+
+	pngChunkExif = "eXIf"
+
+	webpChunkExif          = "EXIF"
+	webpChunkExtended      = "VP8X"
+	webpExtendedFlagExif   = 0x08
+	webpChunkHeaderLength  = 8
+	webpFileHeaderLength   = 12
+	pngFileSignatureLength = 8
+)
+
+var jpegExifHeader = []byte("Exif\x00\x00")
+var jpegXmpHeader = []byte("http://ns.adobe.com/xap/1.0/\x00")
+var pngFileSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// the byte size of each tiff field type, the index is the field type
+var tiffFieldTypeSizes = []int{0, 1, 1, 2, 4, 8, 1, 1, 2, 4, 8, 4, 8}
+
+// ImageOrientation represents the orientation value defined in exif
+type ImageOrientation int
+
+// Image orientations
+const (
+	IMAGE_ORIENTATION_NORMAL               ImageOrientation = 1
+	IMAGE_ORIENTATION_FLIP_HORIZONTAL      ImageOrientation = 2
+	IMAGE_ORIENTATION_ROTATE_180           ImageOrientation = 3
+	IMAGE_ORIENTATION_FLIP_VERTICAL        ImageOrientation = 4
+	IMAGE_ORIENTATION_TRANSPOSE            ImageOrientation = 5
+	IMAGE_ORIENTATION_ROTATE_90_CLOCKWISE  ImageOrientation = 6
+	IMAGE_ORIENTATION_TRANSVERSE           ImageOrientation = 7
+	IMAGE_ORIENTATION_ROTATE_270_CLOCKWISE ImageOrientation = 8
+	imageOrientationMinValue                                = IMAGE_ORIENTATION_NORMAL
+	imageOrientationMaxValue                                = IMAGE_ORIENTATION_ROTATE_270_CLOCKWISE
+)
+
+// StripGpsMetadata returns a copy of the jpeg, png or webp image data without gps location metadata,
+// the gps fields in jpeg exif are cleared and the xmp segments are removed, and the whole exif chunk in png and webp is removed,
+// and the original data is returned if the image format is not supported
+func StripGpsMetadata(data []byte) []byte {
+	if len(data) > 2 && data[0] == 0xFF && data[1] == jpegMarkerStartOfImage {
+		return stripJpegGpsMetadata(data)
+	} else if bytes.HasPrefix(data, pngFileSignature) {
+		return stripPngExifChunk(data)
+	} else if len(data) > webpFileHeaderLength && string(data[0:4]) == "RIFF" && string(data[8:12]) == "WEBP" {
+		return stripWebpExifChunk(data)
+	}
+
+	return data
+}
+
+// GetJpegOrientation returns the orientation in the exif metadata of jpeg image data,
+// or returns normal orientation if there is no exif metadata or the orientation is invalid
+func GetJpegOrientation(data []byte) ImageOrientation {
+	if len(data) < 2 || data[0] != 0xFF || data[1] != jpegMarkerStartOfImage {
+		return IMAGE_ORIENTATION_NORMAL
+	}
+
+	orientation := IMAGE_ORIENTATION_NORMAL
+
+	forEachJpegSegment(data, func(marker byte, segmentStart int, segmentEnd int) bool {
+		if marker != jpegMarkerApp1 || !bytes.HasPrefix(data[segmentStart:segmentEnd], jpegExifHeader) {
+			return true
+		}
+
+		tiff := data[segmentStart+len(jpegExifHeader) : segmentEnd]
+		byteOrder, ifdOffset, ok := readTiffHeader(tiff)
+
+		if !ok {
+			return false
+		}
+
+		forEachTiffIfdEntry(tiff, byteOrder, ifdOffset, func(entryOffset int, tag uint16, fieldType uint16, count uint32) {
+			if tag == tiffTagOrientation && fieldType == 3 && count == 1 {
+				value := ImageOrientation(byteOrder.Uint16(tiff[entryOffset+8:]))
+
+				if value >= imageOrientationMinValue && value <= imageOrientationMaxValue {
+					orientation = value
+				}
+			}
+		})
+
+		return false
+	})
+
+	return orientation
+}
+
+func stripJpegGpsMetadata(data []byte) []byte {
+	result := make([]byte, 0, len(data))
+	result = append(result, data[0:2]...)
+	lastEnd := 2
+
+	forEachJpegSegment(data, func(marker byte, segmentStart int, segmentEnd int) bool {
+		if marker == jpegMarkerApp1 && bytes.HasPrefix(data[segmentStart:segmentEnd], jpegXmpHeader) {
+			result = append(result, data[lastEnd:segmentStart-4]...)
+			lastEnd = segmentEnd
+			return true
+		}
+
+		result = append(result, data[lastEnd:segmentEnd]...)
+		lastEnd = segmentEnd
+
+		if marker == jpegMarkerApp1 && bytes.HasPrefix(data[segmentStart:segmentEnd], jpegExifHeader) {
+			tiffStart := len(result) - (segmentEnd - segmentStart) + len(jpegExifHeader)
+			clearTiffGpsIfd(result[tiffStart:])
+		}
+
+		return true
+	})
+
+	return append(result, data[lastEnd:]...)
+}
+
+func forEachJpegSegment(data []byte, fn func(marker byte, segmentStart int, segmentEnd int) bool) {
+	offset := 2
+
+	for offset+4 <= len(data) {
+		if data[offset] != 0xFF {
+			return
+		}
+
+		marker := data[offset+1]
+
+		if marker == 0xFF {
+			offset++
+			continue
+		}
+
+		if marker == jpegMarkerStartOfScan || (marker >= 0xD0 && marker <= 0xD9) {
+			return
+		}
+
+		segmentLength := int(binary.BigEndian.Uint16(data[offset+2:]))
+		segmentStart := offset + 4
+		segmentEnd := offset + 2 + segmentLength
+
+		if segmentLength < 2 || segmentEnd > len(data) {
+			return
+		}
+
+		if !fn(marker, segmentStart, segmentEnd) {
+			return
+		}
+
+		offset = segmentEnd
+	}
+}
+
+func readTiffHeader(tiff []byte) (binary.ByteOrder, int, bool) {
+	if len(tiff) < 8 {
+		return nil, 0, false
+	}
+
+	var byteOrder binary.ByteOrder
+
+	if tiff[0] == 'I' && tiff[1] == 'I' {
+		byteOrder = binary.LittleEndian
+	} else if tiff[0] == 'M' && tiff[1] == 'M' {
+		byteOrder = binary.BigEndian
+	} else {
+		return nil, 0, false
+	}
+
+	return byteOrder, int(byteOrder.Uint32(tiff[4:8])), true
+}
+
+func forEachTiffIfdEntry(tiff []byte, byteOrder binary.ByteOrder, ifdOffset int, fn func(entryOffset int, tag uint16, fieldType uint16, count uint32)) {
+	if ifdOffset < 8 || ifdOffset+2 > len(tiff) {
+		return
+	}
+
+	entryCount := int(byteOrder.Uint16(tiff[ifdOffset:]))
+
+	for i := 0; i < entryCount; i++ {
+		entryOffset := ifdOffset + 2 + i*12
+
+		if entryOffset+12 > len(tiff) {
+			return
+		}
+
+		fn(entryOffset, byteOrder.Uint16(tiff[entryOffset:]), byteOrder.Uint16(tiff[entryOffset+2:]), byteOrder.Uint32(tiff[entryOffset+4:]))
+	}
+}
+
+func clearTiffGpsIfd(tiff []byte) {
+	byteOrder, ifdOffset, ok := readTiffHeader(tiff)
+
+	if !ok {
+		return
+	}
+
+	gpsIfdOffset := -1
+
+	forEachTiffIfdEntry(tiff, byteOrder, ifdOffset, func(entryOffset int, tag uint16, fieldType uint16, count uint32) {
+		if tag == tiffTagGpsIfdOffset {
+			gpsIfdOffset = int(byteOrder.Uint32(tiff[entryOffset+8:]))
+		}
+	})
+
+	if gpsIfdOffset < 8 || gpsIfdOffset+2 > len(tiff) {
+		return
+	}
+
+	entriesEnd := gpsIfdOffset + 2
+
+	forEachTiffIfdEntry(tiff, byteOrder, gpsIfdOffset, func(entryOffset int, tag uint16, fieldType uint16, count uint32) {
+		entriesEnd = entryOffset + 12
+
+		if int(fieldType) >= len(tiffFieldTypeSizes) {
+			return
+		}
+
+		valueSize := uint64(tiffFieldTypeSizes[fieldType]) * uint64(count)
+
+		if valueSize <= 4 {
+			return
+		}
+
+		valueOffset := uint64(byteOrder.Uint32(tiff[entryOffset+8:]))
+
+		if valueOffset+valueSize <= uint64(len(tiff)) {
+			clear(tiff[valueOffset : valueOffset+valueSize])
+		}
+	})
+
+	// the entry count and next ifd offset are both set to zero, so the gps ifd becomes an empty ifd
+	clear(tiff[gpsIfdOffset:min(entriesEnd+4, len(tiff))])
+}
+
+func stripPngExifChunk(data []byte) []byte {
+	result := make([]byte, 0, len(data))
+	result = append(result, data[0:pngFileSignatureLength]...)
+	offset := pngFileSignatureLength
+
+	for offset+12 <= len(data) {
+		chunkLength := int(binary.BigEndian.Uint32(data[offset:]))
+		chunkEnd := offset + 12 + chunkLength
+
+		if chunkLength < 0 || chunkEnd > len(data) {
+			break
+		}
+
+		if string(data[offset+4:offset+8]) != pngChunkExif {
+			result = append(result, data[offset:chunkEnd]...)
+		}
+
+		offset = chunkEnd
+	}
+
+	return append(result, data[offset:]...)
+}
+
+func stripWebpExifChunk(data []byte) []byte {
+	result := make([]byte, 0, len(data))
+	result = append(result, data[0:webpFileHeaderLength]...)
+	offset := webpFileHeaderLength
+	extendedChunkOffset := -1
+
+	for offset+webpChunkHeaderLength <= len(data) {
+		chunkLength := int(binary.LittleEndian.Uint32(data[offset+4:]))
+		chunkEnd := offset + webpChunkHeaderLength + chunkLength + chunkLength%2
+
+		if chunkLength < 0 || chunkEnd > len(data) {
+			break
+		}
+
+		chunkType := string(data[offset : offset+4])
+
+		if chunkType == webpChunkExtended {
+			extendedChunkOffset = len(result)
+		}
+
+		if chunkType != webpChunkExif {
+			result = append(result, data[offset:chunkEnd]...)
+		}
+
+		offset = chunkEnd
+	}
+
+	result = append(result, data[offset:]...)
+
+	if extendedChunkOffset >= 0 && extendedChunkOffset+webpChunkHeaderLength < len(result) {
+		result[extendedChunkOffset+webpChunkHeaderLength] &^= webpExtendedFlagExif
+	}
+
+	binary.LittleEndian.PutUint32(result[4:8], uint32(len(result)-8))
+
+	return result
+}
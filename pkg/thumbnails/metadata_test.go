@@ -0,0 +1,120 @@
+package thumbnails
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testGpsLatitudeData = []byte{31, 0, 0, 0, 1, 0, 0, 0, 14, 0, 0, 0, 1, 0, 0, 0, 56, 0, 0, 0, 1, 0, 0, 0}
+
+func TestStripGpsMetadata_Jpeg(t *testing.T) {
+	data := createTestJpegWithExif(t, IMAGE_ORIENTATION_ROTATE_90_CLOCKWISE)
+	assert.True(t, bytes.Contains(data, testGpsLatitudeData))
+	assert.True(t, bytes.Contains(data, jpegXmpHeader))
+
+	strippedData := StripGpsMetadata(data)
+	assert.False(t, bytes.Contains(strippedData, testGpsLatitudeData))
+	assert.False(t, bytes.Contains(strippedData, jpegXmpHeader))
+	assert.True(t, bytes.Contains(strippedData, jpegExifHeader))
+	assert.Equal(t, IMAGE_ORIENTATION_ROTATE_90_CLOCKWISE, GetJpegOrientation(strippedData))
+
+	img, err := jpeg.Decode(bytes.NewReader(strippedData))
+	assert.Nil(t, err)
+	assert.Equal(t, 40, img.Bounds().Dx())
+	assert.Equal(t, 20, img.Bounds().Dy())
+}
+
+func TestStripGpsMetadata_Png(t *testing.T) {
+	imageBuffer := &bytes.Buffer{}
+	err := png.Encode(imageBuffer, image.NewRGBA(image.Rect(0, 0, 10, 10)))
+	assert.Nil(t, err)
+
+	exifChunk := createTestPngChunk(pngChunkExif, createTestTiffData(IMAGE_ORIENTATION_NORMAL))
+	data := append([]byte{}, imageBuffer.Bytes()[:pngFileSignatureLength+25]...)
+	data = append(data, exifChunk...)
+	data = append(data, imageBuffer.Bytes()[pngFileSignatureLength+25:]...)
+	assert.True(t, bytes.Contains(data, testGpsLatitudeData))
+
+	strippedData := StripGpsMetadata(data)
+	assert.False(t, bytes.Contains(strippedData, testGpsLatitudeData))
+	assert.Equal(t, imageBuffer.Bytes(), strippedData)
+}
+
+func TestStripGpsMetadata_UnsupportedData(t *testing.T) {
+	data := []byte("GIF89a")
+	assert.Equal(t, data, StripGpsMetadata(data))
+}
+
+func TestGetJpegOrientation(t *testing.T) {
+	data := createTestJpegWithExif(t, IMAGE_ORIENTATION_ROTATE_180)
+	assert.Equal(t, IMAGE_ORIENTATION_ROTATE_180, GetJpegOrientation(data))
+
+	data = createTestJpegWithExif(t, ImageOrientation(100))
+	assert.Equal(t, IMAGE_ORIENTATION_NORMAL, GetJpegOrientation(data))
+
+	assert.Equal(t, IMAGE_ORIENTATION_NORMAL, GetJpegOrientation([]byte("not an image")))
+}
+
+func createTestJpegWithExif(t *testing.T, orientation ImageOrientation) []byte {
+	imageBuffer := &bytes.Buffer{}
+	err := jpeg.Encode(imageBuffer, image.NewRGBA(image.Rect(0, 0, 40, 20)), nil)
+	assert.Nil(t, err)
+
+	exifPayload := append(append([]byte{}, jpegExifHeader...), createTestTiffData(orientation)...)
+	xmpPayload := append(append([]byte{}, jpegXmpHeader...), []byte("<x:xmpmeta><exif:GPSLatitude>31,14.56N</exif:GPSLatitude></x:xmpmeta>")...)
+
+	data := append([]byte{}, imageBuffer.Bytes()[:2]...)
+	data = append(data, createTestJpegSegment(jpegMarkerApp1, exifPayload)...)
+	data = append(data, createTestJpegSegment(jpegMarkerApp1, xmpPayload)...)
+	data = append(data, imageBuffer.Bytes()[2:]...)
+
+	return data
+}
+
+func createTestJpegSegment(marker byte, payload []byte) []byte {
+	segment := []byte{0xFF, marker, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	return append(segment, payload...)
+}
+
+func createTestPngChunk(chunkType string, payload []byte) []byte {
+	chunk := make([]byte, 8, len(payload)+12)
+	binary.BigEndian.PutUint32(chunk, uint32(len(payload)))
+	copy(chunk[4:], chunkType)
+	chunk = append(chunk, payload...)
+	return binary.BigEndian.AppendUint32(chunk, crc32.ChecksumIEEE(chunk[4:]))
+}
+
+func createTestTiffData(orientation ImageOrientation) []byte {
+	byteOrder := binary.LittleEndian
+	tiff := []byte{'I', 'I', 42, 0}
+	tiff = byteOrder.AppendUint32(tiff, 8)
+
+	// ifd0 with orientation and gps ifd pointer
+	tiff = byteOrder.AppendUint16(tiff, 2)
+	tiff = appendTestTiffEntry(tiff, tiffTagOrientation, 3, 1, uint32(orientation))
+	tiff = appendTestTiffEntry(tiff, tiffTagGpsIfdOffset, 4, 1, 38)
+	tiff = byteOrder.AppendUint32(tiff, 0)
+
+	// gps ifd with latitude
+	tiff = byteOrder.AppendUint16(tiff, 1)
+	tiff = appendTestTiffEntry(tiff, 0x0002, 5, 3, 56)
+	tiff = byteOrder.AppendUint32(tiff, 0)
+	tiff = append(tiff, testGpsLatitudeData...)
+
+	return tiff
+}
+
+func appendTestTiffEntry(tiff []byte, tag uint16, fieldType uint16, count uint32, value uint32) []byte {
+	tiff = binary.LittleEndian.AppendUint16(tiff, tag)
+	tiff = binary.LittleEndian.AppendUint16(tiff, fieldType)
+	tiff = binary.LittleEndian.AppendUint32(tiff, count)
+	return binary.LittleEndian.AppendUint32(tiff, value)
+}
@@ -0,0 +1,440 @@
+package thumbnails
+
+import (
+	"bytes"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+)
+
+const pdfMaxPageTreeDepth = 32
+
+var pdfObjectHeaderPattern = regexp.MustCompile(`(\d+)\s+\d+\s+obj\b`)
+var pdfReferencePattern = regexp.MustCompile(`^(\d+)\s+\d+\s+R$`)
+var pdfReferencePrefixPattern = regexp.MustCompile(`^\d+\s+\d+\s+R\b`)
+var pdfDctDecodeFilterName = []byte("/DCTDecode")
+var pdfStreamKeyword = []byte("stream")
+var pdfEndStreamKeyword = []byte("endstream")
+var pdfEndObjectKeyword = []byte("endobj")
+var pdfRootKeyword = []byte("/Root")
+var jpegStartOfImageMarker = []byte{0xFF, 0xD8}
+
+// CreatePdfThumbnail returns the jpeg encoded thumbnail data of the first page of the specified pdf file.
+// It does not render the page, instead it uses the jpeg image which is drawn in the first page, which is the page itself
+// for most scanned invoices and receipts, and it returns error if the first page cannot be resolved or has no jpeg image,
+// then the client shows the generic file icon instead
+func CreatePdfThumbnail(data []byte, maxSize int) ([]byte, error) {
+	imageData, err := extractFirstPagePdfJpegImage(data)
+
+	if err != nil {
+		return nil, err
+	}
+
+	img, err := DecodeImage(imageData)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return CreateImageThumbnail(img, maxSize)
+}
+
+func extractFirstPagePdfJpegImage(data []byte) ([]byte, error) {
+	objects := getPdfObjects(data)
+	rootIndex := bytes.LastIndex(data, pdfRootKeyword)
+
+	if rootIndex < 0 {
+		return nil, errs.ErrNotSupported
+	}
+
+	catalog := getPdfReferencedDictionary(objects, parsePdfValue(data[rootIndex+len(pdfRootKeyword):]))
+
+	if catalog == nil {
+		return nil, errs.ErrNotSupported
+	}
+
+	page := getPdfDictionaryReference(objects, catalog, "/Pages")
+	var resources map[string][]byte
+
+	// descend to the first leaf page, and the resources can be inherited from the ancestor page tree nodes
+	for depth := 0; page != nil && depth < pdfMaxPageTreeDepth; depth++ {
+		if pageResources := getPdfDictionaryReference(objects, page, "/Resources"); pageResources != nil {
+			resources = pageResources
+		}
+
+		if string(page["/Type"]) == "/Page" {
+			break
+		}
+
+		kids := parsePdfArray(page["/Kids"])
+
+		if len(kids) < 1 {
+			return nil, errs.ErrNotSupported
+		}
+
+		page = getPdfReferencedDictionary(objects, kids[0])
+	}
+
+	if page == nil || string(page["/Type"]) != "/Page" || resources == nil {
+		return nil, errs.ErrNotSupported
+	}
+
+	xObjects := getPdfDictionaryReference(objects, resources, "/XObject")
+
+	if xObjects == nil {
+		return nil, errs.ErrNotSupported
+	}
+
+	names := make([]string, 0, len(xObjects))
+
+	for name := range xObjects {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for i := 0; i < len(names); i++ {
+		objectNumber, ok := parsePdfReference(xObjects[names[i]])
+
+		if !ok {
+			continue
+		}
+
+		objectData, exists := objects[objectNumber]
+
+		if !exists {
+			continue
+		}
+
+		dictionary := parsePdfDictionary(objectData)
+
+		// the image which is compressed by other filters before jpeg is not supported
+		if dictionary == nil || string(dictionary["/Subtype"]) != "/Image" || !bytes.Equal(bytes.Trim(dictionary["/Filter"], "[] \t\r\n"), pdfDctDecodeFilterName) {
+			continue
+		}
+
+		streamData := getPdfStreamData(objectData)
+
+		if bytes.HasPrefix(streamData, jpegStartOfImageMarker) {
+			return streamData, nil
+		}
+	}
+
+	return nil, errs.ErrNotSupported
+}
+
+// getPdfObjects returns the map of object number and the object data (from the object body to "endobj"),
+// the object defined later overrides the former one, which is the way of incremental update
+func getPdfObjects(data []byte) map[int64][]byte {
+	objects := make(map[int64][]byte)
+	offset := 0
+
+	for offset < len(data) {
+		match := pdfObjectHeaderPattern.FindSubmatchIndex(data[offset:])
+
+		if match == nil {
+			break
+		}
+
+		objectNumber, err := strconv.ParseInt(string(data[offset+match[2]:offset+match[3]]), 10, 64)
+		bodyStart := offset + match[1]
+		bodyEnd := len(data)
+
+		// skip the stream content to avoid matching the object header in binary data
+		endObjectIndex := bytes.Index(data[bodyStart:], pdfEndObjectKeyword)
+		streamIndex := bytes.Index(data[bodyStart:], pdfStreamKeyword)
+
+		if streamIndex >= 0 && (endObjectIndex < 0 || streamIndex < endObjectIndex) {
+			endStreamIndex := bytes.Index(data[bodyStart+streamIndex:], pdfEndStreamKeyword)
+
+			if endStreamIndex >= 0 {
+				endObjectIndex = bytes.Index(data[bodyStart+streamIndex+endStreamIndex:], pdfEndObjectKeyword)
+
+				if endObjectIndex >= 0 {
+					endObjectIndex += streamIndex + endStreamIndex
+				}
+			} else {
+				endObjectIndex = -1
+			}
+		}
+
+		if endObjectIndex >= 0 {
+			bodyEnd = bodyStart + endObjectIndex
+		}
+
+		if err == nil {
+			objects[objectNumber] = data[bodyStart:bodyEnd]
+		}
+
+		offset = bodyEnd
+	}
+
+	return objects
+}
+
+func getPdfStreamData(objectData []byte) []byte {
+	streamIndex := bytes.Index(objectData, pdfStreamKeyword)
+
+	if streamIndex < 0 {
+		return nil
+	}
+
+	streamStart := streamIndex + len(pdfStreamKeyword)
+
+	if streamStart < len(objectData) && objectData[streamStart] == '\r' {
+		streamStart++
+	}
+
+	if streamStart < len(objectData) && objectData[streamStart] == '\n' {
+		streamStart++
+	}
+
+	streamLength := bytes.LastIndex(objectData[streamStart:], pdfEndStreamKeyword)
+
+	if streamLength < 0 {
+		return nil
+	}
+
+	return objectData[streamStart : streamStart+streamLength]
+}
+
+func getPdfDictionaryReference(objects map[int64][]byte, dictionary map[string][]byte, key string) map[string][]byte {
+	value, exists := dictionary[key]
+
+	if !exists {
+		return nil
+	}
+
+	return getPdfReferencedDictionary(objects, value)
+}
+
+// getPdfReferencedDictionary returns the dictionary which is the value itself or the referenced object
+func getPdfReferencedDictionary(objects map[int64][]byte, value []byte) map[string][]byte {
+	if objectNumber, ok := parsePdfReference(value); ok {
+		objectData, exists := objects[objectNumber]
+
+		if !exists {
+			return nil
+		}
+
+		value = objectData
+	}
+
+	return parsePdfDictionary(value)
+}
+
+func parsePdfReference(value []byte) (int64, bool) {
+	match := pdfReferencePattern.FindSubmatch(bytes.TrimSpace(value))
+
+	if match == nil {
+		return 0, false
+	}
+
+	objectNumber, err := strconv.ParseInt(string(match[1]), 10, 64)
+
+	if err != nil {
+		return 0, false
+	}
+
+	return objectNumber, true
+}
+
+// parsePdfDictionary returns the map of the keys and the raw values of the first level of the dictionary at the beginning of data
+func parsePdfDictionary(data []byte) map[string][]byte {
+	offset := skipPdfWhitespaces(data, 0)
+
+	if !bytes.HasPrefix(data[offset:], []byte("<<")) {
+		return nil
+	}
+
+	end := findPdfValueEnd(data, offset)
+
+	if end < offset+4 || !bytes.HasSuffix(data[:end], []byte(">>")) {
+		return nil
+	}
+
+	content := data[offset+2 : end-2]
+	dictionary := make(map[string][]byte)
+	offset = 0
+
+	for {
+		offset = skipPdfWhitespaces(content, offset)
+
+		if offset >= len(content) || content[offset] != '/' {
+			break
+		}
+
+		keyEnd := findPdfValueEnd(content, offset)
+		key := string(content[offset:keyEnd])
+		valueStart := skipPdfWhitespaces(content, keyEnd)
+		valueEnd := findPdfValueEnd(content, valueStart)
+
+		// the value of reference consists of three tokens, e.g. "12 0 R"
+		if reference := pdfReferencePrefixPattern.FindIndex(content[valueStart:]); reference != nil {
+			valueEnd = valueStart + reference[1]
+		}
+
+		if valueEnd <= valueStart {
+			break
+		}
+
+		dictionary[key] = content[valueStart:valueEnd]
+		offset = valueEnd
+	}
+
+	return dictionary
+}
+
+// parsePdfArray returns the raw items of the array, the references are returned as one item
+func parsePdfArray(data []byte) [][]byte {
+	data = bytes.TrimSpace(data)
+
+	if len(data) < 2 || data[0] != '[' || data[len(data)-1] != ']' {
+		return nil
+	}
+
+	content := data[1 : len(data)-1]
+	items := make([][]byte, 0)
+	offset := 0
+
+	for {
+		offset = skipPdfWhitespaces(content, offset)
+
+		if offset >= len(content) {
+			break
+		}
+
+		end := findPdfValueEnd(content, offset)
+
+		if reference := pdfReferencePrefixPattern.FindIndex(content[offset:]); reference != nil {
+			end = offset + reference[1]
+		}
+
+		if end <= offset {
+			break
+		}
+
+		items = append(items, content[offset:end])
+		offset = end
+	}
+
+	return items
+}
+
+func parsePdfValue(data []byte) []byte {
+	offset := skipPdfWhitespaces(data, 0)
+
+	if reference := pdfReferencePrefixPattern.FindIndex(data[offset:]); reference != nil {
+		return data[offset : offset+reference[1]]
+	}
+
+	return data[offset:findPdfValueEnd(data, offset)]
+}
+
+// findPdfValueEnd returns the end offset of the single token, dictionary, array or string which starts at the offset
+func findPdfValueEnd(data []byte, offset int) int {
+	if offset >= len(data) {
+		return offset
+	}
+
+	switch {
+	case bytes.HasPrefix(data[offset:], []byte("<<")):
+		depth := 0
+
+		for i := offset; i < len(data); i++ {
+			if bytes.HasPrefix(data[i:], []byte("<<")) {
+				depth++
+				i++
+			} else if bytes.HasPrefix(data[i:], []byte(">>")) {
+				depth--
+				i++
+
+				if depth == 0 {
+					return i + 1
+				}
+			} else if data[i] == '(' {
+				i = findPdfValueEnd(data, i) - 1
+			}
+		}
+
+		return len(data)
+	case data[offset] == '[':
+		depth := 0
+
+		for i := offset; i < len(data); i++ {
+			if data[i] == '[' {
+				depth++
+			} else if data[i] == ']' {
+				depth--
+
+				if depth == 0 {
+					return i + 1
+				}
+			} else if data[i] == '(' {
+				i = findPdfValueEnd(data, i) - 1
+			}
+		}
+
+		return len(data)
+	case data[offset] == '(':
+		depth := 0
+
+		for i := offset; i < len(data); i++ {
+			if data[i] == '\\' {
+				i++
+			} else if data[i] == '(' {
+				depth++
+			} else if data[i] == ')' {
+				depth--
+
+				if depth == 0 {
+					return i + 1
+				}
+			}
+		}
+
+		return len(data)
+	case data[offset] == '<':
+		end := bytes.IndexByte(data[offset:], '>')
+
+		if end < 0 {
+			return len(data)
+		}
+
+		return offset + end + 1
+	}
+
+	for i := offset + 1; i < len(data); i++ {
+		if isPdfWhitespace(data[i]) || isPdfDelimiter(data[i]) {
+			return i
+		}
+	}
+
+	return len(data)
+}
+
+func skipPdfWhitespaces(data []byte, offset int) int {
+	for offset < len(data) {
+		if isPdfWhitespace(data[offset]) {
+			offset++
+		} else if data[offset] == '%' {
+			for offset < len(data) && data[offset] != '\r' && data[offset] != '\n' {
+				offset++
+			}
+		} else {
+			break
+		}
+	}
+
+	return offset
+}
+
+func isPdfWhitespace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\r' || b == '\n' || b == '\f' || b == 0
+}
+
+func isPdfDelimiter(b byte) bool {
+	return b == '(' || b == ')' || b == '<' || b == '>' || b == '[' || b == ']' || b == '{' || b == '}' || b == '/' || b == '%'
+}
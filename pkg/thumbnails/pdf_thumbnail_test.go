@@ -0,0 +1,146 @@
+package thumbnails
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+)
+
+func TestCreatePdfThumbnail(t *testing.T) {
+	pdfData := createTestPdfWithJpegImage(t, 800, 600)
+
+	thumbnailData, err := CreatePdfThumbnail(pdfData, DefaultThumbnailMaxSize)
+	assert.Nil(t, err)
+
+	thumbnail, err := jpeg.Decode(bytes.NewReader(thumbnailData))
+	assert.Nil(t, err)
+	assert.Equal(t, 256, thumbnail.Bounds().Dx())
+	assert.Equal(t, 192, thumbnail.Bounds().Dy())
+}
+
+func TestCreatePdfThumbnail_SkipNonJpegStream(t *testing.T) {
+	pdfData := createTestPdf(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /Resources << /XObject << /Im0 4 0 R /Im1 5 0 R >> >> >>",
+		"<< /Type /XObject /Subtype /Image /Filter [/FlateDecode /DCTDecode] /Length 4 >>\nstream\nabcd\nendstream",
+		createTestPdfJpegImageObject(t, 100, 50),
+	)
+
+	thumbnailData, err := CreatePdfThumbnail(pdfData, DefaultThumbnailMaxSize)
+	assert.Nil(t, err)
+
+	thumbnail, err := jpeg.Decode(bytes.NewReader(thumbnailData))
+	assert.Nil(t, err)
+	assert.Equal(t, 100, thumbnail.Bounds().Dx())
+	assert.Equal(t, 50, thumbnail.Bounds().Dy())
+}
+
+func TestCreatePdfThumbnail_InheritedResources(t *testing.T) {
+	pdfData := createTestPdf(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 /Resources 4 0 R >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] >>",
+		"<< /ProcSet [/PDF /ImageC] /XObject << /Im0 5 0 R >> >>",
+		createTestPdfJpegImageObject(t, 120, 60),
+	)
+
+	thumbnailData, err := CreatePdfThumbnail(pdfData, DefaultThumbnailMaxSize)
+	assert.Nil(t, err)
+
+	thumbnail, err := jpeg.Decode(bytes.NewReader(thumbnailData))
+	assert.Nil(t, err)
+	assert.Equal(t, 120, thumbnail.Bounds().Dx())
+	assert.Equal(t, 60, thumbnail.Bounds().Dy())
+}
+
+func TestCreatePdfThumbnail_ImageNotInFirstPage(t *testing.T) {
+	pdfData := createTestPdf(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R 4 0 R] /Count 2 >>",
+		createTestPdfJpegImageObject(t, 100, 50),
+		"<< /Type /Page /Parent 2 0 R /Resources << /Font << /F1 6 0 R >> >> >>",
+		"<< /Type /Page /Parent 2 0 R /Resources << /XObject << /Im0 3 0 R >> >> >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+	)
+
+	_, err := CreatePdfThumbnail(pdfData, DefaultThumbnailMaxSize)
+	assert.EqualError(t, err, errs.ErrNotSupported.Message)
+}
+
+func TestCreatePdfThumbnail_ExceedMaxPixelCount(t *testing.T) {
+	imageBuffer := &bytes.Buffer{}
+	err := jpeg.Encode(imageBuffer, image.NewRGBA(image.Rect(0, 0, 16, 16)), nil)
+	assert.Nil(t, err)
+
+	// change the image size in the start of frame segment to 65535 x 65535
+	imageData := imageBuffer.Bytes()
+	startOfFrameIndex := bytes.Index(imageData, []byte{0xFF, 0xC0})
+	assert.True(t, startOfFrameIndex > 0)
+	copy(imageData[startOfFrameIndex+5:startOfFrameIndex+9], []byte{0xFF, 0xFF, 0xFF, 0xFF})
+
+	pdfData := createTestPdf(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /Resources << /XObject << /Im0 4 0 R >> >> >>",
+		fmt.Sprintf("<< /Type /XObject /Subtype /Image /Width 65535 /Height 65535 /Filter /DCTDecode /Length %d >>\nstream\r\n%s\r\nendstream", len(imageData), imageData),
+	)
+
+	_, err = CreatePdfThumbnail(pdfData, DefaultThumbnailMaxSize)
+	assert.EqualError(t, err, errs.ErrImageTooLarge.Message)
+}
+
+func TestCreatePdfThumbnail_NoEmbeddedImage(t *testing.T) {
+	pdfData := []byte("%PDF-1.4\n1 0 obj\n<< /Type /Catalog >>\nendobj\ntrailer\n<< /Root 1 0 R >>\n%%EOF\n")
+
+	_, err := CreatePdfThumbnail(pdfData, DefaultThumbnailMaxSize)
+	assert.EqualError(t, err, errs.ErrNotSupported.Message)
+}
+
+func createTestPdfWithJpegImage(t *testing.T, width int, height int) []byte {
+	return createTestPdf(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /Resources << /XObject << /Im0 4 0 R >> >> /Contents 5 0 R >>",
+		createTestPdfJpegImageObject(t, width, height),
+		"<< /Length 0 >>\nstream\n\nendstream",
+	)
+}
+
+func createTestPdfJpegImageObject(t *testing.T, width int, height int) string {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+
+	imageBuffer := &bytes.Buffer{}
+	err := jpeg.Encode(imageBuffer, img, nil)
+	assert.Nil(t, err)
+
+	return fmt.Sprintf("<< /Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /DCTDecode /Length %d >>\nstream\r\n%s\r\nendstream", width, height, imageBuffer.Len(), imageBuffer.Bytes())
+}
+
+func createTestPdf(objects ...string) []byte {
+	pdfBuffer := &bytes.Buffer{}
+	pdfBuffer.WriteString("%PDF-1.4\n")
+
+	for i := 0; i < len(objects); i++ {
+		pdfBuffer.WriteString(fmt.Sprintf("%d 0 obj\n%s\nendobj\n", i+1, objects[i]))
+	}
+
+	pdfBuffer.WriteString("trailer\n<< /Size ")
+	pdfBuffer.WriteString(fmt.Sprintf("%d", len(objects)+1))
+	pdfBuffer.WriteString(" /Root 1 0 R >>\n%%EOF\n")
+
+	return pdfBuffer.Bytes()
+}
@@ -0,0 +1,297 @@
+package thumbnails
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+)
+
+// DefaultThumbnailMaxSize represents the default maximum width and height of thumbnail
+const DefaultThumbnailMaxSize = 256
+
+// MaxImagePixelCount represents the maximum pixel count (width * height) of image which can be decoded
+const MaxImagePixelCount = 50000000
+
+const thumbnailJpegQuality = 80
+
+// ResizeImage returns a new image which is scaled down proportionally to fit in the specified maximum width and height,
+// the transparent area is filled with white, and the original image is returned if it is small enough
+func ResizeImage(src image.Image, maxSize int) image.Image {
+	bounds := src.Bounds()
+	width := bounds.Dx()
+	height := bounds.Dy()
+
+	if width <= 0 || height <= 0 || maxSize <= 0 || (width <= maxSize && height <= maxSize) {
+		return src
+	}
+
+	newWidth := maxSize
+	newHeight := maxSize
+
+	if width > height {
+		newHeight = height * maxSize / width
+	} else {
+		newWidth = width * maxSize / height
+	}
+
+	if newWidth < 1 {
+		newWidth = 1
+	}
+
+	if newHeight < 1 {
+		newHeight = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, newWidth, newHeight))
+	readPixel := getPixelReader(src)
+
+	for y := 0; y < newHeight; y++ {
+		srcMinY := bounds.Min.Y + y*height/newHeight
+		srcMaxY := bounds.Min.Y + (y+1)*height/newHeight
+
+		if srcMaxY <= srcMinY {
+			srcMaxY = srcMinY + 1
+		}
+
+		for x := 0; x < newWidth; x++ {
+			srcMinX := bounds.Min.X + x*width/newWidth
+			srcMaxX := bounds.Min.X + (x+1)*width/newWidth
+
+			if srcMaxX <= srcMinX {
+				srcMaxX = srcMinX + 1
+			}
+
+			var totalRed, totalGreen, totalBlue, totalAlpha uint64
+			var count uint64
+
+			for srcY := srcMinY; srcY < srcMaxY; srcY++ {
+				for srcX := srcMinX; srcX < srcMaxX; srcX++ {
+					r, g, b, a := readPixel(srcX, srcY)
+					totalRed += uint64(r)
+					totalGreen += uint64(g)
+					totalBlue += uint64(b)
+					totalAlpha += uint64(a)
+					count++
+				}
+			}
+
+			transparent := 0xffff - totalAlpha/count
+			offset := dst.PixOffset(x, y)
+			pixel := dst.Pix[offset : offset+4 : offset+4]
+			pixel[0] = uint8((totalRed/count + transparent) >> 8)
+			pixel[1] = uint8((totalGreen/count + transparent) >> 8)
+			pixel[2] = uint8((totalBlue/count + transparent) >> 8)
+			pixel[3] = 0xff
+		}
+	}
+
+	return dst
+}
+
+// RotateImage returns a new image which is transformed according to the specified exif orientation,
+// and the original image is returned if the orientation is normal or invalid
+func RotateImage(src image.Image, orientation ImageOrientation) image.Image {
+	if orientation <= IMAGE_ORIENTATION_NORMAL || orientation > imageOrientationMaxValue {
+		return src
+	}
+
+	bounds := src.Bounds()
+	width := bounds.Dx()
+	height := bounds.Dy()
+	swapSize := orientation >= IMAGE_ORIENTATION_TRANSPOSE
+
+	var dst *image.RGBA
+
+	if swapSize {
+		dst = image.NewRGBA(image.Rect(0, 0, height, width))
+	} else {
+		dst = image.NewRGBA(image.Rect(0, 0, width, height))
+	}
+
+	readPixel := getPixelReader(src)
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			dstX, dstY := x, y
+
+			switch orientation {
+			case IMAGE_ORIENTATION_FLIP_HORIZONTAL:
+				dstX, dstY = width-1-x, y
+			case IMAGE_ORIENTATION_ROTATE_180:
+				dstX, dstY = width-1-x, height-1-y
+			case IMAGE_ORIENTATION_FLIP_VERTICAL:
+				dstX, dstY = x, height-1-y
+			case IMAGE_ORIENTATION_TRANSPOSE:
+				dstX, dstY = y, x
+			case IMAGE_ORIENTATION_ROTATE_90_CLOCKWISE:
+				dstX, dstY = height-1-y, x
+			case IMAGE_ORIENTATION_TRANSVERSE:
+				dstX, dstY = height-1-y, width-1-x
+			case IMAGE_ORIENTATION_ROTATE_270_CLOCKWISE:
+				dstX, dstY = y, width-1-x
+			}
+
+			r, g, b, a := readPixel(bounds.Min.X+x, bounds.Min.Y+y)
+			offset := dst.PixOffset(dstX, dstY)
+			pixel := dst.Pix[offset : offset+4 : offset+4]
+			pixel[0] = uint8(r >> 8)
+			pixel[1] = uint8(g >> 8)
+			pixel[2] = uint8(b >> 8)
+			pixel[3] = uint8(a >> 8)
+		}
+	}
+
+	return dst
+}
+
+// CreateImageThumbnail returns the jpeg encoded thumbnail data of the specified image
+func CreateImageThumbnail(src image.Image, maxSize int) ([]byte, error) {
+	thumbnail := ResizeImage(src, maxSize)
+	buffer := &bytes.Buffer{}
+	err := jpeg.Encode(buffer, thumbnail, &jpeg.Options{Quality: thumbnailJpegQuality})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// CreateThumbnailFromImageData returns the jpeg encoded thumbnail data of the specified jpeg, png or gif image data,
+// and the exif orientation of jpeg image is applied to the thumbnail
+func CreateThumbnailFromImageData(data []byte, maxSize int) ([]byte, error) {
+	img, err := DecodeImage(data)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return CreateImageThumbnail(img, maxSize)
+}
+
+// ConvertImageDataToJpeg returns the jpeg encoded data of the specified jpeg, png or gif image data without any metadata,
+// the exif orientation of jpeg image is applied and the transparent area is filled with white
+func ConvertImageDataToJpeg(data []byte, quality int) ([]byte, error) {
+	img, err := DecodeImage(data)
+
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(dst, dst.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.Draw(dst, dst.Bounds(), img, bounds.Min, draw.Over)
+
+	buffer := &bytes.Buffer{}
+	err = jpeg.Encode(buffer, dst, &jpeg.Options{Quality: quality})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// DecodeImage returns the decoded image of the specified jpeg, png or gif image data, and the exif orientation of jpeg image is applied.
+// The image dimensions are checked before decoding to avoid allocating huge memory for the crafted image,
+// and webp and heic images are not supported, they are stored as is without thumbnails and format conversion
+func DecodeImage(data []byte) (image.Image, error) {
+	if isWebpImage(data) || isHeifImage(data) {
+		return nil, errs.ErrImageTypeNotSupported
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+
+	if err != nil {
+		return nil, errs.ErrImageTypeNotSupported
+	}
+
+	if config.Width <= 0 || config.Height <= 0 || int64(config.Width)*int64(config.Height) > MaxImagePixelCount {
+		return nil, errs.ErrImageTooLarge
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+
+	if err != nil {
+		return nil, errs.ErrImageTypeNotSupported
+	}
+
+	return RotateImage(img, GetJpegOrientation(data)), nil
+}
+
+// IsDecodableImageFileExtension returns whether the image with the specified file extension can be decoded to create thumbnails or to be converted
+func IsDecodableImageFileExtension(fileExtension string) bool {
+	return fileExtension == "jpg" || fileExtension == "jpeg" || fileExtension == "png" || fileExtension == "gif"
+}
+
+func isWebpImage(data []byte) bool {
+	return len(data) >= 12 && string(data[0:4]) == "RIFF" && string(data[8:12]) == "WEBP"
+}
+
+func isHeifImage(data []byte) bool {
+	if len(data) < 12 || string(data[4:8]) != "ftyp" {
+		return false
+	}
+
+	brand := string(data[8:12])
+
+	return brand == "heic" || brand == "heix" || brand == "heim" || brand == "heis" || brand == "hevc" || brand == "hevx" || brand == "mif1" || brand == "msf1" || brand == "avif"
+}
+
+// getPixelReader returns the function which reads the alpha-premultiplied 16-bit color of the pixel,
+// it reads the pixel data directly for the image types which are returned by the jpeg, png and gif decoders
+func getPixelReader(src image.Image) func(x int, y int) (uint32, uint32, uint32, uint32) {
+	switch img := src.(type) {
+	case *image.RGBA:
+		return func(x int, y int) (uint32, uint32, uint32, uint32) {
+			offset := img.PixOffset(x, y)
+			pixel := img.Pix[offset : offset+4 : offset+4]
+			return uint32(pixel[0]) * 0x101, uint32(pixel[1]) * 0x101, uint32(pixel[2]) * 0x101, uint32(pixel[3]) * 0x101
+		}
+	case *image.NRGBA:
+		return func(x int, y int) (uint32, uint32, uint32, uint32) {
+			offset := img.PixOffset(x, y)
+			pixel := img.Pix[offset : offset+4 : offset+4]
+			return color.NRGBA{R: pixel[0], G: pixel[1], B: pixel[2], A: pixel[3]}.RGBA()
+		}
+	case *image.YCbCr:
+		return func(x int, y int) (uint32, uint32, uint32, uint32) {
+			yOffset := img.YOffset(x, y)
+			cOffset := img.COffset(x, y)
+			return color.YCbCr{Y: img.Y[yOffset], Cb: img.Cb[cOffset], Cr: img.Cr[cOffset]}.RGBA()
+		}
+	case *image.Gray:
+		return func(x int, y int) (uint32, uint32, uint32, uint32) {
+			gray := uint32(img.Pix[img.PixOffset(x, y)]) * 0x101
+			return gray, gray, gray, 0xffff
+		}
+	case *image.Paletted:
+		palette := make([][4]uint32, len(img.Palette))
+
+		for i := 0; i < len(img.Palette); i++ {
+			r, g, b, a := img.Palette[i].RGBA()
+			palette[i] = [4]uint32{r, g, b, a}
+		}
+
+		return func(x int, y int) (uint32, uint32, uint32, uint32) {
+			index := int(img.Pix[img.PixOffset(x, y)])
+
+			if index >= len(palette) {
+				return 0, 0, 0, 0
+			}
+
+			return palette[index][0], palette[index][1], palette[index][2], palette[index][3]
+		}
+	default:
+		return func(x int, y int) (uint32, uint32, uint32, uint32) {
+			return src.At(x, y).RGBA()
+		}
+	}
+}
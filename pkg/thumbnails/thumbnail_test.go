@@ -0,0 +1,167 @@
+package thumbnails
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+)
+
+func TestResizeImage_SmallImage(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 100, 20))
+	assert.Equal(t, img, ResizeImage(img, DefaultThumbnailMaxSize))
+}
+
+func TestResizeImage_TransparentImage(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 512, 1024))
+	resizedImage := ResizeImage(img, DefaultThumbnailMaxSize)
+
+	assert.Equal(t, 128, resizedImage.Bounds().Dx())
+	assert.Equal(t, 256, resizedImage.Bounds().Dy())
+
+	r, g, b, a := resizedImage.At(10, 10).RGBA()
+	assert.Equal(t, uint32(0xffff), r)
+	assert.Equal(t, uint32(0xffff), g)
+	assert.Equal(t, uint32(0xffff), b)
+	assert.Equal(t, uint32(0xffff), a)
+}
+
+func TestCreateThumbnailFromImageData(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 600, 300))
+	img.Set(0, 0, color.RGBA{R: 255, A: 255})
+
+	imageBuffer := &bytes.Buffer{}
+	err := png.Encode(imageBuffer, img)
+	assert.Nil(t, err)
+
+	thumbnailData, err := CreateThumbnailFromImageData(imageBuffer.Bytes(), DefaultThumbnailMaxSize)
+	assert.Nil(t, err)
+
+	thumbnail, err := jpeg.Decode(bytes.NewReader(thumbnailData))
+	assert.Nil(t, err)
+	assert.Equal(t, 256, thumbnail.Bounds().Dx())
+	assert.Equal(t, 128, thumbnail.Bounds().Dy())
+}
+
+func TestCreateThumbnailFromImageData_InvalidImage(t *testing.T) {
+	_, err := CreateThumbnailFromImageData([]byte("not an image"), DefaultThumbnailMaxSize)
+	assert.EqualError(t, err, errs.ErrImageTypeNotSupported.Message)
+}
+
+func TestRotateImage(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 40, 20))
+	img.Set(0, 0, color.RGBA{R: 255, A: 255})
+
+	rotatedImage := RotateImage(img, IMAGE_ORIENTATION_ROTATE_90_CLOCKWISE)
+	assert.Equal(t, 20, rotatedImage.Bounds().Dx())
+	assert.Equal(t, 40, rotatedImage.Bounds().Dy())
+
+	r, _, _, _ := rotatedImage.At(19, 0).RGBA()
+	assert.Equal(t, uint32(0xffff), r)
+
+	rotatedImage = RotateImage(img, IMAGE_ORIENTATION_ROTATE_180)
+	assert.Equal(t, 40, rotatedImage.Bounds().Dx())
+	assert.Equal(t, 20, rotatedImage.Bounds().Dy())
+
+	r, _, _, _ = rotatedImage.At(39, 19).RGBA()
+	assert.Equal(t, uint32(0xffff), r)
+
+	assert.Equal(t, img, RotateImage(img, IMAGE_ORIENTATION_NORMAL))
+}
+
+func TestCreateThumbnailFromImageData_RotatedJpeg(t *testing.T) {
+	data := createTestJpegWithExif(t, IMAGE_ORIENTATION_ROTATE_270_CLOCKWISE)
+
+	thumbnailData, err := CreateThumbnailFromImageData(data, DefaultThumbnailMaxSize)
+	assert.Nil(t, err)
+
+	thumbnail, err := jpeg.Decode(bytes.NewReader(thumbnailData))
+	assert.Nil(t, err)
+	assert.Equal(t, 20, thumbnail.Bounds().Dx())
+	assert.Equal(t, 40, thumbnail.Bounds().Dy())
+}
+
+func TestConvertImageDataToJpeg(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 30, 10))
+
+	imageBuffer := &bytes.Buffer{}
+	err := png.Encode(imageBuffer, img)
+	assert.Nil(t, err)
+
+	jpegData, err := ConvertImageDataToJpeg(imageBuffer.Bytes(), 90)
+	assert.Nil(t, err)
+
+	convertedImage, err := jpeg.Decode(bytes.NewReader(jpegData))
+	assert.Nil(t, err)
+	assert.Equal(t, 30, convertedImage.Bounds().Dx())
+	assert.Equal(t, 10, convertedImage.Bounds().Dy())
+
+	r, g, b, _ := convertedImage.At(5, 5).RGBA()
+	assert.True(t, r > 0xf000 && g > 0xf000 && b > 0xf000)
+
+	_, err = ConvertImageDataToJpeg([]byte("not an image"), 90)
+	assert.EqualError(t, err, errs.ErrImageTypeNotSupported.Message)
+}
+
+func TestDecodeImage_ExceedMaxPixelCount(t *testing.T) {
+	// gif header with 65535 x 65535 logical screen size and no image data
+	data := []byte{'G', 'I', 'F', '8', '9', 'a', 0xFF, 0xFF, 0xFF, 0xFF, 0x00, 0x00, 0x00, 0x3B}
+
+	_, err := DecodeImage(data)
+	assert.EqualError(t, err, errs.ErrImageTooLarge.Message)
+
+	_, err = CreateThumbnailFromImageData(data, DefaultThumbnailMaxSize)
+	assert.EqualError(t, err, errs.ErrImageTooLarge.Message)
+}
+
+func TestDecodeImage_WebpAndHeicImage(t *testing.T) {
+	_, err := DecodeImage([]byte("RIFF\x24\x00\x00\x00WEBPVP8 \x18\x00\x00\x00"))
+	assert.EqualError(t, err, errs.ErrImageTypeNotSupported.Message)
+
+	_, err = DecodeImage([]byte("\x00\x00\x00\x18ftypheic\x00\x00\x00\x00mif1heic"))
+	assert.EqualError(t, err, errs.ErrImageTypeNotSupported.Message)
+
+	assert.False(t, IsDecodableImageFileExtension("webp"))
+	assert.False(t, IsDecodableImageFileExtension("heic"))
+	assert.True(t, IsDecodableImageFileExtension("png"))
+}
+
+type testGenericImage struct {
+	image.Image
+}
+
+func TestResizeImage_SameResultAsGenericImage(t *testing.T) {
+	rgbaImage := image.NewRGBA(image.Rect(0, 0, 600, 300))
+	nrgbaImage := image.NewNRGBA(image.Rect(0, 0, 600, 300))
+	ycbcrImage := image.NewYCbCr(image.Rect(0, 0, 600, 300), image.YCbCrSubsampleRatio420)
+	palettedImage := image.NewPaletted(image.Rect(0, 0, 600, 300), color.Palette{color.Black, color.RGBA{R: 255, A: 255}, color.Transparent})
+
+	for y := 0; y < 300; y++ {
+		for x := 0; x < 600; x++ {
+			rgbaImage.SetRGBA(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: uint8(x + y), A: 255})
+			nrgbaImage.SetNRGBA(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: uint8(x + y), A: uint8(x * y)})
+			ycbcrImage.Y[ycbcrImage.YOffset(x, y)] = uint8(x + y)
+			ycbcrImage.Cb[ycbcrImage.COffset(x, y)] = uint8(x)
+			ycbcrImage.Cr[ycbcrImage.COffset(x, y)] = uint8(y)
+			palettedImage.SetColorIndex(x, y, uint8((x+y)%3))
+		}
+	}
+
+	images := []image.Image{rgbaImage, nrgbaImage, ycbcrImage, palettedImage}
+
+	for i := 0; i < len(images); i++ {
+		expectedImage := ResizeImage(&testGenericImage{images[i]}, 100)
+		actualImage := ResizeImage(images[i], 100)
+		assert.Equal(t, expectedImage, actualImage)
+
+		expectedImage = RotateImage(&testGenericImage{images[i]}, IMAGE_ORIENTATION_ROTATE_90_CLOCKWISE)
+		actualImage = RotateImage(images[i], IMAGE_ORIENTATION_ROTATE_90_CLOCKWISE)
+		assert.Equal(t, expectedImage, actualImage)
+	}
+}
@@ -3,6 +3,7 @@ package utils
 import (
 	"encoding/json"
 	"errors"
+	"mime"
 	"net/http"
 	"reflect"
 
@@ -10,6 +11,7 @@ import (
 
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
 )
 
 // GetDisplayErrorMessage returns the display error message for given error
@@ -55,6 +57,50 @@ func PrintDataSuccessResult(c *core.WebContext, contentType string, fileName str
 	c.Data(http.StatusOK, contentType, result)
 }
 
+// PrintDataStreamSuccessResult writes success response of data file download to current http context by the writer function,
+// if the writer function fails before any data is written, the error result is returned to the client instead,
+// otherwise the response status and headers have already been sent, so the connection is closed to make the client know the file is incomplete
+func PrintDataStreamSuccessResult(c *core.WebContext, contentType string, fileName string, writer core.DataStreamWriterFunc) {
+	if fileName != "" {
+		c.Header("Content-Disposition", "attachment;filename="+fileName)
+	}
+
+	c.Header("Content-Type", contentType)
+	c.Status(http.StatusOK)
+
+	err := writer(c.Writer)
+
+	if err == nil {
+		c.Writer.Flush()
+		return
+	}
+
+	if !c.Writer.Written() {
+		log.Errorf(c, "[api.PrintDataStreamSuccessResult] failed to write data file, because %s", err.Error())
+		c.Writer.Header().Del("Content-Disposition")
+		c.Writer.Header().Del("Content-Type")
+		PrintDataErrorResult(c, "text/text", errs.Or(err, errs.ErrOperationFailed))
+		return
+	}
+
+	log.Errorf(c, "[api.PrintDataStreamSuccessResult] failed to write data file after %d bytes have been sent, because %s", c.Writer.Size(), err.Error())
+	c.Abort()
+	panic(http.ErrAbortHandler)
+}
+
+// PrintFileSuccessResult writes success response of file download with original file name to current http context
+func PrintFileSuccessResult(c *core.WebContext, contentType string, fileName string, result []byte) {
+	contentDisposition := mime.FormatMediaType("attachment", map[string]string{"filename": fileName})
+
+	if contentDisposition == "" {
+		contentDisposition = "attachment"
+	}
+
+	c.Header("Content-Disposition", contentDisposition)
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Data(http.StatusOK, contentType, result)
+}
+
 // PrintJsonErrorResult writes error response in json format to current http context
 func PrintJsonErrorResult(c *core.WebContext, err *errs.Error) {
 	c.SetResponseError(err)
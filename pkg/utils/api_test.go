@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+)
+
+func TestPrintDataStreamSuccessResult(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	c := createTestDataStreamWebContext(recorder)
+
+	PrintDataStreamSuccessResult(c, "text/csv; charset=utf-8", "export.csv", func(writer io.Writer) error {
+		_, err := writer.Write([]byte("Time,Type\n"))
+		return err
+	})
+
+	assert.False(t, c.IsAborted())
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, "attachment;filename=export.csv", recorder.Header().Get("Content-Disposition"))
+	assert.Equal(t, "Time,Type\n", recorder.Body.String())
+}
+
+func TestPrintDataStreamSuccessResult_WriterFailedBeforeWritingData(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	c := createTestDataStreamWebContext(recorder)
+
+	PrintDataStreamSuccessResult(c, "text/csv; charset=utf-8", "export.csv", func(writer io.Writer) error {
+		return errs.ErrTransactionNotFound
+	})
+
+	assert.True(t, c.IsAborted())
+	assert.Equal(t, errs.ErrTransactionNotFound, c.GetResponseError())
+	assert.Equal(t, errs.ErrTransactionNotFound.HttpStatusCode, recorder.Code)
+	assert.Equal(t, "text/text", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, "", recorder.Header().Get("Content-Disposition"))
+	assert.Equal(t, errs.ErrTransactionNotFound.Message, recorder.Body.String())
+}
+
+func TestPrintDataStreamSuccessResult_WriterFailedAfterWritingData(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	c := createTestDataStreamWebContext(recorder)
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		PrintDataStreamSuccessResult(c, "text/csv; charset=utf-8", "export.csv", func(writer io.Writer) error {
+			_, err := writer.Write([]byte("Time,Type\n"))
+
+			if err != nil {
+				return err
+			}
+
+			return errs.ErrOperationFailed
+		})
+	})
+
+	assert.True(t, c.IsAborted())
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "Time,Type\n", recorder.Body.String())
+}
+
+func createTestDataStreamWebContext(recorder *httptest.ResponseRecorder) *core.WebContext {
+	ginContext, _ := gin.CreateTestContext(recorder)
+	ginContext.Request = httptest.NewRequest(http.MethodGet, "/api/v1/data/export.csv", nil)
+
+	return core.WrapWebContext(ginContext)
+}
@@ -1,12 +1,19 @@
 package utils
 
 import (
+	"bytes"
 	"io"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
 )
 
+// OleCompoundFileContentType represents the content type of compound file which is used by legacy microsoft office documents
+const OleCompoundFileContentType = "application/x-ole-storage"
+
+var oleCompoundFileSignature = []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1}
+
 var imageFileExtensionContentTypeMap = map[string]string{
 	"jpg":  "image/jpeg",
 	"jpeg": "image/jpeg",
@@ -26,6 +33,22 @@ func GetImageContentType(fileExtension string) string {
 	return contentType
 }
 
+// DetectFileContentType returns the content type sniffed from the beginning of file data without any parameters,
+// and it also detects the compound file format which is used by legacy microsoft office documents
+func DetectFileContentType(data []byte) string {
+	if bytes.HasPrefix(data, oleCompoundFileSignature) {
+		return OleCompoundFileContentType
+	}
+
+	contentType := http.DetectContentType(data)
+
+	if index := strings.Index(contentType, ";"); index >= 0 {
+		contentType = strings.TrimSpace(contentType[:index])
+	}
+
+	return contentType
+}
+
 // ListFileNamesWithPrefixAndSuffix returns file name list which has specified prefix and suffix
 func ListFileNamesWithPrefixAndSuffix(path string, prefix string, suffix string) []string {
 	dir, err := os.Open(path)
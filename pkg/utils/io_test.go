@@ -18,6 +18,14 @@ func TestGetImageContentType(t *testing.T) {
 	assert.Equal(t, expectedContentType, actualContentType)
 }
 
+func TestDetectFileContentType(t *testing.T) {
+	assert.Equal(t, "application/pdf", DetectFileContentType([]byte("%PDF-1.4\n%test")))
+	assert.Equal(t, "application/zip", DetectFileContentType([]byte("PK\x03\x04test")))
+	assert.Equal(t, "text/plain", DetectFileContentType([]byte("date,amount\n2024-01-01,1.00\n")))
+	assert.Equal(t, OleCompoundFileContentType, DetectFileContentType([]byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1, 0x00}))
+	assert.Equal(t, "application/octet-stream", DetectFileContentType([]byte{0x00, 0x01, 0x02}))
+}
+
 func TestGetFileNameWithoutExtension(t *testing.T) {
 	fileName := "name.ext"
 	expectedName := "name"
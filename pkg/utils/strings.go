@@ -166,8 +166,19 @@ func MD5EncodeToString(data []byte) string {
 	return hex.EncodeToString(hash)
 }
 
+// SHA256EncodeToString returns a hashed string by sha256
+func SHA256EncodeToString(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
 // AESGCMEncrypt returns a encrypted string by aes-gcm
 func AESGCMEncrypt(key []byte, plainText []byte) ([]byte, error) {
+	return AESGCMEncryptWithAdditionalData(key, plainText, nil)
+}
+
+// AESGCMEncryptWithAdditionalData returns a encrypted string by aes-gcm, the additional data is authenticated but not encrypted
+func AESGCMEncryptWithAdditionalData(key []byte, plainText []byte, additionalData []byte) ([]byte, error) {
 	block, err := aes.NewCipher(key)
 
 	if err != nil {
@@ -186,7 +197,7 @@ func AESGCMEncrypt(key []byte, plainText []byte) ([]byte, error) {
 		return nil, err
 	}
 
-	ciphertext := aesgcm.Seal(nil, nonce, plainText, nil)
+	ciphertext := aesgcm.Seal(nil, nonce, plainText, additionalData)
 	result := append(nonce, ciphertext...)
 
 	return result, nil
@@ -194,6 +205,11 @@ func AESGCMEncrypt(key []byte, plainText []byte) ([]byte, error) {
 
 // AESGCMDecrypt returns a decrypted string by aes-gcm
 func AESGCMDecrypt(key []byte, ciphertext []byte) ([]byte, error) {
+	return AESGCMDecryptWithAdditionalData(key, ciphertext, nil)
+}
+
+// AESGCMDecryptWithAdditionalData returns a decrypted string by aes-gcm, the additional data must be the same as the one used in encryption
+func AESGCMDecryptWithAdditionalData(key []byte, ciphertext []byte, additionalData []byte) ([]byte, error) {
 	block, err := aes.NewCipher(key)
 
 	if err != nil {
@@ -215,7 +231,7 @@ func AESGCMDecrypt(key []byte, ciphertext []byte) ([]byte, error) {
 	nonce := ciphertext[:nonceSize]
 	ciphertext = ciphertext[nonceSize:]
 
-	plainText, err := aesgcm.Open(nil, nonce, ciphertext, nil)
+	plainText, err := aesgcm.Open(nil, nonce, ciphertext, additionalData)
 
 	if err != nil {
 		return nil, err
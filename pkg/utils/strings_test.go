@@ -147,6 +147,18 @@ func TestMD5EncodeToString(t *testing.T) {
 	assert.Equal(t, expectedValue, actualValue)
 }
 
+func TestSHA256EncodeToString(t *testing.T) {
+	str := "foobar"
+	expectedValue := "c3ab8ff13720e8ad9047dd39466b3c8974e592c2fa383d4a3960714caef0c4f2"
+	actualValue := SHA256EncodeToString([]byte(str))
+	assert.Equal(t, expectedValue, actualValue)
+
+	str = ""
+	expectedValue = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	actualValue = SHA256EncodeToString([]byte(str))
+	assert.Equal(t, expectedValue, actualValue)
+}
+
 func TestEncodePassword(t *testing.T) {
 	password := "foobar"
 	salt := "salt"
@@ -5,13 +5,20 @@ type UuidType uint8
 
 // Types of uuid
 const (
-	UUID_TYPE_DEFAULT     UuidType = 0
-	UUID_TYPE_USER        UuidType = 1
-	UUID_TYPE_ACCOUNT     UuidType = 2
-	UUID_TYPE_TRANSACTION UuidType = 3
-	UUID_TYPE_CATEGORY    UuidType = 4
-	UUID_TYPE_TAG         UuidType = 5
-	UUID_TYPE_TAG_INDEX   UuidType = 6
-	UUID_TYPE_TEMPLATE    UuidType = 7
-	UUID_TYPE_PICTURE     UuidType = 8
+	UUID_TYPE_DEFAULT       UuidType = 0
+	UUID_TYPE_USER          UuidType = 1
+	UUID_TYPE_ACCOUNT       UuidType = 2
+	UUID_TYPE_TRANSACTION   UuidType = 3
+	UUID_TYPE_CATEGORY      UuidType = 4
+	UUID_TYPE_TAG           UuidType = 5
+	UUID_TYPE_TAG_INDEX     UuidType = 6
+	UUID_TYPE_TEMPLATE      UuidType = 7
+	UUID_TYPE_PICTURE       UuidType = 8
+	UUID_TYPE_LEDGER        UuidType = 9
+	UUID_TYPE_LEDGER_MEMBER UuidType = 10
+	UUID_TYPE_CONTACT       UuidType = 11
+	UUID_TYPE_EVENT         UuidType = 12
+	UUID_TYPE_TAG_GROUP     UuidType = 13
+	UUID_TYPE_CUSTOM_FIELD  UuidType = 14
+	UUID_TYPE_ATTACHMENT    UuidType = 15
 )
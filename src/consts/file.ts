@@ -305,11 +305,61 @@ export const SUPPORTED_IMPORT_FILE_CATEGORY_AND_TYPES: ImportFileCategoryAndType
                     anchor: 'how-to-get-firefly-iii-data-export-file'
                 }
             },
+            {
+                type: 'ynab',
+                name: 'YNAB Data Export File',
+                extensions: '.zip,.csv',
+                supportedAdditionalOptions: {
+                    payeeAsTag: false,
+                    payeeAsDescription: true
+                }
+            },
+            {
+                type: 'actual_budget',
+                name: 'Actual Budget Data Export File',
+                extensions: '.zip,.sqlite',
+                supportedAdditionalOptions: {
+                    payeeAsTag: false,
+                    payeeAsDescription: true
+                }
+            },
+            {
+                type: 'homebank',
+                name: 'HomeBank Data File',
+                extensions: '.xhb',
+                supportedAdditionalOptions: {
+                    payeeAsTag: false,
+                    payeeAsDescription: true
+                }
+            },
+            {
+                type: 'kmymoney',
+                name: 'KMyMoney Data File',
+                extensions: '.kmy',
+                supportedAdditionalOptions: {
+                    payeeAsTag: false,
+                    payeeAsDescription: true
+                }
+            },
+            {
+                type: 'money_manager_ex',
+                name: 'Money Manager Ex Database File',
+                extensions: '.mmb',
+                supportedAdditionalOptions: {
+                    payeeAsTag: false,
+                    payeeAsDescription: true
+                }
+            },
             {
                 type: 'beancount',
                 name: 'Beancount Data File',
                 extensions: '.beancount'
             },
+            {
+                type: 'ledger',
+                name: 'Ledger / hledger Journal File',
+                extensions: '.ledger,.journal,.hledger,.dat,.txt'
+            },
             {
                 type: 'feidee_mymoney_csv',
                 name: 'Feidee MyMoney (App) Data Export File',
@@ -4,6 +4,14 @@ export class KnownFileType {
     public static readonly JSON = new KnownFileType('json', 'application/json');
     public static readonly CSV = new KnownFileType('csv', 'text/csv');
     public static readonly TSV = new KnownFileType('tsv', 'text/tab-separated-values');
+    public static readonly OFX = new KnownFileType('ofx', 'application/x-ofx');
+    public static readonly QFX = new KnownFileType('qfx', 'application/vnd.intu.qfx');
+    public static readonly QIF = new KnownFileType('qif', 'application/qif');
+    public static readonly BEANCOUNT = new KnownFileType('beancount', 'text/x-beancount');
+    public static readonly LEDGER = new KnownFileType('ledger', 'text/x-ledger');
+    public static readonly GNUCASH = new KnownFileType('gnucash', 'application/x-gnucash');
+    public static readonly XLSX = new KnownFileType('xlsx', 'application/vnd.openxmlformats-officedocument.spreadsheetml.sheet');
+    public static readonly ZIP = new KnownFileType('zip', 'application/zip');
     public static readonly TXT = new KnownFileType('txt', 'text/text');
     public static readonly MARKDOWN = new KnownFileType('md', 'text/markdown');
     public static readonly JS = new KnownFileType('js', 'application/javascript');
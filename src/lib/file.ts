@@ -1,6 +1,6 @@
 import chardet, { type Match } from 'chardet';
 
-import type { ImportFileTypeAndExtensions } from '@/core/file.ts';
+import { type ImportFileTypeAndExtensions, KnownFileType } from '@/core/file.ts';
 
 import { UTF_8, CHARDET_ENCODING_NAME_MAPPING } from '@/consts/file.ts';
 
@@ -15,6 +15,14 @@ export function getFileExtension(filename: string): string {
     return parts[parts.length - 1] as string;
 }
 
+export function getExportFileExtension(fileType: string): string {
+    if (fileType.indexOf(`${KnownFileType.QIF.extension}_`) === 0) {
+        return KnownFileType.QIF.extension;
+    }
+
+    return fileType;
+}
+
 export function findExtensionByType(items: ImportFileTypeAndExtensions[] | undefined, type: string): string | undefined {
     if (!items || items.length < 1) {
         return undefined;
@@ -440,7 +440,9 @@ export default {
             const tagFilter = encodeURIComponent(req.tagFilter);
             const amountFilter = encodeURIComponent(req.amountFilter);
             const keyword = encodeURIComponent(req.keyword);
-            params = `max_time=${req.maxTime}&min_time=${req.minTime}&type=${req.type}&category_ids=${req.categoryIds}&account_ids=${req.accountIds}&tag_filter=${tagFilter}&amount_filter=${amountFilter}&keyword=${keyword}`;
+            const columns = encodeURIComponent(req.columns || '');
+            const timezone = encodeURIComponent(req.timezone || '');
+            params = `max_time=${req.maxTime}&min_time=${req.minTime}&type=${req.type}&category_ids=${req.categoryIds}&account_ids=${req.accountIds}&tag_filter=${tagFilter}&amount_filter=${amountFilter}&keyword=${keyword}&columns=${columns}&timezone=${timezone}`;
         } else {
             params = 'max_time=0&min_time=0&type=0&category_ids=&account_ids=&tag_filter=&amount_filter=&keyword=';
         }
@@ -453,6 +455,41 @@ export default {
             return axios.get<BlobPart>('v1/data/export.tsv?' + params, {
                 timeout: DEFAULT_EXPORT_API_TIMEOUT
             } as ApiRequestConfig);
+        } else if (fileType === 'ofx') {
+            return axios.get<BlobPart>('v1/data/export.ofx?' + params, {
+                timeout: DEFAULT_EXPORT_API_TIMEOUT
+            } as ApiRequestConfig);
+        } else if (fileType === 'qfx') {
+            return axios.get<BlobPart>('v1/data/export.qfx?' + params, {
+                timeout: DEFAULT_EXPORT_API_TIMEOUT
+            } as ApiRequestConfig);
+        } else if (fileType === 'qif_ymd' || fileType === 'qif_mdy' || fileType === 'qif_dmy') {
+            return axios.get<BlobPart>(`v1/data/export.qif?date_format=${fileType.substring(4)}&` + params, {
+                timeout: DEFAULT_EXPORT_API_TIMEOUT
+            } as ApiRequestConfig);
+        } else if (fileType === 'beancount') {
+            return axios.get<BlobPart>('v1/data/export.beancount?' + params, {
+                timeout: DEFAULT_EXPORT_API_TIMEOUT
+            } as ApiRequestConfig);
+        } else if (fileType === 'ledger') {
+            return axios.get<BlobPart>('v1/data/export.ledger?' + params, {
+                timeout: DEFAULT_EXPORT_API_TIMEOUT
+            } as ApiRequestConfig);
+        } else if (fileType === 'gnucash') {
+            return axios.get<BlobPart>('v1/data/export.gnucash?' + params, {
+                timeout: DEFAULT_EXPORT_API_TIMEOUT,
+                responseType: 'blob'
+            } as ApiRequestConfig);
+        } else if (fileType === 'xlsx') {
+            return axios.get<BlobPart>('v1/data/export.xlsx?' + params, {
+                timeout: DEFAULT_EXPORT_API_TIMEOUT,
+                responseType: 'blob'
+            } as ApiRequestConfig);
+        } else if (fileType === 'zip') {
+            return axios.get<BlobPart>('v1/data/backup.zip', {
+                timeout: DEFAULT_EXPORT_API_TIMEOUT,
+                responseType: 'blob'
+            } as ApiRequestConfig);
         } else {
             return Promise.reject('Parameter Invalid');
         }
@@ -7,6 +7,8 @@ export interface ExportTransactionDataRequest {
     readonly tagFilter: string;
     readonly amountFilter: string;
     readonly keyword: string;
+    readonly columns?: string;
+    readonly timezone?: string;
 }
 
 export interface ClearDataRequest {
@@ -26,6 +28,10 @@ export interface DataStatisticsResponse {
     readonly totalTransactionPictureCount: string;
     readonly totalTransactionTemplateCount: string;
     readonly totalScheduledTransactionCount: string;
+    readonly storageUsedBytes: string;
+    readonly storageQuota: string;
+    readonly transactionQuotaUsedCount: string;
+    readonly transactionQuota: string;
 }
 
 export interface DisplayDataStatistics {
@@ -1,14 +1,16 @@
 export class TransactionPicture implements TransactionPictureInfoBasicResponse {
     public pictureId: string;
     public originalUrl: string;
+    public thumbnailUrl?: string;
 
-    private constructor(pictureId: string, originalUrl: string) {
+    private constructor(pictureId: string, originalUrl: string, thumbnailUrl?: string) {
         this.pictureId = pictureId;
         this.originalUrl = originalUrl;
+        this.thumbnailUrl = thumbnailUrl;
     }
 
     public static of(picture: TransactionPictureInfoBasicResponse): TransactionPicture {
-        return new TransactionPicture(picture.pictureId, picture.originalUrl);
+        return new TransactionPicture(picture.pictureId, picture.originalUrl, picture.thumbnailUrl);
     }
 
     public static ofMulti(pictureResponses: TransactionPictureInfoBasicResponse[]): TransactionPicture[] {
@@ -29,4 +31,5 @@ export interface TransactionPictureUnusedDeleteRequest {
 export interface TransactionPictureInfoBasicResponse {
     readonly pictureId: string;
     readonly originalUrl: string;
+    readonly thumbnailUrl?: string;
 }
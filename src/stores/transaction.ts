@@ -1394,11 +1394,15 @@ export const useTransactionsStore = defineStore('transactions', () => {
         });
     }
 
-    function getTransactionPictureUrl(pictureInfo?: TransactionPictureInfoBasicResponse | null, disableBrowserCache?: boolean | string): string | undefined {
+    function getTransactionPictureUrl(pictureInfo?: TransactionPictureInfoBasicResponse | null, disableBrowserCache?: boolean | string, thumbnail?: boolean): string | undefined {
         if (!pictureInfo || !pictureInfo.originalUrl) {
             return undefined;
         }
 
+        if (thumbnail && pictureInfo.thumbnailUrl) {
+            return services.getTransactionPictureUrlWithToken(pictureInfo.thumbnailUrl, disableBrowserCache);
+        }
+
         return services.getTransactionPictureUrlWithToken(pictureInfo.originalUrl, disableBrowserCache);
     }
 
@@ -389,6 +389,30 @@ export const useUserStore = defineStore('user', () => {
                     } else if (fileType === 'tsv' && !KnownFileType.TSV.isSameType(contentType)) {
                         reject({ message: 'Unable to retrieve exported user data' });
                         return;
+                    } else if (fileType === 'ofx' && !KnownFileType.OFX.isSameType(contentType)) {
+                        reject({ message: 'Unable to retrieve exported user data' });
+                        return;
+                    } else if (fileType === 'qfx' && !KnownFileType.QFX.isSameType(contentType)) {
+                        reject({ message: 'Unable to retrieve exported user data' });
+                        return;
+                    } else if (fileType.indexOf('qif_') === 0 && !KnownFileType.QIF.isSameType(contentType)) {
+                        reject({ message: 'Unable to retrieve exported user data' });
+                        return;
+                    } else if (fileType === 'beancount' && !KnownFileType.BEANCOUNT.isSameType(contentType)) {
+                        reject({ message: 'Unable to retrieve exported user data' });
+                        return;
+                    } else if (fileType === 'ledger' && !KnownFileType.LEDGER.isSameType(contentType)) {
+                        reject({ message: 'Unable to retrieve exported user data' });
+                        return;
+                    } else if (fileType === 'gnucash' && !KnownFileType.GNUCASH.isSameType(contentType)) {
+                        reject({ message: 'Unable to retrieve exported user data' });
+                        return;
+                    } else if (fileType === 'xlsx' && !KnownFileType.XLSX.isSameType(contentType)) {
+                        reject({ message: 'Unable to retrieve exported user data' });
+                        return;
+                    } else if (fileType === 'zip' && !KnownFileType.ZIP.isSameType(contentType)) {
+                        reject({ message: 'Unable to retrieve exported user data' });
+                        return;
                     }
                 }
 
@@ -399,8 +399,8 @@ export function useTransactionEditPageBase(type: TransactionEditPageType, initMo
         return formatAmountToLocalizedNumeralsWithCurrency(amount, currencyCode);
     }
 
-    function getTransactionPictureUrl(pictureInfo?: TransactionPictureInfoBasicResponse | null): string | undefined {
-        return transactionsStore.getTransactionPictureUrl(pictureInfo);
+    function getTransactionPictureUrl(pictureInfo?: TransactionPictureInfoBasicResponse | null, thumbnail?: boolean): string | undefined {
+        return transactionsStore.getTransactionPictureUrl(pictureInfo, undefined, thumbnail);
     }
 
     watch(() => transaction.value.sourceAmount, (newValue, oldValue) => {
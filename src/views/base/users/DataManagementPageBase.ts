@@ -6,6 +6,8 @@ import { useUserStore } from '@/stores/user.ts';
 
 import type { DataStatisticsResponse, DisplayDataStatistics } from '@/models/data_management.ts';
 
+import { getExportFileExtension } from '@/lib/file.ts';
+
 export function useDataManagementPageBase() {
     const { tt, formatNumberToLocalizedNumerals } = useI18n();
 
@@ -29,7 +31,8 @@ export function useDataManagementPageBase() {
         };
     });
 
-    function getExportFileName(fileExtension: string): string {
+    function getExportFileName(fileType: string): string {
+        const fileExtension = getExportFileExtension(fileType);
         const nickname = userStore.currentUserNickname;
 
         if (nickname) {